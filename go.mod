@@ -47,6 +47,7 @@ require (
 	github.com/centrifugal/centrifuge v0.29.1 // @grafana/grafana-app-platform-squad
 	github.com/crewjam/saml v0.4.13 // @grafana/backend-platform
 	github.com/fatih/color v1.15.0 // @grafana/backend-platform
+	github.com/fsnotify/fsnotify v1.6.0 // @grafana/alerting-squad-backend
 	github.com/gchaincl/sqlhooks v1.3.0 // @grafana/backend-platform
 	github.com/go-git/go-git/v5 v5.4.2 // @grafana/grafana-app-platform-squad
 	github.com/go-ldap/ldap/v3 v3.4.4 // @grafana/grafana-authnz-team
@@ -326,7 +327,6 @@ require (
 	github.com/emicklei/go-restful/v3 v3.10.1 // indirect
 	github.com/evanphx/json-patch v4.12.0+incompatible // indirect
 	github.com/felixge/httpsnoop v1.0.3 // indirect
-	github.com/fsnotify/fsnotify v1.6.0 // indirect
 	github.com/getsentry/sentry-go v0.12.0 // indirect
 	github.com/go-asn1-ber/asn1-ber v1.5.4 // indirect
 	github.com/goccy/go-json v0.9.11 // indirect
@@ -3,9 +3,11 @@ package api
 import (
 	"context"
 	"errors"
+	"net/http"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/util"
 )
 
 // swagger:route POST /admin/provisioning/dashboards/reload admin_provisioning adminProvisioningReloadDashboards
@@ -100,10 +102,20 @@ func (hs *HTTPServer) AdminProvisioningReloadNotifications(c *contextmodel.ReqCo
 	return response.Success("Notifications config reloaded")
 }
 
+// AdminProvisioningReloadAlerting provisions the alert rules, contact points, notification policies, mute timings and
+// templates from the provisioning files again. The response contains the number of resources of each kind in the
+// files and whether they were applied, also when provisioning failed.
 func (hs *HTTPServer) AdminProvisioningReloadAlerting(c *contextmodel.ReqContext) response.Response {
-	err := hs.ProvisioningService.ProvisionAlerting(c.Req.Context())
+	summary, err := hs.ProvisioningService.ReloadAlerting(c.Req.Context())
 	if err != nil {
-		return response.Error(500, "", err)
+		return response.JSON(http.StatusInternalServerError, util.DynMap{
+			"message": "Failed to reload alerting config",
+			"error":   err.Error(),
+			"summary": summary,
+		})
 	}
-	return response.Success("Alerting config reloaded")
+	return response.JSON(http.StatusOK, util.DynMap{
+		"message": "Alerting config reloaded",
+		"summary": summary,
+	})
 }
@@ -139,7 +139,7 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 		{
 			desc:         "should work for alert rules with specific scope",
 			expectedCode: http.StatusOK,
-			expectedBody: `{"message":"Alerting config reloaded"}`,
+			expectedBody: `{"message":"Alerting config reloaded","summary":{"files":0,"resources":null}}`,
 			permissions: []accesscontrol.Permission{
 				{
 					Action: ActionProvisioningReload,
@@ -148,7 +148,7 @@ func TestAPI_AdminProvisioningReload_AccessControl(t *testing.T) {
 			},
 			url: "/api/admin/provisioning/alerting/reload",
 			checkCall: func(mock provisioning.ProvisioningServiceMock) {
-				assert.Len(t, mock.Calls.ProvisionAlerting, 1)
+				assert.Len(t, mock.Calls.ReloadAlerting, 1)
 			},
 		},
 		{
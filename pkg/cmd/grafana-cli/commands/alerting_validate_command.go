@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/logger"
+	"github.com/grafana/grafana/pkg/cmd/grafana-cli/utils"
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
+)
+
+var errMissingAlertingDirectory = errors.New("missing directory argument")
+
+// validateAlertingCommand validates the alerting provisioning files of a directory without a database, so that the
+// files can be checked in CI before they are deployed. It fails if any file is invalid.
+func validateAlertingCommand(c utils.CommandLine) error {
+	dir := c.Args().First()
+	if dir == "" {
+		return errMissingAlertingDirectory
+	}
+
+	fileErrs, err := prov_alerting.Validate(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read alerting provisioning files: %w", err)
+	}
+	for _, fileErr := range fileErrs {
+		logger.Errorf("%s\n", fileErr.Error())
+	}
+	if len(fileErrs) > 0 {
+		return fmt.Errorf("%d invalid alerting provisioning files in %s", len(fileErrs), dir)
+	}
+	logger.Infof("alerting provisioning files in %s are valid\n", dir)
+	return nil
+}
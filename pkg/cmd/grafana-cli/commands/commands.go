@@ -225,6 +225,14 @@ grafana-cli user-manager conflicts ingest-file <filepath>
 	},
 }
 
+var alertingCommands = []*cli.Command{
+	{
+		Name:   "validate",
+		Usage:  "validate <directory> - validates the alerting provisioning files of a directory without a database",
+		Action: runPluginCommand(validateAlertingCommand),
+	},
+}
+
 var Commands = []*cli.Command{
 	{
 		Name:        "plugins",
@@ -236,4 +244,9 @@ var Commands = []*cli.Command{
 		Usage:       "Grafana admin commands",
 		Subcommands: adminCommands,
 	},
+	{
+		Name:        "alerting",
+		Usage:       "Grafana alerting commands",
+		Subcommands: alertingCommands,
+	},
 }
@@ -55,14 +55,18 @@ func (f *FakeKVStore) Keys(ctx context.Context, orgId int64, namespace string, k
 func (f *FakeKVStore) GetAll(ctx context.Context, orgId int64, namespace string) (map[int64]map[string]string, error) {
 	items := make(map[int64]map[string]string)
 	for k := range f.store {
-		orgId := k.OrgId
-		namespace := k.Namespace
+		if orgId != AllOrganizations && k.OrgId != orgId {
+			continue
+		}
+		if k.Namespace != namespace {
+			continue
+		}
 
-		if _, ok := items[orgId]; !ok {
-			items[orgId] = make(map[string]string)
+		if _, ok := items[k.OrgId]; !ok {
+			items[k.OrgId] = make(map[string]string)
 		}
 
-		items[orgId][namespace] = f.store[k]
+		items[k.OrgId][k.Key] = f.store[k]
 	}
 
 	return items, nil
@@ -9,7 +9,8 @@ const (
 )
 
 var dependencyMap = map[string][]string{
-	GrafanaAPIServer: {},
+	// The apiserver waits for the core services, as they register the API groups it serves.
+	GrafanaAPIServer: {Core},
 	Core:             {},
 	All:              {Core},
 }
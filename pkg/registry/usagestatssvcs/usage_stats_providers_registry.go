@@ -3,16 +3,19 @@ package usagestatssvcs
 import (
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert"
 	"github.com/grafana/grafana/pkg/services/user"
 )
 
 func ProvideUsageStatsProvidersRegistry(
 	accesscontrol accesscontrol.Service,
 	user user.Service,
+	ngAlert *ngalert.AlertNG,
 ) *UsageStatsProvidersRegistry {
 	return NewUsageStatsProvidersRegistry(
 		accesscontrol,
 		user,
+		ngAlert,
 	)
 }
 
@@ -232,6 +232,8 @@ var wireBasicSet = wire.NewSet(
 	ngstore.ProvideDBStore,
 	ngimage.ProvideDeleteExpiredService,
 	ngalert.ProvideService,
+	ngalert.ProvideProvisioningGRPCService,
+	ngalert.ProvideNotificationsAPIService,
 	librarypanels.ProvideService,
 	wire.Bind(new(librarypanels.Service), new(*librarypanels.LibraryPanelService)),
 	libraryelements.ProvideService,
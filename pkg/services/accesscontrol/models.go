@@ -455,9 +455,14 @@ const (
 	ActionAlertingNotificationsExternalRead  = "alert.notifications.external:read"
 
 	// Alerting provisioning actions
-	ActionAlertingProvisioningRead        = "alert.provisioning:read"
-	ActionAlertingProvisioningReadSecrets = "alert.provisioning.secrets:read"
-	ActionAlertingProvisioningWrite       = "alert.provisioning:write"
+	ActionAlertingProvisioningRead              = "alert.provisioning:read"
+	ActionAlertingProvisioningReadSecrets       = "alert.provisioning.secrets:read"
+	ActionAlertingProvisioningReadMaskedSecrets = "alert.provisioning.secrets.masked:read"
+	ActionAlertingProvisioningWrite             = "alert.provisioning:write"
+
+	// Alerting receiver actions, scoped to the UIDs of contact points
+	ActionAlertingReceiversRead  = "alert.notifications.receivers:read"
+	ActionAlertingReceiversWrite = "alert.notifications.receivers:write"
 
 	// Feature Management actions
 	ActionFeatureManagementRead  = "featuremgmt.read"
@@ -475,6 +480,11 @@ var (
 	ScopeAnnotationsID               = Scope(ScopeAnnotationsRoot, "id", Parameter(":annotationId"))
 	ScopeAnnotationsTypeDashboard    = ScopeAnnotationsProvider.GetResourceScopeType(annotations.Dashboard.String())
 	ScopeAnnotationsTypeOrganization = ScopeAnnotationsProvider.GetResourceScopeType(annotations.Organization.String())
+
+	// Alerting receiver scopes
+	ScopeReceiversRoot     = "receivers"
+	ScopeReceiversProvider = NewScopeProvider(ScopeReceiversRoot)
+	ScopeReceiversAll      = ScopeReceiversProvider.GetResourceAllScope()
 )
 
 func BuiltInRolesWithParents(builtInRoles []string) map[string]struct{} {
@@ -0,0 +1,73 @@
+// Package builder lets services of Grafana serve their resources from the Grafana apiserver. It only depends on the
+// Kubernetes API machinery, so that services can register their API groups without depending on the apiserver.
+package builder
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apiserver/pkg/registry/rest"
+)
+
+// APIGroupBuilder builds an API group that is served by the Grafana apiserver.
+type APIGroupBuilder interface {
+	// GetGroupVersion returns the group and version of the API.
+	GetGroupVersion() schema.GroupVersion
+	// InstallSchema adds the kinds of the API to the scheme.
+	InstallSchema(scheme *runtime.Scheme) error
+	// GetStorage returns the storage of the resources of the API by their plural, lowercase name.
+	GetStorage() map[string]rest.Storage
+}
+
+var registry struct {
+	sync.Mutex
+	builders []APIGroupBuilder
+}
+
+// Register adds an API group to the Grafana apiserver. The apiserver installs the API groups that are registered when
+// it starts, API groups that are registered later are not served.
+func Register(b APIGroupBuilder) {
+	registry.Lock()
+	defer registry.Unlock()
+	registry.builders = append(registry.builders, b)
+}
+
+// Registered returns the API groups that have been registered so far.
+func Registered() []APIGroupBuilder {
+	registry.Lock()
+	defer registry.Unlock()
+	return append([]APIGroupBuilder(nil), registry.builders...)
+}
+
+// DefaultNamespace is the namespace of the resources of the main org.
+const DefaultNamespace = "default"
+
+const orgNamespacePrefix = "org-"
+
+// NamespaceForOrg returns the namespace of the resources of an org. The main org uses the default namespace, the
+// resources of the other orgs are in the namespace org-<id>.
+func NamespaceForOrg(orgID int64) string {
+	if orgID == 1 {
+		return DefaultNamespace
+	}
+	return orgNamespacePrefix + strconv.FormatInt(orgID, 10)
+}
+
+// OrgIDForNamespace returns the ID of the org whose resources are in the namespace.
+func OrgIDForNamespace(namespace string) (int64, error) {
+	if namespace == DefaultNamespace {
+		return 1, nil
+	}
+	if !strings.HasPrefix(namespace, orgNamespacePrefix) {
+		return 0, fmt.Errorf("namespace %q does not belong to an org", namespace)
+	}
+	orgID, err := strconv.ParseInt(strings.TrimPrefix(namespace, orgNamespacePrefix), 10, 64)
+	if err != nil || orgID < 1 {
+		return 0, fmt.Errorf("namespace %q does not belong to an org", namespace)
+	}
+	return orgID, nil
+}
@@ -3,6 +3,7 @@ package grafanaapiserver
 import (
 	"context"
 	"crypto/x509"
+	"fmt"
 	"net"
 	"os"
 	"path"
@@ -11,6 +12,11 @@ import (
 	"github.com/grafana/dskit/services"
 	grafanaapiserveroptions "github.com/grafana/grafana-apiserver/pkg/cmd/server/options"
 	"github.com/grafana/grafana/pkg/modules"
+	"github.com/grafana/grafana/pkg/services/grafana-apiserver/builder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apiserver/pkg/authentication/authenticator"
 	"k8s.io/apiserver/pkg/authentication/request/headerrequest"
 	"k8s.io/apiserver/pkg/authentication/user"
@@ -129,6 +135,12 @@ func (s *service) start(ctx context.Context) error {
 		return err
 	}
 
+	for _, b := range builder.Registered() {
+		if err := installAPIGroup(server.GenericAPIServer, b); err != nil {
+			return err
+		}
+	}
+
 	s.restConfig = server.GenericAPIServer.LoopbackClientConfig
 	err = s.writeKubeConfiguration(s.restConfig)
 	if err != nil {
@@ -213,6 +225,31 @@ func (s *service) writeKubeConfiguration(restConfig *rest.Config) error {
 	return clientcmd.WriteToFile(clientConfig, path.Join(s.dataPath, "grafana.kubeconfig"))
 }
 
+// installAPIGroup serves the resources of an API group that was registered by a service of Grafana.
+func installAPIGroup(server *genericapiserver.GenericAPIServer, b builder.APIGroupBuilder) error {
+	gv := b.GetGroupVersion()
+	scheme := runtime.NewScheme()
+	if err := b.InstallSchema(scheme); err != nil {
+		return fmt.Errorf("failed to install the schema of %s: %w", gv, err)
+	}
+	// The options of requests, like the list options, are decoded as unversioned meta types.
+	metav1.AddToGroupVersion(scheme, schema.GroupVersion{Version: "v1"})
+	scheme.AddUnversionedTypes(schema.GroupVersion{Group: "", Version: "v1"},
+		&metav1.Status{},
+		&metav1.APIVersions{},
+		&metav1.APIGroupList{},
+		&metav1.APIGroup{},
+		&metav1.APIResourceList{},
+	)
+
+	apiGroupInfo := genericapiserver.NewDefaultAPIGroupInfo(gv.Group, scheme, metav1.ParameterCodec, serializer.NewCodecFactory(scheme))
+	apiGroupInfo.VersionedResourcesStorageMap[gv.Version] = b.GetStorage()
+	if err := server.InstallAPIGroup(&apiGroupInfo); err != nil {
+		return fmt.Errorf("failed to install %s: %w", gv, err)
+	}
+	return nil
+}
+
 func newAuthenticator(cert *x509.Certificate) (authenticator.Request, error) {
 	reqHeaderOptions := options.RequestHeaderAuthenticationOptions{
 		UsernameHeaders:     []string{"X-Remote-User"},
@@ -166,6 +166,9 @@ var (
 				{
 					Action: accesscontrol.ActionAlertingProvisioningWrite, // organization scope
 				},
+				{
+					Action: accesscontrol.ActionAlertingProvisioningReadMaskedSecrets, // organization scope
+				},
 			},
 		},
 		Grants: []string{string(org.RoleAdmin)},
@@ -72,6 +72,8 @@ type API struct {
 	RuleStore            RuleStore
 	AlertingStore        AlertingStore
 	AdminConfigStore     store.AdminConfigurationStore
+	OrgStore             store.OrgStore
+	ProvisioningAudit    ProvisioningAuditStore
 	DataProxy            *datasourceproxy.DataSourceProxyService
 	MultiOrgAlertmanager *notifier.MultiOrgAlertmanager
 	StateManager         *state.Manager
@@ -81,6 +83,22 @@ type API struct {
 	Templates            *provisioning.TemplateService
 	MuteTimings          *provisioning.MuteTimingService
 	AlertRules           *provisioning.AlertRuleService
+	ConfigHealth         *provisioning.HealthService
+	AlertRuleTester      *provisioning.AlertRuleTestService
+	EffectiveConfig      *provisioning.EffectiveConfigService
+	GlobalContactPoints  *provisioning.GlobalContactPointService
+	GlobalTemplates      *provisioning.GlobalTemplateService
+	Snapshots            *provisioning.SnapshotService
+	AlertmanagerImport   *provisioning.AlertmanagerImportService
+	ConfigHistory        *provisioning.ConfigHistoryService
+	Bundles              *provisioning.BundleService
+	BundleScheduler      *provisioning.BundleScheduler
+	OrgBootstrap         *provisioning.OrgBootstrapService
+	MaintenanceWindows   *provisioning.MaintenanceWindowService
+	Silences             *provisioning.SilenceService
+	Variables            *provisioning.ProvisioningVariablesService
+	ProvisioningFreeze   *provisioning.ProvisioningFreezeService
+	Provenance           *provisioning.ProvenanceService
 	AlertsRouter         *sender.AlertsRouter
 	EvaluatorFactory     eval.EvaluatorFactory
 	FeatureManager       featuremgmt.FeatureToggles
@@ -156,6 +174,24 @@ func (api *API) RegisterAPIEndpoints(m *metrics.API) {
 		templates:           api.Templates,
 		muteTimings:         api.MuteTimings,
 		alertRules:          api.AlertRules,
+		orgs:                api.OrgStore,
+		audit:               api.ProvisioningAudit,
+		health:              api.ConfigHealth,
+		ruleTester:          api.AlertRuleTester,
+		effectiveConfig:     api.EffectiveConfig,
+		globalContactPoints: api.GlobalContactPoints,
+		globalTemplates:     api.GlobalTemplates,
+		snapshots:           api.Snapshots,
+		alertmanagerImport:  api.AlertmanagerImport,
+		configHistory:       api.ConfigHistory,
+		bundles:             api.Bundles,
+		bundleScheduler:     api.BundleScheduler,
+		orgBootstrap:        api.OrgBootstrap,
+		maintenanceWindows:  api.MaintenanceWindows,
+		silences:            api.Silences,
+		variables:           api.Variables,
+		freeze:              api.ProvisioningFreeze,
+		provenance:          api.Provenance,
 	}), m)
 
 	api.RegisterHistoryApiEndpoints(NewStateHistoryApi(&HistorySrv{
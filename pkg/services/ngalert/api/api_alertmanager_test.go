@@ -810,22 +810,27 @@ func createRequestCtxInOrg(org int64) *contextmodel.ReqContext {
 	}
 }
 
+// provenanceSetter is the part of a provisioning store that the helpers below need.
+type provenanceSetter interface {
+	SetProvenance(ctx context.Context, o ngmodels.Provisionable, org int64, p ngmodels.Provenance) error
+}
+
 // setRouteProvenance marks an org's routing tree as provisioned.
-func setRouteProvenance(t *testing.T, orgID int64, ps provisioning.ProvisioningStore) {
+func setRouteProvenance(t *testing.T, orgID int64, ps provenanceSetter) {
 	t.Helper()
 	err := ps.SetProvenance(context.Background(), &apimodels.Route{}, orgID, ngmodels.ProvenanceAPI)
 	require.NoError(t, err)
 }
 
 // setContactPointProvenance marks a contact point as provisioned.
-func setContactPointProvenance(t *testing.T, orgID int64, UID string, ps provisioning.ProvisioningStore) {
+func setContactPointProvenance(t *testing.T, orgID int64, UID string, ps provenanceSetter) {
 	t.Helper()
 	err := ps.SetProvenance(context.Background(), &apimodels.EmbeddedContactPoint{UID: UID}, orgID, ngmodels.ProvenanceAPI)
 	require.NoError(t, err)
 }
 
 // setTemplateProvenance marks a template as provisioned.
-func setTemplateProvenance(t *testing.T, orgID int64, name string, ps provisioning.ProvisioningStore) {
+func setTemplateProvenance(t *testing.T, orgID int64, name string, ps provenanceSetter) {
 	t.Helper()
 	err := ps.SetProvenance(context.Background(), &apimodels.NotificationTemplate{Name: name}, orgID, ngmodels.ProvenanceAPI)
 	require.NoError(t, err)
@@ -5,7 +5,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
 
 	"github.com/grafana/grafana/pkg/api/response"
 	"github.com/grafana/grafana/pkg/infra/log"
@@ -20,6 +27,12 @@ import (
 
 const disableProvenanceHeaderName = "X-Disable-Provenance"
 
+// totalCountHeader is the number of items that match a paged query.
+const totalCountHeader = "X-Total-Count"
+
+// ifMatchHeader is the version of the object that a change is based on.
+const ifMatchHeader = "If-Match"
+
 type ProvisioningSrv struct {
 	log                 log.Logger
 	policies            NotificationPolicyService
@@ -27,71 +40,165 @@ type ProvisioningSrv struct {
 	templates           TemplateService
 	muteTimings         MuteTimingService
 	alertRules          AlertRuleService
+	orgs                store.OrgStore
+	audit               ProvisioningAuditStore
+	health              ConfigHealthService
+	ruleTester          AlertRuleTestService
+	effectiveConfig     EffectiveConfigService
+	globalContactPoints GlobalContactPointService
+	globalTemplates     GlobalTemplateService
+	snapshots           SnapshotService
+	alertmanagerImport  AlertmanagerImportService
+	configHistory       ConfigHistoryService
+	bundles             ProvisioningBundleService
+	bundleScheduler     ProvisioningBundleScheduler
+	orgBootstrap        OrgBootstrapService
+	maintenanceWindows  MaintenanceWindowService
+	silences            SilenceService
+	variables           ProvisioningVariablesService
+	freeze              ProvisioningFreezeService
+	provenance          ProvenanceService
 }
 
 type ContactPointService interface {
 	GetContactPoints(ctx context.Context, q provisioning.ContactPointQuery, user *user.SignedInUser) ([]definitions.EmbeddedContactPoint, error)
+	StreamContactPoints(ctx context.Context, q provisioning.ContactPointQuery, user *user.SignedInUser) (*provisioning.ContactPointStream, error)
 	CreateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance) (definitions.EmbeddedContactPoint, error)
-	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance) error
-	DeleteContactPoint(ctx context.Context, orgID int64, uid string) error
+	CreateContactPoints(ctx context.Context, orgID int64, contactPoints []definitions.EmbeddedContactPoint, p alerting_models.Provenance) ([]definitions.EmbeddedContactPoint, error)
+	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance, opts provisioning.UpdateContactPointOptions) error
+	DeleteContactPoint(ctx context.Context, orgID int64, uid string, opts provisioning.DeleteContactPointOptions) error
+	ListDeletedContactPoints(ctx context.Context, orgID int64) ([]definitions.DeletedContactPoint, error)
+	GetContactPointStatus(ctx context.Context, orgID int64) ([]definitions.ContactPointStatus, error)
+	EnableContactPoint(ctx context.Context, orgID int64, uid string) (definitions.EmbeddedContactPoint, error)
+	DisableIntegration(ctx context.Context, orgID int64, uid string) (definitions.EmbeddedContactPoint, error)
+	RestoreContactPoint(ctx context.Context, orgID int64, uid string, p alerting_models.Provenance) (definitions.EmbeddedContactPoint, error)
+	MigrateContactPoint(ctx context.Context, orgID int64, uid string, p alerting_models.Provenance) (definitions.EmbeddedContactPoint, error)
+	RotateContactPointSecrets(ctx context.Context, orgID int64, uid string, newSecrets map[string]string, p alerting_models.Provenance) error
+	TestContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, alert *definitions.TestReceiversConfigAlertParams) (definitions.ContactPointTestResult, error)
 }
 
 type TemplateService interface {
 	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
 	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error)
 	DeleteTemplate(ctx context.Context, orgID int64, name string) error
+	PreviewTemplate(ctx context.Context, orgID int64, content string, alerts []*amv2.PostableAlert) (definitions.TemplatePreview, error)
 }
 
 type NotificationPolicyService interface {
 	GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
-	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance) error
+	UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p alerting_models.Provenance) (definitions.PolicyTreeDiff, error)
 	ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error)
+	CreateRoute(ctx context.Context, orgID int64, parent provisioning.RouteRef, route definitions.Route, p alerting_models.Provenance) (definitions.Route, error)
+	UpdateRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, route definitions.Route, p alerting_models.Provenance) (definitions.Route, error)
+	DeleteRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, p alerting_models.Provenance) error
+	TestRoutePolicy(ctx context.Context, orgID int64, labelSets []model.LabelSet) ([]definitions.RoutePolicyTestResult, error)
 }
 
 type MuteTimingService interface {
 	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
 	CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
 	UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
-	DeleteMuteTiming(ctx context.Context, name string, orgID int64) error
+	DeleteMuteTiming(ctx context.Context, name string, orgID int64, opts provisioning.DeleteMuteTimingOptions) error
+	GetMuteTimingUsage(ctx context.Context, name string, orgID int64) (definitions.MuteTimingUsage, error)
+	PreviewMuteTiming(ctx context.Context, orgID int64, name string, from, to time.Time) (definitions.MuteTimingPreview, error)
 }
 
 type AlertRuleService interface {
-	GetAlertRules(ctx context.Context, orgID int64) ([]*alerting_models.AlertRule, error)
+	GetAlertRules(ctx context.Context, q provisioning.AlertRuleQuery) ([]*alerting_models.AlertRule, map[string]alerting_models.Provenance, error)
 	GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (alerting_models.AlertRule, alerting_models.Provenance, error)
 	CreateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance, userID int64) (alerting_models.AlertRule, error)
 	UpdateAlertRule(ctx context.Context, rule alerting_models.AlertRule, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
+	PatchAlertRule(ctx context.Context, orgID int64, ruleUID string, patch provisioning.AlertRulePatch, provenance alerting_models.Provenance) (alerting_models.AlertRule, error)
 	DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance alerting_models.Provenance) error
+	GetOrphanedRuleLinks(ctx context.Context, orgID int64) ([]provisioning.OrphanedRuleLink, error)
+	ClearOrphanedRuleLinks(ctx context.Context, orgID int64) ([]provisioning.OrphanedRuleLink, error)
 	GetRuleGroup(ctx context.Context, orgID int64, folder, group string) (alerting_models.AlertRuleGroup, error)
 	ReplaceRuleGroup(ctx context.Context, orgID int64, group alerting_models.AlertRuleGroup, userID int64, provenance alerting_models.Provenance) error
+	SetRuleGroupPaused(ctx context.Context, orgID int64, folder, group string, paused bool) error
+	GetRuleGroupEvaluation(ctx context.Context, orgID int64, folder, group string) (alerting_models.RuleGroupEvaluation, error)
+	SetRuleGroupEvaluation(ctx context.Context, orgID int64, folder, group string, evaluation alerting_models.RuleGroupEvaluation) error
+	EstimateRuleGroupLoad(ctx context.Context, orgID int64, folder, group string) (provisioning.RuleGroupLoad, error)
+	ImportPrometheusRules(ctx context.Context, orgID int64, userID int64, imp definitions.AlertRuleImport, provenance alerting_models.Provenance) (definitions.AlertRuleImportResult, error)
 	GetAlertRuleWithFolderTitle(ctx context.Context, orgID int64, ruleUID string) (provisioning.AlertRuleWithFolderTitle, error)
 	GetAlertRuleGroupWithFolderTitle(ctx context.Context, orgID int64, folder, group string) (alerting_models.AlertRuleGroupWithFolderTitle, error)
-	GetAlertGroupsWithFolderTitle(ctx context.Context, orgID int64) ([]alerting_models.AlertRuleGroupWithFolderTitle, error)
+	GetAlertGroupsWithFolderTitle(ctx context.Context, q provisioning.AlertRuleQuery) ([]alerting_models.AlertRuleGroupWithFolderTitle, error)
 }
 
 func (srv *ProvisioningSrv) RouteGetPolicyTree(c *contextmodel.ReqContext) response.Response {
+	q, err := parsePolicySubtreeQuery(c)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
 	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgID)
 	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
-		return ErrResp(http.StatusNotFound, err, "")
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+
+	policies, err = provisioning.SelectPolicySubtree(policies, q)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
 	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 
 	return response.JSON(http.StatusOK, policies)
 }
 
+// parsePolicySubtreeQuery reads the optional path and depth query parameters of the policy tree endpoint.
+// The path is a dot-separated list of child indexes, e.g. "0.2" selects the third child of the first child of the root.
+func parsePolicySubtreeQuery(c *contextmodel.ReqContext) (provisioning.PolicySubtreeQuery, error) {
+	q := provisioning.PolicySubtreeQuery{}
+	path, err := parseRoutePath(c.Query("path"))
+	if err != nil {
+		return q, err
+	}
+	q.Path = path
+	if depth := c.Query("depth"); depth != "" {
+		d, err := strconv.Atoi(depth)
+		if err != nil {
+			return q, fmt.Errorf("invalid depth %q: %w", depth, err)
+		}
+		q.Depth = d
+	}
+	return q, nil
+}
+
+// parseRoutePath parses a dot-separated list of child indexes. An empty string is the path of the root.
+func parseRoutePath(path string) ([]int, error) {
+	if path == "" {
+		return nil, nil
+	}
+	// Longer paths are rejected before they are split, so that the size of the query does not matter.
+	if strings.Count(path, ".") >= provisioning.MaxPolicySubtreeDepth {
+		return nil, fmt.Errorf("invalid path: it must not have more than %d segments", provisioning.MaxPolicySubtreeDepth)
+	}
+	var result []int
+	for _, segment := range strings.Split(path, ".") {
+		idx, err := strconv.Atoi(segment)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %q: segments must be child indexes", path)
+		}
+		result = append(result, idx)
+	}
+	return result, nil
+}
+
 func (srv *ProvisioningSrv) RouteGetPolicyTreeExport(c *contextmodel.ReqContext) response.Response {
 	policies, err := srv.policies.GetPolicyTree(c.Req.Context(), c.OrgID)
 	if err != nil {
 		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
-			return ErrResp(http.StatusNotFound, err, "")
+			return provisioningErrResp(http.StatusNotFound, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 
 	e, err := AlertingFileExportFromRoute(c.OrgID, policies)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
 
 	return exportResponse(c, e)
@@ -99,60 +206,184 @@ func (srv *ProvisioningSrv) RouteGetPolicyTreeExport(c *contextmodel.ReqContext)
 
 func (srv *ProvisioningSrv) RoutePutPolicyTree(c *contextmodel.ReqContext, tree definitions.Route) response.Response {
 	provenance := determineProvenance(c)
-	err := srv.policies.UpdatePolicyTree(c.Req.Context(), c.OrgID, tree, alerting_models.Provenance(provenance))
+	ctx, dryRun := dryRunContext(c)
+	diff, err := srv.policies.UpdatePolicyTree(ctx, c.OrgID, tree, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrDryRun) {
+		return response.JSON(http.StatusOK, dryRun.Result())
+	}
+	if resp, ok := brokenPolicyReferencesResp(err); ok {
+		return resp
+	}
 	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
-		return ErrResp(http.StatusNotFound, err, "")
+		return provisioningErrResp(http.StatusNotFound, err, "")
 	}
 	if errors.Is(err, provisioning.ErrValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
 	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 
-	return response.JSON(http.StatusAccepted, util.DynMap{"message": "policies updated"})
+	return response.JSON(http.StatusAccepted, definitions.PolicyTreeUpdateResult{Message: "policies updated", Diff: diff})
 }
 
 func (srv *ProvisioningSrv) RouteResetPolicyTree(c *contextmodel.ReqContext) response.Response {
-	tree, err := srv.policies.ResetPolicyTree(c.Req.Context(), c.OrgID)
+	ctx, dryRun := dryRunContext(c)
+	tree, err := srv.policies.ResetPolicyTree(ctx, c.OrgID)
+	if errors.Is(err, provisioning.ErrDryRun) {
+		return response.JSON(http.StatusOK, dryRun.Result())
+	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusAccepted, tree)
 }
 
+func (srv *ProvisioningSrv) RoutePostPolicyTreeTest(c *contextmodel.ReqContext, body definitions.RoutePolicyTest) response.Response {
+	results, err := srv.policies.TestRoutePolicy(c.Req.Context(), c.OrgID, body.LabelSets)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to test the notification policy tree")
+	}
+	return response.JSON(http.StatusOK, results)
+}
+
+func (srv *ProvisioningSrv) RoutePostPolicyRoute(c *contextmodel.ReqContext, route definitions.Route) response.Response {
+	parent := provisioning.RouteRef{UID: c.Query("parent")}
+	if parent.UID == "" {
+		path, err := parseRoutePath(c.Query("path"))
+		if err != nil {
+			return provisioningErrResp(http.StatusBadRequest, err, "")
+		}
+		parent.Path = path
+	}
+	provenance := determineProvenance(c)
+	ctx, dryRun := dryRunContext(c)
+	created, err := srv.policies.CreateRoute(ctx, c.OrgID, parent, route, alerting_models.Provenance(provenance))
+	if err != nil {
+		return policyRouteErrResp(err, dryRun)
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutPolicyRoute(c *contextmodel.ReqContext, route definitions.Route, UID string) response.Response {
+	provenance := determineProvenance(c)
+	ctx, dryRun := dryRunContext(c)
+	updated, err := srv.policies.UpdateRoute(ctx, c.OrgID, provisioning.RouteRef{UID: UID}, route, alerting_models.Provenance(provenance))
+	if err != nil {
+		return policyRouteErrResp(err, dryRun)
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeletePolicyRoute(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := determineProvenance(c)
+	ctx, dryRun := dryRunContext(c)
+	err := srv.policies.DeleteRoute(ctx, c.OrgID, provisioning.RouteRef{UID: UID}, alerting_models.Provenance(provenance))
+	if err != nil {
+		return policyRouteErrResp(err, dryRun)
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func policyRouteErrResp(err error, dryRun *provisioning.DryRun) response.Response {
+	if errors.Is(err, provisioning.ErrDryRun) {
+		return response.JSON(http.StatusOK, dryRun.Result())
+	}
+	if errors.Is(err, provisioning.ErrNotFound) || errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if resp, ok := brokenPolicyReferencesResp(err); ok {
+		return resp
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) || errors.Is(err, provisioning.ErrVersionConflict) {
+		return provisioningErrResp(http.StatusConflict, err, "")
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	return provisioningErrResp(http.StatusInternalServerError, err, "")
+}
+
+// brokenPolicyReferencesResp lists the broken references of a rejected notification policy tree, if there are any.
+func brokenPolicyReferencesResp(err error) (response.Response, bool) {
+	var broken *provisioning.BrokenPolicyReferencesError
+	if !errors.As(err, &broken) {
+		return nil, false
+	}
+	return response.JSON(http.StatusBadRequest, definitions.BrokenPolicyReferences{
+		Message:          err.Error(),
+		BrokenReferences: broken.References,
+	}), true
+}
+
 func (srv *ProvisioningSrv) RouteGetContactPoints(c *contextmodel.ReqContext) response.Response {
 	q := provisioning.ContactPointQuery{
-		Name:  c.Query("name"),
-		OrgID: c.OrgID,
+		Name:         c.Query("name"),
+		NamePatterns: c.QueryStrings("namePattern"),
+		Types:        c.QueryStrings("type"),
+		OrgID:        c.OrgID,
+		SortBy:       provisioning.ContactPointSortBy(c.Query("sortBy")),
+		Offset:       c.QueryInt("offset"),
+		Limit:        c.QueryInt("limit"),
+		Mask:         c.QueryBoolWithDefault("mask", false),
 	}
-	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q, nil)
+	stream, err := srv.contactPointService.StreamContactPoints(c.Req.Context(), q, c.SignedInUser)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return provisioningErrResp(http.StatusBadRequest, err, "")
+		}
 		if errors.Is(err, provisioning.ErrPermissionDenied) {
-			return ErrResp(http.StatusForbidden, err, "")
+			return provisioningErrResp(http.StatusForbidden, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
-	return response.JSON(http.StatusOK, cps)
+	return contactPointStreamResponse{ctx: c.Req.Context(), stream: stream}
 }
 
 func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *contextmodel.ReqContext) response.Response {
+	target := c.Query("target")
+	if target == "" {
+		target = exportTargetGrafana
+	}
+	if target != exportTargetGrafana && target != exportTargetAlertmanager {
+		return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("unsupported export target '%s', expected '%s' or '%s'", target, exportTargetGrafana, exportTargetAlertmanager), "")
+	}
 	q := provisioning.ContactPointQuery{
-		Name:    c.Query("name"),
-		OrgID:   c.OrgID,
-		Decrypt: c.QueryBoolWithDefault("decrypt", false),
+		Name:         c.Query("name"),
+		NamePatterns: c.QueryStrings("namePattern"),
+		Types:        c.QueryStrings("type"),
+		OrgID:        c.OrgID,
+		Decrypt:      c.QueryBoolWithDefault("decrypt", false),
 	}
 	cps, err := srv.contactPointService.GetContactPoints(c.Req.Context(), q, c.SignedInUser)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrValidation) {
+			return provisioningErrResp(http.StatusBadRequest, err, "")
+		}
 		if errors.Is(err, provisioning.ErrPermissionDenied) {
-			return ErrResp(http.StatusForbidden, err, "")
+			return provisioningErrResp(http.StatusForbidden, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 
+	if target == exportTargetAlertmanager {
+		return exportResponse(c, AlertmanagerReceiversExportFromEmbeddedContactPoints(cps))
+	}
 	e, err := AlertingFileExportFromEmbeddedContactPoints(c.OrgID, cps)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
 	}
 
 	return exportResponse(c, e)
@@ -160,44 +391,217 @@ func (srv *ProvisioningSrv) RouteGetContactPointsExport(c *contextmodel.ReqConte
 
 func (srv *ProvisioningSrv) RoutePostContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint) response.Response {
 	provenance := determineProvenance(c)
-	contactPoint, err := srv.contactPointService.CreateContactPoint(c.Req.Context(), c.OrgID, cp, alerting_models.Provenance(provenance))
+	ctx, dryRun := dryRunContext(c)
+	contactPoint, err := srv.contactPointService.CreateContactPoint(ctx, c.OrgID, cp, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrDryRun) {
+		return response.JSON(http.StatusOK, dryRun.Result())
+	}
 	if errors.Is(err, provisioning.ErrValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
 	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusAccepted, contactPoint)
 }
 
+func (srv *ProvisioningSrv) RoutePostContactPoints(c *contextmodel.ReqContext, cps definitions.ContactPoints) response.Response {
+	provenance := determineProvenance(c)
+	contactPoints, err := srv.contactPointService.CreateContactPoints(c.Req.Context(), c.OrgID, cps, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrQuotaReached) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoints)
+}
+
+// RoutePostContactPointTest responds with the result of the test notification even if it was not delivered.
+func (srv *ProvisioningSrv) RoutePostContactPointTest(c *contextmodel.ReqContext, test definitions.ContactPointTest) response.Response {
+	ctx, cancelFunc, err := contextWithTimeoutFromRequest(
+		c.Req.Context(),
+		c.Req,
+		defaultTestReceiversTimeout,
+		maxTestReceiversTimeout)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	defer cancelFunc()
+
+	result, err := srv.contactPointService.TestContactPoint(ctx, c.OrgID, test.ContactPoint, test.Alert)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
 func (srv *ProvisioningSrv) RoutePutContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint, UID string) response.Response {
 	cp.UID = UID
 	provenance := determineProvenance(c)
-	err := srv.contactPointService.UpdateContactPoint(c.Req.Context(), c.OrgID, cp, alerting_models.Provenance(provenance))
+	opts := provisioning.UpdateContactPointOptions{
+		CascadeRename: c.QueryBoolWithDefault("cascadeRename", false),
+		Version:       requestedVersion(c),
+	}
+	ctx, dryRun := dryRunContext(c)
+	err := srv.contactPointService.UpdateContactPoint(ctx, c.OrgID, cp, alerting_models.Provenance(provenance), opts)
+	if errors.Is(err, provisioning.ErrDryRun) {
+		return response.JSON(http.StatusOK, dryRun.Result())
+	}
 	if errors.Is(err, provisioning.ErrValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
 	}
 	if errors.Is(err, provisioning.ErrNotFound) {
-		return ErrResp(http.StatusNotFound, err, "")
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+		return provisioningErrResp(http.StatusConflict, err, "")
 	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint updated"})
 }
 
+func (srv *ProvisioningSrv) RoutePutContactPointSecrets(c *contextmodel.ReqContext, secrets definitions.ContactPointSecrets, UID string) response.Response {
+	provenance := determineProvenance(c)
+	err := srv.contactPointService.RotateContactPointSecrets(c.Req.Context(), c.OrgID, UID, secrets, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint secrets rotated"})
+}
+
+func (srv *ProvisioningSrv) RoutePostContactPointMigrate(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := determineProvenance(c)
+	contactPoint, err := srv.contactPointService.MigrateContactPoint(c.Req.Context(), c.OrgID, UID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoint)
+}
+
 func (srv *ProvisioningSrv) RouteDeleteContactPoint(c *contextmodel.ReqContext, UID string) response.Response {
-	err := srv.contactPointService.DeleteContactPoint(c.Req.Context(), c.OrgID, UID)
+	opts := provisioning.DeleteContactPointOptions{
+		Recoverable: !c.QueryBoolWithDefault("permanent", false),
+		Version:     requestedVersion(c),
+	}
+	ctx, dryRun := dryRunContext(c)
+	err := srv.contactPointService.DeleteContactPoint(ctx, c.OrgID, UID, opts)
+	if errors.Is(err, provisioning.ErrDryRun) {
+		return response.JSON(http.StatusOK, dryRun.Result())
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if errors.Is(err, provisioning.ErrVersionConflict) || errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+		return provisioningErrResp(http.StatusConflict, err, "")
+	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint deleted"})
 }
 
+func (srv *ProvisioningSrv) RouteGetDeletedContactPoints(c *contextmodel.ReqContext) response.Response {
+	deleted, err := srv.contactPointService.ListDeletedContactPoints(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, deleted)
+}
+
+func (srv *ProvisioningSrv) RouteGetContactPointStatus(c *contextmodel.ReqContext) response.Response {
+	statuses, err := srv.contactPointService.GetContactPointStatus(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	if c.QueryBoolWithDefault("failing", false) {
+		failing := make([]definitions.ContactPointStatus, 0, len(statuses))
+		for _, status := range statuses {
+			if status.LastNotifyAttemptError != "" || status.LastProbeError != "" || status.Disabled {
+				failing = append(failing, status)
+			}
+		}
+		statuses = failing
+	}
+	return response.JSON(http.StatusOK, statuses)
+}
+
+func (srv *ProvisioningSrv) RoutePostContactPointEnable(c *contextmodel.ReqContext, UID string) response.Response {
+	contactPoint, err := srv.contactPointService.EnableContactPoint(c.Req.Context(), c.OrgID, UID)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoint)
+}
+
+func (srv *ProvisioningSrv) RoutePostContactPointDisable(c *contextmodel.ReqContext, UID string) response.Response {
+	contactPoint, err := srv.contactPointService.DisableIntegration(c.Req.Context(), c.OrgID, UID)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoint)
+}
+
+func (srv *ProvisioningSrv) RoutePostContactPointRestore(c *contextmodel.ReqContext, UID string) response.Response {
+	provenance := determineProvenance(c)
+	contactPoint, err := srv.contactPointService.RestoreContactPoint(c.Req.Context(), c.OrgID, UID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoint)
+}
+
 func (srv *ProvisioningSrv) RouteGetTemplates(c *contextmodel.ReqContext) response.Response {
 	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgID)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	result := make([]definitions.NotificationTemplate, 0, len(templates))
 	for k, v := range templates {
@@ -206,10 +610,25 @@ func (srv *ProvisioningSrv) RouteGetTemplates(c *contextmodel.ReqContext) respon
 	return response.JSON(http.StatusOK, result)
 }
 
+func (srv *ProvisioningSrv) RouteGetTemplatesExport(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	result := make([]definitions.NotificationTemplate, 0, len(templates))
+	for k, v := range templates {
+		result = append(result, definitions.NotificationTemplate{Name: k, Template: v})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return exportResponse(c, AlertingFileExportFromTemplates(c.OrgID, result))
+}
+
 func (srv *ProvisioningSrv) RouteGetTemplate(c *contextmodel.ReqContext, name string) response.Response {
 	templates, err := srv.templates.GetTemplates(c.Req.Context(), c.OrgID)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	if tmpl, ok := templates[name]; ok {
 		return response.JSON(http.StatusOK, definitions.NotificationTemplate{Name: name, Template: tmpl})
@@ -226,25 +645,39 @@ func (srv *ProvisioningSrv) RoutePutTemplate(c *contextmodel.ReqContext, body de
 	modified, err := srv.templates.SetTemplate(c.Req.Context(), c.OrgID, tmpl)
 	if err != nil {
 		if errors.Is(err, provisioning.ErrValidation) {
-			return ErrResp(http.StatusBadRequest, err, "")
+			return provisioningErrResp(http.StatusBadRequest, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		if errors.Is(err, provisioning.ErrQuotaReached) {
+			return provisioningErrResp(http.StatusForbidden, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusAccepted, modified)
 }
 
 func (srv *ProvisioningSrv) RouteDeleteTemplate(c *contextmodel.ReqContext, name string) response.Response {
 	err := srv.templates.DeleteTemplate(c.Req.Context(), c.OrgID, name)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusNoContent, nil)
 }
 
+func (srv *ProvisioningSrv) RoutePostTemplatePreview(c *contextmodel.ReqContext, body definitions.TemplatePreviewParams) response.Response {
+	preview, err := srv.templates.PreviewTemplate(c.Req.Context(), c.OrgID, body.Template, body.Alerts)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, preview)
+}
+
 func (srv *ProvisioningSrv) RouteGetMuteTiming(c *contextmodel.ReqContext, name string) response.Response {
 	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	for _, timing := range timings {
 		if name == timing.Name {
@@ -257,19 +690,37 @@ func (srv *ProvisioningSrv) RouteGetMuteTiming(c *contextmodel.ReqContext, name
 func (srv *ProvisioningSrv) RouteGetMuteTimings(c *contextmodel.ReqContext) response.Response {
 	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusOK, timings)
 }
 
+func (srv *ProvisioningSrv) RouteGetMuteTimingsExport(c *contextmodel.ReqContext) response.Response {
+	timings, err := srv.muteTimings.GetMuteTimings(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		return timings[i].Name < timings[j].Name
+	})
+	return exportResponse(c, AlertingFileExportFromMuteTimings(c.OrgID, timings))
+}
+
 func (srv *ProvisioningSrv) RoutePostMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval) response.Response {
 	mt.Provenance = determineProvenance(c)
-	created, err := srv.muteTimings.CreateMuteTiming(c.Req.Context(), mt, c.OrgID)
+	ctx, dryRun := dryRunContext(c)
+	created, err := srv.muteTimings.CreateMuteTiming(ctx, mt, c.OrgID)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrDryRun) {
+			return response.JSON(http.StatusOK, dryRun.Result())
+		}
 		if errors.Is(err, provisioning.ErrValidation) {
-			return ErrResp(http.StatusBadRequest, err, "")
+			return provisioningErrResp(http.StatusBadRequest, err, "")
+		}
+		if errors.Is(err, provisioning.ErrQuotaReached) {
+			return provisioningErrResp(http.StatusForbidden, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusCreated, created)
 }
@@ -277,12 +728,16 @@ func (srv *ProvisioningSrv) RoutePostMuteTiming(c *contextmodel.ReqContext, mt d
 func (srv *ProvisioningSrv) RoutePutMuteTiming(c *contextmodel.ReqContext, mt definitions.MuteTimeInterval, name string) response.Response {
 	mt.Name = name
 	mt.Provenance = determineProvenance(c)
-	updated, err := srv.muteTimings.UpdateMuteTiming(c.Req.Context(), mt, c.OrgID)
+	ctx, dryRun := dryRunContext(c)
+	updated, err := srv.muteTimings.UpdateMuteTiming(ctx, mt, c.OrgID)
 	if err != nil {
+		if errors.Is(err, provisioning.ErrDryRun) {
+			return response.JSON(http.StatusOK, dryRun.Result())
+		}
 		if errors.Is(err, provisioning.ErrValidation) {
-			return ErrResp(http.StatusBadRequest, err, "")
+			return provisioningErrResp(http.StatusBadRequest, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	if updated == nil {
 		return response.Empty(http.StatusNotFound)
@@ -290,20 +745,104 @@ func (srv *ProvisioningSrv) RoutePutMuteTiming(c *contextmodel.ReqContext, mt de
 	return response.JSON(http.StatusAccepted, updated)
 }
 
+func (srv *ProvisioningSrv) RouteGetMuteTimingUsage(c *contextmodel.ReqContext, name string) response.Response {
+	usage, err := srv.muteTimings.GetMuteTimingUsage(c.Req.Context(), name, c.OrgID)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, usage)
+}
+
+func (srv *ProvisioningSrv) RouteGetMuteTimingPreview(c *contextmodel.ReqContext, name string) response.Response {
+	from, to := time.Now(), time.Time{}
+	for _, p := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &from}, {"to", &to}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("invalid %s %q: %w", p.name, v, err), "")
+		}
+		*p.target = time.Unix(seconds, 0)
+	}
+	if to.IsZero() {
+		to = from.Add(7 * 24 * time.Hour)
+	}
+	preview, err := srv.muteTimings.PreviewMuteTiming(c.Req.Context(), c.OrgID, name, from, to)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, preview)
+}
+
 func (srv *ProvisioningSrv) RouteDeleteMuteTiming(c *contextmodel.ReqContext, name string) response.Response {
-	err := srv.muteTimings.DeleteMuteTiming(c.Req.Context(), name, c.OrgID)
+	opts := provisioning.DeleteMuteTimingOptions{
+		Force: c.QueryBoolWithDefault("force", false),
+	}
+	ctx, dryRun := dryRunContext(c)
+	err := srv.muteTimings.DeleteMuteTiming(ctx, name, c.OrgID, opts)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		if errors.Is(err, provisioning.ErrDryRun) {
+			return response.JSON(http.StatusOK, dryRun.Result())
+		}
+		if errors.Is(err, provisioning.ErrValidation) {
+			return provisioningErrResp(http.StatusBadRequest, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusNoContent, nil)
 }
 
 func (srv *ProvisioningSrv) RouteGetAlertRules(c *contextmodel.ReqContext) response.Response {
-	rules, err := srv.alertRules.GetAlertRules(c.Req.Context(), c.OrgID)
+	q, err := parseAlertRuleQuery(c)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	rules, provenances, err := srv.alertRules.GetAlertRules(c.Req.Context(), q)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRules(rules, provenances))
+}
+
+// parseAlertRuleQuery returns the query of the alert rules the request filters by. Label matchers are given in the
+// text format of the Alertmanager, for example severity=~"critical|major".
+func parseAlertRuleQuery(c *contextmodel.ReqContext) (provisioning.AlertRuleQuery, error) {
+	q := provisioning.AlertRuleQuery{
+		OrgID:          c.OrgID,
+		Title:          c.Query("title"),
+		DatasourceUIDs: c.QueryStrings("datasourceUid"),
+		FolderUIDs:     c.QueryStrings("folderUid"),
+		RuleGroups:     c.QueryStrings("group"),
 	}
-	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRules(rules))
+	for _, s := range c.QueryStrings("label") {
+		m, err := labels.ParseMatcher(s)
+		if err != nil {
+			return q, fmt.Errorf("invalid label matcher %q: %w", s, err)
+		}
+		q.Matchers = append(q.Matchers, m)
+	}
+	if paused := c.Query("paused"); paused != "" {
+		p, err := strconv.ParseBool(paused)
+		if err != nil {
+			return q, fmt.Errorf("invalid paused %q: %w", paused, err)
+		}
+		q.Paused = &p
+	}
+	return q, nil
 }
 
 func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
@@ -312,7 +851,7 @@ func (srv *ProvisioningSrv) RouteRouteGetAlertRule(c *contextmodel.ReqContext, U
 		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
 			return response.Empty(http.StatusNotFound)
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRule(rule, provenace))
 }
@@ -321,117 +860,202 @@ func (srv *ProvisioningSrv) RoutePostAlertRule(c *contextmodel.ReqContext, ar de
 	upstreamModel, err := AlertRuleFromProvisionedAlertRule(ar)
 	upstreamModel.OrgID = c.OrgID
 	if err != nil {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 	provenance := determineProvenance(c)
 	createdAlertRule, err := srv.alertRules.CreateAlertRule(c.Req.Context(), upstreamModel, alerting_models.Provenance(provenance), c.UserID)
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 	if err != nil {
 		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+			return provisioningErrResp(http.StatusConflict, err, "")
 		}
 		if errors.Is(err, alerting_models.ErrQuotaReached) {
-			return ErrResp(http.StatusForbidden, err, "")
+			return provisioningErrResp(http.StatusForbidden, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 
 	resp := ProvisionedAlertRuleFromAlertRule(createdAlertRule, alerting_models.Provenance(provenance))
 	return response.JSON(http.StatusCreated, resp)
 }
 
+func (srv *ProvisioningSrv) RoutePostAlertRuleImport(c *contextmodel.ReqContext, body definitions.AlertRuleImport) response.Response {
+	provenance := determineProvenance(c)
+	result, err := srv.alertRules.ImportPrometheusRules(c.Req.Context(), c.OrgID, c.UserID, body, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrQuotaReached) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to import the rule file")
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleLint(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule) response.Response {
+	rule, err := AlertRuleFromProvisionedAlertRule(ar)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	findings := provisioning.LintAlertRule(rule)
+	return response.JSON(http.StatusOK, AlertRuleLintResultToApi(findings))
+}
+
 func (srv *ProvisioningSrv) RoutePutAlertRule(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule, UID string) response.Response {
 	updated, err := AlertRuleFromProvisionedAlertRule(ar)
 	if err != nil {
-		ErrResp(http.StatusBadRequest, err, "")
+		provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 	updated.OrgID = c.OrgID
 	updated.UID = UID
 	provenance := determineProvenance(c)
 	updatedAlertRule, err := srv.alertRules.UpdateAlertRule(c.Req.Context(), updated, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
 	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
 		return response.Empty(http.StatusNotFound)
 	}
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 	if err != nil {
 		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+			return provisioningErrResp(http.StatusConflict, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 
 	resp := ProvisionedAlertRuleFromAlertRule(updatedAlertRule, alerting_models.Provenance(provenance))
 	return response.JSON(http.StatusOK, resp)
 }
 
+func (srv *ProvisioningSrv) RoutePatchAlertRule(c *contextmodel.ReqContext, patch definitions.AlertRulePatch, UID string) response.Response {
+	provenance := determineProvenance(c)
+	updated, err := srv.alertRules.PatchAlertRule(c.Req.Context(), c.OrgID, UID, AlertRulePatchFromApiAlertRulePatch(patch), alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
+		return response.Empty(http.StatusNotFound)
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) {
+		return provisioningErrResp(http.StatusConflict, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, ProvisionedAlertRuleFromAlertRule(updated, alerting_models.Provenance(provenance)))
+}
+
 func (srv *ProvisioningSrv) RouteDeleteAlertRule(c *contextmodel.ReqContext, UID string) response.Response {
 	provenance := determineProvenance(c)
 	err := srv.alertRules.DeleteAlertRule(c.Req.Context(), c.OrgID, UID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusNoContent, "")
 }
 
+func (srv *ProvisioningSrv) RouteGetOrphanedRuleLinks(c *contextmodel.ReqContext) response.Response {
+	links, err := srv.alertRules.GetOrphanedRuleLinks(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get orphaned links of alert rules")
+	}
+	return response.JSON(http.StatusOK, OrphanedRuleLinksToApi(links))
+}
+
+func (srv *ProvisioningSrv) RouteDeleteOrphanedRuleLinks(c *contextmodel.ReqContext) response.Response {
+	links, err := srv.alertRules.ClearOrphanedRuleLinks(c.Req.Context(), c.OrgID)
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrOptimisticLock) {
+			return provisioningErrResp(http.StatusConflict, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to clear orphaned links of alert rules")
+	}
+	return response.JSON(http.StatusOK, OrphanedRuleLinksToApi(links))
+}
+
 func (srv *ProvisioningSrv) RouteGetAlertRuleGroup(c *contextmodel.ReqContext, folder string, group string) response.Response {
 	g, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgID, folder, group)
 	if err != nil {
 		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
-			return ErrResp(http.StatusNotFound, err, "")
+			return provisioningErrResp(http.StatusNotFound, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusOK, ApiAlertRuleGroupFromAlertRuleGroup(g))
 }
 
-// RouteGetAlertRulesExport retrieves all alert rules in a format compatible with file provisioning.
+// RouteGetAlertRulesExport retrieves the alert rules that match the filters of the request in a format compatible with
+// file provisioning, or as Prometheus rule groups.
 func (srv *ProvisioningSrv) RouteGetAlertRulesExport(c *contextmodel.ReqContext) response.Response {
-	groupsWithTitle, err := srv.alertRules.GetAlertGroupsWithFolderTitle(c.Req.Context(), c.OrgID)
+	if err := validateAlertRulesExportTarget(c); err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	q, err := parseAlertRuleQuery(c)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
 	}
-
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groupsWithTitle)
+	groupsWithTitle, err := srv.alertRules.GetAlertGroupsWithFolderTitle(c.Req.Context(), q)
 	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get alert rules")
 	}
 
-	return exportResponse(c, e)
+	return alertRulesExportResponse(c, groupsWithTitle)
 }
 
-// RouteGetAlertRuleGroupExport retrieves the given alert rule group in a format compatible with file provisioning.
+// RouteGetAlertRuleGroupExport retrieves the given alert rule group in a format compatible with file provisioning, or
+// as a Prometheus rule group.
 func (srv *ProvisioningSrv) RouteGetAlertRuleGroupExport(c *contextmodel.ReqContext, folder string, group string) response.Response {
+	if err := validateAlertRulesExportTarget(c); err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
 	g, err := srv.alertRules.GetAlertRuleGroupWithFolderTitle(c.Req.Context(), c.OrgID, folder, group)
 	if err != nil {
 		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
-			return ErrResp(http.StatusNotFound, err, "")
+			return provisioningErrResp(http.StatusNotFound, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "failed to get alert rule group")
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get alert rule group")
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]alerting_models.AlertRuleGroupWithFolderTitle{g})
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
-	}
-
-	return exportResponse(c, e)
+	return alertRulesExportResponse(c, []alerting_models.AlertRuleGroupWithFolderTitle{g})
 }
 
-// RouteGetAlertRuleExport retrieves the given alert rule in a format compatible with file provisioning.
+// RouteGetAlertRuleExport retrieves the given alert rule in a format compatible with file provisioning, or as a
+// Prometheus rule.
 func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext, UID string) response.Response {
+	if err := validateAlertRulesExportTarget(c); err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
 	rule, err := srv.alertRules.GetAlertRuleWithFolderTitle(c.Req.Context(), c.OrgID, UID)
 	if err != nil {
 		if errors.Is(err, alerting_models.ErrAlertRuleNotFound) {
-			return ErrResp(http.StatusNotFound, err, "")
+			return provisioningErrResp(http.StatusNotFound, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 
-	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle([]alerting_models.AlertRuleGroupWithFolderTitle{{
+	return alertRulesExportResponse(c, []alerting_models.AlertRuleGroupWithFolderTitle{{
 		AlertRuleGroup: &alerting_models.AlertRuleGroup{
 			Title:     rule.AlertRule.RuleGroup,
 			FolderUID: rule.AlertRule.NamespaceUID,
@@ -441,10 +1065,35 @@ func (srv *ProvisioningSrv) RouteGetAlertRuleExport(c *contextmodel.ReqContext,
 		OrgID:       c.OrgID,
 		FolderTitle: rule.FolderTitle,
 	}})
-	if err != nil {
-		return ErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+}
+
+// validateAlertRulesExportTarget fails if the target of an export of alert rules is not supported. It is checked
+// before the rules are read, so that requests with typos fail fast.
+func validateAlertRulesExportTarget(c *contextmodel.ReqContext) error {
+	target := c.Query("target")
+	if target != "" && target != exportTargetGrafana && target != exportTargetPrometheus {
+		return fmt.Errorf("unsupported export target '%s', expected '%s' or '%s'", target, exportTargetGrafana, exportTargetPrometheus)
+	}
+	return nil
+}
+
+// alertRulesExportResponse exports the rule groups in the format of the requested target.
+func alertRulesExportResponse(c *contextmodel.ReqContext, groups []alerting_models.AlertRuleGroupWithFolderTitle) response.Response {
+	if c.Query("target") == exportTargetPrometheus {
+		e, err := PrometheusRulesExportFromAlertRuleGroupWithFolderTitle(groups, c.QueryBoolWithDefault("skipIncompatible", false))
+		if errors.Is(err, errIncompatiblePrometheusRule) {
+			return provisioningErrResp(http.StatusBadRequest, err, "")
+		}
+		if err != nil {
+			return provisioningErrResp(http.StatusInternalServerError, err, "failed to create Prometheus rules export")
+		}
+		return exportResponse(c, e)
 	}
 
+	e, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to create alerting file export")
+	}
 	return exportResponse(c, e)
 }
 
@@ -453,22 +1102,97 @@ func (srv *ProvisioningSrv) RoutePutAlertRuleGroup(c *contextmodel.ReqContext, a
 	ag.Title = group
 	groupModel, err := AlertRuleGroupFromApiAlertRuleGroup(ag)
 	if err != nil {
-		ErrResp(http.StatusBadRequest, err, "")
+		provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 	provenance := determineProvenance(c)
 	err = srv.alertRules.ReplaceRuleGroup(c.Req.Context(), c.OrgID, groupModel, c.UserID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
 	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
-		return ErrResp(http.StatusBadRequest, err, "")
+		return provisioningErrResp(http.StatusBadRequest, err, "")
 	}
 	if err != nil {
 		if errors.Is(err, store.ErrOptimisticLock) {
-			return ErrResp(http.StatusConflict, err, "")
+			return provisioningErrResp(http.StatusConflict, err, "")
 		}
-		return ErrResp(http.StatusInternalServerError, err, "")
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
 	}
 	return response.JSON(http.StatusOK, ag)
 }
 
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupPause(c *contextmodel.ReqContext, body definitions.AlertRuleGroupPause, folderUID string, group string) response.Response {
+	err := srv.alertRules.SetRuleGroupPaused(c.Req.Context(), c.OrgID, folderUID, group, body.Paused)
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return provisioningErrResp(http.StatusNotFound, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	g, err := srv.alertRules.GetRuleGroup(c.Req.Context(), c.OrgID, folderUID, group)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, ApiAlertRuleGroupFromAlertRuleGroup(g))
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroupEvaluation(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
+	evaluation, err := srv.alertRules.GetRuleGroupEvaluation(c.Req.Context(), c.OrgID, folderUID, group)
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return provisioningErrResp(http.StatusNotFound, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, definitions.AlertRuleGroupEvaluation{
+		Interval:         evaluation.IntervalSeconds,
+		EvaluationOffset: evaluation.EvaluationOffsetSeconds,
+	})
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertRuleGroupLoad(c *contextmodel.ReqContext, folderUID string, group string) response.Response {
+	load, err := srv.alertRules.EstimateRuleGroupLoad(c.Req.Context(), c.OrgID, folderUID, group)
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return provisioningErrResp(http.StatusNotFound, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, RuleGroupLoadToApi(load))
+}
+
+func (srv *ProvisioningSrv) RoutePutAlertRuleGroupEvaluation(c *contextmodel.ReqContext, body definitions.AlertRuleGroupEvaluation, folderUID string, group string) response.Response {
+	err := srv.alertRules.SetRuleGroupEvaluation(c.Req.Context(), c.OrgID, folderUID, group, alerting_models.RuleGroupEvaluation{
+		IntervalSeconds:         body.Interval,
+		EvaluationOffsetSeconds: body.EvaluationOffset,
+	})
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		if errors.Is(err, store.ErrAlertRuleGroupNotFound) {
+			return provisioningErrResp(http.StatusNotFound, err, "")
+		}
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, body)
+}
+
+// dryRunContext returns the context for the mutation made by the request. If the dryRun query parameter is set, the
+// mutation runs in dry-run mode and its changes are collected in the returned DryRun.
+func dryRunContext(c *contextmodel.ReqContext) (context.Context, *provisioning.DryRun) {
+	if !c.QueryBoolWithDefault("dryRun", false) {
+		return c.Req.Context(), nil
+	}
+	return provisioning.WithDryRun(c.Req.Context())
+}
+
 func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	if _, disabled := ctx.Req.Header[disableProvenanceHeaderName]; disabled {
 		return definitions.Provenance(alerting_models.ProvenanceNone)
@@ -476,6 +1200,16 @@ func determineProvenance(ctx *contextmodel.ReqContext) definitions.Provenance {
 	return definitions.Provenance(alerting_models.ProvenanceAPI)
 }
 
+// requestedVersion returns the version of the If-Match header, or an empty string if the change does not depend on
+// the version of the object.
+func requestedVersion(c *contextmodel.ReqContext) string {
+	version := strings.TrimSpace(c.Req.Header.Get(ifMatchHeader))
+	if version == "*" {
+		return ""
+	}
+	return strings.Trim(strings.TrimPrefix(version, "W/"), `"`)
+}
+
 func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryParams {
 	var format = "yaml"
 
@@ -489,7 +1223,7 @@ func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryPar
 	}
 
 	queryFormat := c.Query("format")
-	if queryFormat == "yaml" || queryFormat == "json" {
+	if queryFormat == "yaml" || queryFormat == "json" || queryFormat == "hcl" || queryFormat == "k8s" {
 		format = queryFormat
 	}
 
@@ -501,8 +1235,11 @@ func extractExportRequest(c *contextmodel.ReqContext) definitions.ExportQueryPar
 	return params
 }
 
-func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExport) response.Response {
+func exportResponse(c *contextmodel.ReqContext, body any) response.Response {
 	params := extractExportRequest(c)
+	if params.Format == "hcl" || params.Format == "k8s" {
+		return renderedExportResponse(params, body)
+	}
 	if params.Download {
 		r := response.JSONDownload
 		if params.Format == "yaml" {
@@ -517,3 +1254,28 @@ func exportResponse(c *contextmodel.ReqContext, body definitions.AlertingFileExp
 	}
 	return r(http.StatusOK, body)
 }
+
+// renderedExportResponse renders an export as Terraform resources or as custom resources of the Grafana Operator.
+// Only exports in provisioning file format can be rendered.
+func renderedExportResponse(params definitions.ExportQueryParams, body any) response.Response {
+	e, ok := body.(definitions.AlertingFileExport)
+	if !ok {
+		return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("the %s format is only supported for exports in provisioning file format", params.Format), "")
+	}
+	render, contentType, filename := AlertingFileExportToHCL, "text/hcl", "export.tf"
+	if params.Format == "k8s" {
+		render, contentType, filename = AlertingFileExportToK8s, "text/yaml", "export.yaml"
+	}
+	b, err := render(e)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, fmt.Sprintf("failed to create the %s export", params.Format))
+	}
+	r := response.Respond(http.StatusOK, b).SetHeader("Content-Type", contentType)
+	if params.Download {
+		if params.Format == "k8s" {
+			r.SetHeader("Content-Type", "application/yaml")
+		}
+		r.SetHeader("Content-Disposition", fmt.Sprintf(`attachment;filename="%s"`, filename))
+	}
+	return r
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// AlertRuleTestService evaluates alert rules once without storing anything.
+type AlertRuleTestService interface {
+	TestAlertRule(ctx context.Context, orgID int64, rule alerting_models.AlertRule, now time.Time) ([]provisioning.AlertRuleTestInstance, error)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertRuleTest(c *contextmodel.ReqContext, ar definitions.ProvisionedAlertRule) response.Response {
+	rule, err := AlertRuleFromProvisionedAlertRule(ar)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	instances, err := srv.ruleTester.TestAlertRule(c.Req.Context(), c.OrgID, rule, time.Now())
+	if errors.Is(err, dashboards.ErrDashboardNotFound) || errors.Is(err, dashboards.ErrFolderNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to evaluate the alert rule")
+	}
+	return response.JSON(http.StatusOK, AlertRuleTestResultToApi(instances))
+}
@@ -0,0 +1,28 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// AlertmanagerImportService imports the configuration of a Prometheus Alertmanager into an organization.
+type AlertmanagerImportService interface {
+	ImportAlertmanagerConfig(ctx context.Context, orgID int64, imp definitions.AlertmanagerImport) (definitions.AlertmanagerImportResult, error)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertmanagerImport(c *contextmodel.ReqContext, body definitions.AlertmanagerImport) response.Response {
+	result, err := srv.alertmanagerImport.ImportAlertmanagerConfig(c.Req.Context(), c.OrgID, body)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to import the Alertmanager configuration")
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ProvisioningAuditStore reads the audit log of changes made through the provisioning services.
+type ProvisioningAuditStore interface {
+	GetProvisioningAuditEntries(ctx context.Context, query alerting_models.ProvisioningAuditQuery) ([]alerting_models.ProvisioningAuditEntry, error)
+	GetResourceHistory(ctx context.Context, orgID int64, resourceType, resourceID string, page alerting_models.ResourceHistoryPage) ([]alerting_models.ProvisioningAuditEntry, int64, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningAudit(c *contextmodel.ReqContext) response.Response {
+	q, err := parseProvisioningAuditQuery(c)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	entries, err := srv.audit.GetProvisioningAuditEntries(c.Req.Context(), q)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the provisioning audit log")
+	}
+	result := make(definitions.ProvisioningAuditEntries, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, ProvisioningAuditEntryToApi(e))
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningResourceHistory(c *contextmodel.ReqContext) response.Response {
+	resourceType := c.Query("resourceType")
+	if resourceType == "" {
+		return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("resourceType is required"), "")
+	}
+	var page alerting_models.ResourceHistoryPage
+	for _, p := range []struct {
+		name   string
+		target *int
+	}{{"offset", &page.Offset}, {"limit", &page.Limit}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("invalid %s %q: must not be negative", p.name, v), "")
+		}
+		*p.target = n
+	}
+	entries, total, err := srv.audit.GetResourceHistory(c.Req.Context(), c.OrgID, resourceType, c.Query("resourceId"), page)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the history of the resource")
+	}
+	result := make(definitions.ProvisioningAuditEntries, 0, len(entries))
+	for _, e := range entries {
+		result = append(result, ProvisioningAuditEntryToApi(e))
+	}
+	return response.JSON(http.StatusOK, result).SetHeader(totalCountHeader, strconv.FormatInt(total, 10))
+}
+
+func parseProvisioningAuditQuery(c *contextmodel.ReqContext) (alerting_models.ProvisioningAuditQuery, error) {
+	q := alerting_models.ProvisioningAuditQuery{
+		OrgID:        c.OrgID,
+		ResourceType: c.Query("resourceType"),
+		ResourceID:   c.Query("resourceId"),
+	}
+	if q.ResourceID != "" && q.ResourceType == "" {
+		return q, fmt.Errorf("resourceId requires resourceType")
+	}
+	for _, p := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		v := c.Query(p.name)
+		if v == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return q, fmt.Errorf("invalid %s %q: %w", p.name, v, err)
+		}
+		*p.target = time.Unix(seconds, 0)
+	}
+	if limit := c.Query("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 0 {
+			return q, fmt.Errorf("invalid limit %q: must not be negative", limit)
+		}
+		q.Limit = l
+	}
+	return q, nil
+}
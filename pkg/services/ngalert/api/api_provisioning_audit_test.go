@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRouteGetProvisioningAudit(t *testing.T) {
+	env := createTestEnv(t, testConfig)
+	sut := createProvisioningSrvSutFromEnv(t, &env)
+
+	for _, e := range []models.ProvisioningAuditEntry{
+		{OrgID: 1, Action: models.ProvisioningAuditActionCreate, ResourceType: "template", ResourceID: "a", NewState: `"content"`, Created: 100},
+		{OrgID: 1, Action: models.ProvisioningAuditActionDelete, ResourceType: "template", ResourceID: "a", OldState: `"content"`, Created: 200},
+		{OrgID: 1, Action: models.ProvisioningAuditActionCreate, ResourceType: "muteTimeInterval", ResourceID: "b", Created: 300},
+		{OrgID: 2, Action: models.ProvisioningAuditActionCreate, ResourceType: "template", ResourceID: "a", Created: 400},
+	} {
+		e := e
+		require.NoError(t, env.store.InsertProvisioningAuditEntry(context.Background(), &e))
+	}
+
+	t.Run("returns the changes of the organization, most recent first", func(t *testing.T) {
+		rc := createTestRequestCtx()
+
+		response := sut.RouteGetProvisioningAudit(&rc)
+
+		require.Equal(t, 200, response.Status())
+		var entries definitions.ProvisioningAuditEntries
+		require.NoError(t, json.Unmarshal(response.Body(), &entries))
+		require.Len(t, entries, 3)
+		require.Equal(t, "muteTimeInterval", entries[0].ResourceType)
+		require.Equal(t, "delete", entries[1].Action)
+		require.JSONEq(t, `"content"`, string(entries[1].OldState))
+		require.Empty(t, entries[1].NewState)
+		require.Equal(t, int64(100), entries[2].Created.Unix())
+	})
+
+	t.Run("filters by resource and time range", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("resourceType", "template")
+		rc.Context.Req.Form.Set("resourceId", "a")
+		rc.Context.Req.Form.Set("from", "150")
+
+		response := sut.RouteGetProvisioningAudit(&rc)
+
+		require.Equal(t, 200, response.Status())
+		var entries definitions.ProvisioningAuditEntries
+		require.NoError(t, json.Unmarshal(response.Body(), &entries))
+		require.Len(t, entries, 1)
+		require.Equal(t, "delete", entries[0].Action)
+	})
+
+	t.Run("rejects invalid queries", func(t *testing.T) {
+		for name, query := range map[string][2]string{
+			"resource id without type": {"resourceId", "a"},
+			"malformed from":           {"from", "yesterday"},
+			"malformed to":             {"to", "1.5"},
+			"negative limit":           {"limit", "-1"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rc := createTestRequestCtx()
+				rc.Context.Req.Form.Set(query[0], query[1])
+
+				response := sut.RouteGetProvisioningAudit(&rc)
+
+				require.Equal(t, 400, response.Status())
+			})
+		}
+	})
+}
+
+func TestRouteGetProvisioningResourceHistory(t *testing.T) {
+	env := createTestEnv(t, testConfig)
+	sut := createProvisioningSrvSutFromEnv(t, &env)
+
+	for _, e := range []models.ProvisioningAuditEntry{
+		{OrgID: 1, Action: models.ProvisioningAuditActionCreate, ResourceType: "template", ResourceID: "a", NewState: `"v1"`, Created: 100},
+		{OrgID: 1, Action: models.ProvisioningAuditActionUpdate, ResourceType: "template", ResourceID: "a", OldState: `"v1"`, NewState: `"v2"`, Created: 200},
+		{OrgID: 1, Action: models.ProvisioningAuditActionUpdate, ResourceType: "template", ResourceID: "a", OldState: `"v2"`, NewState: `"v3"`, Created: 300},
+		{OrgID: 1, Action: models.ProvisioningAuditActionCreate, ResourceType: "template", ResourceID: "b", Created: 400},
+		{OrgID: 1, Action: models.ProvisioningAuditActionUpdate, ResourceType: "route", Created: 500},
+	} {
+		e := e
+		require.NoError(t, env.store.InsertProvisioningAuditEntry(context.Background(), &e))
+	}
+
+	t.Run("returns a page of the changes of the resource and their total number", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("resourceType", "template")
+		rc.Context.Req.Form.Set("resourceId", "a")
+		rc.Context.Req.Form.Set("offset", "1")
+		rc.Context.Req.Form.Set("limit", "1")
+
+		response := sut.RouteGetProvisioningResourceHistory(&rc)
+		response.WriteTo(&rc)
+
+		require.Equal(t, 200, response.Status())
+		require.Equal(t, "3", rc.Context.Resp.Header().Get("X-Total-Count"))
+		var entries definitions.ProvisioningAuditEntries
+		require.NoError(t, json.Unmarshal(response.Body(), &entries))
+		require.Len(t, entries, 1)
+		require.JSONEq(t, `"v2"`, string(entries[0].NewState))
+	})
+
+	t.Run("an empty resource id selects the notification policy tree", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.Context.Req.Form.Set("resourceType", "route")
+
+		response := sut.RouteGetProvisioningResourceHistory(&rc)
+
+		require.Equal(t, 200, response.Status())
+		var entries definitions.ProvisioningAuditEntries
+		require.NoError(t, json.Unmarshal(response.Body(), &entries))
+		require.Len(t, entries, 1)
+	})
+
+	t.Run("rejects invalid queries", func(t *testing.T) {
+		for name, query := range map[string]map[string]string{
+			"missing resource type": {"resourceId": "a"},
+			"negative offset":       {"resourceType": "template", "offset": "-1"},
+			"malformed limit":       {"resourceType": "template", "limit": "ten"},
+		} {
+			t.Run(name, func(t *testing.T) {
+				rc := createTestRequestCtx()
+				for k, v := range query {
+					rc.Context.Req.Form.Set(k, v)
+				}
+
+				response := sut.RouteGetProvisioningResourceHistory(&rc)
+
+				require.Equal(t, 400, response.Status())
+			})
+		}
+	})
+}
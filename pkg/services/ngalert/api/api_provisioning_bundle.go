@@ -0,0 +1,212 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// ProvisioningBundleService applies resources of different types together.
+type ProvisioningBundleService interface {
+	ApplyProvisioningBundle(ctx context.Context, orgID int64, bundle provisioning.ProvisioningBundle, userID int64, provenance alerting_models.Provenance) (definitions.ProvisioningBundleResult, error)
+	DiffProvisioningBundle(ctx context.Context, orgID int64, bundle provisioning.ProvisioningBundle) (definitions.ProvisioningBundleDiff, error)
+	ExportProvisioningBundle(ctx context.Context, orgID int64, u *user.SignedInUser) (provisioning.ProvisioningBundle, error)
+	CopyAlertingConfig(ctx context.Context, sourceOrgID, targetOrgID int64, opts provisioning.CopyAlertingConfigOptions) (definitions.ProvisioningBundleResult, error)
+}
+
+// ProvisioningBundleScheduler applies provisioning bundles at a later time.
+type ProvisioningBundleScheduler interface {
+	ScheduleProvisioningBundle(ctx context.Context, orgID int64, bundle provisioning.ProvisioningBundle, applyAt time.Time, location string, userID int64, provenance alerting_models.Provenance) (definitions.ScheduledProvisioningBundle, error)
+	GetScheduledProvisioningBundles(ctx context.Context, orgID int64) ([]definitions.ScheduledProvisioningBundle, error)
+	CancelScheduledProvisioningBundle(ctx context.Context, orgID int64, uid string) error
+}
+
+func (srv *ProvisioningSrv) RoutePostProvisioningBundle(c *contextmodel.ReqContext, body definitions.ProvisioningBundle) response.Response {
+	bundle, err := provisioningBundleFromApi(body)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+
+	provenance := determineProvenance(c)
+	result, err := srv.bundles.ApplyProvisioningBundle(c.Req.Context(), c.OrgID, bundle, c.UserID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) || errors.Is(err, store.ErrVersionLockedObjectNotFound) || errors.Is(err, provisioning.ErrVersionConflict) {
+		return provisioningErrResp(http.StatusConflict, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to apply the provisioning bundle")
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+func (srv *ProvisioningSrv) RoutePostProvisioningBundleDiff(c *contextmodel.ReqContext, body definitions.ProvisioningBundle) response.Response {
+	bundle, err := provisioningBundleFromApi(body)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+
+	result, err := srv.bundles.DiffProvisioningBundle(c.Req.Context(), c.OrgID, bundle)
+	if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to compare the provisioning bundle")
+	}
+	return response.JSON(http.StatusOK, result)
+}
+
+func (srv *ProvisioningSrv) RoutePostProvisioningBundleExport(c *contextmodel.ReqContext, body definitions.ProvisioningBundleExportRequest) response.Response {
+	key := provisioning.BundleKey{Passphrase: body.Passphrase, PublicKey: body.PublicKey}
+	if (key.Passphrase == "") == (key.PublicKey == "") {
+		// Reject the request before the secrets are decrypted and the export is recorded in the audit log.
+		return provisioningErrResp(http.StatusBadRequest, provisioning.ErrBundleKeyRequired, "")
+	}
+
+	bundle, err := srv.bundles.ExportProvisioningBundle(c.Req.Context(), c.OrgID, c.SignedInUser)
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to export the provisioning bundle")
+	}
+	data, err := json.Marshal(provisioningBundleToApi(bundle))
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to serialize the provisioning bundle")
+	}
+	encrypted, err := provisioning.EncryptBundle(data, key)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to encrypt the provisioning bundle")
+	}
+	return response.JSON(http.StatusOK, encrypted)
+}
+
+func (srv *ProvisioningSrv) RoutePostProvisioningBundleImport(c *contextmodel.ReqContext, body definitions.ProvisioningBundleImportRequest) response.Response {
+	data, err := provisioning.DecryptBundle(body.Bundle, provisioning.BundleKey{Passphrase: body.Passphrase, PrivateKey: body.PrivateKey})
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to decrypt the provisioning bundle")
+	}
+	var decrypted definitions.ProvisioningBundle
+	if err := json.Unmarshal(data, &decrypted); err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "the decrypted bundle is invalid")
+	}
+	return srv.RoutePostProvisioningBundle(c, decrypted)
+}
+
+func (srv *ProvisioningSrv) RoutePostCopyAlertingConfig(c *contextmodel.ReqContext, body definitions.CopyAlertingConfigRequest) response.Response {
+	opts := provisioning.CopyAlertingConfigOptions{
+		ContactPoints:  body.ContactPoints,
+		Templates:      body.Templates,
+		MuteTimings:    body.MuteTimings,
+		Policies:       body.Policies,
+		FolderUIDs:     body.FolderUIDs,
+		DatasourceUIDs: body.DatasourceUIDs,
+		User:           c.SignedInUser,
+		Provenance:     alerting_models.Provenance(determineProvenance(c)),
+	}
+	for _, g := range body.RuleGroups {
+		opts.RuleGroups = append(opts.RuleGroups, provisioning.CopyRuleGroup{FolderUID: g.FolderUID, Title: g.Title})
+	}
+
+	result, err := srv.bundles.CopyAlertingConfig(c.Req.Context(), body.SourceOrgID, body.TargetOrgID, opts)
+	if errors.Is(err, provisioning.ErrValidation) || errors.Is(err, alerting_models.ErrAlertRuleFailedValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrPermissionDenied) {
+		return provisioningErrResp(http.StatusForbidden, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) || errors.Is(err, store.ErrAlertRuleGroupNotFound) || errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, store.ErrOptimisticLock) || errors.Is(err, store.ErrVersionLockedObjectNotFound) || errors.Is(err, provisioning.ErrVersionConflict) {
+		return provisioningErrResp(http.StatusConflict, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to copy the alerting configuration")
+	}
+	return response.JSON(http.StatusAccepted, result)
+}
+
+func (srv *ProvisioningSrv) RouteGetScheduledProvisioningBundles(c *contextmodel.ReqContext) response.Response {
+	bundles, err := srv.bundleScheduler.GetScheduledProvisioningBundles(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the scheduled provisioning bundles")
+	}
+	return response.JSON(http.StatusOK, bundles)
+}
+
+func (srv *ProvisioningSrv) RoutePostScheduledProvisioningBundle(c *contextmodel.ReqContext, body definitions.ScheduledProvisioningBundleRequest) response.Response {
+	bundle, err := provisioningBundleFromApi(body.Bundle)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+
+	provenance := determineProvenance(c)
+	scheduled, err := srv.bundleScheduler.ScheduleProvisioningBundle(c.Req.Context(), c.OrgID, bundle, body.ApplyAt, body.Location, c.UserID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to schedule the provisioning bundle")
+	}
+	return response.JSON(http.StatusCreated, scheduled)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteScheduledProvisioningBundle(c *contextmodel.ReqContext, uid string) response.Response {
+	err := srv.bundleScheduler.CancelScheduledProvisioningBundle(c.Req.Context(), c.OrgID, uid)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to cancel the scheduled provisioning bundle")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
+
+func provisioningBundleToApi(bundle provisioning.ProvisioningBundle) definitions.ProvisioningBundle {
+	result := definitions.ProvisioningBundle{
+		ContactPoints: bundle.ContactPoints,
+		Policies:      bundle.Policies,
+		MuteTimings:   bundle.MuteTimings,
+		Templates:     bundle.Templates,
+	}
+	for _, g := range bundle.RuleGroups {
+		result.RuleGroups = append(result.RuleGroups, ApiAlertRuleGroupFromAlertRuleGroup(g))
+	}
+	return result
+}
+
+func provisioningBundleFromApi(body definitions.ProvisioningBundle) (provisioning.ProvisioningBundle, error) {
+	bundle := provisioning.ProvisioningBundle{
+		ContactPoints: body.ContactPoints,
+		Policies:      body.Policies,
+		MuteTimings:   body.MuteTimings,
+		Templates:     body.Templates,
+	}
+	for _, g := range body.RuleGroups {
+		group, err := AlertRuleGroupFromApiAlertRuleGroup(g)
+		if err != nil {
+			return provisioning.ProvisioningBundle{}, fmt.Errorf("%w: rule group '%s' in folder '%s': %s", provisioning.ErrValidation, g.Title, g.FolderUID, err.Error())
+		}
+		bundle.RuleGroups = append(bundle.RuleGroups, group)
+	}
+	return bundle, nil
+}
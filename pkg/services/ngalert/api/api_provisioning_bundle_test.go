@@ -0,0 +1,306 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRoutePostProvisioningBundle(t *testing.T) {
+	t.Run("applies the bundle and returns 202", func(t *testing.T) {
+		bundles := &fakeProvisioningBundleService{}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundle(&rc, definitions.ProvisioningBundle{
+			Templates: []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+		})
+
+		require.Equal(t, 202, response.Status())
+		require.Len(t, bundles.applied.Templates, 1)
+		require.Equal(t, alerting_models.ProvenanceAPI, bundles.provenance)
+	})
+
+	t.Run("disabled provenance is applied", func(t *testing.T) {
+		bundles := &fakeProvisioningBundleService{}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+		rc.Req.Header.Add(disableProvenanceHeaderName, "true")
+
+		response := sut.RoutePostProvisioningBundle(&rc, definitions.ProvisioningBundle{})
+
+		require.Equal(t, 202, response.Status())
+		require.Equal(t, alerting_models.ProvenanceNone, bundles.provenance)
+	})
+
+	t.Run("rejected resource returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("template 'team': %w", provisioning.ErrValidation)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundle(&rc, definitions.ProvisioningBundle{})
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("concurrent change returns 409", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("contact point 'team': %w", store.ErrVersionLockedObjectNotFound)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundle(&rc, definitions.ProvisioningBundle{})
+
+		require.Equal(t, 409, response.Status())
+	})
+}
+
+func TestRoutePostProvisioningBundleDiff(t *testing.T) {
+	t.Run("returns the diff with 200", func(t *testing.T) {
+		bundles := &fakeProvisioningBundleService{diff: definitions.ProvisioningBundleDiff{Resources: []definitions.ResourceDiff{
+			{ResourceType: "template", ResourceID: "team", Action: definitions.ResourceDiffCreate},
+		}}}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundleDiff(&rc, definitions.ProvisioningBundle{
+			Templates: []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+		})
+
+		require.Equal(t, 200, response.Status())
+		require.Len(t, bundles.diffed.Templates, 1)
+		require.Contains(t, string(response.Body()), `"action":"create"`)
+	})
+
+	t.Run("rejected resource returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("rule group 'group' in folder 'folder': %w", provisioning.ErrValidation)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundleDiff(&rc, definitions.ProvisioningBundle{})
+
+		require.Equal(t, 400, response.Status())
+	})
+}
+
+func TestRoutePostProvisioningBundleExport(t *testing.T) {
+	t.Run("returns the encrypted bundle with 200 that can be imported with the passphrase", func(t *testing.T) {
+		bundles := &fakeProvisioningBundleService{exported: provisioning.ProvisioningBundle{
+			Templates: []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+		}}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundleExport(&rc, definitions.ProvisioningBundleExportRequest{Passphrase: "secret"})
+
+		require.Equal(t, 200, response.Status())
+		var encrypted definitions.EncryptedProvisioningBundle
+		require.NoError(t, json.Unmarshal(response.Body(), &encrypted))
+		require.Equal(t, definitions.BundleEncryptionPassphrase, encrypted.Mode)
+		require.NotContains(t, string(response.Body()), "content")
+
+		response = sut.RoutePostProvisioningBundleImport(&rc, definitions.ProvisioningBundleImportRequest{Bundle: encrypted, Passphrase: "secret"})
+
+		require.Equal(t, 202, response.Status())
+		require.Equal(t, bundles.exported.Templates, bundles.applied.Templates)
+	})
+
+	t.Run("missing key returns 400 without exporting", func(t *testing.T) {
+		bundles := &fakeProvisioningBundleService{}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundleExport(&rc, definitions.ProvisioningBundleExportRequest{})
+
+		require.Equal(t, 400, response.Status())
+		require.False(t, bundles.exportCalled)
+	})
+
+	t.Run("denied secrets return 403", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("contact points: %w", provisioning.ErrPermissionDenied)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundleExport(&rc, definitions.ProvisioningBundleExportRequest{Passphrase: "secret"})
+
+		require.Equal(t, 403, response.Status())
+	})
+}
+
+func TestRoutePostProvisioningBundleImport(t *testing.T) {
+	t.Run("wrong passphrase returns 400", func(t *testing.T) {
+		encrypted, err := provisioning.EncryptBundle([]byte(`{}`), provisioning.BundleKey{Passphrase: "secret"})
+		require.NoError(t, err)
+		bundles := &fakeProvisioningBundleService{}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostProvisioningBundleImport(&rc, definitions.ProvisioningBundleImportRequest{Bundle: encrypted, Passphrase: "wrong"})
+
+		require.Equal(t, 400, response.Status())
+		require.Nil(t, bundles.applied.Templates)
+	})
+}
+
+func TestRoutePostCopyAlertingConfig(t *testing.T) {
+	t.Run("copies the selected resources and returns 202", func(t *testing.T) {
+		bundles := &fakeProvisioningBundleService{}
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = bundles
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostCopyAlertingConfig(&rc, definitions.CopyAlertingConfigRequest{
+			SourceOrgID: 1,
+			TargetOrgID: 2,
+			Templates:   []string{"team"},
+			RuleGroups:  []definitions.CopyRuleGroup{{FolderUID: "folder", Title: "group"}},
+			FolderUIDs:  map[string]string{"folder": "tenant-folder"},
+		})
+
+		require.Equal(t, 202, response.Status())
+		require.Equal(t, [2]int64{1, 2}, bundles.copiedOrgs)
+		require.Equal(t, []string{"team"}, bundles.copied.Templates)
+		require.Equal(t, []provisioning.CopyRuleGroup{{FolderUID: "folder", Title: "group"}}, bundles.copied.RuleGroups)
+		require.Equal(t, "tenant-folder", bundles.copied.FolderUIDs["folder"])
+		require.Equal(t, alerting_models.ProvenanceAPI, bundles.copied.Provenance)
+		require.Same(t, rc.SignedInUser, bundles.copied.User)
+	})
+
+	t.Run("same organization returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("%w: the source and target organization must be different", provisioning.ErrValidation)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostCopyAlertingConfig(&rc, definitions.CopyAlertingConfigRequest{SourceOrgID: 1, TargetOrgID: 1})
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("missing resource returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("rule group 'group' in folder 'folder': %w", store.ErrAlertRuleGroupNotFound)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostCopyAlertingConfig(&rc, definitions.CopyAlertingConfigRequest{SourceOrgID: 1, TargetOrgID: 2})
+
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("denied secrets return 403", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.bundles = &fakeProvisioningBundleService{err: fmt.Errorf("%w: secrets", provisioning.ErrPermissionDenied)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostCopyAlertingConfig(&rc, definitions.CopyAlertingConfigRequest{SourceOrgID: 1, TargetOrgID: 2, ContactPoints: []string{"team"}})
+
+		require.Equal(t, 403, response.Status())
+	})
+}
+
+func TestRouteScheduledProvisioningBundles(t *testing.T) {
+	bundle := definitions.ProvisioningBundle{
+		Templates: []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+	}
+
+	t.Run("schedules the bundle and returns 201", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostScheduledProvisioningBundle(&rc, definitions.ScheduledProvisioningBundleRequest{
+			ApplyAt: time.Now().Add(time.Hour),
+			Bundle:  bundle,
+		})
+		require.Equal(t, 201, response.Status())
+
+		response = sut.RouteGetScheduledProvisioningBundles(&rc)
+		require.Equal(t, 200, response.Status())
+		var scheduled []definitions.ScheduledProvisioningBundle
+		require.NoError(t, json.Unmarshal(response.Body(), &scheduled))
+		require.Len(t, scheduled, 1)
+		require.Equal(t, 1, scheduled[0].Templates)
+		require.Equal(t, definitions.ScheduledProvisioningBundlePending, scheduled[0].Status)
+	})
+
+	t.Run("past time returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostScheduledProvisioningBundle(&rc, definitions.ScheduledProvisioningBundleRequest{
+			ApplyAt: time.Now().Add(-time.Hour),
+			Bundle:  bundle,
+		})
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("unknown time zone returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostScheduledProvisioningBundle(&rc, definitions.ScheduledProvisioningBundleRequest{
+			ApplyAt:  time.Now().Add(time.Hour),
+			Location: "Mars/Olympus_Mons",
+			Bundle:   bundle,
+		})
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("cancelling an unknown bundle returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RouteDeleteScheduledProvisioningBundle(&rc, "unknown")
+
+		require.Equal(t, 404, response.Status())
+	})
+}
+
+type fakeProvisioningBundleService struct {
+	err          error
+	applied      provisioning.ProvisioningBundle
+	provenance   alerting_models.Provenance
+	diffed       provisioning.ProvisioningBundle
+	diff         definitions.ProvisioningBundleDiff
+	exported     provisioning.ProvisioningBundle
+	exportCalled bool
+	copied       provisioning.CopyAlertingConfigOptions
+	copiedOrgs   [2]int64
+}
+
+func (f *fakeProvisioningBundleService) ApplyProvisioningBundle(_ context.Context, _ int64, bundle provisioning.ProvisioningBundle, _ int64, provenance alerting_models.Provenance) (definitions.ProvisioningBundleResult, error) {
+	f.applied = bundle
+	f.provenance = provenance
+	return definitions.ProvisioningBundleResult{}, f.err
+}
+
+func (f *fakeProvisioningBundleService) DiffProvisioningBundle(_ context.Context, _ int64, bundle provisioning.ProvisioningBundle) (definitions.ProvisioningBundleDiff, error) {
+	f.diffed = bundle
+	return f.diff, f.err
+}
+
+func (f *fakeProvisioningBundleService) ExportProvisioningBundle(_ context.Context, _ int64, _ *user.SignedInUser) (provisioning.ProvisioningBundle, error) {
+	f.exportCalled = true
+	return f.exported, f.err
+}
+
+func (f *fakeProvisioningBundleService) CopyAlertingConfig(_ context.Context, sourceOrgID, targetOrgID int64, opts provisioning.CopyAlertingConfigOptions) (definitions.ProvisioningBundleResult, error) {
+	f.copied = opts
+	f.copiedOrgs = [2]int64{sourceOrgID, targetOrgID}
+	return definitions.ProvisioningBundleResult{}, f.err
+}
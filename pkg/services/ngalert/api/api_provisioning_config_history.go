@@ -0,0 +1,50 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// ConfigHistoryService lists the saved versions of the Alertmanager configuration and rolls it back to one of them.
+type ConfigHistoryService interface {
+	ListAlertmanagerConfigVersions(ctx context.Context, orgID int64) ([]definitions.AlertmanagerConfigVersion, error)
+	RollbackAlertmanagerConfig(ctx context.Context, orgID int64, configID int64) (definitions.AlertmanagerConfigVersion, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertmanagerConfigVersions(c *contextmodel.ReqContext) response.Response {
+	versions, err := srv.configHistory.ListAlertmanagerConfigVersions(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the Alertmanager configuration versions")
+	}
+	return response.JSON(http.StatusOK, versions)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertmanagerConfigRollback(c *contextmodel.ReqContext, id string) response.Response {
+	configID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("%w: invalid version %q", provisioning.ErrValidation, id), "")
+	}
+	version, err := srv.configHistory.RollbackAlertmanagerConfig(c.Req.Context(), c.OrgID, configID)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+		return provisioningErrResp(http.StatusConflict, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to roll back the Alertmanager configuration")
+	}
+	return response.JSON(http.StatusAccepted, version)
+}
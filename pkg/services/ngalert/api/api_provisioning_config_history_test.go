@@ -0,0 +1,82 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestRoutePostAlertmanagerConfigRollback(t *testing.T) {
+	t.Run("rolls back the configuration and returns 202", func(t *testing.T) {
+		configHistory := &fakeConfigHistoryService{version: definitions.AlertmanagerConfigVersion{ID: 3, Current: true}}
+		sut := createProvisioningSrvSut(t)
+		sut.configHistory = configHistory
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertmanagerConfigRollback(&rc, "2")
+
+		require.Equal(t, 202, response.Status())
+		require.Equal(t, int64(2), configHistory.rolledBackTo)
+	})
+
+	t.Run("invalid version returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.configHistory = &fakeConfigHistoryService{}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertmanagerConfigRollback(&rc, "latest")
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("unknown version returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.configHistory = &fakeConfigHistoryService{err: fmt.Errorf("%w: no version", provisioning.ErrNotFound)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertmanagerConfigRollback(&rc, "2")
+
+		require.Equal(t, 404, response.Status())
+	})
+
+	t.Run("invalid receiver returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.configHistory = &fakeConfigHistoryService{err: fmt.Errorf("%w: invalid receiver", provisioning.ErrValidation)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertmanagerConfigRollback(&rc, "2")
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("concurrent change returns 409", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.configHistory = &fakeConfigHistoryService{err: store.ErrVersionLockedObjectNotFound}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertmanagerConfigRollback(&rc, "2")
+
+		require.Equal(t, 409, response.Status())
+	})
+}
+
+type fakeConfigHistoryService struct {
+	version      definitions.AlertmanagerConfigVersion
+	err          error
+	rolledBackTo int64
+}
+
+func (f *fakeConfigHistoryService) ListAlertmanagerConfigVersions(context.Context, int64) ([]definitions.AlertmanagerConfigVersion, error) {
+	return []definitions.AlertmanagerConfigVersion{f.version}, f.err
+}
+
+func (f *fakeConfigHistoryService) RollbackAlertmanagerConfig(_ context.Context, _ int64, configID int64) (definitions.AlertmanagerConfigVersion, error) {
+	f.rolledBackTo = configID
+	return f.version, f.err
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// EffectiveConfigService returns the alerting configuration of an organization annotated with the origin of its resources.
+type EffectiveConfigService interface {
+	GetEffectiveConfig(ctx context.Context, orgID int64) (definitions.EffectiveConfig, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningEffectiveConfig(c *contextmodel.ReqContext) response.Response {
+	cfg, err := srv.effectiveConfig.GetEffectiveConfig(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the effective alerting configuration")
+	}
+	return response.JSON(http.StatusOK, cfg)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRouteGetProvisioningEffectiveConfig(t *testing.T) {
+	env := createTestEnv(t, testContactPointConfig)
+	sut := createProvisioningSrvSutFromEnv(t, &env)
+	rc := createTestRequestCtx()
+
+	response := sut.RouteGetProvisioningEffectiveConfig(&rc)
+
+	require.Equal(t, 200, response.Status())
+	var cfg definitions.EffectiveConfig
+	require.NoError(t, json.Unmarshal(response.Body(), &cfg))
+	require.NotEmpty(t, cfg.Policies.Route.Receiver)
+	var slack *definitions.EmbeddedContactPoint
+	for i := range cfg.ContactPoints {
+		if cfg.ContactPoints[i].ContactPoint.Name == "slack test" {
+			slack = &cfg.ContactPoints[i].ContactPoint
+		}
+	}
+	require.NotNil(t, slack)
+	require.Equal(t, definitions.RedactedValue, slack.Settings.Get("url").MustString())
+}
@@ -0,0 +1,260 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	"github.com/grafana/grafana/pkg/infra/log"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// allOrgsExportConcurrency is the maximum number of organizations that are exported in parallel.
+// It also bounds the number of exported organizations held in memory while the response is written.
+const allOrgsExportConcurrency = 4
+
+// RouteGetAllOrgsExport exports the alerting resources of all organizations in provisioning file format.
+// The response is streamed with one document per organization, in the order of the organization IDs.
+func (srv *ProvisioningSrv) RouteGetAllOrgsExport(c *contextmodel.ReqContext) response.Response {
+	orgs, err := srv.orgs.GetOrgs(c.Req.Context())
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get organizations")
+	}
+	return &allOrgsExportResponse{
+		params:      extractExportRequest(c),
+		orgs:        orgs,
+		export:      srv.exportOrg,
+		concurrency: allOrgsExportConcurrency,
+		log:         srv.log,
+	}
+}
+
+// exportOrg exports the contact points, notification policies and alert rules of an organization.
+// Secure settings of contact points are redacted.
+func (srv *ProvisioningSrv) exportOrg(ctx context.Context, orgID int64) (definitions.AlertingFileExport, error) {
+	cps, err := srv.contactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: orgID}, nil)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return definitions.AlertingFileExport{}, fmt.Errorf("failed to get contact points: %w", err)
+	}
+	e, err := AlertingFileExportFromEmbeddedContactPoints(orgID, cps)
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+
+	policies, err := srv.policies.GetPolicyTree(ctx, orgID)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return definitions.AlertingFileExport{}, fmt.Errorf("failed to get notification policies: %w", err)
+	}
+	if err == nil {
+		p, err := AlertingFileExportFromRoute(orgID, policies)
+		if err != nil {
+			return definitions.AlertingFileExport{}, err
+		}
+		e.Policies = p.Policies
+	}
+
+	groups, err := srv.alertRules.GetAlertGroupsWithFolderTitle(ctx, provisioning.AlertRuleQuery{OrgID: orgID})
+	if err != nil {
+		return definitions.AlertingFileExport{}, fmt.Errorf("failed to get alert rules: %w", err)
+	}
+	g, err := AlertingFileExportFromAlertRuleGroupWithFolderTitle(groups)
+	if err != nil {
+		return definitions.AlertingFileExport{}, err
+	}
+	e.Groups = g.Groups
+	return e, nil
+}
+
+type orgExportResult struct {
+	export definitions.AlertingFileExport
+	err    error
+}
+
+// allOrgsExportResponse is a response that exports organizations in parallel and streams the exports in order.
+// A slot for exporting another organization is only freed once an export is written, so that exports that complete
+// ahead of a slow organization do not pile up in memory.
+type allOrgsExportResponse struct {
+	params      definitions.ExportQueryParams
+	orgs        []int64
+	export      func(ctx context.Context, orgID int64) (definitions.AlertingFileExport, error)
+	concurrency int
+	log         log.Logger
+}
+
+func (r *allOrgsExportResponse) Status() int {
+	return http.StatusOK
+}
+
+func (r *allOrgsExportResponse) Body() []byte {
+	return nil
+}
+
+func (r *allOrgsExportResponse) WriteTo(c *contextmodel.ReqContext) {
+	header := c.Resp.Header()
+	filename := "export." + r.params.Format
+	switch r.params.Format {
+	case "yaml":
+		header.Set("Content-Type", "text/yaml")
+	case "hcl":
+		header.Set("Content-Type", "text/hcl")
+		filename = "export.tf"
+	case "k8s":
+		header.Set("Content-Type", "text/yaml")
+		filename = "export.yaml"
+	default:
+		header.Set("Content-Type", "application/json")
+	}
+	if r.params.Download {
+		if r.params.Format == "yaml" || r.params.Format == "k8s" {
+			header.Set("Content-Type", "application/yaml")
+		}
+		header.Set("Content-Disposition", fmt.Sprintf(`attachment;filename="%s"`, filename))
+	}
+	c.Resp.WriteHeader(http.StatusOK)
+
+	if err := r.stream(c.Req.Context(), c.Resp); err != nil {
+		// The status has already been written, so the best we can do is to stop writing.
+		r.log.Error("Failed to export all organizations", "error", err)
+	}
+}
+
+func (r *allOrgsExportResponse) stream(ctx context.Context, w io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]chan orgExportResult, len(r.orgs))
+	for i := range results {
+		results[i] = make(chan orgExportResult, 1)
+	}
+	slots := make(chan struct{}, r.concurrency)
+	go func() {
+		for i, orgID := range r.orgs {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			go func(result chan<- orgExportResult, orgID int64) {
+				e, err := r.export(ctx, orgID)
+				if err != nil {
+					err = fmt.Errorf("failed to export organization %d: %w", orgID, err)
+				}
+				result <- orgExportResult{export: e, err: err}
+			}(results[i], orgID)
+		}
+	}()
+
+	enc := newExportEncoder(w, r.params.Format)
+	for _, result := range results {
+		var res orgExportResult
+		select {
+		case res = <-result:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		<-slots
+		if res.err != nil {
+			return res.err
+		}
+		if err := enc.Encode(res.export); err != nil {
+			return err
+		}
+	}
+	return enc.Close()
+}
+
+type exportEncoder interface {
+	Encode(v any) error
+	Close() error
+}
+
+type jsonExportEncoder struct {
+	*json.Encoder
+}
+
+func (jsonExportEncoder) Close() error {
+	return nil
+}
+
+// hclExportEncoder writes the exports as a single Terraform configuration, with unique resource names across
+// organizations.
+type hclExportEncoder struct {
+	w       io.Writer
+	names   hclResourceNames
+	written bool
+}
+
+func (e *hclExportEncoder) Encode(v any) error {
+	export, ok := v.(definitions.AlertingFileExport)
+	if !ok {
+		return fmt.Errorf("unsupported export of type %T", v)
+	}
+	body := &hcl.Body{}
+	if err := appendHCLResources(body, e.names, export); err != nil {
+		return err
+	}
+	if body.Empty() {
+		return nil
+	}
+	if e.written {
+		if _, err := io.WriteString(e.w, "\n"); err != nil {
+			return err
+		}
+	}
+	e.written = true
+	_, err := e.w.Write(body.Bytes())
+	return err
+}
+
+func (*hclExportEncoder) Close() error {
+	return nil
+}
+
+// k8sExportEncoder writes the exports as a stream of custom resources of the Grafana Operator, with unique names
+// across organizations.
+type k8sExportEncoder struct {
+	*yaml.Encoder
+	names k8sResourceNames
+}
+
+func (e *k8sExportEncoder) Encode(v any) error {
+	export, ok := v.(definitions.AlertingFileExport)
+	if !ok {
+		return fmt.Errorf("unsupported export of type %T", v)
+	}
+	manifests, err := appendK8sManifests(nil, e.names, export)
+	if err != nil {
+		return err
+	}
+	for _, m := range manifests {
+		if err := e.Encoder.Encode(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newExportEncoder returns an encoder that writes a stream of documents: YAML documents separated by "---",
+// JSON documents separated by newlines, the resources of all documents as one Terraform configuration, or the
+// custom resources of all documents separated by "---".
+func newExportEncoder(w io.Writer, format string) exportEncoder {
+	switch format {
+	case "yaml":
+		return yaml.NewEncoder(w)
+	case "hcl":
+		return &hclExportEncoder{w: w, names: hclResourceNames{}}
+	case "k8s":
+		return &k8sExportEncoder{Encoder: yaml.NewEncoder(w), names: k8sResourceNames{}}
+	}
+	return jsonExportEncoder{json.NewEncoder(w)}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestAllOrgsExportResponse(t *testing.T) {
+	orgs := []int64{1, 2, 3, 4, 5, 6}
+
+	t.Run("exports are written in order of the organizations", func(t *testing.T) {
+		sut := &allOrgsExportResponse{
+			params: definitions.ExportQueryParams{Format: "json"},
+			orgs:   orgs,
+			export: func(_ context.Context, orgID int64) (definitions.AlertingFileExport, error) {
+				// Make earlier organizations slower, so that they complete last.
+				time.Sleep(time.Duration(len(orgs)-int(orgID)) * time.Millisecond)
+				return definitions.AlertingFileExport{APIVersion: 1, ContactPoints: []definitions.ContactPointExport{{OrgID: orgID}}}, nil
+			},
+			concurrency: 3,
+			log:         log.NewNopLogger(),
+		}
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, sut.stream(context.Background(), buf))
+
+		dec := json.NewDecoder(buf)
+		for _, orgID := range orgs {
+			var e definitions.AlertingFileExport
+			require.NoError(t, dec.Decode(&e))
+			require.Equal(t, orgID, e.ContactPoints[0].OrgID)
+		}
+		require.False(t, dec.More())
+	})
+
+	t.Run("yaml exports are written as separate documents", func(t *testing.T) {
+		sut := &allOrgsExportResponse{
+			params: definitions.ExportQueryParams{Format: "yaml"},
+			orgs:   orgs,
+			export: func(_ context.Context, orgID int64) (definitions.AlertingFileExport, error) {
+				return definitions.AlertingFileExport{APIVersion: 1, ContactPoints: []definitions.ContactPointExport{{OrgID: orgID}}}, nil
+			},
+			concurrency: 2,
+			log:         log.NewNopLogger(),
+		}
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, sut.stream(context.Background(), buf))
+
+		dec := yaml.NewDecoder(buf)
+		for _, orgID := range orgs {
+			var e definitions.AlertingFileExport
+			require.NoError(t, dec.Decode(&e))
+			require.Equal(t, orgID, e.ContactPoints[0].OrgID)
+		}
+	})
+
+	t.Run("hcl exports are written as one configuration with unique resource names", func(t *testing.T) {
+		sut := &allOrgsExportResponse{
+			params: definitions.ExportQueryParams{Format: "hcl"},
+			orgs:   []int64{1, 2},
+			export: func(_ context.Context, orgID int64) (definitions.AlertingFileExport, error) {
+				return definitions.AlertingFileExport{APIVersion: 1, Templates: []definitions.NotificationTemplateExport{{OrgID: orgID, Name: "alerts", Template: "{{ define \"alerts\" }}{{ end }}"}}}, nil
+			},
+			concurrency: 2,
+			log:         log.NewNopLogger(),
+		}
+		buf := &bytes.Buffer{}
+
+		require.NoError(t, sut.stream(context.Background(), buf))
+
+		require.Equal(t, `resource "grafana_message_template" "alerts" {
+  name     = "alerts"
+  template = "{{ define \"alerts\" }}{{ end }}"
+}
+
+resource "grafana_message_template" "alerts_2" {
+  org_id   = "2"
+  name     = "alerts"
+  template = "{{ define \"alerts\" }}{{ end }}"
+}
+`, buf.String())
+	})
+
+	t.Run("no more organizations than the concurrency are exported at once", func(t *testing.T) {
+		var running, maxRunning atomic.Int32
+		sut := &allOrgsExportResponse{
+			params: definitions.ExportQueryParams{Format: "json"},
+			orgs:   orgs,
+			export: func(_ context.Context, orgID int64) (definitions.AlertingFileExport, error) {
+				n := running.Add(1)
+				defer running.Add(-1)
+				for {
+					m := maxRunning.Load()
+					if n <= m || maxRunning.CompareAndSwap(m, n) {
+						break
+					}
+				}
+				time.Sleep(time.Millisecond)
+				return definitions.AlertingFileExport{APIVersion: 1}, nil
+			},
+			concurrency: 2,
+			log:         log.NewNopLogger(),
+		}
+
+		require.NoError(t, sut.stream(context.Background(), &bytes.Buffer{}))
+
+		require.LessOrEqual(t, maxRunning.Load(), int32(2))
+	})
+
+	t.Run("export stops at the first failed organization", func(t *testing.T) {
+		expectedErr := errors.New("test")
+		sut := &allOrgsExportResponse{
+			params: definitions.ExportQueryParams{Format: "json"},
+			orgs:   orgs,
+			export: func(_ context.Context, orgID int64) (definitions.AlertingFileExport, error) {
+				if orgID == 3 {
+					return definitions.AlertingFileExport{}, expectedErr
+				}
+				return definitions.AlertingFileExport{APIVersion: 1}, nil
+			},
+			concurrency: 2,
+			log:         log.NewNopLogger(),
+		}
+		buf := &bytes.Buffer{}
+
+		err := sut.stream(context.Background(), buf)
+
+		require.ErrorIs(t, err, expectedErr)
+		require.Equal(t, 2, bytes.Count(buf.Bytes(), []byte("\n")))
+	})
+}
@@ -0,0 +1,37 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// ProvisioningFreezeService manages the provisioning freeze of an organization.
+type ProvisioningFreezeService interface {
+	GetFreeze(ctx context.Context, orgID int64) (definitions.ProvisioningFreeze, error)
+	SetFreeze(ctx context.Context, orgID int64, freeze definitions.ProvisioningFreeze) (definitions.ProvisioningFreeze, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningFreeze(c *contextmodel.ReqContext) response.Response {
+	freeze, err := srv.freeze.GetFreeze(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, freeze)
+}
+
+func (srv *ProvisioningSrv) RoutePutProvisioningFreeze(c *contextmodel.ReqContext, body definitions.ProvisioningFreeze) response.Response {
+	freeze, err := srv.freeze.SetFreeze(c.Req.Context(), c.OrgID, body)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, freeze)
+}
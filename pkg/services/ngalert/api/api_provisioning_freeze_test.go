@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+func TestRouteProvisioningFreeze(t *testing.T) {
+	t.Run("successful PUT returns 202 and the freeze is returned", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutProvisioningFreeze(&rc, definitions.ProvisioningFreeze{Frozen: true, Reason: "incident"})
+
+		require.Equal(t, 202, response.Status())
+		response = sut.RouteGetProvisioningFreeze(&rc)
+		require.Equal(t, 200, response.Status())
+		var freeze definitions.ProvisioningFreeze
+		require.NoError(t, json.Unmarshal(response.Body(), &freeze))
+		require.Equal(t, definitions.ProvisioningFreeze{Frozen: true, Reason: "incident"}, freeze)
+	})
+
+	t.Run("invalid service account returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutProvisioningFreeze(&rc, definitions.ProvisioningFreeze{Frozen: true, AllowedServiceAccountIDs: []int64{0}})
+
+		require.Equal(t, 400, response.Status())
+	})
+}
+
+func TestCheckProvisioningFreeze(t *testing.T) {
+	freeze := provisioning.NewProvisioningFreezeService(kvstore.NewFakeKVStore(), nil, nil, log.NewNopLogger(), tracing.InitializeTracerForTest(), nil)
+	api := &API{ProvisioningFreeze: freeze}
+	authorized := func(c *contextmodel.ReqContext) {}
+	denied := func(c *contextmodel.ReqContext) { c.Resp.WriteHeader(http.StatusForbidden) }
+
+	t.Run("changes are allowed if provisioning is not frozen", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.OrgID = 1
+
+		api.checkProvisioningFreeze(authorized).(func(c *contextmodel.ReqContext))(&rc)
+
+		require.False(t, rc.Resp.Written())
+	})
+
+	_, err := freeze.SetFreeze(context.Background(), 1, definitions.ProvisioningFreeze{Frozen: true})
+	require.NoError(t, err)
+
+	t.Run("changes are rejected with 423 if provisioning is frozen", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.OrgID = 1
+
+		api.checkProvisioningFreeze(authorized).(func(c *contextmodel.ReqContext))(&rc)
+
+		require.Equal(t, http.StatusLocked, rc.Resp.Status())
+	})
+
+	t.Run("unauthorized requests are not checked", func(t *testing.T) {
+		rc := createTestRequestCtx()
+		rc.OrgID = 1
+
+		api.checkProvisioningFreeze(denied).(func(c *contextmodel.ReqContext))(&rc)
+
+		require.Equal(t, http.StatusForbidden, rc.Resp.Status())
+	})
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// GlobalContactPointService manages the contact points shared by all organizations of the instance.
+type GlobalContactPointService interface {
+	GetGlobalContactPoints(ctx context.Context) ([]definitions.EmbeddedContactPoint, error)
+	CreateGlobalContactPoint(ctx context.Context, contactPoint definitions.EmbeddedContactPoint) (definitions.EmbeddedContactPoint, error)
+	UpdateGlobalContactPoint(ctx context.Context, contactPoint definitions.EmbeddedContactPoint) error
+	DeleteGlobalContactPoint(ctx context.Context, uid string) error
+}
+
+func (srv *ProvisioningSrv) RouteGetGlobalContactPoints(c *contextmodel.ReqContext) response.Response {
+	cps, err := srv.globalContactPoints.GetGlobalContactPoints(c.Req.Context())
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, cps)
+}
+
+func (srv *ProvisioningSrv) RoutePostGlobalContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint) response.Response {
+	contactPoint, err := srv.globalContactPoints.CreateGlobalContactPoint(c.Req.Context(), cp)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, contactPoint)
+}
+
+func (srv *ProvisioningSrv) RoutePutGlobalContactPoint(c *contextmodel.ReqContext, cp definitions.EmbeddedContactPoint, UID string) response.Response {
+	cp.UID = UID
+	err := srv.globalContactPoints.UpdateGlobalContactPoint(c.Req.Context(), cp)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint updated"})
+}
+
+func (srv *ProvisioningSrv) RouteDeleteGlobalContactPoint(c *contextmodel.ReqContext, UID string) response.Response {
+	err := srv.globalContactPoints.DeleteGlobalContactPoint(c.Req.Context(), UID)
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, util.DynMap{"message": "contactpoint deleted"})
+}
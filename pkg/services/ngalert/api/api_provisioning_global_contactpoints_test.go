@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+func TestRouteGlobalContactPoints(t *testing.T) {
+	t.Run("successful POST returns 202 and the contact point is listed", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		// Creating a global contact point adds it to the configuration of every organization.
+		env.configs.(*provisioning.MockAMConfigStore).EXPECT().SaveSucceeds()
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		rc := createTestRequestCtx()
+		settings, err := simplejson.NewJson([]byte(`{"recipient":"value_recipient","token":"value_token"}`))
+		require.NoError(t, err)
+		cp := definitions.EmbeddedContactPoint{Name: "global", Type: "slack", Settings: settings}
+
+		response := sut.RoutePostGlobalContactPoint(&rc, cp)
+
+		require.Equal(t, 202, response.Status())
+		response = sut.RouteGetGlobalContactPoints(&rc)
+		require.Equal(t, 200, response.Status())
+		var cps []definitions.EmbeddedContactPoint
+		require.NoError(t, json.Unmarshal(response.Body(), &cps))
+		require.Len(t, cps, 1)
+		require.Equal(t, "global", cps[0].Name)
+		require.Equal(t, definitions.RedactedValue, cps[0].Settings.Get("token").MustString())
+	})
+
+	t.Run("invalid contact point returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostGlobalContactPoint(&rc, createInvalidContactPoint())
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("unknown contact point returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RouteDeleteGlobalContactPoint(&rc, "unknown")
+
+		require.Equal(t, 404, response.Status())
+	})
+}
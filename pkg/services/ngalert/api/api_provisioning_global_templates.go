@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// GlobalTemplateService manages the notification templates shared by all organizations of the instance.
+type GlobalTemplateService interface {
+	GetGlobalTemplates(ctx context.Context) ([]definitions.NotificationTemplate, error)
+	SetGlobalTemplate(ctx context.Context, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error)
+	DeleteGlobalTemplate(ctx context.Context, name string) error
+}
+
+func (srv *ProvisioningSrv) RouteGetGlobalTemplates(c *contextmodel.ReqContext) response.Response {
+	templates, err := srv.globalTemplates.GetGlobalTemplates(c.Req.Context())
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, templates)
+}
+
+func (srv *ProvisioningSrv) RoutePutGlobalTemplate(c *contextmodel.ReqContext, body definitions.NotificationTemplateContent, name string) response.Response {
+	tmpl, err := srv.globalTemplates.SetGlobalTemplate(c.Req.Context(), definitions.NotificationTemplate{Name: name, Template: body.Template})
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, tmpl)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteGlobalTemplate(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.globalTemplates.DeleteGlobalTemplate(c.Req.Context(), name)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
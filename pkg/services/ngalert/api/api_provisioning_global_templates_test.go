@@ -0,0 +1,63 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRouteGlobalTemplates(t *testing.T) {
+	t.Run("successful PUT returns 202 and the template is listed", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		// Setting a global template adds it to the configuration of every organization.
+		keepSavedConfigs(t, &env)
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutGlobalTemplate(&rc, definitions.NotificationTemplateContent{Template: `{{ define "global" }}text{{ end }}`}, "global")
+
+		require.Equal(t, 202, response.Status())
+		response = sut.RouteGetGlobalTemplates(&rc)
+		require.Equal(t, 200, response.Status())
+		var templates []definitions.NotificationTemplate
+		require.NoError(t, json.Unmarshal(response.Body(), &templates))
+		require.Len(t, templates, 1)
+		require.Equal(t, "global", templates[0].Name)
+	})
+
+	t.Run("inherited template cannot be deleted in the organization", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		keepSavedConfigs(t, &env)
+		// The provenance of the inherited template is what keeps it from being deleted.
+		env.prov = &env.store
+		sut := createProvisioningSrvSutFromEnv(t, &env)
+		rc := createTestRequestCtx()
+		response := sut.RoutePutGlobalTemplate(&rc, definitions.NotificationTemplateContent{Template: `{{ define "global" }}text{{ end }}`}, "global")
+		require.Equal(t, 202, response.Status())
+
+		response = sut.RouteDeleteTemplate(&rc, "global")
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("invalid template returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutGlobalTemplate(&rc, definitions.NotificationTemplateContent{}, "global")
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("unknown template returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RouteDeleteGlobalTemplate(&rc, "unknown")
+
+		require.Equal(t, 404, response.Status())
+	})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// ConfigHealthService summarizes the health of the alerting configuration of an organization.
+type ConfigHealthService interface {
+	GetConfigHealth(ctx context.Context, orgID int64) (provisioning.ConfigHealth, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningHealth(c *contextmodel.ReqContext) response.Response {
+	health, err := srv.health.GetConfigHealth(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the health of the alerting configuration")
+	}
+	return response.JSON(http.StatusOK, ConfigHealthToApi(health))
+}
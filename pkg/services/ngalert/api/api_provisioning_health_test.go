@@ -0,0 +1,25 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRouteGetProvisioningHealth(t *testing.T) {
+	sut := createProvisioningSrvSut(t)
+	rc := createTestRequestCtx()
+
+	response := sut.RouteGetProvisioningHealth(&rc)
+
+	require.Equal(t, 200, response.Status())
+	var health definitions.ProvisioningHealth
+	require.NoError(t, json.Unmarshal(response.Body(), &health))
+	require.Positive(t, health.ConfigSize)
+	require.NotNil(t, health.LastConfigSave)
+	require.Nil(t, health.FileProvisioning)
+	require.NotNil(t, health.InvalidReceivers)
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// MaintenanceWindowService mutes notification policies once or on a recurring schedule.
+type MaintenanceWindowService interface {
+	GetMaintenanceWindows(ctx context.Context, orgID int64) ([]definitions.MaintenanceWindow, error)
+	CreateMaintenanceWindow(ctx context.Context, orgID int64, mw definitions.MaintenanceWindow, p alerting_models.Provenance) (definitions.MaintenanceWindow, error)
+	DeleteMaintenanceWindow(ctx context.Context, orgID int64, name string) error
+}
+
+func (srv *ProvisioningSrv) RouteGetMaintenanceWindows(c *contextmodel.ReqContext) response.Response {
+	windows, err := srv.maintenanceWindows.GetMaintenanceWindows(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, windows)
+}
+
+func (srv *ProvisioningSrv) RoutePostMaintenanceWindow(c *contextmodel.ReqContext, mw definitions.MaintenanceWindow) response.Response {
+	provenance := determineProvenance(c)
+	created, err := srv.maintenanceWindows.CreateMaintenanceWindow(c.Req.Context(), c.OrgID, mw, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteMaintenanceWindow(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.maintenanceWindows.DeleteMaintenanceWindow(c.Req.Context(), c.OrgID, name)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
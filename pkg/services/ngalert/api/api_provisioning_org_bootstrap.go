@@ -0,0 +1,59 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// OrgBootstrapService manages the provisioning bundle that is applied to every new organization of the instance.
+type OrgBootstrapService interface {
+	GetOrgBootstrapBundle(ctx context.Context) (definitions.OrgBootstrapBundle, error)
+	SetOrgBootstrapBundle(ctx context.Context, bundle provisioning.ProvisioningBundle, userID int64, provenance alerting_models.Provenance) (definitions.OrgBootstrapBundle, error)
+	DeleteOrgBootstrapBundle(ctx context.Context) error
+}
+
+func (srv *ProvisioningSrv) RouteGetOrgBootstrapBundle(c *contextmodel.ReqContext) response.Response {
+	bundle, err := srv.orgBootstrap.GetOrgBootstrapBundle(c.Req.Context())
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, bundle)
+}
+
+func (srv *ProvisioningSrv) RoutePutOrgBootstrapBundle(c *contextmodel.ReqContext, body definitions.ProvisioningBundle) response.Response {
+	bundle, err := provisioningBundleFromApi(body)
+	if err != nil {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+
+	provenance := determineProvenance(c)
+	summary, err := srv.orgBootstrap.SetOrgBootstrapBundle(c.Req.Context(), bundle, c.UserID, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to set the bootstrap bundle")
+	}
+	return response.JSON(http.StatusAccepted, summary)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteOrgBootstrapBundle(c *contextmodel.ReqContext) response.Response {
+	err := srv.orgBootstrap.DeleteOrgBootstrapBundle(c.Req.Context())
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
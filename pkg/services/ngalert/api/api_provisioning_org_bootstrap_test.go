@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRouteOrgBootstrapBundle(t *testing.T) {
+	t.Run("successful PUT returns 202 and the summary of the bundle", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+		body := definitions.ProvisioningBundle{
+			Templates: []definitions.NotificationTemplate{{Name: "base", Template: `{{ define "base" }}text{{ end }}`}},
+		}
+
+		response := sut.RoutePutOrgBootstrapBundle(&rc, body)
+
+		require.Equal(t, 202, response.Status())
+		response = sut.RouteGetOrgBootstrapBundle(&rc)
+		require.Equal(t, 200, response.Status())
+		var summary definitions.OrgBootstrapBundle
+		require.NoError(t, json.Unmarshal(response.Body(), &summary))
+		require.Equal(t, 1, summary.Templates)
+		require.Equal(t, definitions.Provenance("api"), summary.Provenance)
+
+		response = sut.RouteDeleteOrgBootstrapBundle(&rc)
+		require.Equal(t, 204, response.Status())
+	})
+
+	t.Run("empty bundle returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutOrgBootstrapBundle(&rc, definitions.ProvisioningBundle{})
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("missing bundle returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		require.Equal(t, 404, sut.RouteGetOrgBootstrapBundle(&rc).Status())
+		require.Equal(t, 404, sut.RouteDeleteOrgBootstrapBundle(&rc).Status())
+	})
+}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// ProvenanceService lists the provenance of the alerting resources and removes the provenance records whose
+// resources no longer exist.
+type ProvenanceService interface {
+	GetProvenances(ctx context.Context, orgID int64, resourceType string) ([]definitions.ResourceProvenance, error)
+	CleanupOrphanedProvenance(ctx context.Context) (definitions.ProvenanceCleanup, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetProvenances(c *contextmodel.ReqContext) response.Response {
+	provenances, err := srv.provenance.GetProvenances(c.Req.Context(), c.OrgID, c.Query("resourceType"))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the provenance of the resources")
+	}
+	return response.JSON(http.StatusOK, provenances)
+}
+
+func (srv *ProvisioningSrv) RoutePostProvenanceCleanup(c *contextmodel.ReqContext) response.Response {
+	result, err := srv.provenance.CleanupOrphanedProvenance(c.Req.Context())
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to clean up orphaned provenance records")
+	}
+	return response.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// SilenceService manages silences of the Alertmanager that are tracked by provisioning.
+type SilenceService interface {
+	GetSilences(ctx context.Context, orgID int64) ([]definitions.ProvisionedSilence, error)
+	GetSilence(ctx context.Context, orgID int64, uid string) (definitions.ProvisionedSilence, error)
+	CreateSilence(ctx context.Context, orgID int64, s definitions.ProvisionedSilence, p alerting_models.Provenance) (definitions.ProvisionedSilence, error)
+	UpdateSilence(ctx context.Context, orgID int64, s definitions.ProvisionedSilence, p alerting_models.Provenance) (definitions.ProvisionedSilence, error)
+	DeleteSilence(ctx context.Context, orgID int64, uid string) error
+}
+
+func (srv *ProvisioningSrv) RouteGetSilences(c *contextmodel.ReqContext) response.Response {
+	silences, err := srv.silences.GetSilences(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, silences)
+}
+
+func (srv *ProvisioningSrv) RouteGetSilence(c *contextmodel.ReqContext, uid string) response.Response {
+	silence, err := srv.silences.GetSilence(c.Req.Context(), c.OrgID, uid)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, silence)
+}
+
+func (srv *ProvisioningSrv) RoutePostSilence(c *contextmodel.ReqContext, s definitions.ProvisionedSilence) response.Response {
+	provenance := determineProvenance(c)
+	created, err := srv.silences.CreateSilence(c.Req.Context(), c.OrgID, s, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusCreated, created)
+}
+
+func (srv *ProvisioningSrv) RoutePutSilence(c *contextmodel.ReqContext, s definitions.ProvisionedSilence, uid string) response.Response {
+	s.UID = uid
+	provenance := determineProvenance(c)
+	updated, err := srv.silences.UpdateSilence(c.Req.Context(), c.OrgID, s, alerting_models.Provenance(provenance))
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, updated)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteSilence(c *contextmodel.ReqContext, uid string) response.Response {
+	err := srv.silences.DeleteSilence(c.Req.Context(), c.OrgID, uid)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
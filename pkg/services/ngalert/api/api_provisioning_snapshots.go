@@ -0,0 +1,42 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// SnapshotService lists the snapshots of the alerting configuration of an organization and restores them.
+type SnapshotService interface {
+	GetSnapshots(ctx context.Context, orgID int64) ([]definitions.AlertingSnapshot, error)
+	RestoreSnapshot(ctx context.Context, orgID int64, at time.Time) (definitions.AlertingSnapshot, error)
+}
+
+func (srv *ProvisioningSrv) RouteGetAlertingSnapshots(c *contextmodel.ReqContext) response.Response {
+	snapshots, err := srv.snapshots.GetSnapshots(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to get the alerting configuration snapshots")
+	}
+	return response.JSON(http.StatusOK, snapshots)
+}
+
+func (srv *ProvisioningSrv) RoutePostAlertingSnapshotRestore(c *contextmodel.ReqContext, body definitions.AlertingSnapshotRestore) response.Response {
+	if body.Timestamp.IsZero() {
+		return provisioningErrResp(http.StatusBadRequest, fmt.Errorf("%w: timestamp is required", provisioning.ErrValidation), "")
+	}
+	snapshot, err := srv.snapshots.RestoreSnapshot(c.Req.Context(), c.OrgID, body.Timestamp)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "failed to restore the alerting configuration snapshot")
+	}
+	return response.JSON(http.StatusAccepted, snapshot)
+}
@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+func TestRoutePostAlertingSnapshotRestore(t *testing.T) {
+	t.Run("restores the snapshot and returns 202", func(t *testing.T) {
+		snapshots := &fakeSnapshotService{snapshot: definitions.AlertingSnapshot{ID: 1, Created: time.Unix(100, 0)}}
+		sut := createProvisioningSrvSut(t)
+		sut.snapshots = snapshots
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingSnapshotRestore(&rc, definitions.AlertingSnapshotRestore{Timestamp: time.Unix(150, 0)})
+
+		require.Equal(t, 202, response.Status())
+		require.Equal(t, time.Unix(150, 0), snapshots.restoredAt)
+	})
+
+	t.Run("missing timestamp returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.snapshots = &fakeSnapshotService{}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingSnapshotRestore(&rc, definitions.AlertingSnapshotRestore{})
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("no snapshot returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		sut.snapshots = &fakeSnapshotService{err: fmt.Errorf("%w: no snapshot", provisioning.ErrNotFound)}
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePostAlertingSnapshotRestore(&rc, definitions.AlertingSnapshotRestore{Timestamp: time.Unix(150, 0)})
+
+		require.Equal(t, 404, response.Status())
+	})
+}
+
+type fakeSnapshotService struct {
+	snapshot   definitions.AlertingSnapshot
+	err        error
+	restoredAt time.Time
+}
+
+func (f *fakeSnapshotService) GetSnapshots(context.Context, int64) ([]definitions.AlertingSnapshot, error) {
+	return []definitions.AlertingSnapshot{f.snapshot}, f.err
+}
+
+func (f *fakeSnapshotService) RestoreSnapshot(_ context.Context, _ int64, at time.Time) (definitions.AlertingSnapshot, error) {
+	f.restoredAt = at
+	return f.snapshot, f.err
+}
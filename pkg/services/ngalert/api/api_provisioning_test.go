@@ -7,9 +7,12 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/grafana/grafana-plugin-sdk-go/data"
 	prometheus "github.com/prometheus/alertmanager/config"
 	"github.com/prometheus/alertmanager/pkg/labels"
 	"github.com/prometheus/alertmanager/timeinterval"
@@ -18,13 +21,18 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
@@ -47,6 +55,60 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 200, response.Status())
 		})
 
+		t.Run("GET with path and depth returns the selected subtree", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.policies = createFakeNotificationPolicyService()
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("path", "0")
+			rc.Context.Req.Form.Set("depth", "1")
+
+			response := sut.RouteGetPolicyTree(&rc)
+
+			require.Equal(t, 200, response.Status())
+			route := definitions.Route{}
+			require.NoError(t, json.Unmarshal(response.Body(), &route))
+			require.Equal(t, "nested-receiver", route.Receiver)
+			require.Empty(t, route.Routes)
+		})
+
+		t.Run("GET with invalid path returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("path", "a.b")
+
+			response := sut.RouteGetPolicyTree(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("GET with too long path or too large depth returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.policies = createFakeNotificationPolicyService()
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("path", strings.Repeat("0.", provisioning.MaxPolicySubtreeDepth)+"0")
+
+			response := sut.RouteGetPolicyTree(&rc)
+
+			require.Equal(t, 400, response.Status())
+
+			rc = createTestRequestCtx()
+			rc.Context.Req.Form.Set("depth", strconv.Itoa(provisioning.MaxPolicySubtreeDepth+1))
+
+			response = sut.RouteGetPolicyTree(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("GET with path outside of the tree returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("path", "5")
+
+			response := sut.RouteGetPolicyTree(&rc)
+
+			require.Equal(t, 404, response.Status())
+		})
+
 		t.Run("successful PUT returns 202", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
@@ -66,6 +128,85 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 202, response.Status())
 		})
 
+		t.Run("POST route adds a child to the parent and returns 201", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			policies := sut.policies.(*fakeNotificationPolicyService)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("path", "0")
+
+			response := sut.RoutePostPolicyRoute(&rc, definitions.Route{Receiver: "team-receiver"})
+
+			require.Equal(t, 201, response.Status())
+			require.Equal(t, []int{0}, policies.lastRef.Path)
+			require.Equal(t, models.ProvenanceAPI, policies.prov)
+		})
+
+		t.Run("POST route with invalid path returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("path", "a")
+
+			response := sut.RoutePostPolicyRoute(&rc, definitions.Route{})
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("PUT route addresses the route by UID and returns 202", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			policies := sut.policies.(*fakeNotificationPolicyService)
+			rc := createTestRequestCtx()
+			rc.Req.Header.Add(disableProvenanceHeaderName, "true")
+
+			response := sut.RoutePutPolicyRoute(&rc, definitions.Route{Receiver: "team-receiver"}, "team-route")
+
+			require.Equal(t, 202, response.Status())
+			require.Equal(t, "team-route", policies.lastRef.UID)
+			require.Equal(t, models.ProvenanceNone, policies.prov)
+		})
+
+		t.Run("DELETE route returns 204", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeletePolicyRoute(&rc, "team-route")
+
+			require.Equal(t, 204, response.Status())
+		})
+
+		t.Run("POST test returns the routing of each label set", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeTest(&rc, definitions.RoutePolicyTest{
+				LabelSets: []model.LabelSet{{"team": "a"}, {"team": "b"}},
+			})
+
+			require.Equal(t, 200, response.Status())
+			var results []definitions.RoutePolicyTestResult
+			require.NoError(t, json.Unmarshal(response.Body(), &results))
+			require.Len(t, results, 2)
+		})
+
+		t.Run("POST test with invalid labels returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.policies = &fakeRejectingNotificationPolicyService{}
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostPolicyTreeTest(&rc, definitions.RoutePolicyTest{})
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("unknown route returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			sut.policies = &fakeRejectingNotificationPolicyService{}
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeletePolicyRoute(&rc, "unknown")
+
+			require.Equal(t, 404, response.Status())
+		})
+
 		t.Run("when new policy tree is invalid", func(t *testing.T) {
 			t.Run("PUT returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -76,7 +217,20 @@ func TestProvisioningApi(t *testing.T) {
 				response := sut.RoutePutPolicyTree(&rc, tree)
 
 				require.Equal(t, 400, response.Status())
-				expBody := `{"error":"invalid object specification: invalid policy tree","message":"invalid object specification: invalid policy tree"}`
+				expBody := `{"type":"about:blank","title":"Bad Request","status":400,"detail":"invalid object specification: invalid policy tree","code":"invalid","message":"invalid object specification: invalid policy tree"}`
+				require.Equal(t, expBody, string(response.Body()))
+			})
+
+			t.Run("PUT returns 400 with the broken references", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				sut.policies = &fakeBrokenReferencesNotificationPolicyService{}
+				rc := createTestRequestCtx()
+				tree := definitions.Route{}
+
+				response := sut.RoutePutPolicyTree(&rc, tree)
+
+				require.Equal(t, 400, response.Status())
+				expBody := `{"message":"invalid object specification: receiver 'unknown' of route at path [0] does not exist","brokenReferences":[{"path":[0],"uid":"broken","kind":"receiver","name":"unknown"}]}`
 				require.Equal(t, expBody, string(response.Body()))
 			})
 		})
@@ -144,6 +298,23 @@ func TestProvisioningApi(t *testing.T) {
 	})
 
 	t.Run("contact points", func(t *testing.T) {
+		t.Run("have reached the contact point quota, POST returns 403", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			// The quota is checked in the transaction that saves the configuration, which is rolled back.
+			env.configs.(*provisioning.MockAMConfigStore).EXPECT().SaveSucceeds()
+			quotas := provisioning.MockQuotaChecker{}
+			quotas.EXPECT().LimitExceeded()
+			env.quotas = &quotas
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			cp := createInvalidContactPoint()
+			cp.Settings.Set("url", "https://hooks.slack.com/services/test")
+
+			response := sut.RoutePostContactPoint(&rc, cp)
+
+			require.Equal(t, 403, response.Status())
+		})
+
 		t.Run("are invalid", func(t *testing.T) {
 			t.Run("POST returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -179,6 +350,265 @@ func TestProvisioningApi(t *testing.T) {
 
 			require.Equal(t, 404, response.Status())
 		})
+
+		t.Run("are missing, PUT returns the problem details", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			cp := createInvalidContactPoint()
+
+			response := sut.RoutePutContactPoint(&rc, cp, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+			problem := definitions.ProvisioningProblem{}
+			require.NoError(t, json.Unmarshal(response.Body(), &problem))
+			require.Equal(t, 404, problem.Status)
+			require.Equal(t, "Not Found", problem.Title)
+			require.Equal(t, string(provisioning.ErrCodeNotFound), problem.Code)
+			require.Equal(t, "contactPoint", problem.ResourceType)
+			require.Equal(t, "does not exist", problem.ResourceID)
+			require.Equal(t, problem.Detail, problem.Message)
+		})
+
+		t.Run("with receiver permissions, GET returns only the permitted contact points", func(t *testing.T) {
+			env := createTestEnv(t, testContactPointConfig)
+			permitted := "ad95bd8a-49ed-4adc-bf89-1b444fa1aa5b"
+			env.ac.Callback = func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+				return evaluator.Evaluate(map[string][]string{
+					accesscontrol.ActionAlertingReceiversRead: {accesscontrol.ScopeReceiversProvider.GetResourceScopeUID(permitted)},
+				}), nil
+			}
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetContactPoints(&rc)
+
+			require.Equal(t, 200, response.Status())
+			cps := []definitions.EmbeddedContactPoint{}
+			require.NoError(t, json.Unmarshal(response.Body(), &cps))
+			require.Len(t, cps, 1)
+			require.Equal(t, permitted, cps[0].UID)
+		})
+
+		t.Run("are missing, migrate returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostContactPointMigrate(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, rotating secrets returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutContactPointSecrets(&rc, definitions.ContactPointSecrets{"url": "https://hooks.slack.com/services/test"}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("have no such secure setting, rotating secrets returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePutContactPointSecrets(&rc, definitions.ContactPointSecrets{"addresses": "test@example.com"}, "email-uid")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are not deprecated, migrate returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostContactPointMigrate(&rc, "email-uid")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are deleted, they can be restored unless deleted permanently", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			keepSavedConfigs(t, &env)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			cp := createInvalidContactPoint()
+			cp.Settings.Set("url", "https://hooks.slack.com/services/test")
+			response := sut.RoutePostContactPoint(&rc, cp)
+			require.Equal(t, 202, response.Status())
+			created := definitions.EmbeddedContactPoint{}
+			require.NoError(t, json.Unmarshal(response.Body(), &created))
+
+			response = sut.RouteDeleteContactPoint(&rc, created.UID)
+			require.Equal(t, 202, response.Status())
+			response = sut.RouteGetDeletedContactPoints(&rc)
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), created.UID)
+			response = sut.RoutePostContactPointRestore(&rc, created.UID)
+			require.Equal(t, 202, response.Status())
+
+			rc.Context.Req.Form.Set("permanent", "true")
+			response = sut.RouteDeleteContactPoint(&rc, created.UID)
+			require.Equal(t, 202, response.Status())
+			response = sut.RoutePostContactPointRestore(&rc, created.UID)
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("status is requested, only contact points whose last attempt failed are returned with failing", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetContactPointStatus(&rc)
+			require.Equal(t, 200, response.Status())
+			var statuses []definitions.ContactPointStatus
+			require.NoError(t, json.Unmarshal(response.Body(), &statuses))
+			require.NotEmpty(t, statuses)
+
+			rc.Context.Req.Form.Set("failing", "true")
+			response = sut.RouteGetContactPointStatus(&rc)
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &statuses))
+			require.Empty(t, statuses)
+		})
+
+		t.Run("enable is requested for an unknown contact point, POST returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostContactPointEnable(&rc, "unknown")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are disabled, they are reported as failing until they are enabled", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			keepSavedConfigs(t, &env)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("failing", "true")
+
+			response := sut.RoutePostContactPointDisable(&rc, "email-uid")
+			require.Equal(t, 202, response.Status())
+			disabled := definitions.EmbeddedContactPoint{}
+			require.NoError(t, json.Unmarshal(response.Body(), &disabled))
+			require.True(t, disabled.Disabled)
+			response = sut.RouteGetContactPointStatus(&rc)
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), "email-uid")
+
+			response = sut.RoutePostContactPointEnable(&rc, "email-uid")
+			require.Equal(t, 202, response.Status())
+			response = sut.RouteGetContactPointStatus(&rc)
+			require.Equal(t, 200, response.Status())
+			require.NotContains(t, string(response.Body()), "email-uid")
+
+			response = sut.RoutePostContactPointDisable(&rc, "unknown")
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are changed since the version in If-Match, PUT and DELETE return 409", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			keepSavedConfigs(t, &env)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			cp := createInvalidContactPoint()
+			cp.Settings.Set("url", "https://hooks.slack.com/services/test")
+			response := sut.RoutePostContactPoint(&rc, cp)
+			require.Equal(t, 202, response.Status())
+			created := definitions.EmbeddedContactPoint{}
+			require.NoError(t, json.Unmarshal(response.Body(), &created))
+			rc.Context.Req.Header.Set("If-Match", `"outdated"`)
+
+			response = sut.RoutePutContactPoint(&rc, created, created.UID)
+			require.Equal(t, 409, response.Status())
+			response = sut.RouteDeleteContactPoint(&rc, created.UID)
+			require.Equal(t, 409, response.Status())
+		})
+
+		t.Run("are created in dry-run mode, POST returns the changes without saving them", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			cp := createInvalidContactPoint()
+			cp.Name = "dry-run-contact-point"
+			cp.Settings.Set("url", "https://hooks.slack.com/services/test")
+			rc.Context.Req.Form.Set("dryRun", "true")
+
+			response := sut.RoutePostContactPoint(&rc, cp)
+
+			require.Equal(t, 200, response.Status())
+			result := definitions.ProvisioningDryRun{}
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.NotEmpty(t, result.Changes)
+			rc.Context.Req.Form.Del("dryRun")
+			response = sut.RouteGetContactPoints(&rc)
+			require.NotContains(t, string(response.Body()), "dry-run-contact-point")
+		})
+
+		t.Run("are paged, GET returns the total count in a header", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			keepSavedConfigs(t, &env)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			for _, name := range []string{"a", "b"} {
+				cp := createInvalidContactPoint()
+				cp.Name = name
+				cp.Settings.Set("url", "https://hooks.slack.com/services/test")
+				require.Equal(t, 202, sut.RoutePostContactPoint(&rc, cp).Status())
+			}
+			rc.Context.Req.Form.Set("limit", "1")
+			rc.Context.Req.Form.Set("sortBy", "uid")
+
+			response := sut.RouteGetContactPoints(&rc)
+			response.WriteTo(&rc)
+
+			require.Equal(t, 200, response.Status())
+			cps := definitions.ContactPoints{}
+			require.NoError(t, json.Unmarshal(response.Body(), &cps))
+			require.Len(t, cps, 1)
+			require.Equal(t, "3", rc.Context.Resp.Header().Get("X-Total-Count"))
+
+			rc.Context.Req.Form.Set("sortBy", "settings")
+			require.Equal(t, 400, sut.RouteGetContactPoints(&rc).Status())
+		})
+
+		t.Run("are listed, GET streams the contact points to the client", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			recorder := httptest.NewRecorder()
+			rc.Context.Resp = web.NewResponseWriter("GET", recorder)
+
+			response := sut.RouteGetContactPoints(&rc)
+			response.WriteTo(&rc)
+
+			require.Equal(t, 200, recorder.Code)
+			require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+			require.Equal(t, "1", recorder.Header().Get("X-Total-Count"))
+			cps := definitions.ContactPoints{}
+			require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &cps))
+			require.Len(t, cps, 1)
+			require.JSONEq(t, string(response.Body()), recorder.Body.String())
+		})
+
+		t.Run("are tested, POST returns the delivery of the test notification", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			cp := createInvalidContactPoint()
+			cp.Settings.Set("url", "https://hooks.slack.com/services/test")
+
+			response := sut.RoutePostContactPointTest(&rc, definitions.ContactPointTest{ContactPoint: cp})
+
+			require.Equal(t, 200, response.Status())
+			result := definitions.ContactPointTestResult{}
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Equal(t, "ok", result.Status)
+			require.Equal(t, 200, result.StatusCode)
+		})
+
+		t.Run("are invalid, POST test returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RoutePostContactPointTest(&rc, definitions.ContactPointTest{ContactPoint: createInvalidContactPoint()})
+
+			require.Equal(t, 400, response.Status())
+		})
 	})
 
 	t.Run("templates", func(t *testing.T) {
@@ -195,6 +625,17 @@ func TestProvisioningApi(t *testing.T) {
 				require.Contains(t, string(response.Body()), "template must have content")
 			})
 		})
+
+		t.Run("preview returns 200 with the parse errors", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			body := definitions.TemplatePreviewParams{Template: "{{ define \"test\" }}\n{{ .Status }"}
+
+			response := sut.RoutePostTemplatePreview(&rc, body)
+
+			require.Equal(t, 200, response.Status())
+			require.Contains(t, string(response.Body()), `"line":2`)
+		})
 	})
 
 	t.Run("mute timings", func(t *testing.T) {
@@ -216,20 +657,106 @@ func TestProvisioningApi(t *testing.T) {
 				rc := createTestRequestCtx()
 				mti := createInvalidMuteTiming()
 
-				response := sut.RoutePutMuteTiming(&rc, mti, "interval")
+				response := sut.RoutePutMuteTiming(&rc, mti, "interval")
+
+				require.Equal(t, 400, response.Status())
+				require.NotEmpty(t, response.Body())
+				require.Contains(t, string(response.Body()), "invalid")
+			})
+		})
+
+		t.Run("are missing, PUT returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			mti := definitions.MuteTimeInterval{}
+
+			response := sut.RoutePutMuteTiming(&rc, mti, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, GET usage returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetMuteTimingUsage(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
+	t.Run("maintenance windows", func(t *testing.T) {
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			now := time.Now()
+			mw := definitions.MaintenanceWindow{Name: "release", Start: now, End: now.Add(-time.Hour)}
+
+			response := sut.RoutePostMaintenanceWindow(&rc, mw)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are missing, DELETE returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeleteMaintenanceWindow(&rc, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+	})
+
+	t.Run("silences", func(t *testing.T) {
+		t.Run("are invalid, POST returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			now := time.Now()
+			silence := definitions.ProvisionedSilence{UID: "maintenance", StartsAt: now, EndsAt: now.Add(time.Hour)}
+
+			response := sut.RoutePostSilence(&rc, silence)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are created, GET returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			now := time.Now()
+			silence := definitions.ProvisionedSilence{
+				UID:      "maintenance",
+				Matchers: definitions.ObjectMatchers{{Name: "team", Value: "a"}},
+				StartsAt: now,
+				EndsAt:   now.Add(time.Hour),
+			}
+
+			response := sut.RoutePostSilence(&rc, silence)
+			require.Equal(t, 201, response.Status())
 
-				require.Equal(t, 400, response.Status())
-				require.NotEmpty(t, response.Body())
-				require.Contains(t, string(response.Body()), "invalid")
-			})
+			response = sut.RouteGetSilence(&rc, "maintenance")
+			require.Equal(t, 200, response.Status())
 		})
 
 		t.Run("are missing, PUT returns 404", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
-			mti := definitions.MuteTimeInterval{}
+			now := time.Now()
+			silence := definitions.ProvisionedSilence{
+				Matchers: definitions.ObjectMatchers{{Name: "team", Value: "a"}},
+				StartsAt: now,
+				EndsAt:   now.Add(time.Hour),
+			}
 
-			response := sut.RoutePutMuteTiming(&rc, mti, "does not exist")
+			response := sut.RoutePutSilence(&rc, silence, "does-not-exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are missing, DELETE returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteDeleteSilence(&rc, "does-not-exist")
 
 			require.Equal(t, 404, response.Status())
 		})
@@ -263,6 +790,19 @@ func TestProvisioningApi(t *testing.T) {
 				require.NotEmpty(t, response.Body())
 				require.Contains(t, string(response.Body()), "invalid alert rule")
 			})
+
+			t.Run("PATCH returns 400 if the condition is not a query", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				rule := createTestAlertRule("rule", 1)
+				rule.UID = t.Name()
+				insertRule(t, sut, rule)
+				condition := "does not exist"
+
+				response := sut.RoutePatchAlertRule(&rc, definitions.AlertRulePatch{Condition: &condition}, rule.UID)
+				require.Equal(t, 400, response.Status())
+				require.Contains(t, string(response.Body()), "invalid alert rule")
+			})
 		})
 
 		t.Run("exist in non-default orgs", func(t *testing.T) {
@@ -311,6 +851,62 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 404, response.Status())
 		})
 
+		t.Run("are missing, PATCH returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			title := "rule"
+
+			response := sut.RoutePatchAlertRule(&rc, definitions.AlertRulePatch{Title: &title}, "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("with provisioning permissions scoped to folders, POST returns 403 for other folders", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			env.ac.Callback = func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+				return evaluator.Evaluate(map[string][]string{
+					accesscontrol.ActionAlertingProvisioningWrite: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("team-folder")},
+				}), nil
+			}
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+			rc.Req = rc.Req.WithContext(appcontext.WithUser(context.Background(), rc.SignedInUser))
+
+			response := sut.RoutePostAlertRule(&rc, createTestAlertRuleWithFolderAndGroup("rule", 1, "other-folder", "group"))
+
+			require.Equal(t, 403, response.Status())
+			problem := definitions.ProvisioningProblem{}
+			require.NoError(t, json.Unmarshal(response.Body(), &problem))
+			require.Equal(t, string(provisioning.ErrCodePermissionDenied), problem.Code)
+
+			response = sut.RoutePostAlertRule(&rc, createTestAlertRuleWithFolderAndGroup("rule", 1, "team-folder", "group"))
+
+			require.Equal(t, 201, response.Status())
+		})
+
+		t.Run("are patched, PATCH returns 200 and keeps the fields that are not given", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rule := createUpdatableTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			rule.Labels = map[string]string{"team": "sre"}
+			insertRule(t, sut, rule)
+			rc := createTestRequestCtx()
+			forDuration := model.Duration(5 * time.Minute)
+
+			response := sut.RoutePatchAlertRule(&rc, definitions.AlertRulePatch{
+				For:         &forDuration,
+				Annotations: map[string]string{"summary": "patched"},
+			}, rule.UID)
+
+			require.Equal(t, 200, response.Status())
+			patched := deserializeRule(t, response.Body())
+			require.Equal(t, forDuration, patched.For)
+			require.Equal(t, map[string]string{"summary": "patched"}, patched.Annotations)
+			require.Equal(t, rule.Title, patched.Title)
+			require.Equal(t, rule.Condition, patched.Condition)
+			require.Equal(t, rule.Labels, patched.Labels)
+		})
+
 		t.Run("are missing, GET returns 404", func(t *testing.T) {
 			sut := createProvisioningSrvSut(t)
 			rc := createTestRequestCtx()
@@ -319,6 +915,101 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 404, response.Status())
 		})
 
+		t.Run("are filtered, GET returns 200 with the matching rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			insertRule(t, sut, createTestAlertRule("disk full", 1))
+			insertRule(t, sut, createTestAlertRule("instance down", 1))
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("title", "DISK")
+
+			response := sut.RouteGetAlertRules(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var rules definitions.ProvisionedAlertRules
+			require.NoError(t, json.Unmarshal(response.Body(), &rules))
+			require.Len(t, rules, 1)
+			require.Equal(t, "disk full", rules[0].Title)
+		})
+
+		t.Run("are filtered with an invalid label matcher, GET returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rc.Context.Req.Form.Set("label", `severity=~"("`)
+
+			response := sut.RouteGetAlertRules(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("link to a deleted dashboard, orphaned links are returned and cleared", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rule := createUpdatableTestAlertRule("rule", 1)
+			rule.UID = t.Name()
+			rule.Annotations = map[string]string{
+				models.DashboardUIDAnnotation: "deleted-dashboard",
+				models.PanelIDAnnotation:      "1",
+			}
+			insertRule(t, sut, rule)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetOrphanedRuleLinks(&rc)
+
+			require.Equal(t, 200, response.Status())
+			var links definitions.OrphanedRuleLinks
+			require.NoError(t, json.Unmarshal(response.Body(), &links))
+			require.Len(t, links, 1)
+			require.Equal(t, rule.UID, links[0].RuleUID)
+			require.True(t, links[0].DashboardMissing)
+
+			response = sut.RouteDeleteOrphanedRuleLinks(&rc)
+			require.Equal(t, 200, response.Status())
+
+			response = sut.RouteGetOrphanedRuleLinks(&rc)
+			require.Equal(t, 200, response.Status())
+			require.NoError(t, json.Unmarshal(response.Body(), &links))
+			require.Empty(t, links)
+		})
+
+		t.Run("are linted, POST returns 200 with the findings", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.Labels = map[string]string{"value": "{{ $value }}"}
+
+			response := sut.RoutePostAlertRuleLint(&rc, rule)
+
+			require.Equal(t, 200, response.Status())
+			var result definitions.AlertRuleLintResult
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Len(t, result.Findings, 2)
+			require.Equal(t, provisioning.LintCodeValueInLabels, result.Findings[0].Code)
+			require.Equal(t, "error", result.Findings[0].Severity)
+			require.Equal(t, provisioning.LintCodeMissingSummary, result.Findings[1].Code)
+		})
+
+		t.Run("are tested, POST returns 200 with the resulting instances", func(t *testing.T) {
+			env := createTestEnv(t, testConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			evaluator := &eval_mocks.ConditionEvaluatorMock{}
+			evaluator.EXPECT().Evaluate(mock.Anything, mock.Anything).Call.Return(func(_ context.Context, now time.Time) eval.Results {
+				return eval.Results{{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: now}}
+			}, nil)
+			sut.ruleTester = provisioning.NewAlertRuleTestService(eval_mocks.NewEvaluatorFactory(evaluator), env.dashboardService,
+				setting.UnifiedAlertingSettings{}, nil, env.log, env.tracer, nil)
+			rc := createTestRequestCtx()
+			rule := createTestAlertRule("rule", 1)
+			rule.For = 0
+
+			response := sut.RoutePostAlertRuleTest(&rc, rule)
+
+			require.Equal(t, 200, response.Status())
+			var result definitions.AlertRuleTestResult
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Len(t, result.Instances, 1)
+			require.Equal(t, "Alerting", result.Instances[0].State)
+			require.Equal(t, "a", result.Instances[0].Labels["instance"])
+		})
+
 		t.Run("have reached the rule quota, POST returns 403", func(t *testing.T) {
 			env := createTestEnv(t, testConfig)
 			quotas := provisioning.MockQuotaChecker{}
@@ -355,6 +1046,117 @@ func TestProvisioningApi(t *testing.T) {
 			require.Equal(t, 404, response.Status())
 		})
 
+		t.Run("are present, PUT pause returns 200 with the paused rules", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createUpdatableTestAlertRule("rule", 1))
+
+			response := sut.RoutePutAlertRuleGroupPause(&rc, definitions.AlertRuleGroupPause{Paused: true}, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			var group definitions.AlertRuleGroup
+			require.NoError(t, json.Unmarshal(response.Body(), &group))
+			require.NotEmpty(t, group.Rules)
+			for _, rule := range group.Rules {
+				require.True(t, rule.IsPaused)
+			}
+		})
+
+		t.Run("are missing, PUT pause returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RoutePutAlertRuleGroupPause(&rc, definitions.AlertRuleGroupPause{Paused: true}, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are present, PUT evaluation returns 200 and GET evaluation returns it", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createUpdatableTestAlertRule("rule", 1))
+			evaluation := definitions.AlertRuleGroupEvaluation{Interval: 120, EvaluationOffset: 30}
+
+			response := sut.RoutePutAlertRuleGroupEvaluation(&rc, evaluation, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+
+			response = sut.RouteGetAlertRuleGroupEvaluation(&rc, "folder-uid", "my-cool-group")
+			require.Equal(t, 200, response.Status())
+			var read definitions.AlertRuleGroupEvaluation
+			require.NoError(t, json.Unmarshal(response.Body(), &read))
+			require.Equal(t, evaluation, read)
+		})
+
+		t.Run("have an offset that is not shorter than the interval, PUT evaluation returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RoutePutAlertRuleGroupEvaluation(&rc, definitions.AlertRuleGroupEvaluation{Interval: 60, EvaluationOffset: 60}, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 400, response.Status())
+		})
+
+		t.Run("are missing, GET evaluation returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleGroupEvaluation(&rc, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are present, GET load returns 200", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			insertRule(t, sut, createTestAlertRule("rule", 1))
+
+			response := sut.RouteGetAlertRuleGroupLoad(&rc, "folder-uid", "my-cool-group")
+
+			require.Equal(t, 200, response.Status())
+			var load definitions.AlertRuleGroupLoad
+			require.NoError(t, json.Unmarshal(response.Body(), &load))
+			require.Equal(t, 1, load.Rules)
+			require.Nil(t, load.EvaluationDuration)
+		})
+
+		t.Run("are missing, GET load returns 404", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			response := sut.RouteGetAlertRuleGroupLoad(&rc, "folder-uid", "does not exist")
+
+			require.Equal(t, 404, response.Status())
+		})
+
+		t.Run("are imported from a rule file, POST import returns 202", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			body := definitions.AlertRuleImport{
+				FolderUID:     "folder-uid",
+				DatasourceUID: "prometheus",
+				Rules:         "groups:\n  - name: imported\n    rules:\n      - alert: InstanceDown\n        expr: up == 0\n",
+			}
+
+			response := sut.RoutePostAlertRuleImport(&rc, body)
+
+			require.Equal(t, 202, response.Status())
+			var result definitions.AlertRuleImportResult
+			require.NoError(t, json.Unmarshal(response.Body(), &result))
+			require.Equal(t, []string{"imported"}, result.Groups)
+		})
+
+		t.Run("are imported from an invalid rule file, POST import returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+			body := definitions.AlertRuleImport{FolderUID: "folder-uid", DatasourceUID: "prometheus", Rules: "groups: {"}
+
+			response := sut.RoutePostAlertRuleImport(&rc, body)
+
+			require.Equal(t, 400, response.Status())
+		})
+
 		t.Run("are invalid at group level", func(t *testing.T) {
 			t.Run("PUT returns 400", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
@@ -514,6 +1316,41 @@ func TestProvisioningApi(t *testing.T) {
 				require.Equal(t, "", rc.Context.Resp.Header().Get("Content-Disposition"))
 			})
 
+			t.Run("target prometheus with rules without a PromQL expression, GET returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("target", "prometheus")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 400, response.Status())
+			})
+
+			t.Run("target prometheus skipping incompatible rules, GET returns 200 without them", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+				insertRule(t, sut, createTestAlertRule("rule", 1))
+
+				rc.Context.Req.Form.Set("target", "prometheus")
+				rc.Context.Req.Form.Set("skipIncompatible", "true")
+				rc.Context.Req.Form.Set("format", "json")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 200, response.Status())
+				require.JSONEq(t, "{}", string(response.Body()))
+			})
+
+			t.Run("unknown target, GET returns 400", func(t *testing.T) {
+				sut := createProvisioningSrvSut(t)
+				rc := createTestRequestCtx()
+
+				rc.Context.Req.Form.Set("target", "mimir")
+				response := sut.RouteGetAlertRuleGroupExport(&rc, "folder-uid", "my-cool-group")
+
+				require.Equal(t, 400, response.Status())
+			})
+
 			t.Run("yaml body content is the default", func(t *testing.T) {
 				sut := createProvisioningSrvSut(t)
 				rc := createTestRequestCtx()
@@ -1033,7 +1870,6 @@ func TestProvisioningApiContactPointExport(t *testing.T) {
 			env := createTestEnv(t, testConfig)
 			env.ac = &recordingAccessControlFake{
 				Callback: func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
-					require.Equal(t, accesscontrol.ActionAlertingProvisioningReadSecrets, evaluator.String())
 					return true, nil
 				},
 			}
@@ -1047,7 +1883,8 @@ func TestProvisioningApiContactPointExport(t *testing.T) {
 			response.WriteTo(&rc)
 
 			require.Equal(t, 200, response.Status())
-			require.Len(t, env.ac.EvaluateRecordings, 1)
+			// The permission to decrypt secure settings is checked first, then the permission to read the contact points.
+			require.Len(t, env.ac.EvaluateRecordings, 2)
 			require.Equal(t, accesscontrol.ActionAlertingProvisioningReadSecrets, env.ac.EvaluateRecordings[0].Evaluator.String())
 		})
 
@@ -1170,6 +2007,32 @@ func TestProvisioningApiContactPointExport(t *testing.T) {
 				require.Equal(t, expectedResponse, string(response.Body()))
 			})
 		})
+
+		t.Run("target alertmanager, yaml body contains receivers of the Prometheus Alertmanager", func(t *testing.T) {
+			env := createTestEnv(t, testContactPointConfig)
+			sut := createProvisioningSrvSutFromEnv(t, &env)
+			rc := createTestRequestCtx()
+
+			rc.Context.Req.Header.Add("Accept", "application/yaml")
+			rc.Context.Req.Form.Set("target", "alertmanager")
+
+			response := sut.RouteGetContactPointsExport(&rc)
+
+			expectedResponse := "receivers:\n    - name: grafana-default-email\n      email_configs:\n        - send_resolved: true\n          to: <example@email.com>\n    - name: multiple integrations\n      discord_configs:\n        - send_resolved: true\n          webhook_url: some url\n    - name: pagerduty test\n      pagerduty_configs:\n        - client: some client\n          routing_key: '[REDACTED]'\n          send_resolved: true\n          severity: criticalish\n    - name: slack test\n      slack_configs:\n        - api_url: '[REDACTED]'\n          send_resolved: false\n          text: title body test\n          title: title test\n"
+			require.Equal(t, 200, response.Status())
+			require.Equal(t, expectedResponse, string(response.Body()))
+		})
+
+		t.Run("unknown target, GET returns 400", func(t *testing.T) {
+			sut := createProvisioningSrvSut(t)
+			rc := createTestRequestCtx()
+
+			rc.Context.Req.Form.Set("target", "mimir")
+
+			response := sut.RouteGetContactPointsExport(&rc)
+
+			require.Equal(t, 400, response.Status())
+		})
 	})
 }
 
@@ -1184,6 +2047,7 @@ type testEnvironment struct {
 	quotas           provisioning.QuotaChecker
 	prov             provisioning.ProvisioningStore
 	ac               *recordingAccessControlFake
+	tracer           tracing.Tracer
 }
 
 func createTestEnv(t *testing.T, testConfig string) testEnvironment {
@@ -1236,7 +2100,12 @@ func createTestEnv(t *testing.T, testConfig string) testEnvironment {
 			Title: "Folder Title2",
 		}}, nil).Maybe()
 
-	ac := &recordingAccessControlFake{}
+	// The user of the requests has all permissions, unless the test says otherwise.
+	ac := &recordingAccessControlFake{
+		Callback: func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+			return true, nil
+		},
+	}
 
 	return testEnvironment{
 		secrets:          secretsService,
@@ -1247,10 +2116,23 @@ func createTestEnv(t *testing.T, testConfig string) testEnvironment {
 		xact:             xact,
 		prov:             prov,
 		quotas:           quotas,
+		tracer:           tracing.InitializeTracerForTest(),
 		ac:               ac,
 	}
 }
 
+// keepSavedConfigs makes the configuration store of the environment return the configuration that was saved last, for
+// tests that read what they changed.
+func keepSavedConfigs(t *testing.T, env *testEnvironment) {
+	t.Helper()
+
+	initial, err := env.configs.GetLatestAlertmanagerConfiguration(context.Background(), &models.GetLatestAlertmanagerConfigurationQuery{OrgID: 1})
+	require.NoError(t, err)
+	configs := &provisioning.MockAMConfigStore{}
+	configs.EXPECT().GetsAndSavesConfig(*initial)
+	env.configs = configs
+}
+
 func createProvisioningSrvSut(t *testing.T) ProvisioningSrv {
 	t.Helper()
 
@@ -1261,13 +2143,27 @@ func createProvisioningSrvSut(t *testing.T) ProvisioningSrv {
 func createProvisioningSrvSutFromEnv(t *testing.T, env *testEnvironment) ProvisioningSrv {
 	t.Helper()
 
+	orgs := notifier.NewFakeOrgStore(t, []int64{1})
+	variables := provisioning.NewProvisioningVariablesService(kvstore.NewFakeKVStore(), env.tracer, nil)
 	return ProvisioningSrv{
 		log:                 env.log,
+		orgs:                &orgs,
+		audit:               env.store,
+		health:              provisioning.NewHealthService(env.configs, env.secrets, provisioning.NewFileProvisioningStatusStore(kvstore.NewFakeKVStore()), env.log, env.tracer, nil),
+		effectiveConfig:     provisioning.NewEffectiveConfigService(env.configs, env.prov, env.store, env.log, env.tracer, nil),
 		policies:            newFakeNotificationPolicyService(),
-		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, env.prov, env.xact, env.log, env.ac),
-		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.log),
-		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.xact, env.log),
-		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.dashboardService, env.quotas, env.xact, 60, 10, env.log),
+		contactPointService: provisioning.NewContactPointService(env.configs, env.secrets, nil, env.prov, env.store, provisioning.NewContactPointExpirationStore(kvstore.NewFakeKVStore()), provisioning.NewDeletedContactPointStore(kvstore.NewFakeKVStore(), time.Hour), variables, &provisioning.FakeReceiverTester{}, &provisioning.FakeIntegrationStatusReader{}, http.DefaultClient, env.store, env.xact, env.quotas, env.log, env.ac, env.tracer, nil),
+		templates:           provisioning.NewTemplateService(env.configs, env.prov, env.xact, env.quotas, env.log, env.tracer, nil),
+		muteTimings:         provisioning.NewMuteTimingService(env.configs, env.prov, env.store, env.xact, env.quotas, env.log, env.tracer, nil),
+		maintenanceWindows:  provisioning.NewMaintenanceWindowService(env.configs, env.prov, kvstore.NewFakeKVStore(), env.xact, env.log, env.tracer, nil),
+		silences:            provisioning.NewSilenceService(nil, env.prov, kvstore.NewFakeKVStore(), env.xact, env.log, env.tracer, nil),
+		bundleScheduler:     provisioning.NewBundleScheduler(nil, nil, kvstore.NewFakeKVStore(), env.secrets, env.xact, env.log, env.tracer, nil),
+		orgBootstrap:        provisioning.NewOrgBootstrapService(nil, nil, kvstore.NewFakeKVStore(), env.secrets, env.xact, env.log, env.tracer, nil),
+		alertRules:          provisioning.NewAlertRuleService(env.store, env.prov, env.configs, env.dashboardService, env.quotas, env.xact, nil, 60, 10, env.log, env.ac, env.tracer, nil),
+		globalContactPoints: provisioning.NewGlobalContactPointService(kvstore.NewFakeKVStore(), env.configs, env.secrets, env.prov, env.xact, &orgs, env.log, env.tracer, nil),
+		globalTemplates:     provisioning.NewGlobalTemplateService(kvstore.NewFakeKVStore(), env.configs, env.prov, env.xact, &orgs, env.log, env.tracer, nil),
+		variables:           variables,
+		freeze:              provisioning.NewProvisioningFreezeService(kvstore.NewFakeKVStore(), nil, nil, env.log, env.tracer, nil),
 	}
 }
 
@@ -1288,8 +2184,10 @@ func createTestRequestCtx() contextmodel.ReqContext {
 }
 
 type fakeNotificationPolicyService struct {
-	tree definitions.Route
-	prov models.Provenance
+	tree      definitions.Route
+	prov      models.Provenance
+	lastRef   provisioning.RouteRef
+	labelSets []model.LabelSet
 }
 
 func newFakeNotificationPolicyService() *fakeNotificationPolicyService {
@@ -1343,13 +2241,13 @@ func (f *fakeNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID
 	return result, nil
 }
 
-func (f *fakeNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
+func (f *fakeNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) (definitions.PolicyTreeDiff, error) {
 	if orgID != 1 {
-		return store.ErrNoAlertmanagerConfiguration
+		return definitions.PolicyTreeDiff{}, store.ErrNoAlertmanagerConfiguration
 	}
 	f.tree = tree
 	f.prov = p
-	return nil
+	return definitions.PolicyTreeDiff{}, nil
 }
 
 func (f *fakeNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
@@ -1357,34 +2255,102 @@ func (f *fakeNotificationPolicyService) ResetPolicyTree(ctx context.Context, org
 	return f.tree, nil
 }
 
+func (f *fakeNotificationPolicyService) CreateRoute(ctx context.Context, orgID int64, parent provisioning.RouteRef, route definitions.Route, p models.Provenance) (definitions.Route, error) {
+	f.lastRef = parent
+	f.prov = p
+	return route, nil
+}
+
+func (f *fakeNotificationPolicyService) UpdateRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, route definitions.Route, p models.Provenance) (definitions.Route, error) {
+	f.lastRef = ref
+	f.prov = p
+	return route, nil
+}
+
+func (f *fakeNotificationPolicyService) DeleteRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, p models.Provenance) error {
+	f.lastRef = ref
+	return nil
+}
+
+func (f *fakeNotificationPolicyService) TestRoutePolicy(ctx context.Context, orgID int64, labelSets []model.LabelSet) ([]definitions.RoutePolicyTestResult, error) {
+	f.labelSets = labelSets
+	results := make([]definitions.RoutePolicyTestResult, 0, len(labelSets))
+	for _, lset := range labelSets {
+		results = append(results, definitions.RoutePolicyTestResult{Labels: lset, Routes: []definitions.MatchedRoute{{Receiver: f.tree.Receiver}}})
+	}
+	return results, nil
+}
+
 type fakeFailingNotificationPolicyService struct{}
 
 func (f *fakeFailingNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	return definitions.Route{}, fmt.Errorf("something went wrong")
 }
 
-func (f *fakeFailingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
-	return fmt.Errorf("something went wrong")
+func (f *fakeFailingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) (definitions.PolicyTreeDiff, error) {
+	return definitions.PolicyTreeDiff{}, fmt.Errorf("something went wrong")
 }
 
 func (f *fakeFailingNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	return definitions.Route{}, fmt.Errorf("something went wrong")
 }
 
+func (f *fakeFailingNotificationPolicyService) CreateRoute(ctx context.Context, orgID int64, parent provisioning.RouteRef, route definitions.Route, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) UpdateRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, route definitions.Route, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) DeleteRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, p models.Provenance) error {
+	return fmt.Errorf("something went wrong")
+}
+
+func (f *fakeFailingNotificationPolicyService) TestRoutePolicy(ctx context.Context, orgID int64, labelSets []model.LabelSet) ([]definitions.RoutePolicyTestResult, error) {
+	return nil, fmt.Errorf("something went wrong")
+}
+
 type fakeRejectingNotificationPolicyService struct{}
 
 func (f *fakeRejectingNotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	return definitions.Route{}, nil
 }
 
-func (f *fakeRejectingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
-	return fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
+func (f *fakeRejectingNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) (definitions.PolicyTreeDiff, error) {
+	return definitions.PolicyTreeDiff{}, fmt.Errorf("%w: invalid policy tree", provisioning.ErrValidation)
 }
 
 func (f *fakeRejectingNotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
 	return definitions.Route{}, nil
 }
 
+func (f *fakeRejectingNotificationPolicyService) CreateRoute(ctx context.Context, orgID int64, parent provisioning.RouteRef, route definitions.Route, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("%w: invalid route", provisioning.ErrValidation)
+}
+
+func (f *fakeRejectingNotificationPolicyService) UpdateRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, route definitions.Route, p models.Provenance) (definitions.Route, error) {
+	return definitions.Route{}, fmt.Errorf("%w: route %s does not exist", provisioning.ErrNotFound, ref)
+}
+
+func (f *fakeRejectingNotificationPolicyService) DeleteRoute(ctx context.Context, orgID int64, ref provisioning.RouteRef, p models.Provenance) error {
+	return fmt.Errorf("%w: route %s does not exist", provisioning.ErrNotFound, ref)
+}
+
+func (f *fakeRejectingNotificationPolicyService) TestRoutePolicy(ctx context.Context, orgID int64, labelSets []model.LabelSet) ([]definitions.RoutePolicyTestResult, error) {
+	return nil, fmt.Errorf("%w: invalid label set", provisioning.ErrValidation)
+}
+
+type fakeBrokenReferencesNotificationPolicyService struct {
+	fakeRejectingNotificationPolicyService
+}
+
+func (f *fakeBrokenReferencesNotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) (definitions.PolicyTreeDiff, error) {
+	return definitions.PolicyTreeDiff{}, &provisioning.BrokenPolicyReferencesError{References: []definitions.PolicyReference{
+		{Path: []int{0}, UID: "broken", Kind: "receiver", Name: "unknown"},
+	}}
+}
+
 func createInvalidContactPoint() definitions.EmbeddedContactPoint {
 	settings, _ := simplejson.NewJson([]byte(`{}`))
 	return definitions.EmbeddedContactPoint{
@@ -1457,6 +2423,14 @@ func createTestAlertRule(title string, orgID int64) definitions.ProvisionedAlert
 	}
 }
 
+// createUpdatableTestAlertRule creates a test rule that is still valid once it is read back from the store, which keeps
+// the relative time range of the queries in seconds.
+func createUpdatableTestAlertRule(title string, orgID int64) definitions.ProvisionedAlertRule {
+	rule := createTestAlertRule(title, orgID)
+	rule.Data[0].RelativeTimeRange.From = definitions.Duration(time.Minute)
+	return rule
+}
+
 func insertRule(t *testing.T, srv ProvisioningSrv, rule definitions.ProvisionedAlertRule) {
 	insertRuleInOrg(t, srv, rule, 1)
 }
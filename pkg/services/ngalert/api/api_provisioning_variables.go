@@ -0,0 +1,49 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// ProvisioningVariablesService manages the variables of an organization that are substituted in contact points.
+type ProvisioningVariablesService interface {
+	GetVariables(ctx context.Context, orgID int64) ([]definitions.ProvisioningVariable, error)
+	SetVariable(ctx context.Context, orgID int64, v definitions.ProvisioningVariable) (definitions.ProvisioningVariable, error)
+	DeleteVariable(ctx context.Context, orgID int64, name string) error
+}
+
+func (srv *ProvisioningSrv) RouteGetProvisioningVariables(c *contextmodel.ReqContext) response.Response {
+	variables, err := srv.variables.GetVariables(c.Req.Context(), c.OrgID)
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusOK, variables)
+}
+
+func (srv *ProvisioningSrv) RoutePutProvisioningVariable(c *contextmodel.ReqContext, body definitions.ProvisioningVariableContent, name string) response.Response {
+	v, err := srv.variables.SetVariable(c.Req.Context(), c.OrgID, definitions.ProvisioningVariable{Name: name, Value: body.Value})
+	if errors.Is(err, provisioning.ErrValidation) {
+		return provisioningErrResp(http.StatusBadRequest, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusAccepted, v)
+}
+
+func (srv *ProvisioningSrv) RouteDeleteProvisioningVariable(c *contextmodel.ReqContext, name string) response.Response {
+	err := srv.variables.DeleteVariable(c.Req.Context(), c.OrgID, name)
+	if errors.Is(err, provisioning.ErrNotFound) {
+		return provisioningErrResp(http.StatusNotFound, err, "")
+	}
+	if err != nil {
+		return provisioningErrResp(http.StatusInternalServerError, err, "")
+	}
+	return response.JSON(http.StatusNoContent, nil)
+}
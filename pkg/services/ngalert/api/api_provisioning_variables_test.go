@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestRouteProvisioningVariables(t *testing.T) {
+	t.Run("successful PUT returns 202 and the variable is listed", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutProvisioningVariable(&rc, definitions.ProvisioningVariableContent{Value: "#staging"}, "SLACK_CHANNEL_PREFIX")
+
+		require.Equal(t, 202, response.Status())
+		response = sut.RouteGetProvisioningVariables(&rc)
+		require.Equal(t, 200, response.Status())
+		var variables []definitions.ProvisioningVariable
+		require.NoError(t, json.Unmarshal(response.Body(), &variables))
+		require.Equal(t, []definitions.ProvisioningVariable{{Name: "SLACK_CHANNEL_PREFIX", Value: "#staging"}}, variables)
+	})
+
+	t.Run("invalid name returns 400", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RoutePutProvisioningVariable(&rc, definitions.ProvisioningVariableContent{Value: "#staging"}, "slack-channel")
+
+		require.Equal(t, 400, response.Status())
+	})
+
+	t.Run("unknown variable returns 404", func(t *testing.T) {
+		sut := createProvisioningSrvSut(t)
+		rc := createTestRequestCtx()
+
+		response := sut.RouteDeleteProvisioningVariable(&rc, "unknown")
+
+		require.Equal(t, 404, response.Status())
+	})
+}
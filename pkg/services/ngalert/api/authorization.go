@@ -8,9 +8,11 @@ import (
 	"github.com/grafana/grafana/pkg/expr"
 	"github.com/grafana/grafana/pkg/middleware"
 	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/datasources"
 	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/web"
 )
@@ -180,50 +182,204 @@ func (api *API) authorize(method, path string) web.Handler {
 	case http.MethodDelete + "/api/v1/ngalert/admin_config",
 		http.MethodGet + "/api/v1/ngalert/admin_config",
 		http.MethodPost + "/api/v1/ngalert/admin_config",
-		http.MethodGet + "/api/v1/ngalert/alertmanagers":
+		http.MethodGet + "/api/v1/ngalert/alertmanagers",
+		http.MethodGet + "/api/v1/provisioning/effective-config",
+		http.MethodGet + "/api/v1/provisioning/snapshots",
+		http.MethodPost + "/api/v1/provisioning/snapshots/restore",
+		http.MethodPost + "/api/v1/provisioning/alertmanager/import",
+		http.MethodGet + "/api/v1/provisioning/alertmanager/versions",
+		http.MethodPost + "/api/v1/provisioning/alertmanager/versions/{ID}/rollback":
+		if _, ok := frozenProvisioningRoutes[method+path]; ok {
+			return api.checkProvisioningFreeze(middleware.ReqOrgAdmin)
+		}
 		return middleware.ReqOrgAdmin
 
+	// Grafana-only Provisioning Paths spanning all organizations
+	case http.MethodGet + "/api/v1/provisioning/all-orgs/export",
+		http.MethodGet + "/api/v1/provisioning/global/contact-points",
+		http.MethodPost + "/api/v1/provisioning/global/contact-points",
+		http.MethodPut + "/api/v1/provisioning/global/contact-points/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/global/contact-points/{UID}",
+		http.MethodGet + "/api/v1/provisioning/global/templates",
+		http.MethodPut + "/api/v1/provisioning/global/templates/{name}",
+		http.MethodDelete + "/api/v1/provisioning/global/templates/{name}",
+		http.MethodGet + "/api/v1/provisioning/global/bootstrap-bundle",
+		http.MethodPut + "/api/v1/provisioning/global/bootstrap-bundle",
+		http.MethodDelete + "/api/v1/provisioning/global/bootstrap-bundle",
+		http.MethodPost + "/api/v1/provisioning/global/copy",
+		http.MethodPost + "/api/v1/provisioning/provenance/cleanup":
+		return middleware.ReqGrafanaAdmin
+
+	// Grafana-only Provisioning Paths of contact points, which can also be accessed with the permissions on receivers.
+	// The service only returns and changes the contact points that the user has permissions on.
+	case http.MethodGet + "/api/v1/provisioning/contact-points",
+		http.MethodGet + "/api/v1/provisioning/contact-points/export":
+		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingProvisioningRead), ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets), ac.EvalPermission(ac.ActionAlertingReceiversRead)) // organization or receiver scope
+	case http.MethodPut + "/api/v1/provisioning/contact-points/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}":
+		eval = ac.EvalAny(provisioning.EvalOrgProvisioningWrite(), ac.EvalPermission(ac.ActionAlertingReceiversWrite)) // organization or receiver scope
+
 	// Grafana-only Provisioning Read Paths
 	case http.MethodGet + "/api/v1/provisioning/policies",
 		http.MethodGet + "/api/v1/provisioning/policies/export",
-		http.MethodGet + "/api/v1/provisioning/contact-points",
-		http.MethodGet + "/api/v1/provisioning/contact-points/export",
+		http.MethodPost + "/api/v1/provisioning/policies/test",
+		http.MethodGet + "/api/v1/provisioning/contact-points/deleted",
+		http.MethodGet + "/api/v1/provisioning/contact-points/status",
 		http.MethodGet + "/api/v1/provisioning/templates",
 		http.MethodGet + "/api/v1/provisioning/templates/{name}",
+		http.MethodGet + "/api/v1/provisioning/templates/export",
+		http.MethodPost + "/api/v1/provisioning/templates/preview",
 		http.MethodGet + "/api/v1/provisioning/mute-timings",
+		http.MethodGet + "/api/v1/provisioning/mute-timings/export",
 		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}",
+		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}/usage",
+		http.MethodGet + "/api/v1/provisioning/mute-timings/{name}/preview",
+		http.MethodGet + "/api/v1/provisioning/maintenance-windows",
+		http.MethodGet + "/api/v1/provisioning/silences",
+		http.MethodGet + "/api/v1/provisioning/silences/{UID}",
+		http.MethodGet + "/api/v1/provisioning/variables",
+		http.MethodGet + "/api/v1/provisioning/freeze",
 		http.MethodGet + "/api/v1/provisioning/alert-rules",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/export",
 		http.MethodGet + "/api/v1/provisioning/alert-rules/{UID}/export",
+		http.MethodGet + "/api/v1/provisioning/alert-rules/orphaned-links",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/lint",
 		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
-		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export":
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation",
+		http.MethodGet + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/load",
+		http.MethodGet + "/api/v1/provisioning/audit",
+		http.MethodGet + "/api/v1/provisioning/history",
+		http.MethodGet + "/api/v1/provisioning/provenance",
+		http.MethodGet + "/api/v1/provisioning/bundle/scheduled",
+		http.MethodGet + "/api/v1/provisioning/health":
 		eval = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingProvisioningRead), ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets)) // organization scope
 
 	case http.MethodPut + "/api/v1/provisioning/policies",
 		http.MethodDelete + "/api/v1/provisioning/policies",
+		http.MethodPost + "/api/v1/provisioning/policies/routes",
+		http.MethodPut + "/api/v1/provisioning/policies/routes/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/policies/routes/{UID}",
 		http.MethodPost + "/api/v1/provisioning/contact-points",
-		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}",
-		http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}",
+		http.MethodPost + "/api/v1/provisioning/contact-points/batch",
+		http.MethodPost + "/api/v1/provisioning/contact-points/test",
+		http.MethodPut + "/api/v1/provisioning/contact-points/{UID}/secrets",
+		http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/migrate",
+		http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/restore",
+		http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/enable",
+		http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/disable",
 		http.MethodPut + "/api/v1/provisioning/templates/{name}",
 		http.MethodDelete + "/api/v1/provisioning/templates/{name}",
 		http.MethodPost + "/api/v1/provisioning/mute-timings",
 		http.MethodPut + "/api/v1/provisioning/mute-timings/{name}",
 		http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}",
-		http.MethodPost + "/api/v1/provisioning/alert-rules",
+		http.MethodPost + "/api/v1/provisioning/maintenance-windows",
+		http.MethodDelete + "/api/v1/provisioning/maintenance-windows/{name}",
+		http.MethodPost + "/api/v1/provisioning/silences",
+		http.MethodPut + "/api/v1/provisioning/silences/{UID}",
+		http.MethodDelete + "/api/v1/provisioning/silences/{UID}",
+		http.MethodPut + "/api/v1/provisioning/variables/{name}",
+		http.MethodDelete + "/api/v1/provisioning/variables/{name}",
+		http.MethodPut + "/api/v1/provisioning/freeze",
+		http.MethodPost + "/api/v1/provisioning/bundle",
+		http.MethodPost + "/api/v1/provisioning/bundle/diff",
+		http.MethodPost + "/api/v1/provisioning/bundle/import",
+		http.MethodPost + "/api/v1/provisioning/bundle/scheduled",
+		http.MethodDelete + "/api/v1/provisioning/bundle/scheduled/{UID}":
+		eval = provisioning.EvalOrgProvisioningWrite() // organization scope
+
+	// The export of the full provisioning state contains the secrets of the contact points.
+	case http.MethodPost + "/api/v1/provisioning/bundle/export":
+		eval = ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets) // organization scope
+
+	// Grafana-only Provisioning Paths of alert rules, which can also be accessed with the provisioning permission scoped
+	// to folders. The service only changes the rules of the folders that the user has permissions on.
+	case http.MethodPost + "/api/v1/provisioning/alert-rules",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/import",
+		http.MethodPost + "/api/v1/provisioning/alert-rules/test",
 		http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}",
+		http.MethodPatch + "/api/v1/provisioning/alert-rules/{UID}",
 		http.MethodDelete + "/api/v1/provisioning/alert-rules/{UID}",
-		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":
-		eval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite) // organization scope
+		http.MethodDelete + "/api/v1/provisioning/alert-rules/orphaned-links",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause",
+		http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation":
+		eval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite) // organization or folder scope
 	}
 
 	if eval != nil {
+		if _, ok := frozenProvisioningRoutes[method+path]; ok {
+			return api.checkProvisioningFreeze(authorize(eval))
+		}
 		return authorize(eval)
 	}
 
 	panic(fmt.Sprintf("no authorization handler for method [%s] of endpoint [%s]", method, path))
 }
 
+// frozenProvisioningRoutes are the provisioning routes that change the provisioned resources of an organization, and
+// are rejected while its provisioning is frozen.
+var frozenProvisioningRoutes = map[string]struct{}{
+	http.MethodPost + "/api/v1/provisioning/snapshots/restore":                                {},
+	http.MethodPost + "/api/v1/provisioning/alertmanager/import":                              {},
+	http.MethodPost + "/api/v1/provisioning/alertmanager/versions/{ID}/rollback":              {},
+	http.MethodPut + "/api/v1/provisioning/policies":                                          {},
+	http.MethodDelete + "/api/v1/provisioning/policies":                                       {},
+	http.MethodPost + "/api/v1/provisioning/policies/routes":                                  {},
+	http.MethodPut + "/api/v1/provisioning/policies/routes/{UID}":                             {},
+	http.MethodDelete + "/api/v1/provisioning/policies/routes/{UID}":                          {},
+	http.MethodPost + "/api/v1/provisioning/contact-points":                                   {},
+	http.MethodPost + "/api/v1/provisioning/contact-points/batch":                             {},
+	http.MethodPut + "/api/v1/provisioning/contact-points/{UID}":                              {},
+	http.MethodDelete + "/api/v1/provisioning/contact-points/{UID}":                           {},
+	http.MethodPut + "/api/v1/provisioning/contact-points/{UID}/secrets":                      {},
+	http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/migrate":                     {},
+	http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/restore":                     {},
+	http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/enable":                      {},
+	http.MethodPost + "/api/v1/provisioning/contact-points/{UID}/disable":                     {},
+	http.MethodPut + "/api/v1/provisioning/templates/{name}":                                  {},
+	http.MethodDelete + "/api/v1/provisioning/templates/{name}":                               {},
+	http.MethodPost + "/api/v1/provisioning/mute-timings":                                     {},
+	http.MethodPut + "/api/v1/provisioning/mute-timings/{name}":                               {},
+	http.MethodDelete + "/api/v1/provisioning/mute-timings/{name}":                            {},
+	http.MethodPost + "/api/v1/provisioning/maintenance-windows":                              {},
+	http.MethodDelete + "/api/v1/provisioning/maintenance-windows/{name}":                     {},
+	http.MethodPost + "/api/v1/provisioning/silences":                                         {},
+	http.MethodPut + "/api/v1/provisioning/silences/{UID}":                                    {},
+	http.MethodDelete + "/api/v1/provisioning/silences/{UID}":                                 {},
+	http.MethodPut + "/api/v1/provisioning/variables/{name}":                                  {},
+	http.MethodDelete + "/api/v1/provisioning/variables/{name}":                               {},
+	http.MethodPost + "/api/v1/provisioning/bundle":                                           {},
+	http.MethodPost + "/api/v1/provisioning/bundle/import":                                    {},
+	http.MethodPost + "/api/v1/provisioning/bundle/scheduled":                                 {},
+	http.MethodPost + "/api/v1/provisioning/alert-rules":                                      {},
+	http.MethodPost + "/api/v1/provisioning/alert-rules/import":                               {},
+	http.MethodPut + "/api/v1/provisioning/alert-rules/{UID}":                                 {},
+	http.MethodPatch + "/api/v1/provisioning/alert-rules/{UID}":                               {},
+	http.MethodDelete + "/api/v1/provisioning/alert-rules/{UID}":                              {},
+	http.MethodDelete + "/api/v1/provisioning/alert-rules/orphaned-links":                     {},
+	http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}":            {},
+	http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause":      {},
+	http.MethodPut + "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation": {},
+}
+
+// checkProvisioningFreeze returns a handler that authorizes the request with the given handler, and then rejects it
+// with status 423 if the provisioning of the organization is frozen and the user is not allowed to write during the
+// freeze.
+func (api *API) checkProvisioningFreeze(authorize web.Handler) web.Handler {
+	next := authorize.(func(c *contextmodel.ReqContext))
+	return func(c *contextmodel.ReqContext) {
+		next(c)
+		if c.Resp.Written() || api.ProvisioningFreeze == nil {
+			return
+		}
+		if err := api.ProvisioningFreeze.CheckWrite(c.Req.Context(), c.OrgID, c.SignedInUser); err != nil {
+			provisioningErrResp(http.StatusInternalServerError, err, "").WriteTo(c)
+		}
+	}
+}
+
 // authorizeDatasourceAccessForRule checks that user has access to all data sources declared by the rule
 func authorizeDatasourceAccessForRule(rule *ngmodels.AlertRule, evaluator func(evaluator ac.Evaluator) bool) bool {
 	for _, query := range rule.Data {
@@ -49,7 +49,7 @@ func TestAuthorize(t *testing.T) {
 		}
 		paths[p] = methods
 	}
-	require.Len(t, paths, 50)
+	require.Len(t, paths, 105)
 
 	ac := acmock.New()
 	api := &API{AccessControl: ac}
@@ -69,6 +69,18 @@ func TestAuthorize(t *testing.T) {
 			api.authorize("test", "test")
 		})
 	})
+
+	t.Run("routes rejected during a provisioning freeze are known", func(t *testing.T) {
+		known := make(map[string]struct{})
+		for path, methods := range paths {
+			for _, method := range methods {
+				known[method+path] = struct{}{}
+			}
+		}
+		for route := range frozenProvisioningRoutes {
+			require.Contains(t, known, route)
+		}
+	})
 }
 
 func createAllCombinationsOfPermissions(permissions map[string][]string) []map[string][]string {
@@ -8,11 +8,12 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
 // AlertRuleFromProvisionedAlertRule converts definitions.ProvisionedAlertRule to models.AlertRule
 func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (models.AlertRule, error) {
-	return models.AlertRule{
+	rule := models.AlertRule{
 		ID:           a.ID,
 		UID:          a.UID,
 		OrgID:        a.OrgID,
@@ -28,7 +29,34 @@ func AlertRuleFromProvisionedAlertRule(a definitions.ProvisionedAlertRule) (mode
 		Annotations:  a.Annotations,
 		Labels:       a.Labels,
 		IsPaused:     a.IsPaused,
-	}, nil
+	}
+	if a.NotificationSettings != nil {
+		settings := NotificationSettingsFromApi(*a.NotificationSettings)
+		if err := settings.Validate(); err != nil {
+			return models.AlertRule{}, err
+		}
+		rule.NotificationSettings = []models.NotificationSettings{settings}
+	}
+	return rule, nil
+}
+
+// NotificationSettingsFromApi converts definitions.AlertRuleNotificationSettings to models.NotificationSettings.
+func NotificationSettingsFromApi(s definitions.AlertRuleNotificationSettings) models.NotificationSettings {
+	return models.NotificationSettings{
+		Receiver:          s.Receiver,
+		MuteTimeIntervals: s.MuteTimeIntervals,
+	}
+}
+
+// ApiNotificationSettingsFromAlertRule returns the notification settings of the rule, or nil if it has none.
+func ApiNotificationSettingsFromAlertRule(rule models.AlertRule) *definitions.AlertRuleNotificationSettings {
+	if len(rule.NotificationSettings) == 0 {
+		return nil
+	}
+	return &definitions.AlertRuleNotificationSettings{
+		Receiver:          rule.NotificationSettings[0].Receiver,
+		MuteTimeIntervals: rule.NotificationSettings[0].MuteTimeIntervals,
+	}
 }
 
 // ProvisionedAlertRuleFromAlertRule converts models.AlertRule to definitions.ProvisionedAlertRule and sets provided provenance status
@@ -50,14 +78,97 @@ func ProvisionedAlertRuleFromAlertRule(rule models.AlertRule, provenance models.
 		Labels:       rule.Labels,
 		Provenance:   definitions.Provenance(provenance), // TODO validate enum conversion?
 		IsPaused:     rule.IsPaused,
+
+		NotificationSettings: ApiNotificationSettingsFromAlertRule(rule),
 	}
 }
 
-// ProvisionedAlertRuleFromAlertRules converts a collection of models.AlertRule to definitions.ProvisionedAlertRules with provenance status models.ProvenanceNone
-func ProvisionedAlertRuleFromAlertRules(rules []*models.AlertRule) definitions.ProvisionedAlertRules {
+// ProvisionedAlertRuleFromAlertRules converts a collection of models.AlertRule to definitions.ProvisionedAlertRules.
+// Rules without an entry in provenances get provenance status models.ProvenanceNone.
+func ProvisionedAlertRuleFromAlertRules(rules []*models.AlertRule, provenances map[string]models.Provenance) definitions.ProvisionedAlertRules {
 	result := make([]definitions.ProvisionedAlertRule, 0, len(rules))
 	for _, r := range rules {
-		result = append(result, ProvisionedAlertRuleFromAlertRule(*r, models.ProvenanceNone))
+		provenance := models.ProvenanceNone
+		if p, ok := provenances[r.UID]; ok {
+			provenance = p
+		}
+		result = append(result, ProvisionedAlertRuleFromAlertRule(*r, provenance))
+	}
+	return result
+}
+
+// AlertRulePatchFromApiAlertRulePatch converts definitions.AlertRulePatch to provisioning.AlertRulePatch
+func AlertRulePatchFromApiAlertRulePatch(p definitions.AlertRulePatch) provisioning.AlertRulePatch {
+	patch := provisioning.AlertRulePatch{
+		Title:       p.Title,
+		Condition:   p.Condition,
+		Annotations: p.Annotations,
+		Labels:      p.Labels,
+		IsPaused:    p.IsPaused,
+	}
+	if p.Data != nil {
+		patch.Data = AlertQueriesFromApiAlertQueries(p.Data)
+	}
+	if p.NoDataState != nil {
+		s := models.NoDataState(*p.NoDataState)
+		patch.NoDataState = &s
+	}
+	if p.ExecErrState != nil {
+		s := models.ExecutionErrorState(*p.ExecErrState)
+		patch.ExecErrState = &s
+	}
+	if p.For != nil {
+		d := time.Duration(*p.For)
+		patch.For = &d
+	}
+	return patch
+}
+
+// OrphanedRuleLinksToApi converts a collection of provisioning.OrphanedRuleLink to definitions.OrphanedRuleLinks
+func OrphanedRuleLinksToApi(links []provisioning.OrphanedRuleLink) definitions.OrphanedRuleLinks {
+	result := make(definitions.OrphanedRuleLinks, 0, len(links))
+	for _, l := range links {
+		result = append(result, definitions.OrphanedRuleLink{
+			RuleUID:          l.RuleUID,
+			Title:            l.Title,
+			FolderUID:        l.FolderUID,
+			DashboardUID:     l.DashboardUID,
+			PanelID:          l.PanelID,
+			DashboardMissing: l.DashboardMissing,
+		})
+	}
+	return result
+}
+
+// AlertRuleLintResultToApi converts a collection of provisioning.AlertRuleLintFinding to definitions.AlertRuleLintResult
+func AlertRuleLintResultToApi(findings []provisioning.AlertRuleLintFinding) definitions.AlertRuleLintResult {
+	result := definitions.AlertRuleLintResult{
+		Findings: make([]definitions.AlertRuleLintFinding, 0, len(findings)),
+	}
+	for _, f := range findings {
+		result.Findings = append(result.Findings, definitions.AlertRuleLintFinding{
+			Code:     f.Code,
+			Severity: string(f.Severity),
+			Message:  f.Message,
+		})
+	}
+	return result
+}
+
+// AlertRuleTestResultToApi converts a collection of provisioning.AlertRuleTestInstance to definitions.AlertRuleTestResult
+func AlertRuleTestResultToApi(instances []provisioning.AlertRuleTestInstance) definitions.AlertRuleTestResult {
+	result := definitions.AlertRuleTestResult{
+		Instances: make([]definitions.AlertRuleTestInstance, 0, len(instances)),
+	}
+	for _, i := range instances {
+		result.Instances = append(result.Instances, definitions.AlertRuleTestInstance{
+			Labels:      i.Labels,
+			Annotations: i.Annotations,
+			State:       i.State,
+			StateReason: i.StateReason,
+			Values:      i.Values,
+			Error:       i.Error,
+		})
 	}
 	return result
 }
@@ -120,10 +231,11 @@ func ApiAlertRuleGroupFromAlertRuleGroup(d models.AlertRuleGroup) definitions.Al
 		rules = append(rules, ProvisionedAlertRuleFromAlertRule(d.Rules[i], d.Provenance))
 	}
 	return definitions.AlertRuleGroup{
-		Title:     d.Title,
-		FolderUID: d.FolderUID,
-		Interval:  d.Interval,
-		Rules:     rules,
+		Title:            d.Title,
+		FolderUID:        d.FolderUID,
+		Interval:         d.Interval,
+		Rules:            rules,
+		EvaluationOffset: d.EvaluationOffset,
 	}
 }
 
@@ -151,11 +263,12 @@ func AlertRuleGroupExportFromAlertRuleGroupWithFolderTitle(d models.AlertRuleGro
 		rules = append(rules, alert)
 	}
 	return definitions.AlertRuleGroupExport{
-		OrgID:    d.OrgID,
-		Name:     d.Title,
-		Folder:   d.FolderTitle,
-		Interval: model.Duration(time.Duration(d.Interval) * time.Second),
-		Rules:    rules,
+		OrgID:     d.OrgID,
+		Name:      d.Title,
+		Folder:    d.FolderTitle,
+		FolderUID: d.FolderUID,
+		Interval:  model.Duration(time.Duration(d.Interval) * time.Second),
+		Rules:     rules,
 	}, nil
 }
 
@@ -193,6 +306,8 @@ func AlertRuleExportFromAlertRule(rule models.AlertRule) (definitions.AlertRuleE
 		Annotations:  rule.Annotations,
 		Labels:       rule.Labels,
 		IsPaused:     rule.IsPaused,
+
+		NotificationSettings: ApiNotificationSettingsFromAlertRule(rule),
 	}, nil
 }
 
@@ -273,6 +388,31 @@ func AlertingFileExportFromRoute(orgID int64, route definitions.Route) (definiti
 	return f, nil
 }
 
+// AlertingFileExportFromMuteTimings creates a definitions.AlertingFileExport DTO from []definitions.MuteTimeInterval.
+func AlertingFileExportFromMuteTimings(orgID int64, muteTimings []definitions.MuteTimeInterval) definitions.AlertingFileExport {
+	f := definitions.AlertingFileExport{APIVersion: 1}
+	for _, mt := range muteTimings {
+		f.MuteTimings = append(f.MuteTimings, definitions.MuteTimeIntervalExport{
+			OrgID:            orgID,
+			MuteTimeInterval: mt.MuteTimeInterval,
+		})
+	}
+	return f
+}
+
+// AlertingFileExportFromTemplates creates a definitions.AlertingFileExport DTO from []definitions.NotificationTemplate.
+func AlertingFileExportFromTemplates(orgID int64, templates []definitions.NotificationTemplate) definitions.AlertingFileExport {
+	f := definitions.AlertingFileExport{APIVersion: 1}
+	for _, t := range templates {
+		f.Templates = append(f.Templates, definitions.NotificationTemplateExport{
+			OrgID:    orgID,
+			Name:     t.Name,
+			Template: t.Template,
+		})
+	}
+	return f
+}
+
 // RouteExportFromRoute creates a definitions.RouteExport DTO from definitions.Route.
 func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 	export := definitions.RouteExport{
@@ -298,3 +438,80 @@ func RouteExportFromRoute(route *definitions.Route) *definitions.RouteExport {
 
 	return &export
 }
+
+// ProvisioningAuditEntryToApi converts models.ProvisioningAuditEntry to definitions.ProvisioningAuditEntry.
+func ProvisioningAuditEntryToApi(e models.ProvisioningAuditEntry) definitions.ProvisioningAuditEntry {
+	result := definitions.ProvisioningAuditEntry{
+		ID:            e.ID,
+		OrgID:         e.OrgID,
+		ActorID:       e.ActorID,
+		ActorLogin:    e.ActorLogin,
+		Action:        string(e.Action),
+		ResourceType:  e.ResourceType,
+		ResourceID:    e.ResourceID,
+		Provenance:    definitions.Provenance(e.Provenance),
+		Source:        e.Source,
+		CorrelationID: e.CorrelationID,
+		Created:       time.Unix(e.Created, 0).UTC(),
+	}
+	if e.OldState != "" {
+		result.OldState = definitions.RawMessage(e.OldState)
+	}
+	if e.NewState != "" {
+		result.NewState = definitions.RawMessage(e.NewState)
+	}
+	return result
+}
+
+func ConfigHealthToApi(h provisioning.ConfigHealth) definitions.ProvisioningHealth {
+	result := definitions.ProvisioningHealth{
+		ConfigSize:       h.ConfigSize,
+		InvalidReceivers: make([]definitions.InvalidReceiver, 0, len(h.InvalidReceivers)),
+	}
+	if !h.LastConfigSave.IsZero() {
+		lastSave := h.LastConfigSave
+		result.LastConfigSave = &lastSave
+	}
+	if h.FileProvisioning != nil {
+		status := &definitions.FileProvisioningStatus{
+			LastAttempt: h.FileProvisioning.LastAttempt,
+			Error:       h.FileProvisioning.Error,
+			FileErrors:  h.FileProvisioning.FileErrors,
+		}
+		if !h.FileProvisioning.LastSuccess.IsZero() {
+			lastSuccess := h.FileProvisioning.LastSuccess
+			status.LastSuccess = &lastSuccess
+		}
+		result.FileProvisioning = status
+	}
+	for _, r := range h.InvalidReceivers {
+		result.InvalidReceivers = append(result.InvalidReceivers, definitions.InvalidReceiver{
+			UID:   r.UID,
+			Name:  r.Name,
+			Type:  r.Type,
+			Error: r.Error,
+		})
+	}
+	return result
+}
+
+func RuleGroupLoadToApi(l provisioning.RuleGroupLoad) definitions.AlertRuleGroupLoad {
+	result := definitions.AlertRuleGroupLoad{
+		Rules:              l.Rules,
+		PausedRules:        l.PausedRules,
+		Queries:            l.Queries,
+		DatasourceUIDs:     l.DatasourceUIDs,
+		EvaluationsPerHour: l.EvaluationsPerHour,
+		QueriesPerHour:     l.QueriesPerHour,
+	}
+	if d := l.EvaluationDuration; d != nil {
+		result.EvaluationDuration = &definitions.EvaluationDurationPercentiles{
+			Samples: d.Samples,
+			P50:     d.P50.Seconds(),
+			P90:     d.P90.Seconds(),
+			P99:     d.P99.Seconds(),
+			Max:     d.Max.Seconds(),
+		}
+	}
+	return result
+}
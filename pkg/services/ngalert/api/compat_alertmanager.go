@@ -0,0 +1,283 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	exportTargetGrafana      = "grafana"
+	exportTargetAlertmanager = "alertmanager"
+
+	defaultSlackAPIURL     = "https://slack.com/api/chat.postMessage"
+	defaultVictorOpsAPIURL = "https://alert.victorops.com/integrations/generic/20131114/alert/"
+)
+
+// alertmanagerIntegration converts a Grafana integration to the configuration of the equivalent integration of the
+// Prometheus Alertmanager.
+type alertmanagerIntegration struct {
+	// configs returns the configurations of the receiver the integration is added to.
+	configs func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any
+	// convert returns the configuration of the equivalent integration, or false if the settings have none.
+	convert func(settings *simplejson.Json) (map[string]any, bool)
+}
+
+var alertmanagerIntegrations = map[string]alertmanagerIntegration{
+	"discord": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.DiscordConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{"webhook_url": settings.Get("url").MustString()}
+			setStrings(cfg, settings, map[string]string{"title": "title", "message": "message"})
+			return cfg, true
+		},
+	},
+	"email": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.EmailConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			addresses := util.SplitEmails(settings.Get("addresses").MustString())
+			if len(addresses) == 0 {
+				return nil, false
+			}
+			cfg := map[string]any{"to": strings.Join(addresses, ", ")}
+			if subject := settings.Get("subject").MustString(); subject != "" {
+				cfg["headers"] = map[string]string{"Subject": subject}
+			}
+			return cfg, true
+		},
+	},
+	"pagerduty": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.PagerdutyConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{"routing_key": settings.Get("integrationKey").MustString()}
+			setStrings(cfg, settings, map[string]string{
+				"severity":   "severity",
+				"class":      "class",
+				"component":  "component",
+				"group":      "group",
+				"summary":    "description",
+				"source":     "source",
+				"client":     "client",
+				"client_url": "client_url",
+			})
+			if details := settings.Get("details").MustMap(); len(details) > 0 {
+				cfg["details"] = details
+			}
+			return cfg, true
+		},
+	},
+	"slack": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.SlackConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{}
+			if url := settings.Get("url").MustString(); url != "" {
+				cfg["api_url"] = url
+			} else {
+				// Slack apps post messages with a bearer token.
+				cfg["api_url"] = settings.Get("endpointUrl").MustString(defaultSlackAPIURL)
+				cfg["http_config"] = map[string]any{
+					"authorization": map[string]any{"credentials": settings.Get("token").MustString()},
+				}
+			}
+			setStrings(cfg, settings, map[string]string{
+				"recipient":  "channel",
+				"username":   "username",
+				"icon_emoji": "icon_emoji",
+				"icon_url":   "icon_url",
+				"title":      "title",
+				"text":       "text",
+			})
+			return cfg, true
+		},
+	},
+	"webhook": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.WebhookConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			// Webhooks of the Prometheus Alertmanager always use POST.
+			if method := settings.Get("httpMethod").MustString("POST"); !strings.EqualFold(method, "POST") {
+				return nil, false
+			}
+			cfg := map[string]any{"url": settings.Get("url").MustString()}
+			if maxAlerts, ok := numberSetting(settings, "maxAlerts"); ok && maxAlerts > 0 {
+				cfg["max_alerts"] = maxAlerts
+			}
+			if username := settings.Get("username").MustString(); username != "" {
+				cfg["http_config"] = map[string]any{
+					"basic_auth": map[string]any{"username": username, "password": settings.Get("password").MustString()},
+				}
+			} else if credentials := settings.Get("authorization_credentials").MustString(); credentials != "" {
+				cfg["http_config"] = map[string]any{
+					"authorization": map[string]any{
+						"type":        settings.Get("authorization_scheme").MustString("Bearer"),
+						"credentials": credentials,
+					},
+				}
+			}
+			return cfg, true
+		},
+	},
+	"opsgenie": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.OpsGenieConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{"api_key": settings.Get("apiKey").MustString()}
+			// The URL of the Prometheus Alertmanager does not include the path of the API.
+			if url := settings.Get("apiUrl").MustString(); url != "" {
+				cfg["api_url"] = strings.TrimSuffix(strings.TrimSuffix(url, "/"), "v2/alerts")
+			}
+			setStrings(cfg, settings, map[string]string{"message": "message", "description": "description"})
+			return cfg, true
+		},
+	},
+	"pushover": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.PushoverConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{
+				"user_key": settings.Get("userKey").MustString(),
+				"token":    settings.Get("apiToken").MustString(),
+			}
+			setStrings(cfg, settings, map[string]string{
+				"title":   "title",
+				"message": "message",
+				"device":  "device",
+				"sound":   "sound",
+			})
+			if priority, ok := numberSetting(settings, "priority"); ok {
+				cfg["priority"] = strconv.FormatInt(priority, 10)
+			}
+			// Grafana configures the retry and expiry in seconds.
+			if retry, ok := numberSetting(settings, "retry"); ok && retry > 0 {
+				cfg["retry"] = fmt.Sprintf("%ds", retry)
+			}
+			if expire, ok := numberSetting(settings, "expire"); ok && expire > 0 {
+				cfg["expire"] = fmt.Sprintf("%ds", expire)
+			}
+			return cfg, true
+		},
+	},
+	"victorops": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.VictorOpsConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			// Grafana configures the REST endpoint including the API key and the routing key, which the Prometheus
+			// Alertmanager configures separately.
+			cfg := map[string]any{}
+			url := settings.Get("url").MustString()
+			if url == definitions.RedactedValue {
+				cfg["api_key"] = definitions.RedactedValue
+				cfg["routing_key"] = definitions.RedactedValue
+			} else {
+				i := strings.LastIndex(strings.TrimSuffix(url, "/"), "/alert/")
+				if i < 0 {
+					return nil, false
+				}
+				keys := strings.Split(strings.Trim(url[i+len("/alert/"):], "/"), "/")
+				if len(keys) != 2 {
+					return nil, false
+				}
+				cfg["api_url"] = url[:i+len("/alert/")]
+				cfg["api_key"] = keys[0]
+				cfg["routing_key"] = keys[1]
+			}
+			setStrings(cfg, settings, map[string]string{
+				"messageType": "message_type",
+				"title":       "entity_display_name",
+				"description": "state_message",
+			})
+			return cfg, true
+		},
+	},
+	"telegram": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.TelegramConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			// The Prometheus Alertmanager only supports numeric chat IDs.
+			chatID, ok := numberSetting(settings, "chatid")
+			if !ok {
+				return nil, false
+			}
+			cfg := map[string]any{
+				"bot_token": settings.Get("bottoken").MustString(),
+				"chat_id":   chatID,
+			}
+			setStrings(cfg, settings, map[string]string{"message": "message", "parse_mode": "parse_mode"})
+			if settings.Get("disable_notifications").MustBool() {
+				cfg["disable_notifications"] = true
+			}
+			return cfg, true
+		},
+	},
+	"webex": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.WebexConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{
+				"room_id": settings.Get("room_id").MustString(),
+				"http_config": map[string]any{
+					"authorization": map[string]any{"credentials": settings.Get("bot_token").MustString()},
+				},
+			}
+			setStrings(cfg, settings, map[string]string{"api_url": "api_url", "message": "message"})
+			return cfg, true
+		},
+	},
+	"teams": {
+		configs: func(r *definitions.AlertmanagerReceiverExport) *[]map[string]any { return &r.MSTeamsConfigs },
+		convert: func(settings *simplejson.Json) (map[string]any, bool) {
+			cfg := map[string]any{"webhook_url": settings.Get("url").MustString()}
+			setStrings(cfg, settings, map[string]string{"title": "title", "message": "text"})
+			return cfg, true
+		},
+	},
+}
+
+// AlertmanagerReceiversExportFromEmbeddedContactPoints creates a definitions.AlertmanagerReceiversExport DTO from
+// definitions.EmbeddedContactPoint. Integrations without an equivalent in the Prometheus Alertmanager are left out.
+// Receivers of contact points without any exported integration only have a name, so that routes that use them stay
+// valid.
+func AlertmanagerReceiversExportFromEmbeddedContactPoints(ecps []definitions.EmbeddedContactPoint) definitions.AlertmanagerReceiversExport {
+	export := definitions.AlertmanagerReceiversExport{Receivers: make([]definitions.AlertmanagerReceiverExport, 0)}
+	index := make(map[string]int)
+	for _, ecp := range ecps {
+		i, ok := index[ecp.Name]
+		if !ok {
+			i = len(export.Receivers)
+			index[ecp.Name] = i
+			export.Receivers = append(export.Receivers, definitions.AlertmanagerReceiverExport{Name: ecp.Name})
+		}
+		integration, ok := alertmanagerIntegrations[ecp.Type]
+		if !ok || ecp.Settings == nil {
+			continue
+		}
+		cfg, ok := integration.convert(ecp.Settings)
+		if !ok {
+			continue
+		}
+		cfg["send_resolved"] = !ecp.DisableResolveMessage
+		configs := integration.configs(&export.Receivers[i])
+		*configs = append(*configs, cfg)
+	}
+	return export
+}
+
+// setStrings copies the string settings that are set to the configuration under the names they are mapped to.
+func setStrings(cfg map[string]any, settings *simplejson.Json, names map[string]string) {
+	for key, name := range names {
+		if value := settings.Get(key).MustString(); value != "" {
+			cfg[name] = value
+		}
+	}
+}
+
+// numberSetting returns a numeric setting, which Grafana accepts both as a number and as a string.
+func numberSetting(settings *simplejson.Json, key string) (int64, bool) {
+	value := settings.Get(key).Interface()
+	if value == nil {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(fmt.Sprint(value)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
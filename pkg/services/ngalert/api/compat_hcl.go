@@ -0,0 +1,335 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/hcl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// terraformIntegrationBlocks are the blocks of the grafana_contact_point resource of the Terraform provider by
+// integration type, for the types whose block is not named after the lowercase type.
+var terraformIntegrationBlocks = map[string]string{
+	"prometheus-alertmanager": "alertmanager",
+}
+
+// hclResourceNames gives the resources of a Terraform configuration unique names.
+type hclResourceNames map[string]int
+
+// name returns the name of a resource of the given type. Resources whose names collide get a numeric suffix.
+func (n hclResourceNames) name(resourceType, name string) string {
+	id := hcl.Identifier(name)
+	key := resourceType + "." + id
+	n[key]++
+	if n[key] > 1 {
+		id = fmt.Sprintf("%s_%d", id, n[key])
+	}
+	return id
+}
+
+// AlertingFileExportToHCL renders an export as the resources of the Grafana Terraform provider. The secure settings
+// of contact points are replaced by references to sensitive variables, which are declared in the configuration.
+func AlertingFileExportToHCL(e definitions.AlertingFileExport) ([]byte, error) {
+	body := &hcl.Body{}
+	if err := appendHCLResources(body, hclResourceNames{}, e); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// appendHCLResources appends the resources of an export to a Terraform configuration.
+func appendHCLResources(body *hcl.Body, names hclResourceNames, e definitions.AlertingFileExport) error {
+	for _, cp := range e.ContactPoints {
+		if err := appendHCLContactPoint(body, names, cp); err != nil {
+			return err
+		}
+	}
+	for _, p := range e.Policies {
+		appendHCLNotificationPolicy(body, names, p)
+	}
+	for _, mt := range e.MuteTimings {
+		if err := appendHCLMuteTiming(body, names, mt); err != nil {
+			return err
+		}
+	}
+	for _, t := range e.Templates {
+		resource := body.AppendBlock("resource", "grafana_message_template", names.name("grafana_message_template", t.Name))
+		setHCLOrgID(resource, t.OrgID)
+		resource.SetAttribute("name", hcl.String(t.Name))
+		resource.SetAttribute("template", hcl.String(t.Template))
+	}
+	for _, g := range e.Groups {
+		if err := appendHCLRuleGroup(body, names, g); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setHCLOrgID sets the organization of a resource, unless it is the default organization of the provider.
+func setHCLOrgID(resource *hcl.Body, orgID int64) {
+	if orgID > 1 {
+		resource.SetAttribute("org_id", hcl.String(fmt.Sprint(orgID)))
+	}
+}
+
+func appendHCLContactPoint(body *hcl.Body, names hclResourceNames, cp definitions.ContactPointExport) error {
+	resourceName := names.name("grafana_contact_point", cp.Name)
+	// The variables are declared before the contact point that refers to them.
+	variables := &hcl.Body{}
+	resource := &hcl.Body{}
+	setHCLOrgID(resource, cp.OrgID)
+	resource.SetAttribute("name", hcl.String(cp.Name))
+	for _, receiver := range cp.Receivers {
+		blockType, ok := terraformIntegrationBlocks[receiver.Type]
+		if !ok {
+			blockType = strings.ToLower(receiver.Type)
+		}
+		var settings map[string]any
+		if err := json.Unmarshal(receiver.Settings, &settings); err != nil {
+			return fmt.Errorf("failed to read the settings of contact point '%s': %w", cp.Name, err)
+		}
+		secureKeys, err := provisioning.GetSecretKeysForContactPointType(receiver.Type)
+		if err != nil {
+			return err
+		}
+		secure := make(map[string]struct{}, len(secureKeys))
+		for _, key := range secureKeys {
+			secure[key] = struct{}{}
+		}
+
+		integration := resource.AppendBlock(blockType)
+		integration.SetAttribute("uid", hcl.String(receiver.UID))
+		if receiver.DisableResolveMessage {
+			integration.SetAttribute("disable_resolve_message", hcl.Bool(true))
+		}
+		keys := make([]string, 0, len(settings))
+		for key := range settings {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			attribute := snakeCase(key)
+			if _, ok := secure[key]; ok {
+				variable := fmt.Sprintf("%s_%s_%s", resourceName, hcl.Identifier(blockType), attribute)
+				declaration := variables.AppendBlock("variable", variable)
+				declaration.SetAttribute("type", hcl.Reference("string"))
+				declaration.SetAttribute("sensitive", hcl.Bool(true))
+				integration.SetAttribute(attribute, hcl.Reference("var."+variable))
+				continue
+			}
+			value, err := hclIntegrationSetting(blockType, key, settings[key])
+			if err != nil {
+				return fmt.Errorf("failed to convert setting '%s' of contact point '%s': %w", key, cp.Name, err)
+			}
+			integration.SetAttribute(attribute, value)
+		}
+	}
+	body.Append(variables)
+	body.AppendBlock("resource", "grafana_contact_point", resourceName).Append(resource)
+	return nil
+}
+
+// hclIntegrationSetting converts a setting of an integration to the attribute of the Terraform provider. The
+// addresses of email integrations are a list in the provider.
+func hclIntegrationSetting(blockType, key string, value any) (hcl.Value, error) {
+	if s, ok := value.(string); ok && blockType == "email" && key == "addresses" {
+		return hcl.Strings(splitAddresses(s)), nil
+	}
+	return hcl.FromJSON(value)
+}
+
+func splitAddresses(s string) []string {
+	addresses := []string{}
+	for _, address := range strings.FieldsFunc(s, func(r rune) bool { return r == ';' || r == ',' || r == '\n' }) {
+		if address = strings.TrimSpace(address); address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+var snakeCaseRegex = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// snakeCase converts the camel case key of a setting to the snake case of the Terraform provider.
+func snakeCase(key string) string {
+	return strings.ToLower(snakeCaseRegex.ReplaceAllString(key, "${1}_${2}"))
+}
+
+func appendHCLNotificationPolicy(body *hcl.Body, names hclResourceNames, p definitions.NotificationPolicyExport) {
+	resourceName := "notification_policy"
+	if p.OrgID > 1 {
+		resourceName = fmt.Sprintf("notification_policy_org_%d", p.OrgID)
+	}
+	resource := body.AppendBlock("resource", "grafana_notification_policy", names.name("grafana_notification_policy", resourceName))
+	setHCLOrgID(resource, p.OrgID)
+	if p.Policy != nil {
+		setHCLPolicy(resource, p.Policy)
+	}
+}
+
+// setHCLPolicy sets the attributes and nested policies of a policy of the grafana_notification_policy resource.
+func setHCLPolicy(b *hcl.Body, route *definitions.RouteExport) {
+	if route.Receiver != "" {
+		b.SetAttribute("contact_point", hcl.String(route.Receiver))
+	}
+	if len(route.GroupByStr) > 0 {
+		b.SetAttribute("group_by", hcl.Strings(route.GroupByStr))
+	}
+	if len(route.MuteTimeIntervals) > 0 {
+		b.SetAttribute("mute_timings", hcl.Strings(route.MuteTimeIntervals))
+	}
+	if route.Continue {
+		b.SetAttribute("continue", hcl.Bool(true))
+	}
+	setHCLDuration(b, "group_wait", route.GroupWait)
+	setHCLDuration(b, "group_interval", route.GroupInterval)
+	setHCLDuration(b, "repeat_interval", route.RepeatInterval)
+
+	appendMatcher := func(label, match, value string) {
+		matcher := b.AppendBlock("matcher")
+		matcher.SetAttribute("label", hcl.String(label))
+		matcher.SetAttribute("match", hcl.String(match))
+		matcher.SetAttribute("value", hcl.String(value))
+	}
+	for _, label := range sortedKeys(route.Match) {
+		appendMatcher(label, "=", route.Match[label])
+	}
+	for _, label := range sortedKeys(route.MatchRE) {
+		appendMatcher(label, "=~", route.MatchRE[label].String())
+	}
+	for _, m := range route.Matchers {
+		appendMatcher(m.Name, m.Type.String(), m.Value)
+	}
+	for _, m := range route.ObjectMatchers {
+		appendMatcher(m.Name, m.Type.String(), m.Value)
+	}
+	for _, child := range route.Routes {
+		setHCLPolicy(b.AppendBlock("policy"), child)
+	}
+}
+
+func setHCLDuration(b *hcl.Body, name string, d *model.Duration) {
+	if d != nil {
+		b.SetAttribute(name, hcl.String(d.String()))
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hclTimeInterval is a time interval of a mute timing with the ranges formatted like in the Alertmanager
+// configuration, which is also how the Terraform provider expects them.
+type hclTimeInterval struct {
+	Times []struct {
+		StartTime string `json:"start_time"`
+		EndTime   string `json:"end_time"`
+	} `json:"times"`
+	Weekdays    []string `json:"weekdays"`
+	DaysOfMonth []string `json:"days_of_month"`
+	Months      []string `json:"months"`
+	Years       []string `json:"years"`
+	Location    string   `json:"location"`
+}
+
+func appendHCLMuteTiming(body *hcl.Body, names hclResourceNames, mt definitions.MuteTimeIntervalExport) error {
+	resource := body.AppendBlock("resource", "grafana_mute_timing", names.name("grafana_mute_timing", mt.Name))
+	setHCLOrgID(resource, mt.OrgID)
+	resource.SetAttribute("name", hcl.String(mt.Name))
+	for _, interval := range mt.TimeIntervals {
+		data, err := json.Marshal(interval)
+		if err != nil {
+			return err
+		}
+		var ti hclTimeInterval
+		if err := json.Unmarshal(data, &ti); err != nil {
+			return err
+		}
+		b := resource.AppendBlock("intervals")
+		if len(ti.Weekdays) > 0 {
+			b.SetAttribute("weekdays", hcl.Strings(ti.Weekdays))
+		}
+		if len(ti.DaysOfMonth) > 0 {
+			b.SetAttribute("days_of_month", hcl.Strings(ti.DaysOfMonth))
+		}
+		if len(ti.Months) > 0 {
+			b.SetAttribute("months", hcl.Strings(ti.Months))
+		}
+		if len(ti.Years) > 0 {
+			b.SetAttribute("years", hcl.Strings(ti.Years))
+		}
+		if ti.Location != "" {
+			b.SetAttribute("location", hcl.String(ti.Location))
+		}
+		for _, t := range ti.Times {
+			times := b.AppendBlock("times")
+			times.SetAttribute("start", hcl.String(t.StartTime))
+			times.SetAttribute("end", hcl.String(t.EndTime))
+		}
+	}
+	return nil
+}
+
+func appendHCLRuleGroup(body *hcl.Body, names hclResourceNames, g definitions.AlertRuleGroupExport) error {
+	resource := body.AppendBlock("resource", "grafana_rule_group", names.name("grafana_rule_group", g.Folder+"_"+g.Name))
+	setHCLOrgID(resource, g.OrgID)
+	resource.SetAttribute("name", hcl.String(g.Name))
+	resource.SetAttribute("folder_uid", hcl.String(g.FolderUID))
+	resource.SetAttribute("interval_seconds", hcl.Int(int64(time.Duration(g.Interval).Seconds())))
+	for _, r := range g.Rules {
+		rule := resource.AppendBlock("rule")
+		rule.SetAttribute("name", hcl.String(r.Title))
+		rule.SetAttribute("uid", hcl.String(r.UID))
+		rule.SetAttribute("condition", hcl.String(r.Condition))
+		rule.SetAttribute("for", hcl.String(r.For.String()))
+		rule.SetAttribute("no_data_state", hcl.String(string(r.NoDataState)))
+		rule.SetAttribute("exec_err_state", hcl.String(string(r.ExecErrState)))
+		if r.IsPaused {
+			rule.SetAttribute("is_paused", hcl.Bool(true))
+		}
+		if len(r.Annotations) > 0 {
+			rule.SetAttribute("annotations", hcl.StringMap(r.Annotations))
+		}
+		if len(r.Labels) > 0 {
+			rule.SetAttribute("labels", hcl.StringMap(r.Labels))
+		}
+		for _, q := range r.Data {
+			data := rule.AppendBlock("data")
+			data.SetAttribute("ref_id", hcl.String(q.RefID))
+			if q.QueryType != "" {
+				data.SetAttribute("query_type", hcl.String(q.QueryType))
+			}
+			data.SetAttribute("datasource_uid", hcl.String(q.DatasourceUID))
+			queryModel, err := hcl.FromJSON(q.Model)
+			if err != nil {
+				return fmt.Errorf("failed to convert the model of query '%s' of rule '%s': %w", q.RefID, r.Title, err)
+			}
+			data.SetAttribute("model", hcl.Call("jsonencode", queryModel))
+			timeRange := data.AppendBlock("relative_time_range")
+			timeRange.SetAttribute("from", hcl.Int(int64(time.Duration(q.RelativeTimeRange.From).Seconds())))
+			timeRange.SetAttribute("to", hcl.Int(int64(time.Duration(q.RelativeTimeRange.To).Seconds())))
+		}
+		if ns := r.NotificationSettings; ns != nil {
+			settings := rule.AppendBlock("notification_settings")
+			settings.SetAttribute("contact_point", hcl.String(ns.Receiver))
+			if len(ns.MuteTimeIntervals) > 0 {
+				settings.SetAttribute("mute_timings", hcl.Strings(ns.MuteTimeIntervals))
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestAlertingFileExportToHCL(t *testing.T) {
+	t.Run("secure settings of contact points are variables", func(t *testing.T) {
+		export := definitions.AlertingFileExport{
+			APIVersion: 1,
+			ContactPoints: []definitions.ContactPointExport{{
+				OrgID: 2,
+				Name:  "Team A",
+				Receivers: []definitions.ReceiverExport{
+					{UID: "slack-uid", Type: "slack", Settings: definitions.RawMessage(`{"recipient":"#alerts","token":"[REDACTED]","mentionChannel":"here"}`)},
+					{UID: "email-uid", Type: "email", Settings: definitions.RawMessage(`{"addresses":"a@example.com;b@example.com"}`), DisableResolveMessage: true},
+				},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+
+		require.Equal(t, `variable "team_a_slack_token" {
+  type      = string
+  sensitive = true
+}
+
+resource "grafana_contact_point" "team_a" {
+  org_id = "2"
+  name   = "Team A"
+
+  slack {
+    uid             = "slack-uid"
+    mention_channel = "here"
+    recipient       = "#alerts"
+    token           = var.team_a_slack_token
+  }
+
+  email {
+    uid                     = "email-uid"
+    disable_resolve_message = true
+    addresses               = ["a@example.com", "b@example.com"]
+  }
+}
+`, string(b))
+	})
+
+	t.Run("policies, mute timings and rule groups are resources", func(t *testing.T) {
+		var muteTiming amConfig.MuteTimeInterval
+		require.NoError(t, json.Unmarshal([]byte(`{"name":"weekends","time_intervals":[{"weekdays":["saturday","sunday"],"times":[{"start_time":"00:00","end_time":"06:00"}]}]}`), &muteTiming))
+		matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+		require.NoError(t, err)
+		groupWait := model.Duration(30 * time.Second)
+		export := definitions.AlertingFileExport{
+			APIVersion: 1,
+			Policies: []definitions.NotificationPolicyExport{{
+				OrgID: 1,
+				Policy: &definitions.RouteExport{
+					Receiver:   "Team A",
+					GroupByStr: []string{"alertname"},
+					GroupWait:  &groupWait,
+					Routes: []*definitions.RouteExport{{
+						Receiver:          "Team A",
+						ObjectMatchers:    definitions.ObjectMatchers{matcher},
+						MuteTimeIntervals: []string{"weekends"},
+					}},
+				},
+			}},
+			MuteTimings: []definitions.MuteTimeIntervalExport{{OrgID: 1, MuteTimeInterval: muteTiming}},
+			Groups: []definitions.AlertRuleGroupExport{{
+				OrgID:     1,
+				Name:      "cpu",
+				Folder:    "Infra",
+				FolderUID: "infra-uid",
+				Interval:  model.Duration(time.Minute),
+				Rules: []definitions.AlertRuleExport{{
+					UID:          "rule-uid",
+					Title:        "High CPU",
+					Condition:    "A",
+					NoDataState:  definitions.NoData,
+					ExecErrState: definitions.AlertingErrState,
+					For:          model.Duration(5 * time.Minute),
+					Data: []definitions.AlertQueryExport{{
+						RefID:             "A",
+						DatasourceUID:     "prometheus-uid",
+						RelativeTimeRange: definitions.RelativeTimeRange{From: definitions.Duration(10 * time.Minute)},
+						Model:             map[string]any{"expr": "cpu > 0.9"},
+					}},
+				}},
+			}},
+		}
+
+		b, err := AlertingFileExportToHCL(export)
+		require.NoError(t, err)
+
+		require.Equal(t, `resource "grafana_notification_policy" "notification_policy" {
+  contact_point = "Team A"
+  group_by      = ["alertname"]
+  group_wait    = "30s"
+
+  policy {
+    contact_point = "Team A"
+    mute_timings  = ["weekends"]
+
+    matcher {
+      label = "team"
+      match = "="
+      value = "a"
+    }
+  }
+}
+
+resource "grafana_mute_timing" "weekends" {
+  name = "weekends"
+
+  intervals {
+    weekdays = ["saturday", "sunday"]
+
+    times {
+      start = "00:00"
+      end   = "06:00"
+    }
+  }
+}
+
+resource "grafana_rule_group" "infra_cpu" {
+  name             = "cpu"
+  folder_uid       = "infra-uid"
+  interval_seconds = 60
+
+  rule {
+    name           = "High CPU"
+    uid            = "rule-uid"
+    condition      = "A"
+    for            = "5m"
+    no_data_state  = "NoData"
+    exec_err_state = "Alerting"
+
+    data {
+      ref_id         = "A"
+      datasource_uid = "prometheus-uid"
+      model = jsonencode({
+        expr = "cpu > 0.9"
+      })
+
+      relative_time_range {
+        from = 600
+        to   = 0
+      }
+    }
+  }
+}
+`, string(b))
+	})
+
+	t.Run("fails for unknown contact point types", func(t *testing.T) {
+		_, err := AlertingFileExportToHCL(definitions.AlertingFileExport{
+			ContactPoints: []definitions.ContactPointExport{{Name: "unknown", Receivers: []definitions.ReceiverExport{{Type: "unknown", Settings: definitions.RawMessage(`{}`)}}}},
+		})
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,296 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	amConfig "github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/common/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// k8sOperatorAPIVersion is the API version of the custom resources of the Grafana Operator.
+const k8sOperatorAPIVersion = "grafana.integreatly.org/v1beta1"
+
+// k8sMaxNameLength leaves room for the suffix of colliding names within the 253 characters of a Kubernetes name.
+const k8sMaxNameLength = 240
+
+// k8sManifest is a custom resource of the Grafana Operator.
+type k8sManifest struct {
+	APIVersion string      `yaml:"apiVersion"`
+	Kind       string      `yaml:"kind"`
+	Metadata   k8sMetadata `yaml:"metadata"`
+	Spec       any         `yaml:"spec"`
+}
+
+type k8sMetadata struct {
+	Name string `yaml:"name"`
+}
+
+// k8sInstanceSelector selects the Grafana instances a resource is applied to. It is exported empty and has to be set
+// to the labels of the Grafana instances before the manifests are applied.
+type k8sInstanceSelector struct {
+	MatchLabels map[string]string `yaml:"matchLabels"`
+}
+
+type k8sContactPointSpec struct {
+	InstanceSelector      k8sInstanceSelector `yaml:"instanceSelector"`
+	UID                   string              `yaml:"uid,omitempty"`
+	Name                  string              `yaml:"name"`
+	Type                  string              `yaml:"type"`
+	DisableResolveMessage bool                `yaml:"disableResolveMessage,omitempty"`
+	Settings              map[string]any      `yaml:"settings"`
+	ValuesFrom            []k8sValueFrom      `yaml:"valuesFrom,omitempty"`
+}
+
+// k8sValueFrom sets a setting of a contact point from a key of a secret.
+type k8sValueFrom struct {
+	TargetPath string `yaml:"targetPath"`
+	ValueFrom  struct {
+		SecretKeyRef struct {
+			Name string `yaml:"name"`
+			Key  string `yaml:"key"`
+		} `yaml:"secretKeyRef"`
+	} `yaml:"valueFrom"`
+}
+
+type k8sNotificationPolicySpec struct {
+	InstanceSelector k8sInstanceSelector `yaml:"instanceSelector"`
+	Route            *k8sRoute           `yaml:"route"`
+}
+
+// k8sRoute is a notification policy of the Grafana Operator. All matchers are exported as object matchers.
+type k8sRoute struct {
+	Receiver          string          `yaml:"receiver,omitempty"`
+	GroupBy           []string        `yaml:"group_by,omitempty"`
+	ObjectMatchers    [][3]string     `yaml:"object_matchers,omitempty"`
+	MuteTimeIntervals []string        `yaml:"mute_time_intervals,omitempty"`
+	Continue          bool            `yaml:"continue,omitempty"`
+	GroupWait         *model.Duration `yaml:"group_wait,omitempty"`
+	GroupInterval     *model.Duration `yaml:"group_interval,omitempty"`
+	RepeatInterval    *model.Duration `yaml:"repeat_interval,omitempty"`
+	Routes            []*k8sRoute     `yaml:"routes,omitempty"`
+}
+
+type k8sMuteTimingSpec struct {
+	InstanceSelector          k8sInstanceSelector `yaml:"instanceSelector"`
+	amConfig.MuteTimeInterval `yaml:",inline"`
+}
+
+type k8sNotificationTemplateSpec struct {
+	InstanceSelector k8sInstanceSelector `yaml:"instanceSelector"`
+	Name             string              `yaml:"name"`
+	Template         string              `yaml:"template"`
+}
+
+type k8sAlertRuleGroupSpec struct {
+	InstanceSelector k8sInstanceSelector `yaml:"instanceSelector"`
+	Name             string              `yaml:"name"`
+	FolderUID        string              `yaml:"folderUID"`
+	Interval         model.Duration      `yaml:"interval"`
+	Rules            []k8sAlertRule      `yaml:"rules"`
+}
+
+type k8sAlertRule struct {
+	UID                  string                                     `yaml:"uid"`
+	Title                string                                     `yaml:"title"`
+	Condition            string                                     `yaml:"condition"`
+	Data                 []definitions.AlertQueryExport             `yaml:"data"`
+	NoDataState          definitions.NoDataState                    `yaml:"noDataState"`
+	ExecErrState         definitions.ExecutionErrorState            `yaml:"execErrState"`
+	For                  model.Duration                             `yaml:"for"`
+	Annotations          map[string]string                          `yaml:"annotations,omitempty"`
+	Labels               map[string]string                          `yaml:"labels,omitempty"`
+	IsPaused             bool                                       `yaml:"isPaused,omitempty"`
+	NotificationSettings *definitions.AlertRuleNotificationSettings `yaml:"notificationSettings,omitempty"`
+}
+
+// k8sResourceNames gives the custom resources of a kind unique names.
+type k8sResourceNames map[string]int
+
+// name returns the name of a resource of the given kind. Resources whose names collide get a numeric suffix.
+func (n k8sResourceNames) name(kind, name string) string {
+	id := k8sName(name)
+	key := kind + "/" + id
+	n[key]++
+	if n[key] > 1 {
+		id = fmt.Sprintf("%s-%d", id, n[key])
+	}
+	return id
+}
+
+// k8sName turns a name into a valid Kubernetes name: lowercase alphanumeric characters, '-' and '.', starting and
+// ending with an alphanumeric character.
+func k8sName(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' {
+			sb.WriteRune(r)
+		} else if !strings.HasSuffix(sb.String(), "-") {
+			sb.WriteRune('-')
+		}
+	}
+	id := sb.String()
+	if len(id) > k8sMaxNameLength {
+		id = id[:k8sMaxNameLength]
+	}
+	id = strings.Trim(id, "-.")
+	if id == "" {
+		return "unnamed"
+	}
+	return id
+}
+
+// AlertingFileExportToK8s renders an export as custom resources of the Grafana Operator, in a stream of YAML
+// documents. The secure settings of contact points are read from a secret with the name of the contact point resource.
+func AlertingFileExportToK8s(e definitions.AlertingFileExport) ([]byte, error) {
+	manifests, err := appendK8sManifests(nil, k8sResourceNames{}, e)
+	if err != nil {
+		return nil, err
+	}
+	buf := &bytes.Buffer{}
+	enc := yaml.NewEncoder(buf)
+	for _, m := range manifests {
+		if err := enc.Encode(m); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// appendK8sManifests appends the custom resources of an export.
+func appendK8sManifests(manifests []k8sManifest, names k8sResourceNames, e definitions.AlertingFileExport) ([]k8sManifest, error) {
+	manifest := func(kind, name string, spec any) k8sManifest {
+		return k8sManifest{
+			APIVersion: k8sOperatorAPIVersion,
+			Kind:       kind,
+			Metadata:   k8sMetadata{Name: names.name(kind, name)},
+			Spec:       spec,
+		}
+	}
+	for _, cp := range e.ContactPoints {
+		for _, receiver := range cp.Receivers {
+			m := manifest("GrafanaContactPoint", cp.Name, nil)
+			spec, err := k8sContactPointSpecFromReceiver(m.Metadata.Name, cp.Name, receiver)
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert contact point '%s': %w", cp.Name, err)
+			}
+			m.Spec = spec
+			manifests = append(manifests, m)
+		}
+	}
+	for _, p := range e.Policies {
+		if p.Policy == nil {
+			continue
+		}
+		name := "notification-policy"
+		if p.OrgID > 1 {
+			name = fmt.Sprintf("notification-policy-org-%d", p.OrgID)
+		}
+		manifests = append(manifests, manifest("GrafanaNotificationPolicy", name, k8sNotificationPolicySpec{
+			Route: k8sRouteFromRouteExport(p.Policy),
+		}))
+	}
+	for _, mt := range e.MuteTimings {
+		manifests = append(manifests, manifest("GrafanaMuteTiming", mt.Name, k8sMuteTimingSpec{
+			MuteTimeInterval: mt.MuteTimeInterval,
+		}))
+	}
+	for _, t := range e.Templates {
+		manifests = append(manifests, manifest("GrafanaNotificationTemplate", t.Name, k8sNotificationTemplateSpec{
+			Name:     t.Name,
+			Template: t.Template,
+		}))
+	}
+	for _, g := range e.Groups {
+		rules := make([]k8sAlertRule, 0, len(g.Rules))
+		for _, r := range g.Rules {
+			rules = append(rules, k8sAlertRule{
+				UID:                  r.UID,
+				Title:                r.Title,
+				Condition:            r.Condition,
+				Data:                 r.Data,
+				NoDataState:          r.NoDataState,
+				ExecErrState:         r.ExecErrState,
+				For:                  r.For,
+				Annotations:          r.Annotations,
+				Labels:               r.Labels,
+				IsPaused:             r.IsPaused,
+				NotificationSettings: r.NotificationSettings,
+			})
+		}
+		manifests = append(manifests, manifest("GrafanaAlertRuleGroup", g.Folder+"-"+g.Name, k8sAlertRuleGroupSpec{
+			Name:      g.Name,
+			FolderUID: g.FolderUID,
+			Interval:  g.Interval,
+			Rules:     rules,
+		}))
+	}
+	return manifests, nil
+}
+
+// k8sContactPointSpecFromReceiver creates the spec of a contact point resource with one integration. The secure
+// settings are left out of the settings and read from the secret with the name of the resource instead.
+func k8sContactPointSpecFromReceiver(resourceName, name string, receiver definitions.ReceiverExport) (k8sContactPointSpec, error) {
+	spec := k8sContactPointSpec{
+		UID:                   receiver.UID,
+		Name:                  name,
+		Type:                  receiver.Type,
+		DisableResolveMessage: receiver.DisableResolveMessage,
+		Settings:              map[string]any{},
+	}
+	if err := json.Unmarshal(receiver.Settings, &spec.Settings); err != nil {
+		return k8sContactPointSpec{}, err
+	}
+	secureKeys, err := provisioning.GetSecretKeysForContactPointType(receiver.Type)
+	if err != nil {
+		return k8sContactPointSpec{}, err
+	}
+	for _, key := range secureKeys {
+		if _, ok := spec.Settings[key]; !ok {
+			continue
+		}
+		delete(spec.Settings, key)
+		v := k8sValueFrom{TargetPath: key}
+		v.ValueFrom.SecretKeyRef.Name = resourceName
+		v.ValueFrom.SecretKeyRef.Key = key
+		spec.ValuesFrom = append(spec.ValuesFrom, v)
+	}
+	return spec, nil
+}
+
+func k8sRouteFromRouteExport(route *definitions.RouteExport) *k8sRoute {
+	r := &k8sRoute{
+		Receiver:          route.Receiver,
+		GroupBy:           route.GroupByStr,
+		MuteTimeIntervals: route.MuteTimeIntervals,
+		Continue:          route.Continue,
+		GroupWait:         route.GroupWait,
+		GroupInterval:     route.GroupInterval,
+		RepeatInterval:    route.RepeatInterval,
+	}
+	for _, label := range sortedKeys(route.Match) {
+		r.ObjectMatchers = append(r.ObjectMatchers, [3]string{label, "=", route.Match[label]})
+	}
+	for _, label := range sortedKeys(route.MatchRE) {
+		r.ObjectMatchers = append(r.ObjectMatchers, [3]string{label, "=~", route.MatchRE[label].String()})
+	}
+	for _, m := range route.Matchers {
+		r.ObjectMatchers = append(r.ObjectMatchers, [3]string{m.Name, m.Type.String(), m.Value})
+	}
+	for _, m := range route.ObjectMatchers {
+		r.ObjectMatchers = append(r.ObjectMatchers, [3]string{m.Name, m.Type.String(), m.Value})
+	}
+	for _, child := range route.Routes {
+		r.Routes = append(r.Routes, k8sRouteFromRouteExport(child))
+	}
+	return r
+}
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestAlertingFileExportToK8s(t *testing.T) {
+	matcher, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+	export := definitions.AlertingFileExport{
+		APIVersion: 1,
+		ContactPoints: []definitions.ContactPointExport{{
+			OrgID: 1,
+			Name:  "Team A",
+			Receivers: []definitions.ReceiverExport{
+				{UID: "slack-uid", Type: "slack", Settings: definitions.RawMessage(`{"recipient":"#alerts","token":"[REDACTED]"}`)},
+				{UID: "email-uid", Type: "email", Settings: definitions.RawMessage(`{"addresses":"a@example.com"}`)},
+			},
+		}},
+		Policies: []definitions.NotificationPolicyExport{{
+			OrgID: 1,
+			Policy: &definitions.RouteExport{
+				Receiver: "Team A",
+				Routes: []*definitions.RouteExport{{
+					Receiver:       "Team A",
+					Match:          map[string]string{"severity": "critical"},
+					ObjectMatchers: definitions.ObjectMatchers{matcher},
+				}},
+			},
+		}},
+		Templates: []definitions.NotificationTemplateExport{{OrgID: 1, Name: "alerts", Template: `{{ define "alerts" }}{{ end }}`}},
+		Groups: []definitions.AlertRuleGroupExport{{
+			OrgID:     1,
+			Name:      "cpu",
+			Folder:    "Infra",
+			FolderUID: "infra-uid",
+			Interval:  model.Duration(time.Minute),
+			Rules: []definitions.AlertRuleExport{{
+				UID:          "rule-uid",
+				Title:        "High CPU",
+				Condition:    "A",
+				NoDataState:  definitions.NoData,
+				ExecErrState: definitions.AlertingErrState,
+				For:          model.Duration(5 * time.Minute),
+				Data: []definitions.AlertQueryExport{{
+					RefID:         "A",
+					DatasourceUID: "prometheus-uid",
+					Model:         map[string]any{"expr": "cpu > 0.9"},
+				}},
+			}},
+		}},
+	}
+
+	b, err := AlertingFileExportToK8s(export)
+	require.NoError(t, err)
+
+	var manifests []map[string]any
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	for {
+		var m map[string]any
+		err := dec.Decode(&m)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		require.NoError(t, err)
+		require.Equal(t, k8sOperatorAPIVersion, m["apiVersion"])
+		manifests = append(manifests, m)
+	}
+	require.Len(t, manifests, 5)
+
+	kinds := make([]string, 0, len(manifests))
+	names := make([]string, 0, len(manifests))
+	for _, m := range manifests {
+		kinds = append(kinds, m["kind"].(string))
+		names = append(names, m["metadata"].(map[string]any)["name"].(string))
+	}
+	require.Equal(t, []string{"GrafanaContactPoint", "GrafanaContactPoint", "GrafanaNotificationPolicy", "GrafanaNotificationTemplate", "GrafanaAlertRuleGroup"}, kinds)
+	require.Equal(t, []string{"team-a", "team-a-2", "notification-policy", "alerts", "infra-cpu"}, names)
+
+	t.Run("secure settings of contact points are read from secrets", func(t *testing.T) {
+		spec := manifests[0]["spec"].(map[string]any)
+		require.Equal(t, "Team A", spec["name"])
+		require.Equal(t, "slack", spec["type"])
+		require.Equal(t, map[string]any{"recipient": "#alerts"}, spec["settings"])
+		require.Equal(t, []any{map[string]any{
+			"targetPath": "token",
+			"valueFrom": map[string]any{
+				"secretKeyRef": map[string]any{"name": "team-a", "key": "token"},
+			},
+		}}, spec["valuesFrom"])
+		require.NotContains(t, manifests[1]["spec"], "valuesFrom")
+	})
+
+	t.Run("matchers of notification policies are object matchers", func(t *testing.T) {
+		route := manifests[2]["spec"].(map[string]any)["route"].(map[string]any)
+		require.Equal(t, []any{
+			[]any{"severity", "=", "critical"},
+			[]any{"team", "=", "a"},
+		}, route["routes"].([]any)[0].(map[string]any)["object_matchers"])
+	})
+
+	t.Run("rule groups refer to folders by UID", func(t *testing.T) {
+		spec := manifests[4]["spec"].(map[string]any)
+		require.Equal(t, "cpu", spec["name"])
+		require.Equal(t, "infra-uid", spec["folderUID"])
+		require.Equal(t, "1m", spec["interval"])
+		rule := spec["rules"].([]any)[0].(map[string]any)
+		require.Equal(t, "5m", rule["for"])
+		require.Equal(t, "prometheus-uid", rule["data"].([]any)[0].(map[string]any)["datasourceUid"])
+	})
+}
+
+func TestK8sName(t *testing.T) {
+	require.Equal(t, "team-a-alerts", k8sName("Team A / Alerts"))
+	require.Equal(t, "v1.2", k8sName("-v1.2-"))
+	require.Equal(t, "unnamed", k8sName("!!"))
+}
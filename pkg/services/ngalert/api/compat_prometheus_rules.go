@@ -0,0 +1,165 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const exportTargetPrometheus = "prometheus"
+
+// errIncompatiblePrometheusRule is returned for alert rules whose condition has no equivalent PromQL expression.
+var errIncompatiblePrometheusRule = errors.New("alert rule cannot be exported as a Prometheus rule")
+
+// prometheusRuleQueryModel holds the fields of the model of a query or expression that the export depends on.
+type prometheusRuleQueryModel struct {
+	Type       string `json:"type"`
+	Expr       string `json:"expr"`
+	Expression string `json:"expression"`
+	Reducer    string `json:"reducer"`
+	Settings   *struct {
+		Mode string `json:"mode"`
+	} `json:"settings"`
+	Conditions []struct {
+		Evaluator       expr.ConditionEvalJSON  `json:"evaluator"`
+		UnloadEvaluator *expr.ConditionEvalJSON `json:"unloadEvaluator"`
+	} `json:"conditions"`
+}
+
+// PrometheusRulesExportFromAlertRuleGroupWithFolderTitle creates a definitions.PrometheusRulesExport DTO from
+// models.AlertRuleGroupWithFolderTitle. Rules that cannot be exported fail the export, unless skipIncompatible is set,
+// in which case they are left out. Groups without any exported rule are left out as well.
+func PrometheusRulesExportFromAlertRuleGroupWithFolderTitle(groups []models.AlertRuleGroupWithFolderTitle, skipIncompatible bool) (definitions.PrometheusRulesExport, error) {
+	export := definitions.PrometheusRulesExport{}
+	for _, group := range groups {
+		g := definitions.PrometheusRuleGroupExport{
+			Name:     group.Title,
+			Interval: model.Duration(time.Duration(group.Interval) * time.Second),
+			Rules:    make([]definitions.ApiRuleNode, 0, len(group.Rules)),
+		}
+		for _, rule := range group.Rules {
+			node, err := PrometheusRuleFromAlertRule(rule)
+			if errors.Is(err, errIncompatiblePrometheusRule) && skipIncompatible {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			g.Rules = append(g.Rules, node)
+		}
+		if len(g.Rules) > 0 {
+			export[group.FolderTitle] = append(export[group.FolderTitle], g)
+		}
+	}
+	return export, nil
+}
+
+// PrometheusRuleFromAlertRule creates a Prometheus alerting rule from models.AlertRule. The condition of the rule
+// must be a query with a PromQL expression, or a threshold of such a query that is optionally reduced to its last
+// value. Annotations that are internal to Grafana are left out.
+func PrometheusRuleFromAlertRule(rule models.AlertRule) (definitions.ApiRuleNode, error) {
+	promQL, err := prometheusExprFromAlertRule(rule)
+	if err != nil {
+		return definitions.ApiRuleNode{}, fmt.Errorf("%w: rule '%s' (%s): %s", errIncompatiblePrometheusRule, rule.Title, rule.UID, err.Error())
+	}
+	node := definitions.ApiRuleNode{
+		Alert:  rule.Title,
+		Expr:   promQL,
+		Labels: rule.Labels,
+	}
+	if rule.For > 0 {
+		d := model.Duration(rule.For)
+		node.For = &d
+	}
+	for key, value := range rule.Annotations {
+		if strings.HasPrefix(key, "__") {
+			continue
+		}
+		if node.Annotations == nil {
+			node.Annotations = map[string]string{}
+		}
+		node.Annotations[key] = value
+	}
+	return node, nil
+}
+
+func prometheusExprFromAlertRule(rule models.AlertRule) (string, error) {
+	queries := make(map[string]models.AlertQuery, len(rule.Data))
+	for _, q := range rule.Data {
+		queries[q.RefID] = q
+	}
+	condition, m, err := prometheusRuleQuery(queries, rule.Condition)
+	if err != nil {
+		return "", err
+	}
+	if !expr.IsDataSource(condition.DatasourceUID) {
+		return prometheusExprFromQuery(m)
+	}
+	if m.Type != "threshold" {
+		return "", fmt.Errorf("condition is a %s expression", m.Type)
+	}
+	if len(m.Conditions) != 1 || m.Conditions[0].UnloadEvaluator != nil {
+		return "", errors.New("threshold has more than one condition or a recovery threshold")
+	}
+	evaluator := m.Conditions[0].Evaluator
+	var op string
+	switch evaluator.Type {
+	case expr.ThresholdIsAbove:
+		op = ">"
+	case expr.ThresholdIsBelow:
+		op = "<"
+	default:
+		return "", fmt.Errorf("threshold of type %s", evaluator.Type)
+	}
+	if len(evaluator.Params) == 0 {
+		return "", errors.New("threshold has no value")
+	}
+
+	input, m, err := prometheusRuleQuery(queries, m.Expression)
+	if err != nil {
+		return "", err
+	}
+	if expr.IsDataSource(input.DatasourceUID) {
+		// An instant vector has one sample per series, which is its last value.
+		if m.Type != "reduce" || m.Reducer != "last" || (m.Settings != nil && m.Settings.Mode != "") {
+			return "", errors.New("threshold is not of the last value of a query")
+		}
+		if _, m, err = prometheusRuleQuery(queries, m.Expression); err != nil {
+			return "", err
+		}
+	}
+	promQL, err := prometheusExprFromQuery(m)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("(%s) %s %s", promQL, op, strconv.FormatFloat(evaluator.Params[0], 'f', -1, 64)), nil
+}
+
+// prometheusRuleQuery returns the query of the rule with the given ref ID together with its model.
+func prometheusRuleQuery(queries map[string]models.AlertQuery, refID string) (models.AlertQuery, prometheusRuleQueryModel, error) {
+	q, ok := queries[refID]
+	if !ok {
+		return models.AlertQuery{}, prometheusRuleQueryModel{}, fmt.Errorf("query %s does not exist", refID)
+	}
+	var m prometheusRuleQueryModel
+	if err := json.Unmarshal(q.Model, &m); err != nil {
+		return models.AlertQuery{}, prometheusRuleQueryModel{}, fmt.Errorf("invalid model of query %s: %w", refID, err)
+	}
+	return q, m, nil
+}
+
+func prometheusExprFromQuery(m prometheusRuleQueryModel) (string, error) {
+	if strings.TrimSpace(m.Expr) == "" {
+		return "", errors.New("query has no PromQL expression")
+	}
+	return m.Expr, nil
+}
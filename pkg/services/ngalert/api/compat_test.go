@@ -2,10 +2,14 @@ package api
 
 import (
 	"testing"
+	"time"
 
+	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/components/simplejson"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 func TestToModel(t *testing.T) {
@@ -35,3 +39,109 @@ func TestToModel(t *testing.T) {
 		require.Len(t, tm.Rules, 1)
 	})
 }
+
+func TestAlertmanagerReceiversExportFromEmbeddedContactPoints(t *testing.T) {
+	settings := func(s string) *simplejson.Json {
+		j, err := simplejson.NewJson([]byte(s))
+		require.NoError(t, err)
+		return j
+	}
+	export := AlertmanagerReceiversExportFromEmbeddedContactPoints([]definitions.EmbeddedContactPoint{
+		{Name: "oncall", Type: "victorops", Settings: settings(`{"url":"https://alert.victorops.com/integrations/generic/20131114/alert/api-key/routing-key"}`)},
+		{Name: "oncall", Type: "webhook", Settings: settings(`{"url":"http://localhost","httpMethod":"PUT"}`)},
+		{Name: "oncall", Type: "webhook", Settings: settings(`{"url":"http://localhost","maxAlerts":"10","username":"user","password":"pass"}`), DisableResolveMessage: true},
+		{Name: "chat", Type: "telegram", Settings: settings(`{"bottoken":"token","chatid":"-100"}`)},
+		{Name: "chat", Type: "telegram", Settings: settings(`{"bottoken":"token","chatid":"@channel"}`)},
+		{Name: "unsupported", Type: "line", Settings: settings(`{"token":"token"}`)},
+	})
+
+	require.Len(t, export.Receivers, 3)
+	oncall := export.Receivers[0]
+	require.Equal(t, "oncall", oncall.Name)
+	require.Equal(t, []map[string]any{{
+		"api_url":       "https://alert.victorops.com/integrations/generic/20131114/alert/",
+		"api_key":       "api-key",
+		"routing_key":   "routing-key",
+		"send_resolved": true,
+	}}, oncall.VictorOpsConfigs)
+	require.Equal(t, []map[string]any{{
+		"url":        "http://localhost",
+		"max_alerts": int64(10),
+		"http_config": map[string]any{
+			"basic_auth": map[string]any{"username": "user", "password": "pass"},
+		},
+		"send_resolved": false,
+	}}, oncall.WebhookConfigs)
+	chat := export.Receivers[1]
+	require.Equal(t, []map[string]any{{"bot_token": "token", "chat_id": int64(-100), "send_resolved": true}}, chat.TelegramConfigs)
+	require.Equal(t, definitions.AlertmanagerReceiverExport{Name: "unsupported"}, export.Receivers[2])
+}
+
+func TestPrometheusRulesExportFromAlertRuleGroupWithFolderTitle(t *testing.T) {
+	query := func(refID, datasourceUID, model string) models.AlertQuery {
+		return models.AlertQuery{RefID: refID, DatasourceUID: datasourceUID, Model: []byte(model)}
+	}
+	single := models.AlertRule{
+		UID:         "single",
+		Title:       "InstanceDown",
+		Condition:   "A",
+		Data:        []models.AlertQuery{query("A", "prometheus", `{"expr":"up == 0"}`)},
+		For:         5 * time.Minute,
+		Labels:      map[string]string{"severity": "critical"},
+		Annotations: map[string]string{"summary": "{{ $labels.instance }} is down", models.DashboardUIDAnnotation: "dashboard"},
+	}
+	threshold := models.AlertRule{
+		UID:       "threshold",
+		Title:     "HighLatency",
+		Condition: "C",
+		Data: []models.AlertQuery{
+			query("A", "prometheus", `{"expr":"histogram_quantile(0.99, rate(latency_bucket[5m]))"}`),
+			query("B", "__expr__", `{"type":"reduce","expression":"A","reducer":"last"}`),
+			query("C", "__expr__", `{"type":"threshold","expression":"B","conditions":[{"evaluator":{"type":"gt","params":[0.5]}}]}`),
+		},
+	}
+	mean := models.AlertRule{
+		UID:       "mean",
+		Title:     "MeanLatency",
+		Condition: "C",
+		Data: []models.AlertQuery{
+			query("A", "prometheus", `{"expr":"rate(latency_sum[5m])"}`),
+			query("B", "__expr__", `{"type":"reduce","expression":"A","reducer":"mean"}`),
+			query("C", "__expr__", `{"type":"threshold","expression":"B","conditions":[{"evaluator":{"type":"gt","params":[1]}}]}`),
+		},
+	}
+	groups := []models.AlertRuleGroupWithFolderTitle{{
+		AlertRuleGroup: &models.AlertRuleGroup{Title: "group", Interval: 60, Rules: []models.AlertRule{single, threshold, mean}},
+		FolderTitle:    "folder",
+	}}
+
+	t.Run("rules with a single PromQL expression are exported", func(t *testing.T) {
+		export, err := PrometheusRulesExportFromAlertRuleGroupWithFolderTitle(groups, true)
+		require.NoError(t, err)
+
+		require.Len(t, export["folder"], 1)
+		group := export["folder"][0]
+		require.Equal(t, "group", group.Name)
+		require.Equal(t, model.Duration(time.Minute), group.Interval)
+		forDuration := model.Duration(5 * time.Minute)
+		require.Equal(t, []definitions.ApiRuleNode{
+			{
+				Alert:       "InstanceDown",
+				Expr:        "up == 0",
+				For:         &forDuration,
+				Labels:      map[string]string{"severity": "critical"},
+				Annotations: map[string]string{"summary": "{{ $labels.instance }} is down"},
+			},
+			{
+				Alert: "HighLatency",
+				Expr:  "(histogram_quantile(0.99, rate(latency_bucket[5m]))) > 0.5",
+			},
+		}, group.Rules)
+	})
+
+	t.Run("incompatible rules fail the export unless they are skipped", func(t *testing.T) {
+		_, err := PrometheusRulesExportFromAlertRuleGroupWithFolderTitle(groups, false)
+		require.ErrorIs(t, err, errIncompatiblePrometheusRule)
+		require.ErrorContains(t, err, "MeanLatency")
+	})
+}
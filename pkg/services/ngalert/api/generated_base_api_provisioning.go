@@ -22,30 +22,101 @@ import (
 type ProvisioningApi interface {
 	RouteDeleteAlertRule(*contextmodel.ReqContext) response.Response
 	RouteDeleteContactpoints(*contextmodel.ReqContext) response.Response
+	RouteDeleteGlobalContactpoint(*contextmodel.ReqContext) response.Response
+	RouteDeleteGlobalTemplate(*contextmodel.ReqContext) response.Response
+	RouteDeleteMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RouteDeleteMuteTiming(*contextmodel.ReqContext) response.Response
+	RouteDeleteOrgBootstrapBundle(*contextmodel.ReqContext) response.Response
+	RouteDeleteOrphanedRuleLinks(*contextmodel.ReqContext) response.Response
+	RouteDeletePolicyRoute(*contextmodel.ReqContext) response.Response
+	RouteDeleteProvisioningVariable(*contextmodel.ReqContext) response.Response
+	RouteDeleteScheduledProvisioningBundle(*contextmodel.ReqContext) response.Response
+	RouteDeleteSilence(*contextmodel.ReqContext) response.Response
 	RouteDeleteTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRule(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleExport(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleGroupEvaluation(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRuleGroupExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertRuleGroupLoad(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRules(*contextmodel.ReqContext) response.Response
 	RouteGetAlertRulesExport(*contextmodel.ReqContext) response.Response
+	RouteGetAlertingSnapshots(*contextmodel.ReqContext) response.Response
+	RouteGetAlertmanagerConfigVersions(*contextmodel.ReqContext) response.Response
+	RouteGetAllOrgsExport(*contextmodel.ReqContext) response.Response
 	RouteGetContactpoints(*contextmodel.ReqContext) response.Response
 	RouteGetContactpointsExport(*contextmodel.ReqContext) response.Response
+	RouteGetContactpointsStatus(*contextmodel.ReqContext) response.Response
+	RouteGetDeletedContactpoints(*contextmodel.ReqContext) response.Response
+	RouteGetGlobalContactpoints(*contextmodel.ReqContext) response.Response
+	RouteGetGlobalTemplates(*contextmodel.ReqContext) response.Response
+	RouteGetMaintenanceWindows(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTiming(*contextmodel.ReqContext) response.Response
+	RouteGetMuteTimingPreview(*contextmodel.ReqContext) response.Response
+	RouteGetMuteTimingUsage(*contextmodel.ReqContext) response.Response
 	RouteGetMuteTimings(*contextmodel.ReqContext) response.Response
+	RouteGetMuteTimingsExport(*contextmodel.ReqContext) response.Response
+	RouteGetOrgBootstrapBundle(*contextmodel.ReqContext) response.Response
+	RouteGetOrphanedRuleLinks(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTree(*contextmodel.ReqContext) response.Response
 	RouteGetPolicyTreeExport(*contextmodel.ReqContext) response.Response
+	RouteGetProvenances(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningAudit(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningEffectiveConfig(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningFreeze(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningHealth(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningResourceHistory(*contextmodel.ReqContext) response.Response
+	RouteGetProvisioningVariables(*contextmodel.ReqContext) response.Response
+	RouteGetScheduledProvisioningBundles(*contextmodel.ReqContext) response.Response
+	RouteGetSilence(*contextmodel.ReqContext) response.Response
+	RouteGetSilences(*contextmodel.ReqContext) response.Response
 	RouteGetTemplate(*contextmodel.ReqContext) response.Response
 	RouteGetTemplates(*contextmodel.ReqContext) response.Response
+	RouteGetTemplatesExport(*contextmodel.ReqContext) response.Response
+	RoutePatchAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePostAlertRule(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleImport(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleLint(*contextmodel.ReqContext) response.Response
+	RoutePostAlertRuleTest(*contextmodel.ReqContext) response.Response
+	RoutePostAlertingSnapshotRestore(*contextmodel.ReqContext) response.Response
+	RoutePostAlertmanagerConfigRollback(*contextmodel.ReqContext) response.Response
+	RoutePostAlertmanagerImport(*contextmodel.ReqContext) response.Response
+	RoutePostContactpointDisable(*contextmodel.ReqContext) response.Response
+	RoutePostContactpointEnable(*contextmodel.ReqContext) response.Response
+	RoutePostContactpointMigrate(*contextmodel.ReqContext) response.Response
+	RoutePostContactpointRestore(*contextmodel.ReqContext) response.Response
+	RoutePostContactpointTest(*contextmodel.ReqContext) response.Response
 	RoutePostContactpoints(*contextmodel.ReqContext) response.Response
+	RoutePostContactpointsBatch(*contextmodel.ReqContext) response.Response
+	RoutePostCopyAlertingConfig(*contextmodel.ReqContext) response.Response
+	RoutePostGlobalContactpoints(*contextmodel.ReqContext) response.Response
+	RoutePostMaintenanceWindow(*contextmodel.ReqContext) response.Response
 	RoutePostMuteTiming(*contextmodel.ReqContext) response.Response
+	RoutePostPolicyRoute(*contextmodel.ReqContext) response.Response
+	RoutePostPolicyTreeTest(*contextmodel.ReqContext) response.Response
+	RoutePostProvenanceCleanup(*contextmodel.ReqContext) response.Response
+	RoutePostProvisioningBundle(*contextmodel.ReqContext) response.Response
+	RoutePostProvisioningBundleDiff(*contextmodel.ReqContext) response.Response
+	RoutePostProvisioningBundleExport(*contextmodel.ReqContext) response.Response
+	RoutePostProvisioningBundleImport(*contextmodel.ReqContext) response.Response
+	RoutePostScheduledProvisioningBundle(*contextmodel.ReqContext) response.Response
+	RoutePostSilence(*contextmodel.ReqContext) response.Response
+	RoutePostTemplatePreview(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRule(*contextmodel.ReqContext) response.Response
 	RoutePutAlertRuleGroup(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleGroupEvaluation(*contextmodel.ReqContext) response.Response
+	RoutePutAlertRuleGroupPause(*contextmodel.ReqContext) response.Response
 	RoutePutContactpoint(*contextmodel.ReqContext) response.Response
+	RoutePutContactpointSecrets(*contextmodel.ReqContext) response.Response
+	RoutePutGlobalContactpoint(*contextmodel.ReqContext) response.Response
+	RoutePutGlobalTemplate(*contextmodel.ReqContext) response.Response
 	RoutePutMuteTiming(*contextmodel.ReqContext) response.Response
+	RoutePutOrgBootstrapBundle(*contextmodel.ReqContext) response.Response
+	RoutePutPolicyRoute(*contextmodel.ReqContext) response.Response
 	RoutePutPolicyTree(*contextmodel.ReqContext) response.Response
+	RoutePutProvisioningFreeze(*contextmodel.ReqContext) response.Response
+	RoutePutProvisioningVariable(*contextmodel.ReqContext) response.Response
+	RoutePutSilence(*contextmodel.ReqContext) response.Response
 	RoutePutTemplate(*contextmodel.ReqContext) response.Response
 	RouteResetPolicyTree(*contextmodel.ReqContext) response.Response
 }
@@ -60,11 +131,52 @@ func (f *ProvisioningApiHandler) RouteDeleteContactpoints(ctx *contextmodel.ReqC
 	uIDParam := web.Params(ctx.Req)[":UID"]
 	return f.handleRouteDeleteContactpoints(ctx, uIDParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteGlobalContactpoint(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteGlobalContactpoint(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteDeleteGlobalTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteDeleteGlobalTemplate(ctx, nameParam)
+}
+func (f *ProvisioningApiHandler) RouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteDeleteMaintenanceWindow(ctx, nameParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteDeleteMuteTiming(ctx, nameParam)
 }
+func (f *ProvisioningApiHandler) RouteDeleteOrgBootstrapBundle(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteDeleteOrgBootstrapBundle(ctx)
+}
+func (f *ProvisioningApiHandler) RouteDeleteOrphanedRuleLinks(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteDeleteOrphanedRuleLinks(ctx)
+}
+func (f *ProvisioningApiHandler) RouteDeletePolicyRoute(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeletePolicyRoute(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteDeleteProvisioningVariable(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteDeleteProvisioningVariable(ctx, nameParam)
+}
+func (f *ProvisioningApiHandler) RouteDeleteScheduledProvisioningBundle(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteScheduledProvisioningBundle(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteDeleteSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteDeleteSilence(ctx, uIDParam)
+}
 func (f *ProvisioningApiHandler) RouteDeleteTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -86,38 +198,125 @@ func (f *ProvisioningApiHandler) RouteGetAlertRuleGroup(ctx *contextmodel.ReqCon
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroup(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupEvaluation(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRouteGetAlertRuleGroupEvaluation(ctx, folderUIDParam, groupParam)
+}
 func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupExport(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
 	groupParam := web.Params(ctx.Req)[":Group"]
 	return f.handleRouteGetAlertRuleGroupExport(ctx, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertRuleGroupLoad(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	return f.handleRouteGetAlertRuleGroupLoad(ctx, folderUIDParam, groupParam)
+}
 func (f *ProvisioningApiHandler) RouteGetAlertRules(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRules(ctx)
 }
 func (f *ProvisioningApiHandler) RouteGetAlertRulesExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetAlertRulesExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetAlertingSnapshots(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertingSnapshots(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetAlertmanagerConfigVersions(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAlertmanagerConfigVersions(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetAllOrgsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetAllOrgsExport(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpoints(ctx)
 }
 func (f *ProvisioningApiHandler) RouteGetContactpointsExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetContactpointsExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetContactpointsStatus(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetContactpointsStatus(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetDeletedContactpoints(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetDeletedContactpoints(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetGlobalContactpoints(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGlobalContactpoints(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetGlobalTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetGlobalTemplates(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetMaintenanceWindows(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
 	return f.handleRouteGetMuteTiming(ctx, nameParam)
 }
+func (f *ProvisioningApiHandler) RouteGetMuteTimingPreview(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteGetMuteTimingPreview(ctx, nameParam)
+}
+func (f *ProvisioningApiHandler) RouteGetMuteTimingUsage(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	return f.handleRouteGetMuteTimingUsage(ctx, nameParam)
+}
 func (f *ProvisioningApiHandler) RouteGetMuteTimings(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetMuteTimings(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetMuteTimingsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetMuteTimingsExport(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetOrgBootstrapBundle(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetOrgBootstrapBundle(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetOrphanedRuleLinks(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetOrphanedRuleLinks(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetPolicyTree(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTree(ctx)
 }
 func (f *ProvisioningApiHandler) RouteGetPolicyTreeExport(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetPolicyTreeExport(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetProvenances(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvenances(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetProvisioningAudit(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningAudit(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetProvisioningEffectiveConfig(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningEffectiveConfig(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetProvisioningFreeze(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningFreeze(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetProvisioningHealth(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningHealth(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetProvisioningResourceHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningResourceHistory(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetProvisioningVariables(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetProvisioningVariables(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetScheduledProvisioningBundles(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetScheduledProvisioningBundles(ctx)
+}
+func (f *ProvisioningApiHandler) RouteGetSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRouteGetSilence(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RouteGetSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetSilences(ctx)
+}
 func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -126,6 +325,19 @@ func (f *ProvisioningApiHandler) RouteGetTemplate(ctx *contextmodel.ReqContext)
 func (f *ProvisioningApiHandler) RouteGetTemplates(ctx *contextmodel.ReqContext) response.Response {
 	return f.handleRouteGetTemplates(ctx)
 }
+func (f *ProvisioningApiHandler) RouteGetTemplatesExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRouteGetTemplatesExport(ctx)
+}
+func (f *ProvisioningApiHandler) RoutePatchAlertRule(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.AlertRulePatch{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePatchAlertRule(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.ProvisionedAlertRule{}
@@ -134,6 +346,79 @@ func (f *ProvisioningApiHandler) RoutePostAlertRule(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePostAlertRule(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostAlertRuleImport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertRuleImport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleImport(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleLint(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisionedAlertRule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleLint(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertRuleTest(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisionedAlertRule{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertRuleTest(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertingSnapshotRestore(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertingSnapshotRestore{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertingSnapshotRestore(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertmanagerConfigRollback(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	iDParam := web.Params(ctx.Req)[":ID"]
+	return f.handleRoutePostAlertmanagerConfigRollback(ctx, iDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostAlertmanagerImport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.AlertmanagerImport{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostAlertmanagerImport(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostContactpointDisable(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostContactpointDisable(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostContactpointEnable(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostContactpointEnable(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostContactpointMigrate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostContactpointMigrate(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostContactpointRestore(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	return f.handleRoutePostContactpointRestore(ctx, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePostContactpointTest(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ContactPointTest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostContactpointTest(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.EmbeddedContactPoint{}
@@ -142,6 +427,38 @@ func (f *ProvisioningApiHandler) RoutePostContactpoints(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePostContactpoints(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostContactpointsBatch(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ContactPoints{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostContactpointsBatch(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostCopyAlertingConfig(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.CopyAlertingConfigRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostCopyAlertingConfig(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostGlobalContactpoints(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.EmbeddedContactPoint{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostGlobalContactpoints(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostMaintenanceWindow(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.MaintenanceWindow{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostMaintenanceWindow(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.MuteTimeInterval{}
@@ -150,6 +467,81 @@ func (f *ProvisioningApiHandler) RoutePostMuteTiming(ctx *contextmodel.ReqContex
 	}
 	return f.handleRoutePostMuteTiming(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePostPolicyRoute(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.Route{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostPolicyRoute(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostPolicyTreeTest(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.RoutePolicyTest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostPolicyTreeTest(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostProvenanceCleanup(ctx *contextmodel.ReqContext) response.Response {
+	return f.handleRoutePostProvenanceCleanup(ctx)
+}
+func (f *ProvisioningApiHandler) RoutePostProvisioningBundle(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisioningBundle{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostProvisioningBundle(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostProvisioningBundleDiff(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisioningBundle{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostProvisioningBundleDiff(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostProvisioningBundleExport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisioningBundleExportRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostProvisioningBundleExport(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostProvisioningBundleImport(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisioningBundleImportRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostProvisioningBundleImport(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostScheduledProvisioningBundle(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ScheduledProvisioningBundleRequest{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostScheduledProvisioningBundle(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisionedSilence{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostSilence(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePostTemplatePreview(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.TemplatePreviewParams{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePostTemplatePreview(ctx, conf)
+}
 func (f *ProvisioningApiHandler) RoutePutAlertRule(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -171,6 +563,28 @@ func (f *ProvisioningApiHandler) RoutePutAlertRuleGroup(ctx *contextmodel.ReqCon
 	}
 	return f.handleRoutePutAlertRuleGroup(ctx, conf, folderUIDParam, groupParam)
 }
+func (f *ProvisioningApiHandler) RoutePutAlertRuleGroupEvaluation(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleGroupEvaluation{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleGroupEvaluation(ctx, conf, folderUIDParam, groupParam)
+}
+func (f *ProvisioningApiHandler) RoutePutAlertRuleGroupPause(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	folderUIDParam := web.Params(ctx.Req)[":FolderUID"]
+	groupParam := web.Params(ctx.Req)[":Group"]
+	// Parse Request Body
+	conf := apimodels.AlertRuleGroupPause{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutAlertRuleGroupPause(ctx, conf, folderUIDParam, groupParam)
+}
 func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	uIDParam := web.Params(ctx.Req)[":UID"]
@@ -181,6 +595,36 @@ func (f *ProvisioningApiHandler) RoutePutContactpoint(ctx *contextmodel.ReqConte
 	}
 	return f.handleRoutePutContactpoint(ctx, conf, uIDParam)
 }
+func (f *ProvisioningApiHandler) RoutePutContactpointSecrets(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.ContactPointSecrets{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutContactpointSecrets(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutGlobalContactpoint(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.EmbeddedContactPoint{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutGlobalContactpoint(ctx, conf, uIDParam)
+}
+func (f *ProvisioningApiHandler) RoutePutGlobalTemplate(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	// Parse Request Body
+	conf := apimodels.NotificationTemplateContent{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutGlobalTemplate(ctx, conf, nameParam)
+}
 func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -191,6 +635,24 @@ func (f *ProvisioningApiHandler) RoutePutMuteTiming(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePutMuteTiming(ctx, conf, nameParam)
 }
+func (f *ProvisioningApiHandler) RoutePutOrgBootstrapBundle(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisioningBundle{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutOrgBootstrapBundle(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePutPolicyRoute(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.Route{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutPolicyRoute(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutPolicyTree(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Request Body
 	conf := apimodels.Route{}
@@ -199,6 +661,34 @@ func (f *ProvisioningApiHandler) RoutePutPolicyTree(ctx *contextmodel.ReqContext
 	}
 	return f.handleRoutePutPolicyTree(ctx, conf)
 }
+func (f *ProvisioningApiHandler) RoutePutProvisioningFreeze(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Request Body
+	conf := apimodels.ProvisioningFreeze{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutProvisioningFreeze(ctx, conf)
+}
+func (f *ProvisioningApiHandler) RoutePutProvisioningVariable(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	nameParam := web.Params(ctx.Req)[":name"]
+	// Parse Request Body
+	conf := apimodels.ProvisioningVariableContent{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutProvisioningVariable(ctx, conf, nameParam)
+}
+func (f *ProvisioningApiHandler) RoutePutSilence(ctx *contextmodel.ReqContext) response.Response {
+	// Parse Path Parameters
+	uIDParam := web.Params(ctx.Req)[":UID"]
+	// Parse Request Body
+	conf := apimodels.ProvisionedSilence{}
+	if err := web.Bind(ctx.Req, &conf); err != nil {
+		return response.Error(http.StatusBadRequest, "bad request data", err)
+	}
+	return f.handleRoutePutSilence(ctx, conf, uIDParam)
+}
 func (f *ProvisioningApiHandler) RoutePutTemplate(ctx *contextmodel.ReqContext) response.Response {
 	// Parse Path Parameters
 	nameParam := web.Params(ctx.Req)[":name"]
@@ -238,16 +728,115 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 			),
 		)
 		group.Delete(
-			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
+			toMacaronPath("/api/v1/provisioning/global/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
-			api.authorize(http.MethodDelete, "/api/v1/provisioning/mute-timings/{name}"),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/global/contact-points/{UID}"),
 			metrics.Instrument(
 				http.MethodDelete,
-				"/api/v1/provisioning/mute-timings/{name}",
+				"/api/v1/provisioning/global/contact-points/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteGlobalContactpoint),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/global/templates/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/global/templates/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/global/templates/{name}",
+				api.Hooks.Wrap(srv.RouteDeleteGlobalTemplate),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/maintenance-windows/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/maintenance-windows/{name}",
+				api.Hooks.Wrap(srv.RouteDeleteMaintenanceWindow),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/mute-timings/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/mute-timings/{name}",
 				api.Hooks.Wrap(srv.RouteDeleteMuteTiming),
 				m,
 			),
 		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/global/bootstrap-bundle"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/global/bootstrap-bundle"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/global/bootstrap-bundle",
+				api.Hooks.Wrap(srv.RouteDeleteOrgBootstrapBundle),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/alert-rules/orphaned-links"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/alert-rules/orphaned-links"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/alert-rules/orphaned-links",
+				api.Hooks.Wrap(srv.RouteDeleteOrphanedRuleLinks),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/policies/routes/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/policies/routes/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/policies/routes/{UID}",
+				api.Hooks.Wrap(srv.RouteDeletePolicyRoute),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/variables/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/variables/{name}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/variables/{name}",
+				api.Hooks.Wrap(srv.RouteDeleteProvisioningVariable),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/bundle/scheduled/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/bundle/scheduled/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/bundle/scheduled/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteScheduledProvisioningBundle),
+				m,
+			),
+		)
+		group.Delete(
+			toMacaronPath("/api/v1/provisioning/silences/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodDelete, "/api/v1/provisioning/silences/{UID}"),
+			metrics.Instrument(
+				http.MethodDelete,
+				"/api/v1/provisioning/silences/{UID}",
+				api.Hooks.Wrap(srv.RouteDeleteSilence),
+				m,
+			),
+		)
 		group.Delete(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -292,6 +881,17 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleGroupEvaluation),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -303,6 +903,17 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/load"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/load"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/load",
+				api.Hooks.Wrap(srv.RouteGetAlertRuleGroupLoad),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -325,6 +936,39 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/snapshots"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/snapshots"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/snapshots",
+				api.Hooks.Wrap(srv.RouteGetAlertingSnapshots),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alertmanager/versions"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alertmanager/versions"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alertmanager/versions",
+				api.Hooks.Wrap(srv.RouteGetAlertmanagerConfigVersions),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/all-orgs/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/all-orgs/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/all-orgs/export",
+				api.Hooks.Wrap(srv.RouteGetAllOrgsExport),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -347,6 +991,61 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points/status"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/status"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points/status",
+				api.Hooks.Wrap(srv.RouteGetContactpointsStatus),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/contact-points/deleted"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/contact-points/deleted"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/contact-points/deleted",
+				api.Hooks.Wrap(srv.RouteGetDeletedContactpoints),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/global/contact-points"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/global/contact-points"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/global/contact-points",
+				api.Hooks.Wrap(srv.RouteGetGlobalContactpoints),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/global/templates"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/global/templates"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/global/templates",
+				api.Hooks.Wrap(srv.RouteGetGlobalTemplates),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/maintenance-windows",
+				api.Hooks.Wrap(srv.RouteGetMaintenanceWindows),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -358,6 +1057,28 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/{name}/preview"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}/preview"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/{name}/preview",
+				api.Hooks.Wrap(srv.RouteGetMuteTimingPreview),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/{name}/usage"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/{name}/usage"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/{name}/usage",
+				api.Hooks.Wrap(srv.RouteGetMuteTimingUsage),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/mute-timings"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -369,6 +1090,39 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/mute-timings/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/mute-timings/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/mute-timings/export",
+				api.Hooks.Wrap(srv.RouteGetMuteTimingsExport),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/global/bootstrap-bundle"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/global/bootstrap-bundle"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/global/bootstrap-bundle",
+				api.Hooks.Wrap(srv.RouteGetOrgBootstrapBundle),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/alert-rules/orphaned-links"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/alert-rules/orphaned-links"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/alert-rules/orphaned-links",
+				api.Hooks.Wrap(srv.RouteGetOrphanedRuleLinks),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/policies"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -391,6 +1145,116 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/provenance"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/provenance"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/provenance",
+				api.Hooks.Wrap(srv.RouteGetProvenances),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/audit"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/audit"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/audit",
+				api.Hooks.Wrap(srv.RouteGetProvisioningAudit),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/effective-config"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/effective-config"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/effective-config",
+				api.Hooks.Wrap(srv.RouteGetProvisioningEffectiveConfig),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/freeze"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/freeze"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/freeze",
+				api.Hooks.Wrap(srv.RouteGetProvisioningFreeze),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/health"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/health"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/health",
+				api.Hooks.Wrap(srv.RouteGetProvisioningHealth),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/history"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/history"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/history",
+				api.Hooks.Wrap(srv.RouteGetProvisioningResourceHistory),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/variables"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/variables"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/variables",
+				api.Hooks.Wrap(srv.RouteGetProvisioningVariables),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/bundle/scheduled"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/bundle/scheduled"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/bundle/scheduled",
+				api.Hooks.Wrap(srv.RouteGetScheduledProvisioningBundles),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/silences/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/silences/{UID}"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/silences/{UID}",
+				api.Hooks.Wrap(srv.RouteGetSilence),
+				m,
+			),
+		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/silences"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/silences"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/silences",
+				api.Hooks.Wrap(srv.RouteGetSilences),
+				m,
+			),
+		)
 		group.Get(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -413,6 +1277,28 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Get(
+			toMacaronPath("/api/v1/provisioning/templates/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodGet, "/api/v1/provisioning/templates/export"),
+			metrics.Instrument(
+				http.MethodGet,
+				"/api/v1/provisioning/templates/export",
+				api.Hooks.Wrap(srv.RouteGetTemplatesExport),
+				m,
+			),
+		)
+		group.Patch(
+			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPatch, "/api/v1/provisioning/alert-rules/{UID}"),
+			metrics.Instrument(
+				http.MethodPatch,
+				"/api/v1/provisioning/alert-rules/{UID}",
+				api.Hooks.Wrap(srv.RoutePatchAlertRule),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/alert-rules"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -424,6 +1310,127 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/import"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/import",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleImport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/lint"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/lint"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/lint",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleLint),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alert-rules/test"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alert-rules/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alert-rules/test",
+				api.Hooks.Wrap(srv.RoutePostAlertRuleTest),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/snapshots/restore"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/snapshots/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/snapshots/restore",
+				api.Hooks.Wrap(srv.RoutePostAlertingSnapshotRestore),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alertmanager/versions/{ID}/rollback"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alertmanager/versions/{ID}/rollback"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alertmanager/versions/{ID}/rollback",
+				api.Hooks.Wrap(srv.RoutePostAlertmanagerConfigRollback),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/alertmanager/import"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/alertmanager/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/alertmanager/import",
+				api.Hooks.Wrap(srv.RoutePostAlertmanagerImport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/disable"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/{UID}/disable"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/{UID}/disable",
+				api.Hooks.Wrap(srv.RoutePostContactpointDisable),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/enable"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/{UID}/enable"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/{UID}/enable",
+				api.Hooks.Wrap(srv.RoutePostContactpointEnable),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/migrate"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/{UID}/migrate"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/{UID}/migrate",
+				api.Hooks.Wrap(srv.RoutePostContactpointMigrate),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/restore"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/{UID}/restore"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/{UID}/restore",
+				api.Hooks.Wrap(srv.RoutePostContactpointRestore),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/test"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/test",
+				api.Hooks.Wrap(srv.RoutePostContactpointTest),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/contact-points"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -435,6 +1442,50 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/contact-points/batch"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/contact-points/batch"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/contact-points/batch",
+				api.Hooks.Wrap(srv.RoutePostContactpointsBatch),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/global/copy"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/global/copy"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/global/copy",
+				api.Hooks.Wrap(srv.RoutePostCopyAlertingConfig),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/global/contact-points"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/global/contact-points"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/global/contact-points",
+				api.Hooks.Wrap(srv.RoutePostGlobalContactpoints),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/maintenance-windows"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/maintenance-windows"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/maintenance-windows",
+				api.Hooks.Wrap(srv.RoutePostMaintenanceWindow),
+				m,
+			),
+		)
 		group.Post(
 			toMacaronPath("/api/v1/provisioning/mute-timings"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -446,6 +1497,116 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/routes"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/routes"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/routes",
+				api.Hooks.Wrap(srv.RoutePostPolicyRoute),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/policies/test"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/policies/test"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/policies/test",
+				api.Hooks.Wrap(srv.RoutePostPolicyTreeTest),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/provenance/cleanup"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/provenance/cleanup"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/provenance/cleanup",
+				api.Hooks.Wrap(srv.RoutePostProvenanceCleanup),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bundle"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bundle"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bundle",
+				api.Hooks.Wrap(srv.RoutePostProvisioningBundle),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bundle/diff"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bundle/diff"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bundle/diff",
+				api.Hooks.Wrap(srv.RoutePostProvisioningBundleDiff),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bundle/export"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bundle/export"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bundle/export",
+				api.Hooks.Wrap(srv.RoutePostProvisioningBundleExport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bundle/import"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bundle/import"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bundle/import",
+				api.Hooks.Wrap(srv.RoutePostProvisioningBundleImport),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/bundle/scheduled"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/bundle/scheduled"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/bundle/scheduled",
+				api.Hooks.Wrap(srv.RoutePostScheduledProvisioningBundle),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/silences"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/silences"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/silences",
+				api.Hooks.Wrap(srv.RoutePostSilence),
+				m,
+			),
+		)
+		group.Post(
+			toMacaronPath("/api/v1/provisioning/templates/preview"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPost, "/api/v1/provisioning/templates/preview"),
+			metrics.Instrument(
+				http.MethodPost,
+				"/api/v1/provisioning/templates/preview",
+				api.Hooks.Wrap(srv.RoutePostTemplatePreview),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/alert-rules/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -468,6 +1629,28 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleGroupEvaluation),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause",
+				api.Hooks.Wrap(srv.RoutePutAlertRuleGroupPause),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/contact-points/{UID}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -479,6 +1662,39 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/contact-points/{UID}/secrets"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/contact-points/{UID}/secrets"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/contact-points/{UID}/secrets",
+				api.Hooks.Wrap(srv.RoutePutContactpointSecrets),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/global/contact-points/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/global/contact-points/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/global/contact-points/{UID}",
+				api.Hooks.Wrap(srv.RoutePutGlobalContactpoint),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/global/templates/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/global/templates/{name}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/global/templates/{name}",
+				api.Hooks.Wrap(srv.RoutePutGlobalTemplate),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/mute-timings/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -490,6 +1706,28 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/global/bootstrap-bundle"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/global/bootstrap-bundle"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/global/bootstrap-bundle",
+				api.Hooks.Wrap(srv.RoutePutOrgBootstrapBundle),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/policies/routes/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/policies/routes/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/policies/routes/{UID}",
+				api.Hooks.Wrap(srv.RoutePutPolicyRoute),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/policies"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -501,6 +1739,39 @@ func (api *API) RegisterProvisioningApiEndpoints(srv ProvisioningApi, m *metrics
 				m,
 			),
 		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/freeze"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/freeze"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/freeze",
+				api.Hooks.Wrap(srv.RoutePutProvisioningFreeze),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/variables/{name}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/variables/{name}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/variables/{name}",
+				api.Hooks.Wrap(srv.RoutePutProvisioningVariable),
+				m,
+			),
+		)
+		group.Put(
+			toMacaronPath("/api/v1/provisioning/silences/{UID}"),
+			requestmeta.SetOwner(requestmeta.TeamAlerting),
+			api.authorize(http.MethodPut, "/api/v1/provisioning/silences/{UID}"),
+			metrics.Instrument(
+				http.MethodPut,
+				"/api/v1/provisioning/silences/{UID}",
+				api.Hooks.Wrap(srv.RoutePutSilence),
+				m,
+			),
+		)
 		group.Put(
 			toMacaronPath("/api/v1/provisioning/templates/{name}"),
 			requestmeta.SetOwner(requestmeta.TeamAlerting),
@@ -0,0 +1,392 @@
+// Package hcl writes Terraform configurations in the HashiCorp Configuration Language. It only supports what the
+// alerting exports need: blocks, attributes, literal values, references and function calls.
+package hcl
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Body is the content of a configuration file or a block: attributes and nested blocks, in the order they are added.
+type Body struct {
+	items []item
+}
+
+type item struct {
+	attribute *attribute
+	block     *block
+}
+
+type attribute struct {
+	name  string
+	value Value
+}
+
+type block struct {
+	typ    string
+	labels []string
+	body   *Body
+}
+
+// SetAttribute adds an attribute to the body. Nil values are left out.
+func (b *Body) SetAttribute(name string, value Value) {
+	if value == nil {
+		return
+	}
+	b.items = append(b.items, item{attribute: &attribute{name: name, value: value}})
+}
+
+// AppendBlock adds a nested block to the body and returns the body of the block.
+func (b *Body) AppendBlock(typ string, labels ...string) *Body {
+	body := &Body{}
+	b.items = append(b.items, item{block: &block{typ: typ, labels: labels, body: body}})
+	return body
+}
+
+// Append adds the attributes and blocks of another body to the body.
+func (b *Body) Append(other *Body) {
+	b.items = append(b.items, other.items...)
+}
+
+// Empty reports whether the body has no attributes and no blocks.
+func (b *Body) Empty() bool {
+	return len(b.items) == 0
+}
+
+// Bytes returns the body formatted like terraform fmt does.
+func (b *Body) Bytes() []byte {
+	w := &writer{}
+	w.body(b, 0)
+	return w.buf.Bytes()
+}
+
+// Value is the value of an attribute.
+type Value interface {
+	write(w *writer, indent int)
+}
+
+type literal string
+
+func (v literal) write(w *writer, _ int) {
+	w.buf.WriteString(string(v))
+}
+
+// String returns a string value. Strings with several lines are written as heredocs.
+func String(s string) Value {
+	return stringValue(s)
+}
+
+type stringValue string
+
+func (v stringValue) write(w *writer, indent int) {
+	s := string(v)
+	if strings.Contains(strings.TrimSuffix(s, "\n"), "\n") && !heredocDelimiterRegex.MatchString(s) {
+		w.buf.WriteString("<<EOT\n")
+		w.buf.WriteString(escapeTemplate(s))
+		if !strings.HasSuffix(s, "\n") {
+			w.buf.WriteString("\n")
+		}
+		w.buf.WriteString("EOT")
+		return
+	}
+	w.buf.WriteString(quote(s))
+}
+
+var heredocDelimiterRegex = regexp.MustCompile(`(?m)^\s*EOT\s*$`)
+
+// Int returns a number value.
+func Int(i int64) Value {
+	return literal(strconv.FormatInt(i, 10))
+}
+
+// Float returns a number value.
+func Float(f float64) Value {
+	return literal(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Bool returns a bool value.
+func Bool(b bool) Value {
+	return literal(strconv.FormatBool(b))
+}
+
+// Null returns the null value.
+func Null() Value {
+	return literal("null")
+}
+
+// Reference returns a reference to another object of the configuration, such as var.name.
+func Reference(traversal string) Value {
+	return literal(traversal)
+}
+
+// Strings returns a list of strings.
+func Strings(values []string) Value {
+	list := make([]Value, 0, len(values))
+	for _, v := range values {
+		list = append(list, String(v))
+	}
+	return List(list)
+}
+
+// List returns a list of values.
+func List(values []Value) Value {
+	return listValue(values)
+}
+
+type listValue []Value
+
+func (v listValue) write(w *writer, indent int) {
+	w.buf.WriteString("[")
+	for i, value := range v {
+		if i > 0 {
+			w.buf.WriteString(", ")
+		}
+		value.write(w, indent)
+	}
+	w.buf.WriteString("]")
+}
+
+// Object returns an object with the given attributes, written in the order of their keys.
+func Object(attributes map[string]Value) Value {
+	return objectValue(attributes)
+}
+
+// StringMap returns an object of strings.
+func StringMap(m map[string]string) Value {
+	attributes := make(map[string]Value, len(m))
+	for k, v := range m {
+		attributes[k] = String(v)
+	}
+	return Object(attributes)
+}
+
+type objectValue map[string]Value
+
+func (v objectValue) write(w *writer, indent int) {
+	if len(v) == 0 {
+		w.buf.WriteString("{}")
+		return
+	}
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	pairs := make([]pair, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, pair{key: objectKey(k), value: v[k]})
+	}
+	w.buf.WriteString("{\n")
+	w.pairs(pairs, indent+1)
+	w.indent(indent)
+	w.buf.WriteString("}")
+}
+
+// Call returns a call of a function with the given arguments, such as jsonencode.
+func Call(name string, args ...Value) Value {
+	return callValue{name: name, args: args}
+}
+
+type callValue struct {
+	name string
+	args []Value
+}
+
+func (v callValue) write(w *writer, indent int) {
+	w.buf.WriteString(v.name)
+	w.buf.WriteString("(")
+	for i, arg := range v.args {
+		if i > 0 {
+			w.buf.WriteString(", ")
+		}
+		arg.write(w, indent)
+	}
+	w.buf.WriteString(")")
+}
+
+// FromJSON returns the value of a document decoded from JSON, such as map[string]any.
+func FromJSON(v any) (Value, error) {
+	switch v := v.(type) {
+	case nil:
+		return Null(), nil
+	case string:
+		return String(v), nil
+	case bool:
+		return Bool(v), nil
+	case float64:
+		return Float(v), nil
+	case int:
+		return Int(int64(v)), nil
+	case int64:
+		return Int(v), nil
+	case []any:
+		list := make([]Value, 0, len(v))
+		for _, e := range v {
+			value, err := FromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, value)
+		}
+		return List(list), nil
+	case map[string]any:
+		attributes := make(map[string]Value, len(v))
+		for k, e := range v {
+			value, err := FromJSON(e)
+			if err != nil {
+				return nil, err
+			}
+			attributes[k] = value
+		}
+		return Object(attributes), nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %T", v)
+	}
+}
+
+var identifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+// Identifier turns a name into a valid identifier, such as the name of a resource.
+func Identifier(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune('_')
+		}
+	}
+	id := sb.String()
+	if id == "" || !identifierRegex.MatchString(id) {
+		id = "_" + id
+	}
+	return id
+}
+
+func objectKey(k string) string {
+	if identifierRegex.MatchString(k) {
+		return k
+	}
+	return quote(k)
+}
+
+func quote(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '\\':
+			sb.WriteString(`\\`)
+		case '"':
+			sb.WriteString(`\"`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\t':
+			sb.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&sb, `\u%04x`, r)
+			} else {
+				sb.WriteRune(r)
+			}
+		}
+	}
+	sb.WriteByte('"')
+	return escapeTemplate(sb.String())
+}
+
+// escapeTemplate escapes the template sequences of a string, so that it is used literally.
+func escapeTemplate(s string) string {
+	s = strings.ReplaceAll(s, "${", "$${")
+	return strings.ReplaceAll(s, "%{", "%%{")
+}
+
+type writer struct {
+	buf bytes.Buffer
+}
+
+func (w *writer) indent(level int) {
+	w.buf.WriteString(strings.Repeat("  ", level))
+}
+
+// body writes the items of a body. Blocks are separated from the items around them by an empty line.
+func (w *writer) body(b *Body, level int) {
+	for i := 0; i < len(b.items); i++ {
+		if i > 0 && (b.items[i].block != nil || b.items[i-1].block != nil) {
+			w.buf.WriteString("\n")
+		}
+		if b.items[i].block != nil {
+			w.block(b.items[i].block, level)
+			continue
+		}
+		var pairs []pair
+		for ; i < len(b.items) && b.items[i].attribute != nil; i++ {
+			pairs = append(pairs, pair{key: b.items[i].attribute.name, value: b.items[i].attribute.value})
+		}
+		w.pairs(pairs, level)
+		i--
+	}
+}
+
+type pair struct {
+	key   string
+	value Value
+}
+
+// pairs writes consecutive attributes. Like terraform fmt, the equal signs of attributes with single line values are
+// aligned, and an attribute with a value that spans several lines ends the alignment.
+func (w *writer) pairs(pairs []pair, level int) {
+	values := make([]string, 0, len(pairs))
+	for _, p := range pairs {
+		vw := &writer{}
+		p.value.write(vw, level)
+		values = append(values, vw.buf.String())
+	}
+	for i := 0; i < len(pairs); {
+		end := i
+		width := 0
+		for end < len(pairs) && !strings.Contains(values[end], "\n") {
+			if l := len(pairs[end].key); l > width {
+				width = l
+			}
+			end++
+		}
+		if end == i {
+			// A multi-line value is not aligned with the attributes around it.
+			end++
+		}
+		for ; i < end; i++ {
+			w.indent(level)
+			w.buf.WriteString(pairs[i].key)
+			if pad := width - len(pairs[i].key); pad > 0 {
+				w.buf.WriteString(strings.Repeat(" ", pad))
+			}
+			w.buf.WriteString(" = ")
+			w.buf.WriteString(values[i])
+			w.buf.WriteString("\n")
+		}
+	}
+}
+
+func (w *writer) block(b *block, level int) {
+	w.indent(level)
+	w.buf.WriteString(b.typ)
+	for _, label := range b.labels {
+		w.buf.WriteString(" ")
+		w.buf.WriteString(quote(label))
+	}
+	if b.body.Empty() {
+		w.buf.WriteString(" {}\n")
+		return
+	}
+	w.buf.WriteString(" {\n")
+	w.body(b.body, level+1)
+	w.indent(level)
+	w.buf.WriteString("}\n")
+}
@@ -0,0 +1,98 @@
+package hcl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBody(t *testing.T) {
+	body := &Body{}
+	variable := body.AppendBlock("variable", "token")
+	variable.SetAttribute("type", Reference("string"))
+	variable.SetAttribute("sensitive", Bool(true))
+	resource := body.AppendBlock("resource", "grafana_contact_point", "cp")
+	resource.SetAttribute("name", String("cp"))
+	resource.SetAttribute("skipped", nil)
+	slack := resource.AppendBlock("slack")
+	slack.SetAttribute("token", Reference("var.token"))
+	slack.SetAttribute("title", String("{{ .CommonLabels.alertname }} ${x}"))
+	slack.SetAttribute("text", String("line 1\nline 2\n"))
+	resource.SetAttribute("limits", List([]Value{Int(1), Float(1.5), Null()}))
+	resource.SetAttribute("labels", StringMap(map[string]string{"team": "a", "app.kubernetes.io/name": "b"}))
+
+	require.Equal(t, `variable "token" {
+  type      = string
+  sensitive = true
+}
+
+resource "grafana_contact_point" "cp" {
+  name = "cp"
+
+  slack {
+    token = var.token
+    title = "{{ .CommonLabels.alertname }} $${x}"
+    text = <<EOT
+line 1
+line 2
+EOT
+  }
+
+  limits = [1, 1.5, null]
+  labels = {
+    "app.kubernetes.io/name" = "b"
+    team                     = "a"
+  }
+}
+`, string(body.Bytes()))
+}
+
+func TestFromJSON(t *testing.T) {
+	value, err := FromJSON(map[string]any{
+		"expr":    "up == 0",
+		"enabled": true,
+		"refs":    []any{"A", 1.0},
+		"empty":   map[string]any{},
+	})
+	require.NoError(t, err)
+
+	body := &Body{}
+	body.SetAttribute("model", Call("jsonencode", value))
+	require.Equal(t, `model = jsonencode({
+  empty   = {}
+  enabled = true
+  expr    = "up == 0"
+  refs    = ["A", 1]
+})
+`, string(body.Bytes()))
+
+	_, err = FromJSON(struct{}{})
+	require.Error(t, err)
+}
+
+func TestString(t *testing.T) {
+	testCases := []struct {
+		in       string
+		expected string
+	}{
+		{in: `say "hi"`, expected: `"say \"hi\""`},
+		{in: `C:\path`, expected: `"C:\\path"`},
+		{in: "100%{x}", expected: `"100%%{x}"`},
+		{in: "single line\n", expected: `"single line\n"`},
+		{in: "a\nEOT\nb", expected: `"a\nEOT\nb"`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			body := &Body{}
+			body.SetAttribute("s", String(tc.in))
+			require.Equal(t, "s = "+tc.expected+"\n", string(body.Bytes()))
+		})
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	require.Equal(t, "my_contact_point", Identifier("My contact point"))
+	require.Equal(t, "_1st", Identifier("1st"))
+	require.Equal(t, "_", Identifier(""))
+	require.Equal(t, "team-a_alerts", Identifier("team-a/alerts"))
+}
@@ -0,0 +1,128 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/grafana-apiserver/builder"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/org"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// The extra attributes of the users of the apiserver that identify them in Grafana.
+const (
+	extraOrgID   = "org-id"
+	extraUserID  = "user-id"
+	extraOrgRole = "org-role"
+)
+
+var (
+	readEval             = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingProvisioningRead), ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets))
+	writeEval            = provisioning.EvalOrgProvisioningWrite()
+	contactPointReadEval = ac.EvalAny(readEval, ac.EvalPermission(ac.ActionAlertingReceiversRead))
+)
+
+// authorizer authorizes the requests of the apiserver with the same permissions as the corresponding routes of the
+// provisioning HTTP API. The apiserver itself allows all requests of Grafana users.
+type authorizer struct {
+	accessControl ac.AccessControl
+	acService     ac.Service
+	freeze        ProvisioningFreeze
+}
+
+// authorize returns the org of the namespace of the request and the user that makes it, if the user is allowed to.
+// Changes are also checked against the provisioning freeze of the org. Privileged users of the apiserver, like the
+// users of its kubeconfig, are allowed to manage the resources of all orgs.
+func (a *authorizer) authorize(ctx context.Context, gr schema.GroupResource, eval ac.Evaluator, write bool) (int64, *user.SignedInUser, error) {
+	info, ok := request.UserFrom(ctx)
+	if !ok {
+		return 0, nil, apierrors.NewUnauthorized("no user in the request")
+	}
+	orgID, err := builder.OrgIDForNamespace(request.NamespaceValue(ctx))
+	if err != nil {
+		return 0, nil, apierrors.NewBadRequest(err.Error())
+	}
+
+	var u *user.SignedInUser
+	if isPrivileged(info) {
+		u = &user.SignedInUser{OrgID: orgID, OrgRole: org.RoleAdmin, Login: info.GetName()}
+	} else {
+		u, err = a.signedInUser(ctx, info, orgID)
+		if err != nil {
+			return 0, nil, apierrors.NewForbidden(gr, "", err)
+		}
+		allowed, err := a.accessControl.Evaluate(ctx, u, eval)
+		if err != nil {
+			return 0, nil, apierrors.NewInternalError(err)
+		}
+		if !allowed {
+			return 0, nil, apierrors.NewForbidden(gr, "", fmt.Errorf("user is not authorized: requires %s", eval.GoString()))
+		}
+	}
+
+	if write && a.freeze != nil {
+		if err := a.freeze.CheckWrite(ctx, orgID, u); err != nil {
+			return 0, nil, apiErr(gr, "", err)
+		}
+	}
+	return orgID, u, nil
+}
+
+// signedInUser returns the Grafana user of an apiserver user, along with the permissions it has in the org.
+func (a *authorizer) signedInUser(ctx context.Context, info k8suser.Info, orgID int64) (*user.SignedInUser, error) {
+	userOrgID, err := extraInt(info, extraOrgID)
+	if err != nil {
+		return nil, err
+	}
+	if userOrgID != orgID {
+		return nil, fmt.Errorf("user is not signed in to the org of the namespace")
+	}
+	userID, err := extraInt(info, extraUserID)
+	if err != nil {
+		return nil, err
+	}
+	u := &user.SignedInUser{
+		UserID: userID,
+		OrgID:  orgID,
+		Login:  info.GetName(),
+	}
+	if roles := info.GetExtra()[extraOrgRole]; len(roles) > 0 {
+		u.OrgRole = org.RoleType(roles[0])
+	}
+
+	permissions, err := a.acService.GetUserPermissions(ctx, u, ac.Options{})
+	if err != nil {
+		return nil, err
+	}
+	u.Permissions = map[int64]map[string][]string{orgID: ac.GroupScopesByAction(permissions)}
+	return u, nil
+}
+
+func isPrivileged(info k8suser.Info) bool {
+	for _, group := range info.GetGroups() {
+		if group == k8suser.SystemPrivilegedGroup {
+			return true
+		}
+	}
+	return false
+}
+
+func extraInt(info k8suser.Info, key string) (int64, error) {
+	values := info.GetExtra()[key]
+	if len(values) == 0 {
+		return 0, fmt.Errorf("user has no %s", key)
+	}
+	v, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("user has an invalid %s: %w", key, err)
+	}
+	return v, nil
+}
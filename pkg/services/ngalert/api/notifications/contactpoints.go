@@ -0,0 +1,134 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type ContactPointService interface {
+	GetContactPoints(ctx context.Context, q provisioning.ContactPointQuery, u *user.SignedInUser) ([]definitions.EmbeddedContactPoint, error)
+	CreateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance) (definitions.EmbeddedContactPoint, error)
+	UpdateContactPoint(ctx context.Context, orgID int64, contactPoint definitions.EmbeddedContactPoint, p alerting_models.Provenance, opts provisioning.UpdateContactPointOptions) error
+	DeleteContactPoint(ctx context.Context, orgID int64, uid string, opts provisioning.DeleteContactPointOptions) error
+}
+
+// contactPoints serves the contact points of an org. The version of a contact point is its resource version, so
+// that changes based on an outdated contact point are rejected by the contact point service.
+type contactPoints struct {
+	svc ContactPointService
+}
+
+func (c contactPoints) list(ctx context.Context, orgID int64, u *user.SignedInUser) ([]runtime.Object, error) {
+	cps, err := c.svc.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: orgID}, u)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]runtime.Object, 0, len(cps))
+	for _, cp := range cps {
+		obj, err := contactPointToObject(cp)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func (c contactPoints) create(ctx context.Context, orgID int64, obj runtime.Object) (runtime.Object, error) {
+	cp, err := contactPointFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	created, err := c.svc.CreateContactPoint(ctx, orgID, cp, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return nil, err
+	}
+	// The created contact point holds the secure settings as they were given, they are only redacted when the contact
+	// point is read back.
+	return c.get(ctx, orgID, created.UID)
+}
+
+func (c contactPoints) update(ctx context.Context, orgID int64, obj runtime.Object, version string) (runtime.Object, error) {
+	cp, err := contactPointFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	err = c.svc.UpdateContactPoint(ctx, orgID, cp, alerting_models.ProvenanceAPI, provisioning.UpdateContactPointOptions{Version: version})
+	if err != nil {
+		return nil, err
+	}
+	return c.get(ctx, orgID, cp.UID)
+}
+
+func (c contactPoints) delete(ctx context.Context, orgID int64, name, version string) error {
+	return c.svc.DeleteContactPoint(ctx, orgID, name, provisioning.DeleteContactPointOptions{Recoverable: true, Version: version})
+}
+
+func (c contactPoints) get(ctx context.Context, orgID int64, uid string) (runtime.Object, error) {
+	objs, err := c.list(ctx, orgID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		if nameOf(obj) == uid {
+			return obj, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: contact point with uid '%s' not found", provisioning.ErrNotFound, uid)
+}
+
+func contactPointToObject(cp definitions.EmbeddedContactPoint) (*ContactPoint, error) {
+	settings := []byte("{}")
+	if cp.Settings != nil {
+		var err error
+		settings, err = cp.Settings.MarshalJSON()
+		if err != nil {
+			return nil, err
+		}
+	}
+	obj := &ContactPoint{
+		TypeMeta: metav1.TypeMeta{Kind: "ContactPoint", APIVersion: GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            cp.UID,
+			ResourceVersion: cp.Version,
+		},
+		Spec: ContactPointSpec{
+			Name:                  cp.Name,
+			Type:                  cp.Type,
+			Settings:              settings,
+			DisableResolveMessage: cp.DisableResolveMessage,
+		},
+	}
+	if obj.ResourceVersion == "" {
+		obj.ResourceVersion = contentVersion(obj.Spec)
+	}
+	setProvenance(&obj.ObjectMeta, cp.Provenance)
+	return obj, nil
+}
+
+func contactPointFromObject(obj runtime.Object) (definitions.EmbeddedContactPoint, error) {
+	cp, ok := obj.(*ContactPoint)
+	if !ok {
+		return definitions.EmbeddedContactPoint{}, fmt.Errorf("%w: expected a contact point, got %T", provisioning.ErrValidation, obj)
+	}
+	settings, err := simplejson.NewJson(cp.Spec.Settings)
+	if err != nil {
+		return definitions.EmbeddedContactPoint{}, fmt.Errorf("%w: invalid settings: %s", provisioning.ErrValidation, err)
+	}
+	return definitions.EmbeddedContactPoint{
+		UID:                   cp.Name,
+		Name:                  cp.Spec.Name,
+		Type:                  cp.Spec.Type,
+		Settings:              settings,
+		DisableResolveMessage: cp.Spec.DisableResolveMessage,
+	}, nil
+}
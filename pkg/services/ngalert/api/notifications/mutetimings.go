@@ -0,0 +1,131 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type MuteTimingService interface {
+	GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error)
+	CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
+	UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error)
+	DeleteMuteTiming(ctx context.Context, name string, orgID int64, opts provisioning.DeleteMuteTimingOptions) error
+}
+
+// muteTimings serves the mute timings of an org. The resource version of a mute timing is derived from its time
+// intervals, the mute timing service has no versions of its own.
+type muteTimings struct {
+	svc MuteTimingService
+}
+
+func (m muteTimings) list(ctx context.Context, orgID int64, _ *user.SignedInUser) ([]runtime.Object, error) {
+	mts, err := m.svc.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	objs := make([]runtime.Object, 0, len(mts))
+	for _, mt := range mts {
+		obj, err := muteTimingToObject(mt)
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, obj)
+	}
+	return objs, nil
+}
+
+func (m muteTimings) create(ctx context.Context, orgID int64, obj runtime.Object) (runtime.Object, error) {
+	mt, err := muteTimingFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	created, err := m.svc.CreateMuteTiming(ctx, mt, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return muteTimingToObject(*created)
+}
+
+func (m muteTimings) update(ctx context.Context, orgID int64, obj runtime.Object, version string) (runtime.Object, error) {
+	mt, err := muteTimingFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		if err := m.checkVersion(ctx, orgID, mt.Name, version); err != nil {
+			return nil, err
+		}
+	}
+	updated, err := m.svc.UpdateMuteTiming(ctx, mt, orgID)
+	if err != nil {
+		return nil, err
+	}
+	return muteTimingToObject(*updated)
+}
+
+func (m muteTimings) delete(ctx context.Context, orgID int64, name, version string) error {
+	if version != "" {
+		if err := m.checkVersion(ctx, orgID, name, version); err != nil {
+			return err
+		}
+	}
+	return m.svc.DeleteMuteTiming(ctx, name, orgID, provisioning.DeleteMuteTimingOptions{})
+}
+
+// checkVersion returns a version conflict if the mute timing was changed since the given version.
+func (m muteTimings) checkVersion(ctx context.Context, orgID int64, name, version string) error {
+	objs, err := m.list(ctx, orgID, nil)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		if nameOf(obj) != name {
+			continue
+		}
+		if resourceVersionOf(obj) != version {
+			return fmt.Errorf("%w: mute timing '%s' was changed since version '%s'", provisioning.ErrVersionConflict, name, version)
+		}
+		return nil
+	}
+	return apierrors.NewNotFound(MuteTimingResource, name)
+}
+
+func muteTimingToObject(mt definitions.MuteTimeInterval) (*MuteTiming, error) {
+	intervals, err := json.Marshal(mt.TimeIntervals)
+	if err != nil {
+		return nil, err
+	}
+	obj := &MuteTiming{
+		TypeMeta:   metav1.TypeMeta{Kind: "MuteTiming", APIVersion: GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Name: mt.Name},
+		Spec:       MuteTimingSpec{TimeIntervals: intervals},
+	}
+	obj.ResourceVersion = contentVersion(obj.Spec)
+	setProvenance(&obj.ObjectMeta, string(mt.Provenance))
+	return obj, nil
+}
+
+func muteTimingFromObject(obj runtime.Object) (definitions.MuteTimeInterval, error) {
+	m, ok := obj.(*MuteTiming)
+	if !ok {
+		return definitions.MuteTimeInterval{}, fmt.Errorf("%w: expected a mute timing, got %T", provisioning.ErrValidation, obj)
+	}
+	mt := definitions.MuteTimeInterval{Provenance: definitions.Provenance(alerting_models.ProvenanceAPI)}
+	mt.Name = m.Name
+	if len(m.Spec.TimeIntervals) > 0 {
+		if err := json.Unmarshal(m.Spec.TimeIntervals, &mt.TimeIntervals); err != nil {
+			return definitions.MuteTimeInterval{}, fmt.Errorf("%w: invalid time intervals: %s", provisioning.ErrValidation, err)
+		}
+	}
+	return mt, nil
+}
@@ -0,0 +1,240 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	k8suser "k8s.io/apiserver/pkg/authentication/user"
+	"k8s.io/apiserver/pkg/endpoints/request"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestTemplateStorage(t *testing.T) {
+	ctx := privilegedContext("default")
+
+	t.Run("templates can be created, read, updated and deleted", func(t *testing.T) {
+		sut, _ := createTemplateStorageSut(t, &actest.FakeService{})
+
+		created, err := sut.Create(ctx, &Template{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: TemplateSpec{Template: "content"}}, nil, nil)
+		require.NoError(t, err)
+		require.Equal(t, "a", created.(*Template).Name)
+		require.Equal(t, "default", created.(*Template).Namespace)
+		require.Equal(t, "api", created.(*Template).Annotations[ProvenanceAnnotation])
+
+		_, err = sut.Create(ctx, &Template{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: TemplateSpec{Template: "content"}}, nil, nil)
+		require.True(t, apierrors.IsAlreadyExists(err))
+
+		got, err := sut.Get(ctx, "a", nil)
+		require.NoError(t, err)
+		require.Equal(t, "content", got.(*Template).Spec.Template)
+
+		updated, _, err := sut.Update(ctx, "a", rest.DefaultUpdatedObjectInfo(&Template{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", ResourceVersion: got.(*Template).ResourceVersion},
+			Spec:       TemplateSpec{Template: "changed"},
+		}), nil, nil, false, nil)
+		require.NoError(t, err)
+		require.Equal(t, "changed", updated.(*Template).Spec.Template)
+		require.NotEqual(t, got.(*Template).ResourceVersion, updated.(*Template).ResourceVersion)
+
+		list, err := sut.List(ctx, nil)
+		require.NoError(t, err)
+		require.Len(t, list.(*TemplateList).Items, 1)
+
+		_, deleted, err := sut.Delete(ctx, "a", nil, nil)
+		require.NoError(t, err)
+		require.True(t, deleted)
+
+		_, err = sut.Get(ctx, "a", nil)
+		require.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("updates of outdated templates are rejected", func(t *testing.T) {
+		sut, svc := createTemplateStorageSut(t, &actest.FakeService{})
+		svc.templates["a"] = "content"
+
+		_, _, err := sut.Update(ctx, "a", rest.DefaultUpdatedObjectInfo(&Template{
+			ObjectMeta: metav1.ObjectMeta{Name: "a", ResourceVersion: "outdated"},
+			Spec:       TemplateSpec{Template: "changed"},
+		}), nil, nil, false, nil)
+
+		require.True(t, apierrors.IsConflict(err))
+		require.Equal(t, "content", svc.templates["a"])
+	})
+
+	t.Run("namespaces that do not belong to an org are rejected", func(t *testing.T) {
+		sut, _ := createTemplateStorageSut(t, &actest.FakeService{})
+
+		_, err := sut.List(privilegedContext("kube-system"), nil)
+
+		require.True(t, apierrors.IsBadRequest(err))
+	})
+
+	t.Run("users need the permissions of the provisioning API", func(t *testing.T) {
+		sut, _ := createTemplateStorageSut(t, &actest.FakeService{
+			ExpectedPermissions: []ac.Permission{{Action: ac.ActionAlertingProvisioningRead}},
+		})
+		userCtx := userContext("org-2", 2)
+
+		_, err := sut.List(userCtx, nil)
+		require.NoError(t, err)
+
+		_, err = sut.Create(userCtx, &Template{ObjectMeta: metav1.ObjectMeta{Name: "a"}, Spec: TemplateSpec{Template: "content"}}, nil, nil)
+		require.True(t, apierrors.IsForbidden(err))
+	})
+
+	t.Run("the provisioning permission scoped to folders does not allow changes", func(t *testing.T) {
+		acService := &actest.FakeService{
+			ExpectedPermissions: []ac.Permission{
+				{Action: ac.ActionAlertingProvisioningRead},
+				{Action: ac.ActionAlertingProvisioningWrite, Scope: "folders:uid:folder-uid"},
+			},
+		}
+		sut, svc := createTemplateStorageSut(t, acService)
+		svc.templates["a"] = "content"
+		userCtx := userContext("org-2", 2)
+
+		_, err := sut.Create(userCtx, &Template{ObjectMeta: metav1.ObjectMeta{Name: "b"}, Spec: TemplateSpec{Template: "content"}}, nil, nil)
+		require.True(t, apierrors.IsForbidden(err))
+		_, _, err = sut.Delete(userCtx, "a", nil, nil)
+		require.True(t, apierrors.IsForbidden(err))
+
+		muteTimings := NewAPIBuilder(nil, nil, nil, acimpl.ProvideAccessControl(setting.NewCfg()), acService, nil).GetStorage()[MuteTimingResource.Resource].(*storage)
+		_, err = muteTimings.Create(userCtx, &MuteTiming{ObjectMeta: metav1.ObjectMeta{Name: "mt"}}, nil, nil)
+		require.True(t, apierrors.IsForbidden(err))
+	})
+
+	t.Run("users cannot read the resources of other orgs", func(t *testing.T) {
+		sut, _ := createTemplateStorageSut(t, &actest.FakeService{
+			ExpectedPermissions: []ac.Permission{{Action: ac.ActionAlertingProvisioningRead}},
+		})
+
+		_, err := sut.List(userContext("default", 2), nil)
+
+		require.True(t, apierrors.IsForbidden(err))
+	})
+}
+
+func TestPollingWatcher(t *testing.T) {
+	states := [][]runtime.Object{
+		{testTemplate("a", "1"), testTemplate("b", "1")},
+		{testTemplate("a", "2"), testTemplate("b", "1")},
+		{testTemplate("a", "2")},
+	}
+	polls := 0
+	w := newPollingWatcher(context.Background(), time.Millisecond, func(ctx context.Context) ([]runtime.Object, error) {
+		state := states[len(states)-1]
+		if polls < len(states) {
+			state = states[polls]
+		}
+		polls++
+		return state, nil
+	})
+	defer w.Stop()
+
+	expected := []struct {
+		eventType watch.EventType
+		name      string
+	}{
+		{watch.Added, "a"},
+		{watch.Added, "b"},
+		{watch.Modified, "a"},
+		{watch.Deleted, "b"},
+	}
+	for _, e := range expected {
+		select {
+		case event := <-w.ResultChan():
+			require.Equal(t, e.eventType, event.Type)
+			require.Equal(t, e.name, nameOf(event.Object))
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for the %s event of %s", e.eventType, e.name)
+		}
+	}
+
+	w.Stop()
+	for range w.ResultChan() {
+	}
+}
+
+func TestPollingWatcherReportsErrors(t *testing.T) {
+	w := newPollingWatcher(context.Background(), time.Millisecond, func(ctx context.Context) ([]runtime.Object, error) {
+		return nil, fmt.Errorf("%w: no templates", provisioning.ErrPermissionDenied)
+	})
+	defer w.Stop()
+
+	event, ok := <-w.ResultChan()
+	require.True(t, ok)
+	require.Equal(t, watch.Error, event.Type)
+	require.IsType(t, &metav1.Status{}, event.Object)
+
+	_, ok = <-w.ResultChan()
+	require.False(t, ok)
+}
+
+func createTemplateStorageSut(t *testing.T, acService *actest.FakeService) (*storage, *fakeTemplateService) {
+	t.Helper()
+
+	svc := &fakeTemplateService{templates: map[string]string{}}
+	b := NewAPIBuilder(nil, svc, nil, acimpl.ProvideAccessControl(setting.NewCfg()), acService, nil)
+	return b.GetStorage()[TemplateResource.Resource].(*storage), svc
+}
+
+func privilegedContext(namespace string) context.Context {
+	ctx := request.WithNamespace(context.Background(), namespace)
+	return request.WithUser(ctx, &k8suser.DefaultInfo{
+		Name:   "admin",
+		Groups: []string{k8suser.SystemPrivilegedGroup},
+	})
+}
+
+func userContext(namespace string, orgID int64) context.Context {
+	ctx := request.WithNamespace(context.Background(), namespace)
+	return request.WithUser(ctx, &k8suser.DefaultInfo{
+		Name:   "1",
+		Groups: []string{"grafana"},
+		Extra: map[string][]string{
+			extraOrgID:   {fmt.Sprint(orgID)},
+			extraUserID:  {"1"},
+			extraOrgRole: {"Viewer"},
+		},
+	})
+}
+
+func testTemplate(name, content string) *Template {
+	return templateToObject(definitions.NotificationTemplate{Name: name, Template: content})
+}
+
+type fakeTemplateService struct {
+	templates map[string]string
+}
+
+func (f *fakeTemplateService) GetTemplates(_ context.Context, _ int64) (map[string]string, error) {
+	result := make(map[string]string, len(f.templates))
+	for name, tmpl := range f.templates {
+		result[name] = tmpl
+	}
+	return result, nil
+}
+
+func (f *fakeTemplateService) SetTemplate(_ context.Context, _ int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error) {
+	f.templates[tmpl.Name] = tmpl.Template
+	return tmpl, nil
+}
+
+func (f *fakeTemplateService) DeleteTemplate(_ context.Context, _ int64, name string) error {
+	delete(f.templates, name)
+	return nil
+}
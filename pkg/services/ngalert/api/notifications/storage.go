@@ -0,0 +1,325 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metainternalversion "k8s.io/apimachinery/pkg/apis/meta/internalversion"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/apiserver/pkg/registry/rest"
+
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/grafana-apiserver/builder"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// defaultWatchInterval is the interval at which watches check the resources for changes.
+const defaultWatchInterval = 10 * time.Second
+
+// resourceService adapts a provisioning service to the objects of a resource. Versions are the resource versions of
+// the objects, an empty version updates or deletes an object regardless of its current version.
+type resourceService interface {
+	list(ctx context.Context, orgID int64, u *user.SignedInUser) ([]runtime.Object, error)
+	create(ctx context.Context, orgID int64, obj runtime.Object) (runtime.Object, error)
+	update(ctx context.Context, orgID int64, obj runtime.Object, version string) (runtime.Object, error)
+	delete(ctx context.Context, orgID int64, name, version string) error
+}
+
+// storage serves a resource of the API from a provisioning service. The resources are stored in the Alertmanager
+// configurations of the orgs, so the storage has no history of changes: watches poll the resources and report the
+// differences between their states.
+type storage struct {
+	rest.TableConvertor
+
+	resource     schema.GroupResource
+	singularName string
+	newFunc      func() runtime.Object
+	newListFunc  func() runtime.Object
+
+	service   resourceService
+	auth      *authorizer
+	readEval  ac.Evaluator
+	writeEval ac.Evaluator
+
+	watchInterval time.Duration
+}
+
+var (
+	_ rest.Storage              = (*storage)(nil)
+	_ rest.Scoper               = (*storage)(nil)
+	_ rest.SingularNameProvider = (*storage)(nil)
+	_ rest.Lister               = (*storage)(nil)
+	_ rest.Getter               = (*storage)(nil)
+	_ rest.Creater              = (*storage)(nil)
+	_ rest.Updater              = (*storage)(nil)
+	_ rest.GracefulDeleter      = (*storage)(nil)
+	_ rest.Watcher              = (*storage)(nil)
+)
+
+func (s *storage) New() runtime.Object {
+	return s.newFunc()
+}
+
+func (s *storage) Destroy() {}
+
+func (s *storage) NamespaceScoped() bool {
+	return true
+}
+
+func (s *storage) GetSingularName() string {
+	return s.singularName
+}
+
+func (s *storage) NewList() runtime.Object {
+	return s.newListFunc()
+}
+
+func (s *storage) List(ctx context.Context, options *metainternalversion.ListOptions) (runtime.Object, error) {
+	orgID, u, err := s.auth.authorize(ctx, s.resource, s.readEval, false)
+	if err != nil {
+		return nil, err
+	}
+	objs, err := s.list(ctx, orgID, u, options)
+	if err != nil {
+		return nil, err
+	}
+	list := s.newListFunc()
+	if err := meta.SetList(list, objs); err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	return list, nil
+}
+
+func (s *storage) Get(ctx context.Context, name string, _ *metav1.GetOptions) (runtime.Object, error) {
+	orgID, u, err := s.auth.authorize(ctx, s.resource, s.readEval, false)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(ctx, orgID, u, name)
+}
+
+func (s *storage) Create(ctx context.Context, obj runtime.Object, createValidation rest.ValidateObjectFunc, _ *metav1.CreateOptions) (runtime.Object, error) {
+	orgID, _, err := s.auth.authorize(ctx, s.resource, s.writeEval, true)
+	if err != nil {
+		return nil, err
+	}
+	if createValidation != nil {
+		if err := createValidation(ctx, obj); err != nil {
+			return nil, err
+		}
+	}
+	created, err := s.service.create(ctx, orgID, obj)
+	if err != nil {
+		return nil, apiErr(s.resource, nameOf(obj), err)
+	}
+	if _, err := withNamespace(created, orgID); err != nil {
+		return nil, err
+	}
+	return created, nil
+}
+
+func (s *storage) Update(ctx context.Context, name string, objInfo rest.UpdatedObjectInfo, createValidation rest.ValidateObjectFunc, updateValidation rest.ValidateObjectUpdateFunc, forceAllowCreate bool, _ *metav1.UpdateOptions) (runtime.Object, bool, error) {
+	orgID, u, err := s.auth.authorize(ctx, s.resource, s.writeEval, true)
+	if err != nil {
+		return nil, false, err
+	}
+
+	old, err := s.get(ctx, orgID, u, name)
+	if err != nil {
+		if !apierrors.IsNotFound(err) || !forceAllowCreate {
+			return nil, false, err
+		}
+		obj, err := objInfo.UpdatedObject(ctx, nil)
+		if err != nil {
+			return nil, false, err
+		}
+		if createValidation != nil {
+			if err := createValidation(ctx, obj); err != nil {
+				return nil, false, err
+			}
+		}
+		created, err := s.service.create(ctx, orgID, obj)
+		if err != nil {
+			return nil, false, apiErr(s.resource, name, err)
+		}
+		if _, err := withNamespace(created, orgID); err != nil {
+			return nil, false, err
+		}
+		return created, true, nil
+	}
+
+	obj, err := objInfo.UpdatedObject(ctx, old)
+	if err != nil {
+		return nil, false, err
+	}
+	if updateValidation != nil {
+		if err := updateValidation(ctx, obj, old); err != nil {
+			return nil, false, err
+		}
+	}
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, false, apierrors.NewBadRequest(err.Error())
+	}
+	if accessor.GetName() != name {
+		return nil, false, apierrors.NewBadRequest(fmt.Sprintf("the name of the object (%s) does not match the name of the request (%s)", accessor.GetName(), name))
+	}
+	updated, err := s.service.update(ctx, orgID, obj, accessor.GetResourceVersion())
+	if err != nil {
+		return nil, false, apiErr(s.resource, name, err)
+	}
+	if _, err := withNamespace(updated, orgID); err != nil {
+		return nil, false, err
+	}
+	return updated, false, nil
+}
+
+func (s *storage) Delete(ctx context.Context, name string, deleteValidation rest.ValidateObjectFunc, options *metav1.DeleteOptions) (runtime.Object, bool, error) {
+	orgID, u, err := s.auth.authorize(ctx, s.resource, s.writeEval, true)
+	if err != nil {
+		return nil, false, err
+	}
+	old, err := s.get(ctx, orgID, u, name)
+	if err != nil {
+		return nil, false, err
+	}
+	if deleteValidation != nil {
+		if err := deleteValidation(ctx, old); err != nil {
+			return nil, false, err
+		}
+	}
+	version := ""
+	if options != nil && options.Preconditions != nil && options.Preconditions.ResourceVersion != nil {
+		version = *options.Preconditions.ResourceVersion
+	}
+	if err := s.service.delete(ctx, orgID, name, version); err != nil {
+		return nil, false, apiErr(s.resource, name, err)
+	}
+	return old, true, nil
+}
+
+// Watch reports the current objects as added, and then polls them for changes until the watch is stopped.
+func (s *storage) Watch(ctx context.Context, options *metainternalversion.ListOptions) (watch.Interface, error) {
+	orgID, u, err := s.auth.authorize(ctx, s.resource, s.readEval, false)
+	if err != nil {
+		return nil, err
+	}
+	interval := s.watchInterval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	return newPollingWatcher(ctx, interval, func(ctx context.Context) ([]runtime.Object, error) {
+		return s.list(ctx, orgID, u, options)
+	}), nil
+}
+
+// list returns the objects of the org that match the selectors of the options.
+func (s *storage) list(ctx context.Context, orgID int64, u *user.SignedInUser, options *metainternalversion.ListOptions) ([]runtime.Object, error) {
+	objs, err := s.service.list(ctx, orgID, u)
+	if err != nil {
+		return nil, apiErr(s.resource, "", err)
+	}
+	result := make([]runtime.Object, 0, len(objs))
+	for _, obj := range objs {
+		accessor, err := withNamespace(obj, orgID)
+		if err != nil {
+			return nil, err
+		}
+		if matches(accessor, options) {
+			result = append(result, obj)
+		}
+	}
+	return result, nil
+}
+
+// withNamespace sets the namespace of the org on an object returned by the service, which does not know about
+// namespaces.
+func withNamespace(obj runtime.Object, orgID int64) (metav1.Object, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return nil, apierrors.NewInternalError(err)
+	}
+	accessor.SetNamespace(builder.NamespaceForOrg(orgID))
+	return accessor, nil
+}
+
+func (s *storage) get(ctx context.Context, orgID int64, u *user.SignedInUser, name string) (runtime.Object, error) {
+	objs, err := s.list(ctx, orgID, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, obj := range objs {
+		if nameOf(obj) == name {
+			return obj, nil
+		}
+	}
+	return nil, apierrors.NewNotFound(s.resource, name)
+}
+
+// matches returns whether an object matches the label and field selectors of the options. The name and namespace
+// are the only fields that can be selected.
+func matches(obj metav1.Object, options *metainternalversion.ListOptions) bool {
+	if options == nil {
+		return true
+	}
+	if options.LabelSelector != nil && !options.LabelSelector.Matches(labels.Set(obj.GetLabels())) {
+		return false
+	}
+	if options.FieldSelector != nil && !options.FieldSelector.Matches(fields.Set{
+		"metadata.name":      obj.GetName(),
+		"metadata.namespace": obj.GetNamespace(),
+	}) {
+		return false
+	}
+	return true
+}
+
+func nameOf(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetName()
+}
+
+// apiErr converts an error of the provisioning services to an error of the apiserver.
+func apiErr(gr schema.GroupResource, name string, err error) error {
+	var statusErr apierrors.APIStatus
+	switch {
+	case errors.As(err, &statusErr):
+		return err
+	case errors.Is(err, provisioning.ErrValidation):
+		return apierrors.NewBadRequest(err.Error())
+	case errors.Is(err, provisioning.ErrNotFound),
+		errors.Is(err, store.ErrNoAlertmanagerConfiguration):
+		return apierrors.NewNotFound(gr, name)
+	case errors.Is(err, provisioning.ErrPermissionDenied),
+		errors.Is(err, provisioning.ErrQuotaReached):
+		return apierrors.NewForbidden(gr, name, err)
+	case errors.Is(err, provisioning.ErrRateLimited):
+		return apierrors.NewTooManyRequestsError(err.Error())
+	case errors.Is(err, provisioning.ErrVersionConflict),
+		errors.Is(err, store.ErrOptimisticLock),
+		errors.Is(err, store.ErrVersionLockedObjectNotFound):
+		return apierrors.NewConflict(gr, name, err)
+	case errors.Is(err, provisioning.ErrFrozen):
+		return &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusLocked,
+			Message: err.Error(),
+		}}
+	}
+	return apierrors.NewInternalError(err)
+}
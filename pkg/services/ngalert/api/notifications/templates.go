@@ -0,0 +1,126 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+type TemplateService interface {
+	GetTemplates(ctx context.Context, orgID int64) (map[string]string, error)
+	SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error)
+	DeleteTemplate(ctx context.Context, orgID int64, name string) error
+}
+
+// templates serves the notification templates of an org. The resource version of a template is derived from its
+// content, the template service has no versions of its own.
+type templates struct {
+	svc TemplateService
+}
+
+func (t templates) list(ctx context.Context, orgID int64, _ *user.SignedInUser) ([]runtime.Object, error) {
+	tmpls, err := t.svc.GetTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tmpls))
+	for name := range tmpls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	objs := make([]runtime.Object, 0, len(names))
+	for _, name := range names {
+		objs = append(objs, templateToObject(definitions.NotificationTemplate{Name: name, Template: tmpls[name]}))
+	}
+	return objs, nil
+}
+
+func (t templates) create(ctx context.Context, orgID int64, obj runtime.Object) (runtime.Object, error) {
+	tmpl, err := templateFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := t.svc.GetTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := existing[tmpl.Name]; ok {
+		return nil, apierrors.NewAlreadyExists(TemplateResource, tmpl.Name)
+	}
+	return t.set(ctx, orgID, tmpl)
+}
+
+func (t templates) update(ctx context.Context, orgID int64, obj runtime.Object, version string) (runtime.Object, error) {
+	tmpl, err := templateFromObject(obj)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := t.svc.GetTemplates(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	current, ok := existing[tmpl.Name]
+	if !ok {
+		return nil, apierrors.NewNotFound(TemplateResource, tmpl.Name)
+	}
+	if version != "" && version != templateVersion(current) {
+		return nil, fmt.Errorf("%w: template '%s' was changed since version '%s'", provisioning.ErrVersionConflict, tmpl.Name, version)
+	}
+	return t.set(ctx, orgID, tmpl)
+}
+
+func (t templates) delete(ctx context.Context, orgID int64, name, version string) error {
+	if version != "" {
+		existing, err := t.svc.GetTemplates(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		if current, ok := existing[name]; ok && templateVersion(current) != version {
+			return fmt.Errorf("%w: template '%s' was changed since version '%s'", provisioning.ErrVersionConflict, name, version)
+		}
+	}
+	return t.svc.DeleteTemplate(ctx, orgID, name)
+}
+
+func (t templates) set(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (runtime.Object, error) {
+	tmpl.Provenance = definitions.Provenance(alerting_models.ProvenanceAPI)
+	saved, err := t.svc.SetTemplate(ctx, orgID, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	return templateToObject(saved), nil
+}
+
+func templateToObject(tmpl definitions.NotificationTemplate) *Template {
+	obj := &Template{
+		TypeMeta: metav1.TypeMeta{Kind: "Template", APIVersion: GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            tmpl.Name,
+			ResourceVersion: templateVersion(tmpl.Template),
+		},
+		Spec: TemplateSpec{Template: tmpl.Template},
+	}
+	setProvenance(&obj.ObjectMeta, string(tmpl.Provenance))
+	return obj
+}
+
+func templateFromObject(obj runtime.Object) (definitions.NotificationTemplate, error) {
+	tmpl, ok := obj.(*Template)
+	if !ok {
+		return definitions.NotificationTemplate{}, fmt.Errorf("%w: expected a template, got %T", provisioning.ErrValidation, obj)
+	}
+	return definitions.NotificationTemplate{Name: tmpl.Name, Template: tmpl.Spec.Template}, nil
+}
+
+func templateVersion(content string) string {
+	return contentVersion(TemplateSpec{Template: content})
+}
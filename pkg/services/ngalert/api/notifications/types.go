@@ -0,0 +1,132 @@
+package notifications
+
+import (
+	"encoding/json"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ProvenanceAnnotation is the annotation that holds the provenance of a resource. Resources that are changed through
+// the apiserver have the API provenance.
+const ProvenanceAnnotation = "grafana.com/provenance"
+
+// ContactPoint is a single integration of a receiver. Its name is the UID of the contact point.
+type ContactPoint struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ContactPointSpec `json:"spec"`
+}
+
+type ContactPointSpec struct {
+	// Name of the receiver the integration belongs to.
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Settings of the integration as a JSON object. Secure settings are redacted.
+	Settings              json.RawMessage `json:"settings"`
+	DisableResolveMessage bool            `json:"disableResolveMessage,omitempty"`
+}
+
+type ContactPointList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []ContactPoint `json:"items"`
+}
+
+// Template is a notification template. Its name is the name of the template.
+type Template struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TemplateSpec `json:"spec"`
+}
+
+type TemplateSpec struct {
+	Template string `json:"template"`
+}
+
+type TemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []Template `json:"items"`
+}
+
+// MuteTiming is a named set of time intervals during which notifications are muted. Its name is the name of the mute
+// timing.
+type MuteTiming struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec MuteTimingSpec `json:"spec"`
+}
+
+type MuteTimingSpec struct {
+	// Time intervals as a JSON array in the format of the provisioning HTTP API.
+	TimeIntervals json.RawMessage `json:"time_intervals"`
+}
+
+type MuteTimingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []MuteTiming `json:"items"`
+}
+
+func (in *ContactPoint) DeepCopyObject() runtime.Object {
+	out := &ContactPoint{TypeMeta: in.TypeMeta, Spec: in.Spec}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.Settings = append(json.RawMessage(nil), in.Spec.Settings...)
+	return out
+}
+
+func (in *ContactPointList) DeepCopyObject() runtime.Object {
+	out := &ContactPointList{TypeMeta: in.TypeMeta}
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]ContactPoint, 0, len(in.Items))
+		for i := range in.Items {
+			out.Items = append(out.Items, *in.Items[i].DeepCopyObject().(*ContactPoint))
+		}
+	}
+	return out
+}
+
+func (in *Template) DeepCopyObject() runtime.Object {
+	out := &Template{TypeMeta: in.TypeMeta, Spec: in.Spec}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	return out
+}
+
+func (in *TemplateList) DeepCopyObject() runtime.Object {
+	out := &TemplateList{TypeMeta: in.TypeMeta}
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]Template, 0, len(in.Items))
+		for i := range in.Items {
+			out.Items = append(out.Items, *in.Items[i].DeepCopyObject().(*Template))
+		}
+	}
+	return out
+}
+
+func (in *MuteTiming) DeepCopyObject() runtime.Object {
+	out := &MuteTiming{TypeMeta: in.TypeMeta, Spec: in.Spec}
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec.TimeIntervals = append(json.RawMessage(nil), in.Spec.TimeIntervals...)
+	return out
+}
+
+func (in *MuteTimingList) DeepCopyObject() runtime.Object {
+	out := &MuteTimingList{TypeMeta: in.TypeMeta}
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		out.Items = make([]MuteTiming, 0, len(in.Items))
+		for i := range in.Items {
+			out.Items = append(out.Items, *in.Items[i].DeepCopyObject().(*MuteTiming))
+		}
+	}
+	return out
+}
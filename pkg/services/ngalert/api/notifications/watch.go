@@ -0,0 +1,121 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// pollingWatcher reports the changes of the objects of a resource by listing them at an interval and comparing their
+// resource versions with the previous listing. The objects of the first listing are reported as added.
+type pollingWatcher struct {
+	result chan watch.Event
+	cancel context.CancelFunc
+}
+
+var _ watch.Interface = (*pollingWatcher)(nil)
+
+func newPollingWatcher(ctx context.Context, interval time.Duration, list func(ctx context.Context) ([]runtime.Object, error)) *pollingWatcher {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &pollingWatcher{
+		result: make(chan watch.Event),
+		cancel: cancel,
+	}
+	go w.run(ctx, interval, list)
+	return w
+}
+
+func (w *pollingWatcher) Stop() {
+	w.cancel()
+}
+
+func (w *pollingWatcher) ResultChan() <-chan watch.Event {
+	return w.result
+}
+
+func (w *pollingWatcher) run(ctx context.Context, interval time.Duration, list func(ctx context.Context) ([]runtime.Object, error)) {
+	defer close(w.result)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	known := map[string]runtime.Object{}
+	for {
+		objs, err := list(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				w.send(ctx, watch.Event{Type: watch.Error, Object: errorStatus(err)})
+			}
+			return
+		}
+
+		current := make(map[string]runtime.Object, len(objs))
+		for _, obj := range objs {
+			name := nameOf(obj)
+			current[name] = obj
+			event := watch.Event{Object: obj}
+			if prev, ok := known[name]; !ok {
+				event.Type = watch.Added
+			} else if resourceVersionOf(prev) != resourceVersionOf(obj) {
+				event.Type = watch.Modified
+			} else {
+				continue
+			}
+			if !w.send(ctx, event) {
+				return
+			}
+		}
+
+		deleted := make([]string, 0)
+		for name := range known {
+			if _, ok := current[name]; !ok {
+				deleted = append(deleted, name)
+			}
+		}
+		sort.Strings(deleted)
+		for _, name := range deleted {
+			if !w.send(ctx, watch.Event{Type: watch.Deleted, Object: known[name]}) {
+				return
+			}
+		}
+		known = current
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// send reports an event, it returns false if the watch was stopped before the event was received.
+func (w *pollingWatcher) send(ctx context.Context, event watch.Event) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case w.result <- event:
+		return true
+	}
+}
+
+func resourceVersionOf(obj runtime.Object) string {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		return ""
+	}
+	return accessor.GetResourceVersion()
+}
+
+func errorStatus(err error) runtime.Object {
+	var statusErr apierrors.APIStatus
+	if errors.As(err, &statusErr) {
+		status := statusErr.Status()
+		return &status
+	}
+	return &apierrors.NewInternalError(err).ErrStatus
+}
@@ -24,10 +24,70 @@ func (f *ProvisioningApiHandler) handleRouteGetPolicyTreeExport(ctx *contextmode
 	return f.svc.RouteGetPolicyTreeExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningAudit(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningAudit(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningHealth(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningHealth(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningResourceHistory(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningResourceHistory(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningEffectiveConfig(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningEffectiveConfig(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertingSnapshots(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertingSnapshots(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertingSnapshotRestore(ctx *contextmodel.ReqContext, body apimodels.AlertingSnapshotRestore) response.Response {
+	return f.svc.RoutePostAlertingSnapshotRestore(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvenances(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvenances(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostProvenanceCleanup(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RoutePostProvenanceCleanup(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertmanagerConfigVersions(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAlertmanagerConfigVersions(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertmanagerConfigRollback(ctx *contextmodel.ReqContext, id string) response.Response {
+	return f.svc.RoutePostAlertmanagerConfigRollback(ctx, id)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertmanagerImport(ctx *contextmodel.ReqContext, body apimodels.AlertmanagerImport) response.Response {
+	return f.svc.RoutePostAlertmanagerImport(ctx, body)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutPolicyTree(ctx *contextmodel.ReqContext, route apimodels.Route) response.Response {
 	return f.svc.RoutePutPolicyTree(ctx, route)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostPolicyRoute(ctx *contextmodel.ReqContext, route apimodels.Route) response.Response {
+	return f.svc.RoutePostPolicyRoute(ctx, route)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostPolicyTreeTest(ctx *contextmodel.ReqContext, body apimodels.RoutePolicyTest) response.Response {
+	return f.svc.RoutePostPolicyTreeTest(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutPolicyRoute(ctx *contextmodel.ReqContext, route apimodels.Route, UID string) response.Response {
+	return f.svc.RoutePutPolicyRoute(ctx, route, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeletePolicyRoute(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeletePolicyRoute(ctx, UID)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetContactpoints(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetContactPoints(ctx)
 }
@@ -40,18 +100,102 @@ func (f *ProvisioningApiHandler) handleRoutePostContactpoints(ctx *contextmodel.
 	return f.svc.RoutePostContactPoint(ctx, cp)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostContactpointsBatch(ctx *contextmodel.ReqContext, cps apimodels.ContactPoints) response.Response {
+	return f.svc.RoutePostContactPoints(ctx, cps)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostContactpointTest(ctx *contextmodel.ReqContext, test apimodels.ContactPointTest) response.Response {
+	return f.svc.RoutePostContactPointTest(ctx, test)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutContactpoint(ctx *contextmodel.ReqContext, cp apimodels.EmbeddedContactPoint, UID string) response.Response {
 	return f.svc.RoutePutContactPoint(ctx, cp, UID)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePutContactpointSecrets(ctx *contextmodel.ReqContext, secrets apimodels.ContactPointSecrets, UID string) response.Response {
+	return f.svc.RoutePutContactPointSecrets(ctx, secrets, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostContactpointMigrate(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RoutePostContactPointMigrate(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetDeletedContactpoints(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetDeletedContactPoints(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetContactpointsStatus(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetContactPointStatus(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostContactpointEnable(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RoutePostContactPointEnable(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostContactpointDisable(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RoutePostContactPointDisable(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostContactpointRestore(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RoutePostContactPointRestore(ctx, UID)
+}
+
 func (f *ProvisioningApiHandler) handleRouteDeleteContactpoints(ctx *contextmodel.ReqContext, UID string) response.Response {
 	return f.svc.RouteDeleteContactPoint(ctx, UID)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetGlobalContactpoints(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetGlobalContactPoints(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostGlobalContactpoints(ctx *contextmodel.ReqContext, cp apimodels.EmbeddedContactPoint) response.Response {
+	return f.svc.RoutePostGlobalContactPoint(ctx, cp)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutGlobalContactpoint(ctx *contextmodel.ReqContext, cp apimodels.EmbeddedContactPoint, UID string) response.Response {
+	return f.svc.RoutePutGlobalContactPoint(ctx, cp, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteGlobalContactpoint(ctx *contextmodel.ReqContext, UID string) response.Response {
+	return f.svc.RouteDeleteGlobalContactPoint(ctx, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetGlobalTemplates(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetGlobalTemplates(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutGlobalTemplate(ctx *contextmodel.ReqContext, body apimodels.NotificationTemplateContent, name string) response.Response {
+	return f.svc.RoutePutGlobalTemplate(ctx, body, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteGlobalTemplate(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteDeleteGlobalTemplate(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetOrgBootstrapBundle(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetOrgBootstrapBundle(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutOrgBootstrapBundle(ctx *contextmodel.ReqContext, body apimodels.ProvisioningBundle) response.Response {
+	return f.svc.RoutePutOrgBootstrapBundle(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteOrgBootstrapBundle(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteDeleteOrgBootstrapBundle(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostCopyAlertingConfig(ctx *contextmodel.ReqContext, body apimodels.CopyAlertingConfigRequest) response.Response {
+	return f.svc.RoutePostCopyAlertingConfig(ctx, body)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetTemplates(ctx *contextmodel.ReqContext) response.Response {
 	return f.svc.RouteGetTemplates(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetTemplatesExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetTemplatesExport(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetTemplate(ctx *contextmodel.ReqContext, name string) response.Response {
 	return f.svc.RouteGetTemplate(ctx, name)
 }
@@ -64,6 +208,62 @@ func (f *ProvisioningApiHandler) handleRouteDeleteTemplate(ctx *contextmodel.Req
 	return f.svc.RouteDeleteTemplate(ctx, name)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostTemplatePreview(ctx *contextmodel.ReqContext, body apimodels.TemplatePreviewParams) response.Response {
+	return f.svc.RoutePostTemplatePreview(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMaintenanceWindows(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMaintenanceWindows(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostMaintenanceWindow(ctx *contextmodel.ReqContext, mw apimodels.MaintenanceWindow) response.Response {
+	return f.svc.RoutePostMaintenanceWindow(ctx, mw)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteMaintenanceWindow(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteDeleteMaintenanceWindow(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetSilences(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetSilences(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteGetSilence(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostSilence(ctx *contextmodel.ReqContext, silence apimodels.ProvisionedSilence) response.Response {
+	return f.svc.RoutePostSilence(ctx, silence)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutSilence(ctx *contextmodel.ReqContext, silence apimodels.ProvisionedSilence, uid string) response.Response {
+	return f.svc.RoutePutSilence(ctx, silence, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteSilence(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteSilence(ctx, uid)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningVariables(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningVariables(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutProvisioningVariable(ctx *contextmodel.ReqContext, body apimodels.ProvisioningVariableContent, name string) response.Response {
+	return f.svc.RoutePutProvisioningVariable(ctx, body, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteProvisioningVariable(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteDeleteProvisioningVariable(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetProvisioningFreeze(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetProvisioningFreeze(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutProvisioningFreeze(ctx *contextmodel.ReqContext, body apimodels.ProvisioningFreeze) response.Response {
+	return f.svc.RoutePutProvisioningFreeze(ctx, body)
+}
+
 func (f *ProvisioningApiHandler) handleRouteGetMuteTiming(ctx *contextmodel.ReqContext, name string) response.Response {
 	return f.svc.RouteGetMuteTiming(ctx, name)
 }
@@ -72,10 +272,50 @@ func (f *ProvisioningApiHandler) handleRouteGetMuteTimings(ctx *contextmodel.Req
 	return f.svc.RouteGetMuteTimings(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetMuteTimingsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetMuteTimingsExport(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMuteTimingPreview(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteGetMuteTimingPreview(ctx, name)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetMuteTimingUsage(ctx *contextmodel.ReqContext, name string) response.Response {
+	return f.svc.RouteGetMuteTimingUsage(ctx, name)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostMuteTiming(ctx *contextmodel.ReqContext, mt apimodels.MuteTimeInterval) response.Response {
 	return f.svc.RoutePostMuteTiming(ctx, mt)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostProvisioningBundle(ctx *contextmodel.ReqContext, bundle apimodels.ProvisioningBundle) response.Response {
+	return f.svc.RoutePostProvisioningBundle(ctx, bundle)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostProvisioningBundleDiff(ctx *contextmodel.ReqContext, bundle apimodels.ProvisioningBundle) response.Response {
+	return f.svc.RoutePostProvisioningBundleDiff(ctx, bundle)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostProvisioningBundleExport(ctx *contextmodel.ReqContext, body apimodels.ProvisioningBundleExportRequest) response.Response {
+	return f.svc.RoutePostProvisioningBundleExport(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostProvisioningBundleImport(ctx *contextmodel.ReqContext, body apimodels.ProvisioningBundleImportRequest) response.Response {
+	return f.svc.RoutePostProvisioningBundleImport(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetScheduledProvisioningBundles(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetScheduledProvisioningBundles(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostScheduledProvisioningBundle(ctx *contextmodel.ReqContext, body apimodels.ScheduledProvisioningBundleRequest) response.Response {
+	return f.svc.RoutePostScheduledProvisioningBundle(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteScheduledProvisioningBundle(ctx *contextmodel.ReqContext, uid string) response.Response {
+	return f.svc.RouteDeleteScheduledProvisioningBundle(ctx, uid)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutMuteTiming(ctx *contextmodel.ReqContext, mt apimodels.MuteTimeInterval, name string) response.Response {
 	return f.svc.RoutePutMuteTiming(ctx, mt, name)
 }
@@ -100,14 +340,42 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRulesExport(ctx *contextmode
 	return f.svc.RouteGetAlertRulesExport(ctx)
 }
 
+func (f *ProvisioningApiHandler) handleRouteGetAllOrgsExport(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetAllOrgsExport(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePostAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
 	return f.svc.RoutePostAlertRule(ctx, ar)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleImport(ctx *contextmodel.ReqContext, body apimodels.AlertRuleImport) response.Response {
+	return f.svc.RoutePostAlertRuleImport(ctx, body)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleLint(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	return f.svc.RoutePostAlertRuleLint(ctx, ar)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePostAlertRuleTest(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule) response.Response {
+	return f.svc.RoutePostAlertRuleTest(ctx, ar)
+}
+
 func (f *ProvisioningApiHandler) handleRoutePutAlertRule(ctx *contextmodel.ReqContext, ar apimodels.ProvisionedAlertRule, UID string) response.Response {
 	return f.svc.RoutePutAlertRule(ctx, ar, UID)
 }
 
+func (f *ProvisioningApiHandler) handleRoutePatchAlertRule(ctx *contextmodel.ReqContext, patch apimodels.AlertRulePatch, UID string) response.Response {
+	return f.svc.RoutePatchAlertRule(ctx, patch, UID)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetOrphanedRuleLinks(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteGetOrphanedRuleLinks(ctx)
+}
+
+func (f *ProvisioningApiHandler) handleRouteDeleteOrphanedRuleLinks(ctx *contextmodel.ReqContext) response.Response {
+	return f.svc.RouteDeleteOrphanedRuleLinks(ctx)
+}
+
 func (f *ProvisioningApiHandler) handleRouteDeleteAlertRule(ctx *contextmodel.ReqContext, UID string) response.Response {
 	return f.svc.RouteDeleteAlertRule(ctx, UID)
 }
@@ -127,3 +395,19 @@ func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupExport(ctx *context
 func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroup(ctx *contextmodel.ReqContext, ag apimodels.AlertRuleGroup, folder, group string) response.Response {
 	return f.svc.RoutePutAlertRuleGroup(ctx, ag, folder, group)
 }
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroupPause(ctx *contextmodel.ReqContext, body apimodels.AlertRuleGroupPause, folder, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupPause(ctx, body, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupEvaluation(ctx *contextmodel.ReqContext, folder, group string) response.Response {
+	return f.svc.RouteGetAlertRuleGroupEvaluation(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRouteGetAlertRuleGroupLoad(ctx *contextmodel.ReqContext, folder, group string) response.Response {
+	return f.svc.RouteGetAlertRuleGroupLoad(ctx, folder, group)
+}
+
+func (f *ProvisioningApiHandler) handleRoutePutAlertRuleGroupEvaluation(ctx *contextmodel.ReqContext, body apimodels.AlertRuleGroupEvaluation, folder, group string) response.Response {
+	return f.svc.RoutePutAlertRuleGroupEvaluation(ctx, body, folder, group)
+}
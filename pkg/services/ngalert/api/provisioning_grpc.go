@@ -0,0 +1,523 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	ac "github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/provisioningpb"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	alerting_models "github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// ProvisioningGRPCServer serves the provisioning services of contact points, notification policies, mute timings,
+// templates and alert rules over gRPC. Requests are authorized with the same permissions as the corresponding routes
+// of the provisioning HTTP API, and changes are rejected while the provisioning of the org is frozen.
+type ProvisioningGRPCServer struct {
+	provisioningpb.UnimplementedProvisioningServer
+
+	accessControl       ac.AccessControl
+	freeze              *provisioning.ProvisioningFreezeService
+	contactPointService ContactPointService
+	policies            NotificationPolicyService
+	muteTimings         MuteTimingService
+	templates           TemplateService
+	alertRules          AlertRuleService
+}
+
+// RegisterProvisioningGRPCServer registers the gRPC provisioning API with the given server.
+func (api *API) RegisterProvisioningGRPCServer(registrar grpc.ServiceRegistrar) {
+	srv := &ProvisioningGRPCServer{
+		accessControl:       api.AccessControl,
+		freeze:              api.ProvisioningFreeze,
+		contactPointService: api.ContactPointService,
+		policies:            api.Policies,
+		muteTimings:         api.MuteTimings,
+		templates:           api.Templates,
+		alertRules:          api.AlertRules,
+	}
+	provisioningpb.RegisterProvisioningServer(registrar, srv)
+}
+
+var (
+	grpcReadEval             = ac.EvalAny(ac.EvalPermission(ac.ActionAlertingProvisioningRead), ac.EvalPermission(ac.ActionAlertingProvisioningReadSecrets))
+	grpcWriteEval            = provisioning.EvalOrgProvisioningWrite()
+	grpcContactPointReadEval = ac.EvalAny(grpcReadEval, ac.EvalPermission(ac.ActionAlertingReceiversRead))
+	// The provisioning permission scoped to folders is enough to change alert rules, the service only changes the
+	// rules of the folders that the user has permissions on.
+	grpcRuleWriteEval = ac.EvalPermission(ac.ActionAlertingProvisioningWrite)
+)
+
+// authorize returns the user of the request if it is allowed to make it. Changes are also checked against the
+// provisioning freeze of the org of the user.
+func (srv *ProvisioningGRPCServer) authorize(ctx context.Context, eval ac.Evaluator, write bool) (*user.SignedInUser, error) {
+	u, err := appcontext.User(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	allowed, err := srv.accessControl.Evaluate(ctx, u, eval)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if !allowed {
+		return nil, status.Errorf(codes.PermissionDenied, "user is not authorized: requires %s", eval.GoString())
+	}
+	if write && srv.freeze != nil {
+		if err := srv.freeze.CheckWrite(ctx, u.OrgID, u); err != nil {
+			return nil, grpcErr(err)
+		}
+	}
+	return u, nil
+}
+
+// grpcErr converts an error of the provisioning services to a gRPC status error.
+func grpcErr(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := codes.Internal
+	switch {
+	case errors.Is(err, provisioning.ErrValidation),
+		errors.Is(err, alerting_models.ErrAlertRuleFailedValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, provisioning.ErrNotFound),
+		errors.Is(err, alerting_models.ErrAlertRuleNotFound),
+		errors.Is(err, store.ErrNoAlertmanagerConfiguration):
+		code = codes.NotFound
+	case errors.Is(err, provisioning.ErrPermissionDenied):
+		code = codes.PermissionDenied
+	case errors.Is(err, provisioning.ErrQuotaReached),
+		errors.Is(err, alerting_models.ErrQuotaReached),
+		errors.Is(err, provisioning.ErrRateLimited):
+		code = codes.ResourceExhausted
+	case errors.Is(err, provisioning.ErrFrozen):
+		code = codes.FailedPrecondition
+	case errors.Is(err, provisioning.ErrVersionConflict),
+		errors.Is(err, store.ErrOptimisticLock),
+		errors.Is(err, store.ErrVersionLockedObjectNotFound):
+		code = codes.Aborted
+	}
+	return status.Error(code, err.Error())
+}
+
+func (srv *ProvisioningGRPCServer) ListContactPoints(req *provisioningpb.ListContactPointsRequest, stream provisioningpb.Provisioning_ListContactPointsServer) error {
+	ctx := stream.Context()
+	u, err := srv.authorize(ctx, grpcContactPointReadEval, false)
+	if err != nil {
+		return err
+	}
+	cps, err := srv.contactPointService.StreamContactPoints(ctx, provisioning.ContactPointQuery{
+		Name:  req.Name,
+		Types: req.Types,
+		OrgID: u.OrgID,
+	}, u)
+	if err != nil {
+		return grpcErr(err)
+	}
+	return grpcErr(cps.Each(ctx, func(cp definitions.EmbeddedContactPoint) error {
+		msg, err := contactPointToProto(cp)
+		if err != nil {
+			return err
+		}
+		return stream.Send(msg)
+	}))
+}
+
+func (srv *ProvisioningGRPCServer) CreateContactPoint(ctx context.Context, req *provisioningpb.CreateContactPointRequest) (*provisioningpb.ContactPoint, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	cp, err := contactPointFromProto(req.ContactPoint)
+	if err != nil {
+		return nil, err
+	}
+	created, err := srv.contactPointService.CreateContactPoint(ctx, u.OrgID, cp, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return contactPointToProto(created)
+}
+
+func (srv *ProvisioningGRPCServer) UpdateContactPoint(ctx context.Context, req *provisioningpb.UpdateContactPointRequest) (*provisioningpb.UpdateContactPointResponse, error) {
+	u, err := srv.authorize(ctx, ac.EvalAny(provisioning.EvalOrgProvisioningWrite(), ac.EvalPermission(ac.ActionAlertingReceiversWrite)), true)
+	if err != nil {
+		return nil, err
+	}
+	cp, err := contactPointFromProto(req.ContactPoint)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.contactPointService.UpdateContactPoint(ctx, u.OrgID, cp, alerting_models.ProvenanceAPI, provisioning.UpdateContactPointOptions{})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.UpdateContactPointResponse{}, nil
+}
+
+func (srv *ProvisioningGRPCServer) DeleteContactPoint(ctx context.Context, req *provisioningpb.DeleteContactPointRequest) (*provisioningpb.DeleteContactPointResponse, error) {
+	u, err := srv.authorize(ctx, ac.EvalAny(provisioning.EvalOrgProvisioningWrite(), ac.EvalPermission(ac.ActionAlertingReceiversWrite)), true)
+	if err != nil {
+		return nil, err
+	}
+	err = srv.contactPointService.DeleteContactPoint(ctx, u.OrgID, req.Uid, provisioning.DeleteContactPointOptions{Recoverable: true})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.DeleteContactPointResponse{}, nil
+}
+
+func (srv *ProvisioningGRPCServer) GetPolicyTree(ctx context.Context, _ *provisioningpb.GetPolicyTreeRequest) (*provisioningpb.PolicyTree, error) {
+	u, err := srv.authorize(ctx, grpcReadEval, false)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := srv.policies.GetPolicyTree(ctx, u.OrgID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return policyTreeToProto(tree)
+}
+
+func (srv *ProvisioningGRPCServer) ReplacePolicyTree(ctx context.Context, req *provisioningpb.ReplacePolicyTreeRequest) (*provisioningpb.ReplacePolicyTreeResponse, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	var tree definitions.Route
+	if err := unmarshalProtoJSON(req.GetTree().GetRoute(), &tree, "route"); err != nil {
+		return nil, err
+	}
+	if _, err := srv.policies.UpdatePolicyTree(ctx, u.OrgID, tree, alerting_models.ProvenanceAPI); err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.ReplacePolicyTreeResponse{}, nil
+}
+
+func (srv *ProvisioningGRPCServer) ResetPolicyTree(ctx context.Context, _ *provisioningpb.ResetPolicyTreeRequest) (*provisioningpb.PolicyTree, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	tree, err := srv.policies.ResetPolicyTree(ctx, u.OrgID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return policyTreeToProto(tree)
+}
+
+func (srv *ProvisioningGRPCServer) ListMuteTimings(ctx context.Context, _ *provisioningpb.ListMuteTimingsRequest) (*provisioningpb.ListMuteTimingsResponse, error) {
+	u, err := srv.authorize(ctx, grpcReadEval, false)
+	if err != nil {
+		return nil, err
+	}
+	timings, err := srv.muteTimings.GetMuteTimings(ctx, u.OrgID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	resp := &provisioningpb.ListMuteTimingsResponse{MuteTimings: make([]*provisioningpb.MuteTiming, 0, len(timings))}
+	for _, timing := range timings {
+		msg, err := muteTimingToProto(timing)
+		if err != nil {
+			return nil, grpcErr(err)
+		}
+		resp.MuteTimings = append(resp.MuteTimings, msg)
+	}
+	return resp, nil
+}
+
+func (srv *ProvisioningGRPCServer) CreateMuteTiming(ctx context.Context, req *provisioningpb.CreateMuteTimingRequest) (*provisioningpb.MuteTiming, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	mt, err := muteTimingFromProto(req.MuteTiming)
+	if err != nil {
+		return nil, err
+	}
+	created, err := srv.muteTimings.CreateMuteTiming(ctx, mt, u.OrgID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return muteTimingToProto(*created)
+}
+
+func (srv *ProvisioningGRPCServer) UpdateMuteTiming(ctx context.Context, req *provisioningpb.UpdateMuteTimingRequest) (*provisioningpb.MuteTiming, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	mt, err := muteTimingFromProto(req.MuteTiming)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := srv.muteTimings.UpdateMuteTiming(ctx, mt, u.OrgID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	if updated == nil {
+		return nil, status.Errorf(codes.NotFound, "mute timing '%s' not found", mt.Name)
+	}
+	return muteTimingToProto(*updated)
+}
+
+func (srv *ProvisioningGRPCServer) DeleteMuteTiming(ctx context.Context, req *provisioningpb.DeleteMuteTimingRequest) (*provisioningpb.DeleteMuteTimingResponse, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.muteTimings.DeleteMuteTiming(ctx, req.Name, u.OrgID, provisioning.DeleteMuteTimingOptions{}); err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.DeleteMuteTimingResponse{}, nil
+}
+
+func (srv *ProvisioningGRPCServer) ListTemplates(ctx context.Context, _ *provisioningpb.ListTemplatesRequest) (*provisioningpb.ListTemplatesResponse, error) {
+	u, err := srv.authorize(ctx, grpcReadEval, false)
+	if err != nil {
+		return nil, err
+	}
+	templates, err := srv.templates.GetTemplates(ctx, u.OrgID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	resp := &provisioningpb.ListTemplatesResponse{Templates: make([]*provisioningpb.NotificationTemplate, 0, len(templates))}
+	for name, tmpl := range templates {
+		resp.Templates = append(resp.Templates, &provisioningpb.NotificationTemplate{Name: name, Template: tmpl})
+	}
+	sort.Slice(resp.Templates, func(i, j int) bool {
+		return resp.Templates[i].Name < resp.Templates[j].Name
+	})
+	return resp, nil
+}
+
+func (srv *ProvisioningGRPCServer) SetTemplate(ctx context.Context, req *provisioningpb.SetTemplateRequest) (*provisioningpb.NotificationTemplate, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	if req.Template == nil {
+		return nil, status.Error(codes.InvalidArgument, "template is required")
+	}
+	modified, err := srv.templates.SetTemplate(ctx, u.OrgID, definitions.NotificationTemplate{
+		Name:       req.Template.Name,
+		Template:   req.Template.Template,
+		Provenance: definitions.Provenance(alerting_models.ProvenanceAPI),
+	})
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.NotificationTemplate{Name: modified.Name, Template: modified.Template, Provenance: string(modified.Provenance)}, nil
+}
+
+func (srv *ProvisioningGRPCServer) DeleteTemplate(ctx context.Context, req *provisioningpb.DeleteTemplateRequest) (*provisioningpb.DeleteTemplateResponse, error) {
+	u, err := srv.authorize(ctx, grpcWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.templates.DeleteTemplate(ctx, u.OrgID, req.Name); err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.DeleteTemplateResponse{}, nil
+}
+
+func (srv *ProvisioningGRPCServer) ListAlertRules(ctx context.Context, req *provisioningpb.ListAlertRulesRequest) (*provisioningpb.ListAlertRulesResponse, error) {
+	u, err := srv.authorize(ctx, grpcReadEval, false)
+	if err != nil {
+		return nil, err
+	}
+	q := provisioning.AlertRuleQuery{OrgID: u.OrgID}
+	if req.FolderUid != "" {
+		q.FolderUIDs = []string{req.FolderUid}
+	}
+	if req.RuleGroup != "" {
+		q.RuleGroups = []string{req.RuleGroup}
+	}
+	rules, provenances, err := srv.alertRules.GetAlertRules(ctx, q)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	resp := &provisioningpb.ListAlertRulesResponse{Rules: make([]*provisioningpb.AlertRule, 0, len(rules))}
+	for _, rule := range ProvisionedAlertRuleFromAlertRules(rules, provenances) {
+		msg, err := alertRuleToProto(rule)
+		if err != nil {
+			return nil, grpcErr(err)
+		}
+		resp.Rules = append(resp.Rules, msg)
+	}
+	return resp, nil
+}
+
+func (srv *ProvisioningGRPCServer) GetAlertRule(ctx context.Context, req *provisioningpb.GetAlertRuleRequest) (*provisioningpb.AlertRule, error) {
+	u, err := srv.authorize(ctx, grpcReadEval, false)
+	if err != nil {
+		return nil, err
+	}
+	rule, provenance, err := srv.alertRules.GetAlertRule(ctx, u.OrgID, req.Uid)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return alertRuleToProto(ProvisionedAlertRuleFromAlertRule(rule, provenance))
+}
+
+func (srv *ProvisioningGRPCServer) CreateAlertRule(ctx context.Context, req *provisioningpb.CreateAlertRuleRequest) (*provisioningpb.AlertRule, error) {
+	u, err := srv.authorize(ctx, grpcRuleWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := alertRuleFromProto(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.OrgID = u.OrgID
+	created, err := srv.alertRules.CreateAlertRule(ctx, rule, alerting_models.ProvenanceAPI, u.UserID)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return alertRuleToProto(ProvisionedAlertRuleFromAlertRule(created, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningGRPCServer) UpdateAlertRule(ctx context.Context, req *provisioningpb.UpdateAlertRuleRequest) (*provisioningpb.AlertRule, error) {
+	u, err := srv.authorize(ctx, grpcRuleWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := alertRuleFromProto(req.Rule)
+	if err != nil {
+		return nil, err
+	}
+	rule.OrgID = u.OrgID
+	rule.UID = req.Uid
+	updated, err := srv.alertRules.UpdateAlertRule(ctx, rule, alerting_models.ProvenanceAPI)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return alertRuleToProto(ProvisionedAlertRuleFromAlertRule(updated, alerting_models.ProvenanceAPI))
+}
+
+func (srv *ProvisioningGRPCServer) DeleteAlertRule(ctx context.Context, req *provisioningpb.DeleteAlertRuleRequest) (*provisioningpb.DeleteAlertRuleResponse, error) {
+	u, err := srv.authorize(ctx, grpcRuleWriteEval, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := srv.alertRules.DeleteAlertRule(ctx, u.OrgID, req.Uid, alerting_models.ProvenanceAPI); err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.DeleteAlertRuleResponse{}, nil
+}
+
+// unmarshalProtoJSON decodes a JSON field of a request, or fails with an invalid argument error.
+func unmarshalProtoJSON(data []byte, v any, field string) error {
+	if len(data) == 0 {
+		return status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return status.Errorf(codes.InvalidArgument, "invalid %s: %s", field, err)
+	}
+	return nil
+}
+
+func contactPointToProto(cp definitions.EmbeddedContactPoint) (*provisioningpb.ContactPoint, error) {
+	settings, err := cp.Settings.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &provisioningpb.ContactPoint{
+		Uid:                   cp.UID,
+		Name:                  cp.Name,
+		Type:                  cp.Type,
+		Settings:              settings,
+		DisableResolveMessage: cp.DisableResolveMessage,
+		Provenance:            cp.Provenance,
+		Version:               cp.Version,
+	}, nil
+}
+
+func contactPointFromProto(msg *provisioningpb.ContactPoint) (definitions.EmbeddedContactPoint, error) {
+	if msg == nil {
+		return definitions.EmbeddedContactPoint{}, status.Error(codes.InvalidArgument, "contact point is required")
+	}
+	settings := simplejson.New()
+	if len(msg.Settings) > 0 {
+		var err error
+		if settings, err = simplejson.NewJson(msg.Settings); err != nil {
+			return definitions.EmbeddedContactPoint{}, status.Errorf(codes.InvalidArgument, "invalid settings: %s", err)
+		}
+	}
+	return definitions.EmbeddedContactPoint{
+		UID:                   msg.Uid,
+		Name:                  msg.Name,
+		Type:                  msg.Type,
+		Settings:              settings,
+		DisableResolveMessage: msg.DisableResolveMessage,
+	}, nil
+}
+
+func policyTreeToProto(tree definitions.Route) (*provisioningpb.PolicyTree, error) {
+	route, err := json.Marshal(tree)
+	if err != nil {
+		return nil, grpcErr(err)
+	}
+	return &provisioningpb.PolicyTree{Route: route}, nil
+}
+
+func muteTimingToProto(mt definitions.MuteTimeInterval) (*provisioningpb.MuteTiming, error) {
+	intervals, err := json.Marshal(mt.TimeIntervals)
+	if err != nil {
+		return nil, err
+	}
+	return &provisioningpb.MuteTiming{Name: mt.Name, TimeIntervals: intervals, Provenance: string(mt.Provenance)}, nil
+}
+
+func muteTimingFromProto(msg *provisioningpb.MuteTiming) (definitions.MuteTimeInterval, error) {
+	if msg == nil {
+		return definitions.MuteTimeInterval{}, status.Error(codes.InvalidArgument, "mute timing is required")
+	}
+	mt := definitions.MuteTimeInterval{Provenance: definitions.Provenance(alerting_models.ProvenanceAPI)}
+	mt.Name = msg.Name
+	if len(msg.TimeIntervals) > 0 {
+		if err := unmarshalProtoJSON(msg.TimeIntervals, &mt.TimeIntervals, "time intervals"); err != nil {
+			return definitions.MuteTimeInterval{}, err
+		}
+	}
+	return mt, nil
+}
+
+func alertRuleToProto(rule definitions.ProvisionedAlertRule) (*provisioningpb.AlertRule, error) {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, err
+	}
+	return &provisioningpb.AlertRule{
+		Uid:        rule.UID,
+		FolderUid:  rule.FolderUID,
+		RuleGroup:  rule.RuleGroup,
+		Title:      rule.Title,
+		Provenance: string(rule.Provenance),
+		Rule:       data,
+	}, nil
+}
+
+func alertRuleFromProto(msg *provisioningpb.AlertRule) (alerting_models.AlertRule, error) {
+	var rule definitions.ProvisionedAlertRule
+	if err := unmarshalProtoJSON(msg.GetRule(), &rule, "rule"); err != nil {
+		return alerting_models.AlertRule{}, err
+	}
+	upstream, err := AlertRuleFromProvisionedAlertRule(rule)
+	if err != nil {
+		return alerting_models.AlertRule{}, status.Error(codes.InvalidArgument, fmt.Sprintf("invalid rule: %s", err))
+	}
+	return upstream, nil
+}
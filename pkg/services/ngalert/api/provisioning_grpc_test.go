@@ -0,0 +1,169 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/provisioningpb"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestProvisioningGRPCServer(t *testing.T) {
+	ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1, UserID: 1})
+
+	t.Run("templates and mute timings are listed", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		sut := createProvisioningGRPCServerSut(t, &env)
+
+		templates, err := sut.ListTemplates(ctx, &provisioningpb.ListTemplatesRequest{})
+		require.NoError(t, err)
+		require.Len(t, templates.Templates, 1)
+		require.Equal(t, "a", templates.Templates[0].Name)
+		require.Equal(t, "template", templates.Templates[0].Template)
+
+		timings, err := sut.ListMuteTimings(ctx, &provisioningpb.ListMuteTimingsRequest{})
+		require.NoError(t, err)
+		require.Len(t, timings.MuteTimings, 1)
+		require.Equal(t, "interval", timings.MuteTimings[0].Name)
+		intervals := []any{}
+		require.NoError(t, json.Unmarshal(timings.MuteTimings[0].TimeIntervals, &intervals))
+		require.Empty(t, intervals)
+	})
+
+	t.Run("contact points are streamed one at a time", func(t *testing.T) {
+		env := createTestEnv(t, testContactPointConfig)
+		sut := createProvisioningGRPCServerSut(t, &env)
+		stream := &fakeContactPointStream{ctx: ctx}
+
+		err := sut.ListContactPoints(&provisioningpb.ListContactPointsRequest{}, stream)
+
+		require.NoError(t, err)
+		require.NotEmpty(t, stream.sent)
+		for _, cp := range stream.sent {
+			settings := map[string]any{}
+			require.NoError(t, json.Unmarshal(cp.Settings, &settings))
+			require.NotEmpty(t, cp.Uid)
+		}
+	})
+
+	t.Run("the policy tree is returned as JSON", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		sut := createProvisioningGRPCServerSut(t, &env)
+
+		tree, err := sut.GetPolicyTree(ctx, &provisioningpb.GetPolicyTreeRequest{})
+
+		require.NoError(t, err)
+		route := definitions.Route{}
+		require.NoError(t, json.Unmarshal(tree.Route, &route))
+		require.Equal(t, "some-receiver", route.Receiver)
+	})
+
+	t.Run("invalid resources are rejected with invalid argument", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		sut := createProvisioningGRPCServerSut(t, &env)
+
+		_, err := sut.CreateContactPoint(ctx, &provisioningpb.CreateContactPointRequest{
+			ContactPoint: &provisioningpb.ContactPoint{Name: "invalid", Type: "slack", Settings: []byte("{")},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = sut.SetTemplate(ctx, &provisioningpb.SetTemplateRequest{
+			Template: &provisioningpb.NotificationTemplate{Name: "empty"},
+		})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		_, err = sut.CreateAlertRule(ctx, &provisioningpb.CreateAlertRuleRequest{})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("requests without permission are rejected", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		env.ac.Callback = func(user *user.SignedInUser, evaluator accesscontrol.Evaluator) (bool, error) {
+			return false, nil
+		}
+		sut := createProvisioningGRPCServerSut(t, &env)
+
+		_, err := sut.ListMuteTimings(ctx, &provisioningpb.ListMuteTimingsRequest{})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		_, err = sut.ListMuteTimings(context.Background(), &provisioningpb.ListMuteTimingsRequest{})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("the provisioning permission scoped to folders does not allow changes other than to alert rules", func(t *testing.T) {
+		env := createTestEnv(t, testConfig)
+		sut := createProvisioningGRPCServerSut(t, &env)
+		sut.accessControl = acimpl.ProvideAccessControl(setting.NewCfg())
+		folderCtx := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1, UserID: 1, Permissions: map[int64]map[string][]string{
+			1: {accesscontrol.ActionAlertingProvisioningWrite: {"folders:uid:folder-uid"}},
+		}})
+
+		_, err := sut.CreateContactPoint(folderCtx, &provisioningpb.CreateContactPointRequest{
+			ContactPoint: &provisioningpb.ContactPoint{Name: "cp", Type: "email", Settings: []byte(`{"addresses":"a@b.c"}`)},
+		})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = sut.ReplacePolicyTree(folderCtx, &provisioningpb.ReplacePolicyTreeRequest{Tree: &provisioningpb.PolicyTree{Route: []byte(`{"receiver":"some-receiver"}`)}})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = sut.ResetPolicyTree(folderCtx, &provisioningpb.ResetPolicyTreeRequest{})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = sut.CreateMuteTiming(folderCtx, &provisioningpb.CreateMuteTimingRequest{MuteTiming: &provisioningpb.MuteTiming{Name: "mt"}})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = sut.DeleteMuteTiming(folderCtx, &provisioningpb.DeleteMuteTimingRequest{Name: "interval"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = sut.SetTemplate(folderCtx, &provisioningpb.SetTemplateRequest{Template: &provisioningpb.NotificationTemplate{Name: "b", Template: "content"}})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+		_, err = sut.DeleteTemplate(folderCtx, &provisioningpb.DeleteTemplateRequest{Name: "a"})
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+
+		// Alert rules are checked against the folders by the service.
+		_, err = sut.CreateAlertRule(folderCtx, &provisioningpb.CreateAlertRuleRequest{})
+		require.NotEqual(t, codes.PermissionDenied, status.Code(err))
+
+		orgCtx := appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: 1, UserID: 1, Permissions: map[int64]map[string][]string{
+			1: {accesscontrol.ActionAlertingProvisioningWrite: {}},
+		}})
+		_, err = sut.SetTemplate(orgCtx, &provisioningpb.SetTemplateRequest{Template: &provisioningpb.NotificationTemplate{Name: "empty"}})
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func createProvisioningGRPCServerSut(t *testing.T, env *testEnvironment) *ProvisioningGRPCServer {
+	t.Helper()
+
+	srv := createProvisioningSrvSutFromEnv(t, env)
+	return &ProvisioningGRPCServer{
+		accessControl:       env.ac,
+		contactPointService: srv.contactPointService,
+		policies:            srv.policies,
+		muteTimings:         srv.muteTimings,
+		templates:           srv.templates,
+		alertRules:          srv.alertRules,
+	}
+}
+
+// fakeContactPointStream records the contact points that are sent to the client.
+type fakeContactPointStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*provisioningpb.ContactPoint
+}
+
+func (s *fakeContactPointStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeContactPointStream) Send(cp *provisioningpb.ContactPoint) error {
+	s.sent = append(s.sent, cp)
+	return nil
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/grafana/grafana/pkg/api/response"
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// problemResponse is a response with the problem details of an error of the provisioning API.
+type problemResponse struct {
+	*response.NormalResponse
+	err error
+}
+
+// WriteTo logs the error, as error responses are logged, and writes the problem details.
+func (r problemResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	ctx.Logger.Error(r.err.Error(), "error", r.err, "status", r.Status(), "remote_addr", ctx.RemoteAddr())
+	r.NormalResponse.WriteTo(ctx)
+}
+
+// provisioningErrResp creates a response with the RFC 7807 problem details of an error of the provisioning API. The
+// code, field and resource of the problem are those of the provisioning.Error in the chain of the error, and the code
+// is derived from the status otherwise. Updates that were rejected by the rate limit of the org are reported with
+// status 429, and changes rejected because provisioning is frozen with status 423, whatever the given status.
+func provisioningErrResp(status int, err error, msg string, args ...any) response.Response {
+	if errors.Is(err, provisioning.ErrRateLimited) {
+		status = http.StatusTooManyRequests
+	}
+	if errors.Is(err, provisioning.ErrFrozen) {
+		status = http.StatusLocked
+	}
+	if msg != "" {
+		formattedMsg := fmt.Sprintf(msg, args...)
+		err = fmt.Errorf("%s: %w", formattedMsg, err)
+	}
+	problem := definitions.ProvisioningProblem{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  err.Error(),
+		Code:    string(problemCode(status)),
+		Message: err.Error(),
+	}
+	var provErr *provisioning.Error
+	if errors.As(err, &provErr) {
+		problem.Code = string(provErr.Code)
+		problem.Field = provErr.Field
+		problem.ResourceType = provErr.ResourceType
+		problem.ResourceID = provErr.ResourceID
+	}
+	return problemResponse{
+		NormalResponse: response.Respond(status, problem).SetHeader("Content-Type", "application/problem+json"),
+		err:            err,
+	}
+}
+
+func problemCode(status int) provisioning.ErrorCode {
+	switch {
+	case status == http.StatusNotFound:
+		return provisioning.ErrCodeNotFound
+	case status == http.StatusForbidden || status == http.StatusUnauthorized:
+		return provisioning.ErrCodePermissionDenied
+	case status == http.StatusConflict:
+		return provisioning.ErrCodeVersionConflict
+	case status == http.StatusTooManyRequests:
+		return provisioning.ErrCodeRateLimited
+	case status == http.StatusLocked:
+		return provisioning.ErrCodeFrozen
+	case status >= 500:
+		return provisioning.ErrCodeInternal
+	default:
+		return provisioning.ErrCodeValidation
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+func TestProvisioningErrResp(t *testing.T) {
+	problemOf := func(t *testing.T, status int, err error, msg string, args ...any) definitions.ProvisioningProblem {
+		t.Helper()
+		resp := provisioningErrResp(status, err, msg, args...)
+		problem := definitions.ProvisioningProblem{}
+		require.NoError(t, json.Unmarshal(resp.Body(), &problem))
+		require.Equal(t, resp.Status(), problem.Status)
+		return problem
+	}
+
+	t.Run("uses the code of provisioning errors", func(t *testing.T) {
+		problem := problemOf(t, http.StatusForbidden, fmt.Errorf("%w: templates", provisioning.ErrQuotaReached), "")
+
+		require.Equal(t, string(provisioning.ErrCodeQuotaReached), problem.Code)
+		require.Equal(t, "Forbidden", problem.Title)
+	})
+
+	t.Run("derives the code of other errors from the status", func(t *testing.T) {
+		problem := problemOf(t, http.StatusInternalServerError, errors.New("test error"), "failed to get %s", "templates")
+
+		require.Equal(t, string(provisioning.ErrCodeInternal), problem.Code)
+		require.Equal(t, "failed to get templates: test error", problem.Detail)
+	})
+
+	t.Run("rate limited updates have status 429", func(t *testing.T) {
+		problem := problemOf(t, http.StatusInternalServerError, provisioning.ErrRateLimited, "")
+
+		require.Equal(t, http.StatusTooManyRequests, problem.Status)
+		require.Equal(t, string(provisioning.ErrCodeRateLimited), problem.Code)
+	})
+
+	t.Run("changes rejected by a provisioning freeze have status 423", func(t *testing.T) {
+		problem := problemOf(t, http.StatusInternalServerError, provisioning.ErrFrozen, "")
+
+		require.Equal(t, http.StatusLocked, problem.Status)
+		require.Equal(t, string(provisioning.ErrCodeFrozen), problem.Code)
+	})
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+
+	jsoniter "github.com/json-iterator/go"
+
+	contextmodel "github.com/grafana/grafana/pkg/services/contexthandler/model"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// contactPointStreamFlushInterval is the number of contact points after which a streamed response is flushed to the
+// client.
+const contactPointStreamFlushInterval = 100
+
+// contactPointStreamResponse writes a list of contact points to the client one contact point at a time, so that the
+// contact points of orgs with thousands of integrations are never all converted and encoded in memory at once. The
+// response is sent with chunked encoding, as its length is not known in advance.
+type contactPointStreamResponse struct {
+	ctx    context.Context
+	stream *provisioning.ContactPointStream
+}
+
+// Status gets the response's status.
+func (r contactPointStreamResponse) Status() int {
+	return http.StatusOK
+}
+
+// Body encodes the whole list of contact points. It is only meant for callers that do not write the response.
+func (r contactPointStreamResponse) Body() []byte {
+	var buf bytes.Buffer
+	if err := r.encode(&buf, nil); err != nil {
+		return nil
+	}
+	return buf.Bytes()
+}
+
+// WriteTo writes the contact points to the client as they are converted. Once the first bytes are written, the status
+// cannot be changed anymore, so a failure is only logged and ends the response with an incomplete JSON document.
+func (r contactPointStreamResponse) WriteTo(ctx *contextmodel.ReqContext) {
+	header := ctx.Resp.Header()
+	header.Set("Content-Type", "application/json")
+	header.Set(totalCountHeader, strconv.Itoa(r.stream.Total))
+	ctx.Resp.WriteHeader(r.Status())
+	if err := r.encode(ctx.Resp, ctx.Resp); err != nil {
+		ctx.Logger.Error("Error writing contact points to response", "err", err)
+	}
+}
+
+// encode writes the contact points as a JSON array to w and flushes the flusher, if there is one, at regular intervals.
+func (r contactPointStreamResponse) encode(w io.Writer, flusher http.Flusher) error {
+	// Contact points are encoded like the other JSON responses, with a configuration that's compatible with the
+	// standard library.
+	stream := jsoniter.ConfigCompatibleWithStandardLibrary.BorrowStream(w)
+	defer jsoniter.ConfigCompatibleWithStandardLibrary.ReturnStream(stream)
+
+	stream.WriteArrayStart()
+	written := 0
+	err := r.stream.Each(r.ctx, func(cp definitions.EmbeddedContactPoint) error {
+		if written > 0 {
+			stream.WriteMore()
+		}
+		stream.WriteVal(cp)
+		if stream.Error != nil {
+			return stream.Error
+		}
+		written++
+		if written%contactPointStreamFlushInterval == 0 {
+			if err := stream.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	stream.WriteArrayEnd()
+	stream.WriteRaw("\n")
+	return stream.Flush()
+}
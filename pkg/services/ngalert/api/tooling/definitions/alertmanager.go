@@ -3,7 +3,9 @@ package definitions
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"strings"
@@ -549,6 +551,9 @@ type PostableUserConfig struct {
 	TemplateFiles      map[string]string         `yaml:"template_files" json:"template_files"`
 	AlertmanagerConfig PostableApiAlertingConfig `yaml:"alertmanager_config" json:"alertmanager_config"`
 	amSimple           map[string]interface{}    `yaml:"-" json:"-"`
+	// amRaw is the JSON of the Alertmanager configuration that amSimple is decoded from on demand if the configuration
+	// was read with DecodePostableUserConfig.
+	amRaw json.RawMessage `yaml:"-" json:"-"`
 }
 
 func (c *PostableUserConfig) UnmarshalJSON(b []byte) error {
@@ -576,6 +581,36 @@ func (c *PostableUserConfig) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// DecodePostableUserConfig reads a PostableUserConfig like UnmarshalJSON does. Instead of the generic copy of the
+// Alertmanager configuration that MarshalYAML relies on, it only keeps the JSON of the Alertmanager configuration,
+// which takes considerably less memory for large configurations, and MarshalYAML decodes the copy from it when needed.
+// The configuration is read in a single pass, so that callers with a string do not have to copy it.
+func DecodePostableUserConfig(r io.Reader) (*PostableUserConfig, error) {
+	type plain PostableUserConfig
+	c := &PostableUserConfig{}
+	tmp := struct {
+		*plain
+		AlertmanagerConfig json.RawMessage `json:"alertmanager_config"`
+	}{plain: (*plain)(c)}
+	dec := json.NewDecoder(r)
+	if err := dec.Decode(&tmp); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	if len(tmp.AlertmanagerConfig) > 0 {
+		if err := json.Unmarshal(tmp.AlertmanagerConfig, &c.AlertmanagerConfig); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	c.amRaw = tmp.AlertmanagerConfig
+	return c, nil
+}
+
 func (c *PostableUserConfig) validate() error {
 	// Taken from https://github.com/prometheus/alertmanager/blob/master/config/config.go#L170-L191
 	// Check if we have a root route. We cannot check for it in the
@@ -610,7 +645,13 @@ func (c *PostableUserConfig) GetGrafanaReceiverMap() map[string]*PostableGrafana
 
 // MarshalYAML implements yaml.Marshaller.
 func (c *PostableUserConfig) MarshalYAML() (interface{}, error) {
-	yml, err := yaml.Marshal(c.amSimple)
+	amSimple := c.amSimple
+	if amSimple == nil && c.amRaw != nil {
+		if err := json.Unmarshal(c.amRaw, &amSimple); err != nil {
+			return nil, err
+		}
+	}
+	yml, err := yaml.Marshal(amSimple)
 	if err != nil {
 		return nil, err
 	}
@@ -798,6 +839,8 @@ type Config struct {
 // A Route is a node that contains definitions of how to handle alerts. This is modified
 // from the upstream alertmanager in that it adds the ObjectMatchers property.
 type Route struct {
+	// UID identifies the route within the notification policy tree, so that it can be changed on its own.
+	UID      string `yaml:"uid,omitempty" json:"uid,omitempty"`
 	Receiver string `yaml:"receiver,omitempty" json:"receiver,omitempty"`
 
 	GroupByStr []string          `yaml:"group_by,omitempty" json:"group_by,omitempty"`
@@ -1108,6 +1151,8 @@ type GettableGrafanaReceiver struct {
 	Settings              RawMessage      `json:"settings,omitempty"`
 	SecureFields          map[string]bool `json:"secureFields"`
 	Provenance            Provenance      `json:"provenance,omitempty"`
+	Disabled              bool            `json:"disabled,omitempty"`
+	Probe                 bool            `json:"probe,omitempty"`
 }
 
 type PostableGrafanaReceiver struct {
@@ -1117,6 +1162,12 @@ type PostableGrafanaReceiver struct {
 	DisableResolveMessage bool              `json:"disableResolveMessage"`
 	Settings              RawMessage        `json:"settings,omitempty"`
 	SecureSettings        map[string]string `json:"secureSettings"`
+	// Disabled integrations do not send notifications. Integrations are disabled when they fail to send notifications
+	// too many times in a row, see the disable_contact_points_after_failures setting.
+	Disabled bool `json:"disabled,omitempty"`
+	// Probe enables periodic reachability checks of the endpoint of the integration, see the
+	// contact_point_probe_interval setting.
+	Probe bool `json:"probe,omitempty"`
 }
 
 type ReceiverType int
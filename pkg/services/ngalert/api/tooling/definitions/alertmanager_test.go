@@ -1,6 +1,7 @@
 package definitions
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"os"
@@ -1041,6 +1042,44 @@ func Test_Marshaling_Validation(t *testing.T) {
 	require.Equal(t, expected, tmp.AlertmanagerConfig.Config.Route.GroupBy)
 }
 
+func Test_DecodePostableUserConfig(t *testing.T) {
+	t.Run("decodes the same configuration as json.Unmarshal", func(t *testing.T) {
+		jsonEncoded, err := os.ReadFile("alertmanager_test_artifact.json")
+		require.NoError(t, err)
+		var expected PostableUserConfig
+		require.NoError(t, json.Unmarshal(jsonEncoded, &expected))
+
+		actual, err := DecodePostableUserConfig(bytes.NewReader(jsonEncoded))
+
+		require.NoError(t, err)
+		require.Equal(t, expected.TemplateFiles, actual.TemplateFiles)
+		require.Equal(t, expected.AlertmanagerConfig, actual.AlertmanagerConfig)
+		expectedYAML, err := yaml.Marshal(&expected)
+		require.NoError(t, err)
+		actualYAML, err := yaml.Marshal(actual)
+		require.NoError(t, err)
+		require.Equal(t, string(expectedYAML), string(actualYAML))
+	})
+
+	t.Run("rejects a configuration without route", func(t *testing.T) {
+		_, err := DecodePostableUserConfig(strings.NewReader(`{"template_files": {}}`))
+
+		require.ErrorContains(t, err, "no route provided in config")
+	})
+
+	t.Run("rejects malformed configuration", func(t *testing.T) {
+		_, err := DecodePostableUserConfig(strings.NewReader(`{"alertmanager_config": `))
+
+		require.Error(t, err)
+	})
+
+	t.Run("rejects data after the configuration", func(t *testing.T) {
+		_, err := DecodePostableUserConfig(strings.NewReader(`{"alertmanager_config": {"route": {}}} {}`))
+
+		require.Error(t, err)
+	})
+}
+
 func Test_RawMessageMarshaling(t *testing.T) {
 	type Data struct {
 		Field RawMessage `json:"field" yaml:"field"`
@@ -1,15 +1,24 @@
 package definitions
 
+// swagger:route GET /api/v1/provisioning/all-orgs/export provisioning stable RouteGetAllOrgsExport
+//
+// Export the alert rules, contact points and notification policies of all organizations in provisioning file format, with one document per organization. Secure settings of contact points are redacted. Requires the Grafana server admin role.
+//
+//     Responses:
+//       200: AlertingFileExport
+
 // AlertingFileExport is the full provisioned file export.
 // swagger:model
 type AlertingFileExport struct {
-	APIVersion    int64                      `json:"apiVersion" yaml:"apiVersion"`
-	Groups        []AlertRuleGroupExport     `json:"groups,omitempty" yaml:"groups,omitempty"`
-	ContactPoints []ContactPointExport       `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
-	Policies      []NotificationPolicyExport `json:"policies,omitempty" yaml:"policies,omitempty"`
+	APIVersion    int64                        `json:"apiVersion" yaml:"apiVersion"`
+	Groups        []AlertRuleGroupExport       `json:"groups,omitempty" yaml:"groups,omitempty"`
+	ContactPoints []ContactPointExport         `json:"contactPoints,omitempty" yaml:"contactPoints,omitempty"`
+	Policies      []NotificationPolicyExport   `json:"policies,omitempty" yaml:"policies,omitempty"`
+	MuteTimings   []MuteTimeIntervalExport     `json:"muteTimes,omitempty" yaml:"muteTimes,omitempty"`
+	Templates     []NotificationTemplateExport `json:"templates,omitempty" yaml:"templates,omitempty"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetContactpointsExport RouteGetContactpointExport
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport RouteGetContactpointsExport RouteGetContactpointExport RouteGetAllOrgsExport RouteGetMuteTimingsExport RouteGetTemplatesExport
 type ExportQueryParams struct {
 	// Whether to initiate a download of the file or not.
 	// in: query
@@ -17,7 +26,7 @@ type ExportQueryParams struct {
 	// default: false
 	Download bool `json:"download"`
 
-	// Format of the downloaded file, either yaml or json. Accept header can also be used, but the query parameter will take precedence.
+	// Format of the downloaded file, either yaml, json, hcl or k8s. The hcl format renders the resources of the Grafana Terraform provider, with secure settings of contact points as sensitive variables. The k8s format renders the custom resources of the Grafana Operator, with secure settings of contact points read from secrets. Accept header can also be used, but the query parameter will take precedence.
 	// in: query
 	// required: false
 	// default: yaml
@@ -26,7 +35,7 @@ type ExportQueryParams struct {
 
 // swagger:parameters RouteGetContactpointsExport RouteGetContactpointExport
 type DecryptQueryParams struct {
-	// Whether any contained secure settings should be decrypted or left redacted. Redacted settings will contain RedactedValue instead. Currently, only org admin can view decrypted secure settings.
+	// Whether any contained secure settings should be decrypted or left redacted. Redacted settings will contain RedactedValue instead. Decrypting requires the alert.provisioning.secrets:read permission, and every decrypted contact point is recorded in the audit log.
 	// in: query
 	// required: false
 	// default: false
@@ -0,0 +1,40 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/alert-rules/test provisioning stable RoutePostAlertRuleTest
+//
+// Evaluate an alert rule once against its data sources and return the alert instances that result. Neither the rule nor the state of its alerts is stored, and no notifications are sent.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleTestResult
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostAlertRuleTest
+type AlertRuleTestPayload struct {
+	// in:body
+	Body ProvisionedAlertRule
+}
+
+// AlertRuleTestResult holds the alert instances that result from a test evaluation of an alert rule.
+// swagger:model
+type AlertRuleTestResult struct {
+	Instances []AlertRuleTestInstance `json:"instances"`
+}
+
+// swagger:model
+type AlertRuleTestInstance struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// The state of the instance after the evaluation. As the rule has no previous state, instances of rules with a
+	// pending period are Pending instead of Alerting.
+	// example: Alerting
+	State       string `json:"state"`
+	StateReason string `json:"stateReason,omitempty"`
+	// The values of the reduce and math expressions and classic conditions of the rule by ref ID.
+	Values map[string]float64 `json:"values,omitempty"`
+	// The error of the evaluation, if the instance is in state Error.
+	Error string `json:"error,omitempty"`
+}
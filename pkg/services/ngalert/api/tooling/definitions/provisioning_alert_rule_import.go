@@ -0,0 +1,41 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/alert-rules/import provisioning stable RoutePostAlertRuleImport
+//
+// Import the alerting rules of a Prometheus or Loki rule file as Grafana-managed rules that query a data source. Either all rule groups are imported or none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: AlertRuleImportResult
+//       400: ValidationError
+
+// swagger:parameters RoutePostAlertRuleImport
+type AlertRuleImportPayload struct {
+	// in:body
+	Body AlertRuleImport
+}
+
+// AlertRuleImport is a Prometheus or Loki rule file to import.
+// swagger:model
+type AlertRuleImport struct {
+	// FolderUID is the UID of the folder the rule groups are created in.
+	// required: true
+	FolderUID string `json:"folderUid"`
+	// DatasourceUID is the UID of the data source the rules query.
+	// required: true
+	DatasourceUID string `json:"datasourceUid"`
+	// Rules is the content of the rule file.
+	// required: true
+	Rules string `json:"rules"`
+}
+
+// AlertRuleImportResult describes what was imported from a Prometheus or Loki rule file.
+// swagger:model
+type AlertRuleImportResult struct {
+	// Groups are the names of the imported rule groups.
+	Groups []string `json:"groups"`
+	// Warnings are the parts of the rule file that have no equivalent in Grafana and were left out.
+	Warnings []string `json:"warnings"`
+}
@@ -0,0 +1,31 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/alert-rules/orphaned-links provisioning stable RouteGetOrphanedRuleLinks
+//
+// Get the links of alert rules to dashboards or panels that do not exist.
+//
+//     Responses:
+//       200: OrphanedRuleLinks
+
+// swagger:route DELETE /api/v1/provisioning/alert-rules/orphaned-links provisioning stable RouteDeleteOrphanedRuleLinks
+//
+// Remove the links of alert rules to dashboards or panels that do not exist. The rules keep their provenance.
+//
+//     Responses:
+//       200: OrphanedRuleLinks
+
+// swagger:model
+type OrphanedRuleLinks []OrphanedRuleLink
+
+// OrphanedRuleLink is a link of an alert rule to a dashboard panel that does not exist.
+// swagger:model
+type OrphanedRuleLink struct {
+	RuleUID   string `json:"ruleUid"`
+	Title     string `json:"title"`
+	FolderUID string `json:"folderUid"`
+	// example: 7MeksYbmk
+	DashboardUID string `json:"dashboardUid"`
+	PanelID      int64  `json:"panelId"`
+	// Whether the dashboard does not exist. Otherwise, only the panel is missing from the dashboard.
+	DashboardMissing bool `json:"dashboardMissing"`
+}
@@ -0,0 +1,34 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/alert-rules/lint provisioning stable RoutePostAlertRuleLint
+//
+// Check an alert rule for common problems that do not make it invalid. The rule is not stored.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleLintResult
+//       400: ValidationError
+
+// swagger:parameters RoutePostAlertRuleLint
+type AlertRuleLintPayload struct {
+	// in:body
+	Body ProvisionedAlertRule
+}
+
+// AlertRuleLintResult holds the problems found in an alert rule, sorted by severity and code.
+// swagger:model
+type AlertRuleLintResult struct {
+	Findings []AlertRuleLintFinding `json:"findings"`
+}
+
+// swagger:model
+type AlertRuleLintFinding struct {
+	// The check that found the problem.
+	// example: no-for-duration
+	Code string `json:"code"`
+	// enum: error,warning,info
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
@@ -8,17 +8,19 @@ import (
 
 // swagger:route GET /api/v1/provisioning/alert-rules provisioning stable RouteGetAlertRules
 //
-// Get all the alert rules.
+// Get all the alert rules, optionally filtered.
 //
 //     Responses:
 //       200: ProvisionedAlertRules
+//       400: ValidationError
 
 // swagger:route GET /api/v1/provisioning/alert-rules/export provisioning stable RouteGetAlertRulesExport
 //
-// Export all alert rules in provisioning file format.
+// Export all alert rules in provisioning file format, or as Prometheus rule groups.
 //
 //     Responses:
 //       200: AlertingFileExport
+//       400: ValidationError
 //       404: description: Not found.
 
 // swagger:route GET /api/v1/provisioning/alert-rules/{UID} provisioning stable RouteGetAlertRule
@@ -31,7 +33,7 @@ import (
 
 // swagger:route GET /api/v1/provisioning/alert-rules/{UID}/export provisioning stable RouteGetAlertRuleExport
 //
-// Export an alert rule in provisioning file format.
+// Export an alert rule in provisioning file format, or as a Prometheus rule group.
 //
 //     Produces:
 //     - application/json
@@ -40,6 +42,7 @@ import (
 //
 //     Responses:
 //       200: AlertingFileExport
+//       400: ValidationError
 //       404: description: Not found.
 
 // swagger:route POST /api/v1/provisioning/alert-rules provisioning stable RoutePostAlertRule
@@ -64,6 +67,18 @@ import (
 //       200: ProvisionedAlertRule
 //       400: ValidationError
 
+// swagger:route PATCH /api/v1/provisioning/alert-rules/{UID} provisioning stable RoutePatchAlertRule
+//
+// Update the given fields of an existing alert rule and keep all others.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisionedAlertRule
+//       400: ValidationError
+//       404: description: Not found.
+
 // swagger:route DELETE /api/v1/provisioning/alert-rules/{UID} provisioning stable RouteDeleteAlertRule
 //
 // Delete a specific alert rule by UID.
@@ -71,7 +86,36 @@ import (
 //     Responses:
 //       204: description: The alert rule was deleted successfully.
 
-// swagger:parameters RouteGetAlertRule RoutePutAlertRule RouteDeleteAlertRule RouteGetAlertRuleExport
+// swagger:parameters RouteGetAlertRules RouteGetAlertRulesExport
+type AlertRuleListParams struct {
+	// Filter by a case-insensitive substring of the title.
+	// in: query
+	// required: false
+	Title string `json:"title"`
+	// Filter by label matchers in the text format of the Alertmanager, for example severity=~"critical|major". Rules
+	// whose labels match all matchers are returned.
+	// in: query
+	// required: false
+	Label []string `json:"label"`
+	// Filter by data source UID. Rules that query any of the given data sources are returned.
+	// in: query
+	// required: false
+	DatasourceUID []string `json:"datasourceUid"`
+	// Filter by whether the rule is paused.
+	// in: query
+	// required: false
+	Paused *bool `json:"paused"`
+	// Filter by folder UID. Rules in any of the given folders are returned.
+	// in: query
+	// required: false
+	FolderUID []string `json:"folderUid"`
+	// Filter by the name of the rule group. Rules in any of the given groups are returned.
+	// in: query
+	// required: false
+	Group []string `json:"group"`
+}
+
+// swagger:parameters RouteGetAlertRule RoutePutAlertRule RoutePatchAlertRule RouteDeleteAlertRule RouteGetAlertRuleExport
 type AlertRuleUIDReference struct {
 	// Alert rule UID
 	// in:path
@@ -84,7 +128,13 @@ type AlertRulePayload struct {
 	Body ProvisionedAlertRule
 }
 
-// swagger:parameters RoutePostAlertRule RoutePutAlertRule
+// swagger:parameters RoutePatchAlertRule
+type AlertRulePatchPayload struct {
+	// in:body
+	Body AlertRulePatch
+}
+
+// swagger:parameters RoutePostAlertRule RoutePutAlertRule RoutePatchAlertRule
 type AlertRuleHeaders struct {
 	// in:header
 	XDisableProvenance string `json:"X-Disable-Provenance"`
@@ -133,6 +183,42 @@ type ProvisionedAlertRule struct {
 	Provenance Provenance `json:"provenance,omitempty"`
 	// example: false
 	IsPaused bool `json:"isPaused"`
+	// NotificationSettings optionally send the notifications of the rule directly to a contact point, instead of
+	// routing them with the notification policy tree.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notificationSettings,omitempty"`
+}
+
+// AlertRuleNotificationSettings send the notifications of an alert rule directly to a contact point, bypassing the
+// notification policy tree.
+// swagger:model
+type AlertRuleNotificationSettings struct {
+	// Name of the contact point that receives the notifications of the rule. The contact point must exist.
+	// required: true
+	// example: grafana-default-email
+	Receiver string `json:"receiver" yaml:"receiver"`
+	// Names of the mute timings during which no notifications of the rule are sent. The mute timings must exist.
+	// example: ["weekends"]
+	MuteTimeIntervals []string `json:"muteTimeIntervals,omitempty" yaml:"muteTimeIntervals,omitempty"`
+}
+
+// AlertRulePatch holds the fields of an alert rule to update. Fields that are left out are kept as they are. Labels
+// and annotations that are given replace those of the rule as a whole.
+// swagger:model
+type AlertRulePatch struct {
+	// example: Always firing
+	Title *string `json:"title,omitempty"`
+	// example: A
+	Condition    *string              `json:"condition,omitempty"`
+	Data         []AlertQuery         `json:"data,omitempty"`
+	NoDataState  *NoDataState         `json:"noDataState,omitempty"`
+	ExecErrState *ExecutionErrorState `json:"execErrState,omitempty"`
+	For          *model.Duration      `json:"for,omitempty"`
+	// example: {"runbook_url": "https://supercoolrunbook.com/page/13"}
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// example: {"team": "sre-team-1"}
+	Labels map[string]string `json:"labels,omitempty"`
+	// example: false
+	IsPaused *bool `json:"isPaused,omitempty"`
 }
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RouteGetAlertRuleGroup
@@ -145,7 +231,7 @@ type ProvisionedAlertRule struct {
 
 // swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/export provisioning stable RouteGetAlertRuleGroupExport
 //
-// Export an alert rule group in provisioning file format.
+// Export an alert rule group in provisioning file format, or as a Prometheus rule group.
 //
 //     Produces:
 //     - application/json
@@ -154,6 +240,7 @@ type ProvisionedAlertRule struct {
 //
 //     Responses:
 //       200: AlertingFileExport
+//       400: ValidationError
 //       404: description: Not found.
 
 // swagger:route PUT /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group} provisioning stable RoutePutAlertRuleGroup
@@ -167,13 +254,52 @@ type ProvisionedAlertRule struct {
 //       200: AlertRuleGroup
 //       400: ValidationError
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport
+// swagger:route PUT /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/pause provisioning stable RoutePutAlertRuleGroupPause
+//
+// Pause or resume the evaluation of all rules in a rule group.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleGroup
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation provisioning stable RouteGetAlertRuleGroupEvaluation
+//
+// Get the interval and the evaluation offset of a rule group.
+//
+//     Responses:
+//       200: AlertRuleGroupEvaluation
+//       404: description: Not found.
+
+// swagger:route PUT /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/evaluation provisioning stable RoutePutAlertRuleGroupEvaluation
+//
+// Set the interval and the evaluation offset of a rule group.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: AlertRuleGroupEvaluation
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/folder/{FolderUID}/rule-groups/{Group}/load provisioning stable RouteGetAlertRuleGroupLoad
+//
+// Estimate the load that evaluating a rule group puts on the scheduler and the data sources.
+//
+//     Responses:
+//       200: AlertRuleGroupLoad
+//       404: description: Not found.
+
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport RoutePutAlertRuleGroupPause RouteGetAlertRuleGroupEvaluation RoutePutAlertRuleGroupEvaluation RouteGetAlertRuleGroupLoad
 type FolderUIDPathParam struct {
 	// in:path
 	FolderUID string `json:"FolderUID"`
 }
 
-// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport
+// swagger:parameters RouteGetAlertRuleGroup RoutePutAlertRuleGroup RouteGetAlertRuleGroupExport RoutePutAlertRuleGroupPause RouteGetAlertRuleGroupEvaluation RoutePutAlertRuleGroupEvaluation RouteGetAlertRuleGroupLoad
 type RuleGroupPathParam struct {
 	// in:path
 	Group string `json:"Group"`
@@ -185,6 +311,64 @@ type AlertRuleGroupPayload struct {
 	Body AlertRuleGroup
 }
 
+// swagger:parameters RoutePutAlertRuleGroupPause
+type AlertRuleGroupPausePayload struct {
+	// in:body
+	Body AlertRuleGroupPause
+}
+
+// swagger:model
+type AlertRuleGroupPause struct {
+	// Paused is whether the rules of the group are evaluated.
+	Paused bool `json:"paused"`
+}
+
+// swagger:parameters RoutePutAlertRuleGroupEvaluation
+type AlertRuleGroupEvaluationPayload struct {
+	// in:body
+	Body AlertRuleGroupEvaluation
+}
+
+// swagger:model
+type AlertRuleGroupEvaluation struct {
+	// Interval is how often the rules of the group are evaluated, in seconds.
+	// example: 60
+	Interval int64 `json:"interval"`
+	// EvaluationOffset delays the evaluation of the group within its interval, in seconds, so that groups with the
+	// same interval are not all evaluated at the same time. It must be shorter than the interval.
+	// example: 30
+	EvaluationOffset int64 `json:"evaluationOffset"`
+}
+
+// swagger:model
+type AlertRuleGroupLoad struct {
+	// Rules is the number of rules of the group.
+	Rules int `json:"rules"`
+	// PausedRules is the number of rules of the group that are paused.
+	PausedRules int `json:"pausedRules"`
+	// Queries is the number of data source queries of the rules, without expressions.
+	Queries int `json:"queries"`
+	// DatasourceUIDs are the data sources that the rules query.
+	DatasourceUIDs []string `json:"datasourceUids"`
+	// EvaluationsPerHour is how often the rules that are not paused are evaluated per hour.
+	EvaluationsPerHour float64 `json:"evaluationsPerHour"`
+	// QueriesPerHour is how many data source queries the rules that are not paused run per hour.
+	QueriesPerHour float64 `json:"queriesPerHour"`
+	// EvaluationDuration are the percentiles of how long the last evaluation of each rule took. It is missing if none
+	// of the rules was evaluated since Grafana started.
+	EvaluationDuration *EvaluationDurationPercentiles `json:"evaluationDuration,omitempty"`
+}
+
+// EvaluationDurationPercentiles are percentiles of evaluation durations, in seconds.
+type EvaluationDurationPercentiles struct {
+	// Samples is the number of evaluations.
+	Samples int     `json:"samples"`
+	P50     float64 `json:"p50"`
+	P90     float64 `json:"p90"`
+	P99     float64 `json:"p99"`
+	Max     float64 `json:"max"`
+}
+
 // swagger:model
 type AlertRuleGroupMetadata struct {
 	Interval int64 `json:"interval"`
@@ -196,6 +380,38 @@ type AlertRuleGroup struct {
 	FolderUID string                 `json:"folderUid"`
 	Interval  int64                  `json:"interval"`
 	Rules     []ProvisionedAlertRule `json:"rules"`
+	// EvaluationOffset delays the evaluation of the group within its interval, in seconds. It is changed with the
+	// evaluation of the group, and kept when the group is replaced.
+	// readonly: true
+	EvaluationOffset int64 `json:"evaluationOffset,omitempty"`
+}
+
+// swagger:parameters RouteGetAlertRuleGroupExport RouteGetAlertRuleExport RouteGetAlertRulesExport
+type AlertRuleExportParams struct {
+	// Target of the export, either grafana for the provisioning file format or prometheus for Prometheus rule groups
+	// by the title of their folder. Only rules whose condition is a single query, or a threshold of the last value of a
+	// single query, can be exported to prometheus.
+	// in: query
+	// required: false
+	// default: grafana
+	Target string `json:"target"`
+	// Whether rules that cannot be exported to prometheus are left out instead of failing the export.
+	// in: query
+	// required: false
+	// default: false
+	SkipIncompatible bool `json:"skipIncompatible"`
+}
+
+// PrometheusRulesExport is the export of alert rules as Prometheus rule groups, by the title of their folder. This is
+// the format of the rules of a Mimir or Cortex ruler.
+// swagger:model
+type PrometheusRulesExport map[string][]PrometheusRuleGroupExport
+
+// PrometheusRuleGroupExport is an alert rule group as a rule group of a Prometheus rule file.
+type PrometheusRuleGroupExport struct {
+	Name     string         `json:"name" yaml:"name"`
+	Interval model.Duration `json:"interval,omitempty" yaml:"interval,omitempty"`
+	Rules    []ApiRuleNode  `json:"rules" yaml:"rules"`
 }
 
 // AlertRuleGroupExport is the provisioned file export of AlertRuleGroupV1.
@@ -205,6 +421,8 @@ type AlertRuleGroupExport struct {
 	Folder   string            `json:"folder" yaml:"folder"`
 	Interval model.Duration    `json:"interval" yaml:"interval"`
 	Rules    []AlertRuleExport `json:"rules" yaml:"rules"`
+	// FolderUID is only used by the hcl format, the provisioning file format identifies folders by their title.
+	FolderUID string `json:"-" yaml:"-"`
 }
 
 // AlertRuleExport is the provisioned file export of models.AlertRule.
@@ -221,6 +439,8 @@ type AlertRuleExport struct {
 	Annotations  map[string]string   `json:"annotations,omitempty" yaml:"annotations,omitempty"`
 	Labels       map[string]string   `json:"labels,omitempty" yaml:"labels,omitempty"`
 	IsPaused     bool                `json:"isPaused" yaml:"isPaused"`
+	// NotificationSettings optionally send the notifications of the rule directly to a contact point.
+	NotificationSettings *AlertRuleNotificationSettings `json:"notificationSettings,omitempty" yaml:"notificationSettings,omitempty"`
 }
 
 // AlertQueryExport is the provisioned export of models.AlertQuery.
@@ -0,0 +1,41 @@
+package definitions
+
+// swagger:route POST /api/v1/provisioning/alertmanager/import provisioning stable RoutePostAlertmanagerImport
+//
+// Import the receivers, routes, mute time intervals and templates of the configuration of a Prometheus Alertmanager as contact points, notification policies, mute timings and templates. Either everything is imported or nothing.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: AlertmanagerImportResult
+//       400: ValidationError
+
+// swagger:parameters RoutePostAlertmanagerImport
+type AlertmanagerImportPayload struct {
+	// in:body
+	Body AlertmanagerImport
+}
+
+// AlertmanagerImport is the configuration of a Prometheus Alertmanager to import.
+// swagger:model
+type AlertmanagerImport struct {
+	// Config is the content of the alertmanager.yml file.
+	// required: true
+	Config string `json:"config"`
+	// Templates are the contents of the template files the configuration uses, by their file name.
+	Templates map[string]string `json:"templates,omitempty"`
+}
+
+// AlertmanagerImportResult describes what was imported from the configuration of a Prometheus Alertmanager.
+// swagger:model
+type AlertmanagerImportResult struct {
+	// ContactPoints are the names of the imported contact points.
+	ContactPoints []string `json:"contactPoints"`
+	// MuteTimings are the names of the imported mute timings.
+	MuteTimings []string `json:"muteTimings"`
+	// Templates are the names of the imported templates.
+	Templates []string `json:"templates"`
+	// Warnings are the parts of the configuration that have no equivalent in Grafana and were left out.
+	Warnings []string `json:"warnings"`
+}
@@ -0,0 +1,95 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/audit provisioning stable RouteGetProvisioningAudit
+//
+// Get the changes made to provisioned resources of the organization, most recent first. Secure settings are redacted.
+//
+//     Responses:
+//       200: ProvisioningAuditEntries
+//       400: ValidationError
+
+// swagger:parameters RouteGetProvisioningAudit
+type ProvisioningAuditParams struct {
+	// Only return changes of resources of this type, for example contactPoint, route, template,
+	// muteTimeInterval or alertRule.
+	// in:query
+	// required:false
+	ResourceType string `json:"resourceType"`
+	// Only return changes of the resource with this identifier. Requires resourceType.
+	// in:query
+	// required:false
+	ResourceID string `json:"resourceId"`
+	// Only return changes made at or after this time, in seconds since the epoch.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// Only return changes made at or before this time, in seconds since the epoch.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+	// The maximum number of changes to return.
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+}
+
+// swagger:route GET /api/v1/provisioning/history provisioning stable RouteGetProvisioningResourceHistory
+//
+// Get the changes made to a single provisioned resource, most recent first. Secure settings are redacted. The X-Total-Count header is the number of all changes of the resource.
+//
+//     Responses:
+//       200: ProvisioningAuditEntries
+//       400: ValidationError
+
+// swagger:parameters RouteGetProvisioningResourceHistory
+type ProvisioningResourceHistoryParams struct {
+	// The type of the resource, for example contactPoint, route, template, muteTimeInterval or alertRule.
+	// in:query
+	// required:true
+	ResourceType string `json:"resourceType"`
+	// The identifier of the resource. Empty for the notification policy tree.
+	// in:query
+	// required:false
+	ResourceID string `json:"resourceId"`
+	// The number of most recent changes to skip.
+	// in:query
+	// required:false
+	// default:0
+	Offset int `json:"offset"`
+	// The maximum number of changes to return.
+	// in:query
+	// required:false
+	// default:100
+	Limit int `json:"limit"`
+}
+
+// swagger:model
+type ProvisioningAuditEntries []ProvisioningAuditEntry
+
+// swagger:model
+type ProvisioningAuditEntry struct {
+	ID    int64 `json:"id"`
+	OrgID int64 `json:"orgId"`
+	// The user who made the change. Empty when the change was made by Grafana, for example by file provisioning.
+	ActorID    int64  `json:"actorId,omitempty"`
+	ActorLogin string `json:"actorLogin,omitempty"`
+	// enum: create,update,delete,restore,expire,decrypt
+	Action       string     `json:"action"`
+	ResourceType string     `json:"resourceType"`
+	ResourceID   string     `json:"resourceId"`
+	Provenance   Provenance `json:"provenance,omitempty"`
+	// Where the change comes from when it was not made by a user, for example the path of the provisioning file.
+	Source string `json:"source,omitempty"`
+	// The ID of the trace of the request that made the change, if the request was traced.
+	CorrelationID string `json:"correlationId,omitempty"`
+	// The resource before the change. Absent for creations.
+	OldState RawMessage `json:"oldState,omitempty"`
+	// The resource after the change. Absent for deletions.
+	NewState RawMessage `json:"newState,omitempty"`
+	Created  time.Time  `json:"created"`
+}
@@ -0,0 +1,318 @@
+package definitions
+
+import "time"
+
+// swagger:route POST /api/v1/provisioning/bundle provisioning stable RoutePostProvisioningBundle
+//
+// Apply contact points, mute timings, templates, the notification policy tree and rule groups together. Either all of them are applied or none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisioningBundleResult
+//       400: ValidationError
+//       409: description: A resource of the bundle was changed while the bundle was applied.
+
+// swagger:route POST /api/v1/provisioning/bundle/diff provisioning stable RoutePostProvisioningBundleDiff
+//
+// Compare a provisioning bundle with the current state of the organization and get the changes that applying it would make. Nothing is changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ProvisioningBundleDiff
+//       400: ValidationError
+
+// swagger:route POST /api/v1/provisioning/bundle/export provisioning stable RoutePostProvisioningBundleExport
+//
+// Export the full provisioning state of the organization, including contact points with their secrets, as a bundle encrypted with the given passphrase or RSA public key.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: EncryptedProvisioningBundle
+//       400: ValidationError
+//       403: PermissionDenied
+
+// swagger:route POST /api/v1/provisioning/bundle/import provisioning stable RoutePostProvisioningBundleImport
+//
+// Decrypt a bundle that was exported with RoutePostProvisioningBundleExport and apply it. Either all of its resources are applied or none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisioningBundleResult
+//       400: ValidationError
+//       409: description: A resource of the bundle was changed while the bundle was applied.
+
+// swagger:route GET /api/v1/provisioning/bundle/scheduled provisioning stable RouteGetScheduledProvisioningBundles
+//
+// Get the provisioning bundles that are scheduled to be applied later, and the scheduled bundles that failed.
+//
+//     Responses:
+//       200: ScheduledProvisioningBundles
+
+// swagger:route POST /api/v1/provisioning/bundle/scheduled provisioning stable RoutePostScheduledProvisioningBundle
+//
+// Schedule a provisioning bundle to be applied at the given time. Either all of its resources are applied or none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ScheduledProvisioningBundle
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/bundle/scheduled/{UID} provisioning stable RouteDeleteScheduledProvisioningBundle
+//
+// Cancel a scheduled provisioning bundle, or remove a scheduled bundle that failed.
+//
+//     Responses:
+//       204: description: The scheduled bundle was cancelled successfully.
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/global/copy provisioning stable RoutePostCopyAlertingConfig
+//
+// Copy the selected contact points, templates, mute timings, rule groups and the notification policy tree of one organization into another organization. Either all of them are copied or none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisioningBundleResult
+//       400: ValidationError
+//       403: PermissionDenied
+//       404: description: An organization or a selected resource of the source organization does not exist.
+//       409: description: A resource of the target organization was changed while the resources were copied.
+
+// swagger:parameters RoutePostProvisioningBundle RoutePostProvisioningBundleDiff
+type ProvisioningBundlePayload struct {
+	// in:body
+	Body ProvisioningBundle
+}
+
+// swagger:parameters RoutePostProvisioningBundleExport
+type ProvisioningBundleExportPayload struct {
+	// in:body
+	Body ProvisioningBundleExportRequest
+}
+
+// swagger:parameters RoutePostProvisioningBundleImport
+type ProvisioningBundleImportPayload struct {
+	// in:body
+	Body ProvisioningBundleImportRequest
+}
+
+// swagger:parameters RoutePostScheduledProvisioningBundle
+type ScheduledProvisioningBundlePayload struct {
+	// in:body
+	Body ScheduledProvisioningBundleRequest
+}
+
+// swagger:parameters RouteDeleteScheduledProvisioningBundle
+type ScheduledProvisioningBundleUIDReference struct {
+	// UID is the unique identifier of the scheduled bundle
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostProvisioningBundle RoutePostProvisioningBundleImport RoutePostScheduledProvisioningBundle
+type ProvisioningBundleHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// ProvisioningBundle is a set of resources that are applied together.
+// swagger:model
+type ProvisioningBundle struct {
+	// ContactPoints are updated if a contact point with the same UID exists, and created otherwise.
+	ContactPoints []EmbeddedContactPoint `json:"contactPoints,omitempty"`
+	// Policies replaces the notification policy tree if set.
+	Policies *Route `json:"policies,omitempty"`
+	// MuteTimings are updated if a mute timing with the same name exists, and created otherwise.
+	MuteTimings []MuteTimeInterval `json:"muteTimings,omitempty"`
+	// Templates are created or replaced by name.
+	Templates []NotificationTemplate `json:"templates,omitempty"`
+	// RuleGroups replace the rule groups with the same folder and title.
+	RuleGroups []AlertRuleGroup `json:"ruleGroups,omitempty"`
+}
+
+// BundleEncryptionMode is how the key of an encrypted bundle is protected.
+// swagger:enum BundleEncryptionMode
+type BundleEncryptionMode string
+
+const (
+	// BundleEncryptionPassphrase bundles are encrypted with a key derived from a passphrase.
+	BundleEncryptionPassphrase BundleEncryptionMode = "passphrase"
+	// BundleEncryptionPublicKey bundles are encrypted with a random key, which is encrypted with an RSA public key.
+	BundleEncryptionPublicKey BundleEncryptionMode = "publicKey"
+)
+
+// ProvisioningBundleExportRequest is the key to encrypt an exported bundle with. Exactly one of the fields must be set.
+// swagger:model
+type ProvisioningBundleExportRequest struct {
+	Passphrase string `json:"passphrase,omitempty"`
+	// PublicKey is a PEM encoded RSA public key.
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// ProvisioningBundleImportRequest is an encrypted bundle together with the key to decrypt it with.
+// swagger:model
+type ProvisioningBundleImportRequest struct {
+	Bundle EncryptedProvisioningBundle `json:"bundle"`
+	// Passphrase decrypts bundles that were encrypted with a passphrase.
+	Passphrase string `json:"passphrase,omitempty"`
+	// PrivateKey is the PEM encoded RSA private key that decrypts bundles that were encrypted with its public key.
+	PrivateKey string `json:"privateKey,omitempty"`
+}
+
+// EncryptedProvisioningBundle is a ProvisioningBundle encrypted with AES-256-GCM. Binary fields are base64 encoded.
+// swagger:model
+type EncryptedProvisioningBundle struct {
+	// Version is the version of the format of the bundle.
+	// example: 1
+	Version int                  `json:"version"`
+	Mode    BundleEncryptionMode `json:"mode"`
+	// Salt is the salt of the key derivation from the passphrase.
+	Salt []byte `json:"salt,omitempty"`
+	// EncryptedKey is the key of the bundle encrypted with the RSA public key.
+	EncryptedKey []byte `json:"encryptedKey,omitempty"`
+	Nonce        []byte `json:"nonce"`
+	Ciphertext   []byte `json:"ciphertext"`
+}
+
+// ProvisioningBundleResult describes what was applied from a provisioning bundle.
+// swagger:model
+type ProvisioningBundleResult struct {
+	// ContactPoints are the UIDs of the applied contact points.
+	ContactPoints []string `json:"contactPoints"`
+	// Policies is whether the notification policy tree was replaced.
+	Policies bool `json:"policies"`
+	// MuteTimings are the names of the applied mute timings.
+	MuteTimings []string `json:"muteTimings"`
+	// Templates are the names of the applied templates.
+	Templates []string `json:"templates"`
+	// RuleGroups are the applied rule groups as folder UID and title separated by a slash.
+	RuleGroups []string `json:"ruleGroups"`
+}
+
+// ScheduledProvisioningBundleRequest is a provisioning bundle together with the time to apply it at.
+// swagger:model
+type ScheduledProvisioningBundleRequest struct {
+	// ApplyAt is when the bundle is applied. It must be in the future.
+	ApplyAt time.Time `json:"applyAt"`
+	// Location is the time zone of ApplyAt, for example Europe/Berlin. If it is set, the date and time of ApplyAt are
+	// taken in this time zone and the offset of ApplyAt is ignored, so that daylight saving time is accounted for.
+	Location string             `json:"location,omitempty"`
+	Bundle   ProvisioningBundle `json:"bundle"`
+}
+
+// Statuses of scheduled provisioning bundles.
+const (
+	ScheduledProvisioningBundlePending = "pending"
+	ScheduledProvisioningBundleFailed  = "failed"
+)
+
+// ScheduledProvisioningBundle is a provisioning bundle that is applied later. Bundles are removed once they were
+// applied, and kept with the error if applying them failed. The resources of the bundle are not returned, because
+// contact points can contain secrets.
+// swagger:model
+type ScheduledProvisioningBundle struct {
+	UID string `json:"uid"`
+	// ApplyAt is when the bundle is applied, in the time zone of Location.
+	ApplyAt  time.Time `json:"applyAt"`
+	Location string    `json:"location,omitempty"`
+	// Status is pending until the bundle is applied, or failed if applying it failed.
+	Status string `json:"status"`
+	// Error is why applying the bundle failed.
+	Error      string     `json:"error,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	Provenance Provenance `json:"provenance,omitempty"`
+	// ContactPoints, MuteTimings, Templates and RuleGroups are the number of resources of each type in the bundle.
+	ContactPoints int `json:"contactPoints"`
+	MuteTimings   int `json:"muteTimings"`
+	Templates     int `json:"templates"`
+	RuleGroups    int `json:"ruleGroups"`
+	// Policies is whether the bundle replaces the notification policy tree.
+	Policies bool `json:"policies"`
+}
+
+// swagger:model
+type ScheduledProvisioningBundles []ScheduledProvisioningBundle
+
+// ResourceDiffAction is the change that applying a provisioning bundle makes to a resource.
+type ResourceDiffAction string
+
+const (
+	ResourceDiffCreate ResourceDiffAction = "create"
+	ResourceDiffUpdate ResourceDiffAction = "update"
+	ResourceDiffDelete ResourceDiffAction = "delete"
+	ResourceDiffNoop   ResourceDiffAction = "noop"
+)
+
+// ProvisioningBundleDiff is the changes that applying a provisioning bundle would make.
+// swagger:model
+type ProvisioningBundleDiff struct {
+	// Resources are the resources of the bundle and the alert rules that replacing its rule groups would delete.
+	Resources []ResourceDiff `json:"resources"`
+}
+
+// ResourceDiff is the change that applying a provisioning bundle would make to a single resource.
+type ResourceDiff struct {
+	// ResourceType is the type of the resource: contactPoint, template, muteTimeInterval, route or alertRule.
+	ResourceType string `json:"resourceType"`
+	// ResourceID is the UID or name of the resource. Empty for contact points and alert rules that are created without a UID.
+	ResourceID string `json:"resourceId"`
+	// Name is the name or title of the resource.
+	Name string `json:"name,omitempty"`
+	// Action is create, update, delete or noop.
+	Action ResourceDiffAction `json:"action"`
+	// Changes are the changed fields of an updated resource. Secrets are redacted.
+	Changes []ConfigChange `json:"changes,omitempty"`
+}
+
+// swagger:parameters RoutePostCopyAlertingConfig
+type CopyAlertingConfigPayload struct {
+	// in:body
+	Body CopyAlertingConfigRequest
+}
+
+// swagger:parameters RoutePostCopyAlertingConfig
+type CopyAlertingConfigHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// CopyAlertingConfigRequest selects the resources that are copied from one organization to another, and how the
+// folders and data sources they reference are mapped between the organizations.
+// swagger:model
+type CopyAlertingConfigRequest struct {
+	SourceOrgID int64 `json:"sourceOrgId"`
+	TargetOrgID int64 `json:"targetOrgId"`
+	// ContactPoints are the names of the contact points to copy, with all of their integrations.
+	ContactPoints []string `json:"contactPoints,omitempty"`
+	// Templates are the names of the templates to copy.
+	Templates []string `json:"templates,omitempty"`
+	// MuteTimings are the names of the mute timings to copy.
+	MuteTimings []string `json:"muteTimings,omitempty"`
+	// Policies copies the notification policy tree, replacing the tree of the target organization.
+	Policies bool `json:"policies,omitempty"`
+	// RuleGroups are the rule groups to copy, identified by the UID of their folder in the source organization.
+	RuleGroups []CopyRuleGroup `json:"ruleGroups,omitempty"`
+	// FolderUIDs maps the UIDs of folders in the source organization to the UIDs of folders in the target
+	// organization. Folders that are not mapped keep their UID.
+	FolderUIDs map[string]string `json:"folderUids,omitempty"`
+	// DatasourceUIDs maps the UIDs of data sources in the source organization to the UIDs of data sources in the
+	// target organization. Data sources that are not mapped keep their UID.
+	DatasourceUIDs map[string]string `json:"datasourceUids,omitempty"`
+}
+
+// CopyRuleGroup identifies a rule group to copy.
+type CopyRuleGroup struct {
+	FolderUID string `json:"folderUid"`
+	Title     string `json:"title"`
+}
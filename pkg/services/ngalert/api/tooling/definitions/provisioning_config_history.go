@@ -0,0 +1,44 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/alertmanager/versions provisioning stable RouteGetAlertmanagerConfigVersions
+//
+// Get the saved versions of the Alertmanager configuration of the organization, most recent first.
+//
+//     Responses:
+//       200: AlertmanagerConfigVersions
+
+// swagger:route POST /api/v1/provisioning/alertmanager/versions/{ID}/rollback provisioning stable RoutePostAlertmanagerConfigRollback
+//
+// Roll the Alertmanager configuration of the organization back to a saved version.
+//
+//     Responses:
+//       202: AlertmanagerConfigVersion
+//       400: ValidationError
+//       404: description: Not found.
+//       409: description: The configuration was changed during the rollback.
+
+// swagger:parameters RoutePostAlertmanagerConfigRollback
+type AlertmanagerConfigVersionParam struct {
+	// The ID of the version to roll back to.
+	// in:path
+	// required: true
+	ID int64
+}
+
+// swagger:model
+type AlertmanagerConfigVersions []AlertmanagerConfigVersion
+
+// AlertmanagerConfigVersion is a saved version of the Alertmanager configuration of an organization.
+// swagger:model
+type AlertmanagerConfigVersion struct {
+	ID      int64     `json:"id"`
+	Created time.Time `json:"created"`
+	// The last time the version was applied to the Alertmanager. It is omitted if the version was never applied.
+	LastApplied *time.Time `json:"lastApplied,omitempty"`
+	// Whether the version is the current configuration.
+	Current bool `json:"current"`
+}
@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	"github.com/grafana/grafana/pkg/components/simplejson"
 )
 
@@ -8,17 +10,34 @@ import (
 //
 // Get all the contact points.
 //
+// The X-Total-Count header of the response is the number of contact points that match the query before the offset and limit are applied.
+//
 //     Responses:
 //       200: ContactPoints
 
 // swagger:route GET /api/v1/provisioning/contact-points/export provisioning stable RouteGetContactpointsExport
 //
-// Export all contact points in provisioning file format.
+// Export all contact points in provisioning file format, or as receivers of the configuration of a Prometheus Alertmanager.
 //
 //     Responses:
 //       200: AlertingFileExport
+//       400: ValidationError
 //       403: PermissionDenied
 
+// swagger:route GET /api/v1/provisioning/contact-points/deleted provisioning stable RouteGetDeletedContactpoints
+//
+// Get the deleted contact points that can still be restored.
+//
+//     Responses:
+//       200: DeletedContactPoints
+
+// swagger:route GET /api/v1/provisioning/contact-points/status provisioning stable RouteGetContactpointsStatus
+//
+// Get the delivery status of the contact points, such as the error of the last notification attempt and the number of consecutive failed attempts.
+//
+//     Responses:
+//       200: ContactPointStatuses
+
 // swagger:route POST /api/v1/provisioning/contact-points provisioning stable RoutePostContactpoints
 //
 // Create a contact point.
@@ -30,6 +49,44 @@ import (
 //       202: EmbeddedContactPoint
 //       400: ValidationError
 
+// swagger:route POST /api/v1/provisioning/contact-points/batch provisioning stable RoutePostContactpointsBatch
+//
+// Create several contact points at once. Either all contact points are created or none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ContactPoints
+//       400: ValidationError
+
+// swagger:route POST /api/v1/provisioning/contact-points/test provisioning stable RoutePostContactpointTest
+//
+// Send a test notification through a contact point and report how the delivery went.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: ContactPointTestResult
+//       400: ValidationError
+
+// swagger:route POST /api/v1/provisioning/contact-points/{UID}/enable provisioning stable RoutePostContactpointEnable
+//
+// Enable a contact point that was disabled with the disable endpoint, or because it failed to send notifications too many times in a row.
+//
+//     Responses:
+//       202: EmbeddedContactPoint
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/contact-points/{UID}/disable provisioning stable RoutePostContactpointDisable
+//
+// Disable a contact point without deleting it. The other contact points with the same name keep sending notifications.
+//
+//     Responses:
+//       202: EmbeddedContactPoint
+//       404: description: Not found.
+
 // swagger:route PUT /api/v1/provisioning/contact-points/{UID} provisioning stable RoutePutContactpoint
 //
 // Update an existing contact point.
@@ -40,6 +97,37 @@ import (
 //     Responses:
 //       202: Ack
 //       400: ValidationError
+//       409: description: The contact point was changed since the version in the If-Match header.
+
+// swagger:route PUT /api/v1/provisioning/contact-points/{UID}/secrets provisioning stable RoutePutContactpointSecrets
+//
+// Rotate secure settings of a contact point. Only the given secure settings are replaced, all other settings stay as they are.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/contact-points/{UID}/migrate provisioning stable RoutePostContactpointMigrate
+//
+// Migrate a contact point that uses a deprecated integration type or settings to the supported successor.
+//
+//     Responses:
+//       202: EmbeddedContactPoint
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/contact-points/{UID}/restore provisioning stable RoutePostContactpointRestore
+//
+// Restore a deleted contact point.
+//
+//     Responses:
+//       202: EmbeddedContactPoint
+//       400: ValidationError
+//       404: description: Not found.
 
 // swagger:route DELETE /api/v1/provisioning/contact-points/{UID} provisioning stable RouteDeleteContactpoints
 //
@@ -50,8 +138,9 @@ import (
 //
 //     Responses:
 //       204: description: The contact point was deleted successfully.
+//       409: description: The contact point was changed since the version in the If-Match header.
 
-// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints
+// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints RoutePutContactpointSecrets RoutePostContactpointMigrate RoutePostContactpointRestore RoutePostContactpointEnable RoutePostContactpointDisable RoutePutGlobalContactpoint RouteDeleteGlobalContactpoint
 type ContactPointUIDReference struct {
 	// UID is the contact point unique identifier
 	// in:path
@@ -64,17 +153,148 @@ type ContactPointParams struct {
 	// in: query
 	// required: false
 	Name string `json:"name"`
+	// Filter by name patterns such as team-a-*, where * matches any sequence of characters and ? matches a single
+	// character. Contact points whose name matches any of the patterns are returned.
+	// in: query
+	// required: false
+	NamePattern []string `json:"namePattern"`
+	// Filter by integration type. Contact points of any of the given types are returned.
+	// in: query
+	// required: false
+	Type []string `json:"type"`
 }
 
-// swagger:parameters RoutePostContactpoints RoutePutContactpoint
+// swagger:parameters RouteGetContactpointsExport
+type ContactPointExportParams struct {
+	// Target of the export, either grafana for the provisioning file format or alertmanager for receivers of the
+	// configuration of a Prometheus Alertmanager. Integrations without an equivalent in the Prometheus Alertmanager are
+	// left out of the alertmanager export.
+	// in: query
+	// required: false
+	// default: grafana
+	Target string `json:"target"`
+}
+
+// swagger:parameters RouteGetContactpoints
+type ContactPointListParams struct {
+	// Order by name, type or uid. Contact points with equal fields are ordered by uid.
+	// in: query
+	// required: false
+	// default: name
+	SortBy string `json:"sortBy"`
+	// Skip the first contact points of the order.
+	// in: query
+	// required: false
+	// default: 0
+	Offset int `json:"offset"`
+	// Return at most this many contact points. Zero means no limit.
+	// in: query
+	// required: false
+	// default: 0
+	Limit int `json:"limit"`
+	// Show the last four characters of secure settings, for example ****abcd, instead of [REDACTED]. Short secure
+	// settings stay redacted. Requires the alert.provisioning.secrets.masked:read or alert.provisioning.secrets:read
+	// permission.
+	// in: query
+	// required: false
+	// default: false
+	Mask bool `json:"mask"`
+}
+
+// swagger:parameters RouteGetContactpointsStatus
+type ContactPointStatusParams struct {
+	// Only return the contact points whose last notification attempt or endpoint check failed, and the disabled ones.
+	// in: query
+	// required: false
+	// default: false
+	Failing bool `json:"failing"`
+}
+
+// swagger:parameters RoutePutContactpoint
+type ContactPointUpdateParams struct {
+	// Whether a rename applies to all integrations of the contact point and updates the notification policies that use it to the new name.
+	// in: query
+	// required: false
+	// default: false
+	CascadeRename bool `json:"cascadeRename"`
+}
+
+// swagger:parameters RouteDeleteContactpoints
+type ContactPointDeleteParams struct {
+	// Whether the contact point is deleted permanently instead of being kept in the trash, from where it can be restored.
+	// in: query
+	// required: false
+	// default: false
+	Permanent bool `json:"permanent"`
+}
+
+// swagger:parameters RoutePutContactpoint RouteDeleteContactpoints
+type ContactPointVersionHeaders struct {
+	// The version of the contact point the change is based on. The change is rejected if the contact point was changed since.
+	// in:header
+	IfMatch string `json:"If-Match"`
+}
+
+// swagger:parameters RoutePostContactpoints RoutePutContactpoint RoutePostGlobalContactpoints RoutePutGlobalContactpoint
 type ContactPointPayload struct {
 	// in:body
 	Body EmbeddedContactPoint
 }
 
+// swagger:parameters RoutePostContactpointsBatch
+type ContactPointsPayload struct {
+	// in:body
+	Body ContactPoints
+}
+
+// swagger:parameters RoutePostContactpointTest
+type ContactPointTestPayload struct {
+	// in:body
+	Body ContactPointTest
+}
+
+// swagger:parameters RoutePutContactpointSecrets
+type ContactPointSecretsPayload struct {
+	// in:body
+	Body ContactPointSecrets
+}
+
 // swagger:model
 type ContactPoints []EmbeddedContactPoint
 
+// ContactPointSecrets are the new values of secure settings of a contact point by their name.
+// swagger:model
+type ContactPointSecrets map[string]string
+
+// swagger:model
+type ContactPointTest struct {
+	// ContactPoint is the contact point to send the test notification through. Redacted secure settings of an existing
+	// contact point are replaced with their stored values.
+	// required: true
+	ContactPoint EmbeddedContactPoint `json:"contactPoint"`
+	// Alert sets the annotations and labels of the test notification. A default test alert is sent if it is not set.
+	Alert *TestReceiversConfigAlertParams `json:"alert,omitempty"`
+}
+
+// ContactPointTestResult describes the delivery of a test notification through a contact point.
+// swagger:model
+type ContactPointTestResult struct {
+	// Status is ok if the notification was delivered, and failed otherwise.
+	// example: ok
+	Status string `json:"status"`
+	// StatusCode classifies the delivery like the Alertmanager API for testing receivers does: 200 if the notification
+	// was delivered, 400 if the integration is misconfigured, 408 if the delivery timed out and 500 otherwise.
+	// example: 200
+	StatusCode int `json:"statusCode"`
+	// Error is why the notification was not delivered.
+	Error string `json:"error,omitempty"`
+	// Duration is how long the delivery took.
+	// example: 215ms
+	Duration string `json:"duration"`
+	// NotifiedAt is when the test notification was sent.
+	NotifiedAt time.Time `json:"notifiedAt"`
+}
+
 // EmbeddedContactPoint is the contact point type that is used
 // by grafanas embedded alertmanager implementation.
 // swagger:model
@@ -97,8 +317,74 @@ type EmbeddedContactPoint struct {
 	DisableResolveMessage bool `json:"disableResolveMessage"`
 	// readonly: true
 	Provenance string `json:"provenance,omitempty"`
+	// ExpiresAt is the time after which the contact point is removed. Notification policies that use it are routed
+	// to the receiver of the root policy instead. The contact point is permanent if it is not set.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// Warnings about the deprecated integration type or settings the contact point uses. They are resolved by
+	// migrating the contact point.
+	// readonly: true
+	Warnings []string `json:"warnings,omitempty"`
+	// Version changes whenever the contact point is changed. Updates and deletions that pass it in the If-Match
+	// header are rejected if the contact point was changed since.
+	// readonly: true
+	Version string `json:"version,omitempty"`
+	// Disabled contact points do not send notifications, the other contact points with the same name still do.
+	// Contact points are disabled with the disable endpoint or when they fail to send notifications too many times in
+	// a row, and are enabled again with the enable endpoint. Their settings are kept while they are disabled.
+	// readonly: true
+	Disabled bool `json:"disabled,omitempty"`
+	// Probe enables periodic checks of whether the endpoint of the contact point can be reached. Their results are
+	// reported in the contact point status. Only webhook, Slack and PagerDuty contact points can be probed.
+	Probe bool `json:"probe,omitempty"`
+	// Order is the position of the contact point among the contact points with the same name, starting at 0.
+	// Notification templates and the UI list the integrations of a contact point in this order. Creations and updates
+	// that set it move the contact point to the position, positions past the end move it last. Otherwise created
+	// contact points are added last and updated ones keep their position.
+	// example: 0
+	Order *int `json:"order,omitempty"`
 }
 
+// DeletedContactPoint is a contact point in the trash of an organization.
+// swagger:model
+type DeletedContactPoint struct {
+	// ContactPoint is the deleted contact point with redacted secure settings.
+	ContactPoint EmbeddedContactPoint `json:"contactPoint"`
+	// DeletedAt is when the contact point was deleted.
+	DeletedAt time.Time `json:"deletedAt"`
+	// PurgeAt is when the contact point is deleted permanently and can no longer be restored.
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
+// swagger:model
+type DeletedContactPoints []DeletedContactPoint
+
+// ContactPointStatus is the delivery status of a contact point since the Alertmanager of the organization started.
+// swagger:model
+type ContactPointStatus struct {
+	UID  string `json:"uid"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// LastNotifyAttempt is when the contact point last tried to send a notification. It is not set if the contact
+	// point has not tried to send a notification yet.
+	LastNotifyAttempt *time.Time `json:"lastNotifyAttempt,omitempty"`
+	// LastNotifyAttemptDuration is how long the last attempt took, for example 1.5s.
+	LastNotifyAttemptDuration string `json:"lastNotifyAttemptDuration,omitempty"`
+	// LastNotifyAttemptError is the error of the last attempt, empty if it succeeded.
+	LastNotifyAttemptError string `json:"lastNotifyAttemptError,omitempty"`
+	// ConsecutiveFailures is the number of attempts that failed since the last successful one.
+	ConsecutiveFailures int `json:"consecutiveFailures"`
+	// Disabled contact points do not send notifications, see EmbeddedContactPoint.
+	Disabled bool `json:"disabled,omitempty"`
+	// LastProbe is when the endpoint of the contact point was last checked. It is only set for contact points that
+	// are probed.
+	LastProbe *time.Time `json:"lastProbe,omitempty"`
+	// LastProbeError is why the endpoint could not be reached by the last check, empty if it was reached.
+	LastProbeError string `json:"lastProbeError,omitempty"`
+}
+
+// swagger:model
+type ContactPointStatuses []ContactPointStatus
+
 // ContactPointExport is the provisioned file export of alerting.ContactPointV1.
 type ContactPointExport struct {
 	OrgID     int64            `json:"orgId" yaml:"orgId"`
@@ -114,6 +400,30 @@ type ReceiverExport struct {
 	DisableResolveMessage bool       `json:"disableResolveMessage" yaml:"disableResolveMessage"`
 }
 
+// AlertmanagerReceiversExport is the export of contact points as receivers of the configuration of a Prometheus
+// Alertmanager.
+// swagger:model
+type AlertmanagerReceiversExport struct {
+	Receivers []AlertmanagerReceiverExport `json:"receivers" yaml:"receivers"`
+}
+
+// AlertmanagerReceiverExport is a contact point as a receiver of the configuration of a Prometheus Alertmanager. Each
+// integration is exported as the configuration of the equivalent upstream integration.
+type AlertmanagerReceiverExport struct {
+	Name             string           `json:"name" yaml:"name"`
+	DiscordConfigs   []map[string]any `json:"discord_configs,omitempty" yaml:"discord_configs,omitempty"`
+	EmailConfigs     []map[string]any `json:"email_configs,omitempty" yaml:"email_configs,omitempty"`
+	PagerdutyConfigs []map[string]any `json:"pagerduty_configs,omitempty" yaml:"pagerduty_configs,omitempty"`
+	SlackConfigs     []map[string]any `json:"slack_configs,omitempty" yaml:"slack_configs,omitempty"`
+	WebhookConfigs   []map[string]any `json:"webhook_configs,omitempty" yaml:"webhook_configs,omitempty"`
+	OpsGenieConfigs  []map[string]any `json:"opsgenie_configs,omitempty" yaml:"opsgenie_configs,omitempty"`
+	PushoverConfigs  []map[string]any `json:"pushover_configs,omitempty" yaml:"pushover_configs,omitempty"`
+	VictorOpsConfigs []map[string]any `json:"victorops_configs,omitempty" yaml:"victorops_configs,omitempty"`
+	TelegramConfigs  []map[string]any `json:"telegram_configs,omitempty" yaml:"telegram_configs,omitempty"`
+	WebexConfigs     []map[string]any `json:"webex_configs,omitempty" yaml:"webex_configs,omitempty"`
+	MSTeamsConfigs   []map[string]any `json:"msteams_configs,omitempty" yaml:"msteams_configs,omitempty"`
+}
+
 const RedactedValue = "[REDACTED]"
 
 func (e *EmbeddedContactPoint) ResourceID() string {
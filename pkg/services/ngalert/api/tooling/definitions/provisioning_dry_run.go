@@ -0,0 +1,28 @@
+package definitions
+
+// swagger:parameters RoutePostContactpoints RoutePutContactpoint RouteDeleteContactpoints RoutePutPolicyTree RouteResetPolicyTree RoutePostPolicyRoute RoutePutPolicyRoute RouteDeletePolicyRoute RoutePostMuteTiming RoutePutMuteTiming RouteDeleteMuteTiming
+type ProvisioningDryRunParams struct {
+	// Whether the change is only validated and computed, and the changes it would make to the Alertmanager
+	// configuration are returned instead of being saved.
+	// in: query
+	// required: false
+	// default: false
+	DryRun bool `json:"dryRun"`
+}
+
+// ProvisioningDryRun is the result of a change that was made in dry-run mode.
+// swagger:model
+type ProvisioningDryRun struct {
+	// Changes are the changes the request would have made to the Alertmanager configuration.
+	Changes []ConfigChange `json:"changes"`
+}
+
+// ConfigChange is a change to a value of the Alertmanager configuration. Secure settings are redacted.
+type ConfigChange struct {
+	// Path is the location of the value in the configuration, for example [alertmanager_config][route][receiver].
+	Path string `json:"path"`
+	// Before is the value before the change. Absent for added values.
+	Before any `json:"before,omitempty"`
+	// After is the value after the change. Absent for removed values.
+	After any `json:"after,omitempty"`
+}
@@ -0,0 +1,56 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/effective-config provisioning stable RouteGetProvisioningEffectiveConfig
+//
+// Get the applied alerting configuration with the provenance and source of every resource. Secure settings are redacted.
+//
+//     Responses:
+//       200: EffectiveConfig
+
+// swagger:model
+type EffectiveConfig struct {
+	Policies      EffectivePolicies       `json:"policies"`
+	ContactPoints []EffectiveContactPoint `json:"contactPoints"`
+	Templates     []EffectiveTemplate     `json:"templates"`
+	MuteTimings   []EffectiveMuteTiming   `json:"muteTimings"`
+}
+
+// swagger:model
+type EffectivePolicies struct {
+	Route  Route          `json:"route"`
+	Source ResourceSource `json:"source"`
+}
+
+// swagger:model
+type EffectiveContactPoint struct {
+	ContactPoint EmbeddedContactPoint `json:"contactPoint"`
+	Source       ResourceSource       `json:"source"`
+}
+
+// swagger:model
+type EffectiveTemplate struct {
+	Template NotificationTemplate `json:"template"`
+	Source   ResourceSource       `json:"source"`
+}
+
+// swagger:model
+type EffectiveMuteTiming struct {
+	MuteTiming MuteTimeInterval `json:"muteTiming"`
+	Source     ResourceSource   `json:"source"`
+}
+
+// ResourceSource describes the last recorded change of a resource. It is empty if the resource was not changed
+// since the audit log was introduced.
+// swagger:model
+type ResourceSource struct {
+	// The path of the file the resource was provisioned from.
+	File string `json:"file,omitempty"`
+	// The login of the user who changed the resource.
+	Actor string `json:"actor,omitempty"`
+	// The time of the change.
+	Changed *time.Time `json:"changed,omitempty"`
+}
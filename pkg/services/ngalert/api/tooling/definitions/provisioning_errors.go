@@ -0,0 +1,28 @@
+package definitions
+
+// ProvisioningProblem is the RFC 7807 problem details of a failed request of the provisioning API, served with the
+// content type application/problem+json.
+// swagger:model
+type ProvisioningProblem struct {
+	// example: about:blank
+	Type string `json:"type"`
+	// example: Bad Request
+	Title string `json:"title"`
+	// example: 400
+	Status int `json:"status"`
+	// example: invalid object specification: settings should not be empty
+	Detail string `json:"detail"`
+	// Code is the stable, machine-readable kind of the error.
+	// enum: invalid,not-found,permission-denied,version-conflict,quota-reached,rate-limited,frozen,internal
+	// example: invalid
+	Code string `json:"code"`
+	// Field is the path of the offending field of the request, if known.
+	// example: settings
+	Field string `json:"field,omitempty"`
+	// example: contactPoint
+	ResourceType string `json:"resourceType,omitempty"`
+	ResourceID   string `json:"resourceId,omitempty"`
+	// Message is the same as Detail. It is kept for clients of the former error responses.
+	// example: invalid object specification: settings should not be empty
+	Message string `json:"message"`
+}
@@ -0,0 +1,40 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/freeze provisioning stable RouteGetProvisioningFreeze
+//
+// Get the provisioning freeze of the organization.
+//
+//     Responses:
+//       200: ProvisioningFreeze
+
+// swagger:route PUT /api/v1/provisioning/freeze provisioning stable RoutePutProvisioningFreeze
+//
+// Freeze or unfreeze the provisioning of the organization. While provisioning is frozen, requests that change provisioned resources are rejected with status 423, except for the requests of the allowed service accounts.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisioningFreeze
+//       400: ValidationError
+
+// swagger:parameters RoutePutProvisioningFreeze
+type ProvisioningFreezePayload struct {
+	// in:body
+	Body ProvisioningFreeze
+}
+
+// ProvisioningFreeze is the provisioning freeze of an organization.
+// swagger:model
+type ProvisioningFreeze struct {
+	Frozen bool `json:"frozen"`
+	// Reason is reported in the errors of the rejected requests.
+	// example: incident INC-1234
+	Reason string `json:"reason,omitempty"`
+	// AllowedServiceAccountIDs are the IDs of the service accounts that can still change the provisioned resources.
+	AllowedServiceAccountIDs []int64 `json:"allowedServiceAccountIds,omitempty"`
+	// FrozenByConfiguration is true if the organization is frozen by the configuration of Grafana, which cannot be
+	// lifted with the API.
+	// readonly: true
+	FrozenByConfiguration bool `json:"frozenByConfiguration,omitempty"`
+}
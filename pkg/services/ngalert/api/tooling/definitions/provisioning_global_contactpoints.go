@@ -0,0 +1,38 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/global/contact-points provisioning stable RouteGetGlobalContactpoints
+//
+// Get the contact points shared by all organizations.
+//
+//     Responses:
+//       200: ContactPoints
+
+// swagger:route POST /api/v1/provisioning/global/contact-points provisioning stable RoutePostGlobalContactpoints
+//
+// Create a contact point shared by all organizations.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: EmbeddedContactPoint
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/global/contact-points/{UID} provisioning stable RoutePutGlobalContactpoint
+//
+// Update an existing contact point shared by all organizations.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Ack
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/global/contact-points/{UID} provisioning stable RouteDeleteGlobalContactpoint
+//
+// Delete a contact point shared by all organizations.
+//
+//     Responses:
+//       204: description: The contact point was deleted successfully.
+//       400: ValidationError
@@ -0,0 +1,27 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/global/templates provisioning stable RouteGetGlobalTemplates
+//
+// Get the notification templates shared by all organizations.
+//
+//     Responses:
+//       200: NotificationTemplates
+
+// swagger:route PUT /api/v1/provisioning/global/templates/{name} provisioning stable RoutePutGlobalTemplate
+//
+// Create or update a notification template shared by all organizations.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: NotificationTemplate
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/global/templates/{name} provisioning stable RouteDeleteGlobalTemplate
+//
+// Delete a notification template shared by all organizations.
+//
+//     Responses:
+//       204: description: The template was deleted successfully.
+//       404: description: Not found.
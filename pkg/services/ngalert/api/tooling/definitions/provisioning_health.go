@@ -0,0 +1,43 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/health provisioning stable RouteGetProvisioningHealth
+//
+// Get a summary of the health of the alerting configuration of the organization.
+//
+//     Responses:
+//       200: ProvisioningHealth
+
+// swagger:model
+type ProvisioningHealth struct {
+	// The time the current Alertmanager configuration was saved. Absent if the organization has no configuration yet.
+	LastConfigSave *time.Time `json:"lastConfigSave,omitempty"`
+	// The size of the current Alertmanager configuration in bytes.
+	ConfigSize int `json:"configSize"`
+	// The outcome of the last provisioning of the organization's alerting resources from files. Absent if nothing
+	// was provisioned from files in the organization.
+	FileProvisioning *FileProvisioningStatus `json:"fileProvisioning,omitempty"`
+	// The integrations of contact points that fail validation when the configuration is loaded.
+	InvalidReceivers []InvalidReceiver `json:"invalidReceivers"`
+}
+
+// swagger:model
+type FileProvisioningStatus struct {
+	LastAttempt time.Time  `json:"lastAttempt"`
+	LastSuccess *time.Time `json:"lastSuccess,omitempty"`
+	// The error of the last attempt. Absent if the last attempt succeeded.
+	Error string `json:"error,omitempty"`
+	// The errors of the files that failed to be applied when they changed, by file name.
+	FileErrors map[string]string `json:"fileErrors,omitempty"`
+}
+
+// swagger:model
+type InvalidReceiver struct {
+	UID   string `json:"uid"`
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
@@ -0,0 +1,91 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/maintenance-windows provisioning stable RouteGetMaintenanceWindows
+//
+// Get all the maintenance windows.
+//
+//     Responses:
+//       200: MaintenanceWindows
+
+// swagger:route POST /api/v1/provisioning/maintenance-windows provisioning stable RoutePostMaintenanceWindow
+//
+// Create a maintenance window. Its mute timing is attached to the notification policies that alerts matching its matchers are routed to.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: MaintenanceWindow
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/maintenance-windows/{name} provisioning stable RouteDeleteMaintenanceWindow
+//
+// Delete a maintenance window and its mute timing.
+//
+//     Responses:
+//       204: description: The maintenance window was deleted successfully.
+//       404: description: Not found.
+
+// swagger:parameters RoutePostMaintenanceWindow
+type MaintenanceWindowPayload struct {
+	// in:body
+	Body MaintenanceWindow
+}
+
+// swagger:parameters RouteDeleteMaintenanceWindow
+type MaintenanceWindowParams struct {
+	// Maintenance window name
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:model
+type MaintenanceWindows []MaintenanceWindow
+
+const (
+	MaintenanceWindowDaily   = "daily"
+	MaintenanceWindowWeekly  = "weekly"
+	MaintenanceWindowMonthly = "monthly"
+)
+
+// MaintenanceWindow mutes notifications once or on a recurring schedule. It is backed by a mute timing of the same
+// name, which is removed together with the window once the window expires.
+// swagger:model
+type MaintenanceWindow struct {
+	// Name is the name of the maintenance window and of its mute timing.
+	Name string `json:"name"`
+	// Start is the start of the first occurrence of the window.
+	Start time.Time `json:"start"`
+	// End is the end of the first occurrence of the window. Occurrences of recurring windows are at most a day long.
+	End time.Time `json:"end"`
+	// Location is the time zone recurring windows follow, for example Europe/Berlin. Defaults to UTC.
+	Location string `json:"location,omitempty"`
+	// Recurrence repeats the window. The window occurs once if it is not set.
+	Recurrence *MaintenanceWindowRecurrence `json:"recurrence,omitempty"`
+	// Matchers select the notification policies the window is attached to: the policies that alerts with these
+	// labels are routed to. Only equality matchers are supported.
+	Matchers ObjectMatchers `json:"matchers"`
+	// Policies are the notification policies the window is attached to.
+	// readonly: true
+	Policies []PolicyReference `json:"policies,omitempty"`
+	// ExpiresAt is when the window and its mute timing are removed. Windows that recur forever do not expire.
+	// readonly: true
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// readonly: true
+	Provenance Provenance `json:"provenance,omitempty"`
+}
+
+// MaintenanceWindowRecurrence repeats a maintenance window.
+type MaintenanceWindowRecurrence struct {
+	// Frequency is daily, weekly or monthly. Monthly windows are skipped in months without the day of the month
+	// of the first occurrence.
+	// enum: daily,weekly,monthly
+	Frequency string `json:"frequency"`
+	// Until is the time after which the window no longer recurs and is removed. The window recurs forever if it is
+	// not set.
+	Until *time.Time `json:"until,omitempty"`
+}
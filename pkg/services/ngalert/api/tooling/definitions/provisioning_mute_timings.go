@@ -1,6 +1,8 @@
 package definitions
 
 import (
+	"time"
+
 	"github.com/prometheus/alertmanager/config"
 )
 
@@ -41,25 +43,98 @@ import (
 //       200: MuteTimeInterval
 //       400: ValidationError
 
+// swagger:route GET /api/v1/provisioning/mute-timings/{name}/usage provisioning stable RouteGetMuteTimingUsage
+//
+// Get the notification policies that use a mute timing.
+//
+//     Responses:
+//       200: MuteTimingUsage
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/mute-timings/{name}/preview provisioning stable RouteGetMuteTimingPreview
+//
+// Get the time windows in which a mute timing mutes notifications.
+//
+//     Responses:
+//       200: MuteTimingPreview
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route GET /api/v1/provisioning/mute-timings/export provisioning stable RouteGetMuteTimingsExport
+//
+// Export all mute timings in provisioning file format.
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+
 // swagger:route DELETE /api/v1/provisioning/mute-timings/{name} provisioning stable RouteDeleteMuteTiming
 //
 // Delete a mute timing.
 //
 //     Responses:
 //       204: description: The mute timing was deleted successfully.
+//       400: ValidationError
 
 // swagger:route
 
 // swagger:model
 type MuteTimings []MuteTimeInterval
 
-// swagger:parameters RouteGetTemplate RouteGetMuteTiming RoutePutMuteTiming stable RouteDeleteMuteTiming
+// swagger:parameters RouteGetTemplate RouteGetMuteTiming RoutePutMuteTiming stable RouteDeleteMuteTiming RouteGetMuteTimingUsage RouteGetMuteTimingPreview
 type RouteGetMuteTimingParam struct {
 	// Mute timing name
 	// in:path
 	Name string `json:"name"`
 }
 
+// swagger:parameters RouteDeleteMuteTiming
+type MuteTimingDeleteParams struct {
+	// Whether the mute timing is deleted even if notification policies or alert rules use it. It is removed from these policies and rules.
+	// in: query
+	// required: false
+	// default: false
+	Force bool `json:"force"`
+}
+
+// swagger:parameters RouteGetMuteTimingPreview
+type MuteTimingPreviewParams struct {
+	// The start of the preview, in seconds since the epoch. Defaults to now.
+	// in:query
+	// required:false
+	From int64 `json:"from"`
+	// The end of the preview, in seconds since the epoch. Defaults to seven days after the start. The preview
+	// cannot be longer than 90 days.
+	// in:query
+	// required:false
+	To int64 `json:"to"`
+}
+
+// MuteTimingPreview is the list of time windows within a range in which a mute timing mutes notifications.
+// swagger:model
+type MuteTimingPreview struct {
+	Name string    `json:"name"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Windows are the windows in which notifications are muted, in chronological order.
+	Windows []MuteTimingWindow `json:"windows"`
+}
+
+// MuteTimingWindow is a window in which a mute timing mutes notifications. The end is exclusive.
+type MuteTimingWindow struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// MuteTimingUsage lists the notification policies that use a mute timing.
+// swagger:model
+type MuteTimingUsage struct {
+	// Name is the name of the mute timing.
+	Name string `json:"name"`
+	// Policies are the notification policies that use the mute timing.
+	Policies []PolicyReference `json:"policies"`
+}
+
 // swagger:parameters RoutePostMuteTiming RoutePutMuteTiming
 type MuteTimingPayload struct {
 	// in:body
@@ -79,3 +154,9 @@ func (mt *MuteTimeInterval) ResourceType() string {
 func (mt *MuteTimeInterval) ResourceID() string {
 	return mt.MuteTimeInterval.Name
 }
+
+// MuteTimeIntervalExport is the provisioned file export of alerting.MuteTimeV1.
+type MuteTimeIntervalExport struct {
+	OrgID                   int64 `json:"orgId" yaml:"orgId"`
+	config.MuteTimeInterval `json:",inline" yaml:",inline"`
+}
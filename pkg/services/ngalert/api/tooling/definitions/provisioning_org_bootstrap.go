@@ -0,0 +1,57 @@
+package definitions
+
+import "time"
+
+// swagger:route GET /api/v1/provisioning/global/bootstrap-bundle provisioning stable RouteGetOrgBootstrapBundle
+//
+// Get the provisioning bundle that is applied to every organization when it is created.
+//
+//     Responses:
+//       200: OrgBootstrapBundle
+//       404: description: Not found.
+
+// swagger:route PUT /api/v1/provisioning/global/bootstrap-bundle provisioning stable RoutePutOrgBootstrapBundle
+//
+// Set the provisioning bundle that is applied to every organization when it is created. The organizations that exist already are not changed.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: OrgBootstrapBundle
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/global/bootstrap-bundle provisioning stable RouteDeleteOrgBootstrapBundle
+//
+// Stop applying a provisioning bundle to new organizations.
+//
+//     Responses:
+//       204: description: The bootstrap bundle was deleted successfully.
+//       404: description: Not found.
+
+// swagger:parameters RoutePutOrgBootstrapBundle
+type OrgBootstrapBundlePayload struct {
+	// in:body
+	Body ProvisioningBundle
+}
+
+// swagger:parameters RoutePutOrgBootstrapBundle
+type OrgBootstrapBundleHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// OrgBootstrapBundle is the provisioning bundle that is applied to every organization when it is created, so that it
+// starts with a default contact point, notification policy tree, mute timings and templates. The resources of the
+// bundle are not returned, because contact points can contain secrets.
+// swagger:model
+type OrgBootstrapBundle struct {
+	UpdatedAt  time.Time  `json:"updatedAt"`
+	Provenance Provenance `json:"provenance,omitempty"`
+	// ContactPoints, MuteTimings and Templates are the number of resources of each type in the bundle.
+	ContactPoints int `json:"contactPoints"`
+	MuteTimings   int `json:"muteTimings"`
+	Templates     int `json:"templates"`
+	// Policies is whether the bundle replaces the notification policy tree.
+	Policies bool `json:"policies"`
+}
@@ -12,6 +12,8 @@ import (
 //     Responses:
 //       200: Route
 //         description: The currently active notification routing tree
+//       400: ValidationError
+//       404: NotFound
 
 // swagger:route PUT /api/v1/provisioning/policies provisioning stable RoutePutPolicyTree
 //
@@ -21,7 +23,7 @@ import (
 //     - application/json
 //
 //     Responses:
-//       202: Ack
+//       202: PolicyTreeUpdateResult
 //       400: ValidationError
 
 // swagger:route DELETE /api/v1/provisioning/policies provisioning stable RouteResetPolicyTree
@@ -42,6 +44,181 @@ import (
 //       200: AlertingFileExport
 //       404: NotFound
 
+// swagger:route POST /api/v1/provisioning/policies/routes provisioning stable RoutePostPolicyRoute
+//
+// Add a route to the notification policy tree as the last child of a parent route. The route and its children get a UID if they have none.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: Route
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route PUT /api/v1/provisioning/policies/routes/{UID} provisioning stable RoutePutPolicyRoute
+//
+// Replace a route of the notification policy tree and its children.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: Route
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route DELETE /api/v1/provisioning/policies/routes/{UID} provisioning stable RouteDeletePolicyRoute
+//
+// Delete a route of the notification policy tree and its children.
+//
+//     Responses:
+//       204: description: The route was deleted.
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:route POST /api/v1/provisioning/policies/test provisioning stable RoutePostPolicyTreeTest
+//
+// Run label sets through the notification policy tree and get the routes, receivers, group-by labels and mute timings that alerts with these labels would be handled by.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: RoutePolicyTestResults
+//       400: ValidationError
+//       404: NotFound
+
+// swagger:parameters RoutePostPolicyTreeTest
+type RoutePolicyTestPayload struct {
+	// in:body
+	Body RoutePolicyTest
+}
+
+// RoutePolicyTest is a set of alert labels to run through the notification policy tree.
+// swagger:model
+type RoutePolicyTest struct {
+	LabelSets []model.LabelSet `json:"labelSets"`
+}
+
+// swagger:model
+type RoutePolicyTestResults []RoutePolicyTestResult
+
+// RoutePolicyTestResult is the routing of one label set.
+type RoutePolicyTestResult struct {
+	Labels model.LabelSet `json:"labels"`
+	// Routes are the routes that handle alerts with the labels, in the order the Alertmanager matches them.
+	Routes []MatchedRoute `json:"routes"`
+}
+
+// MatchedRoute is a route of the notification policy tree that matches a label set, with the options it inherits
+// from its parents.
+type MatchedRoute struct {
+	UID string `json:"uid,omitempty"`
+	// Path is the list of child indexes leading from the root to the route.
+	Path              []int    `json:"path"`
+	Receiver          string   `json:"receiver"`
+	GroupBy           []string `json:"groupBy"`
+	MuteTimeIntervals []string `json:"muteTimeIntervals"`
+}
+
+// swagger:parameters RoutePostPolicyRoute
+type PolicyRouteParentParams struct {
+	// UID of the parent route.
+	// in: query
+	// required: false
+	Parent string `json:"parent"`
+	// Dot-separated list of child indexes selecting the parent route if no parent UID is given, e.g. 0.2. Empty selects the root.
+	// in: query
+	// required: false
+	Path string `json:"path"`
+}
+
+// swagger:parameters RoutePutPolicyRoute RouteDeletePolicyRoute
+type PolicyRouteUIDParams struct {
+	// UID of the route.
+	// in: path
+	UID string
+}
+
+// swagger:parameters RoutePostPolicyRoute RoutePutPolicyRoute
+type PolicyRoutePayload struct {
+	// in:body
+	Body Route
+}
+
+// swagger:parameters RoutePostPolicyRoute RoutePutPolicyRoute RouteDeletePolicyRoute
+type PolicyRouteHeaders struct {
+	// in:header
+	XDisableProvenance string `json:"X-Disable-Provenance"`
+}
+
+// PolicyReference is a reference from a route of the notification policy tree to a receiver or mute timing.
+type PolicyReference struct {
+	// Path is the list of child indexes leading from the root to the route.
+	Path []int  `json:"path"`
+	UID  string `json:"uid,omitempty"`
+	// Kind is receiver or muteTimeInterval.
+	Kind string `json:"kind"`
+	// Name is the name of the referenced receiver or mute timing.
+	Name string `json:"name"`
+}
+
+// BrokenPolicyReferences is the error returned if a notification policy tree refers to receivers or mute timings
+// that do not exist.
+// swagger:model
+type BrokenPolicyReferences struct {
+	Message          string            `json:"message"`
+	BrokenReferences []PolicyReference `json:"brokenReferences"`
+}
+
+// RouteChange is a route that was added to, removed from or modified in the notification policy tree.
+type RouteChange struct {
+	// Path is the list of child indexes leading from the root to the route, in the old tree for removed routes and in
+	// the new tree otherwise.
+	Path []int  `json:"path"`
+	UID  string `json:"uid,omitempty"`
+	// Matchers of the route in the syntax of label matchers, for example team="a".
+	Matchers []string `json:"matchers,omitempty"`
+	Receiver string   `json:"receiver,omitempty"`
+	// Fields are the names of the changed fields of a modified route, for example receiver or group_wait.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// PolicyTreeDiff is the difference between two notification policy trees. Routes are matched by UID, and by their
+// matchers within the same parent if they have no UID.
+type PolicyTreeDiff struct {
+	Added    []RouteChange `json:"added"`
+	Removed  []RouteChange `json:"removed"`
+	Modified []RouteChange `json:"modified"`
+}
+
+// IsEmpty returns whether the trees are the same.
+func (d PolicyTreeDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// PolicyTreeUpdateResult is returned when the notification policy tree is set.
+// swagger:model
+type PolicyTreeUpdateResult struct {
+	Message string `json:"message"`
+	// Diff is how the tree was changed.
+	Diff PolicyTreeDiff `json:"diff"`
+}
+
+// swagger:parameters RouteGetPolicyTree
+type PolicyTreeParams struct {
+	// Dot-separated list of child indexes selecting a nested route to return instead of the whole tree, e.g. 0.2
+	// in: query
+	// required: false
+	Path string `json:"path"`
+	// Number of levels of the selected route to return, including the route itself, at most 100. Zero returns all levels.
+	// in: query
+	// required: false
+	// default: 0
+	Depth int `json:"depth"`
+}
+
 // swagger:parameters RoutePutPolicyTree
 type Policytree struct {
 	// The new notification routing tree to use
@@ -0,0 +1,46 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/provenance provisioning stable RouteGetProvenances
+//
+// Get the provenance of the contact points, templates, mute timings, notification policies and alert rules of the organization. Resources managed in the UI have no provenance.
+//
+//     Responses:
+//       200: ResourceProvenances
+//       400: ValidationError
+
+// swagger:parameters RouteGetProvenances
+type ProvenancesParams struct {
+	// Only return resources of this type: contactPoint, template, muteTimeInterval, route or alertRule.
+	// in:query
+	// required:false
+	ResourceType string `json:"resourceType"`
+}
+
+// swagger:route POST /api/v1/provisioning/provenance/cleanup provisioning stable RoutePostProvenanceCleanup
+//
+// Remove the provenance records of all organizations whose contact points, templates, mute timings or alert rules no longer exist.
+//
+//     Responses:
+//       200: ProvenanceCleanup
+
+// swagger:model
+type ResourceProvenances []ResourceProvenance
+
+// ResourceProvenance is the provenance of an alerting resource.
+// swagger:model
+type ResourceProvenance struct {
+	// The type of the resource, for example contactPoint.
+	ResourceType string `json:"resourceType"`
+	// The identifier of the resource. It is the UID of an integration of a contact point or of an alert rule, the
+	// name of a template or of a mute timing, and empty for the notification policy tree.
+	ResourceID string `json:"resourceId"`
+	// How the resource is managed: file, api, converted_prometheus, global or remote. Empty if it is managed in the UI.
+	Provenance Provenance `json:"provenance"`
+}
+
+// ProvenanceCleanup reports the provenance records that were removed because their resources no longer exist.
+// swagger:model
+type ProvenanceCleanup struct {
+	// The number of removed provenance records by resource type.
+	Removed map[string]int `json:"removed"`
+}
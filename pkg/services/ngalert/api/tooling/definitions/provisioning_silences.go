@@ -0,0 +1,135 @@
+package definitions
+
+import (
+	"fmt"
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/silences provisioning stable RouteGetSilences
+//
+// Get all provisioned silences.
+//
+//     Responses:
+//       200: ProvisionedSilences
+
+// swagger:route GET /api/v1/provisioning/silences/{UID} provisioning stable RouteGetSilence
+//
+// Get a provisioned silence.
+//
+//     Responses:
+//       200: ProvisionedSilence
+//       404: description: Not found.
+
+// swagger:route POST /api/v1/provisioning/silences provisioning stable RoutePostSilence
+//
+// Create a provisioned silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       201: ProvisionedSilence
+//       400: ValidationError
+
+// swagger:route PUT /api/v1/provisioning/silences/{UID} provisioning stable RoutePutSilence
+//
+// Update an existing provisioned silence.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisionedSilence
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:route DELETE /api/v1/provisioning/silences/{UID} provisioning stable RouteDeleteSilence
+//
+// Delete a provisioned silence. The silence is expired in the Alertmanager.
+//
+//     Responses:
+//       204: description: The silence was deleted successfully.
+//       404: description: Not found.
+
+// swagger:parameters RouteGetSilence RoutePutSilence RouteDeleteSilence
+type SilenceUIDReference struct {
+	// UID is the unique identifier of the provisioned silence
+	// in:path
+	UID string
+}
+
+// swagger:parameters RoutePostSilence RoutePutSilence
+type ProvisionedSilencePayload struct {
+	// in:body
+	Body ProvisionedSilence
+}
+
+// swagger:model
+type ProvisionedSilences []ProvisionedSilence
+
+const (
+	ProvisionedSilencePending = "pending"
+	ProvisionedSilenceActive  = "active"
+	ProvisionedSilenceExpired = "expired"
+)
+
+// ProvisionedSilence is a silence of the Alertmanager of the organization that is managed through provisioning. It is
+// identified by its UID, which stays the same when the silence is changed, unlike the ID of the silence in the
+// Alertmanager.
+// swagger:model
+type ProvisionedSilence struct {
+	// UID identifies the silence. It is generated if it is empty when the silence is created.
+	UID string `json:"uid" yaml:"uid"`
+	// Matchers select the alerts that the silence mutes.
+	Matchers ObjectMatchers `json:"matchers" yaml:"matchers"`
+	StartsAt time.Time      `json:"startsAt" yaml:"startsAt"`
+	EndsAt   time.Time      `json:"endsAt" yaml:"endsAt"`
+	// Comment describes why the alerts are silenced.
+	Comment   string `json:"comment" yaml:"comment"`
+	CreatedBy string `json:"createdBy" yaml:"createdBy"`
+	// SilenceID is the ID of the silence in the Alertmanager. It is empty until the silence was applied to the
+	// Alertmanager, and changes if the Alertmanager replaces the silence when it is updated.
+	// readonly: true
+	SilenceID string `json:"silenceId,omitempty" yaml:"-"`
+	// Status is pending, active or expired, depending on the start and the end of the silence.
+	// readonly: true
+	Status string `json:"status,omitempty" yaml:"-"`
+	// readonly: true
+	Provenance Provenance `json:"provenance,omitempty" yaml:"-"`
+}
+
+func (s *ProvisionedSilence) ResourceType() string {
+	return "silence"
+}
+
+func (s *ProvisionedSilence) ResourceID() string {
+	return s.UID
+}
+
+// Validate checks the matchers and the time range of the silence.
+func (s *ProvisionedSilence) Validate() error {
+	if len(s.Matchers) == 0 {
+		return fmt.Errorf("silence must have at least one matcher")
+	}
+	for _, m := range s.Matchers {
+		if m.Name == "" {
+			return fmt.Errorf("matcher '%s' has no label name", m.String())
+		}
+	}
+	if !s.EndsAt.After(s.StartsAt) {
+		return fmt.Errorf("the end of the silence must be after its start")
+	}
+	return nil
+}
+
+// StatusAt returns the status of the silence at the given time.
+func (s *ProvisionedSilence) StatusAt(now time.Time) string {
+	switch {
+	case now.Before(s.StartsAt):
+		return ProvisionedSilencePending
+	case now.Before(s.EndsAt):
+		return ProvisionedSilenceActive
+	default:
+		return ProvisionedSilenceExpired
+	}
+}
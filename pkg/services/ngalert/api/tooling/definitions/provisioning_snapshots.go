@@ -0,0 +1,49 @@
+package definitions
+
+import (
+	"time"
+)
+
+// swagger:route GET /api/v1/provisioning/snapshots provisioning stable RouteGetAlertingSnapshots
+//
+// Get the snapshots of the alerting configuration of the organization, most recent first.
+//
+//     Responses:
+//       200: AlertingSnapshots
+
+// swagger:route POST /api/v1/provisioning/snapshots/restore provisioning stable RoutePostAlertingSnapshotRestore
+//
+// Restore the alerting configuration of the organization from the most recent snapshot taken at or before a point in time.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: AlertingSnapshot
+//       400: ValidationError
+//       404: description: Not found.
+
+// swagger:parameters RoutePostAlertingSnapshotRestore
+type AlertingSnapshotRestorePayload struct {
+	// in:body
+	Body AlertingSnapshotRestore
+}
+
+// swagger:model
+type AlertingSnapshots []AlertingSnapshot
+
+// AlertingSnapshot is a copy of the alerting configuration of an organization, including its alert rules, taken at a
+// point in time.
+// swagger:model
+type AlertingSnapshot struct {
+	ID      int64     `json:"id"`
+	Created time.Time `json:"created"`
+}
+
+// swagger:model
+type AlertingSnapshotRestore struct {
+	// The point in time to restore the alerting configuration to. The most recent snapshot taken at or before it is
+	// restored.
+	// required: true
+	Timestamp time.Time `json:"timestamp"`
+}
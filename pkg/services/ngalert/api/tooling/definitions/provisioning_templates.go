@@ -1,5 +1,9 @@
 package definitions
 
+import (
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+)
+
 // swagger:route GET /api/v1/provisioning/templates provisioning stable RouteGetTemplates
 //
 // Get all notification templates.
@@ -16,6 +20,14 @@ package definitions
 //       200: NotificationTemplate
 //       404: description: Not found.
 
+// swagger:route GET /api/v1/provisioning/templates/export provisioning stable RouteGetTemplatesExport
+//
+// Export all notification templates in provisioning file format.
+//
+//     Responses:
+//       200: AlertingFileExport
+//       400: ValidationError
+
 // swagger:route PUT /api/v1/provisioning/templates/{name} provisioning stable RoutePutTemplate
 //
 // Updates an existing notification template.
@@ -33,8 +45,20 @@ package definitions
 //
 //     Responses:
 //       204: description: The template was deleted successfully.
+//       400: ValidationError
 
-// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate
+// swagger:route POST /api/v1/provisioning/templates/preview provisioning stable RoutePostTemplatePreview
+//
+// Render a notification template against sample alerts without saving it.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       200: TemplatePreview
+//       400: ValidationError
+
+// swagger:parameters RouteGetTemplate RoutePutTemplate RouteDeleteTemplate RoutePutGlobalTemplate RouteDeleteGlobalTemplate
 type RouteGetTemplateParam struct {
 	// Template Name
 	// in:path
@@ -51,16 +75,63 @@ type NotificationTemplate struct {
 // swagger:model
 type NotificationTemplates []NotificationTemplate
 
+// NotificationTemplateExport is the provisioned file export of alerting.TemplateV1.
+type NotificationTemplateExport struct {
+	OrgID    int64  `json:"orgId" yaml:"orgId"`
+	Name     string `json:"name" yaml:"name"`
+	Template string `json:"template" yaml:"template"`
+}
+
 type NotificationTemplateContent struct {
 	Template string `json:"template"`
 }
 
-// swagger:parameters RoutePutTemplate
+// swagger:parameters RoutePutTemplate RoutePutGlobalTemplate
 type NotificationTemplatePayload struct {
 	// in:body
 	Body NotificationTemplateContent
 }
 
+// swagger:parameters RoutePostTemplatePreview
+type TemplatePreviewPayload struct {
+	// in:body
+	Body TemplatePreviewParams
+}
+
+type TemplatePreviewParams struct {
+	// Template to render. The notification templates of the organization are available to it.
+	Template string `json:"template"`
+
+	// Alerts to render the template with. A single firing alert is used if empty.
+	Alerts []*amv2.PostableAlert `json:"alerts,omitempty"`
+}
+
+// swagger:model
+type TemplatePreview struct {
+	// Results are the rendered top-level definitions of the template.
+	Results []TestTemplatesResult `json:"results"`
+
+	// Errors are the errors the template failed to parse or render with.
+	Errors []TemplatePreviewError `json:"errors"`
+}
+
+type TemplatePreviewError struct {
+	// Name of the definition that failed to render. Empty if the Kind is "invalid_template".
+	Name string `json:"name,omitempty"`
+
+	// Kind of template error that occurred.
+	Kind TemplateErrorKind `json:"kind"`
+
+	// Error message.
+	Message string `json:"message"`
+
+	// Line of the template the error occurred at, if it occurred in the previewed template.
+	Line int `json:"line,omitempty"`
+
+	// Column of the line the error occurred at, if known.
+	Column int `json:"column,omitempty"`
+}
+
 func (t *NotificationTemplate) ResourceType() string {
 	return "template"
 }
@@ -0,0 +1,57 @@
+package definitions
+
+// swagger:route GET /api/v1/provisioning/variables provisioning stable RouteGetProvisioningVariables
+//
+// Get all the provisioning variables of the organization.
+//
+//     Responses:
+//       200: ProvisioningVariables
+
+// swagger:route PUT /api/v1/provisioning/variables/{name} provisioning stable RoutePutProvisioningVariable
+//
+// Create or update a provisioning variable. Contact points that refer to the variable get the new value when they are saved again.
+//
+//     Consumes:
+//     - application/json
+//
+//     Responses:
+//       202: ProvisioningVariable
+//       400: ValidationError
+
+// swagger:route DELETE /api/v1/provisioning/variables/{name} provisioning stable RouteDeleteProvisioningVariable
+//
+// Delete a provisioning variable.
+//
+//     Responses:
+//       204: description: The provisioning variable was deleted successfully.
+//       404: description: Not found.
+
+// swagger:parameters RoutePutProvisioningVariable RouteDeleteProvisioningVariable
+type ProvisioningVariableParams struct {
+	// Provisioning variable name
+	// in:path
+	Name string `json:"name"`
+}
+
+// swagger:parameters RoutePutProvisioningVariable
+type ProvisioningVariablePayload struct {
+	// in:body
+	Body ProvisioningVariableContent
+}
+
+// swagger:model
+type ProvisioningVariables []ProvisioningVariable
+
+// ProvisioningVariable is a variable of an organization that is substituted in the settings of contact points when
+// they are saved, where they refer to it as ${vars.NAME}. Variables are not substituted in secure settings.
+// swagger:model
+type ProvisioningVariable struct {
+	// Name consists of letters, digits and underscores, and does not start with a digit.
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// swagger:model
+type ProvisioningVariableContent struct {
+	Value string `json:"value"`
+}
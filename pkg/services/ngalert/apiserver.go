@@ -0,0 +1,27 @@
+package ngalert
+
+import (
+	"github.com/grafana/grafana/pkg/services/featuremgmt"
+	"github.com/grafana/grafana/pkg/services/grafana-apiserver/builder"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/notifications"
+)
+
+// NotificationsAPIService serves the contact points, templates and mute timings of alerting from the Grafana
+// apiserver.
+type NotificationsAPIService struct{}
+
+// ProvideNotificationsAPIService registers the notifications API group with the Grafana apiserver. Nothing is
+// registered if alerting or the apiserver is disabled.
+func ProvideNotificationsAPIService(ng *AlertNG, features featuremgmt.FeatureToggles) *NotificationsAPIService {
+	if ng.api != nil && features.IsEnabled(featuremgmt.FlagGrafanaAPIServer) {
+		builder.Register(notifications.NewAPIBuilder(
+			ng.api.ContactPointService,
+			ng.api.Templates,
+			ng.api.MuteTimings,
+			ng.accesscontrol,
+			ng.accesscontrolService,
+			ng.provisioningFreeze,
+		))
+	}
+	return &NotificationsAPIService{}
+}
@@ -0,0 +1,17 @@
+package ngalert
+
+import (
+	"github.com/grafana/grafana/pkg/services/grpcserver"
+)
+
+// ProvisioningGRPCService exposes the provisioning services of alerting on the gRPC server of Grafana.
+type ProvisioningGRPCService struct{}
+
+// ProvideProvisioningGRPCService registers the gRPC provisioning API of alerting with the gRPC server. Nothing is
+// registered if alerting is disabled.
+func ProvideProvisioningGRPCService(ng *AlertNG, grpcServerProvider grpcserver.Provider) *ProvisioningGRPCService {
+	if ng.api != nil && grpcServerProvider.GetServer() != nil {
+		ng.api.RegisterProvisioningGRPCServer(grpcServerProvider.GetServer())
+	}
+	return &ProvisioningGRPCService{}
+}
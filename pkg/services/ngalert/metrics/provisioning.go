@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+type Provisioning struct {
+	OperationsTotal   *prometheus.CounterVec
+	OperationDuration *prometheus.HistogramVec
+	// AlertmanagerConfigSize is the size of the latest Alertmanager configuration of each org that was read or saved.
+	AlertmanagerConfigSize *prometheus.GaugeVec
+	// ContactPointCacheHits and ContactPointCacheMisses count the reads of contact points that used the cached
+	// configuration of the org, and that had to load it from the store.
+	ContactPointCacheHits   prometheus.Counter
+	ContactPointCacheMisses prometheus.Counter
+	// ProvenanceCleanupRemovedTotal counts the provenance records that were removed because their resources no longer
+	// exist, by resource type.
+	ProvenanceCleanupRemovedTotal *prometheus.CounterVec
+	// PolicyTreeRoutes is the number of routes below the root of the notification policy tree of each org that was
+	// saved.
+	PolicyTreeRoutes *prometheus.GaugeVec
+}
+
+func NewProvisioningMetrics(r prometheus.Registerer) *Provisioning {
+	return &Provisioning{
+		OperationsTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_operations_total",
+			Help:      "The total number of operations of the provisioning services, by outcome. Concurrent modifications of the configuration are reported with the conflict outcome.",
+		}, []string{"operation", "resource_type", "org", "outcome"}),
+		OperationDuration: promauto.With(r).NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_operation_duration_seconds",
+			Help:      "Histogram of the duration of operations of the provisioning services.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"operation", "resource_type", "outcome"}),
+		AlertmanagerConfigSize: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_alertmanager_config_size_bytes",
+			Help:      "The size of the latest Alertmanager configuration of the org that was read or saved by the provisioning services.",
+		}, []string{"org"}),
+		ContactPointCacheHits: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_contact_point_cache_hits_total",
+			Help:      "The total number of reads of contact points that used the cached configuration of the org.",
+		}),
+		ContactPointCacheMisses: promauto.With(r).NewCounter(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_contact_point_cache_misses_total",
+			Help:      "The total number of reads of contact points that loaded the configuration of the org from the store.",
+		}),
+		ProvenanceCleanupRemovedTotal: promauto.With(r).NewCounterVec(prometheus.CounterOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_orphaned_provenance_removed_total",
+			Help:      "The total number of provenance records that were removed because their resources no longer exist.",
+		}, []string{"resource_type"}),
+		PolicyTreeRoutes: promauto.With(r).NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Subsystem: Subsystem,
+			Name:      "provisioning_policy_tree_routes",
+			Help:      "The number of routes of the latest notification policy tree of the org that was saved by the provisioning services.",
+		}, []string{"org"}),
+	}
+}
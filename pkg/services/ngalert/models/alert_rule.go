@@ -117,8 +117,11 @@ const (
 var (
 	// InternalLabelNameSet are labels that grafana automatically include as part of the labelset.
 	InternalLabelNameSet = map[string]struct{}{
-		alertingModels.RuleUIDLabel:      {},
-		alertingModels.NamespaceUIDLabel: {},
+		alertingModels.RuleUIDLabel:         {},
+		alertingModels.NamespaceUIDLabel:    {},
+		AutogeneratedRouteLabel:             {},
+		AutogeneratedRouteReceiverNameLabel: {},
+		AutogeneratedRouteSettingsHashLabel: {},
 	}
 	InternalAnnotationNameSet = map[string]struct{}{
 		DashboardUIDAnnotation:              {},
@@ -134,6 +137,16 @@ type AlertRuleGroup struct {
 	Interval   int64
 	Provenance Provenance
 	Rules      []AlertRule
+	// EvaluationOffset is the number of seconds by which the evaluation of the group is delayed within its interval.
+	// It is only changed by setting the evaluation of the group, replacing the rules of a group keeps it.
+	EvaluationOffset int64
+}
+
+// RuleGroupEvaluation is when the rules of a group are evaluated: every IntervalSeconds, delayed by
+// EvaluationOffsetSeconds so that groups with the same interval can be evaluated at different times.
+type RuleGroupEvaluation struct {
+	IntervalSeconds         int64
+	EvaluationOffsetSeconds int64
 }
 
 // AlertRuleGroupWithFolderTitle extends AlertRuleGroup with orgID and folder title
@@ -167,6 +180,12 @@ type AlertRule struct {
 	Annotations map[string]string
 	Labels      map[string]string
 	IsPaused    bool
+	// NotificationSettings optionally send the notifications of the rule directly to a contact point instead of the
+	// notification policy tree. There is at most one of them.
+	NotificationSettings []NotificationSettings `xorm:"notification_settings"`
+	// EvaluationOffsetSeconds delays the evaluation of the rule within its interval. Like the interval, it is the same
+	// for all rules of a group.
+	EvaluationOffsetSeconds int64
 }
 
 // AlertRuleWithOptionals This is to avoid having to pass in additional arguments deep in the call stack. Alert rule
@@ -178,6 +197,8 @@ type AlertRuleWithOptionals struct {
 	// This parameter is to know if an optional API field was sent and, therefore, patch it with the current field from
 	// DB in case it was not sent.
 	HasPause bool
+	// HasNotificationSettings is whether the notification settings were sent, and are replaced even if they are empty.
+	HasNotificationSettings bool
 }
 
 // AlertsRulesBy is a function that defines the ordering of alert rules.
@@ -413,10 +434,12 @@ type AlertRuleVersion struct {
 	ExecErrState    ExecutionErrorState
 	// ideally this field should have been apimodels.ApiDuration
 	// but this is currently not possible because of circular dependencies
-	For         time.Duration
-	Annotations map[string]string
-	Labels      map[string]string
-	IsPaused    bool
+	For                     time.Duration
+	Annotations             map[string]string
+	Labels                  map[string]string
+	IsPaused                bool
+	NotificationSettings    []NotificationSettings `xorm:"notification_settings"`
+	EvaluationOffsetSeconds int64
 }
 
 // GetAlertRuleByUIDQuery is the query for retrieving/deleting an alert rule by UID and organisation ID.
@@ -442,6 +465,10 @@ type ListAlertRulesQuery struct {
 	// to return just those for a dashboard and panel.
 	DashboardUID string
 	PanelID      int64
+
+	// ReceiverName is optional and allows filtering rules to return just those whose notification settings send
+	// notifications to the contact point with this name.
+	ReceiverName string
 }
 
 // CountAlertRulesQuery is the query for counting alert rules
@@ -535,6 +562,9 @@ func PatchPartialAlertRule(existingRule *AlertRule, ruleToPatch *AlertRuleWithOp
 	if !ruleToPatch.HasPause {
 		ruleToPatch.IsPaused = existingRule.IsPaused
 	}
+	if !ruleToPatch.HasNotificationSettings {
+		ruleToPatch.NotificationSettings = existingRule.NotificationSettings
+	}
 }
 
 func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error {
@@ -545,6 +575,16 @@ func ValidateRuleGroupInterval(intervalSeconds, baseIntervalSeconds int64) error
 	return nil
 }
 
+// ValidateRuleGroupEvaluationOffset checks that the evaluation offset of a group is a multiple of the scheduler
+// interval that is shorter than the interval of the group.
+func ValidateRuleGroupEvaluationOffset(offsetSeconds, intervalSeconds, baseIntervalSeconds int64) error {
+	if offsetSeconds < 0 || offsetSeconds%baseIntervalSeconds != 0 || offsetSeconds >= intervalSeconds {
+		return fmt.Errorf("%w: evaluation offset (%v) should be divided exactly by scheduler interval %v and be shorter than the interval of the group (%v)",
+			ErrAlertRuleFailedValidation, time.Duration(offsetSeconds)*time.Second, baseIntervalSeconds, time.Duration(intervalSeconds)*time.Second)
+	}
+	return nil
+}
+
 type RulesGroup []*AlertRule
 
 func (g RulesGroup) SortByGroupIndex() {
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"strconv"
+)
+
+// AlertingSnapshot is a copy of the alerting configuration of an organization at a point in time: its Alertmanager
+// configuration, its alert rules and the provenance of its provisioned resources. Snapshots are taken periodically and
+// kept independently of the history of the Alertmanager configuration, so that an organization can be restored after
+// its configuration was damaged by many unwanted changes.
+type AlertingSnapshot struct {
+	ID    int64 `xorm:"pk autoincr 'id'"`
+	OrgID int64 `xorm:"org_id"`
+	// AlertmanagerConfiguration is empty if the organization had no Alertmanager configuration yet.
+	AlertmanagerConfiguration string `xorm:"alertmanager_configuration"`
+	// Rules is the JSON representation of the alert rules of the organization.
+	Rules string `xorm:"rules"`
+	// Provenances is the JSON representation of the provenances of the organization by resource type and resource ID.
+	Provenances string `xorm:"provenances"`
+	Created     int64  `xorm:"'created'"`
+}
+
+func (s AlertingSnapshot) TableName() string {
+	return "alerting_snapshot"
+}
+
+func (s *AlertingSnapshot) ResourceType() string {
+	return "alertingSnapshot"
+}
+
+func (s *AlertingSnapshot) ResourceID() string {
+	return strconv.FormatInt(s.ID, 10)
+}
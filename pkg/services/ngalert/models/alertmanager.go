@@ -1,7 +1,22 @@
 package models
 
+import "github.com/grafana/grafana/pkg/services/quota"
+
 const AlertConfigurationVersion = 1
 
+// Quotas of the notification resources of the Alertmanager configuration of an org. Each kind of resource is a target
+// service of its own, as the quota service checks all targets of a service at once.
+const (
+	QuotaTargetSrvContactPoint       quota.TargetSrv = "ngalert_contact_point"
+	QuotaTargetContactPoint          quota.Target    = "alert_contact_point"
+	QuotaTargetSrvTemplate           quota.TargetSrv = "ngalert_template"
+	QuotaTargetTemplate              quota.Target    = "alert_template"
+	QuotaTargetSrvMuteTiming         quota.TargetSrv = "ngalert_mute_timing"
+	QuotaTargetMuteTiming            quota.Target    = "alert_mute_timing"
+	QuotaTargetSrvNotificationPolicy quota.TargetSrv = "ngalert_notification_policy"
+	QuotaTargetNotificationPolicy    quota.Target    = "alert_notification_policy"
+)
+
 // AlertConfiguration represents a single version of the Alerting Engine Configuration.
 type AlertConfiguration struct {
 	ID int64 `xorm:"pk autoincr 'id'"`
@@ -12,6 +27,8 @@ type AlertConfiguration struct {
 	CreatedAt                 int64 `xorm:"created"`
 	Default                   bool
 	OrgID                     int64 `xorm:"org_id"`
+	// CorrelationID identifies the request that saved the configuration, for example its trace ID.
+	CorrelationID string `xorm:"correlation_id"`
 }
 
 // HistoricAlertConfiguration represents a previously used alerting configuration.
@@ -37,6 +54,8 @@ type SaveAlertmanagerConfigurationCmd struct {
 	Default                   bool
 	OrgID                     int64
 	LastApplied               int64
+	// CorrelationID identifies the request that saves the configuration. Optional.
+	CorrelationID string
 }
 
 // MarkConfigurationAsAppliedCmd is the command for marking a previously saved configuration as successfully applied.
@@ -0,0 +1,51 @@
+package models
+
+// AlertConfigurationResourceKind is the kind of a resource of an Alertmanager configuration that is stored in a row
+// of its own.
+type AlertConfigurationResourceKind string
+
+const (
+	AlertConfigurationResourceReceiver         AlertConfigurationResourceKind = "receiver"
+	AlertConfigurationResourceMuteTimeInterval AlertConfigurationResourceKind = "mute_time_interval"
+	AlertConfigurationResourceTemplate         AlertConfigurationResourceKind = "template"
+)
+
+// AlertConfigurationResource is a receiver, mute time interval or template of the Alertmanager configuration of an
+// organization. Once the configuration of an organization is changed one resource at a time, its resources are left
+// out of the stored configuration document and the full configuration is materialized from both when it is read.
+type AlertConfigurationResource struct {
+	ID    int64                          `xorm:"pk autoincr 'id'"`
+	OrgID int64                          `xorm:"org_id"`
+	Kind  AlertConfigurationResourceKind `xorm:"kind"`
+	Name  string                         `xorm:"name"`
+	// Content is the JSON representation of the resource, as it appears in the configuration document.
+	Content string `xorm:"content"`
+	Updated int64  `xorm:"updated"`
+}
+
+func (r AlertConfigurationResource) TableName() string {
+	return "alert_configuration_resource"
+}
+
+// AlertConfigurationResourceChange is a change of a single resource of an Alertmanager configuration.
+type AlertConfigurationResourceChange struct {
+	Kind AlertConfigurationResourceKind
+	// Name is the name of the resource after the change.
+	Name string
+	// OldName is the name of the resource before the change if the change renames it. A renamed resource keeps its
+	// position in the configuration.
+	OldName string
+	// Content is the JSON representation of the resource after the change. The resource is deleted if it is empty.
+	Content string
+}
+
+// UpdateAlertmanagerConfigResourcesCmd is the command to change single resources of an Alertmanager configuration
+// with optimistic locking.
+type UpdateAlertmanagerConfigResourcesCmd struct {
+	OrgID                    int64
+	FetchedConfigurationHash string
+	ConfigurationVersion     string
+	Changes                  []AlertConfigurationResourceChange
+	// CorrelationID identifies the request that changes the configuration. Optional.
+	CorrelationID string
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// IntegrationStatus is the delivery status of an integration of a contact point since the Alertmanager of the
+// organization started.
+type IntegrationStatus struct {
+	// LastNotifyAttempt is when the integration last tried to send a notification.
+	LastNotifyAttempt time.Time
+	// LastNotifyAttemptDuration is how long the last attempt took.
+	LastNotifyAttemptDuration time.Duration
+	// LastNotifyAttemptError is the error of the last attempt, empty if it succeeded.
+	LastNotifyAttemptError string
+	// ConsecutiveFailures is the number of attempts that failed since the last successful one.
+	ConsecutiveFailures int
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+const (
+	// AutogeneratedRouteLabel marks the alerts of rules with notification settings. They are routed by the routes
+	// that are generated from the notification settings, instead of the notification policy tree.
+	AutogeneratedRouteLabel = "__grafana_autogenerated__"
+	// AutogeneratedRouteReceiverNameLabel is the label with the receiver of the notification settings of the rule.
+	AutogeneratedRouteReceiverNameLabel = "__grafana_receiver__"
+	// AutogeneratedRouteSettingsHashLabel is the label with the fingerprint of the notification settings of the rule,
+	// which selects the generated route of the settings.
+	AutogeneratedRouteSettingsHashLabel = "__grafana_route_settings_hash__"
+)
+
+// NotificationSettings send the notifications of an alert rule directly to a contact point, bypassing the
+// notification policy tree. A rule has at most one of them.
+type NotificationSettings struct {
+	// Receiver is the name of the contact point the notifications are sent to.
+	Receiver string `json:"receiver"`
+	// MuteTimeIntervals are the names of the mute timings during which no notifications are sent.
+	MuteTimeIntervals []string `json:"mute_time_intervals,omitempty"`
+}
+
+// Validate checks that the settings are complete. It does not check that the referenced contact point and mute
+// timings exist.
+func (s NotificationSettings) Validate() error {
+	if s.Receiver == "" {
+		return fmt.Errorf("%w: receiver must be specified", ErrAlertRuleFailedValidation)
+	}
+	seen := make(map[string]struct{}, len(s.MuteTimeIntervals))
+	for _, name := range s.MuteTimeIntervals {
+		if name == "" {
+			return fmt.Errorf("%w: mute time interval names must not be empty", ErrAlertRuleFailedValidation)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("%w: mute time interval '%s' is listed more than once", ErrAlertRuleFailedValidation, name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+// Fingerprint returns a hash of the settings that is the same for equal settings, regardless of the order of the
+// mute time intervals.
+func (s NotificationSettings) Fingerprint() string {
+	h := fnv.New64()
+	writeString := func(v string) {
+		// Strings are prefixed with their length, so that their concatenation is unambiguous.
+		var length [8]byte
+		binary.LittleEndian.PutUint64(length[:], uint64(len(v)))
+		_, _ = h.Write(length[:])
+		_, _ = h.Write([]byte(v))
+	}
+	writeString(s.Receiver)
+	mutes := append([]string(nil), s.MuteTimeIntervals...)
+	sort.Strings(mutes)
+	for _, name := range mutes {
+		writeString(name)
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+// Labels returns the labels that route the alerts of a rule with the settings to the generated route of the settings.
+func (s NotificationSettings) Labels() map[string]string {
+	return map[string]string{
+		AutogeneratedRouteLabel:             "true",
+		AutogeneratedRouteReceiverNameLabel: s.Receiver,
+		AutogeneratedRouteSettingsHashLabel: s.Fingerprint(),
+	}
+}
+
+// UsesReceiver reports whether the notification settings of the rule send notifications to the contact point with the
+// given name.
+func (alertRule *AlertRule) UsesReceiver(name string) bool {
+	for _, s := range alertRule.NotificationSettings {
+		if s.Receiver == name {
+			return true
+		}
+	}
+	return false
+}
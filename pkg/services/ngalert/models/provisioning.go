@@ -8,6 +8,15 @@ const (
 	ProvenanceNone Provenance = ""
 	ProvenanceAPI  Provenance = "api"
 	ProvenanceFile Provenance = "file"
+	// ProvenanceGlobal reflects that the object is inherited from the contact points or templates defined for all
+	// organizations of the instance. It is read-only in the organization.
+	ProvenanceGlobal Provenance = "global"
+	// ProvenanceRemote reflects that the object is synchronized from another Grafana instance. It can only be changed
+	// on that instance.
+	ProvenanceRemote Provenance = "remote"
+	// ProvenanceConvertedPrometheus reflects that the object was imported from the configuration of a Prometheus
+	// Alertmanager.
+	ProvenanceConvertedPrometheus Provenance = "converted_prometheus"
 )
 
 // Provisionable represents a resource that can be created through a provisioning mechanism, such as Terraform or config file.
@@ -0,0 +1,95 @@
+package models
+
+import (
+	"time"
+)
+
+// ProvisioningAuditAction is the kind of change that was made to a provisioned resource.
+type ProvisioningAuditAction string
+
+const (
+	ProvisioningAuditActionCreate ProvisioningAuditAction = "create"
+	ProvisioningAuditActionUpdate ProvisioningAuditAction = "update"
+	ProvisioningAuditActionDelete ProvisioningAuditAction = "delete"
+	// ProvisioningAuditActionRestore records that the configuration of an organization was restored from a snapshot
+	// or rolled back to a previous version, or that a deleted contact point was restored from the trash.
+	ProvisioningAuditActionRestore ProvisioningAuditAction = "restore"
+	// ProvisioningAuditActionExpire records that a temporary contact point, the mute timing of a maintenance window or
+	// a provisioned silence was removed because it expired.
+	ProvisioningAuditActionExpire ProvisioningAuditAction = "expire"
+	// ProvisioningAuditActionDecrypt records that the secure settings of a contact point were read in clear text, for
+	// example by an export with decrypted secrets. It does not change the resource.
+	ProvisioningAuditActionDecrypt ProvisioningAuditAction = "decrypt"
+	// ProvisioningAuditActionDisable records that a contact point was disabled because it failed to send notifications
+	// too many times in a row. The source of the entry is the error of the last attempt.
+	ProvisioningAuditActionDisable ProvisioningAuditAction = "disable"
+	// ProvisioningAuditActionEnable records that a disabled contact point was enabled again.
+	ProvisioningAuditActionEnable ProvisioningAuditAction = "enable"
+)
+
+// ProvisioningAuditEntry records a change made to a resource through the provisioning services.
+type ProvisioningAuditEntry struct {
+	ID    int64 `xorm:"pk autoincr 'id'"`
+	OrgID int64 `xorm:"org_id"`
+	// ActorID and ActorLogin identify the user who made the change.
+	// They are empty when the change was made by Grafana itself, for example when provisioning from files.
+	ActorID      int64  `xorm:"actor_id"`
+	ActorLogin   string `xorm:"actor_login"`
+	Action       ProvisioningAuditAction
+	ResourceType string `xorm:"resource_type"`
+	ResourceID   string `xorm:"resource_id"`
+	Provenance   Provenance
+	// Source is where the change comes from when it is not made by a user, for example the path of the provisioning
+	// file.
+	Source string `xorm:"source"`
+	// CorrelationID identifies the request that made the change, for example its trace ID.
+	CorrelationID string `xorm:"correlation_id"`
+	// OldState and NewState are the JSON representations of the resource before and after the change, with secure
+	// settings redacted. OldState is empty for creations and NewState is empty for deletions. Decryptions record the
+	// decrypted resource as NewState.
+	OldState string `xorm:"old_state"`
+	NewState string `xorm:"new_state"`
+	Created  int64  `xorm:"'created'"`
+}
+
+func (e ProvisioningAuditEntry) TableName() string {
+	return "provisioning_audit_log"
+}
+
+// ProvisioningAuditQuery is the query for entries of the provisioning audit log.
+// Entries are returned from the most recent to the oldest.
+type ProvisioningAuditQuery struct {
+	OrgID        int64
+	ResourceType string
+	ResourceID   string
+	From         time.Time
+	To           time.Time
+	Limit        int
+}
+
+// ResourceHistoryPage selects a page of the changes of a single resource.
+type ResourceHistoryPage struct {
+	// Offset is the number of most recent changes to skip.
+	Offset int
+	// Limit is the maximum number of changes to return. Zero returns the default number of changes.
+	Limit int
+}
+
+// ProvisioningChangeEvent is published on the event bus for every change made through the provisioning services,
+// once the change is committed.
+type ProvisioningChangeEvent struct {
+	OrgID        int64                   `json:"orgId"`
+	Action       ProvisioningAuditAction `json:"action"`
+	ResourceType string                  `json:"resourceType"`
+	ResourceID   string                  `json:"resourceId"`
+	Provenance   Provenance              `json:"provenance"`
+	// ActorID and ActorLogin identify the user who made the change, if any.
+	ActorID       int64  `json:"actorId,omitempty"`
+	ActorLogin    string `json:"actorLogin,omitempty"`
+	Source        string `json:"source,omitempty"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	// Changes are the paths of the fields of the resource that were changed by an update, for example
+	// settings.recipient. They do not include any values.
+	Changes []string  `json:"changes,omitempty"`
+	Time    time.Time `json:"time"`
+}
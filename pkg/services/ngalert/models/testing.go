@@ -305,20 +305,21 @@ func GenerateGroupKey(orgID int64) AlertRuleGroupKey {
 // CopyRule creates a deep copy of AlertRule
 func CopyRule(r *AlertRule) *AlertRule {
 	result := AlertRule{
-		ID:              r.ID,
-		OrgID:           r.OrgID,
-		Title:           r.Title,
-		Condition:       r.Condition,
-		Updated:         r.Updated,
-		IntervalSeconds: r.IntervalSeconds,
-		Version:         r.Version,
-		UID:             r.UID,
-		NamespaceUID:    r.NamespaceUID,
-		RuleGroup:       r.RuleGroup,
-		RuleGroupIndex:  r.RuleGroupIndex,
-		NoDataState:     r.NoDataState,
-		ExecErrState:    r.ExecErrState,
-		For:             r.For,
+		ID:                      r.ID,
+		OrgID:                   r.OrgID,
+		Title:                   r.Title,
+		Condition:               r.Condition,
+		Updated:                 r.Updated,
+		IntervalSeconds:         r.IntervalSeconds,
+		EvaluationOffsetSeconds: r.EvaluationOffsetSeconds,
+		Version:                 r.Version,
+		UID:                     r.UID,
+		NamespaceUID:            r.NamespaceUID,
+		RuleGroup:               r.RuleGroup,
+		RuleGroupIndex:          r.RuleGroupIndex,
+		NoDataState:             r.NoDataState,
+		ExecErrState:            r.ExecErrState,
+		For:                     r.For,
 	}
 
 	if r.DashboardUID != nil {
@@ -356,6 +357,11 @@ func CopyRule(r *AlertRule) *AlertRule {
 		}
 	}
 
+	for _, ns := range r.NotificationSettings {
+		ns.MuteTimeIntervals = append([]string(nil), ns.MuteTimeIntervals...)
+		result.NotificationSettings = append(result.NotificationSettings, ns)
+	}
+
 	return &result
 }
 
@@ -31,6 +31,7 @@ import (
 	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/secretrefs"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
 	"github.com/grafana/grafana/pkg/services/ngalert/sender"
@@ -138,6 +139,19 @@ type AlertNG struct {
 	accesscontrolService accesscontrol.Service
 	annotationsRepo      annotations.Repository
 	store                *store.DBstore
+	usageStats           *provisioning.UsageStatsService
+	globalContactPoints  *provisioning.GlobalContactPointService
+	globalTemplates      *provisioning.GlobalTemplateService
+	snapshots            *provisioning.SnapshotService
+	provenance           *provisioning.ProvenanceService
+	contactPoints        *provisioning.ContactPointService
+	maintenanceWindows   *provisioning.MaintenanceWindowService
+	silences             *provisioning.SilenceService
+	bundleScheduler      *provisioning.BundleScheduler
+	orgBootstrap         *provisioning.OrgBootstrapService
+	variables            *provisioning.ProvisioningVariablesService
+	provisioningFreeze   *provisioning.ProvisioningFreezeService
+	provisioningWebhook  *provisioning.ProvisioningEventWebhook
 
 	bus          bus.Bus
 	pluginsStore plugins.Store
@@ -153,7 +167,11 @@ func (ng *AlertNG) init() error {
 
 	ng.store.Logger = ng.Log
 
-	decryptFn := ng.SecretsService.GetDecryptedValue
+	secretRefs, err := secretrefs.NewResolver(ng.Cfg.UnifiedAlerting.ContactPointSecretReferences)
+	if err != nil {
+		return fmt.Errorf("invalid contact_point_secret_references: %w", err)
+	}
+	decryptFn := notifier.WithExternalSecretRefs(ng.SecretsService.GetDecryptedValue, secretRefs, log.New("ngalert.notifier.secrets"))
 	multiOrgMetrics := ng.Metrics.GetMultiOrgAlertmanagerMetrics()
 	ng.MultiOrgAlertmanager, err = notifier.NewMultiOrgAlertmanager(ng.Cfg, ng.store, ng.store, ng.KVStore, ng.store, decryptFn, multiOrgMetrics, ng.NotificationService, log.New("ngalert.multiorg.alertmanager"), ng.SecretsService)
 	if err != nil {
@@ -235,13 +253,49 @@ func (ng *AlertNG) init() error {
 	ng.schedule = scheduler
 
 	// Provisioning
-	policyService := provisioning.NewNotificationPolicyService(ng.store, ng.store, ng.store, ng.Cfg.UnifiedAlerting, ng.Log)
-	contactPointService := provisioning.NewContactPointService(ng.store, ng.SecretsService, ng.store, ng.store, ng.Log, ng.accesscontrol)
-	templateService := provisioning.NewTemplateService(ng.store, ng.store, ng.store, ng.Log)
-	muteTimingService := provisioning.NewMuteTimingService(ng.store, ng.store, ng.store, ng.Log)
-	alertRuleService := provisioning.NewAlertRuleService(ng.store, ng.store, ng.dashboardService, ng.QuotaService, ng.store,
+	var amConfigStore provisioning.AMConfigStore = ng.store
+	if perSecond := ng.Cfg.UnifiedAlerting.ProvisioningConfigWriteRate; perSecond > 0 {
+		amConfigStore = provisioning.NewRateLimitedAMConfigStore(amConfigStore, perSecond, ng.Cfg.UnifiedAlerting.ProvisioningConfigWriteBurst)
+	}
+	provisioningMetrics := ng.Metrics.GetProvisioningMetrics()
+	// Changes made through the API are annotated so that they can be correlated with notifications on dashboards.
+	provisioningStore := provisioning.NewAnnotatingProvisioningStore(ng.store, ng.annotationsRepo, log.New("ngalert.provisioning.annotations"))
+	// Changes are published on the event bus once they are committed, and optionally sent to a webhook.
+	provisioningStore = provisioning.NewPublishingProvisioningStore(provisioningStore, ng.store, log.New("ngalert.provisioning.events"))
+	if url := ng.Cfg.UnifiedAlerting.ProvisioningEventsWebhookURL; url != "" {
+		ng.provisioningWebhook = provisioning.NewProvisioningEventWebhook(url, log.New("ngalert.provisioning.webhook"))
+		ng.bus.AddEventListener(ng.provisioningWebhook.Handle)
+	}
+	policyService := provisioning.NewNotificationPolicyService(amConfigStore, provisioningStore, ng.store, ng.QuotaService, ng.Cfg.UnifiedAlerting, ng.Log, ng.tracer, provisioningMetrics)
+	ng.variables = provisioning.NewProvisioningVariablesService(ng.KVStore, ng.tracer, provisioningMetrics)
+	contactPointService := provisioning.NewContactPointService(amConfigStore, ng.SecretsService, secretRefs, provisioningStore, ng.store,
+		provisioning.NewContactPointExpirationStore(ng.KVStore), provisioning.NewDeletedContactPointStore(ng.KVStore, ng.Cfg.UnifiedAlerting.DeletedContactPointRetention),
+		ng.variables, ng.MultiOrgAlertmanager, ng.MultiOrgAlertmanager, notifications.NewWebhookClient(ng.Cfg.UnifiedAlerting.NotificationBlockedIPRanges), ng.store, ng.store, ng.QuotaService, ng.Log, ng.accesscontrol, ng.tracer, provisioningMetrics)
+	templateService := provisioning.NewTemplateService(amConfigStore, provisioningStore, ng.store, ng.QuotaService, ng.Log, ng.tracer, provisioningMetrics)
+	muteTimingService := provisioning.NewMuteTimingService(amConfigStore, provisioningStore, ng.store, ng.store, ng.QuotaService, ng.Log, ng.tracer, provisioningMetrics)
+	alertRuleService := provisioning.NewAlertRuleService(ng.store, provisioningStore, amConfigStore, ng.dashboardService, ng.QuotaService, ng.store, ng.stateManager,
 		int64(ng.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
-		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log)
+		int64(ng.Cfg.UnifiedAlerting.BaseInterval.Seconds()), ng.Log, ng.accesscontrol, ng.tracer, provisioningMetrics)
+	alertRuleTestService := provisioning.NewAlertRuleTestService(evalFactory, ng.dashboardService, ng.Cfg.UnifiedAlerting, appUrl, ng.Log, ng.tracer, provisioningMetrics)
+	healthService := provisioning.NewHealthService(amConfigStore, ng.SecretsService, provisioning.NewFileProvisioningStatusStore(ng.KVStore), ng.Log, ng.tracer, provisioningMetrics)
+	effectiveConfigService := provisioning.NewEffectiveConfigService(amConfigStore, ng.store, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.usageStats = provisioning.NewUsageStatsService(ng.store, ng.Log)
+	ng.snapshots = provisioning.NewSnapshotService(ng.store, amConfigStore, ng.store, provisioningStore, ng.store, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.provenance = provisioning.NewProvenanceService(amConfigStore, ng.store, provisioningStore, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	alertmanagerImportService := provisioning.NewAlertmanagerImportService(contactPointService, policyService, muteTimingService, templateService, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	configHistoryService := provisioning.NewConfigHistoryService(ng.store, amConfigStore, provisioningStore, ng.store, ng.SecretsService, ng.Log, ng.tracer, provisioningMetrics)
+	bundleService := provisioning.NewBundleService(contactPointService, policyService, muteTimingService, templateService, alertRuleService, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.contactPoints = contactPointService
+	ng.provisioningFreeze = provisioning.NewProvisioningFreezeService(ng.KVStore, ng.Cfg.UnifiedAlerting.ProvisioningFrozenOrgs,
+		ng.Cfg.UnifiedAlerting.ProvisioningFreezeAllowedServiceAccounts, ng.Log, ng.tracer, provisioningMetrics)
+	ng.bundleScheduler = provisioning.NewBundleScheduler(bundleService, ng.provisioningFreeze, ng.KVStore, ng.SecretsService, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	// New organizations are marked when they are created, and bootstrapped with the bundle once they have a configuration.
+	ng.orgBootstrap = provisioning.NewOrgBootstrapService(bundleService, ng.provisioningFreeze, ng.KVStore, ng.SecretsService, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.bus.AddEventListener(ng.orgBootstrap.HandleOrgCreated)
+	ng.maintenanceWindows = provisioning.NewMaintenanceWindowService(amConfigStore, provisioningStore, ng.KVStore, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.silences = provisioning.NewSilenceService(ng.MultiOrgAlertmanager, provisioningStore, ng.KVStore, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.globalContactPoints = provisioning.NewGlobalContactPointService(ng.KVStore, amConfigStore, ng.SecretsService, provisioningStore, ng.store, ng.store, ng.Log, ng.tracer, provisioningMetrics)
+	ng.globalTemplates = provisioning.NewGlobalTemplateService(ng.KVStore, amConfigStore, provisioningStore, ng.store, ng.store, ng.Log, ng.tracer, provisioningMetrics)
 
 	ng.api = &api.API{
 		Cfg:                  ng.Cfg,
@@ -254,6 +308,8 @@ func (ng *AlertNG) init() error {
 		RuleStore:            ng.store,
 		AlertingStore:        ng.store,
 		AdminConfigStore:     ng.store,
+		OrgStore:             ng.store,
+		ProvisioningAudit:    ng.store,
 		ProvenanceStore:      ng.store,
 		MultiOrgAlertmanager: ng.MultiOrgAlertmanager,
 		StateManager:         ng.stateManager,
@@ -263,6 +319,22 @@ func (ng *AlertNG) init() error {
 		Templates:            templateService,
 		MuteTimings:          muteTimingService,
 		AlertRules:           alertRuleService,
+		ConfigHealth:         healthService,
+		AlertRuleTester:      alertRuleTestService,
+		EffectiveConfig:      effectiveConfigService,
+		GlobalContactPoints:  ng.globalContactPoints,
+		GlobalTemplates:      ng.globalTemplates,
+		Snapshots:            ng.snapshots,
+		AlertmanagerImport:   alertmanagerImportService,
+		ConfigHistory:        configHistoryService,
+		Bundles:              bundleService,
+		BundleScheduler:      ng.bundleScheduler,
+		OrgBootstrap:         ng.orgBootstrap,
+		MaintenanceWindows:   ng.maintenanceWindows,
+		Silences:             ng.silences,
+		Variables:            ng.variables,
+		ProvisioningFreeze:   ng.provisioningFreeze,
+		Provenance:           ng.provenance,
 		AlertsRouter:         alertsRouter,
 		EvaluatorFactory:     evalFactory,
 		FeatureManager:       ng.FeatureToggles,
@@ -286,6 +358,10 @@ func (ng *AlertNG) init() error {
 		return err
 	}
 
+	if err := registerNotificationQuotaReporters(ng.Cfg, ng.QuotaService, ng.store); err != nil {
+		return err
+	}
+
 	log.RegisterContextualLogProvider(func(ctx context.Context) ([]interface{}, bool) {
 		key, ok := models.RuleKeyFromContext(ctx)
 		if !ok {
@@ -337,6 +413,103 @@ func (ng *AlertNG) Run(ctx context.Context) error {
 			return ng.schedule.Run(subCtx)
 		})
 	}
+	children.Go(func() error {
+		return ng.bundleScheduler.Run(subCtx)
+	})
+	children.Go(func() error {
+		// Organizations created since the last run inherit the global contact points and templates as well, and are
+		// bootstrapped with the bootstrap bundle.
+		for {
+			if err := ng.globalContactPoints.SyncGlobalContactPoints(subCtx); err != nil {
+				ng.Log.Error("Failed to synchronize global contact points", "error", err)
+			}
+			if err := ng.globalTemplates.SyncGlobalTemplates(subCtx); err != nil {
+				ng.Log.Error("Failed to synchronize global templates", "error", err)
+			}
+			if err := ng.orgBootstrap.BootstrapOrgs(subCtx); err != nil {
+				ng.Log.Error("Failed to bootstrap new organizations", "error", err)
+			}
+			select {
+			case <-subCtx.Done():
+				return nil
+			case <-time.After(ng.Cfg.UnifiedAlerting.AlertmanagerConfigPollInterval):
+			}
+		}
+	})
+	children.Go(func() error {
+		for {
+			if err := ng.contactPoints.ExpireContactPoints(subCtx, time.Now()); err != nil {
+				ng.Log.Error("Failed to remove expired contact points", "error", err)
+			}
+			if err := ng.contactPoints.PurgeDeletedContactPoints(subCtx, time.Now()); err != nil {
+				ng.Log.Error("Failed to purge deleted contact points", "error", err)
+			}
+			if err := ng.contactPoints.DisableFailingContactPoints(subCtx, ng.Cfg.UnifiedAlerting.DisableContactPointsAfterFailures); err != nil {
+				ng.Log.Error("Failed to disable failing contact points", "error", err)
+			}
+			if err := ng.maintenanceWindows.ExpireMaintenanceWindows(subCtx, time.Now()); err != nil {
+				ng.Log.Error("Failed to remove expired maintenance windows", "error", err)
+			}
+			if err := ng.silences.SyncSilences(subCtx, time.Now()); err != nil {
+				ng.Log.Error("Failed to synchronize provisioned silences", "error", err)
+			}
+			select {
+			case <-subCtx.Done():
+				return nil
+			case <-time.After(ng.Cfg.UnifiedAlerting.AlertmanagerConfigPollInterval):
+			}
+		}
+	})
+	if interval := ng.Cfg.UnifiedAlerting.AlertingSnapshotInterval; interval > 0 {
+		children.Go(func() error {
+			for {
+				select {
+				case <-subCtx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+				if err := ng.snapshots.TakeSnapshots(subCtx); err != nil {
+					ng.Log.Error("Failed to take snapshots of the alerting configuration", "error", err)
+				}
+				if err := ng.snapshots.PruneSnapshots(subCtx, ng.Cfg.UnifiedAlerting.AlertingSnapshotRetention); err != nil {
+					ng.Log.Error("Failed to delete expired snapshots of the alerting configuration", "error", err)
+				}
+			}
+		})
+	}
+	if interval := ng.Cfg.UnifiedAlerting.ContactPointProbeInterval; interval > 0 {
+		children.Go(func() error {
+			for {
+				select {
+				case <-subCtx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+				if err := ng.contactPoints.ProbeContactPoints(subCtx, time.Now()); err != nil {
+					ng.Log.Error("Failed to probe contact points", "error", err)
+				}
+			}
+		})
+	}
+	if interval := ng.Cfg.UnifiedAlerting.ProvenanceCleanupInterval; interval > 0 {
+		children.Go(func() error {
+			for {
+				select {
+				case <-subCtx.Done():
+					return nil
+				case <-time.After(interval):
+				}
+				if _, err := ng.provenance.CleanupOrphanedProvenance(subCtx); err != nil {
+					ng.Log.Error("Failed to clean up orphaned provenance records", "error", err)
+				}
+			}
+		})
+	}
+	if ng.provisioningWebhook != nil {
+		children.Go(func() error {
+			return ng.provisioningWebhook.Run(subCtx)
+		})
+	}
 	return children.Wait()
 }
 
@@ -354,6 +527,15 @@ func (ng *AlertNG) GetHooks() *api.Hooks {
 	return ng.api.Hooks
 }
 
+// GetUsageStats returns the anonymous usage stats of the alerting configurations of the instance. It returns nothing
+// if the alerting service is disabled.
+func (ng *AlertNG) GetUsageStats(ctx context.Context) map[string]any {
+	if ng.usageStats == nil {
+		return nil
+	}
+	return ng.usageStats.GetUsageStats(ctx)
+}
+
 func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 	limits := &quota.Map{}
 
@@ -383,6 +565,38 @@ func readQuotaConfig(cfg *setting.Cfg) (*quota.Map, error) {
 	return limits, nil
 }
 
+// registerNotificationQuotaReporters registers the quotas of the notification resources of orgs. Only org limits are
+// supported, as the resources are counted in the Alertmanager configuration of each org.
+func registerNotificationQuotaReporters(cfg *setting.Cfg, quotaService quota.Service, amStore provisioning.AMConfigStore) error {
+	limits := map[quota.TargetSrv]int64{}
+	if cfg != nil {
+		limits[models.QuotaTargetSrvContactPoint] = cfg.Quota.Org.AlertContactPoint
+		limits[models.QuotaTargetSrvTemplate] = cfg.Quota.Org.AlertTemplate
+		limits[models.QuotaTargetSrvMuteTiming] = cfg.Quota.Org.AlertMuteTiming
+		limits[models.QuotaTargetSrvNotificationPolicy] = cfg.Quota.Org.AlertNotificationPolicy
+	}
+	for _, q := range provisioning.NotificationQuotas {
+		tag, err := quota.NewTag(q.TargetSrv, q.Target, quota.OrgScope)
+		if err != nil {
+			return err
+		}
+		defaultLimits := &quota.Map{}
+		limit, ok := limits[q.TargetSrv]
+		if !ok {
+			limit = -1
+		}
+		defaultLimits.Set(tag, limit)
+		if err := quotaService.RegisterQuotaReporter(&quota.NewUsageReporter{
+			TargetSrv:     q.TargetSrv,
+			DefaultLimits: defaultLimits,
+			Reporter:      q.Reporter(amStore),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type Historian interface {
 	api.Historian
 	state.Historian
@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"time"
 
+	alertingImages "github.com/grafana/alerting/images"
 	alertingNotify "github.com/grafana/alerting/notify"
 	"github.com/grafana/alerting/receivers"
 	alertingTemplates "github.com/grafana/alerting/templates"
@@ -42,6 +43,7 @@ var silenceMaintenanceInterval = 15 * time.Minute
 type AlertingStore interface {
 	store.AlertingStore
 	store.ImageStore
+	NotificationSettingsStore
 }
 
 type Alertmanager struct {
@@ -56,6 +58,12 @@ type Alertmanager struct {
 
 	decryptFn alertingNotify.GetDecryptedValueFn
 	orgID     int64
+
+	// sender and imageProvider are shared by all integrations of the org.
+	sender            *sender
+	imageProvider     alertingImages.Provider
+	testIntegrations  *integrationPool
+	integrationStatus *integrationStatusTracker
 }
 
 // maintenanceOptions represent the options for components that need maintenance on a frequency within the Alertmanager.
@@ -143,6 +151,10 @@ func newAlertmanager(ctx context.Context, orgID int64, cfg *setting.Cfg, store A
 		decryptFn:           decryptFn,
 		fileStore:           fileStore,
 		logger:              l,
+		sender:              &sender{ns},
+		imageProvider:       newImageProvider(store, log.New("ngalert.notifier.image-provider")),
+		testIntegrations:    newIntegrationPool(testIntegrationPoolSize),
+		integrationStatus:   newIntegrationStatusTracker(),
 	}
 
 	return am, nil
@@ -179,7 +191,7 @@ func (am *Alertmanager) SaveAndApplyDefaultConfig(ctx context.Context) error {
 		}
 
 		err = am.Store.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
-			_, err := am.applyConfig(cfg, []byte(am.Settings.UnifiedAlerting.DefaultConfiguration))
+			_, err := am.applyConfig(ctx, cfg, []byte(am.Settings.UnifiedAlerting.DefaultConfiguration))
 			return err
 		})
 		if err != nil {
@@ -209,7 +221,7 @@ func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.P
 		}
 
 		err = am.Store.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error {
-			_, err := am.applyConfig(cfg, rawConfig)
+			_, err := am.applyConfig(ctx, cfg, rawConfig)
 			return err
 		})
 		if err != nil {
@@ -224,7 +236,7 @@ func (am *Alertmanager) SaveAndApplyConfig(ctx context.Context, cfg *apimodels.P
 // ApplyConfig applies the configuration to the Alertmanager.
 func (am *Alertmanager) ApplyConfig(ctx context.Context, dbCfg *ngmodels.AlertConfiguration) error {
 	var err error
-	cfg, err := Load([]byte(dbCfg.AlertmanagerConfiguration))
+	cfg, err := loadStored(dbCfg.AlertmanagerConfiguration)
 	if err != nil {
 		return fmt.Errorf("failed to parse Alertmanager config: %w", err)
 	}
@@ -281,7 +293,17 @@ func (am *Alertmanager) aggregateInhibitMatchers(rules []config.InhibitRule, amu
 // applyConfig applies a new configuration by re-initializing all components using the configuration provided.
 // It returns a boolean indicating whether the user config was changed and an error.
 // It is not safe to call concurrently.
-func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig []byte) (bool, error) {
+func (am *Alertmanager) applyConfig(ctx context.Context, cfg *apimodels.PostableUserConfig, rawConfig []byte) (bool, error) {
+	// Routes are generated for the notification settings of alert rules, they are not part of the stored configuration.
+	settings, err := am.Store.ListNotificationSettings(ctx, am.orgID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list the notification settings of alert rules: %w", err)
+	}
+	if withRoutes := withAutogeneratedRoutes(cfg, settings, am.logger); withRoutes != cfg {
+		cfg = withRoutes
+		rawConfig = nil
+	}
+
 	// First, let's make sure this config is not already loaded
 	var amConfigChanged bool
 	if rawConfig == nil {
@@ -316,6 +338,9 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 	}
 
 	am.updateConfigMetrics(cfg)
+	// Disabled integrations are not built. Their delivery status is dropped, so that they start over when they are
+	// enabled again.
+	am.integrationStatus.forget(disabledIntegrations(cfg.AlertmanagerConfig))
 
 	err = am.Base.ApplyConfig(AlertingConfiguration{
 		rawAlertmanagerConfig:    rawConfig,
@@ -332,7 +357,7 @@ func (am *Alertmanager) applyConfig(cfg *apimodels.PostableUserConfig, rawConfig
 
 // applyAndMarkConfig applies a configuration and marks it as applied if no errors occur.
 func (am *Alertmanager) applyAndMarkConfig(ctx context.Context, hash string, cfg *apimodels.PostableUserConfig, rawConfig []byte) error {
-	configChanged, err := am.applyConfig(cfg, rawConfig)
+	configChanged, err := am.applyConfig(ctx, cfg, rawConfig)
 	if err != nil {
 		return err
 	}
@@ -354,16 +379,46 @@ func (am *Alertmanager) AppURL() string {
 
 // buildReceiverIntegrations builds a list of integration notifiers off of a receiver config.
 func (am *Alertmanager) buildReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
+	// Test notifications build receivers without a name, one integration at a time. These integrations are pooled so
+	// that testing the same integration repeatedly does not build it from scratch every time. Integrations of the
+	// configuration are never pooled, as they keep track of their notification attempts.
+	if receiver.Name != "" {
+		integrations, err := am.newReceiverIntegrations(receiver, tmpl)
+		if err != nil {
+			return nil, err
+		}
+		return am.integrationStatus.track(receiver, integrations), nil
+	}
+	if len(receiver.Integrations) != 1 {
+		return am.newReceiverIntegrations(receiver, tmpl)
+	}
+	key, err := integrationPoolKey(context.Background(), receiver.Integrations[0], am.decryptFn)
+	if err != nil {
+		return nil, err
+	}
+	if integration, ok := am.testIntegrations.get(key, tmpl); ok {
+		return []*alertingNotify.Integration{integration}, nil
+	}
+	integrations, err := am.newReceiverIntegrations(receiver, tmpl)
+	if err != nil {
+		return nil, err
+	}
+	if len(integrations) == 1 {
+		am.testIntegrations.put(key, tmpl, integrations[0])
+	}
+	return integrations, nil
+}
+
+func (am *Alertmanager) newReceiverIntegrations(receiver *alertingNotify.APIReceiver, tmpl *alertingTemplates.Template) ([]*alertingNotify.Integration, error) {
 	receiverCfg, err := alertingNotify.BuildReceiverConfiguration(context.Background(), receiver, am.decryptFn)
 	if err != nil {
 		return nil, err
 	}
-	s := &sender{am.NotificationService}
-	img := newImageProvider(am.Store, log.New("ngalert.notifier.image-provider"))
+	s := am.sender
 	integrations, err := alertingNotify.BuildReceiverIntegrations(
 		receiverCfg,
 		tmpl,
-		img,
+		am.imageProvider,
 		LoggerFactory,
 		func(n receivers.Metadata) (receivers.WebhookSender, error) {
 			return s, nil
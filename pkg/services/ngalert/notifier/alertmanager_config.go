@@ -52,7 +52,7 @@ func (moa *MultiOrgAlertmanager) ActivateHistoricalConfiguration(ctx context.Con
 		return fmt.Errorf("failed to get historical alertmanager configuration: %w", err)
 	}
 
-	cfg, err := Load([]byte(config.AlertmanagerConfiguration))
+	cfg, err := loadStored(config.AlertmanagerConfiguration)
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal historical alertmanager configuration: %w", err)
 	}
@@ -105,7 +105,7 @@ func (moa *MultiOrgAlertmanager) GetAppliedAlertmanagerConfigurations(ctx contex
 }
 
 func (moa *MultiOrgAlertmanager) gettableUserConfigFromAMConfigString(ctx context.Context, orgID int64, config string) (definitions.GettableUserConfig, error) {
-	cfg, err := Load([]byte(config))
+	cfg, err := loadStored(config)
 	if err != nil {
 		return definitions.GettableUserConfig{}, fmt.Errorf("failed to unmarshal alertmanager configuration: %w", err)
 	}
@@ -136,6 +136,8 @@ func (moa *MultiOrgAlertmanager) gettableUserConfigFromAMConfigString(ctx contex
 				DisableResolveMessage: pr.DisableResolveMessage,
 				Settings:              pr.Settings,
 				SecureFields:          secureFields,
+				Disabled:              pr.Disabled,
+				Probe:                 pr.Probe,
 			}
 			receivers = append(receivers, &gr)
 		}
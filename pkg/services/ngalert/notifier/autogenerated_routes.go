@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"sort"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// NotificationSettingsStore returns the notification settings of the alert rules of an org.
+type NotificationSettingsStore interface {
+	ListNotificationSettings(ctx context.Context, orgID int64) ([]ngmodels.NotificationSettings, error)
+}
+
+// withAutogeneratedRoutes returns the configuration with a route for every distinct notification settings of the
+// alert rules of the org. The routes are inserted before the routes of the notification policy tree, and match the
+// labels that are added to the alerts of the rules, so that their notifications bypass the tree. They are applied to
+// the Alertmanager only and never saved.
+//
+// Settings that reference a contact point that does not exist are skipped, and mute timings that do not exist are
+// ignored, so that a change of the configuration cannot make it impossible to apply.
+func withAutogeneratedRoutes(cfg *apimodels.PostableUserConfig, settings []ngmodels.NotificationSettings, logger log.Logger) *apimodels.PostableUserConfig {
+	if len(settings) == 0 || cfg.AlertmanagerConfig.Route == nil {
+		return cfg
+	}
+
+	receivers := make(map[string]struct{}, len(cfg.AlertmanagerConfig.Receivers))
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		receivers[r.Name] = struct{}{}
+	}
+	muteTimings := make(map[string]struct{}, len(cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimings[mt.Name] = struct{}{}
+	}
+
+	routes := make(map[string]*apimodels.Route)
+	for _, s := range settings {
+		fingerprint := s.Fingerprint()
+		if _, ok := routes[fingerprint]; ok {
+			continue
+		}
+		if _, ok := receivers[s.Receiver]; !ok {
+			logger.Warn("Skipping notification settings of alert rules with unknown contact point", "receiver", s.Receiver)
+			continue
+		}
+		route := &apimodels.Route{
+			Receiver: s.Receiver,
+			ObjectMatchers: apimodels.ObjectMatchers{
+				newEqualMatcher(ngmodels.AutogeneratedRouteReceiverNameLabel, s.Receiver),
+				newEqualMatcher(ngmodels.AutogeneratedRouteSettingsHashLabel, fingerprint),
+			},
+		}
+		for _, name := range s.MuteTimeIntervals {
+			if _, ok := muteTimings[name]; !ok {
+				logger.Warn("Ignoring unknown mute timing in notification settings of alert rules", "receiver", s.Receiver, "muteTiming", name)
+				continue
+			}
+			route.MuteTimeIntervals = append(route.MuteTimeIntervals, name)
+		}
+		routes[fingerprint] = route
+	}
+	if len(routes) == 0 {
+		return cfg
+	}
+
+	// Routes are ordered by fingerprint, so that the configuration and its hash only change with the settings.
+	fingerprints := make([]string, 0, len(routes))
+	for fingerprint := range routes {
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+	parent := &apimodels.Route{
+		Receiver:       cfg.AlertmanagerConfig.Route.Receiver,
+		ObjectMatchers: apimodels.ObjectMatchers{newEqualMatcher(ngmodels.AutogeneratedRouteLabel, "true")},
+		Routes:         make([]*apimodels.Route, 0, len(routes)),
+	}
+	for _, fingerprint := range fingerprints {
+		parent.Routes = append(parent.Routes, routes[fingerprint])
+	}
+
+	// The root route is copied, so that the configuration of the caller is not changed.
+	result := *cfg
+	root := *cfg.AlertmanagerConfig.Route
+	root.Routes = append([]*apimodels.Route{parent}, root.Routes...)
+	result.AlertmanagerConfig.Route = &root
+	return &result
+}
+
+func newEqualMatcher(name, value string) *labels.Matcher {
+	return &labels.Matcher{Type: labels.MatchEqual, Name: name, Value: value}
+}
@@ -0,0 +1,89 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	ngmodels "github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestWithAutogeneratedRoutes(t *testing.T) {
+	newConfig := func() *apimodels.PostableUserConfig {
+		return &apimodels.PostableUserConfig{
+			AlertmanagerConfig: apimodels.PostableApiAlertingConfig{
+				Config: apimodels.Config{
+					Route: &apimodels.Route{
+						Receiver: "default",
+						Routes:   []*apimodels.Route{{Receiver: "team"}},
+					},
+					MuteTimeIntervals: []config.MuteTimeInterval{{Name: "weekends"}},
+				},
+				Receivers: []*apimodels.PostableApiReceiver{
+					{Receiver: config.Receiver{Name: "default"}},
+					{Receiver: config.Receiver{Name: "team"}},
+				},
+			},
+		}
+	}
+	logger := log.NewNopLogger()
+
+	t.Run("configuration without settings is not changed", func(t *testing.T) {
+		cfg := newConfig()
+		require.Same(t, cfg, withAutogeneratedRoutes(cfg, nil, logger))
+	})
+
+	t.Run("a route is added for every distinct settings", func(t *testing.T) {
+		cfg := newConfig()
+		settings := []ngmodels.NotificationSettings{
+			{Receiver: "team", MuteTimeIntervals: []string{"weekends"}},
+			{Receiver: "team"},
+			{Receiver: "team", MuteTimeIntervals: []string{"weekends"}},
+		}
+
+		result := withAutogeneratedRoutes(cfg, settings, logger)
+
+		require.Len(t, result.AlertmanagerConfig.Route.Routes, 2)
+		parent := result.AlertmanagerConfig.Route.Routes[0]
+		require.Equal(t, "default", parent.Receiver)
+		require.Len(t, parent.ObjectMatchers, 1)
+		require.Equal(t, ngmodels.AutogeneratedRouteLabel, parent.ObjectMatchers[0].Name)
+		require.Len(t, parent.Routes, 2)
+		for _, route := range parent.Routes {
+			require.Equal(t, "team", route.Receiver)
+		}
+		require.Equal(t, "team", result.AlertmanagerConfig.Route.Routes[1].Receiver)
+
+		// The configuration of the caller is not changed.
+		require.Len(t, cfg.AlertmanagerConfig.Route.Routes, 1)
+	})
+
+	t.Run("routes match the labels of the settings", func(t *testing.T) {
+		settings := ngmodels.NotificationSettings{Receiver: "team", MuteTimeIntervals: []string{"weekends"}}
+
+		result := withAutogeneratedRoutes(newConfig(), []ngmodels.NotificationSettings{settings}, logger)
+
+		route := result.AlertmanagerConfig.Route.Routes[0].Routes[0]
+		require.Equal(t, []string{"weekends"}, route.MuteTimeIntervals)
+		for _, m := range route.ObjectMatchers {
+			require.True(t, m.Matches(settings.Labels()[m.Name]))
+		}
+	})
+
+	t.Run("settings with unknown contact points are skipped", func(t *testing.T) {
+		cfg := newConfig()
+		settings := []ngmodels.NotificationSettings{{Receiver: "unknown"}}
+		require.Same(t, cfg, withAutogeneratedRoutes(cfg, settings, logger))
+	})
+
+	t.Run("unknown mute timings are ignored", func(t *testing.T) {
+		settings := []ngmodels.NotificationSettings{{Receiver: "team", MuteTimeIntervals: []string{"unknown"}}}
+
+		result := withAutogeneratedRoutes(newConfig(), settings, logger)
+
+		require.Empty(t, result.AlertmanagerConfig.Route.Routes[0].Routes[0].MuteTimeIntervals)
+	})
+}
@@ -1,6 +1,7 @@
 package notifier
 
 import (
+	"bytes"
 	"encoding/json"
 
 	alertingNotify "github.com/grafana/alerting/notify"
@@ -8,22 +9,41 @@ import (
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 )
 
+// escapedVariableRef starts the escaped references to provisioning variables, such as $${vars.NAME}. They are kept in
+// the stored settings of contact points, so that the settings can be read and saved again unchanged, and are sent as
+// the literal ${vars.NAME}.
+var escapedVariableRef = []byte("$${vars.")
+
 func PostableGrafanaReceiverToGrafanaIntegrationConfig(p *apimodels.PostableGrafanaReceiver) *alertingNotify.GrafanaIntegrationConfig {
 	return &alertingNotify.GrafanaIntegrationConfig{
 		UID:                   p.UID,
 		Name:                  p.Name,
 		Type:                  p.Type,
 		DisableResolveMessage: p.DisableResolveMessage,
-		Settings:              json.RawMessage(p.Settings),
+		Settings:              unescapeVariableRefs(json.RawMessage(p.Settings)),
 		SecureSettings:        p.SecureSettings,
 	}
 }
 
+// unescapeVariableRefs replaces the escaped references to provisioning variables in the settings of a contact point
+// with the literal references. Settings without escaped references are returned as they are.
+func unescapeVariableRefs(settings json.RawMessage) json.RawMessage {
+	if !bytes.Contains(settings, escapedVariableRef) {
+		return settings
+	}
+	return bytes.ReplaceAll(settings, escapedVariableRef, escapedVariableRef[1:])
+}
+
+// PostableApiReceiverToApiReceiver converts a receiver of the configuration to a receiver of the Alertmanager. Disabled
+// integrations are left out, so that they do not send notifications.
 func PostableApiReceiverToApiReceiver(r *apimodels.PostableApiReceiver) *alertingNotify.APIReceiver {
 	integrations := alertingNotify.GrafanaIntegrations{
 		Integrations: make([]*alertingNotify.GrafanaIntegrationConfig, 0, len(r.GrafanaManagedReceivers)),
 	}
 	for _, cfg := range r.GrafanaManagedReceivers {
+		if cfg.Disabled {
+			continue
+		}
 		integrations.Integrations = append(integrations.Integrations, PostableGrafanaReceiverToGrafanaIntegrationConfig(cfg))
 	}
 
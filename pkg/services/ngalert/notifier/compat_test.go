@@ -35,6 +35,18 @@ func TestPostableGrafanaReceiverToGrafanaIntegrationConfig(t *testing.T) {
 	}, *actual)
 }
 
+func TestPostableGrafanaReceiverToGrafanaIntegrationConfigUnescapesVariableRefs(t *testing.T) {
+	r := &apimodels.PostableGrafanaReceiver{
+		Type:     "slack",
+		Settings: apimodels.RawMessage(`{"recipient":"$${vars.PREFIX}-alerts","title":"${ENV}"}`),
+	}
+
+	actual := PostableGrafanaReceiverToGrafanaIntegrationConfig(r)
+
+	require.JSONEq(t, `{"recipient":"${vars.PREFIX}-alerts","title":"${ENV}"}`, string(actual.Settings))
+	require.Equal(t, `{"recipient":"$${vars.PREFIX}-alerts","title":"${ENV}"}`, string(r.Settings))
+}
+
 func TestPostableApiReceiverToApiReceiver(t *testing.T) {
 	t.Run("returns empty when no receivers", func(t *testing.T) {
 		r := &apimodels.PostableApiReceiver{
@@ -82,6 +94,33 @@ func TestPostableApiReceiverToApiReceiver(t *testing.T) {
 		require.Equal(t, *PostableGrafanaReceiverToGrafanaIntegrationConfig(r.GrafanaManagedReceivers[0]), *actual.Integrations[0])
 		require.Equal(t, *PostableGrafanaReceiverToGrafanaIntegrationConfig(r.GrafanaManagedReceivers[1]), *actual.Integrations[1])
 	})
+	t.Run("skips disabled receivers", func(t *testing.T) {
+		r := &apimodels.PostableApiReceiver{
+			Receiver: config.Receiver{
+				Name: "test-receiver",
+			},
+			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
+				GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{
+					{
+						UID:      "test-uid",
+						Name:     "test-name",
+						Type:     "slack",
+						Settings: apimodels.RawMessage(`{ "data" : "test" }`),
+						Disabled: true,
+					},
+					{
+						UID:      "test-uid2",
+						Name:     "test-name2",
+						Type:     "webhook",
+						Settings: apimodels.RawMessage(`{ "data2" : "test2" }`),
+					},
+				},
+			},
+		}
+		actual := PostableApiReceiverToApiReceiver(r)
+		require.Len(t, actual.Integrations, 1)
+		require.Equal(t, "test-uid2", actual.Integrations[0].UID)
+	})
 }
 
 func TestPostableApiAlertingConfigToApiReceivers(t *testing.T) {
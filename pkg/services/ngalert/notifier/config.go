@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	alertingNotify "github.com/grafana/alerting/notify"
 	alertingTemplates "github.com/grafana/alerting/templates"
@@ -87,6 +88,17 @@ func Load(rawConfig []byte) (*api.PostableUserConfig, error) {
 	return cfg, nil
 }
 
+// loadStored parses a configuration read from the store. Unlike Load, it does not keep a generic copy of the
+// configuration until it is forwarded in YAML.
+func loadStored(rawConfig string) (*api.PostableUserConfig, error) {
+	cfg, err := api.DecodePostableUserConfig(strings.NewReader(rawConfig))
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse Alertmanager configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
 // AlertingConfiguration provides configuration for an Alertmanager.
 // It implements the notify.Configuration interface.
 type AlertingConfiguration struct {
@@ -0,0 +1,111 @@
+package notifier
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"sync"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+)
+
+// testIntegrationPoolSize is the maximum number of integrations built for test notifications that are kept per org.
+const testIntegrationPoolSize = 100
+
+// integrationPool keeps the integrations built for test notifications, so that repeated tests of the same
+// integration do not build it from scratch. Integrations are keyed by their configuration, including the decrypted
+// secure settings, and are only reused with the template they were built with. The least recently used integration is
+// evicted once the pool is full.
+type integrationPool struct {
+	size int
+
+	mtx     sync.Mutex
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type pooledIntegration struct {
+	key         string
+	tmpl        *alertingTemplates.Template
+	integration *alertingNotify.Integration
+}
+
+func newIntegrationPool(size int) *integrationPool {
+	return &integrationPool{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element, size),
+	}
+}
+
+// get returns the integration built with the given key and template, if any.
+func (p *integrationPool) get(key string, tmpl *alertingTemplates.Template) (*alertingNotify.Integration, bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	e, ok := p.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := e.Value.(*pooledIntegration)
+	if entry.tmpl != tmpl {
+		// The template was replaced by a configuration reload, the integration can no longer be used.
+		p.order.Remove(e)
+		delete(p.entries, key)
+		return nil, false
+	}
+	p.order.MoveToFront(e)
+	return entry.integration, true
+}
+
+// put adds an integration to the pool, evicting the least recently used one if the pool is full.
+func (p *integrationPool) put(key string, tmpl *alertingTemplates.Template, integration *alertingNotify.Integration) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if e, ok := p.entries[key]; ok {
+		e.Value = &pooledIntegration{key: key, tmpl: tmpl, integration: integration}
+		p.order.MoveToFront(e)
+		return
+	}
+	p.entries[key] = p.order.PushFront(&pooledIntegration{key: key, tmpl: tmpl, integration: integration})
+	for p.order.Len() > p.size {
+		oldest := p.order.Back()
+		p.order.Remove(oldest)
+		delete(p.entries, oldest.Value.(*pooledIntegration).key)
+	}
+}
+
+// integrationPoolKey returns a key that identifies an integration configuration. Secure settings are decrypted
+// because they are encrypted with a different nonce every time they are submitted.
+func integrationPoolKey(ctx context.Context, cfg *alertingNotify.GrafanaIntegrationConfig, decrypt alertingNotify.GetDecryptedValueFn) (string, error) {
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = h.Write([]byte(s))
+		_, _ = h.Write([]byte{0})
+	}
+	write(cfg.UID)
+	write(cfg.Name)
+	write(cfg.Type)
+	write(fmt.Sprintf("%t", cfg.DisableResolveMessage))
+	write(string(cfg.Settings))
+
+	keys := make([]string, 0, len(cfg.SecureSettings))
+	secure := make(map[string][]byte, len(cfg.SecureSettings))
+	for k, v := range cfg.SecureSettings {
+		d, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			return "", fmt.Errorf("failed to decode secure settings key %s: %w", k, err)
+		}
+		secure[k] = d
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		write(k)
+		write(decrypt(ctx, secure, k, ""))
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,98 @@
+package notifier
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIntegrationPool(t *testing.T) {
+	tmpl := &alertingTemplates.Template{}
+
+	t.Run("returns pooled integrations built with the same template", func(t *testing.T) {
+		pool := newIntegrationPool(10)
+		integration := &alertingNotify.Integration{}
+		pool.put("key", tmpl, integration)
+
+		actual, ok := pool.get("key", tmpl)
+
+		require.True(t, ok)
+		require.Same(t, integration, actual)
+	})
+
+	t.Run("drops integrations built with another template", func(t *testing.T) {
+		pool := newIntegrationPool(10)
+		pool.put("key", tmpl, &alertingNotify.Integration{})
+
+		_, ok := pool.get("key", &alertingTemplates.Template{})
+
+		require.False(t, ok)
+		_, ok = pool.get("key", tmpl)
+		require.False(t, ok)
+	})
+
+	t.Run("evicts the least recently used integration", func(t *testing.T) {
+		pool := newIntegrationPool(2)
+		pool.put("a", tmpl, &alertingNotify.Integration{})
+		pool.put("b", tmpl, &alertingNotify.Integration{})
+		_, _ = pool.get("a", tmpl)
+
+		pool.put("c", tmpl, &alertingNotify.Integration{})
+
+		_, ok := pool.get("b", tmpl)
+		require.False(t, ok)
+		_, ok = pool.get("a", tmpl)
+		require.True(t, ok)
+		_, ok = pool.get("c", tmpl)
+		require.True(t, ok)
+	})
+}
+
+func TestIntegrationPoolKey(t *testing.T) {
+	// The fake encryption prefixes the value with a counter, so that the same value is never encrypted the same way twice.
+	n := 0
+	encrypt := func(v string) string {
+		n++
+		return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%s", n, v)))
+	}
+	decrypt := func(_ context.Context, sjd map[string][]byte, key string, fallback string) string {
+		v, ok := sjd[key]
+		if !ok {
+			return fallback
+		}
+		var counter int
+		var value string
+		_, _ = fmt.Sscanf(string(v), "%d:%s", &counter, &value)
+		return value
+	}
+	newConfig := func(secret string) *alertingNotify.GrafanaIntegrationConfig {
+		return &alertingNotify.GrafanaIntegrationConfig{
+			UID:            "uid",
+			Name:           "name",
+			Type:           "slack",
+			Settings:       []byte(`{"recipient":"#channel"}`),
+			SecureSettings: map[string]string{"token": encrypt(secret)},
+		}
+	}
+
+	first, err := integrationPoolKey(context.Background(), newConfig("secret"), decrypt)
+	require.NoError(t, err)
+	second, err := integrationPoolKey(context.Background(), newConfig("secret"), decrypt)
+	require.NoError(t, err)
+	other, err := integrationPoolKey(context.Background(), newConfig("other"), decrypt)
+	require.NoError(t, err)
+
+	require.Equal(t, first, second)
+	require.NotEqual(t, first, other)
+
+	cfg := newConfig("secret")
+	cfg.Settings = []byte(`{"recipient":"#other"}`)
+	changed, err := integrationPoolKey(context.Background(), cfg, decrypt)
+	require.NoError(t, err)
+	require.NotEqual(t, first, changed)
+}
@@ -0,0 +1,141 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// integrationStatusTracker records the notification attempts of the integrations of an Alertmanager by integration
+// UID, so that the status of an integration is kept when the configuration is applied again.
+type integrationStatusTracker struct {
+	mtx      sync.RWMutex
+	statuses map[string]models.IntegrationStatus
+}
+
+func newIntegrationStatusTracker() *integrationStatusTracker {
+	return &integrationStatusTracker{
+		statuses: make(map[string]models.IntegrationStatus),
+	}
+}
+
+func (t *integrationStatusTracker) record(uid string, start time.Time, duration time.Duration, err error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	status := t.statuses[uid]
+	status.LastNotifyAttempt = start
+	status.LastNotifyAttemptDuration = duration
+	if err != nil {
+		status.LastNotifyAttemptError = err.Error()
+		status.ConsecutiveFailures++
+	} else {
+		status.LastNotifyAttemptError = ""
+		status.ConsecutiveFailures = 0
+	}
+	t.statuses[uid] = status
+}
+
+// get returns a copy of the statuses of the integrations that tried to send a notification, by integration UID.
+func (t *integrationStatusTracker) get() map[string]models.IntegrationStatus {
+	t.mtx.RLock()
+	defer t.mtx.RUnlock()
+	result := make(map[string]models.IntegrationStatus, len(t.statuses))
+	for uid, status := range t.statuses {
+		result[uid] = status
+	}
+	return result
+}
+
+// forget drops the statuses of the given integrations.
+func (t *integrationStatusTracker) forget(uids []string) {
+	if len(uids) == 0 {
+		return
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for _, uid := range uids {
+		delete(t.statuses, uid)
+	}
+}
+
+// track wraps the integrations built for a receiver so that their notification attempts are recorded. Integrations
+// are built grouped by type, and the index of an integration is its position among the configurations of its type.
+func (t *integrationStatusTracker) track(receiver *alertingNotify.APIReceiver, integrations []*alertingNotify.Integration) []*alertingNotify.Integration {
+	uids := make(map[string][]string, len(receiver.Integrations))
+	for _, cfg := range receiver.Integrations {
+		uids[cfg.Type] = append(uids[cfg.Type], cfg.UID)
+	}
+	tracked := make([]*alertingNotify.Integration, 0, len(integrations))
+	for _, integration := range integrations {
+		typeUIDs := uids[integration.Name()]
+		if integration.Index() >= len(typeUIDs) {
+			tracked = append(tracked, integration)
+			continue
+		}
+		n := &trackedNotifier{integration: integration, uid: typeUIDs[integration.Index()], tracker: t}
+		tracked = append(tracked, alertingNotify.NewIntegration(n, integration, integration.Name(), integration.Index()))
+	}
+	return tracked
+}
+
+// trackedNotifier records the attempts of an integration to send notifications.
+type trackedNotifier struct {
+	integration *alertingNotify.Integration
+	uid         string
+	tracker     *integrationStatusTracker
+}
+
+func (n *trackedNotifier) Notify(ctx context.Context, alerts ...*types.Alert) (bool, error) {
+	start := time.Now()
+	retry, err := n.integration.Notify(ctx, alerts...)
+	n.tracker.record(n.uid, start, time.Since(start), err)
+	return retry, err
+}
+
+// disabledIntegrations returns the UIDs of the disabled integrations of the configuration.
+func disabledIntegrations(cfg apimodels.PostableApiAlertingConfig) []string {
+	var uids []string
+	for _, receiver := range cfg.Receivers {
+		for _, integration := range receiver.GrafanaManagedReceivers {
+			if integration.Disabled {
+				uids = append(uids, integration.UID)
+			}
+		}
+	}
+	return uids
+}
+
+// GetIntegrationStatus returns the delivery status of the integrations of the organization that tried to send a
+// notification, by integration UID. It returns no statuses if the Alertmanager of the organization is not running.
+func (moa *MultiOrgAlertmanager) GetIntegrationStatus(_ context.Context, orgID int64) (map[string]models.IntegrationStatus, error) {
+	am, err := moa.AlertmanagerFor(orgID)
+	if errors.Is(err, ErrNoAlertmanagerForOrg) || errors.Is(err, ErrAlertmanagerNotReady) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return am.integrationStatus.get(), nil
+}
+
+// GetAllIntegrationStatus returns the delivery status of the integrations of all organizations whose Alertmanager is
+// running, by organization ID and integration UID.
+func (moa *MultiOrgAlertmanager) GetAllIntegrationStatus(_ context.Context) map[int64]map[string]models.IntegrationStatus {
+	moa.alertmanagersMtx.RLock()
+	defer moa.alertmanagersMtx.RUnlock()
+	result := make(map[int64]map[string]models.IntegrationStatus, len(moa.alertmanagers))
+	for orgID, am := range moa.alertmanagers {
+		if !am.Ready() {
+			continue
+		}
+		result[orgID] = am.integrationStatus.get()
+	}
+	return result
+}
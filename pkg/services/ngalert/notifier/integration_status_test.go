@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/alertmanager/types"
+	"github.com/stretchr/testify/require"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+type fakeIntegrationNotifier struct {
+	err error
+}
+
+func (n *fakeIntegrationNotifier) Notify(context.Context, ...*types.Alert) (bool, error) {
+	return false, n.err
+}
+
+func (n *fakeIntegrationNotifier) SendResolved() bool {
+	return true
+}
+
+func TestIntegrationStatusTracker(t *testing.T) {
+	receiver := &alertingNotify.APIReceiver{
+		ConfigReceiver: alertingNotify.ConfigReceiver{Name: "team-a"},
+		GrafanaIntegrations: alertingNotify.GrafanaIntegrations{Integrations: []*alertingNotify.GrafanaIntegrationConfig{
+			{UID: "slack-1", Type: "slack"},
+			{UID: "email-1", Type: "email"},
+			{UID: "slack-2", Type: "slack"},
+		}},
+	}
+	slack := &fakeIntegrationNotifier{}
+	email := &fakeIntegrationNotifier{}
+	failing := &fakeIntegrationNotifier{err: errors.New("channel not found")}
+	tracker := newIntegrationStatusTracker()
+
+	integrations := tracker.track(receiver, []*alertingNotify.Integration{
+		alertingNotify.NewIntegration(email, email, "email", 0),
+		alertingNotify.NewIntegration(slack, slack, "slack", 0),
+		alertingNotify.NewIntegration(failing, failing, "slack", 1),
+	})
+	require.Len(t, integrations, 3)
+	for _, integration := range integrations {
+		_, _ = integration.Notify(context.Background())
+	}
+	_, _ = integrations[2].Notify(context.Background())
+
+	statuses := tracker.get()
+	require.Len(t, statuses, 3)
+	require.Zero(t, statuses["slack-1"].ConsecutiveFailures)
+	require.Empty(t, statuses["slack-1"].LastNotifyAttemptError)
+	require.False(t, statuses["email-1"].LastNotifyAttempt.IsZero())
+	require.Equal(t, 2, statuses["slack-2"].ConsecutiveFailures)
+	require.Equal(t, "channel not found", statuses["slack-2"].LastNotifyAttemptError)
+
+	failing.err = nil
+	_, _ = integrations[2].Notify(context.Background())
+	require.Zero(t, tracker.get()["slack-2"].ConsecutiveFailures)
+}
+
+func TestIntegrationStatusTrackerForget(t *testing.T) {
+	tracker := newIntegrationStatusTracker()
+	tracker.record("slack-1", time.Now(), time.Second, errors.New("channel not found"))
+	tracker.record("email-1", time.Now(), time.Second, nil)
+
+	tracker.forget(disabledIntegrations(apimodels.PostableApiAlertingConfig{
+		Receivers: []*apimodels.PostableApiReceiver{{
+			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
+				GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{
+					{UID: "slack-1", Type: "slack", Disabled: true},
+					{UID: "email-1", Type: "email"},
+				},
+			},
+		}},
+	}))
+
+	statuses := tracker.get()
+	require.Len(t, statuses, 1)
+	require.Contains(t, statuses, "email-1")
+}
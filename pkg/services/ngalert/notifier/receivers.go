@@ -91,6 +91,15 @@ func (am *Alertmanager) TestReceivers(ctx context.Context, c apimodels.TestRecei
 	}, err
 }
 
+// TestReceivers sends test notifications through the receivers with the Alertmanager of the organization.
+func (moa *MultiOrgAlertmanager) TestReceivers(ctx context.Context, orgID int64, c alertingNotify.TestReceiversConfigBodyParams) (*alertingNotify.TestReceiversResult, error) {
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return nil, err
+	}
+	return am.Base.TestReceivers(ctx, c)
+}
+
 func (am *Alertmanager) GetReceivers(_ context.Context) []apimodels.Receiver {
 	apiReceivers := make([]apimodels.Receiver, 0, len(am.Base.GetReceivers()))
 	for _, rcv := range am.Base.GetReceivers() {
@@ -0,0 +1,29 @@
+package notifier
+
+import (
+	"context"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/secretrefs"
+)
+
+// WithExternalSecretRefs wraps a function that decrypts the secure settings of integrations. Secure settings that
+// reference an external secret are stored unencrypted and are resolved when the integration is built, so that the
+// secret itself is never stored in the Alertmanager configuration. Only the references that the resolver allows are
+// resolved. Other secure settings are decrypted by fn.
+func WithExternalSecretRefs(fn alertingNotify.GetDecryptedValueFn, resolver *secretrefs.Resolver, logger log.Logger) alertingNotify.GetDecryptedValueFn {
+	return func(ctx context.Context, sjd map[string][]byte, key, fallback string) string {
+		value, ok := sjd[key]
+		if !ok || !secretrefs.IsExternal(string(value)) {
+			return fn(ctx, sjd, key, fallback)
+		}
+		resolved, err := resolver.Resolve(string(value))
+		if err != nil {
+			logger.Error("Failed to resolve external secret of secure setting", "key", key, "error", err)
+			return fallback
+		}
+		return resolved
+	}
+}
@@ -0,0 +1,32 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log/logtest"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/secretrefs"
+)
+
+func TestWithExternalSecretRefs(t *testing.T) {
+	t.Setenv("GF_TEST_SLACK_TOKEN", "xoxb-token")
+	t.Setenv("GF_OTHER_TOKEN", "other-token")
+	resolver, err := secretrefs.NewResolver([]string{"env:GF_TEST_*"})
+	require.NoError(t, err)
+	decrypt := WithExternalSecretRefs(func(_ context.Context, sjd map[string][]byte, key, fallback string) string {
+		return "decrypted-" + string(sjd[key])
+	}, resolver, &logtest.Fake{})
+
+	sjd := map[string][]byte{
+		"token":   []byte("$__env{GF_TEST_SLACK_TOKEN}"),
+		"url":     []byte("encrypted"),
+		"unknown": []byte("$__env{GF_TEST_UNDEFINED}"),
+		"denied":  []byte("$__env{GF_OTHER_TOKEN}"),
+	}
+	require.Equal(t, "xoxb-token", decrypt(context.Background(), sjd, "token", "fallback"))
+	require.Equal(t, "decrypted-encrypted", decrypt(context.Background(), sjd, "url", "fallback"))
+	require.Equal(t, "fallback", decrypt(context.Background(), sjd, "unknown", "fallback"))
+	require.Equal(t, "fallback", decrypt(context.Background(), sjd, "denied", "fallback"))
+}
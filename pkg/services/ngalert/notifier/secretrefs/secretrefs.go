@@ -0,0 +1,90 @@
+// Package secretrefs resolves the secure settings of contact points that reference a secret kept outside of Grafana.
+// It has no dependencies on the notifier, so that the provisioning services can validate references as well.
+package secretrefs
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// ErrUnresolvable is returned for every reference that cannot be resolved, whether it is not allowed, its provider
+// is not available or the secret is missing or empty. Users who can edit contact points must not learn which files
+// or environment variables exist on the host.
+var ErrUnresolvable = errors.New("external secret cannot be resolved")
+
+// externalRefRegex matches secure settings that reference a secret kept outside of Grafana, such as
+// $__env{SLACK_TOKEN}, $__file{/run/secrets/slack-token} or $__vault{alerting:slack_token}. Kubernetes secrets are
+// referenced through the file or the environment variable they are mounted as.
+var externalRefRegex = regexp.MustCompile(`^\$__(\w+)\{([^}]+)\}$`)
+
+// IsExternal reports whether the value of a secure setting is a reference to an external secret.
+func IsExternal(value string) bool {
+	return externalRefRegex.MatchString(value)
+}
+
+// Resolver resolves the references to external secrets that the administrator of Grafana allowed.
+type Resolver struct {
+	allowed []allowedRef
+}
+
+type allowedRef struct {
+	provider string
+	pattern  string
+}
+
+// NewResolver returns a resolver for the references that match any of the allowed patterns. A pattern is a provider
+// and a name in the syntax of path.Match, separated by a colon, for example vault:alerting/* or env:GF_SLACK_*.
+// Without patterns, no references are resolved.
+func NewResolver(allowed []string) (*Resolver, error) {
+	r := &Resolver{allowed: make([]allowedRef, 0, len(allowed))}
+	for _, a := range allowed {
+		provider, pattern, ok := strings.Cut(a, ":")
+		if !ok || provider == "" || pattern == "" {
+			return nil, fmt.Errorf("invalid secret reference pattern '%s': it must be in the form provider:name", a)
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid secret reference pattern '%s': %w", a, err)
+		}
+		r.allowed = append(r.allowed, allowedRef{provider: provider, pattern: pattern})
+	}
+	return r, nil
+}
+
+// Allowed reports whether ref is a reference to an external secret that matches any of the allowed patterns.
+func (r *Resolver) Allowed(ref string) bool {
+	if r == nil {
+		return false
+	}
+	m := externalRefRegex.FindStringSubmatch(ref)
+	if m == nil {
+		return false
+	}
+	for _, a := range r.allowed {
+		if a.provider != m[1] {
+			continue
+		}
+		if ok, _ := path.Match(a.pattern, m[2]); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve returns the value of the external secret that ref references, using the same expanders as the
+// configuration file of Grafana. It returns ErrUnresolvable if the reference is not allowed, no expander is
+// registered for its provider, or the secret is missing or empty.
+func (r *Resolver) Resolve(ref string) (string, error) {
+	if !r.Allowed(ref) {
+		return "", ErrUnresolvable
+	}
+	resolved, err := setting.ExpandVar(ref)
+	if err != nil || resolved == ref || resolved == "" {
+		return "", ErrUnresolvable
+	}
+	return resolved, nil
+}
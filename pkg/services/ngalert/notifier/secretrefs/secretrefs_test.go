@@ -0,0 +1,88 @@
+package secretrefs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsExternal(t *testing.T) {
+	require.True(t, IsExternal("$__env{SLACK_TOKEN}"))
+	require.True(t, IsExternal("$__file{/run/secrets/slack-token}"))
+	require.True(t, IsExternal("$__vault{alerting:slack_token}"))
+	require.False(t, IsExternal("xoxb-$__env{SLACK_TOKEN}"))
+	require.False(t, IsExternal("${SLACK_TOKEN}"))
+	require.False(t, IsExternal("secret"))
+}
+
+func TestNewResolver(t *testing.T) {
+	_, err := NewResolver([]string{"env:GF_SLACK_*", "file:/run/secrets/*"})
+	require.NoError(t, err)
+
+	for _, pattern := range []string{"GF_SLACK_*", "env:", ":GF_SLACK_*", "env:[GF"} {
+		_, err := NewResolver([]string{pattern})
+		require.Errorf(t, err, "pattern %s", pattern)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("GF_TEST_SLACK_TOKEN", "xoxb-token")
+	t.Setenv("GF_TEST_OTHER_TOKEN", "other-token")
+	dir := t.TempDir()
+	file := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(file, []byte("file-token"), 0600))
+
+	t.Run("references are not resolved by default", func(t *testing.T) {
+		for _, r := range []*Resolver{nil, mustResolver(t)} {
+			_, err := r.Resolve("$__env{GF_TEST_SLACK_TOKEN}")
+			require.ErrorIs(t, err, ErrUnresolvable)
+			_, err = r.Resolve("$__file{" + file + "}")
+			require.ErrorIs(t, err, ErrUnresolvable)
+		}
+	})
+
+	t.Run("allowed references are resolved", func(t *testing.T) {
+		r := mustResolver(t, "env:GF_TEST_SLACK_*", "file:"+dir+"/*")
+
+		resolved, err := r.Resolve("$__env{GF_TEST_SLACK_TOKEN}")
+		require.NoError(t, err)
+		require.Equal(t, "xoxb-token", resolved)
+
+		resolved, err = r.Resolve("$__file{" + file + "}")
+		require.NoError(t, err)
+		require.Equal(t, "file-token", resolved)
+	})
+
+	t.Run("references that are not allowed are rejected", func(t *testing.T) {
+		r := mustResolver(t, "env:GF_TEST_SLACK_*")
+
+		_, err := r.Resolve("$__env{GF_TEST_OTHER_TOKEN}")
+		require.ErrorIs(t, err, ErrUnresolvable)
+		_, err = r.Resolve("$__file{" + file + "}")
+		require.ErrorIs(t, err, ErrUnresolvable)
+		_, err = r.Resolve("$__vault{GF_TEST_SLACK_TOKEN}")
+		require.ErrorIs(t, err, ErrUnresolvable)
+	})
+
+	t.Run("every failure returns the same error", func(t *testing.T) {
+		r := mustResolver(t, "env:*", "file:/this/file/does/not/*", "unknown:*", "vault:*")
+
+		var errs []error
+		for _, ref := range []string{"$__env{GF_TEST_UNDEFINED}", "$__file{/this/file/does/not/exist}", "$__unknown{token}", "$__vault{token}"} {
+			_, err := r.Resolve(ref)
+			errs = append(errs, err)
+		}
+		for _, err := range errs {
+			require.Equal(t, ErrUnresolvable, err)
+		}
+	})
+}
+
+func mustResolver(t *testing.T, allowed ...string) *Resolver {
+	t.Helper()
+	r, err := NewResolver(allowed)
+	require.NoError(t, err)
+	return r
+}
@@ -1,6 +1,8 @@
 package notifier
 
 import (
+	"context"
+
 	alertingNotify "github.com/grafana/alerting/notify"
 )
 
@@ -19,3 +21,30 @@ func (am *Alertmanager) CreateSilence(ps *alertingNotify.PostableSilence) (strin
 func (am *Alertmanager) DeleteSilence(silenceID string) error {
 	return am.Base.DeleteSilence(silenceID)
 }
+
+// GetSilence returns the silence with the given ID from the Alertmanager of the organization.
+func (moa *MultiOrgAlertmanager) GetSilence(_ context.Context, orgID int64, silenceID string) (alertingNotify.GettableSilence, error) {
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return alertingNotify.GettableSilence{}, err
+	}
+	return am.GetSilence(silenceID)
+}
+
+// CreateSilence creates or updates a silence in the Alertmanager of the organization and returns its ID.
+func (moa *MultiOrgAlertmanager) CreateSilence(_ context.Context, orgID int64, ps *alertingNotify.PostableSilence) (string, error) {
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return "", err
+	}
+	return am.CreateSilence(ps)
+}
+
+// DeleteSilence expires the silence with the given ID in the Alertmanager of the organization.
+func (moa *MultiOrgAlertmanager) DeleteSilence(_ context.Context, orgID int64, silenceID string) error {
+	am, err := moa.AlertmanagerFor(orgID)
+	if err != nil {
+		return err
+	}
+	return am.DeleteSilence(silenceID)
+}
@@ -0,0 +1,43 @@
+// Package templatedata provides the sample data that notification templates are tested and previewed with.
+package templatedata
+
+import (
+	alertingModels "github.com/grafana/alerting/models"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	prometheusModel "github.com/prometheus/common/model"
+)
+
+var (
+	DefaultLabels = map[string]string{
+		prometheusModel.AlertNameLabel:  `alert title`,
+		alertingModels.FolderTitleLabel: `folder title`,
+	}
+	DefaultAnnotations = map[string]string{
+		alertingModels.ValuesAnnotation:       `{"B":22,"C":1}`,
+		alertingModels.ValueStringAnnotation:  `[ var='B' labels={__name__=go_threads, instance=host.docker.internal:3000, job=grafana} value=22 ], [ var='C' labels={__name__=go_threads, instance=host.docker.internal:3000, job=grafana} value=1 ]`,
+		alertingModels.OrgIDAnnotation:        `1`,
+		alertingModels.DashboardUIDAnnotation: `dashboard_uid`,
+		alertingModels.PanelIDAnnotation:      `1`,
+	}
+)
+
+// AddDefaultLabelsAndAnnotations is a slimmed down version of state.StateToPostableAlert and state.GetRuleExtraLabels using default values.
+func AddDefaultLabelsAndAnnotations(alert *amv2.PostableAlert) {
+	if alert.Labels == nil {
+		alert.Labels = make(map[string]string)
+	}
+	for k, v := range DefaultLabels {
+		if _, ok := alert.Labels[k]; !ok {
+			alert.Labels[k] = v
+		}
+	}
+
+	if alert.Annotations == nil {
+		alert.Annotations = make(map[string]string)
+	}
+	for k, v := range DefaultAnnotations {
+		if _, ok := alert.Annotations[k]; !ok {
+			alert.Annotations[k] = v
+		}
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/templatedata"
 )
 
 var (
@@ -68,7 +69,7 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 		expected: TestTemplatesResults{
 			Results: []alertingNotify.TestTemplatesResult{{
 				Name: "slack.title",
-				Text: DefaultLabels[prometheusModel.AlertNameLabel],
+				Text: templatedata.DefaultLabels[prometheusModel.AlertNameLabel],
 			}},
 			Errors: nil,
 		},
@@ -82,7 +83,7 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 		expected: TestTemplatesResults{
 			Results: []alertingNotify.TestTemplatesResult{{
 				Name: "slack.title",
-				Text: DefaultLabels[alertingModels.FolderTitleLabel],
+				Text: templatedata.DefaultLabels[alertingModels.FolderTitleLabel],
 			}},
 			Errors: nil,
 		},
@@ -110,7 +111,7 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 		expected: TestTemplatesResults{
 			Results: []alertingNotify.TestTemplatesResult{{
 				Name: "slack.title",
-				Text: DefaultAnnotations[alertingModels.ValueStringAnnotation],
+				Text: templatedata.DefaultAnnotations[alertingModels.ValueStringAnnotation],
 			}},
 			Errors: nil,
 		},
@@ -125,8 +126,8 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 			Results: []alertingNotify.TestTemplatesResult{{
 				Name: "slack.title",
 				Text: fmt.Sprintf("http://localhost:9093/d/%s?orgId=%s",
-					DefaultAnnotations[alertingModels.DashboardUIDAnnotation],
-					DefaultAnnotations[alertingModels.OrgIDAnnotation]),
+					templatedata.DefaultAnnotations[alertingModels.DashboardUIDAnnotation],
+					templatedata.DefaultAnnotations[alertingModels.OrgIDAnnotation]),
 			}},
 			Errors: nil,
 		},
@@ -141,9 +142,9 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 			Results: []alertingNotify.TestTemplatesResult{{
 				Name: "slack.title",
 				Text: fmt.Sprintf("http://localhost:9093/d/%s?orgId=%s&viewPanel=%s",
-					DefaultAnnotations[alertingModels.DashboardUIDAnnotation],
-					DefaultAnnotations[alertingModels.OrgIDAnnotation],
-					DefaultAnnotations[alertingModels.PanelIDAnnotation]),
+					templatedata.DefaultAnnotations[alertingModels.DashboardUIDAnnotation],
+					templatedata.DefaultAnnotations[alertingModels.OrgIDAnnotation],
+					templatedata.DefaultAnnotations[alertingModels.PanelIDAnnotation]),
 			}},
 			Errors: nil,
 		},
@@ -157,7 +158,7 @@ CommonAnnotations: {{ range .CommonAnnotations.SortedPairs }}{{ .Name }}={{ .Val
 		expected: TestTemplatesResults{
 			Results: []alertingNotify.TestTemplatesResult{{
 				Name: "slack.title",
-				Text: fmt.Sprintf("http://localhost:3000?orgId=%s", DefaultAnnotations[alertingModels.OrgIDAnnotation]),
+				Text: fmt.Sprintf("http://localhost:3000?orgId=%s", templatedata.DefaultAnnotations[alertingModels.OrgIDAnnotation]),
 			}},
 			Errors: nil,
 		},
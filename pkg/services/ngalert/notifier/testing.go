@@ -22,6 +22,13 @@ type fakeConfigStore struct {
 
 	// historicConfigs stores configs by orgID.
 	historicConfigs map[int64][]*models.HistoricAlertConfiguration
+
+	// notificationSettings stores the notification settings of alert rules by orgID.
+	notificationSettings map[int64][]models.NotificationSettings
+}
+
+func (f *fakeConfigStore) ListNotificationSettings(_ context.Context, orgID int64) ([]models.NotificationSettings, error) {
+	return f.notificationSettings[orgID], nil
 }
 
 // Saves the image or returns an error.
@@ -0,0 +1,80 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// The provisioning write permission is either granted for the whole organization, or scoped to folders. Grants that
+// are scoped to folders only allow changing the alert rules of the folders, which lets service accounts provision the
+// rules of a team without being able to change the rules of other teams or the notification settings.
+
+// EvalOrgProvisioningWrite returns an evaluator that requires the provisioning write permission to be granted for the
+// whole organization, as opposed to grants that are scoped to folders.
+func EvalOrgProvisioningWrite() accesscontrol.Evaluator {
+	return orgPermissionEvaluator{action: accesscontrol.ActionAlertingProvisioningWrite}
+}
+
+// orgPermissionEvaluator requires a permission that is granted without a scope.
+type orgPermissionEvaluator struct {
+	action string
+}
+
+func (e orgPermissionEvaluator) Evaluate(permissions map[string][]string) bool {
+	scopes, ok := permissions[e.action]
+	if !ok {
+		return false
+	}
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, scope := range scopes {
+		if scope == "" {
+			return true
+		}
+	}
+	return false
+}
+
+func (e orgPermissionEvaluator) MutateScopes(_ context.Context, _ accesscontrol.ScopeAttributeMutator) (accesscontrol.Evaluator, error) {
+	return e, nil
+}
+
+func (e orgPermissionEvaluator) String() string {
+	return e.action
+}
+
+func (e orgPermissionEvaluator) GoString() string {
+	return fmt.Sprintf("action:%s scopes:", e.action)
+}
+
+// authorizeRuleWrite checks that the user of the request is allowed to change the alert rules of all given folders.
+// Changes made without a user, such as those of file provisioning, are not restricted.
+func (service *AlertRuleService) authorizeRuleWrite(ctx context.Context, folderUIDs ...string) error {
+	u, err := appcontext.User(ctx)
+	if err != nil || service.ac == nil {
+		return nil
+	}
+	for _, folderUID := range folderUIDs {
+		permitted, err := service.ac.Evaluate(ctx, u, accesscontrol.EvalAny(
+			EvalOrgProvisioningWrite(),
+			accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningWrite, dashboards.ScopeFoldersProvider.GetResourceScopeUID(folderUID)),
+		))
+		if err != nil {
+			return fmt.Errorf("failed to evaluate user permissions: %w", err)
+		}
+		if !permitted {
+			return &Error{
+				Code:         ErrCodePermissionDenied,
+				Reason:       fmt.Sprintf("user is not allowed to provision the alert rules of folder '%s'", folderUID),
+				ResourceType: (&models.AlertRule{}).ResourceType(),
+			}
+		}
+	}
+	return nil
+}
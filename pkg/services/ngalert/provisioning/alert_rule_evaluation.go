@@ -0,0 +1,128 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/benbjohnson/clock"
+	alertingModels "github.com/grafana/alerting/models"
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/backtesting"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/schedule"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// AlertRuleTestInstance is an alert instance that results from a test evaluation of an alert rule.
+type AlertRuleTestInstance struct {
+	Labels      map[string]string
+	Annotations map[string]string
+	// State is the state of the instance after the evaluation, such as Alerting or Pending.
+	State       string
+	StateReason string
+	// Values are the values of the reduce and math expressions and classic conditions of the rule by ref ID.
+	Values map[string]float64
+	// Error is the error of the evaluation, if the instance is in state Error.
+	Error string
+}
+
+// AlertRuleTestService evaluates alert rules once, without storing the rules or the state of their alerts.
+type AlertRuleTestService struct {
+	evaluator        eval.EvaluatorFactory
+	dashboardService dashboards.DashboardService
+	cfg              setting.UnifiedAlertingSettings
+	externalURL      *url.URL
+	log              log.Logger
+	tracer           tracing.Tracer
+	metrics          *metrics.Provisioning
+}
+
+func NewAlertRuleTestService(evaluator eval.EvaluatorFactory, dashboardService dashboards.DashboardService,
+	cfg setting.UnifiedAlertingSettings, externalURL *url.URL, log log.Logger, tracer tracing.Tracer,
+	m *metrics.Provisioning) *AlertRuleTestService {
+	return &AlertRuleTestService{
+		evaluator:        evaluator,
+		dashboardService: dashboardService,
+		cfg:              cfg,
+		externalURL:      externalURL,
+		log:              log,
+		tracer:           tracer,
+		metrics:          m,
+	}
+}
+
+// TestAlertRule executes the queries and expressions of the rule against the data sources once, as the scheduler
+// would at the given time, and returns the alert instances that result. As the rule has no previous state, instances
+// of rules with a pending period are Pending instead of Alerting. Nothing is stored and no notifications are sent.
+func (service *AlertRuleTestService) TestAlertRule(ctx context.Context, orgID int64, rule models.AlertRule, now time.Time) (_ []AlertRuleTestInstance, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "TestAlertRule", orgID,
+		attribute.String("rule_uid", rule.UID))
+	defer func() { done(err) }()
+
+	rule.OrgID = orgID
+	// The interval of a rule is the one of its group, rules that are tested on their own get the default one.
+	if rule.IntervalSeconds <= 0 {
+		interval := service.cfg.DefaultRuleEvaluationInterval
+		if interval <= 0 {
+			interval = setting.DefaultRuleEvaluationInterval
+		}
+		rule.IntervalSeconds = int64(interval.Seconds())
+	}
+	if err := rule.SetDashboardAndPanelFromAnnotations(); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	folder, err := service.dashboardService.GetDashboard(ctx, &dashboards.GetDashboardQuery{OrgID: orgID, UID: rule.NamespaceUID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the folder of the rule: %w", err)
+	}
+
+	// The rule is evaluated with the permissions it would have once stored, which are those of the scheduler.
+	evaluator, err := service.evaluator.Create(eval.NewContext(ctx, schedule.SchedulerUserFor(orgID)), rule.GetEvalCondition())
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to build the queries and expressions of the rule: %s", ErrValidation, err.Error())
+	}
+	results, err := evaluator.Evaluate(ctx, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate the rule: %w", err)
+	}
+
+	manager := state.NewManager(state.ManagerCfg{
+		ExternalURL:             service.externalURL,
+		Images:                  &backtesting.NoopImageService{},
+		Clock:                   clock.New(),
+		MaxStateSaveConcurrency: 1,
+		Tracer:                  service.tracer,
+	})
+	includeFolder := !service.cfg.ReservedLabels.IsReservedLabelDisabled(alertingModels.FolderTitleLabel)
+	transitions := manager.ProcessEvalResults(ctx, now, &rule, results, state.GetRuleExtraLabels(&rule, folder.Title, includeFolder))
+
+	instances := make([]AlertRuleTestInstance, 0, len(transitions))
+	for _, t := range transitions {
+		instance := AlertRuleTestInstance{
+			Labels:      t.Labels,
+			Annotations: t.Annotations,
+			State:       t.State.State.String(),
+			StateReason: t.StateReason,
+			Values:      t.Values,
+		}
+		if t.Error != nil {
+			instance.Error = t.Error.Error()
+		}
+		instances = append(instances, instance)
+	}
+	sort.Slice(instances, func(i, j int) bool {
+		return data.Labels(instances[i].Labels).String() < data.Labels(instances[j].Labels).String()
+	})
+	return instances, nil
+}
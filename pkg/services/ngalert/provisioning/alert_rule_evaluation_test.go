@@ -0,0 +1,73 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/grafana-plugin-sdk-go/data"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval"
+	"github.com/grafana/grafana/pkg/services/ngalert/eval/eval_mocks"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestTestAlertRule(t *testing.T) {
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboard", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardQuery")).Return(&dashboards.Dashboard{
+		UID:   "my-namespace",
+		Title: "My Folder",
+	}, nil).Maybe()
+	now := time.Now()
+	createService := func(evaluator eval.EvaluatorFactory) *AlertRuleTestService {
+		return NewAlertRuleTestService(evaluator, dashboardService, setting.UnifiedAlertingSettings{}, nil, log.NewNopLogger(), tracing.InitializeTracerForTest(), nil)
+	}
+
+	t.Run("returns the instances with their states", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().Evaluate(mock.Anything, now).Return(eval.Results{
+			{Instance: data.Labels{"instance": "b"}, State: eval.Normal, EvaluatedAt: now},
+			{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: now, Values: map[string]eval.NumberValueCapture{
+				"B": {Var: "B", Value: func() *float64 { v := 3.0; return &v }()},
+			}},
+		}, nil)
+		rule := dummyRule("test", 1)
+		rule.For = 0
+
+		instances, err := createService(eval_mocks.NewEvaluatorFactory(evaluator)).TestAlertRule(context.Background(), 1, rule, now)
+		require.NoError(t, err)
+		require.Len(t, instances, 2)
+		require.Equal(t, "a", instances[0].Labels["instance"])
+		require.Equal(t, "My Folder", instances[0].Labels["grafana_folder"])
+		require.Equal(t, eval.Alerting.String(), instances[0].State)
+		require.Equal(t, map[string]float64{"B": 3}, instances[0].Values)
+		require.Equal(t, eval.Normal.String(), instances[1].State)
+	})
+
+	t.Run("instances of rules with a pending period are pending", func(t *testing.T) {
+		evaluator := &eval_mocks.ConditionEvaluatorMock{}
+		evaluator.EXPECT().Evaluate(mock.Anything, now).Return(eval.Results{
+			{Instance: data.Labels{"instance": "a"}, State: eval.Alerting, EvaluatedAt: now},
+		}, nil)
+		rule := dummyRule("test", 1)
+		rule.For = time.Minute
+
+		instances, err := createService(eval_mocks.NewEvaluatorFactory(evaluator)).TestAlertRule(context.Background(), 1, rule, now)
+		require.NoError(t, err)
+		require.Len(t, instances, 1)
+		require.Equal(t, eval.Pending.String(), instances[0].State)
+	})
+
+	t.Run("rules whose queries cannot be built are invalid", func(t *testing.T) {
+		service := createService(eval_mocks.NewFailingEvaluatorFactory(errors.New("unknown data source")))
+
+		_, err := service.TestAlertRule(context.Background(), 1, dummyRule("test", 1), now)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
@@ -0,0 +1,190 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/otel/attribute"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// prometheusRuleQueryRange is the relative time range of the query of imported rules. The query is instant, so the
+// range only has to cover the staleness period of Prometheus.
+const prometheusRuleQueryRange = 10 * time.Minute
+
+// prometheusRuleGroups is the content of a Prometheus or Loki rule file. The expressions are not parsed, as they are
+// evaluated by the data source the rules are imported for.
+type prometheusRuleGroups struct {
+	Groups []prometheusRuleGroup `yaml:"groups"`
+}
+
+type prometheusRuleGroup struct {
+	Name     string           `yaml:"name"`
+	Interval model.Duration   `yaml:"interval,omitempty"`
+	Limit    int              `yaml:"limit,omitempty"`
+	Rules    []prometheusRule `yaml:"rules"`
+}
+
+type prometheusRule struct {
+	Record        string            `yaml:"record,omitempty"`
+	Alert         string            `yaml:"alert,omitempty"`
+	Expr          string            `yaml:"expr"`
+	For           model.Duration    `yaml:"for,omitempty"`
+	KeepFiringFor model.Duration    `yaml:"keep_firing_for,omitempty"`
+	Labels        map[string]string `yaml:"labels,omitempty"`
+	Annotations   map[string]string `yaml:"annotations,omitempty"`
+}
+
+// ImportPrometheusRules converts the alerting rules of a Prometheus or Loki rule file to Grafana-managed rules that
+// query the given data source, and creates their groups in the folder. Every rule has a single query, which is its
+// condition. The groups must not exist in the folder yet. Everything is imported in one transaction. Recording rules
+// and settings without an equivalent in Grafana are left out and reported as warnings.
+func (service *AlertRuleService) ImportPrometheusRules(ctx context.Context, orgID int64, userID int64, imp definitions.AlertRuleImport, provenance models.Provenance) (_ definitions.AlertRuleImportResult, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "ImportPrometheusRules", orgID,
+		attribute.String("namespace_uid", imp.FolderUID), attribute.String("datasource_uid", imp.DatasourceUID))
+	defer func() { done(err) }()
+
+	if imp.FolderUID == "" {
+		return definitions.AlertRuleImportResult{}, fmt.Errorf("%w: folder UID is required", ErrValidation)
+	}
+	if imp.DatasourceUID == "" {
+		return definitions.AlertRuleImportResult{}, fmt.Errorf("%w: data source UID is required", ErrValidation)
+	}
+	if err := service.authorizeRuleWrite(ctx, imp.FolderUID); err != nil {
+		return definitions.AlertRuleImportResult{}, err
+	}
+	var file prometheusRuleGroups
+	decoder := yaml.NewDecoder(strings.NewReader(imp.Rules))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&file); err != nil {
+		return definitions.AlertRuleImportResult{}, fmt.Errorf("%w: invalid rule file: %s", ErrValidation, err.Error())
+	}
+
+	existing, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{imp.FolderUID},
+	})
+	if err != nil {
+		return definitions.AlertRuleImportResult{}, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	groups := map[string]struct{}{}
+	titles := map[string]struct{}{}
+	for _, r := range existing {
+		groups[r.RuleGroup] = struct{}{}
+		titles[r.Title] = struct{}{}
+	}
+
+	result := definitions.AlertRuleImportResult{
+		Groups:   []string{},
+		Warnings: []string{},
+	}
+	var converted []models.AlertRuleGroup
+	for _, g := range file.Groups {
+		if g.Name == "" {
+			return definitions.AlertRuleImportResult{}, fmt.Errorf("%w: rule groups must have a name", ErrValidation)
+		}
+		if _, ok := groups[g.Name]; ok {
+			return definitions.AlertRuleImportResult{}, fmt.Errorf("%w: a rule group with the name '%s' already exists in the folder", ErrValidation, g.Name)
+		}
+		groups[g.Name] = struct{}{}
+		group, err := service.convertPrometheusRuleGroup(g, imp.DatasourceUID, titles, &result)
+		if err != nil {
+			return definitions.AlertRuleImportResult{}, fmt.Errorf("%w: rule group '%s': %s", ErrValidation, g.Name, err.Error())
+		}
+		if len(group.Rules) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("rule group '%s' has no alerting rules and is not imported", g.Name))
+			continue
+		}
+		group.FolderUID = imp.FolderUID
+		converted = append(converted, group)
+	}
+
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, group := range converted {
+			if err := service.ReplaceRuleGroup(ctx, orgID, group, userID, provenance); err != nil {
+				return fmt.Errorf("rule group '%s': %w", group.Title, err)
+			}
+			result.Groups = append(result.Groups, group.Title)
+		}
+		return nil
+	})
+	if err != nil {
+		return definitions.AlertRuleImportResult{}, err
+	}
+	return result, nil
+}
+
+// convertPrometheusRuleGroup converts the alerting rules of the group. Rule titles have to be unique in a folder,
+// while alerts of different rules often have the same name, so titles that are taken get a number appended.
+func (service *AlertRuleService) convertPrometheusRuleGroup(g prometheusRuleGroup, datasourceUID string, titles map[string]struct{}, result *definitions.AlertRuleImportResult) (models.AlertRuleGroup, error) {
+	interval := service.defaultIntervalSeconds
+	if g.Interval != 0 {
+		interval = int64(time.Duration(g.Interval).Seconds())
+	}
+	if g.Limit > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("rule group '%s': the limit of alerts is not supported", g.Name))
+	}
+	group := models.AlertRuleGroup{Title: g.Name, Interval: interval, Rules: []models.AlertRule{}}
+	for _, r := range g.Rules {
+		if r.Record != "" {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("rule group '%s': recording rule '%s' is not supported", g.Name, r.Record))
+			continue
+		}
+		if r.Alert == "" {
+			return models.AlertRuleGroup{}, fmt.Errorf("rules must either record or alert")
+		}
+		if r.Expr == "" {
+			return models.AlertRuleGroup{}, fmt.Errorf("alerting rule '%s' has no expression", r.Alert)
+		}
+		if r.KeepFiringFor != 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("rule group '%s': keep_firing_for of alerting rule '%s' is not supported", g.Name, r.Alert))
+		}
+		query, err := json.Marshal(map[string]any{
+			"refId":     "A",
+			"expr":      r.Expr,
+			"instant":   true,
+			"range":     false,
+			"queryType": "instant",
+		})
+		if err != nil {
+			return models.AlertRuleGroup{}, err
+		}
+		title := r.Alert
+		for i := 2; ; i++ {
+			if _, ok := titles[title]; !ok {
+				break
+			}
+			title = fmt.Sprintf("%s %d", r.Alert, i)
+		}
+		if title != r.Alert {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("rule group '%s': alerting rule '%s' is imported as '%s' as its name is taken", g.Name, r.Alert, title))
+		}
+		titles[title] = struct{}{}
+		group.Rules = append(group.Rules, models.AlertRule{
+			Title:     title,
+			Condition: "A",
+			Data: []models.AlertQuery{{
+				RefID:             "A",
+				RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(prometheusRuleQueryRange)},
+				DatasourceUID:     datasourceUID,
+				Model:             query,
+			}},
+			For:         time.Duration(r.For),
+			Labels:      r.Labels,
+			Annotations: r.Annotations,
+			// Prometheus does not fire alerts if the query has no result or fails.
+			NoDataState:     models.OK,
+			ExecErrState:    models.OkErrState,
+			RuleGroupIndex:  len(group.Rules) + 1,
+			IntervalSeconds: interval,
+		})
+	}
+	return group, nil
+}
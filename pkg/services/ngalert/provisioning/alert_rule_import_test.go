@@ -0,0 +1,101 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const testPrometheusRuleFile = `
+groups:
+  - name: api
+    interval: 30s
+    rules:
+      - record: job:http_requests:rate5m
+        expr: sum by (job) (rate(http_requests_total[5m]))
+      - alert: HighErrorRate
+        expr: job:http_errors:rate5m > 0.1
+        for: 5m
+        labels:
+          severity: warning
+        annotations:
+          summary: '{{ $labels.job }} has an error rate of {{ $value }}'
+      - alert: HighErrorRate
+        expr: job:http_errors:rate5m > 0.5
+        labels:
+          severity: critical
+  - name: recordings
+    rules:
+      - record: job:http_errors:rate5m
+        expr: sum by (job) (rate(http_errors_total[5m]))
+`
+
+func TestImportPrometheusRules(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	ctx := context.Background()
+	var orgID int64 = 1
+
+	t.Run("alerting rules are imported with a single query of the data source", func(t *testing.T) {
+		result, err := ruleService.ImportPrometheusRules(ctx, orgID, 0, definitions.AlertRuleImport{
+			FolderUID:     "import-folder",
+			DatasourceUID: "prometheus",
+			Rules:         testPrometheusRuleFile,
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, []string{"api"}, result.Groups)
+		require.Len(t, result.Warnings, 4)
+
+		group, err := ruleService.GetRuleGroup(ctx, orgID, "import-folder", "api")
+		require.NoError(t, err)
+		require.Equal(t, int64(30), group.Interval)
+		require.Len(t, group.Rules, 2)
+		rule := group.Rules[0]
+		require.Equal(t, "HighErrorRate", rule.Title)
+		require.Equal(t, "HighErrorRate 2", group.Rules[1].Title)
+		require.Equal(t, 5*time.Minute, rule.For)
+		require.Equal(t, map[string]string{"severity": "warning"}, rule.Labels)
+		require.Equal(t, "{{ $labels.job }} has an error rate of {{ $value }}", rule.Annotations["summary"])
+		require.Equal(t, "A", rule.Condition)
+		require.Len(t, rule.Data, 1)
+		require.Equal(t, "prometheus", rule.Data[0].DatasourceUID)
+		var query map[string]any
+		require.NoError(t, json.Unmarshal(rule.Data[0].Model, &query))
+		require.Equal(t, "job:http_errors:rate5m > 0.1", query["expr"])
+
+		_, provenance, err := ruleService.GetAlertRule(ctx, orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+	})
+
+	t.Run("rule groups that exist in the folder are not imported", func(t *testing.T) {
+		imp := definitions.AlertRuleImport{
+			FolderUID:     "import-folder-existing",
+			DatasourceUID: "prometheus",
+			Rules:         testPrometheusRuleFile,
+		}
+		_, err := ruleService.ImportPrometheusRules(ctx, orgID, 0, imp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = ruleService.ImportPrometheusRules(ctx, orgID, 0, imp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("invalid rule files are not imported", func(t *testing.T) {
+		_, err := ruleService.ImportPrometheusRules(ctx, orgID, 0, definitions.AlertRuleImport{
+			FolderUID:     "import-folder-invalid",
+			DatasourceUID: "prometheus",
+			Rules:         "groups:\n  - name: api\n    rules:\n      - alert: NoExpression\n",
+		}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		rules, err := ruleService.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, NamespaceUIDs: []string{"import-folder-invalid"}})
+		require.NoError(t, err)
+		require.Empty(t, rules)
+	})
+}
@@ -0,0 +1,161 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// OrphanedRuleLink is a link of an alert rule to a dashboard panel that does not exist anymore.
+type OrphanedRuleLink struct {
+	RuleUID      string
+	Title        string
+	FolderUID    string
+	DashboardUID string
+	PanelID      int64
+	// DashboardMissing is set if the dashboard does not exist. Otherwise, only the panel is missing from the dashboard.
+	DashboardMissing bool
+}
+
+// GetOrphanedRuleLinks returns the links of the alert rules of the organization to dashboards or panels that do not
+// exist. Rules keep their links when a dashboard is deleted or a panel is removed from it.
+func (service *AlertRuleService) GetOrphanedRuleLinks(ctx context.Context, orgID int64) (_ []OrphanedRuleLink, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetOrphanedRuleLinks", orgID)
+	defer func() { done(err) }()
+	links, _, err := service.findOrphanedRuleLinks(ctx, orgID)
+	return links, err
+}
+
+// ClearOrphanedRuleLinks removes the dashboard and panel annotations from the alert rules of the organization whose
+// links are orphaned, and returns the links that were removed. The rules keep their provenance, as clearing a link
+// that points nowhere does not change who manages the rule.
+func (service *AlertRuleService) ClearOrphanedRuleLinks(ctx context.Context, orgID int64) (_ []OrphanedRuleLink, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "ClearOrphanedRuleLinks", orgID)
+	defer func() { done(err) }()
+	links, rules, err := service.findOrphanedRuleLinks(ctx, orgID)
+	if err != nil || len(links) == 0 {
+		return links, err
+	}
+	folderUIDs := make([]string, 0, len(links))
+	for _, link := range links {
+		folderUIDs = append(folderUIDs, rules[link.RuleUID].NamespaceUID)
+	}
+	if err := service.authorizeRuleWrite(ctx, folderUIDs...); err != nil {
+		return nil, err
+	}
+	provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+	err = service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, link := range links {
+			stored := rules[link.RuleUID]
+			rule := *stored
+			rule.Annotations = make(map[string]string, len(stored.Annotations))
+			for key, value := range stored.Annotations {
+				if key != models.DashboardUIDAnnotation && key != models.PanelIDAnnotation {
+					rule.Annotations[key] = value
+				}
+			}
+			rule.DashboardUID = nil
+			rule.PanelID = nil
+			rule.Updated = time.Now()
+			if err := service.ruleStore.UpdateAlertRules(ctx, []models.UpdateRule{{Existing: stored, New: rule}}); err != nil {
+				return fmt.Errorf("failed to clear the links of alert rule '%s': %w", rule.UID, err)
+			}
+			provenance, ok := provenances[rule.UID]
+			if !ok {
+				provenance = models.ProvenanceNone
+			}
+			if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &rule, provenance, *stored, rule); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return links, nil
+}
+
+// findOrphanedRuleLinks returns the orphaned links sorted by rule UID, together with the rules that have them.
+func (service *AlertRuleService) findOrphanedRuleLinks(ctx context.Context, orgID int64) ([]OrphanedRuleLink, map[string]*models.AlertRule, error) {
+	ruleList, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, nil, err
+	}
+	linked := make([]*models.AlertRule, 0)
+	dq := dashboards.GetDashboardsQuery{OrgID: orgID}
+	seen := map[string]struct{}{}
+	for _, r := range ruleList {
+		uid := r.GetDashboardUID()
+		if uid == "" {
+			continue
+		}
+		linked = append(linked, r)
+		if _, ok := seen[uid]; !ok {
+			seen[uid] = struct{}{}
+			dq.DashboardUIDs = append(dq.DashboardUIDs, uid)
+		}
+	}
+	links := make([]OrphanedRuleLink, 0)
+	rules := make(map[string]*models.AlertRule)
+	if len(linked) == 0 {
+		return links, rules, nil
+	}
+
+	dashes, err := service.dashboardService.GetDashboards(ctx, &dq)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get the linked dashboards: %w", err)
+	}
+	panels := make(map[string]map[int64]struct{}, len(dashes))
+	for _, dash := range dashes {
+		ids := map[int64]struct{}{}
+		if dash.Data != nil {
+			collectPanelIDs(dash.Data.Get("panels").MustArray(), ids)
+			for _, row := range dash.Data.Get("rows").MustArray() {
+				collectPanelIDs(simplejson.NewFromAny(row).Get("panels").MustArray(), ids)
+			}
+		}
+		panels[dash.UID] = ids
+	}
+
+	for _, r := range linked {
+		ids, dashboardExists := panels[r.GetDashboardUID()]
+		if dashboardExists {
+			if _, ok := ids[r.GetPanelID()]; ok {
+				continue
+			}
+		}
+		links = append(links, OrphanedRuleLink{
+			RuleUID:          r.UID,
+			Title:            r.Title,
+			FolderUID:        r.NamespaceUID,
+			DashboardUID:     r.GetDashboardUID(),
+			PanelID:          r.GetPanelID(),
+			DashboardMissing: !dashboardExists,
+		})
+		rules[r.UID] = r
+	}
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].RuleUID < links[j].RuleUID
+	})
+	return links, rules, nil
+}
+
+// collectPanelIDs adds the IDs of the panels to ids, including the panels of collapsed rows.
+func collectPanelIDs(panels []any, ids map[int64]struct{}) {
+	for _, p := range panels {
+		panel := simplejson.NewFromAny(p)
+		if id, err := panel.Get("id").Int64(); err == nil {
+			ids[id] = struct{}{}
+		}
+		collectPanelIDs(panel.Get("panels").MustArray(), ids)
+	}
+}
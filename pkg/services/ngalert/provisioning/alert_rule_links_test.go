@@ -0,0 +1,89 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestOrphanedRuleLinks(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboards", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardsQuery")).Return([]*dashboards.Dashboard{{
+		UID: "dashboard",
+		Data: simplejson.NewFromAny(map[string]any{
+			"panels": []any{
+				map[string]any{"id": 1},
+				map[string]any{"id": 2, "type": "row", "collapsed": true, "panels": []any{
+					map[string]any{"id": 3},
+				}},
+			},
+		}),
+	}}, nil)
+	ruleService.dashboardService = dashboardService
+	ctx := context.Background()
+	var orgID int64 = 1
+
+	linkedRule := func(title, dashboardUID, panelID string) models.AlertRule {
+		rule := dummyRule(title, orgID)
+		rule.Annotations = map[string]string{
+			models.DashboardUIDAnnotation: dashboardUID,
+			models.PanelIDAnnotation:      panelID,
+			"summary":                     title,
+		}
+		created, err := ruleService.CreateAlertRule(ctx, rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+		return created
+	}
+	linkedRule("panel exists", "dashboard", "1")
+	linkedRule("panel in row exists", "dashboard", "3")
+	missingPanel := linkedRule("panel is missing", "dashboard", "4")
+	missingDashboard := linkedRule("dashboard is missing", "deleted-dashboard", "1")
+	_, err := ruleService.CreateAlertRule(ctx, dummyRule("not linked", orgID), models.ProvenanceAPI, 0)
+	require.NoError(t, err)
+
+	t.Run("rules that link to a missing dashboard or panel are returned", func(t *testing.T) {
+		links, err := ruleService.GetOrphanedRuleLinks(ctx, orgID)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []OrphanedRuleLink{
+			{
+				RuleUID:      missingPanel.UID,
+				Title:        missingPanel.Title,
+				FolderUID:    missingPanel.NamespaceUID,
+				DashboardUID: "dashboard",
+				PanelID:      4,
+			},
+			{
+				RuleUID:          missingDashboard.UID,
+				Title:            missingDashboard.Title,
+				FolderUID:        missingDashboard.NamespaceUID,
+				DashboardUID:     "deleted-dashboard",
+				PanelID:          1,
+				DashboardMissing: true,
+			},
+		}, links)
+	})
+
+	t.Run("clearing removes the links of the orphaned rules only", func(t *testing.T) {
+		cleared, err := ruleService.ClearOrphanedRuleLinks(ctx, orgID)
+		require.NoError(t, err)
+		require.Len(t, cleared, 2)
+
+		rule, provenance, err := ruleService.GetAlertRule(ctx, orgID, missingDashboard.UID)
+		require.NoError(t, err)
+		require.Nil(t, rule.DashboardUID)
+		require.Nil(t, rule.PanelID)
+		require.Equal(t, map[string]string{"summary": "dashboard is missing"}, rule.Annotations)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+
+		links, err := ruleService.GetOrphanedRuleLinks(ctx, orgID)
+		require.NoError(t, err)
+		require.Empty(t, links)
+	})
+}
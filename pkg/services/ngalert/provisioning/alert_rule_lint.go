@@ -0,0 +1,142 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/expr/classic"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// LintSeverity is the severity of a finding of the linter of alert rules.
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// Codes of the findings of the linter of alert rules. They are stable, so that pipelines can ignore specific checks.
+const (
+	LintCodeNoForDuration              = "no-for-duration"
+	LintCodeMissingSummary             = "missing-summary"
+	LintCodeValueInLabels              = "value-in-labels"
+	LintCodeClassicConditionDimensions = "classic-condition-multi-dimensional"
+)
+
+// AlertRuleLintFinding is a problem of an alert rule found by the linter.
+type AlertRuleLintFinding struct {
+	Code     string
+	Severity LintSeverity
+	Message  string
+}
+
+// lintValueTemplate matches templates that refer to the value of the query, which changes with every evaluation.
+var lintValueTemplate = regexp.MustCompile(`\$values?\b|\.Values?\b`)
+
+// lintSingleSeriesAggregation matches PromQL expressions that aggregate all series into one.
+var lintSingleSeriesAggregation = regexp.MustCompile(`^(sum|avg|min|max|count|stddev|stdvar|group)\s*\(`)
+
+// LintAlertRule checks an alert rule for common problems that do not make it invalid. The findings are sorted by
+// severity and code.
+func LintAlertRule(rule models.AlertRule) []AlertRuleLintFinding {
+	findings := make([]AlertRuleLintFinding, 0)
+	if rule.For == 0 {
+		findings = append(findings, AlertRuleLintFinding{
+			Code:     LintCodeNoForDuration,
+			Severity: LintSeverityWarning,
+			Message:  "the rule has no pending period, so it fires on the first evaluation that meets the condition",
+		})
+	}
+	if strings.TrimSpace(rule.Annotations["summary"]) == "" {
+		findings = append(findings, AlertRuleLintFinding{
+			Code:     LintCodeMissingSummary,
+			Severity: LintSeverityInfo,
+			Message:  "the rule has no summary annotation to describe its alerts in notifications",
+		})
+	}
+	keys := make([]string, 0, len(rule.Labels))
+	for key := range rule.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if lintValueTemplate.MatchString(rule.Labels[key]) {
+			findings = append(findings, AlertRuleLintFinding{
+				Code:     LintCodeValueInLabels,
+				Severity: LintSeverityError,
+				Message: fmt.Sprintf("label '%s' refers to the query value, so every evaluation creates a new alert "+
+					"instead of updating the existing one, and notifications are not deduplicated", key),
+			})
+		}
+	}
+	for _, refID := range lintClassicConditionMultiDimensionalQueries(rule) {
+		findings = append(findings, AlertRuleLintFinding{
+			Code:     LintCodeClassicConditionDimensions,
+			Severity: LintSeverityWarning,
+			Message: fmt.Sprintf("query %s can return multiple series, but the classic condition reduces them to a single "+
+				"alert without labels of the series; use reduce and threshold expressions to alert per series", refID),
+		})
+	}
+
+	order := map[LintSeverity]int{LintSeverityError: 0, LintSeverityWarning: 1, LintSeverityInfo: 2}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Severity != findings[j].Severity {
+			return order[findings[i].Severity] < order[findings[j].Severity]
+		}
+		return findings[i].Code < findings[j].Code
+	})
+	return findings
+}
+
+// lintClassicConditionMultiDimensionalQueries returns the ref IDs of the queries of classic conditions of the rule
+// that are not aggregated into a single series. Only queries with a PromQL or LogQL expression are checked, as the
+// number of series of other queries cannot be told from their model.
+func lintClassicConditionMultiDimensionalQueries(rule models.AlertRule) []string {
+	queries := make(map[string]models.AlertQuery, len(rule.Data))
+	for _, q := range rule.Data {
+		queries[q.RefID] = q
+	}
+	var result []string
+	seen := map[string]struct{}{}
+	for _, q := range rule.Data {
+		if !expr.IsDataSource(q.DatasourceUID) {
+			continue
+		}
+		var m struct {
+			Type       string                  `json:"type"`
+			Conditions []classic.ConditionJSON `json:"conditions"`
+		}
+		if err := json.Unmarshal(q.Model, &m); err != nil || m.Type != expr.TypeClassicConditions.String() {
+			continue
+		}
+		for _, c := range m.Conditions {
+			if len(c.Query.Params) == 0 {
+				continue
+			}
+			refID := c.Query.Params[0]
+			input, ok := queries[refID]
+			if _, done := seen[refID]; !ok || done || expr.IsDataSource(input.DatasourceUID) {
+				continue
+			}
+			var im struct {
+				Expr string `json:"expr"`
+			}
+			if err := json.Unmarshal(input.Model, &im); err != nil || strings.TrimSpace(im.Expr) == "" {
+				continue
+			}
+			seen[refID] = struct{}{}
+			promQL := strings.TrimSpace(im.Expr)
+			if !lintSingleSeriesAggregation.MatchString(promQL) || strings.Contains(promQL, " by ") ||
+				strings.Contains(promQL, " by(") || strings.Contains(promQL, "without") {
+				result = append(result, refID)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,68 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestLintAlertRule(t *testing.T) {
+	codes := func(findings []AlertRuleLintFinding) []string {
+		result := make([]string, 0, len(findings))
+		for _, f := range findings {
+			result = append(result, f.Code)
+		}
+		return result
+	}
+	classicRule := func(promQL string) models.AlertRule {
+		query, err := json.Marshal(map[string]any{"refId": "A", "expr": promQL})
+		require.NoError(t, err)
+		rule := dummyRule("classic", 1)
+		rule.For = time.Minute
+		rule.Annotations = map[string]string{"summary": "Disk is full"}
+		rule.Condition = "B"
+		rule.Data = []models.AlertQuery{
+			{
+				RefID:         "A",
+				DatasourceUID: "prometheus",
+				Model:         query,
+			},
+			{
+				RefID:         "B",
+				DatasourceUID: expr.DatasourceUID,
+				Model: json.RawMessage(`{"refId":"B","type":"classic_conditions","conditions":[{"evaluator":{"params":[3],"type":"gt"},` +
+					`"operator":{"type":"and"},"query":{"params":["A"]},"reducer":{"type":"last"}}]}`),
+			},
+		}
+		return rule
+	}
+
+	t.Run("a rule without problems has no findings", func(t *testing.T) {
+		require.Empty(t, LintAlertRule(classicRule("sum(rate(http_requests_total[5m]))")))
+	})
+
+	t.Run("findings are sorted by severity", func(t *testing.T) {
+		rule := dummyRule("rule", 1)
+		rule.For = 0
+		rule.Labels = map[string]string{"value": "{{ $value }}"}
+
+		findings := LintAlertRule(rule)
+		require.Equal(t, []string{LintCodeValueInLabels, LintCodeNoForDuration, LintCodeMissingSummary}, codes(findings))
+		require.Equal(t, LintSeverityError, findings[0].Severity)
+	})
+
+	t.Run("classic conditions on queries with multiple series are reported", func(t *testing.T) {
+		for _, promQL := range []string{
+			"rate(http_requests_total[5m])",
+			"sum by (job) (rate(http_requests_total[5m]))",
+			"sum(rate(http_requests_total[5m])) by (job)",
+		} {
+			require.Equal(t, []string{LintCodeClassicConditionDimensions}, codes(LintAlertRule(classicRule(promQL))), promQL)
+		}
+	})
+}
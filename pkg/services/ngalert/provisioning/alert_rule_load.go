@@ -0,0 +1,131 @@
+package provisioning
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/expr"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RuleGroupLoad is an estimate of the load that evaluating the rules of a group puts on the scheduler and on the data
+// sources.
+type RuleGroupLoad struct {
+	// Rules is the number of rules of the group, and PausedRules how many of them are not evaluated.
+	Rules       int
+	PausedRules int
+	// Queries is the number of data source queries of the rules, without expressions.
+	Queries int
+	// DatasourceUIDs are the data sources that the rules query, sorted.
+	DatasourceUIDs []string
+	// EvaluationsPerHour and QueriesPerHour are how often the rules that are not paused are evaluated, and how many
+	// data source queries they run, per hour.
+	EvaluationsPerHour float64
+	QueriesPerHour     float64
+	// EvaluationDuration are the percentiles of how long the last evaluation of each rule took. It is nil if none of
+	// the rules was evaluated since Grafana started.
+	EvaluationDuration *DurationPercentiles
+}
+
+// DurationPercentiles summarizes a set of durations.
+type DurationPercentiles struct {
+	// Samples is the number of durations.
+	Samples int
+	P50     time.Duration
+	P90     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+// EstimateRuleGroupLoad reports how many queries the rules of the group run against which data sources, how often
+// they are evaluated, and how long their evaluation took, so that expensive groups can be found before they delay the
+// evaluation of other rules.
+func (service *AlertRuleService) EstimateRuleGroupLoad(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (_ RuleGroupLoad, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "EstimateRuleGroupLoad", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", ruleGroup))
+	defer func() { done(err) }()
+	ruleList, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespaceUID},
+		RuleGroup:     ruleGroup,
+	})
+	if err != nil {
+		return RuleGroupLoad{}, err
+	}
+	if len(ruleList) == 0 {
+		return RuleGroupLoad{}, store.ErrAlertRuleGroupNotFound
+	}
+
+	load := RuleGroupLoad{Rules: len(ruleList), DatasourceUIDs: []string{}}
+	datasources := map[string]struct{}{}
+	var durations []time.Duration
+	for _, rule := range ruleList {
+		queries := 0
+		for _, q := range rule.Data {
+			if expr.IsDataSource(q.DatasourceUID) {
+				continue
+			}
+			queries++
+			datasources[q.DatasourceUID] = struct{}{}
+		}
+		load.Queries += queries
+		if rule.IsPaused {
+			load.PausedRules++
+		} else if rule.IntervalSeconds > 0 {
+			perHour := float64(time.Hour/time.Second) / float64(rule.IntervalSeconds)
+			load.EvaluationsPerHour += perHour
+			load.QueriesPerHour += perHour * float64(queries)
+		}
+		if duration, ok := service.lastEvaluationDuration(orgID, rule.UID); ok {
+			durations = append(durations, duration)
+		}
+	}
+	for uid := range datasources {
+		load.DatasourceUIDs = append(load.DatasourceUIDs, uid)
+	}
+	sort.Strings(load.DatasourceUIDs)
+	if len(durations) > 0 {
+		load.EvaluationDuration = durationPercentiles(durations)
+	}
+	return load, nil
+}
+
+// lastEvaluationDuration returns how long the last evaluation of the rule took. All alerts of a rule are the result
+// of the same evaluation, the latest of them is used in case some of them are stale.
+func (service *AlertRuleService) lastEvaluationDuration(orgID int64, ruleUID string) (time.Duration, bool) {
+	if service.states == nil {
+		return 0, false
+	}
+	var last time.Time
+	var duration time.Duration
+	for _, s := range service.states.GetStatesForRuleUID(orgID, ruleUID) {
+		if s.LastEvaluationTime.After(last) {
+			last = s.LastEvaluationTime
+			duration = s.EvaluationDuration
+		}
+	}
+	return duration, !last.IsZero()
+}
+
+// durationPercentiles returns the nearest-rank percentiles of the durations.
+func durationPercentiles(durations []time.Duration) *DurationPercentiles {
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	percentile := func(p int) time.Duration {
+		rank := (p*len(durations) + 99) / 100
+		if rank < 1 {
+			rank = 1
+		}
+		return durations[rank-1]
+	}
+	return &DurationPercentiles{
+		Samples: len(durations),
+		P50:     percentile(50),
+		P90:     percentile(90),
+		P99:     percentile(99),
+		Max:     durations[len(durations)-1],
+	}
+}
@@ -0,0 +1,92 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+type fakeRuleStateReader map[string][]*state.State
+
+func (f fakeRuleStateReader) GetStatesForRuleUID(_ int64, alertRuleUID string) []*state.State {
+	return f[alertRuleUID]
+}
+
+func TestEstimateRuleGroupLoad(t *testing.T) {
+	ctx := context.Background()
+	ruleService := createAlertRuleService(t)
+	var orgID int64 = 1
+
+	query := func(refID, datasourceUID string) models.AlertQuery {
+		return models.AlertQuery{
+			RefID:             refID,
+			Model:             json.RawMessage("{}"),
+			DatasourceUID:     datasourceUID,
+			RelativeTimeRange: models.RelativeTimeRange{From: models.Duration(60)},
+		}
+	}
+	group := createDummyGroup("group-test-load", orgID)
+	group.Rules = []models.AlertRule{
+		dummyRule("group-test-load-rule-1", orgID),
+		dummyRule("group-test-load-rule-2", orgID),
+		dummyRule("group-test-load-rule-3", orgID),
+	}
+	group.Rules[0].Data = append(group.Rules[0].Data, query("B", "ds-b"), query("C", "ds-a"))
+	group.Rules[1].Data = append(group.Rules[1].Data, query("B", "ds-a"))
+	group.Rules[2].Data = append(group.Rules[2].Data, query("B", "ds-c"))
+	group.Rules[2].IsPaused = true
+	err := ruleService.ReplaceRuleGroup(ctx, orgID, group, 0, models.ProvenanceAPI)
+	require.NoError(t, err)
+
+	t.Run("counts queries and data sources without expressions", func(t *testing.T) {
+		load, err := ruleService.EstimateRuleGroupLoad(ctx, orgID, "my-namespace", "group-test-load")
+		require.NoError(t, err)
+		require.Equal(t, 3, load.Rules)
+		require.Equal(t, 1, load.PausedRules)
+		require.Equal(t, 4, load.Queries)
+		require.Equal(t, []string{"ds-a", "ds-b", "ds-c"}, load.DatasourceUIDs)
+		// The paused rule is not evaluated, the others are evaluated every minute.
+		require.Equal(t, 120.0, load.EvaluationsPerHour)
+		require.Equal(t, 180.0, load.QueriesPerHour)
+		require.Nil(t, load.EvaluationDuration)
+	})
+
+	t.Run("reports the last evaluation duration of the rules", func(t *testing.T) {
+		stored, err := ruleService.GetRuleGroup(ctx, orgID, "my-namespace", "group-test-load")
+		require.NoError(t, err)
+		now := time.Now()
+		states := fakeRuleStateReader{
+			stored.Rules[0].UID: {
+				{LastEvaluationTime: now.Add(-time.Minute), EvaluationDuration: time.Second},
+				{LastEvaluationTime: now, EvaluationDuration: 3 * time.Second},
+			},
+			stored.Rules[1].UID: {
+				{LastEvaluationTime: now, EvaluationDuration: 2 * time.Second},
+			},
+		}
+		sut := ruleService
+		sut.states = states
+
+		load, err := sut.EstimateRuleGroupLoad(ctx, orgID, "my-namespace", "group-test-load")
+		require.NoError(t, err)
+		require.Equal(t, &DurationPercentiles{
+			Samples: 2,
+			P50:     2 * time.Second,
+			P90:     3 * time.Second,
+			P99:     3 * time.Second,
+			Max:     3 * time.Second,
+		}, load.EvaluationDuration)
+	})
+
+	t.Run("returns not found for groups without rules", func(t *testing.T) {
+		_, err := ruleService.EstimateRuleGroupLoad(ctx, orgID, "my-namespace", "does-not-exist")
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+}
@@ -0,0 +1,142 @@
+package provisioning
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// DanglingNotificationSettingsReference is a contact point or mute timing that the notification settings of an alert
+// rule refer to, but that does not exist in the org.
+type DanglingNotificationSettingsReference struct {
+	RuleUID      string
+	RuleTitle    string
+	NamespaceUID string
+	RuleGroup    string
+	// Field is the field of the notification settings with the reference, notificationSettings.receiver or
+	// notificationSettings.muteTimeIntervals.
+	Field string
+	// Name is the name of the contact point or mute timing that does not exist.
+	Name string
+}
+
+// ValidateRuleNotificationSettings returns all references of the notification settings of the alert rules of the org
+// to contact points and mute timings that do not exist, ordered by rule UID. Rules are only checked when they are
+// saved, so references can dangle after contact points or mute timings were renamed or replaced by an import, in
+// which case the generated routes skip the settings or ignore the mute timings. The settings have no grouping of
+// their own, the generated routes group like the root policy, so there are no group by labels to check.
+func (service *AlertRuleService) ValidateRuleNotificationSettings(ctx context.Context, orgID int64) (_ []DanglingNotificationSettingsReference, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "ValidateRuleNotificationSettings", orgID)
+	defer func() { done(err) }()
+	rules, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	result := []DanglingNotificationSettingsReference{}
+	refs := notificationSettingsReferences{}
+	for _, rule := range rules {
+		if len(rule.NotificationSettings) == 0 {
+			continue
+		}
+		if err := refs.load(ctx, orgID, service.amStore); err != nil {
+			return nil, err
+		}
+		result = append(result, refs.dangling(*rule)...)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].RuleUID < result[j].RuleUID
+	})
+	return result, nil
+}
+
+// validateNotificationSettings checks that the contact points and mute timings that the notification settings of
+// the rules refer to exist in the org. The Alertmanager configuration is only read if any rule has settings.
+func (service *AlertRuleService) validateNotificationSettings(ctx context.Context, orgID int64, rules ...models.AlertRule) error {
+	refs := notificationSettingsReferences{}
+	for _, rule := range rules {
+		if len(rule.NotificationSettings) == 0 {
+			continue
+		}
+		if err := refs.load(ctx, orgID, service.amStore); err != nil {
+			return err
+		}
+		if dangling := refs.dangling(rule); len(dangling) > 0 {
+			ref := dangling[0]
+			if ref.Field == "notificationSettings.receiver" {
+				return notificationSettingsError(rule, ref.Field, "contact point '%s' does not exist", ref.Name)
+			}
+			return notificationSettingsError(rule, ref.Field, "mute timing '%s' does not exist", ref.Name)
+		}
+	}
+	return nil
+}
+
+// notificationSettingsReferences are the contact points and mute timings of the last configuration of an org, which
+// the notification settings of alert rules can refer to.
+type notificationSettingsReferences struct {
+	revision    *cfgRevision
+	muteTimings map[string]struct{}
+}
+
+// load reads the last configuration of the org, unless it was read already.
+func (r *notificationSettingsReferences) load(ctx context.Context, orgID int64, store AMConfigStore) error {
+	if r.revision != nil {
+		return nil
+	}
+	revision, err := getLastConfiguration(ctx, orgID, store)
+	if err != nil {
+		return err
+	}
+	r.revision = revision
+	r.muteTimings = make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		r.muteTimings[mt.Name] = struct{}{}
+	}
+	return nil
+}
+
+// dangling returns the references of the notification settings of the rule that do not exist, the contact point
+// before the mute timings.
+func (r *notificationSettingsReferences) dangling(rule models.AlertRule) []DanglingNotificationSettingsReference {
+	var result []DanglingNotificationSettingsReference
+	add := func(field, name string) {
+		result = append(result, DanglingNotificationSettingsReference{
+			RuleUID:      rule.UID,
+			RuleTitle:    rule.Title,
+			NamespaceUID: rule.NamespaceUID,
+			RuleGroup:    rule.RuleGroup,
+			Field:        field,
+			Name:         name,
+		})
+	}
+	for _, settings := range rule.NotificationSettings {
+		if _, ok := r.revision.receivers().group(settings.Receiver); !ok {
+			add("notificationSettings.receiver", settings.Receiver)
+		}
+		for _, name := range settings.MuteTimeIntervals {
+			if _, ok := r.muteTimings[name]; !ok {
+				add("notificationSettings.muteTimeIntervals", name)
+			}
+		}
+	}
+	return result
+}
+
+// ruleUIDs returns the UIDs of the rules as a comma separated list, for error messages.
+func ruleUIDs(rules models.RulesGroup) string {
+	uids := make([]string, 0, len(rules))
+	for _, rule := range rules {
+		uids = append(uids, rule.UID)
+	}
+	return strings.Join(uids, ", ")
+}
+
+// notificationSettingsError returns a validation error about the notification settings of the rule, which is also
+// an invalid alert rule error.
+func notificationSettingsError(rule models.AlertRule, field string, format string, args ...any) error {
+	err := newValidationError(field, format, args...).withResource(rule.ResourceType(), rule.UID)
+	err.Err = models.ErrAlertRuleFailedValidation
+	return err
+}
@@ -0,0 +1,109 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+)
+
+func TestValidateNotificationSettings(t *testing.T) {
+	service := AlertRuleService{amStore: newFakeAMConfigStore(defaultAlertmanagerConfigJSON)}
+	withSettings := func(settings models.NotificationSettings) models.AlertRule {
+		rule := dummyRule("test", 1)
+		rule.UID = "rule-uid"
+		rule.NotificationSettings = []models.NotificationSettings{settings}
+		return rule
+	}
+
+	t.Run("rules without settings do not read the configuration", func(t *testing.T) {
+		service := AlertRuleService{}
+		require.NoError(t, service.validateNotificationSettings(context.Background(), 1, dummyRule("test", 1)))
+	})
+
+	t.Run("settings with an existing contact point are valid", func(t *testing.T) {
+		rule := withSettings(models.NotificationSettings{Receiver: "a new receiver"})
+		require.NoError(t, service.validateNotificationSettings(context.Background(), 1, rule))
+	})
+
+	t.Run("settings with an unknown contact point are rejected", func(t *testing.T) {
+		rule := withSettings(models.NotificationSettings{Receiver: "unknown"})
+		err := service.validateNotificationSettings(context.Background(), 1, rule)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		var provErr *Error
+		require.ErrorAs(t, err, &provErr)
+		require.Equal(t, "notificationSettings.receiver", provErr.Field)
+		require.Equal(t, "rule-uid", provErr.ResourceID)
+	})
+
+	t.Run("settings with an unknown mute timing are rejected", func(t *testing.T) {
+		rule := withSettings(models.NotificationSettings{Receiver: "grafana-default-email", MuteTimeIntervals: []string{"weekends"}})
+		err := service.validateNotificationSettings(context.Background(), 1, rule)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		var provErr *Error
+		require.ErrorAs(t, err, &provErr)
+		require.Equal(t, "notificationSettings.muteTimeIntervals", provErr.Field)
+	})
+}
+
+func TestValidateRuleNotificationSettings(t *testing.T) {
+	ctx := context.Background()
+	ruleStore := fakes.NewRuleStore(t)
+	service := AlertRuleService{
+		amStore:   newFakeAMConfigStore(defaultAlertmanagerConfigJSON),
+		ruleStore: ruleStore,
+		tracer:    tracing.InitializeTracerForTest(),
+	}
+	put := func(uid string, settings ...models.NotificationSettings) {
+		rule := dummyRule(uid, 1)
+		rule.UID = uid
+		rule.NotificationSettings = settings
+		ruleStore.PutRule(ctx, &rule)
+	}
+
+	t.Run("rules without dangling references are not reported", func(t *testing.T) {
+		put("valid", models.NotificationSettings{Receiver: "a new receiver"})
+		put("without-settings")
+
+		dangling, err := service.ValidateRuleNotificationSettings(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, dangling)
+	})
+
+	t.Run("all dangling references of all rules are reported", func(t *testing.T) {
+		put("renamed-receiver", models.NotificationSettings{Receiver: "renamed", MuteTimeIntervals: []string{"weekends"}})
+		put("missing-mute-timings", models.NotificationSettings{Receiver: "grafana-default-email", MuteTimeIntervals: []string{"nights", "weekends"}})
+
+		dangling, err := service.ValidateRuleNotificationSettings(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []DanglingNotificationSettingsReference{
+			{RuleUID: "missing-mute-timings", Field: "notificationSettings.muteTimeIntervals", Name: "nights"},
+			{RuleUID: "missing-mute-timings", Field: "notificationSettings.muteTimeIntervals", Name: "weekends"},
+			{RuleUID: "renamed-receiver", Field: "notificationSettings.receiver", Name: "renamed"},
+			{RuleUID: "renamed-receiver", Field: "notificationSettings.muteTimeIntervals", Name: "weekends"},
+		}, withoutRuleDetails(dangling))
+		require.Equal(t, "my-cool-group", dangling[0].RuleGroup)
+		require.Equal(t, "my-namespace", dangling[0].NamespaceUID)
+	})
+
+	t.Run("rules of other orgs are not checked", func(t *testing.T) {
+		dangling, err := service.ValidateRuleNotificationSettings(ctx, 2)
+		require.NoError(t, err)
+		require.Empty(t, dangling)
+	})
+}
+
+func withoutRuleDetails(refs []DanglingNotificationSettingsReference) []DanglingNotificationSettingsReference {
+	result := make([]DanglingNotificationSettingsReference, 0, len(refs))
+	for _, ref := range refs {
+		result = append(result, DanglingNotificationSettingsReference{RuleUID: ref.RuleUID, Field: ref.Field, Name: ref.Name})
+	}
+	return result
+}
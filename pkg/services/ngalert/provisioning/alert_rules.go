@@ -5,10 +5,18 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/exp/slices"
+
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	"github.com/grafana/grafana/pkg/services/dashboards"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/quota"
@@ -20,45 +28,141 @@ type AlertRuleService struct {
 	baseIntervalSeconds    int64
 	ruleStore              RuleStore
 	provenanceStore        ProvisioningStore
+	amStore                AMConfigStore
 	dashboardService       dashboards.DashboardService
 	quotas                 QuotaChecker
 	xact                   TransactionManager
+	states                 RuleStateReader
 	log                    log.Logger
+	ac                     accesscontrol.AccessControl
+	tracer                 tracing.Tracer
+	metrics                *metrics.Provisioning
 }
 
 func NewAlertRuleService(ruleStore RuleStore,
 	provenanceStore ProvisioningStore,
+	amStore AMConfigStore,
 	dashboardService dashboards.DashboardService,
 	quotas QuotaChecker,
 	xact TransactionManager,
+	states RuleStateReader,
 	defaultIntervalSeconds int64,
 	baseIntervalSeconds int64,
-	log log.Logger) *AlertRuleService {
+	log log.Logger,
+	ac accesscontrol.AccessControl,
+	tracer tracing.Tracer,
+	m *metrics.Provisioning) *AlertRuleService {
 	return &AlertRuleService{
 		defaultIntervalSeconds: defaultIntervalSeconds,
 		baseIntervalSeconds:    baseIntervalSeconds,
 		ruleStore:              ruleStore,
 		provenanceStore:        provenanceStore,
+		amStore:                amStore,
 		dashboardService:       dashboardService,
 		quotas:                 quotas,
 		xact:                   xact,
+		states:                 states,
 		log:                    log,
+		ac:                     ac,
+		tracer:                 tracer,
+		metrics:                m,
 	}
 }
 
-func (service *AlertRuleService) GetAlertRules(ctx context.Context, orgID int64) ([]*models.AlertRule, error) {
-	q := models.ListAlertRulesQuery{
-		OrgID: orgID,
+// AlertRuleQuery selects alert rules of an organization. Rules are returned if they match all given filters.
+type AlertRuleQuery struct {
+	OrgID int64
+	// Optionally filter by a case-insensitive substring of the title.
+	Title string
+	// Optionally filter by label matchers, all of which must match the labels of the rule.
+	Matchers labels.Matchers
+	// Optionally filter by data source. Rules that query any of the given data sources are returned.
+	DatasourceUIDs []string
+	// Optionally filter by whether the rule is paused.
+	Paused *bool
+	// Optionally filter by folder. Rules in any of the given folders are returned.
+	FolderUIDs []string
+	// Optionally filter by the name of the rule group. Rules in any of the given groups are returned.
+	RuleGroups []string
+}
+
+// GetAlertRules returns the alert rules of the org that match the query together with the provenance of all rules of
+// the org, keyed by rule UID. Provenances are fetched with a single query instead of one per rule.
+func (service *AlertRuleService) GetAlertRules(ctx context.Context, q AlertRuleQuery) (_ []*models.AlertRule, _ map[string]models.Provenance, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetAlertRules", q.OrgID)
+	defer func() { done(err) }()
+	listQuery := models.ListAlertRulesQuery{
+		OrgID: q.OrgID,
 	}
-	rules, err := service.ruleStore.ListAlertRules(ctx, &q)
+	rules, err := service.ruleStore.ListAlertRules(ctx, &listQuery)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	rules = filterAlertRules(rules, q)
+	provenances := make(map[string]models.Provenance)
+	if len(rules) > 0 {
+		resourceType := rules[0].ResourceType()
+		provenances, err = service.provenanceStore.GetProvenances(ctx, q.OrgID, resourceType)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return rules, provenances, nil
+}
+
+// filterAlertRules returns the rules that match the filters of the query.
+func filterAlertRules(rules []*models.AlertRule, q AlertRuleQuery) []*models.AlertRule {
+	title := strings.ToLower(q.Title)
+	result := make([]*models.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		if title != "" && !strings.Contains(strings.ToLower(rule.Title), title) {
+			continue
+		}
+		if q.Paused != nil && rule.IsPaused != *q.Paused {
+			continue
+		}
+		if !matchersMatchLabels(q.Matchers, rule.Labels) {
+			continue
+		}
+		if len(q.DatasourceUIDs) > 0 && !queriesAnyDatasource(rule, q.DatasourceUIDs) {
+			continue
+		}
+		if len(q.FolderUIDs) > 0 && !slices.Contains(q.FolderUIDs, rule.NamespaceUID) {
+			continue
+		}
+		if len(q.RuleGroups) > 0 && !slices.Contains(q.RuleGroups, rule.RuleGroup) {
+			continue
+		}
+		result = append(result, rule)
+	}
+	return result
+}
+
+// matchersMatchLabels returns whether all matchers match the labels. Missing labels match as empty values.
+func matchersMatchLabels(matchers labels.Matchers, lbls map[string]string) bool {
+	for _, m := range matchers {
+		if !m.Matches(lbls[m.Name]) {
+			return false
+		}
+	}
+	return true
+}
+
+func queriesAnyDatasource(rule *models.AlertRule, datasourceUIDs []string) bool {
+	for _, query := range rule.Data {
+		for _, uid := range datasourceUIDs {
+			if query.DatasourceUID == uid {
+				return true
+			}
+		}
 	}
-	// TODO: GET provenance
-	return rules, nil
+	return false
 }
 
-func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (models.AlertRule, models.Provenance, error) {
+func (service *AlertRuleService) GetAlertRule(ctx context.Context, orgID int64, ruleUID string) (_ models.AlertRule, _ models.Provenance, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetAlertRule", orgID,
+		attribute.String("rule_uid", ruleUID))
+	defer func() { done(err) }()
 	query := &models.GetAlertRuleByUIDQuery{
 		OrgID: orgID,
 		UID:   ruleUID,
@@ -80,7 +184,10 @@ type AlertRuleWithFolderTitle struct {
 }
 
 // GetAlertRuleWithFolderTitle returns a single alert rule with its folder title.
-func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context, orgID int64, ruleUID string) (AlertRuleWithFolderTitle, error) {
+func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context, orgID int64, ruleUID string) (_ AlertRuleWithFolderTitle, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetAlertRuleWithFolderTitle", orgID,
+		attribute.String("rule_uid", ruleUID))
+	defer func() { done(err) }()
 	query := &models.GetAlertRuleByUIDQuery{
 		OrgID: orgID,
 		UID:   ruleUID,
@@ -109,18 +216,28 @@ func (service *AlertRuleService) GetAlertRuleWithFolderTitle(ctx context.Context
 // CreateAlertRule creates a new alert rule. This function will ignore any
 // interval that is set in the rule struct and use the already existing group
 // interval or the default one.
-func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, userID int64) (models.AlertRule, error) {
+func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance, userID int64) (_ models.AlertRule, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "CreateAlertRule", rule.OrgID,
+		attribute.String("rule_uid", rule.UID))
+	defer func() { done(err) }()
+	if err := service.authorizeRuleWrite(ctx, rule.NamespaceUID); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := service.validateNotificationSettings(ctx, rule.OrgID, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	if rule.UID == "" {
 		rule.UID = util.GenerateShortUID()
 	}
-	interval, err := service.ruleStore.GetRuleGroupInterval(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
+	evaluation, err := service.getRuleGroupEvaluation(ctx, rule.OrgID, rule.NamespaceUID, rule.RuleGroup)
 	// if the alert group does not exists we just use the default interval
 	if err != nil && errors.Is(err, store.ErrAlertRuleGroupNotFound) {
-		interval = service.defaultIntervalSeconds
+		evaluation = models.RuleGroupEvaluation{IntervalSeconds: service.defaultIntervalSeconds}
 	} else if err != nil {
 		return models.AlertRule{}, err
 	}
-	rule.IntervalSeconds = interval
+	rule.IntervalSeconds = evaluation.IntervalSeconds
+	rule.EvaluationOffsetSeconds = evaluation.EvaluationOffsetSeconds
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -143,7 +260,10 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 			return err
 		}
 
-		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+		if err = service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance); err != nil {
+			return err
+		}
+		return recordAudit(ctx, service.provenanceStore, rule.OrgID, models.ProvisioningAuditActionCreate, &rule, provenance, nil, rule)
 	})
 	if err != nil {
 		return models.AlertRule{}, err
@@ -151,7 +271,10 @@ func (service *AlertRuleService) CreateAlertRule(ctx context.Context, rule model
 	return rule, nil
 }
 
-func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, namespaceUID, group string) (models.AlertRuleGroup, error) {
+func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64, namespaceUID, group string) (_ models.AlertRuleGroup, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetRuleGroup", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", group))
+	defer func() { done(err) }()
 	q := models.ListAlertRulesQuery{
 		OrgID:         orgID,
 		NamespaceUIDs: []string{namespaceUID},
@@ -165,10 +288,11 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 		return models.AlertRuleGroup{}, store.ErrAlertRuleGroupNotFound
 	}
 	res := models.AlertRuleGroup{
-		Title:     ruleList[0].RuleGroup,
-		FolderUID: ruleList[0].NamespaceUID,
-		Interval:  ruleList[0].IntervalSeconds,
-		Rules:     []models.AlertRule{},
+		Title:            ruleList[0].RuleGroup,
+		FolderUID:        ruleList[0].NamespaceUID,
+		Interval:         ruleList[0].IntervalSeconds,
+		EvaluationOffset: ruleList[0].EvaluationOffsetSeconds,
+		Rules:            []models.AlertRule{},
 	}
 	for _, r := range ruleList {
 		if r != nil {
@@ -179,7 +303,13 @@ func (service *AlertRuleService) GetRuleGroup(ctx context.Context, orgID int64,
 }
 
 // UpdateRuleGroup will update the interval for all rules in the group.
-func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) error {
+func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, intervalSeconds int64) (err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "UpdateRuleGroup", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", ruleGroup))
+	defer func() { done(err) }()
+	if err := service.authorizeRuleWrite(ctx, namespaceUID); err != nil {
+		return err
+	}
 	if err := models.ValidateRuleGroupInterval(intervalSeconds, service.baseIntervalSeconds); err != nil {
 		return err
 	}
@@ -205,69 +335,196 @@ func (service *AlertRuleService) UpdateRuleGroup(ctx context.Context, orgID int6
 				New:      newRule,
 			})
 		}
-		return service.ruleStore.UpdateAlertRules(ctx, updateRules)
+		if err := service.ruleStore.UpdateAlertRules(ctx, updateRules); err != nil {
+			return err
+		}
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, update := range updateRules {
+			provenance := provenanceOrNone(provenances, update.New.UID)
+			if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &update.New, provenance, update.Existing, update.New); err != nil {
+				return err
+			}
+		}
+		return nil
 	})
 }
 
-func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, userID int64, provenance models.Provenance) error {
-	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
-		return err
+// GetRuleGroupEvaluation returns the interval and the evaluation offset of the rule group.
+func (service *AlertRuleService) GetRuleGroupEvaluation(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (_ models.RuleGroupEvaluation, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetRuleGroupEvaluation", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", ruleGroup))
+	defer func() { done(err) }()
+	return service.getRuleGroupEvaluation(ctx, orgID, namespaceUID, ruleGroup)
+}
+
+func (service *AlertRuleService) getRuleGroupEvaluation(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (models.RuleGroupEvaluation, error) {
+	ruleList, err := service.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{
+		OrgID:         orgID,
+		NamespaceUIDs: []string{namespaceUID},
+		RuleGroup:     ruleGroup,
+	})
+	if err != nil {
+		return models.RuleGroupEvaluation{}, err
+	}
+	if len(ruleList) == 0 {
+		return models.RuleGroupEvaluation{}, store.ErrAlertRuleGroupNotFound
 	}
+	return models.RuleGroupEvaluation{
+		IntervalSeconds:         ruleList[0].IntervalSeconds,
+		EvaluationOffsetSeconds: ruleList[0].EvaluationOffsetSeconds,
+	}, nil
+}
 
-	// If the provided request did not provide the rules list at all, treat it as though it does not wish to change rules.
-	// This is done for backwards compatibility. Requests which specify only the interval must update only the interval.
-	if group.Rules == nil {
-		listRulesQuery := models.ListAlertRulesQuery{
+// SetRuleGroupEvaluation changes the interval and the evaluation offset of all rules in the group. Groups with the
+// same interval can be given different offsets so that they are not all evaluated at the same time.
+func (service *AlertRuleService) SetRuleGroupEvaluation(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, evaluation models.RuleGroupEvaluation) (err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "SetRuleGroupEvaluation", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", ruleGroup))
+	defer func() { done(err) }()
+	if err := service.authorizeRuleWrite(ctx, namespaceUID); err != nil {
+		return err
+	}
+	if err := models.ValidateRuleGroupInterval(evaluation.IntervalSeconds, service.baseIntervalSeconds); err != nil {
+		return err
+	}
+	if err := models.ValidateRuleGroupEvaluationOffset(evaluation.EvaluationOffsetSeconds, evaluation.IntervalSeconds, service.baseIntervalSeconds); err != nil {
+		return err
+	}
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.ListAlertRulesQuery{
 			OrgID:         orgID,
-			NamespaceUIDs: []string{group.FolderUID},
-			RuleGroup:     group.Title,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     ruleGroup,
 		}
-		ruleList, err := service.ruleStore.ListAlertRules(ctx, &listRulesQuery)
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, query)
 		if err != nil {
 			return fmt.Errorf("failed to list alert rules: %w", err)
 		}
-		group.Rules = make([]models.AlertRule, 0, len(ruleList))
-		for _, r := range ruleList {
-			if r != nil {
-				group.Rules = append(group.Rules, *r)
+		if len(ruleList) == 0 {
+			return store.ErrAlertRuleGroupNotFound
+		}
+		updateRules := make([]models.UpdateRule, 0, len(ruleList))
+		for _, rule := range ruleList {
+			if rule.IntervalSeconds == evaluation.IntervalSeconds && rule.EvaluationOffsetSeconds == evaluation.EvaluationOffsetSeconds {
+				continue
 			}
+			newRule := *rule
+			newRule.IntervalSeconds = evaluation.IntervalSeconds
+			newRule.EvaluationOffsetSeconds = evaluation.EvaluationOffsetSeconds
+			newRule.Updated = time.Now()
+			updateRules = append(updateRules, models.UpdateRule{
+				Existing: rule,
+				New:      newRule,
+			})
 		}
-	}
+		if len(updateRules) == 0 {
+			return nil
+		}
+		if err := service.ruleStore.UpdateAlertRules(ctx, updateRules); err != nil {
+			return err
+		}
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+		for _, update := range updateRules {
+			provenance := provenanceOrNone(provenances, update.New.UID)
+			if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &update.New, provenance, update.Existing, update.New); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
 
-	key := models.AlertRuleGroupKey{
-		OrgID:        orgID,
-		NamespaceUID: group.FolderUID,
-		RuleGroup:    group.Title,
+// SetRuleGroupPaused pauses or resumes the evaluation of all rules in the group.
+func (service *AlertRuleService) SetRuleGroupPaused(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string, paused bool) (err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "SetRuleGroupPaused", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", ruleGroup), attribute.Bool("paused", paused))
+	defer func() { done(err) }()
+	if err := service.authorizeRuleWrite(ctx, namespaceUID); err != nil {
+		return err
 	}
-	rules := make([]*models.AlertRuleWithOptionals, len(group.Rules))
-	group = *syncGroupRuleFields(&group, orgID)
-	for i := range group.Rules {
-		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
+	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		query := &models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{namespaceUID},
+			RuleGroup:     ruleGroup,
+		}
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		if len(ruleList) == 0 {
+			return store.ErrAlertRuleGroupNotFound
+		}
+		updateRules := make([]models.UpdateRule, 0, len(ruleList))
+		for _, rule := range ruleList {
+			if rule.IsPaused == paused {
+				continue
+			}
+			newRule := *rule
+			newRule.IsPaused = paused
+			newRule.Updated = time.Now()
+			updateRules = append(updateRules, models.UpdateRule{
+				Existing: rule,
+				New:      newRule,
+			})
+		}
+		if len(updateRules) == 0 {
+			return nil
+		}
+		if err := service.ruleStore.UpdateAlertRules(ctx, updateRules); err != nil {
+			return err
+		}
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
 			return err
 		}
-		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true})
+		for _, update := range updateRules {
+			provenance := provenanceOrNone(provenances, update.New.UID)
+			if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &update.New, provenance, update.Existing, update.New); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup, userID int64, provenance models.Provenance) (err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "ReplaceRuleGroup", orgID,
+		attribute.String("namespace_uid", group.FolderUID), attribute.String("rule_group", group.Title), attribute.Int("rules", len(group.Rules)))
+	defer func() { done(err) }()
+	if err := service.authorizeRuleWrite(ctx, group.FolderUID); err != nil {
+		return err
 	}
-	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
+	if err := service.validateNotificationSettings(ctx, orgID, group.Rules...); err != nil {
+		return err
+	}
+	delta, err := service.calcDelta(ctx, orgID, group)
 	if err != nil {
-		return fmt.Errorf("failed to calculate diff for alert rules: %w", err)
+		return err
 	}
 
-	// Refresh all calculated fields across all rules.
-	delta = store.UpdateCalculatedRuleFields(delta)
-
 	if len(delta.New) == 0 && len(delta.Update) == 0 && len(delta.Delete) == 0 {
 		return nil
 	}
 
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
+		// Fetch the provenance of all rules at once instead of querying it for every rule in the delta.
+		provenances, err := service.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+		if err != nil {
+			return err
+		}
+
 		// Delete first as this could prevent future unique constraint violations.
 		if len(delta.Delete) > 0 {
 			for _, del := range delta.Delete {
 				// check that provenance is not changed in an invalid way
-				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, del, orgID)
-				if err != nil {
-					return err
-				}
+				storedProvenance := provenanceOrNone(provenances, del.UID)
 				if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
 					return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
 				}
@@ -275,16 +532,21 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 			if err := service.deleteRules(ctx, orgID, delta.Delete...); err != nil {
 				return err
 			}
+			for _, del := range delta.Delete {
+				if del == nil {
+					continue
+				}
+				if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionDelete, del, provenance, del, nil); err != nil {
+					return err
+				}
+			}
 		}
 
 		if len(delta.Update) > 0 {
 			updates := make([]models.UpdateRule, 0, len(delta.Update))
 			for _, update := range delta.Update {
 				// check that provenance is not changed in an invalid way
-				storedProvenance, err := service.provenanceStore.GetProvenance(ctx, update.New, orgID)
-				if err != nil {
-					return err
-				}
+				storedProvenance := provenanceOrNone(provenances, update.New.UID)
 				if canUpdate := canUpdateProvenanceInRuleGroup(storedProvenance, provenance); !canUpdate {
 					return fmt.Errorf("cannot update with provided provenance '%s', needs '%s'", provenance, storedProvenance)
 				}
@@ -300,11 +562,15 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 				if err := service.provenanceStore.SetProvenance(ctx, update.New, orgID, provenance); err != nil {
 					return err
 				}
+				if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, update.New, provenance, update.Existing, update.New); err != nil {
+					return err
+				}
 			}
 		}
 
 		if len(delta.New) > 0 {
-			uids, err := service.ruleStore.InsertAlertRules(ctx, withoutNilAlertRules(delta.New))
+			inserted := withoutNilAlertRules(delta.New)
+			uids, err := service.ruleStore.InsertAlertRules(ctx, inserted)
 			if err != nil {
 				return fmt.Errorf("failed to insert alert rules: %w", err)
 			}
@@ -313,6 +579,15 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 					return err
 				}
 			}
+			for i := range inserted {
+				rule := &inserted[i]
+				if id, ok := uids[rule.UID]; ok {
+					rule.ID = id
+				}
+				if err := recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionCreate, rule, provenance, nil, rule); err != nil {
+					return err
+				}
+			}
 		}
 
 		if err = service.checkLimitsTransactionCtx(ctx, orgID, userID); err != nil {
@@ -323,18 +598,77 @@ func (service *AlertRuleService) ReplaceRuleGroup(ctx context.Context, orgID int
 	})
 }
 
+// calcDelta calculates the changes that replacing the rule group with the given one makes to the stored rules.
+func (service *AlertRuleService) calcDelta(ctx context.Context, orgID int64, group models.AlertRuleGroup) (*store.GroupDelta, error) {
+	if err := models.ValidateRuleGroupInterval(group.Interval, service.baseIntervalSeconds); err != nil {
+		return nil, err
+	}
+
+	// If the provided request did not provide the rules list at all, treat it as though it does not wish to change rules.
+	// This is done for backwards compatibility. Requests which specify only the interval must update only the interval.
+	if group.Rules == nil {
+		listRulesQuery := models.ListAlertRulesQuery{
+			OrgID:         orgID,
+			NamespaceUIDs: []string{group.FolderUID},
+			RuleGroup:     group.Title,
+		}
+		ruleList, err := service.ruleStore.ListAlertRules(ctx, &listRulesQuery)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list alert rules: %w", err)
+		}
+		group.Rules = make([]models.AlertRule, 0, len(ruleList))
+		for _, r := range ruleList {
+			if r != nil {
+				group.Rules = append(group.Rules, *r)
+			}
+		}
+	}
+
+	key := models.AlertRuleGroupKey{
+		OrgID:        orgID,
+		NamespaceUID: group.FolderUID,
+		RuleGroup:    group.Title,
+	}
+	rules := make([]*models.AlertRuleWithOptionals, len(group.Rules))
+	group = *syncGroupRuleFields(&group, orgID)
+	for i := range group.Rules {
+		if err := group.Rules[i].SetDashboardAndPanelFromAnnotations(); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &models.AlertRuleWithOptionals{AlertRule: group.Rules[i], HasPause: true, HasNotificationSettings: true})
+	}
+	delta, err := store.CalculateChanges(ctx, service.ruleStore, key, rules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate diff for alert rules: %w", err)
+	}
+
+	// Refresh all calculated fields across all rules.
+	return store.UpdateCalculatedRuleFields(delta), nil
+}
+
 // UpdateAlertRule updates an alert rule.
-func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (models.AlertRule, error) {
+func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule models.AlertRule, provenance models.Provenance) (_ models.AlertRule, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "UpdateAlertRule", rule.OrgID,
+		attribute.String("rule_uid", rule.UID))
+	defer func() { done(err) }()
 	storedRule, storedProvenance, err := service.GetAlertRule(ctx, rule.OrgID, rule.UID)
 	if err != nil {
 		return models.AlertRule{}, err
 	}
+	// Moving a rule to another folder needs the permission on both folders.
+	if err := service.authorizeRuleWrite(ctx, storedRule.NamespaceUID, rule.NamespaceUID); err != nil {
+		return models.AlertRule{}, err
+	}
+	if err := service.validateNotificationSettings(ctx, rule.OrgID, rule); err != nil {
+		return models.AlertRule{}, err
+	}
 	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
 		return models.AlertRule{}, fmt.Errorf("cannot change provenance from '%s' to '%s'", storedProvenance, provenance)
 	}
 	rule.Updated = time.Now()
 	rule.ID = storedRule.ID
 	rule.IntervalSeconds = storedRule.IntervalSeconds
+	rule.EvaluationOffsetSeconds = storedRule.EvaluationOffsetSeconds
 	err = rule.SetDashboardAndPanelFromAnnotations()
 	if err != nil {
 		return models.AlertRule{}, err
@@ -349,7 +683,10 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 		if err != nil {
 			return err
 		}
-		return service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance)
+		if err := service.provenanceStore.SetProvenance(ctx, &rule, rule.OrgID, provenance); err != nil {
+			return err
+		}
+		return recordAudit(ctx, service.provenanceStore, rule.OrgID, models.ProvisioningAuditActionUpdate, &rule, provenance, storedRule, rule)
 	})
 	if err != nil {
 		return models.AlertRule{}, err
@@ -357,7 +694,89 @@ func (service *AlertRuleService) UpdateAlertRule(ctx context.Context, rule model
 	return rule, err
 }
 
-func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) error {
+// AlertRulePatch holds the fields of an alert rule that a partial update changes. Fields that are nil are kept as
+// they are. Labels and annotations replace those of the rule as a whole.
+type AlertRulePatch struct {
+	Title        *string
+	Condition    *string
+	Data         []models.AlertQuery
+	NoDataState  *models.NoDataState
+	ExecErrState *models.ExecutionErrorState
+	For          *time.Duration
+	Annotations  map[string]string
+	Labels       map[string]string
+	IsPaused     *bool
+}
+
+// PatchAlertRule updates the fields of an alert rule that are set in the patch and keeps all others. The rule that
+// results from the patch is validated as a whole, as the condition and the queries can be changed independently.
+func (service *AlertRuleService) PatchAlertRule(ctx context.Context, orgID int64, ruleUID string, patch AlertRulePatch, provenance models.Provenance) (_ models.AlertRule, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "PatchAlertRule", orgID,
+		attribute.String("rule_uid", ruleUID))
+	defer func() { done(err) }()
+	rule, _, err := service.GetAlertRule(ctx, orgID, ruleUID)
+	if err != nil {
+		return models.AlertRule{}, err
+	}
+	if patch.Title != nil {
+		rule.Title = *patch.Title
+	}
+	if patch.Condition != nil {
+		rule.Condition = *patch.Condition
+	}
+	if patch.Data != nil {
+		rule.Data = patch.Data
+	}
+	if patch.NoDataState != nil {
+		rule.NoDataState = *patch.NoDataState
+	}
+	if patch.ExecErrState != nil {
+		rule.ExecErrState = *patch.ExecErrState
+	}
+	if patch.For != nil {
+		rule.For = *patch.For
+	}
+	if patch.Annotations != nil {
+		rule.Annotations = patch.Annotations
+	}
+	if patch.Labels != nil {
+		rule.Labels = patch.Labels
+	}
+	if patch.IsPaused != nil {
+		rule.IsPaused = *patch.IsPaused
+	}
+	if err := validatePatchedAlertRule(rule); err != nil {
+		return models.AlertRule{}, err
+	}
+	return service.UpdateAlertRule(ctx, rule, provenance)
+}
+
+// validatePatchedAlertRule checks the fields of a patched alert rule that the store does not validate.
+func validatePatchedAlertRule(rule models.AlertRule) error {
+	if rule.Title == "" {
+		return fmt.Errorf("%w: title is required", models.ErrAlertRuleFailedValidation)
+	}
+	if rule.For < 0 {
+		return fmt.Errorf("%w: duration 'for' must not be negative", models.ErrAlertRuleFailedValidation)
+	}
+	if _, err := models.NoDataStateFromString(string(rule.NoDataState)); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, err.Error())
+	}
+	if _, err := models.ErrStateFromString(string(rule.ExecErrState)); err != nil {
+		return fmt.Errorf("%w: %s", models.ErrAlertRuleFailedValidation, err.Error())
+	}
+	for _, query := range rule.Data {
+		if query.RefID == rule.Condition {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: condition '%s' is not the ref ID of any query", models.ErrAlertRuleFailedValidation, rule.Condition)
+}
+
+func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int64, ruleUID string, provenance models.Provenance) (err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "DeleteAlertRule", orgID,
+		attribute.String("rule_uid", ruleUID))
+	defer func() { done(err) }()
 	rule := &models.AlertRule{
 		OrgID: orgID,
 		UID:   ruleUID,
@@ -370,8 +789,23 @@ func (service *AlertRuleService) DeleteAlertRule(ctx context.Context, orgID int6
 	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
 		return fmt.Errorf("cannot delete with provided provenance '%s', needs '%s'", provenance, storedProvenance)
 	}
+	// The stored rule is only needed for the audit log. Deleting a rule that does not exist is not an error.
+	var oldState any
+	storedRule, err := service.ruleStore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: orgID, UID: ruleUID})
+	if err != nil && !errors.Is(err, models.ErrAlertRuleNotFound) {
+		return err
+	}
+	if err == nil {
+		if err := service.authorizeRuleWrite(ctx, storedRule.NamespaceUID); err != nil {
+			return err
+		}
+		oldState = storedRule
+	}
 	return service.xact.InTransaction(ctx, func(ctx context.Context) error {
-		return service.deleteRules(ctx, orgID, rule)
+		if err := service.deleteRules(ctx, orgID, rule); err != nil {
+			return err
+		}
+		return recordAudit(ctx, service.provenanceStore, orgID, models.ProvisioningAuditActionDelete, rule, provenance, oldState, nil)
 	})
 }
 
@@ -404,14 +838,17 @@ func (service *AlertRuleService) deleteRules(ctx context.Context, orgID int64, t
 	for _, uid := range uids {
 		if err := service.provenanceStore.DeleteProvenance(ctx, &models.AlertRule{UID: uid}, orgID); err != nil {
 			// We failed to clean up the record, but this doesn't break things. Log it and move on.
-			service.log.Warn("Failed to delete provenance record for rule: %w", err)
+			service.log.FromContext(ctx).Warn("Failed to delete provenance record for rule", "error", err)
 		}
 	}
 	return nil
 }
 
 // GetAlertRuleGroupWithFolderTitle returns the alert rule group with folder title.
-func (service *AlertRuleService) GetAlertRuleGroupWithFolderTitle(ctx context.Context, orgID int64, namespaceUID, group string) (models.AlertRuleGroupWithFolderTitle, error) {
+func (service *AlertRuleService) GetAlertRuleGroupWithFolderTitle(ctx context.Context, orgID int64, namespaceUID, group string) (_ models.AlertRuleGroupWithFolderTitle, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetAlertRuleGroupWithFolderTitle", orgID,
+		attribute.String("namespace_uid", namespaceUID), attribute.String("rule_group", group))
+	defer func() { done(err) }()
 	q := models.ListAlertRulesQuery{
 		OrgID:         orgID,
 		NamespaceUIDs: []string{namespaceUID},
@@ -452,16 +889,20 @@ func (service *AlertRuleService) GetAlertRuleGroupWithFolderTitle(ctx context.Co
 	return res, nil
 }
 
-// GetAlertGroupsWithFolderTitle returns all groups with folder title that have at least one alert.
-func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Context, orgID int64) ([]models.AlertRuleGroupWithFolderTitle, error) {
-	q := models.ListAlertRulesQuery{
-		OrgID: orgID,
+// GetAlertGroupsWithFolderTitle returns all groups with folder title that have at least one alert matching the query.
+func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Context, q AlertRuleQuery) (_ []models.AlertRuleGroupWithFolderTitle, err error) {
+	ctx, done := startOperation(ctx, service.tracer, service.metrics, "alertRule", "GetAlertGroupsWithFolderTitle", q.OrgID)
+	defer func() { done(err) }()
+	listQuery := models.ListAlertRulesQuery{
+		OrgID: q.OrgID,
 	}
 
-	ruleList, err := service.ruleStore.ListAlertRules(ctx, &q)
+	ruleList, err := service.ruleStore.ListAlertRules(ctx, &listQuery)
 	if err != nil {
 		return nil, err
 	}
+	// Groups only contain the rules that match the query.
+	ruleList = filterAlertRules(ruleList, q)
 
 	groups := make(map[models.AlertRuleGroupKey][]models.AlertRule)
 	namespaces := make(map[string][]*models.AlertRuleGroupKey)
@@ -508,7 +949,7 @@ func (service *AlertRuleService) GetAlertGroupsWithFolderTitle(ctx context.Conte
 				Interval:  rules[0].IntervalSeconds,
 				Rules:     rules,
 			},
-			OrgID:       orgID,
+			OrgID:       q.OrgID,
 			FolderTitle: title,
 		})
 	}
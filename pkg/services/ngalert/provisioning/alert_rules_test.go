@@ -8,12 +8,20 @@ import (
 	"time"
 
 	"github.com/grafana/grafana/pkg/expr"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/acimpl"
+	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/setting"
 )
 
@@ -51,6 +59,81 @@ func TestAlertRuleService(t *testing.T) {
 		}
 	})
 
+	t.Run("listing alert rules should return their provenance", func(t *testing.T) {
+		var orgID int64 = 3
+		created, err := ruleService.CreateAlertRule(context.Background(), dummyRule("test#list", orgID), models.ProvenanceFile, 0)
+		require.NoError(t, err)
+
+		rules, provenances, err := ruleService.GetAlertRules(context.Background(), AlertRuleQuery{OrgID: orgID})
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, models.ProvenanceFile, provenances[created.UID])
+	})
+
+	t.Run("listing alert rules should only return the rules matching the query", func(t *testing.T) {
+		var orgID int64 = 4
+		critical := dummyRule("Disk full", orgID)
+		critical.Labels = map[string]string{"severity": "critical"}
+		_, err := ruleService.CreateAlertRule(context.Background(), critical, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		paused := dummyRule("Disk slow", orgID)
+		paused.IsPaused = true
+		paused.Data[0].DatasourceUID = "prometheus"
+		_, err = ruleService.CreateAlertRule(context.Background(), paused, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("Instance down", "instances", orgID, "other-namespace"), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		titles := func(q AlertRuleQuery) []string {
+			q.OrgID = orgID
+			rules, _, err := ruleService.GetAlertRules(context.Background(), q)
+			require.NoError(t, err)
+			result := make([]string, 0, len(rules))
+			for _, rule := range rules {
+				result = append(result, rule.Title)
+			}
+			return result
+		}
+		matcher := func(typ labels.MatchType, name, value string) *labels.Matcher {
+			m, err := labels.NewMatcher(typ, name, value)
+			require.NoError(t, err)
+			return m
+		}
+		isPaused := true
+		require.ElementsMatch(t, []string{"Disk full", "Disk slow"}, titles(AlertRuleQuery{Title: "disk"}))
+		require.ElementsMatch(t, []string{"Disk slow"}, titles(AlertRuleQuery{Paused: &isPaused}))
+		require.ElementsMatch(t, []string{"Disk slow"}, titles(AlertRuleQuery{DatasourceUIDs: []string{"prometheus"}}))
+		require.ElementsMatch(t, []string{"Disk full"}, titles(AlertRuleQuery{
+			Matchers: labels.Matchers{matcher(labels.MatchEqual, "severity", "critical")},
+		}))
+		require.ElementsMatch(t, []string{"Disk slow", "Instance down"}, titles(AlertRuleQuery{
+			Matchers: labels.Matchers{matcher(labels.MatchNotEqual, "severity", "critical")},
+		}))
+		require.ElementsMatch(t, []string{"Instance down"}, titles(AlertRuleQuery{FolderUIDs: []string{"other-namespace"}}))
+		require.ElementsMatch(t, []string{"Disk full", "Disk slow"}, titles(AlertRuleQuery{RuleGroups: []string{"my-cool-group", "unknown"}}))
+	})
+
+	t.Run("exported groups should only contain the rules matching the query", func(t *testing.T) {
+		var orgID int64 = 5
+		critical := dummyRule("Disk full", orgID)
+		critical.Labels = map[string]string{"team": "a"}
+		_, err := ruleService.CreateAlertRule(context.Background(), critical, models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(context.Background(), dummyRule("Disk slow", orgID), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+		_, err = ruleService.CreateAlertRule(context.Background(), createTestRule("Instance down", "instances", orgID, "my-namespace"), models.ProvenanceNone, 0)
+		require.NoError(t, err)
+
+		m, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+		require.NoError(t, err)
+		groups, err := ruleService.GetAlertGroupsWithFolderTitle(context.Background(), AlertRuleQuery{OrgID: orgID, Matchers: labels.Matchers{m}})
+		require.NoError(t, err)
+		require.Len(t, groups, 1)
+		require.Equal(t, "my-cool-group", groups[0].AlertRuleGroup.Title)
+		require.Len(t, groups[0].AlertRuleGroup.Rules, 1)
+		require.Equal(t, "Disk full", groups[0].AlertRuleGroup.Rules[0].Title)
+	})
+
 	t.Run("alert rule group should be updated correctly", func(t *testing.T) {
 		rule := dummyRule("test#3", orgID)
 		rule.RuleGroup = "a"
@@ -67,6 +150,123 @@ func TestAlertRuleService(t *testing.T) {
 		require.Equal(t, interval, rule.IntervalSeconds)
 	})
 
+	t.Run("alert rule group should be paused and resumed", func(t *testing.T) {
+		group := createDummyGroup("group-test-pause", orgID)
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = ruleService.SetRuleGroupPaused(context.Background(), orgID, "my-namespace", "group-test-pause", true)
+		require.NoError(t, err)
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-pause")
+		require.NoError(t, err)
+		require.NotEmpty(t, readGroup.Rules)
+		for _, rule := range readGroup.Rules {
+			require.True(t, rule.IsPaused)
+			_, provenance, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceAPI, provenance)
+		}
+
+		err = ruleService.SetRuleGroupPaused(context.Background(), orgID, "my-namespace", "group-test-pause", false)
+		require.NoError(t, err)
+		readGroup, err = ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-pause")
+		require.NoError(t, err)
+		for _, rule := range readGroup.Rules {
+			require.False(t, rule.IsPaused)
+		}
+	})
+
+	t.Run("pausing an unknown alert rule group should fail", func(t *testing.T) {
+		err := ruleService.SetRuleGroupPaused(context.Background(), orgID, "my-namespace", "does-not-exist", true)
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("alert rule group evaluation should be set and kept by other changes", func(t *testing.T) {
+		group := createDummyGroup("group-test-evaluation", orgID)
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		evaluation := models.RuleGroupEvaluation{IntervalSeconds: 120, EvaluationOffsetSeconds: 30}
+		err = ruleService.SetRuleGroupEvaluation(context.Background(), orgID, "my-namespace", "group-test-evaluation", evaluation)
+		require.NoError(t, err)
+		read, err := ruleService.GetRuleGroupEvaluation(context.Background(), orgID, "my-namespace", "group-test-evaluation")
+		require.NoError(t, err)
+		require.Equal(t, evaluation, read)
+
+		// Replacing the group and adding rules to it keeps the offset.
+		group.Interval = 120
+		group.Rules = append(group.Rules, dummyRule("group-test-evaluation-rule-2", orgID))
+		err = ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+		rule := dummyRule("group-test-evaluation-rule-3", orgID)
+		rule.RuleGroup = "group-test-evaluation"
+		_, err = ruleService.CreateAlertRule(context.Background(), rule, models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		readGroup, err := ruleService.GetRuleGroup(context.Background(), orgID, "my-namespace", "group-test-evaluation")
+		require.NoError(t, err)
+		require.Equal(t, int64(30), readGroup.EvaluationOffset)
+		require.Len(t, readGroup.Rules, 3)
+		for _, rule := range readGroup.Rules {
+			require.Equal(t, int64(120), rule.IntervalSeconds)
+			require.Equal(t, int64(30), rule.EvaluationOffsetSeconds)
+		}
+	})
+
+	t.Run("alert rule group evaluation should be validated", func(t *testing.T) {
+		group := createDummyGroup("group-test-evaluation-invalid", orgID)
+		err := ruleService.ReplaceRuleGroup(context.Background(), orgID, group, 0, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		for _, evaluation := range []models.RuleGroupEvaluation{
+			{IntervalSeconds: 60, EvaluationOffsetSeconds: 60},
+			{IntervalSeconds: 60, EvaluationOffsetSeconds: 15},
+			{IntervalSeconds: 60, EvaluationOffsetSeconds: -10},
+			{IntervalSeconds: 0, EvaluationOffsetSeconds: 0},
+		} {
+			err = ruleService.SetRuleGroupEvaluation(context.Background(), orgID, "my-namespace", "group-test-evaluation-invalid", evaluation)
+			require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+		}
+		err = ruleService.SetRuleGroupEvaluation(context.Background(), orgID, "my-namespace", "does-not-exist", models.RuleGroupEvaluation{IntervalSeconds: 60})
+		require.ErrorIs(t, err, store.ErrAlertRuleGroupNotFound)
+	})
+
+	t.Run("patching an alert rule should only change the given fields", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("test-patch", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		forDuration := 5 * time.Minute
+		patched, err := ruleService.PatchAlertRule(context.Background(), orgID, rule.UID, AlertRulePatch{
+			For:         &forDuration,
+			Annotations: map[string]string{"summary": "patched"},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, forDuration, patched.For)
+
+		stored, _, err := ruleService.GetAlertRule(context.Background(), orgID, rule.UID)
+		require.NoError(t, err)
+		require.Equal(t, forDuration, stored.For)
+		require.Equal(t, map[string]string{"summary": "patched"}, stored.Annotations)
+		require.Equal(t, rule.Title, stored.Title)
+		require.Equal(t, rule.Condition, stored.Condition)
+	})
+
+	t.Run("patching an alert rule should validate the resulting rule", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(context.Background(), dummyRule("test-patch-invalid", orgID), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		condition := "B"
+		_, err = ruleService.PatchAlertRule(context.Background(), orgID, rule.UID, AlertRulePatch{Condition: &condition}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		noData := models.NoDataState("Unknown")
+		_, err = ruleService.PatchAlertRule(context.Background(), orgID, rule.UID, AlertRulePatch{NoDataState: &noData}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+
+		_, err = ruleService.PatchAlertRule(context.Background(), orgID, "does-not-exist", AlertRulePatch{Condition: &condition}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, models.ErrAlertRuleNotFound)
+	})
+
 	t.Run("if a folder was renamed the interval should be fetched from the renamed folder", func(t *testing.T) {
 		var orgID int64 = 2
 		rule := dummyRule("test#1", orgID)
@@ -531,6 +731,72 @@ func TestAlertRuleService(t *testing.T) {
 	})
 }
 
+func TestAlertRuleServiceFolderPermissions(t *testing.T) {
+	ruleService := createAlertRuleService(t)
+	ruleService.ac = acimpl.ProvideAccessControl(setting.NewCfg())
+	var orgID int64 = 1
+	userCtx := func(permissions map[string][]string) context.Context {
+		return appcontext.WithUser(context.Background(), &user.SignedInUser{OrgID: orgID, Permissions: map[int64]map[string][]string{orgID: permissions}})
+	}
+	teamCtx := userCtx(map[string][]string{
+		accesscontrol.ActionAlertingProvisioningWrite: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("team-folder")},
+	})
+
+	t.Run("rules can be changed in the folders of the permission", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(teamCtx, createTestRule("team rule", "team-group", orgID, "team-folder"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		rule.Title = "team rule renamed"
+		_, err = ruleService.UpdateAlertRule(teamCtx, rule, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, ruleService.SetRuleGroupPaused(teamCtx, orgID, "team-folder", "team-group", true))
+		require.NoError(t, ruleService.DeleteAlertRule(teamCtx, orgID, rule.UID, models.ProvenanceAPI))
+	})
+
+	t.Run("rules of other folders cannot be changed", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(context.Background(), createTestRule("other rule", "other-group", orgID, "other-folder"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		_, err = ruleService.CreateAlertRule(teamCtx, createTestRule("new rule", "other-group", orgID, "other-folder"), models.ProvenanceAPI, 0)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		_, err = ruleService.UpdateAlertRule(teamCtx, rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		err = ruleService.UpdateRuleGroup(teamCtx, orgID, "other-folder", "other-group", 120)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		err = ruleService.ReplaceRuleGroup(teamCtx, orgID, models.AlertRuleGroup{Title: "other-group", FolderUID: "other-folder", Interval: 60}, 0, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+		err = ruleService.DeleteAlertRule(teamCtx, orgID, rule.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+
+	t.Run("rules cannot be moved out of the folders of the permission", func(t *testing.T) {
+		rule, err := ruleService.CreateAlertRule(teamCtx, createTestRule("moved rule", "team-group", orgID, "team-folder"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+
+		rule.NamespaceUID = "other-folder"
+		_, err = ruleService.UpdateAlertRule(teamCtx, rule, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+
+	t.Run("permissions of the organization allow changing the rules of all folders", func(t *testing.T) {
+		orgCtx := userCtx(map[string][]string{
+			accesscontrol.ActionAlertingProvisioningWrite: {""},
+		})
+
+		_, err := ruleService.CreateAlertRule(orgCtx, createTestRule("org rule", "other-group", orgID, "other-folder"), models.ProvenanceAPI, 0)
+		require.NoError(t, err)
+	})
+}
+
+func TestEvalOrgProvisioningWrite(t *testing.T) {
+	require.True(t, EvalOrgProvisioningWrite().Evaluate(map[string][]string{accesscontrol.ActionAlertingProvisioningWrite: {""}}))
+	require.True(t, EvalOrgProvisioningWrite().Evaluate(map[string][]string{accesscontrol.ActionAlertingProvisioningWrite: nil}))
+	require.False(t, EvalOrgProvisioningWrite().Evaluate(map[string][]string{
+		accesscontrol.ActionAlertingProvisioningWrite: {dashboards.ScopeFoldersProvider.GetResourceScopeUID("team-folder")},
+	}))
+	require.False(t, EvalOrgProvisioningWrite().Evaluate(map[string][]string{accesscontrol.ActionAlertingProvisioningRead: {""}}))
+}
+
 func createAlertRuleService(t *testing.T) AlertRuleService {
 	t.Helper()
 	sqlStore := db.InitTestDB(t)
@@ -543,14 +809,22 @@ func createAlertRuleService(t *testing.T) AlertRuleService {
 	}
 	quotas := MockQuotaChecker{}
 	quotas.EXPECT().LimitOK()
+	dashboardService := dashboards.NewFakeDashboardService(t)
+	dashboardService.On("GetDashboards", mock.Anything, mock.AnythingOfType("*dashboards.GetDashboardsQuery")).Return([]*dashboards.Dashboard{{
+		UID:   "my-namespace",
+		Title: "My Namespace",
+	}}, nil).Maybe()
 	return AlertRuleService{
 		ruleStore:              store,
 		provenanceStore:        store,
+		amStore:                &store,
+		dashboardService:       dashboardService,
 		quotas:                 &quotas,
 		xact:                   sqlStore,
 		log:                    log.New("testing"),
 		baseIntervalSeconds:    10,
 		defaultIntervalSeconds: 60,
+		tracer:                 tracing.InitializeTracerForTest(),
 	}
 }
 
@@ -0,0 +1,407 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	commoncfg "github.com/prometheus/common/config"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// AlertmanagerImportService imports the configuration of a Prometheus Alertmanager into the alerting configuration
+// of an organization, so that receivers managed by an external Alertmanager can be migrated to Grafana.
+type AlertmanagerImportService struct {
+	contactPoints *ContactPointService
+	policies      *NotificationPolicyService
+	muteTimings   *MuteTimingService
+	templates     *TemplateService
+	xact          TransactionManager
+	log           log.Logger
+	tracer        tracing.Tracer
+	metrics       *metrics.Provisioning
+}
+
+func NewAlertmanagerImportService(contactPoints *ContactPointService, policies *NotificationPolicyService, muteTimings *MuteTimingService,
+	templates *TemplateService, xact TransactionManager, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *AlertmanagerImportService {
+	return &AlertmanagerImportService{
+		contactPoints: contactPoints,
+		policies:      policies,
+		muteTimings:   muteTimings,
+		templates:     templates,
+		xact:          xact,
+		log:           log,
+		tracer:        tracer,
+		metrics:       m,
+	}
+}
+
+// ImportAlertmanagerConfig parses the alertmanager.yml of a Prometheus Alertmanager and imports its receivers as
+// contact points, its mute time intervals as mute timings, its templates and its route as the notification policy
+// tree. Everything is imported in one transaction with ProvenanceConvertedPrometheus. Integrations and settings
+// without an equivalent in Grafana are left out and reported as warnings.
+func (svc *AlertmanagerImportService) ImportAlertmanagerConfig(ctx context.Context, orgID int64, imp definitions.AlertmanagerImport) (result definitions.AlertmanagerImportResult, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.importAlertmanagerConfig(ctx, orgID, imp)
+		return err
+	})
+	return result, err
+}
+
+func (svc *AlertmanagerImportService) importAlertmanagerConfig(ctx context.Context, orgID int64, imp definitions.AlertmanagerImport) (_ definitions.AlertmanagerImportResult, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "config", "ImportAlertmanagerConfig", orgID,
+		attribute.Int("templates", len(imp.Templates)))
+	defer func() { done(err) }()
+
+	amConfig, err := config.Load(imp.Config)
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, fmt.Errorf("%w: invalid Alertmanager configuration: %s", ErrValidation, err.Error())
+	}
+	result := definitions.AlertmanagerImportResult{
+		ContactPoints: []string{},
+		MuteTimings:   []string{},
+		Templates:     []string{},
+		Warnings:      []string{},
+	}
+	if len(amConfig.InhibitRules) > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d inhibition rules are not supported", len(amConfig.InhibitRules)))
+	}
+
+	contactPoints, err := convertAlertmanagerReceivers(amConfig.Receivers, &result)
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+	existing, err := svc.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID}, nil)
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+	for _, cp := range existing {
+		for _, name := range result.ContactPoints {
+			if cp.Name == name {
+				return definitions.AlertmanagerImportResult{}, fmt.Errorf("%w: a contact point with the name '%s' already exists", ErrValidation, name)
+			}
+		}
+	}
+
+	muteTimings := make([]definitions.MuteTimeInterval, 0, len(amConfig.MuteTimeIntervals)+len(amConfig.TimeIntervals))
+	for _, mt := range amConfig.MuteTimeIntervals {
+		muteTimings = append(muteTimings, definitions.MuteTimeInterval{MuteTimeInterval: mt, Provenance: definitions.Provenance(models.ProvenanceConvertedPrometheus)})
+	}
+	for _, ti := range amConfig.TimeIntervals {
+		muteTimings = append(muteTimings, definitions.MuteTimeInterval{
+			MuteTimeInterval: config.MuteTimeInterval{Name: ti.Name, TimeIntervals: ti.TimeIntervals},
+			Provenance:       definitions.Provenance(models.ProvenanceConvertedPrometheus),
+		})
+	}
+
+	templates, err := convertAlertmanagerTemplates(amConfig.Templates, imp.Templates)
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+
+	if err := checkActiveTimeIntervals(amConfig.Route); err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+	route := definitions.AsGrafanaRoute(amConfig.Route)
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if _, err := svc.contactPoints.CreateContactPoints(ctx, orgID, contactPoints, models.ProvenanceConvertedPrometheus); err != nil {
+			return err
+		}
+		for _, mt := range muteTimings {
+			if _, err := svc.muteTimings.CreateMuteTiming(ctx, mt, orgID); err != nil {
+				return fmt.Errorf("mute timing '%s': %w", mt.Name, err)
+			}
+			result.MuteTimings = append(result.MuteTimings, mt.Name)
+		}
+		for _, tmpl := range templates {
+			if _, err := svc.templates.SetTemplate(ctx, orgID, tmpl); err != nil {
+				return fmt.Errorf("template '%s': %w", tmpl.Name, err)
+			}
+			result.Templates = append(result.Templates, tmpl.Name)
+		}
+		_, err := svc.policies.UpdatePolicyTree(ctx, orgID, *route, models.ProvenanceConvertedPrometheus)
+		return err
+	})
+	if err != nil {
+		return definitions.AlertmanagerImportResult{}, err
+	}
+	return result, nil
+}
+
+// convertAlertmanagerTemplates returns the template files the configuration uses as templates named after the
+// files. The configuration only refers to them with file patterns, so their contents have to be given.
+func convertAlertmanagerTemplates(patterns []string, files map[string]string) ([]definitions.NotificationTemplate, error) {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	templates := make([]definitions.NotificationTemplate, 0, len(files))
+	for _, name := range names {
+		used := false
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(filepath.Base(pattern), filepath.Base(name)); ok {
+				used = true
+				break
+			}
+		}
+		if !used {
+			return nil, fmt.Errorf("%w: template file '%s' is not used by the configuration", ErrValidation, name)
+		}
+		templates = append(templates, definitions.NotificationTemplate{
+			Name:       strings.TrimSuffix(filepath.Base(name), filepath.Ext(name)),
+			Template:   files[name],
+			Provenance: definitions.Provenance(models.ProvenanceConvertedPrometheus),
+		})
+	}
+	return templates, nil
+}
+
+// checkActiveTimeIntervals fails if a route is only active during time intervals, which Grafana does not support.
+func checkActiveTimeIntervals(route *config.Route) error {
+	if len(route.ActiveTimeIntervals) > 0 {
+		return fmt.Errorf("%w: active time intervals of routes are not supported", ErrValidation)
+	}
+	for _, r := range route.Routes {
+		if err := checkActiveTimeIntervals(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// convertAlertmanagerReceivers converts the integrations of the receivers to contact points named after their
+// receiver. Integrations without an equivalent are reported as warnings. Receivers without any integration that can
+// be converted cannot be imported, as Grafana has no contact points without integrations.
+func convertAlertmanagerReceivers(receivers []config.Receiver, result *definitions.AlertmanagerImportResult) ([]definitions.EmbeddedContactPoint, error) {
+	var contactPoints []definitions.EmbeddedContactPoint
+	for _, r := range receivers {
+		converted, err := convertAlertmanagerReceiver(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: receiver '%s': %s", ErrValidation, r.Name, err.Error())
+		}
+		if len(r.SNSConfigs) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("receiver '%s': SNS integrations are not supported", r.Name))
+		}
+		if len(r.WechatConfigs) > 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("receiver '%s': WeChat integrations are not supported", r.Name))
+		}
+		if len(converted) == 0 {
+			return nil, fmt.Errorf("%w: receiver '%s' has no integration that can be imported", ErrValidation, r.Name)
+		}
+		contactPoints = append(contactPoints, converted...)
+		result.ContactPoints = append(result.ContactPoints, r.Name)
+	}
+	return contactPoints, nil
+}
+
+func convertAlertmanagerReceiver(r config.Receiver) ([]definitions.EmbeddedContactPoint, error) {
+	var contactPoints []definitions.EmbeddedContactPoint
+	add := func(typ string, sendResolved bool, settings map[string]any) {
+		contactPoints = append(contactPoints, definitions.EmbeddedContactPoint{
+			Name:                  r.Name,
+			Type:                  typ,
+			Settings:              simplejson.NewFromAny(settings),
+			DisableResolveMessage: !sendResolved,
+		})
+	}
+	for _, c := range r.DiscordConfigs {
+		settings := map[string]any{"url": secretURL(c.WebhookURL)}
+		setNonEmpty(settings, map[string]string{"title": c.Title, "message": c.Message})
+		add("discord", c.SendResolved(), settings)
+	}
+	for _, c := range r.EmailConfigs {
+		settings := map[string]any{"addresses": c.To}
+		setNonEmpty(settings, map[string]string{"subject": c.Headers["Subject"]})
+		add("email", c.SendResolved(), settings)
+	}
+	for _, c := range r.PagerdutyConfigs {
+		if c.RoutingKeyFile != "" {
+			return nil, fmt.Errorf("routing keys from files are not supported")
+		}
+		if c.RoutingKey == "" {
+			return nil, fmt.Errorf("only PagerDuty integrations with a routing key of the Events API v2 are supported")
+		}
+		settings := map[string]any{"integrationKey": string(c.RoutingKey)}
+		setNonEmpty(settings, map[string]string{
+			"severity":   c.Severity,
+			"class":      c.Class,
+			"component":  c.Component,
+			"group":      c.Group,
+			"summary":    c.Description,
+			"source":     c.Source,
+			"client":     c.Client,
+			"client_url": c.ClientURL,
+		})
+		if len(c.Details) > 0 {
+			settings["details"] = c.Details
+		}
+		add("pagerduty", c.SendResolved(), settings)
+	}
+	for _, c := range r.SlackConfigs {
+		if c.APIURLFile != "" {
+			return nil, fmt.Errorf("Slack URLs from files are not supported")
+		}
+		settings := map[string]any{}
+		if credentials, ok, err := authorizationCredentials(c.HTTPConfig); err != nil {
+			return nil, err
+		} else if ok {
+			// Slack apps post messages with a bearer token.
+			settings["token"] = credentials
+			settings["endpointUrl"] = secretURL(c.APIURL)
+		} else {
+			settings["url"] = secretURL(c.APIURL)
+		}
+		setNonEmpty(settings, map[string]string{
+			"recipient":  c.Channel,
+			"username":   c.Username,
+			"icon_emoji": c.IconEmoji,
+			"icon_url":   c.IconURL,
+			"title":      c.Title,
+			"text":       c.Text,
+		})
+		add("slack", c.SendResolved(), settings)
+	}
+	for _, c := range r.WebhookConfigs {
+		if c.URLFile != "" {
+			return nil, fmt.Errorf("webhook URLs from files are not supported")
+		}
+		settings := map[string]any{"url": secretURL(c.URL), "httpMethod": "POST"}
+		if c.MaxAlerts > 0 {
+			settings["maxAlerts"] = strconv.FormatUint(c.MaxAlerts, 10)
+		}
+		if c.HTTPConfig != nil && c.HTTPConfig.BasicAuth != nil {
+			if c.HTTPConfig.BasicAuth.PasswordFile != "" {
+				return nil, fmt.Errorf("passwords from files are not supported")
+			}
+			settings["username"] = c.HTTPConfig.BasicAuth.Username
+			settings["password"] = string(c.HTTPConfig.BasicAuth.Password)
+		} else if credentials, ok, err := authorizationCredentials(c.HTTPConfig); err != nil {
+			return nil, err
+		} else if ok {
+			settings["authorization_scheme"] = c.HTTPConfig.Authorization.Type
+			settings["authorization_credentials"] = credentials
+		}
+		add("webhook", c.SendResolved(), settings)
+	}
+	for _, c := range r.OpsGenieConfigs {
+		if c.APIKeyFile != "" {
+			return nil, fmt.Errorf("API keys from files are not supported")
+		}
+		settings := map[string]any{"apiKey": string(c.APIKey)}
+		// Grafana configures the URL including the path of the API.
+		if c.APIURL != nil {
+			settings["apiUrl"] = strings.TrimSuffix(c.APIURL.String(), "/") + "/v2/alerts"
+		}
+		setNonEmpty(settings, map[string]string{"message": c.Message, "description": c.Description})
+		add("opsgenie", c.SendResolved(), settings)
+	}
+	for _, c := range r.PushoverConfigs {
+		if c.UserKeyFile != "" || c.TokenFile != "" {
+			return nil, fmt.Errorf("user keys and tokens from files are not supported")
+		}
+		settings := map[string]any{"userKey": string(c.UserKey), "apiToken": string(c.Token)}
+		setNonEmpty(settings, map[string]string{
+			"title":    c.Title,
+			"message":  c.Message,
+			"device":   c.Device,
+			"sound":    c.Sound,
+			"priority": c.Priority,
+		})
+		// Grafana configures the retry and expiry in seconds.
+		if retry := time.Duration(c.Retry); retry > 0 {
+			settings["retry"] = strconv.Itoa(int(retry.Seconds()))
+		}
+		if expire := time.Duration(c.Expire); expire > 0 {
+			settings["expire"] = strconv.Itoa(int(expire.Seconds()))
+		}
+		add("pushover", c.SendResolved(), settings)
+	}
+	for _, c := range r.VictorOpsConfigs {
+		if c.APIKeyFile != "" {
+			return nil, fmt.Errorf("API keys from files are not supported")
+		}
+		// Grafana configures the REST endpoint including the API key and the routing key.
+		settings := map[string]any{
+			"url": fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(c.APIURL.String(), "/"), string(c.APIKey), c.RoutingKey),
+		}
+		setNonEmpty(settings, map[string]string{
+			"messageType": c.MessageType,
+			"title":       c.EntityDisplayName,
+			"description": c.StateMessage,
+		})
+		add("victorops", c.SendResolved(), settings)
+	}
+	for _, c := range r.TelegramConfigs {
+		if c.BotTokenFile != "" {
+			return nil, fmt.Errorf("bot tokens from files are not supported")
+		}
+		settings := map[string]any{
+			"bottoken": string(c.BotToken),
+			"chatid":   strconv.FormatInt(c.ChatID, 10),
+		}
+		setNonEmpty(settings, map[string]string{"message": c.Message, "parse_mode": c.ParseMode})
+		if c.DisableNotifications {
+			settings["disable_notifications"] = true
+		}
+		add("telegram", c.SendResolved(), settings)
+	}
+	for _, c := range r.WebexConfigs {
+		credentials, _, err := authorizationCredentials(c.HTTPConfig)
+		if err != nil {
+			return nil, err
+		}
+		settings := map[string]any{"bot_token": credentials, "room_id": c.RoomID}
+		if c.APIURL != nil {
+			settings["api_url"] = c.APIURL.String()
+		}
+		setNonEmpty(settings, map[string]string{"message": c.Message})
+		add("webex", c.SendResolved(), settings)
+	}
+	for _, c := range r.MSTeamsConfigs {
+		settings := map[string]any{"url": secretURL(c.WebhookURL)}
+		setNonEmpty(settings, map[string]string{"title": c.Title, "message": c.Text})
+		add("teams", c.SendResolved(), settings)
+	}
+	return contactPoints, nil
+}
+
+// authorizationCredentials returns the credentials of the Authorization header of the HTTP configuration, if any.
+func authorizationCredentials(httpConfig *commoncfg.HTTPClientConfig) (string, bool, error) {
+	if httpConfig == nil || httpConfig.Authorization == nil {
+		return "", false, nil
+	}
+	if httpConfig.Authorization.CredentialsFile != "" {
+		return "", false, fmt.Errorf("credentials from files are not supported")
+	}
+	return string(httpConfig.Authorization.Credentials), httpConfig.Authorization.Credentials != "", nil
+}
+
+func secretURL(u *config.SecretURL) string {
+	if u == nil || u.URL == nil {
+		return ""
+	}
+	return u.URL.String()
+}
+
+// setNonEmpty adds the settings that are not empty.
+func setNonEmpty(settings map[string]any, values map[string]string) {
+	for key, value := range values {
+		if value != "" {
+			settings[key] = value
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+const testUpstreamAlertmanagerConfig = `
+route:
+  receiver: team-a
+  group_by: [alertname]
+  routes:
+    - receiver: team-b
+      matchers:
+        - severity="critical"
+      mute_time_intervals: [weekends]
+receivers:
+  - name: team-a
+    slack_configs:
+      - api_url: https://hooks.slack.com/services/team-a
+        channel: '#alerts'
+        send_resolved: true
+    sns_configs:
+      - topic_arn: arn:aws:sns:us-east-1:123456789012:alerts
+        sigv4:
+          region: us-east-1
+  - name: team-b
+    pagerduty_configs:
+      - routing_key: team-b-key
+        severity: critical
+inhibit_rules:
+  - source_matchers: [severity="critical"]
+    target_matchers: [severity="warning"]
+time_intervals:
+  - name: weekends
+    time_intervals:
+      - weekdays: [saturday, sunday]
+templates:
+  - /etc/alertmanager/templates/*.tmpl
+`
+
+func TestImportAlertmanagerConfig(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("receivers, routes, mute time intervals and templates are imported", func(t *testing.T) {
+		sut := createAlertmanagerImportServiceSut(t, secretsService)
+
+		result, err := sut.ImportAlertmanagerConfig(ctx, 1, definitions.AlertmanagerImport{
+			Config:    testUpstreamAlertmanagerConfig,
+			Templates: map[string]string{"team.tmpl": `{{ define "team.title" }}{{ .CommonLabels.alertname }}{{ end }}`},
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"team-a", "team-b"}, result.ContactPoints)
+		require.Equal(t, []string{"weekends"}, result.MuteTimings)
+		require.Equal(t, []string{"team"}, result.Templates)
+		require.Len(t, result.Warnings, 2)
+
+		q := cpsQuery(1)
+		q.Name = "team-a"
+		cps, err := sut.contactPoints.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "slack", cps[0].Type)
+		require.Equal(t, "#alerts", cps[0].Settings.Get("recipient").MustString())
+		require.Equal(t, string(models.ProvenanceConvertedPrometheus), cps[0].Provenance)
+
+		tree, err := sut.policies.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "team-a", tree.Receiver)
+		require.Len(t, tree.Routes, 1)
+		require.Equal(t, "team-b", tree.Routes[0].Receiver)
+		require.Equal(t, []string{"weekends"}, tree.Routes[0].MuteTimeIntervals)
+		require.Equal(t, definitions.Provenance(models.ProvenanceConvertedPrometheus), tree.Provenance)
+	})
+
+	t.Run("invalid configurations are rejected", func(t *testing.T) {
+		sut := createAlertmanagerImportServiceSut(t, secretsService)
+
+		_, err := sut.ImportAlertmanagerConfig(ctx, 1, definitions.AlertmanagerImport{Config: "route: {}"})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("receivers that collide with existing contact points are rejected", func(t *testing.T) {
+		sut := createAlertmanagerImportServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		cp.Name = "team-b"
+		_, err := sut.contactPoints.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.ImportAlertmanagerConfig(ctx, 1, definitions.AlertmanagerImport{Config: testUpstreamAlertmanagerConfig})
+		require.ErrorIs(t, err, ErrValidation)
+		muteTimings, err := sut.muteTimings.GetMuteTimings(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, muteTimings)
+	})
+
+	t.Run("receivers without integrations that can be imported are rejected", func(t *testing.T) {
+		sut := createAlertmanagerImportServiceSut(t, secretsService)
+
+		_, err := sut.ImportAlertmanagerConfig(ctx, 1, definitions.AlertmanagerImport{Config: `
+route:
+  receiver: blackhole
+receivers:
+  - name: blackhole
+`})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createAlertmanagerImportServiceSut(t *testing.T, secretsService *manager.SecretsService) *AlertmanagerImportService {
+	contactPoints := createContactPointServiceSut(t, secretsService)
+	logger := log.NewNopLogger()
+	tracer := tracing.InitializeTracerForTest()
+	policies := NewNotificationPolicyService(contactPoints.amStore, contactPoints.provenanceStore, contactPoints.xact, nil,
+		setting.UnifiedAlertingSettings{DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration()}, logger, tracer, nil)
+	muteTimings := NewMuteTimingService(contactPoints.amStore, contactPoints.provenanceStore, contactPoints.ruleStore, contactPoints.xact, nil, logger, tracer, nil)
+	templates := NewTemplateService(contactPoints.amStore, contactPoints.provenanceStore, contactPoints.xact, nil, logger, tracer, nil)
+	return NewAlertmanagerImportService(contactPoints, policies, muteTimings, templates, contactPoints.xact, logger, tracer, nil)
+}
@@ -0,0 +1,93 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ConfigChangeAnnotationTag is the tag of the annotations written for changes of the alerting configuration.
+const ConfigChangeAnnotationTag = "alerting-config"
+
+// AnnotationStore saves annotations.
+type AnnotationStore interface {
+	Save(ctx context.Context, item *annotations.Item) error
+}
+
+// annotatingProvisioningStore is a ProvisioningStore that writes an organization-wide annotation for every change
+// added to the audit log, so that changes of notification behavior can be correlated with configuration edits on
+// dashboards.
+type annotatingProvisioningStore struct {
+	ProvisioningStore
+	annotations AnnotationStore
+	log         log.Logger
+}
+
+// NewAnnotatingProvisioningStore returns a ProvisioningStore that annotates the changes recorded in the given store.
+// Annotations are best effort: failing to write one does not fail the change.
+func NewAnnotatingProvisioningStore(store ProvisioningStore, annotations AnnotationStore, log log.Logger) ProvisioningStore {
+	return &annotatingProvisioningStore{
+		ProvisioningStore: store,
+		annotations:       annotations,
+		log:               log,
+	}
+}
+
+func (s *annotatingProvisioningStore) InsertProvisioningAuditEntry(ctx context.Context, entry *models.ProvisioningAuditEntry) error {
+	if err := s.ProvisioningStore.InsertProvisioningAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	item := configChangeAnnotation(entry)
+	if err := s.annotations.Save(ctx, item); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to annotate alerting configuration change", "org", entry.OrgID,
+			"resourceType", entry.ResourceType, "resourceId", entry.ResourceID, "error", err)
+	}
+	return nil
+}
+
+var resourceTypeNames = map[string]string{
+	"contactPoint":     "Contact point",
+	"route":            "Notification policies",
+	"template":         "Notification template",
+	"muteTimeInterval": "Mute timing",
+	"alertRule":        "Alert rule",
+}
+
+func configChangeAnnotation(entry *models.ProvisioningAuditEntry) *annotations.Item {
+	name, ok := resourceTypeNames[entry.ResourceType]
+	if !ok {
+		name = entry.ResourceType
+	}
+	text := name
+	if entry.ResourceID != "" {
+		text = fmt.Sprintf("%s %q", name, entry.ResourceID)
+	}
+	text = fmt.Sprintf("%s %sd", text, entry.Action)
+	if entry.ActorLogin != "" {
+		text = fmt.Sprintf("%s by %s", text, entry.ActorLogin)
+	}
+	epoch := entry.Created * 1000
+	return &annotations.Item{
+		OrgID:    entry.OrgID,
+		UserID:   entry.ActorID,
+		Text:     text,
+		Epoch:    epoch,
+		EpochEnd: epoch,
+		Tags: []string{
+			ConfigChangeAnnotationTag,
+			"resourceType:" + entry.ResourceType,
+			"action:" + string(entry.Action),
+		},
+		Data: simplejson.NewFromAny(map[string]any{
+			"resourceType": entry.ResourceType,
+			"resourceId":   entry.ResourceID,
+			"action":       entry.Action,
+			"provenance":   entry.Provenance,
+			"actorLogin":   entry.ActorLogin,
+		}),
+	}
+}
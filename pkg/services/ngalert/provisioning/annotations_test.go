@@ -0,0 +1,70 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/annotations"
+	"github.com/grafana/grafana/pkg/services/annotations/annotationstest"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestAnnotatingProvisioningStore(t *testing.T) {
+	entry := models.ProvisioningAuditEntry{
+		OrgID:        1,
+		ActorID:      42,
+		ActorLogin:   "editor",
+		Action:       models.ProvisioningAuditActionUpdate,
+		ResourceType: "contactPoint",
+		ResourceID:   "cp-uid",
+		Provenance:   models.ProvenanceAPI,
+		Created:      1000,
+	}
+
+	t.Run("annotates changes recorded in the audit log", func(t *testing.T) {
+		store := NewFakeProvisioningStore()
+		repo := annotationstest.NewFakeAnnotationsRepo()
+		sut := NewAnnotatingProvisioningStore(store, repo, log.NewNopLogger())
+
+		e := entry
+		require.NoError(t, sut.InsertProvisioningAuditEntry(context.Background(), &e))
+
+		require.Len(t, store.auditEntries, 1)
+		require.Equal(t, 1, repo.Len())
+		item := repo.Items()[1]
+		require.Equal(t, int64(1), item.OrgID)
+		require.Equal(t, int64(42), item.UserID)
+		require.Equal(t, int64(1000000), item.Epoch)
+		require.Equal(t, `Contact point "cp-uid" updated by editor`, item.Text)
+		require.Equal(t, []string{ConfigChangeAnnotationTag, "resourceType:contactPoint", "action:update"}, item.Tags)
+		require.Equal(t, "cp-uid", item.Data.Get("resourceId").MustString())
+	})
+
+	t.Run("describes changes without resource identifier or actor", func(t *testing.T) {
+		item := configChangeAnnotation(&models.ProvisioningAuditEntry{
+			OrgID:        1,
+			Action:       models.ProvisioningAuditActionUpdate,
+			ResourceType: "route",
+		})
+		require.Equal(t, "Notification policies updated", item.Text)
+	})
+
+	t.Run("does not fail the change if the annotation cannot be saved", func(t *testing.T) {
+		store := NewFakeProvisioningStore()
+		sut := NewAnnotatingProvisioningStore(store, failingAnnotationStore{}, log.NewNopLogger())
+
+		e := entry
+		require.NoError(t, sut.InsertProvisioningAuditEntry(context.Background(), &e))
+		require.Len(t, store.auditEntries, 1)
+	})
+}
+
+type failingAnnotationStore struct{}
+
+func (failingAnnotationStore) Save(context.Context, *annotations.Item) error {
+	return errors.New("test error")
+}
@@ -0,0 +1,81 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+type sourceContextKey struct{}
+
+// WithSource returns a context that attributes the changes made with it to the given source, for example the path of
+// the file the resources are provisioned from. The source is recorded in the audit log.
+func WithSource(ctx context.Context, source string) context.Context {
+	return context.WithValue(ctx, sourceContextKey{}, source)
+}
+
+func sourceFromContext(ctx context.Context) string {
+	source, _ := ctx.Value(sourceContextKey{}).(string)
+	return source
+}
+
+// recordAudit adds an entry for a change of a provisioned resource to the audit log. It is meant to be called within
+// the transaction of the change, so that the entry is only kept if the change is. The actor is the user found in the
+// context, if any, and so are the source and the correlation ID. The old and new states are recorded as JSON and must not contain secrets;
+// either can be nil.
+func recordAudit(ctx context.Context, store ProvisioningStore, orgID int64, action models.ProvisioningAuditAction,
+	resource models.Provisionable, provenance models.Provenance, oldState, newState any) error {
+	entry := &models.ProvisioningAuditEntry{
+		OrgID:         orgID,
+		Action:        action,
+		ResourceType:  resource.ResourceType(),
+		ResourceID:    resource.ResourceID(),
+		Provenance:    provenance,
+		Source:        sourceFromContext(ctx),
+		CorrelationID: correlationID(ctx),
+		Created:       time.Now().Unix(),
+	}
+	if u, err := appcontext.User(ctx); err == nil {
+		entry.ActorID = u.UserID
+		entry.ActorLogin = u.Login
+	}
+	var err error
+	if entry.OldState, err = auditState(oldState); err != nil {
+		return err
+	}
+	if entry.NewState, err = auditState(newState); err != nil {
+		return err
+	}
+	return store.InsertProvisioningAuditEntry(ctx, entry)
+}
+
+func auditState(state any) (string, error) {
+	if state == nil {
+		return "", nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize state for the audit log: %w", err)
+	}
+	// Nil pointers are recorded as the absence of state as well.
+	if string(data) == "null" {
+		return "", nil
+	}
+	return string(data), nil
+}
+
+// redactedReceiver returns a copy of a receiver that is safe to record in the audit log. Secure settings are
+// encrypted in the configuration already, but only their presence is recorded.
+func redactedReceiver(r *apimodels.PostableGrafanaReceiver) *apimodels.PostableGrafanaReceiver {
+	redacted := *r
+	redacted.SecureSettings = make(map[string]string, len(r.SecureSettings))
+	for k := range r.SecureSettings {
+		redacted.SecureSettings[k] = apimodels.RedactedValue
+	}
+	return &redacted
+}
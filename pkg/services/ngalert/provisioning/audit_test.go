@@ -0,0 +1,58 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestRecordAudit(t *testing.T) {
+	tmpl := &definitions.NotificationTemplate{Name: "a", Template: "content"}
+
+	t.Run("records the user of the context as the actor", func(t *testing.T) {
+		store := NewFakeProvisioningStore()
+		ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{UserID: 42, Login: "editor"})
+
+		require.NoError(t, recordAudit(ctx, store, 1, models.ProvisioningAuditActionCreate, tmpl, models.ProvenanceAPI, nil, tmpl))
+
+		require.Len(t, store.auditEntries, 1)
+		entry := store.auditEntries[0]
+		require.Equal(t, int64(42), entry.ActorID)
+		require.Equal(t, "editor", entry.ActorLogin)
+		require.Empty(t, entry.Source)
+		require.Empty(t, entry.OldState)
+		require.JSONEq(t, `{"name":"a","template":"content"}`, entry.NewState)
+	})
+
+	t.Run("records the source of the context", func(t *testing.T) {
+		store := NewFakeProvisioningStore()
+		ctx := WithSource(context.Background(), "/etc/grafana/provisioning/alerting/templates.yaml")
+
+		require.NoError(t, recordAudit(ctx, store, 1, models.ProvisioningAuditActionDelete, tmpl, models.ProvenanceFile, tmpl, nil))
+
+		require.Len(t, store.auditEntries, 1)
+		entry := store.auditEntries[0]
+		require.Zero(t, entry.ActorID)
+		require.Equal(t, "/etc/grafana/provisioning/alerting/templates.yaml", entry.Source)
+		require.Empty(t, entry.NewState)
+	})
+
+	t.Run("records the trace ID of the context as the correlation ID", func(t *testing.T) {
+		store := NewFakeProvisioningStore()
+		ctx, span := tracing.InitializeTracerForTest().Start(context.Background(), "request")
+		defer span.End()
+
+		require.NoError(t, recordAudit(ctx, store, 1, models.ProvisioningAuditActionUpdate, tmpl, models.ProvenanceAPI, tmpl, tmpl))
+
+		require.Len(t, store.auditEntries, 1)
+		require.NotEmpty(t, store.auditEntries[0].CorrelationID)
+		require.Equal(t, tracing.TraceIDFromContext(ctx, false), store.auditEntries[0].CorrelationID)
+	})
+}
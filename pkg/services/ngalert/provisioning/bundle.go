@@ -0,0 +1,164 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ProvisioningBundle is a set of resources of different types that are applied together.
+type ProvisioningBundle struct {
+	// ContactPoints are updated if a contact point with the same UID exists, and created otherwise.
+	ContactPoints []definitions.EmbeddedContactPoint
+	// Policies replaces the notification policy tree if set.
+	Policies *definitions.Route
+	// MuteTimings are updated if a mute timing with the same name exists, and created otherwise.
+	MuteTimings []definitions.MuteTimeInterval
+	// Templates are created or replaced by name.
+	Templates []definitions.NotificationTemplate
+	// RuleGroups replace the rule groups with the same folder and title.
+	RuleGroups []models.AlertRuleGroup
+}
+
+// BundleService applies provisioning bundles, so that GitOps workflows can change several resources of an
+// organization without leaving the organization in a partially changed state if one of them is rejected.
+type BundleService struct {
+	contactPoints *ContactPointService
+	policies      *NotificationPolicyService
+	muteTimings   *MuteTimingService
+	templates     *TemplateService
+	alertRules    *AlertRuleService
+	xact          TransactionManager
+	log           log.Logger
+	tracer        tracing.Tracer
+	metrics       *metrics.Provisioning
+}
+
+func NewBundleService(contactPoints *ContactPointService, policies *NotificationPolicyService, muteTimings *MuteTimingService,
+	templates *TemplateService, alertRules *AlertRuleService, xact TransactionManager, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *BundleService {
+	return &BundleService{
+		contactPoints: contactPoints,
+		policies:      policies,
+		muteTimings:   muteTimings,
+		templates:     templates,
+		alertRules:    alertRules,
+		xact:          xact,
+		log:           log,
+		tracer:        tracer,
+		metrics:       m,
+	}
+}
+
+// ApplyProvisioningBundle applies all resources of the bundle in one transaction with the given provenance. If any
+// of them is rejected, none of the changes are kept and the error names the rejected resource. Resources are applied
+// in the order they can depend on each other: templates, mute timings, contact points, the notification policy tree
+// and finally the rule groups.
+func (svc *BundleService) ApplyProvisioningBundle(ctx context.Context, orgID int64, bundle ProvisioningBundle, userID int64,
+	provenance models.Provenance) (result definitions.ProvisioningBundleResult, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.applyProvisioningBundle(ctx, orgID, bundle, userID, provenance)
+		return err
+	})
+	return result, err
+}
+
+func (svc *BundleService) applyProvisioningBundle(ctx context.Context, orgID int64, bundle ProvisioningBundle, userID int64,
+	provenance models.Provenance) (_ definitions.ProvisioningBundleResult, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "bundle", "ApplyProvisioningBundle", orgID,
+		attribute.Int("contact_points", len(bundle.ContactPoints)), attribute.Int("mute_timings", len(bundle.MuteTimings)),
+		attribute.Int("templates", len(bundle.Templates)), attribute.Int("rule_groups", len(bundle.RuleGroups)))
+	defer func() { done(err) }()
+
+	result := definitions.ProvisioningBundleResult{
+		ContactPoints: []string{},
+		MuteTimings:   []string{},
+		Templates:     []string{},
+		RuleGroups:    []string{},
+	}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		for _, tmpl := range bundle.Templates {
+			tmpl.Provenance = definitions.Provenance(provenance)
+			if _, err := svc.templates.SetTemplate(ctx, orgID, tmpl); err != nil {
+				return fmt.Errorf("template '%s': %w", tmpl.Name, err)
+			}
+			result.Templates = append(result.Templates, tmpl.Name)
+		}
+
+		if len(bundle.MuteTimings) > 0 {
+			existing, err := svc.muteTimings.GetMuteTimings(ctx, orgID)
+			if err != nil {
+				return err
+			}
+			names := make(map[string]struct{}, len(existing))
+			for _, mt := range existing {
+				names[mt.Name] = struct{}{}
+			}
+			for _, mt := range bundle.MuteTimings {
+				mt.Provenance = definitions.Provenance(provenance)
+				if _, ok := names[mt.Name]; ok {
+					_, err = svc.muteTimings.UpdateMuteTiming(ctx, mt, orgID)
+				} else {
+					_, err = svc.muteTimings.CreateMuteTiming(ctx, mt, orgID)
+					names[mt.Name] = struct{}{}
+				}
+				if err != nil {
+					return fmt.Errorf("mute timing '%s': %w", mt.Name, err)
+				}
+				result.MuteTimings = append(result.MuteTimings, mt.Name)
+			}
+		}
+
+		if len(bundle.ContactPoints) > 0 {
+			existing, err := svc.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID}, nil)
+			if err != nil {
+				return err
+			}
+			uids := make(map[string]struct{}, len(existing))
+			for _, cp := range existing {
+				uids[cp.UID] = struct{}{}
+			}
+			for _, cp := range bundle.ContactPoints {
+				name := cp.Name
+				if _, ok := uids[cp.UID]; ok && cp.UID != "" {
+					err = svc.contactPoints.UpdateContactPoint(ctx, orgID, cp, provenance, UpdateContactPointOptions{})
+				} else {
+					cp, err = svc.contactPoints.CreateContactPoint(ctx, orgID, cp, provenance)
+				}
+				if err != nil {
+					return fmt.Errorf("contact point '%s': %w", name, err)
+				}
+				uids[cp.UID] = struct{}{}
+				result.ContactPoints = append(result.ContactPoints, cp.UID)
+			}
+		}
+
+		if bundle.Policies != nil {
+			if _, err := svc.policies.UpdatePolicyTree(ctx, orgID, *bundle.Policies, provenance); err != nil {
+				return fmt.Errorf("notification policies: %w", err)
+			}
+			result.Policies = true
+		}
+
+		for _, group := range bundle.RuleGroups {
+			if err := svc.alertRules.ReplaceRuleGroup(ctx, orgID, group, userID, provenance); err != nil {
+				return fmt.Errorf("rule group '%s' in folder '%s': %w", group.Title, group.FolderUID, err)
+			}
+			result.RuleGroups = append(result.RuleGroups, group.FolderUID+"/"+group.Title)
+		}
+		return nil
+	})
+	if err != nil {
+		return definitions.ProvisioningBundleResult{}, err
+	}
+	svc.log.FromContext(ctx).Info("Applied provisioning bundle", "org", orgID, "contactPoints", len(result.ContactPoints),
+		"muteTimings", len(result.MuteTimings), "templates", len(result.Templates), "policies", result.Policies,
+		"ruleGroups", len(result.RuleGroups))
+	return result, nil
+}
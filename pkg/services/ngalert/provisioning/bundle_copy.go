@@ -0,0 +1,213 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// CopyAlertingConfigOptions selects the resources that CopyAlertingConfig copies from one organization to another,
+// and how the folders and data sources they reference are mapped between the organizations.
+type CopyAlertingConfigOptions struct {
+	// ContactPoints are the names of the contact points to copy, with all of their integrations.
+	ContactPoints []string
+	// Templates are the names of the templates to copy.
+	Templates []string
+	// MuteTimings are the names of the mute timings to copy.
+	MuteTimings []string
+	// Policies copies the notification policy tree, replacing the tree of the target organization.
+	Policies bool
+	// RuleGroups are the rule groups to copy, identified by the UID of their folder in the source organization.
+	RuleGroups []CopyRuleGroup
+	// FolderUIDs maps the UIDs of folders in the source organization to the UIDs of folders in the target
+	// organization. Folders that are not mapped keep their UID.
+	FolderUIDs map[string]string
+	// DatasourceUIDs maps the UIDs of data sources in the source organization to the UIDs of data sources in the
+	// target organization. Data sources that are not mapped keep their UID.
+	DatasourceUIDs map[string]string
+	// User reads the secrets of the copied contact points in the source organization, which requires the
+	// alert.provisioning.secrets:read permission, and is recorded as the author of the copied rules.
+	User *user.SignedInUser
+	// Provenance is the provenance of the copied resources in the target organization.
+	Provenance models.Provenance
+}
+
+// CopyRuleGroup identifies a rule group to copy.
+type CopyRuleGroup struct {
+	FolderUID string
+	Title     string
+}
+
+// CopyAlertingConfig copies the selected resources of the source organization into the target organization, for
+// example to set up the alerting of a new tenant from a template organization. The secure settings of the contact
+// points are decrypted and encrypted again by the target organization. Copied rules get new UIDs, and their folders
+// and data sources are mapped with the options. All resources are applied in one transaction like a provisioning
+// bundle, so nothing is copied if any of them is rejected.
+func (svc *BundleService) CopyAlertingConfig(ctx context.Context, sourceOrgID, targetOrgID int64, opts CopyAlertingConfigOptions) (_ definitions.ProvisioningBundleResult, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "bundle", "CopyAlertingConfig", sourceOrgID,
+		attribute.Int64("target_org_id", targetOrgID))
+	defer func() { done(err) }()
+
+	if sourceOrgID == targetOrgID {
+		return definitions.ProvisioningBundleResult{}, newValidationError("targetOrgId", "the source and target organization must be different")
+	}
+	bundle, err := svc.selectCopiedResources(ctx, sourceOrgID, opts)
+	if err != nil {
+		return definitions.ProvisioningBundleResult{}, err
+	}
+	for i := range bundle.RuleGroups {
+		if err := remapRuleGroup(&bundle.RuleGroups[i], targetOrgID, opts); err != nil {
+			return definitions.ProvisioningBundleResult{}, err
+		}
+	}
+
+	var userID int64
+	if opts.User != nil {
+		userID = opts.User.UserID
+	}
+	result, err := svc.ApplyProvisioningBundle(ctx, targetOrgID, bundle, userID, opts.Provenance)
+	if err != nil {
+		return definitions.ProvisioningBundleResult{}, err
+	}
+	svc.log.FromContext(ctx).Info("Copied alerting configuration", "sourceOrg", sourceOrgID, "targetOrg", targetOrgID)
+	return result, nil
+}
+
+// selectCopiedResources reads the resources selected by the options from the source organization. An error is
+// returned if any of them does not exist.
+func (svc *BundleService) selectCopiedResources(ctx context.Context, orgID int64, opts CopyAlertingConfigOptions) (ProvisioningBundle, error) {
+	var bundle ProvisioningBundle
+
+	if len(opts.Templates) > 0 {
+		templates, err := svc.templates.GetTemplates(ctx, orgID)
+		if err != nil {
+			return ProvisioningBundle{}, err
+		}
+		for _, name := range opts.Templates {
+			tmpl, ok := templates[name]
+			if !ok {
+				return ProvisioningBundle{}, newNotFoundError((&definitions.NotificationTemplate{}).ResourceType(), name, "template '%s' not found", name)
+			}
+			bundle.Templates = append(bundle.Templates, definitions.NotificationTemplate{Name: name, Template: tmpl})
+		}
+	}
+
+	if len(opts.MuteTimings) > 0 {
+		muteTimings, err := svc.muteTimings.GetMuteTimings(ctx, orgID)
+		if err != nil {
+			return ProvisioningBundle{}, err
+		}
+		byName := make(map[string]definitions.MuteTimeInterval, len(muteTimings))
+		for _, mt := range muteTimings {
+			byName[mt.Name] = mt
+		}
+		for _, name := range opts.MuteTimings {
+			mt, ok := byName[name]
+			if !ok {
+				return ProvisioningBundle{}, newNotFoundError((&definitions.MuteTimeInterval{}).ResourceType(), name, "mute timing '%s' not found", name)
+			}
+			bundle.MuteTimings = append(bundle.MuteTimings, mt)
+		}
+	}
+
+	if len(opts.ContactPoints) > 0 {
+		contactPoints, err := svc.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID, Decrypt: true}, opts.User)
+		if err != nil {
+			return ProvisioningBundle{}, err
+		}
+		for _, name := range opts.ContactPoints {
+			found := false
+			for _, cp := range contactPoints {
+				if cp.Name != name {
+					continue
+				}
+				found = true
+				cp.Provenance = ""
+				cp.Warnings = nil
+				bundle.ContactPoints = append(bundle.ContactPoints, cp)
+			}
+			if !found {
+				return ProvisioningBundle{}, newNotFoundError((&definitions.EmbeddedContactPoint{}).ResourceType(), name, "contact point '%s' not found", name)
+			}
+		}
+	}
+
+	if opts.Policies {
+		policies, err := svc.policies.GetPolicyTree(ctx, orgID)
+		if err != nil {
+			return ProvisioningBundle{}, err
+		}
+		bundle.Policies = &policies
+	}
+
+	for _, g := range opts.RuleGroups {
+		group, err := svc.alertRules.GetRuleGroup(ctx, orgID, g.FolderUID, g.Title)
+		if err != nil {
+			return ProvisioningBundle{}, fmt.Errorf("rule group '%s' in folder '%s': %w", g.Title, g.FolderUID, err)
+		}
+		bundle.RuleGroups = append(bundle.RuleGroups, group)
+	}
+	return bundle, nil
+}
+
+// remapRuleGroup prepares a rule group of the source organization to be created in the target organization. The
+// rules lose their identity, so that they are created rather than updated, and their folder and the data sources of
+// their queries are mapped.
+func remapRuleGroup(group *models.AlertRuleGroup, targetOrgID int64, opts CopyAlertingConfigOptions) error {
+	if uid, ok := opts.FolderUIDs[group.FolderUID]; ok {
+		group.FolderUID = uid
+	}
+	group.Provenance = models.ProvenanceNone
+	for i := range group.Rules {
+		rule := &group.Rules[i]
+		rule.ID = 0
+		rule.UID = ""
+		rule.Version = 0
+		rule.OrgID = targetOrgID
+		rule.NamespaceUID = group.FolderUID
+		data := make([]models.AlertQuery, 0, len(rule.Data))
+		for _, q := range rule.Data {
+			uid, ok := opts.DatasourceUIDs[q.DatasourceUID]
+			if !ok {
+				data = append(data, q)
+				continue
+			}
+			model, err := remapQueryDatasource(q.Model, q.DatasourceUID, uid)
+			if err != nil {
+				return newValidationError("", "query '%s' of rule '%s' has an invalid model: %s", q.RefID, rule.Title, err.Error())
+			}
+			q.DatasourceUID = uid
+			q.Model = model
+			data = append(data, q)
+		}
+		rule.Data = data
+	}
+	return nil
+}
+
+// remapQueryDatasource replaces the data source that is referenced by the model of a query, if it is the given one.
+// Numbers are kept as they are written, so that large integers are not rounded.
+func remapQueryDatasource(model json.RawMessage, from, to string) (json.RawMessage, error) {
+	if len(model) == 0 {
+		return model, nil
+	}
+	var m map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(model))
+	decoder.UseNumber()
+	if err := decoder.Decode(&m); err != nil {
+		return nil, err
+	}
+	ds, ok := m["datasource"].(map[string]any)
+	if !ok || ds["uid"] != from {
+		return model, nil
+	}
+	ds["uid"] = to
+	return json.Marshal(m)
+}
@@ -0,0 +1,253 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/google/go-cmp/cmp"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/cmputil"
+)
+
+const (
+	contactPointResourceType       = "contactPoint"
+	templateResourceType           = "template"
+	muteTimingResourceType         = "muteTimeInterval"
+	notificationPolicyResourceType = "route"
+	alertRuleResourceType          = "alertRule"
+)
+
+// DiffProvisioningBundle compares the bundle with the current state of the organization and returns the changes
+// that applying it would make to every resource of the bundle, without changing anything. Secrets of contact points
+// are redacted in the returned changes.
+func (svc *BundleService) DiffProvisioningBundle(ctx context.Context, orgID int64, bundle ProvisioningBundle) (_ definitions.ProvisioningBundleDiff, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "bundle", "DiffProvisioningBundle", orgID,
+		attribute.Int("contact_points", len(bundle.ContactPoints)), attribute.Int("mute_timings", len(bundle.MuteTimings)),
+		attribute.Int("templates", len(bundle.Templates)), attribute.Int("rule_groups", len(bundle.RuleGroups)))
+	defer func() { done(err) }()
+
+	result := definitions.ProvisioningBundleDiff{Resources: []definitions.ResourceDiff{}}
+
+	if len(bundle.Templates) > 0 {
+		existing, err := svc.templates.GetTemplates(ctx, orgID)
+		if err != nil {
+			return definitions.ProvisioningBundleDiff{}, err
+		}
+		for _, tmpl := range bundle.Templates {
+			// Templates are stored wrapped in a definition, compare them the way they would be stored.
+			if err := tmpl.Validate(); err != nil {
+				return definitions.ProvisioningBundleDiff{}, fmt.Errorf("%w: template '%s': %s", ErrValidation, tmpl.Name, err.Error())
+			}
+			current, ok := existing[tmpl.Name]
+			diff := definitions.ResourceDiff{ResourceType: templateResourceType, ResourceID: tmpl.Name, Name: tmpl.Name}
+			switch {
+			case !ok:
+				diff.Action = definitions.ResourceDiffCreate
+			case current == tmpl.Template:
+				diff.Action = definitions.ResourceDiffNoop
+			default:
+				diff.Action = definitions.ResourceDiffUpdate
+				diff.Changes = []definitions.ConfigChange{{Path: "[template]", Before: current, After: tmpl.Template}}
+			}
+			result.Resources = append(result.Resources, diff)
+		}
+	}
+
+	if len(bundle.MuteTimings) > 0 {
+		existing, err := svc.muteTimings.GetMuteTimings(ctx, orgID)
+		if err != nil {
+			return definitions.ProvisioningBundleDiff{}, err
+		}
+		byName := make(map[string]definitions.MuteTimeInterval, len(existing))
+		for _, mt := range existing {
+			byName[mt.Name] = mt
+		}
+		for _, mt := range bundle.MuteTimings {
+			diff := definitions.ResourceDiff{ResourceType: muteTimingResourceType, ResourceID: mt.Name, Name: mt.Name}
+			current, ok := byName[mt.Name]
+			if !ok {
+				diff.Action = definitions.ResourceDiffCreate
+			} else if diff.Changes, err = jsonChanges(current.MuteTimeInterval, mt.MuteTimeInterval, nil); err != nil {
+				return definitions.ProvisioningBundleDiff{}, fmt.Errorf("mute timing '%s': %w", mt.Name, err)
+			}
+			result.Resources = append(result.Resources, withUpdateAction(diff))
+		}
+	}
+
+	if len(bundle.ContactPoints) > 0 {
+		revision, err := getLastConfiguration(ctx, orgID, svc.contactPoints.amStore)
+		if err != nil {
+			return definitions.ProvisioningBundleDiff{}, err
+		}
+		for _, cp := range bundle.ContactPoints {
+			diff, err := svc.diffContactPoint(revision, cp)
+			if err != nil {
+				return definitions.ProvisioningBundleDiff{}, fmt.Errorf("contact point '%s': %w", cp.Name, err)
+			}
+			result.Resources = append(result.Resources, diff)
+		}
+	}
+
+	if bundle.Policies != nil {
+		current, err := svc.policies.GetPolicyTree(ctx, orgID)
+		if err != nil {
+			return definitions.ProvisioningBundleDiff{}, err
+		}
+		desired := *bundle.Policies
+		current.Provenance, desired.Provenance = "", ""
+		diff := definitions.ResourceDiff{ResourceType: notificationPolicyResourceType, ResourceID: notificationPolicyResourceType}
+		if diff.Changes, err = jsonChanges(current, desired, nil); err != nil {
+			return definitions.ProvisioningBundleDiff{}, fmt.Errorf("notification policies: %w", err)
+		}
+		result.Resources = append(result.Resources, withUpdateAction(diff))
+	}
+
+	for _, group := range bundle.RuleGroups {
+		diffs, err := svc.diffRuleGroup(ctx, orgID, group)
+		if err != nil {
+			return definitions.ProvisioningBundleDiff{}, fmt.Errorf("rule group '%s' in folder '%s': %w", group.Title, group.FolderUID, err)
+		}
+		result.Resources = append(result.Resources, diffs...)
+	}
+	return result, nil
+}
+
+// diffContactPoint compares a contact point of a bundle with the stored one that has the same UID. Redacted secrets
+// of the desired contact point keep the stored value, the same way they do when the contact point is updated.
+func (svc *BundleService) diffContactPoint(revision *cfgRevision, cp definitions.EmbeddedContactPoint) (definitions.ResourceDiff, error) {
+	diff := definitions.ResourceDiff{ResourceType: contactPointResourceType, ResourceID: cp.UID, Name: cp.Name}
+	if _, ok := revision.receivers().receiver(cp.UID); !ok || cp.UID == "" {
+		diff.Action = definitions.ResourceDiffCreate
+		return diff, nil
+	}
+	current, err := svc.contactPoints.getContactPointDecrypted(revision, cp.UID)
+	if err != nil {
+		return definitions.ResourceDiff{}, err
+	}
+	secretKeys, err := GetSecretKeysForContactPointType(cp.Type)
+	if err != nil {
+		return definitions.ResourceDiff{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	settings := map[string]any{}
+	if cp.Settings != nil {
+		for key, value := range cp.Settings.MustMap() {
+			settings[key] = value
+		}
+	}
+	secrets := make(map[string]struct{}, len(secretKeys))
+	for _, key := range secretKeys {
+		secrets["[settings]["+key+"]"] = struct{}{}
+		if settings[key] == definitions.RedactedValue {
+			settings[key] = current.Settings.Get(key).Interface()
+		}
+	}
+	type comparable struct {
+		Name                  string `json:"name"`
+		Type                  string `json:"type"`
+		DisableResolveMessage bool   `json:"disableResolveMessage"`
+		Settings              any    `json:"settings"`
+	}
+	diff.Changes, err = jsonChanges(
+		comparable{Name: current.Name, Type: current.Type, DisableResolveMessage: current.DisableResolveMessage, Settings: current.Settings},
+		comparable{Name: cp.Name, Type: cp.Type, DisableResolveMessage: cp.DisableResolveMessage, Settings: settings},
+		func(path string) bool {
+			_, ok := secrets[path]
+			return ok
+		})
+	if err != nil {
+		return definitions.ResourceDiff{}, err
+	}
+	return withUpdateAction(diff), nil
+}
+
+// diffRuleGroup returns the changes that replacing the rule group makes to each of its current and desired rules.
+func (svc *BundleService) diffRuleGroup(ctx context.Context, orgID int64, group models.AlertRuleGroup) ([]definitions.ResourceDiff, error) {
+	delta, err := svc.alertRules.calcDelta(ctx, orgID, group)
+	if err != nil {
+		return nil, err
+	}
+	diffs := make([]definitions.ResourceDiff, 0, len(group.Rules)+len(delta.Delete))
+	changed := make(map[string]struct{}, len(delta.Update))
+	for _, rule := range delta.New {
+		diffs = append(diffs, definitions.ResourceDiff{ResourceType: alertRuleResourceType, ResourceID: rule.UID, Name: rule.Title, Action: definitions.ResourceDiffCreate})
+		changed[rule.UID] = struct{}{}
+	}
+	for _, update := range delta.Update {
+		diff := definitions.ResourceDiff{ResourceType: alertRuleResourceType, ResourceID: update.Existing.UID, Name: update.New.Title, Action: definitions.ResourceDiffUpdate}
+		for _, d := range update.Diff {
+			diff.Changes = append(diff.Changes, definitions.ConfigChange{Path: d.Path, Before: reflectValue(d.Left), After: reflectValue(d.Right)})
+		}
+		diffs = append(diffs, diff)
+		changed[update.Existing.UID] = struct{}{}
+	}
+	for _, rule := range group.Rules {
+		if _, ok := changed[rule.UID]; ok || rule.UID == "" {
+			continue
+		}
+		diffs = append(diffs, definitions.ResourceDiff{ResourceType: alertRuleResourceType, ResourceID: rule.UID, Name: rule.Title, Action: definitions.ResourceDiffNoop})
+	}
+	for _, rule := range delta.Delete {
+		diffs = append(diffs, definitions.ResourceDiff{ResourceType: alertRuleResourceType, ResourceID: rule.UID, Name: rule.Title, Action: definitions.ResourceDiffDelete})
+	}
+	return diffs, nil
+}
+
+// withUpdateAction sets the action of a diff of an existing resource from its changes, unless it is already set.
+func withUpdateAction(diff definitions.ResourceDiff) definitions.ResourceDiff {
+	if diff.Action != "" {
+		return diff
+	}
+	diff.Action = definitions.ResourceDiffNoop
+	if len(diff.Changes) > 0 {
+		diff.Action = definitions.ResourceDiffUpdate
+	}
+	return diff
+}
+
+// jsonChanges compares the JSON representations of two values. Values at paths for which secret returns true are
+// redacted.
+func jsonChanges(before, after any, secret func(path string) bool) ([]definitions.ConfigChange, error) {
+	var left, right any
+	if err := remarshal(before, &left); err != nil {
+		return nil, err
+	}
+	if err := remarshal(after, &right); err != nil {
+		return nil, err
+	}
+	reporter := cmputil.DiffReporter{}
+	cmp.Equal(left, right, cmp.Reporter(&reporter))
+	changes := make([]definitions.ConfigChange, 0, len(reporter.Diffs))
+	for _, diff := range reporter.Diffs {
+		change := definitions.ConfigChange{Path: diff.Path, Before: reflectValue(diff.Left), After: reflectValue(diff.Right)}
+		if secret != nil && secret(diff.Path) {
+			if change.Before != nil {
+				change.Before = definitions.RedactedValue
+			}
+			if change.After != nil {
+				change.After = definitions.RedactedValue
+			}
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+func remarshal(value any, target *any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, target)
+}
+
+func reflectValue(v reflect.Value) any {
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
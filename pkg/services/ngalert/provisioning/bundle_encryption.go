@@ -0,0 +1,194 @@
+package provisioning
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/scrypt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// Encrypted bundles carry the secure settings of contact points, so they are encrypted with AES-256-GCM using either
+// a key derived from a passphrase with scrypt, or a random key that is encrypted with an RSA public key. The key
+// material never leaves the request, so that bundles can be moved between instances without any plaintext secrets.
+const (
+	encryptedBundleVersion = 1
+	bundleKeyLength        = 32
+	bundleSaltLength       = 16
+	// scrypt parameters recommended for interactive logins as of 2017.
+	bundleScryptN = 1 << 15
+	bundleScryptR = 8
+	bundleScryptP = 1
+)
+
+// ErrBundleDecryption is returned if an encrypted bundle cannot be decrypted with the given key, or was changed after
+// it was encrypted.
+var ErrBundleDecryption = newValidationError("", "the bundle cannot be decrypted with the given key")
+
+// ErrBundleKeyRequired is returned if a bundle is encrypted without a passphrase or public key, or with both.
+var ErrBundleKeyRequired = newValidationError("", "either a passphrase or a public key is required")
+
+// BundleKey is the user-supplied key material of an encrypted bundle. Exactly one of the fields must be set.
+type BundleKey struct {
+	Passphrase string
+	// PublicKey is a PEM encoded RSA public key, used to encrypt bundles.
+	PublicKey string
+	// PrivateKey is a PEM encoded RSA private key, used to decrypt bundles that were encrypted with its public key.
+	PrivateKey string
+}
+
+// EncryptBundle encrypts the serialized bundle with the passphrase or public key of the key.
+func EncryptBundle(plaintext []byte, key BundleKey) (definitions.EncryptedProvisioningBundle, error) {
+	if (key.Passphrase == "") == (key.PublicKey == "") {
+		return definitions.EncryptedProvisioningBundle{}, ErrBundleKeyRequired
+	}
+	result := definitions.EncryptedProvisioningBundle{Version: encryptedBundleVersion}
+	var dataKey []byte
+	if key.Passphrase != "" {
+		result.Mode = definitions.BundleEncryptionPassphrase
+		result.Salt = make([]byte, bundleSaltLength)
+		if _, err := io.ReadFull(rand.Reader, result.Salt); err != nil {
+			return definitions.EncryptedProvisioningBundle{}, err
+		}
+		var err error
+		if dataKey, err = scrypt.Key([]byte(key.Passphrase), result.Salt, bundleScryptN, bundleScryptR, bundleScryptP, bundleKeyLength); err != nil {
+			return definitions.EncryptedProvisioningBundle{}, err
+		}
+	} else {
+		publicKey, err := parseBundlePublicKey(key.PublicKey)
+		if err != nil {
+			return definitions.EncryptedProvisioningBundle{}, err
+		}
+		result.Mode = definitions.BundleEncryptionPublicKey
+		dataKey = make([]byte, bundleKeyLength)
+		if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+			return definitions.EncryptedProvisioningBundle{}, err
+		}
+		if result.EncryptedKey, err = rsa.EncryptOAEP(sha256.New(), rand.Reader, publicKey, dataKey, nil); err != nil {
+			return definitions.EncryptedProvisioningBundle{}, newValidationError("publicKey", "failed to encrypt with the public key: %s", err.Error())
+		}
+	}
+
+	gcm, err := newBundleCipher(dataKey)
+	if err != nil {
+		return definitions.EncryptedProvisioningBundle{}, err
+	}
+	result.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, result.Nonce); err != nil {
+		return definitions.EncryptedProvisioningBundle{}, err
+	}
+	result.Ciphertext = gcm.Seal(nil, result.Nonce, plaintext, bundleAdditionalData(result))
+	return result, nil
+}
+
+// DecryptBundle decrypts a bundle that was encrypted by EncryptBundle and returns the serialized bundle.
+func DecryptBundle(bundle definitions.EncryptedProvisioningBundle, key BundleKey) ([]byte, error) {
+	if bundle.Version != encryptedBundleVersion {
+		return nil, newValidationError("bundle.version", "unsupported version %d, expected %d", bundle.Version, encryptedBundleVersion)
+	}
+	var dataKey []byte
+	switch bundle.Mode {
+	case definitions.BundleEncryptionPassphrase:
+		if key.Passphrase == "" {
+			return nil, newValidationError("passphrase", "the bundle is encrypted with a passphrase")
+		}
+		var err error
+		if dataKey, err = scrypt.Key([]byte(key.Passphrase), bundle.Salt, bundleScryptN, bundleScryptR, bundleScryptP, bundleKeyLength); err != nil {
+			return nil, err
+		}
+	case definitions.BundleEncryptionPublicKey:
+		if key.PrivateKey == "" {
+			return nil, newValidationError("privateKey", "the bundle is encrypted with a public key")
+		}
+		privateKey, err := parseBundlePrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		if dataKey, err = rsa.DecryptOAEP(sha256.New(), rand.Reader, privateKey, bundle.EncryptedKey, nil); err != nil {
+			return nil, ErrBundleDecryption
+		}
+	default:
+		return nil, newValidationError("bundle.mode", "unsupported mode '%s'", bundle.Mode)
+	}
+
+	gcm, err := newBundleCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(bundle.Nonce) != gcm.NonceSize() {
+		return nil, newValidationError("bundle.nonce", "the nonce must be %d bytes long", gcm.NonceSize())
+	}
+	plaintext, err := gcm.Open(nil, bundle.Nonce, bundle.Ciphertext, bundleAdditionalData(bundle))
+	if err != nil {
+		return nil, ErrBundleDecryption
+	}
+	return plaintext, nil
+}
+
+func newBundleCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// bundleAdditionalData authenticates the fields of the bundle that are not encrypted, so that they cannot be changed
+// without failing the decryption.
+func bundleAdditionalData(bundle definitions.EncryptedProvisioningBundle) []byte {
+	return []byte(fmt.Sprintf("%d:%s", bundle.Version, bundle.Mode))
+}
+
+func parseBundlePublicKey(data string) (*rsa.PublicKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, newValidationError("publicKey", "the public key must be PEM encoded")
+	}
+	if block.Type == "RSA PUBLIC KEY" {
+		key, err := x509.ParsePKCS1PublicKey(block.Bytes)
+		if err != nil {
+			return nil, newValidationError("publicKey", "invalid public key: %s", err.Error())
+		}
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, newValidationError("publicKey", "invalid public key: %s", err.Error())
+	}
+	rsaKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, newValidationError("publicKey", "the public key must be an RSA key")
+	}
+	return rsaKey, nil
+}
+
+func parseBundlePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, newValidationError("privateKey", "the private key must be PEM encoded")
+	}
+	if block.Type == "RSA PRIVATE KEY" {
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, newValidationError("privateKey", "invalid private key: %s", err.Error())
+		}
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, newValidationError("privateKey", "invalid private key: %s", err.Error())
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, newValidationError("privateKey", "the private key must be an RSA key")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,86 @@
+package provisioning
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestEncryptBundle(t *testing.T) {
+	plaintext := []byte(`{"templates":[{"name":"team","template":"content"}]}`)
+
+	t.Run("bundle encrypted with a passphrase is decrypted with the passphrase", func(t *testing.T) {
+		encrypted, err := EncryptBundle(plaintext, BundleKey{Passphrase: "secret"})
+		require.NoError(t, err)
+		require.Equal(t, definitions.BundleEncryptionPassphrase, encrypted.Mode)
+		require.NotContains(t, string(encrypted.Ciphertext), "content")
+
+		decrypted, err := DecryptBundle(encrypted, BundleKey{Passphrase: "secret"})
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("bundle encrypted with a public key is decrypted with the private key", func(t *testing.T) {
+		privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		publicKeyBytes, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+		require.NoError(t, err)
+		publicKey := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicKeyBytes}))
+		privateKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(privateKey)}))
+
+		encrypted, err := EncryptBundle(plaintext, BundleKey{PublicKey: publicKey})
+		require.NoError(t, err)
+		require.Equal(t, definitions.BundleEncryptionPublicKey, encrypted.Mode)
+		require.NotEmpty(t, encrypted.EncryptedKey)
+
+		decrypted, err := DecryptBundle(encrypted, BundleKey{PrivateKey: privateKeyPEM})
+		require.NoError(t, err)
+		require.Equal(t, plaintext, decrypted)
+	})
+
+	t.Run("wrong passphrase is rejected", func(t *testing.T) {
+		encrypted, err := EncryptBundle(plaintext, BundleKey{Passphrase: "secret"})
+		require.NoError(t, err)
+
+		_, err = DecryptBundle(encrypted, BundleKey{Passphrase: "wrong"})
+		require.ErrorIs(t, err, ErrBundleDecryption)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("changed bundle is rejected", func(t *testing.T) {
+		encrypted, err := EncryptBundle(plaintext, BundleKey{Passphrase: "secret"})
+		require.NoError(t, err)
+		encrypted.Ciphertext[0] ^= 0xff
+
+		_, err = DecryptBundle(encrypted, BundleKey{Passphrase: "secret"})
+		require.ErrorIs(t, err, ErrBundleDecryption)
+	})
+
+	t.Run("key is required", func(t *testing.T) {
+		_, err := EncryptBundle(plaintext, BundleKey{})
+		require.ErrorIs(t, err, ErrBundleKeyRequired)
+
+		_, err = EncryptBundle(plaintext, BundleKey{Passphrase: "secret", PublicKey: "key"})
+		require.ErrorIs(t, err, ErrBundleKeyRequired)
+	})
+
+	t.Run("invalid public key is rejected", func(t *testing.T) {
+		_, err := EncryptBundle(plaintext, BundleKey{PublicKey: "not a key"})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("unsupported version is rejected", func(t *testing.T) {
+		encrypted, err := EncryptBundle(plaintext, BundleKey{Passphrase: "secret"})
+		require.NoError(t, err)
+		encrypted.Version = 2
+
+		_, err = DecryptBundle(encrypted, BundleKey{Passphrase: "secret"})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
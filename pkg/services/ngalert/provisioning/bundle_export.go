@@ -0,0 +1,58 @@
+package provisioning
+
+import (
+	"context"
+	"sort"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// ExportProvisioningBundle returns the full provisioning state of the organization as a bundle that can be applied to
+// another instance: all rule groups, contact points with decrypted secure settings, the notification policy tree,
+// templates and mute timings. The user must be allowed to read secrets, and the decryption of the contact points is
+// recorded in the audit log.
+func (svc *BundleService) ExportProvisioningBundle(ctx context.Context, orgID int64, u *user.SignedInUser) (_ ProvisioningBundle, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "bundle", "ExportProvisioningBundle", orgID)
+	defer func() { done(err) }()
+
+	contactPoints, err := svc.contactPoints.GetContactPoints(ctx, ContactPointQuery{OrgID: orgID, Decrypt: true}, u)
+	if err != nil {
+		return ProvisioningBundle{}, err
+	}
+	policies, err := svc.policies.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return ProvisioningBundle{}, err
+	}
+	muteTimings, err := svc.muteTimings.GetMuteTimings(ctx, orgID)
+	if err != nil {
+		return ProvisioningBundle{}, err
+	}
+	templates, err := svc.templates.GetTemplates(ctx, orgID)
+	if err != nil {
+		return ProvisioningBundle{}, err
+	}
+	groups, err := svc.alertRules.GetAlertGroupsWithFolderTitle(ctx, AlertRuleQuery{OrgID: orgID})
+	if err != nil {
+		return ProvisioningBundle{}, err
+	}
+
+	bundle := ProvisioningBundle{
+		ContactPoints: contactPoints,
+		Policies:      &policies,
+		MuteTimings:   muteTimings,
+		Templates:     make([]definitions.NotificationTemplate, 0, len(templates)),
+		RuleGroups:    make([]models.AlertRuleGroup, 0, len(groups)),
+	}
+	for name, tmpl := range templates {
+		bundle.Templates = append(bundle.Templates, definitions.NotificationTemplate{Name: name, Template: tmpl})
+	}
+	sort.Slice(bundle.Templates, func(i, j int) bool {
+		return bundle.Templates[i].Name < bundle.Templates[j].Name
+	})
+	for _, g := range groups {
+		bundle.RuleGroups = append(bundle.RuleGroups, *g.AlertRuleGroup)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,379 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const scheduledBundlesKey = "scheduled_bundles"
+
+// ScheduledBundlePollInterval is how often the scheduler looks for bundles that were scheduled by other instances.
+// Bundles that are known to the scheduler are applied at their time.
+const ScheduledBundlePollInterval = 10 * time.Second
+
+// provisioningBundleApplier applies provisioning bundles, see BundleService.
+type provisioningBundleApplier interface {
+	ApplyProvisioningBundle(ctx context.Context, orgID int64, bundle ProvisioningBundle, userID int64, provenance models.Provenance) (definitions.ProvisioningBundleResult, error)
+}
+
+// scheduledBundle is a scheduled provisioning bundle as it is stored. The bundle is encrypted, because contact points
+// can contain secrets.
+type scheduledBundle struct {
+	UID        string                                  `json:"uid"`
+	ApplyAt    time.Time                               `json:"applyAt"`
+	Location   string                                  `json:"location,omitempty"`
+	Status     string                                  `json:"status"`
+	Error      string                                  `json:"error,omitempty"`
+	CreatedAt  time.Time                               `json:"createdAt"`
+	UserID     int64                                   `json:"userId"`
+	Provenance models.Provenance                       `json:"provenance"`
+	Bundle     []byte                                  `json:"bundle"`
+	Summary    definitions.ScheduledProvisioningBundle `json:"summary"`
+}
+
+// BundleScheduler applies provisioning bundles at a later time, so that changes land at the start of a maintenance
+// window. The scheduled bundles are kept in the key-value store per organization until they are applied. Every bundle
+// is applied in one transaction together with its removal from the schedule. The due bundles of an organization
+// whose provisioning is frozen are applied when the freeze is lifted.
+type BundleScheduler struct {
+	bundles           provisioningBundleApplier
+	freeze            *ProvisioningFreezeService
+	kv                kvstore.KVStore
+	encryptionService secrets.Service
+	xact              TransactionManager
+	log               log.Logger
+	tracer            tracing.Tracer
+	metrics           *metrics.Provisioning
+	// mtx serializes the changes to the scheduled bundles of this instance.
+	mtx sync.Mutex
+}
+
+func NewBundleScheduler(bundles provisioningBundleApplier, freeze *ProvisioningFreezeService, kv kvstore.KVStore, encryptionService secrets.Service,
+	xact TransactionManager, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *BundleScheduler {
+	return &BundleScheduler{
+		bundles:           bundles,
+		freeze:            freeze,
+		kv:                kv,
+		encryptionService: encryptionService,
+		xact:              xact,
+		log:               log,
+		tracer:            tracer,
+		metrics:           m,
+	}
+}
+
+// ScheduleProvisioningBundle schedules the bundle to be applied at the given time with the permissions of the user
+// and the given provenance. If location is set, the date and time of applyAt are taken in that time zone.
+func (s *BundleScheduler) ScheduleProvisioningBundle(ctx context.Context, orgID int64, bundle ProvisioningBundle, applyAt time.Time,
+	location string, userID int64, provenance models.Provenance) (_ definitions.ScheduledProvisioningBundle, err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "ScheduleProvisioningBundle", orgID)
+	defer func() { done(err) }()
+
+	if location != "" {
+		loc, err := time.LoadLocation(location)
+		if err != nil {
+			return definitions.ScheduledProvisioningBundle{}, newValidationError("location", "unknown time zone '%s'", location)
+		}
+		applyAt = time.Date(applyAt.Year(), applyAt.Month(), applyAt.Day(), applyAt.Hour(), applyAt.Minute(), applyAt.Second(), applyAt.Nanosecond(), loc)
+	}
+	now := time.Now()
+	if !applyAt.After(now) {
+		return definitions.ScheduledProvisioningBundle{}, newValidationError("applyAt", "the bundle must be scheduled in the future")
+	}
+	if len(bundle.ContactPoints) == 0 && bundle.Policies == nil && len(bundle.MuteTimings) == 0 && len(bundle.Templates) == 0 && len(bundle.RuleGroups) == 0 {
+		return definitions.ScheduledProvisioningBundle{}, newValidationError("bundle", "the bundle has no resources")
+	}
+
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return definitions.ScheduledProvisioningBundle{}, err
+	}
+	encrypted, err := s.encryptionService.Encrypt(ctx, data, secrets.WithoutScope())
+	if err != nil {
+		return definitions.ScheduledProvisioningBundle{}, fmt.Errorf("failed to encrypt the bundle: %w", err)
+	}
+	scheduled := scheduledBundle{
+		UID:        util.GenerateShortUID(),
+		ApplyAt:    applyAt,
+		Location:   location,
+		Status:     definitions.ScheduledProvisioningBundlePending,
+		CreatedAt:  now,
+		UserID:     userID,
+		Provenance: provenance,
+		Bundle:     encrypted,
+		Summary: definitions.ScheduledProvisioningBundle{
+			ContactPoints: len(bundle.ContactPoints),
+			MuteTimings:   len(bundle.MuteTimings),
+			Templates:     len(bundle.Templates),
+			RuleGroups:    len(bundle.RuleGroups),
+			Policies:      bundle.Policies != nil,
+		},
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	bundles, err := s.getScheduledBundles(ctx, orgID)
+	if err != nil {
+		return definitions.ScheduledProvisioningBundle{}, err
+	}
+	bundles = append(bundles, scheduled)
+	if err := s.setScheduledBundles(ctx, orgID, bundles); err != nil {
+		return definitions.ScheduledProvisioningBundle{}, err
+	}
+	s.log.FromContext(ctx).Info("Scheduled provisioning bundle", "org", orgID, "uid", scheduled.UID, "applyAt", applyAt)
+	return scheduled.toApi(), nil
+}
+
+// GetScheduledProvisioningBundles returns the pending and failed scheduled bundles of the organization, ordered by the
+// time they are applied at.
+func (s *BundleScheduler) GetScheduledProvisioningBundles(ctx context.Context, orgID int64) (_ []definitions.ScheduledProvisioningBundle, err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "GetScheduledProvisioningBundles", orgID)
+	defer func() { done(err) }()
+	bundles, err := s.getScheduledBundles(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.ScheduledProvisioningBundle, 0, len(bundles))
+	for _, b := range bundles {
+		result = append(result, b.toApi())
+	}
+	return result, nil
+}
+
+// CancelScheduledProvisioningBundle removes the scheduled bundle with the given UID. Failed bundles are removed the
+// same way.
+func (s *BundleScheduler) CancelScheduledProvisioningBundle(ctx context.Context, orgID int64, uid string) (err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "CancelScheduledProvisioningBundle", orgID,
+		attribute.String("bundle_uid", uid))
+	defer func() { done(err) }()
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	bundles, err := s.getScheduledBundles(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for i, b := range bundles {
+		if b.UID != uid {
+			continue
+		}
+		bundles = append(bundles[:i], bundles[i+1:]...)
+		if err := s.setScheduledBundles(ctx, orgID, bundles); err != nil {
+			return err
+		}
+		s.log.FromContext(ctx).Info("Cancelled scheduled provisioning bundle", "org", orgID, "uid", uid)
+		return nil
+	}
+	return newNotFoundError("scheduledBundle", uid, "scheduled bundle '%s' does not exist", uid)
+}
+
+// ApplyDueProvisioningBundles applies the pending bundles of all organizations that are due at the given time, in
+// the order they were scheduled for. It returns when the next pending bundle is due, or the zero time if there is
+// none.
+func (s *BundleScheduler) ApplyDueProvisioningBundles(ctx context.Context, now time.Time) (time.Time, error) {
+	all, err := s.kv.GetAll(ctx, kvstore.AllOrganizations, fileProvisioningStatusNamespace)
+	if err != nil {
+		return time.Time{}, err
+	}
+	orgIDs := make([]int64, 0, len(all))
+	for orgID, values := range all {
+		if _, ok := values[scheduledBundlesKey]; ok {
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+
+	var next time.Time
+	var errs []error
+	for _, orgID := range orgIDs {
+		orgNext, err := s.applyDue(ctx, orgID, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("organization %d: %w", orgID, err))
+		}
+		if !orgNext.IsZero() && (next.IsZero() || orgNext.Before(next)) {
+			next = orgNext
+		}
+	}
+	return next, errors.Join(errs...)
+}
+
+// Run applies the scheduled bundles when they are due until the context is cancelled.
+func (s *BundleScheduler) Run(ctx context.Context) error {
+	for {
+		next, err := s.ApplyDueProvisioningBundles(ctx, time.Now())
+		if err != nil {
+			s.log.Error("Failed to apply scheduled provisioning bundles", "error", err)
+		}
+		wait := ScheduledBundlePollInterval
+		if !next.IsZero() && time.Until(next) < wait {
+			wait = time.Until(next)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(wait):
+		}
+	}
+}
+
+func (s *BundleScheduler) applyDue(ctx context.Context, orgID int64, now time.Time) (time.Time, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	bundles, err := s.getScheduledBundles(ctx, orgID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	frozen := false
+	if s.freeze != nil {
+		if frozen, err = s.freeze.IsFrozen(ctx, orgID); err != nil {
+			return time.Time{}, err
+		}
+	}
+	var next time.Time
+	for _, b := range bundles {
+		if b.Status != definitions.ScheduledProvisioningBundlePending {
+			continue
+		}
+		if b.ApplyAt.After(now) {
+			if next.IsZero() || b.ApplyAt.Before(next) {
+				next = b.ApplyAt
+			}
+			continue
+		}
+		if frozen {
+			continue
+		}
+		if err := s.apply(ctx, orgID, b.UID); err != nil {
+			return next, err
+		}
+	}
+	return next, nil
+}
+
+// apply applies the scheduled bundle and removes it from the schedule in one transaction. If applying the bundle
+// fails, it is kept with the error.
+func (s *BundleScheduler) apply(ctx context.Context, orgID int64, uid string) (err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "ApplyScheduledProvisioningBundle", orgID,
+		attribute.String("bundle_uid", uid))
+	defer func() { done(err) }()
+	logger := s.log.FromContext(ctx).New("org", orgID, "uid", uid)
+
+	var applyErr error
+	// The lock of the configuration is taken before the transaction, as applying the bundle takes it as well.
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		applyErr = nil
+		return s.xact.InTransaction(ctx, func(ctx context.Context) error {
+			bundles, err := s.getScheduledBundles(ctx, orgID)
+			if err != nil {
+				return err
+			}
+			idx := -1
+			for i, b := range bundles {
+				if b.UID == uid {
+					idx = i
+				}
+			}
+			if idx < 0 {
+				// The bundle was cancelled or applied by another instance.
+				return nil
+			}
+			scheduled := bundles[idx]
+			bundle, err := s.decryptBundle(ctx, scheduled)
+			if err == nil {
+				_, err = s.bundles.ApplyProvisioningBundle(ctx, orgID, bundle, scheduled.UserID, scheduled.Provenance)
+			}
+			if err != nil {
+				applyErr = err
+				return err
+			}
+			return s.setScheduledBundles(ctx, orgID, append(bundles[:idx], bundles[idx+1:]...))
+		})
+	})
+	if applyErr == nil {
+		if err == nil {
+			logger.Info("Applied scheduled provisioning bundle")
+		}
+		return err
+	}
+
+	logger.Warn("Failed to apply scheduled provisioning bundle", "error", applyErr)
+	bundles, err := s.getScheduledBundles(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	for i := range bundles {
+		if bundles[i].UID == uid {
+			bundles[i].Status = definitions.ScheduledProvisioningBundleFailed
+			bundles[i].Error = applyErr.Error()
+		}
+	}
+	return s.setScheduledBundles(ctx, orgID, bundles)
+}
+
+func (s *BundleScheduler) decryptBundle(ctx context.Context, scheduled scheduledBundle) (ProvisioningBundle, error) {
+	data, err := s.encryptionService.Decrypt(ctx, scheduled.Bundle)
+	if err != nil {
+		return ProvisioningBundle{}, fmt.Errorf("failed to decrypt the bundle: %w", err)
+	}
+	var bundle ProvisioningBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return ProvisioningBundle{}, fmt.Errorf("failed to unmarshal the bundle: %w", err)
+	}
+	return bundle, nil
+}
+
+func (b scheduledBundle) toApi() definitions.ScheduledProvisioningBundle {
+	result := b.Summary
+	result.UID = b.UID
+	result.ApplyAt = b.ApplyAt
+	if loc, err := time.LoadLocation(b.Location); err == nil && b.Location != "" {
+		result.ApplyAt = b.ApplyAt.In(loc)
+	}
+	result.Location = b.Location
+	result.Status = b.Status
+	result.Error = b.Error
+	result.CreatedAt = b.CreatedAt
+	result.Provenance = definitions.Provenance(b.Provenance)
+	return result
+}
+
+// getScheduledBundles returns the scheduled bundles of the organization ordered by the time they are applied at.
+func (s *BundleScheduler) getScheduledBundles(ctx context.Context, orgID int64) ([]scheduledBundle, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, scheduledBundlesKey)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var bundles []scheduledBundle
+	if err := json.Unmarshal([]byte(value), &bundles); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scheduled bundles: %w", err)
+	}
+	sort.SliceStable(bundles, func(i, j int) bool { return bundles[i].ApplyAt.Before(bundles[j].ApplyAt) })
+	return bundles, nil
+}
+
+func (s *BundleScheduler) setScheduledBundles(ctx context.Context, orgID int64, bundles []scheduledBundle) error {
+	if len(bundles) == 0 {
+		return s.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, scheduledBundlesKey)
+	}
+	data, err := json.Marshal(bundles)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, scheduledBundlesKey, string(data))
+}
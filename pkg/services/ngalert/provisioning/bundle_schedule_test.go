@@ -0,0 +1,149 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+type fakeBundleApplier struct {
+	err     error
+	applied []ProvisioningBundle
+}
+
+func (f *fakeBundleApplier) ApplyProvisioningBundle(_ context.Context, _ int64, bundle ProvisioningBundle, _ int64, _ models.Provenance) (definitions.ProvisioningBundleResult, error) {
+	if f.err != nil {
+		return definitions.ProvisioningBundleResult{}, f.err
+	}
+	f.applied = append(f.applied, bundle)
+	return definitions.ProvisioningBundleResult{}, nil
+}
+
+func TestBundleScheduler(t *testing.T) {
+	ctx := context.Background()
+	bundle := ProvisioningBundle{
+		Templates: []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+	}
+
+	t.Run("bundles are applied once they are due", func(t *testing.T) {
+		sut, applier := createBundleSchedulerSut()
+		applyAt := time.Now().Add(time.Hour)
+		scheduled, err := sut.ScheduleProvisioningBundle(ctx, 1, bundle, applyAt, "", 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		next, err := sut.ApplyDueProvisioningBundles(ctx, applyAt.Add(-time.Minute))
+		require.NoError(t, err)
+		require.True(t, applyAt.Equal(next))
+		require.Empty(t, applier.applied)
+
+		next, err = sut.ApplyDueProvisioningBundles(ctx, applyAt)
+		require.NoError(t, err)
+		require.True(t, next.IsZero())
+		require.Equal(t, []ProvisioningBundle{bundle}, applier.applied)
+		bundles, err := sut.GetScheduledProvisioningBundles(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, bundles)
+		require.ErrorIs(t, sut.CancelScheduledProvisioningBundle(ctx, 1, scheduled.UID), ErrNotFound)
+	})
+
+	t.Run("date and time are taken in the time zone of the location", func(t *testing.T) {
+		sut, _ := createBundleSchedulerSut()
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		year := time.Now().Year() + 1
+
+		// 22:00 UTC on a winter day is taken as 22:00 in Berlin, which is 21:00 UTC.
+		scheduled, err := sut.ScheduleProvisioningBundle(ctx, 1, bundle, time.Date(year, 1, 15, 22, 0, 0, 0, time.UTC), "Europe/Berlin", 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.True(t, time.Date(year, 1, 15, 21, 0, 0, 0, time.UTC).Equal(scheduled.ApplyAt))
+		require.Equal(t, time.Date(year, 1, 15, 22, 0, 0, 0, berlin).String(), scheduled.ApplyAt.String())
+		bundles, err := sut.GetScheduledProvisioningBundles(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, scheduled.ApplyAt.String(), bundles[0].ApplyAt.String())
+	})
+
+	t.Run("failed bundles are kept with the error", func(t *testing.T) {
+		sut, applier := createBundleSchedulerSut()
+		applier.err = errors.New("template is invalid")
+		applyAt := time.Now().Add(time.Hour)
+		scheduled, err := sut.ScheduleProvisioningBundle(ctx, 1, bundle, applyAt, "", 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.ApplyDueProvisioningBundles(ctx, applyAt)
+		require.NoError(t, err)
+
+		bundles, err := sut.GetScheduledProvisioningBundles(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, bundles, 1)
+		require.Equal(t, definitions.ScheduledProvisioningBundleFailed, bundles[0].Status)
+		require.Equal(t, "template is invalid", bundles[0].Error)
+		// Failed bundles are not retried.
+		applier.err = nil
+		_, err = sut.ApplyDueProvisioningBundles(ctx, applyAt.Add(time.Hour))
+		require.NoError(t, err)
+		require.Empty(t, applier.applied)
+		require.NoError(t, sut.CancelScheduledProvisioningBundle(ctx, 1, scheduled.UID))
+	})
+
+	t.Run("cancelled bundles are not applied", func(t *testing.T) {
+		sut, applier := createBundleSchedulerSut()
+		applyAt := time.Now().Add(time.Hour)
+		scheduled, err := sut.ScheduleProvisioningBundle(ctx, 1, bundle, applyAt, "", 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.CancelScheduledProvisioningBundle(ctx, 1, scheduled.UID))
+		_, err = sut.ApplyDueProvisioningBundles(ctx, applyAt)
+		require.NoError(t, err)
+
+		require.Empty(t, applier.applied)
+	})
+
+	t.Run("due bundles wait while provisioning is frozen", func(t *testing.T) {
+		sut, applier := createBundleSchedulerSut()
+		sut.freeze = createProvisioningFreezeServiceSut(nil, nil)
+		applyAt := time.Now().Add(time.Hour)
+		_, err := sut.ScheduleProvisioningBundle(ctx, 1, bundle, applyAt, "", 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, err = sut.freeze.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: true})
+		require.NoError(t, err)
+
+		_, err = sut.ApplyDueProvisioningBundles(ctx, applyAt)
+		require.NoError(t, err)
+		require.Empty(t, applier.applied)
+
+		_, err = sut.freeze.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: false})
+		require.NoError(t, err)
+		_, err = sut.ApplyDueProvisioningBundles(ctx, applyAt.Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, []ProvisioningBundle{bundle}, applier.applied)
+	})
+
+	t.Run("invalid schedules are rejected", func(t *testing.T) {
+		sut, _ := createBundleSchedulerSut()
+		now := time.Now()
+
+		_, err := sut.ScheduleProvisioningBundle(ctx, 1, bundle, now.Add(-time.Minute), "", 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = sut.ScheduleProvisioningBundle(ctx, 1, bundle, now.Add(time.Hour), "Mars/Olympus_Mons", 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = sut.ScheduleProvisioningBundle(ctx, 1, ProvisioningBundle{}, now.Add(time.Hour), "", 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createBundleSchedulerSut() (*BundleScheduler, *fakeBundleApplier) {
+	applier := &fakeBundleApplier{}
+	return NewBundleScheduler(applier, nil, kvstore.NewFakeKVStore(), fakes.NewFakeSecretsService(), newNopTransactionManager(),
+		log.NewNopLogger(), tracing.InitializeTracerForTest(), nil), applier
+}
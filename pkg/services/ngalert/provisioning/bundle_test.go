@@ -0,0 +1,325 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/user"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestApplyProvisioningBundle(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("resources are applied together", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		cp.Name = "team-a"
+
+		result, err := sut.ApplyProvisioningBundle(ctx, 1, ProvisioningBundle{
+			ContactPoints: []definitions.EmbeddedContactPoint{cp},
+			Policies: &definitions.Route{
+				Receiver: "team-a",
+				Routes:   []*definitions.Route{{Receiver: "team-a", MuteTimeIntervals: []string{"weekends"}}},
+			},
+			MuteTimings: []definitions.MuteTimeInterval{{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}},
+			Templates:   []definitions.NotificationTemplate{{Name: "team", Template: `{{ define "team" }}{{ end }}`}},
+		}, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Len(t, result.ContactPoints, 1)
+		require.Equal(t, []string{"weekends"}, result.MuteTimings)
+		require.Equal(t, []string{"team"}, result.Templates)
+		require.True(t, result.Policies)
+		tree, err := sut.policies.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, "team-a", tree.Receiver)
+		require.Equal(t, definitions.Provenance(models.ProvenanceAPI), tree.Provenance)
+	})
+
+	t.Run("existing contact points and mute timings are updated", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+		cp, err := sut.contactPoints.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, err = sut.muteTimings.CreateMuteTiming(ctx, definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}, 1)
+		require.NoError(t, err)
+		cp.Settings.Set("recipient", "updated")
+
+		result, err := sut.ApplyProvisioningBundle(ctx, 1, ProvisioningBundle{
+			ContactPoints: []definitions.EmbeddedContactPoint{cp},
+			MuteTimings:   []definitions.MuteTimeInterval{{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}},
+		}, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{cp.UID}, result.ContactPoints)
+		q := cpsQuery(1)
+		q.Name = cp.Name
+		cps, err := sut.contactPoints.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "updated", cps[0].Settings.Get("recipient").MustString())
+		muteTimings, err := sut.muteTimings.GetMuteTimings(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, muteTimings, 1)
+	})
+
+	t.Run("nothing is applied if a resource is rejected", func(t *testing.T) {
+		sut, amStore := createBundleServiceSut(t, secretsService)
+		before := amStore.config.AlertmanagerConfiguration
+
+		_, err := sut.ApplyProvisioningBundle(ctx, 1, ProvisioningBundle{
+			MuteTimings: []definitions.MuteTimeInterval{{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}},
+			Templates:   []definitions.NotificationTemplate{{Name: "team", Template: `{{ define "team" }}{{ end }}`}},
+			Policies:    &definitions.Route{Receiver: "unknown"},
+		}, 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "notification policies")
+
+		require.Equal(t, before, amStore.config.AlertmanagerConfiguration)
+		templates, err := sut.templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, templates)
+	})
+}
+
+func TestDiffProvisioningBundle(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("new resources are created", func(t *testing.T) {
+		sut, amStore := createBundleServiceSut(t, secretsService)
+		before := amStore.config.AlertmanagerConfiguration
+
+		result, err := sut.DiffProvisioningBundle(ctx, 1, ProvisioningBundle{
+			ContactPoints: []definitions.EmbeddedContactPoint{createTestContactPoint()},
+			MuteTimings:   []definitions.MuteTimeInterval{{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}},
+			Templates:     []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, result.Resources, 3)
+		for _, diff := range result.Resources {
+			require.Equal(t, definitions.ResourceDiffCreate, diff.Action)
+		}
+		require.Equal(t, before, amStore.config.AlertmanagerConfiguration)
+	})
+
+	t.Run("changed fields of existing resources are returned with secrets redacted", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		cp.Type = "slack"
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "value", "token": "secret"})
+		cp, err := sut.contactPoints.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, err = sut.templates.SetTemplate(ctx, 1, definitions.NotificationTemplate{Name: "team", Template: "content"})
+		require.NoError(t, err)
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "updated", "token": "rotated"})
+
+		result, err := sut.DiffProvisioningBundle(ctx, 1, ProvisioningBundle{
+			ContactPoints: []definitions.EmbeddedContactPoint{cp},
+			Templates:     []definitions.NotificationTemplate{{Name: "team", Template: "content"}},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, result.Resources, 2)
+		require.Equal(t, definitions.ResourceDiffNoop, result.Resources[0].Action)
+		require.Equal(t, definitions.ResourceDiffUpdate, result.Resources[1].Action)
+		require.ElementsMatch(t, []definitions.ConfigChange{
+			{Path: "[settings][recipient]", Before: "value", After: "updated"},
+			{Path: "[settings][token]", Before: definitions.RedactedValue, After: definitions.RedactedValue},
+		}, result.Resources[1].Changes)
+	})
+
+	t.Run("redacted secrets are unchanged", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		cp.Type = "slack"
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "value", "token": "secret"})
+		cp, err := sut.contactPoints.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "value", "token": definitions.RedactedValue})
+
+		result, err := sut.DiffProvisioningBundle(ctx, 1, ProvisioningBundle{ContactPoints: []definitions.EmbeddedContactPoint{cp}})
+		require.NoError(t, err)
+
+		require.Len(t, result.Resources, 1)
+		require.Equal(t, definitions.ResourceDiffNoop, result.Resources[0].Action)
+	})
+
+	t.Run("notification policy changes are returned", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+		tree, err := sut.policies.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		tree.GroupByStr = []string{"alertname"}
+
+		result, err := sut.DiffProvisioningBundle(ctx, 1, ProvisioningBundle{Policies: &tree})
+		require.NoError(t, err)
+
+		require.Len(t, result.Resources, 1)
+		require.Equal(t, definitions.ResourceDiffUpdate, result.Resources[0].Action)
+		require.NotEmpty(t, result.Resources[0].Changes)
+	})
+
+	t.Run("rule changes are returned per rule", func(t *testing.T) {
+		ruleService := createAlertRuleService(t)
+		sut := NewBundleService(nil, nil, nil, nil, &ruleService, nil, log.NewNopLogger(), tracing.InitializeTracerForTest(), nil)
+		group := createDummyGroup("group", 1)
+		group.Rules = append(group.Rules, dummyRule("group-rule-2", 1))
+		require.NoError(t, ruleService.ReplaceRuleGroup(ctx, 1, group, 0, models.ProvenanceAPI))
+		stored, err := ruleService.GetRuleGroup(ctx, 1, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.Len(t, stored.Rules, 2)
+		updated := stored.Rules[0]
+		updated.Title = "renamed"
+		desired := stored
+		desired.Rules = []models.AlertRule{updated, dummyRule("group-rule-3", 1)}
+
+		result, err := sut.DiffProvisioningBundle(ctx, 1, ProvisioningBundle{RuleGroups: []models.AlertRuleGroup{desired}})
+		require.NoError(t, err)
+
+		actions := map[definitions.ResourceDiffAction][]string{}
+		for _, diff := range result.Resources {
+			actions[diff.Action] = append(actions[diff.Action], diff.Name)
+		}
+		require.Equal(t, map[definitions.ResourceDiffAction][]string{
+			definitions.ResourceDiffCreate: {"group-rule-3"},
+			definitions.ResourceDiffUpdate: {"renamed"},
+			definitions.ResourceDiffDelete: {stored.Rules[1].Title},
+		}, actions)
+		stored, err = ruleService.GetRuleGroup(ctx, 1, group.FolderUID, group.Title)
+		require.NoError(t, err)
+		require.Len(t, stored.Rules, 2)
+	})
+}
+
+// rollbackTransactionManager restores the configuration of a fakeAMConfigStore if the outermost transaction fails.
+type rollbackTransactionManager struct {
+	store *fakeAMConfigStore
+	depth int
+}
+
+func (m *rollbackTransactionManager) InTransaction(ctx context.Context, work func(ctx context.Context) error) error {
+	saved := m.store.config
+	m.depth++
+	err := work(ctx)
+	m.depth--
+	if err != nil && m.depth == 0 {
+		m.store.config = saved
+	}
+	return err
+}
+
+func TestCopyAlertingConfig(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("selected resources are copied", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+		sut.contactPoints.ac = actest.FakeAccessControl{ExpectedEvaluate: true}
+		cp := createTestContactPoint()
+		cp.Name = "team-a"
+		cp, err := sut.contactPoints.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		_, err = sut.muteTimings.CreateMuteTiming(ctx, definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}, 1)
+		require.NoError(t, err)
+		_, err = sut.templates.SetTemplate(ctx, 1, definitions.NotificationTemplate{Name: "team", Template: `{{ define "team" }}{{ end }}`})
+		require.NoError(t, err)
+
+		result, err := sut.CopyAlertingConfig(ctx, 1, 2, CopyAlertingConfigOptions{
+			ContactPoints: []string{"team-a"},
+			MuteTimings:   []string{"weekends"},
+			Templates:     []string{"team"},
+			User:          &user.SignedInUser{OrgID: 1, UserID: 1},
+			Provenance:    models.ProvenanceAPI,
+		})
+		require.NoError(t, err)
+
+		require.Equal(t, []string{cp.UID}, result.ContactPoints)
+		require.Equal(t, []string{"weekends"}, result.MuteTimings)
+		require.Equal(t, []string{"team"}, result.Templates)
+		require.False(t, result.Policies)
+	})
+
+	t.Run("copy into the same organization is rejected", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+
+		_, err := sut.CopyAlertingConfig(ctx, 1, 1, CopyAlertingConfigOptions{Templates: []string{"team"}})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("missing resource is rejected", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+
+		_, err := sut.CopyAlertingConfig(ctx, 1, 2, CopyAlertingConfigOptions{MuteTimings: []string{"missing"}})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("contact points are not copied without the permission to read secrets", func(t *testing.T) {
+		sut, _ := createBundleServiceSut(t, secretsService)
+
+		_, err := sut.CopyAlertingConfig(ctx, 1, 2, CopyAlertingConfigOptions{
+			ContactPoints: []string{"email receiver"},
+			User:          &user.SignedInUser{OrgID: 1, UserID: 1},
+		})
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+}
+
+func TestRemapRuleGroup(t *testing.T) {
+	rule := createTestRule("rule", "group", 1, "source-folder")
+	rule.UID = "rule-uid"
+	rule.ID = 10
+	rule.Data = append(rule.Data, models.AlertQuery{
+		RefID:         "B",
+		DatasourceUID: "source-ds",
+		Model:         json.RawMessage(`{"datasource":{"type":"prometheus","uid":"source-ds"},"expr":"up","intervalMs":1000}`),
+	})
+	group := models.AlertRuleGroup{Title: "group", FolderUID: "source-folder", Interval: 60, Rules: []models.AlertRule{rule}}
+
+	err := remapRuleGroup(&group, 2, CopyAlertingConfigOptions{
+		FolderUIDs:     map[string]string{"source-folder": "target-folder"},
+		DatasourceUIDs: map[string]string{"source-ds": "target-ds"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "target-folder", group.FolderUID)
+	copied := group.Rules[0]
+	require.Empty(t, copied.UID)
+	require.Zero(t, copied.ID)
+	require.Equal(t, int64(2), copied.OrgID)
+	require.Equal(t, "target-folder", copied.NamespaceUID)
+	require.Equal(t, rule.Data[0], copied.Data[0], "expressions are not mapped")
+	require.Equal(t, "target-ds", copied.Data[1].DatasourceUID)
+	require.JSONEq(t, `{"datasource":{"type":"prometheus","uid":"target-ds"},"expr":"up","intervalMs":1000}`, string(copied.Data[1].Model))
+	require.Equal(t, "rule-uid", rule.UID, "the source rule is unchanged")
+}
+
+func createBundleServiceSut(t *testing.T, secretsService *manager.SecretsService) (*BundleService, *fakeAMConfigStore) {
+	contactPoints := createContactPointServiceSut(t, secretsService)
+	amStore := contactPoints.amStore.(*fakeAMConfigStore)
+	logger := log.NewNopLogger()
+	tracer := tracing.InitializeTracerForTest()
+	policies := NewNotificationPolicyService(contactPoints.amStore, contactPoints.provenanceStore, contactPoints.xact, nil,
+		setting.UnifiedAlertingSettings{DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration()}, logger, tracer, nil)
+	muteTimings := NewMuteTimingService(contactPoints.amStore, contactPoints.provenanceStore, contactPoints.ruleStore, contactPoints.xact, nil, logger, tracer, nil)
+	templates := NewTemplateService(contactPoints.amStore, contactPoints.provenanceStore, contactPoints.xact, nil, logger, tracer, nil)
+	xact := &rollbackTransactionManager{store: amStore}
+	return NewBundleService(contactPoints, policies, muteTimings, templates, nil, xact, logger, tracer, nil), amStore
+}
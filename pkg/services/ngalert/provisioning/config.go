@@ -4,17 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
-func deserializeAlertmanagerConfig(config []byte) (*definitions.PostableUserConfig, error) {
-	result := definitions.PostableUserConfig{}
-	if err := json.Unmarshal(config, &result); err != nil {
+func deserializeAlertmanagerConfig(config string) (*definitions.PostableUserConfig, error) {
+	result, err := definitions.DecodePostableUserConfig(strings.NewReader(config))
+	if err != nil {
 		return nil, fmt.Errorf("failed to deserialize alertmanager configuration: %w", err)
 	}
-	return &result, nil
+	return result, nil
 }
 
 func serializeAlertmanagerConfig(config definitions.PostableUserConfig) ([]byte, error) {
@@ -25,6 +26,15 @@ type cfgRevision struct {
 	cfg              *definitions.PostableUserConfig
 	concurrencyToken string
 	version          string
+	receiverIdx      *receiverIndex
+}
+
+// receivers returns the receiver index of the revision's configuration. The index is built on first use.
+func (r *cfgRevision) receivers() *receiverIndex {
+	if r.receiverIdx == nil {
+		r.receiverIdx = newReceiverIndex(r.cfg)
+	}
+	return r.receiverIdx
 }
 
 func getLastConfiguration(ctx context.Context, orgID int64, store AMConfigStore) (*cfgRevision, error) {
@@ -41,7 +51,7 @@ func getLastConfiguration(ctx context.Context, orgID int64, store AMConfigStore)
 	}
 
 	concurrencyToken := alertManagerConfig.ConfigurationHash
-	cfg, err := deserializeAlertmanagerConfig([]byte(alertManagerConfig.AlertmanagerConfiguration))
+	cfg, err := deserializeAlertmanagerConfig(alertManagerConfig.AlertmanagerConfiguration)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,256 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// alertmanagerConfigResourceType is the resource type of the audit entries of rollbacks of the Alertmanager
+// configuration.
+const alertmanagerConfigResourceType = "alertmanagerConfiguration"
+
+// ConfigHistoryStore reads the saved versions of the Alertmanager configuration of the organizations.
+type ConfigHistoryStore interface {
+	GetAlertmanagerConfigurationHistory(ctx context.Context, orgID int64, limit int) ([]*models.HistoricAlertConfiguration, error)
+	GetHistoricalConfiguration(ctx context.Context, orgID int64, id int64) (*models.HistoricAlertConfiguration, error)
+}
+
+// configResourceTypes are the types of provisioned resources that are part of the Alertmanager configuration.
+var configResourceTypes = []string{
+	(&definitions.EmbeddedContactPoint{}).ResourceType(),
+	(&definitions.NotificationTemplate{}).ResourceType(),
+	(&definitions.MuteTimeInterval{}).ResourceType(),
+	(&definitions.Route{}).ResourceType(),
+}
+
+// ConfigHistoryService lists the saved versions of the Alertmanager configuration of an organization and rolls the
+// configuration back to one of them, for example to revert a bad provisioning push.
+type ConfigHistoryService struct {
+	history           ConfigHistoryStore
+	amStore           AMConfigStore
+	provenanceStore   ProvisioningStore
+	xact              TransactionManager
+	encryptionService secrets.Service
+	log               log.Logger
+	tracer            tracing.Tracer
+	metrics           *metrics.Provisioning
+}
+
+func NewConfigHistoryService(history ConfigHistoryStore, amStore AMConfigStore, provenanceStore ProvisioningStore, xact TransactionManager,
+	encryptionService secrets.Service, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *ConfigHistoryService {
+	return &ConfigHistoryService{
+		history:           history,
+		amStore:           newTracedAMConfigStore(amStore, tracer, log, m),
+		provenanceStore:   provenanceStore,
+		xact:              xact,
+		encryptionService: newTracedSecretsService(encryptionService, tracer),
+		log:               log,
+		tracer:            tracer,
+		metrics:           m,
+	}
+}
+
+// ListAlertmanagerConfigVersions returns the saved versions of the Alertmanager configuration of the organization,
+// most recent first. Only the most recent store.ConfigRecordsLimit versions are kept.
+func (svc *ConfigHistoryService) ListAlertmanagerConfigVersions(ctx context.Context, orgID int64) (_ []definitions.AlertmanagerConfigVersion, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "config", "ListAlertmanagerConfigVersions", orgID)
+	defer func() { done(err) }()
+
+	configs, err := svc.history.GetAlertmanagerConfigurationHistory(ctx, orgID, store.ConfigRecordsLimit)
+	if err != nil {
+		return nil, err
+	}
+	current, err := svc.currentHash(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.AlertmanagerConfigVersion, 0, len(configs))
+	for i, cfg := range configs {
+		// Older versions can have the same content as the current one, only the most recent of them is current.
+		result = append(result, configVersionModel(cfg, i == 0 && cfg.ConfigurationHash == current))
+	}
+	return result, nil
+}
+
+// RollbackAlertmanagerConfig replaces the Alertmanager configuration of the organization with a previously saved
+// version. The receivers of the version are validated again, as they may rely on integrations or secrets that
+// changed since. The provenance of the resources that are part of the version is kept, while the provenance of the
+// resources that are not is removed. The rollback is saved as a new version and recorded in the provisioning audit
+// log. It returns the new version, or the given version if it is the current configuration already. It returns
+// ErrNotFound if the organization has no such version, and ErrValidation if a receiver of the version is invalid.
+func (svc *ConfigHistoryService) RollbackAlertmanagerConfig(ctx context.Context, orgID int64, configID int64) (result definitions.AlertmanagerConfigVersion, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.rollbackAlertmanagerConfig(ctx, orgID, configID)
+		return err
+	})
+	return result, err
+}
+
+func (svc *ConfigHistoryService) rollbackAlertmanagerConfig(ctx context.Context, orgID int64, configID int64) (_ definitions.AlertmanagerConfigVersion, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "config", "RollbackAlertmanagerConfig", orgID,
+		attribute.Int64("configId", configID))
+	defer func() { done(err) }()
+
+	version, err := svc.history.GetHistoricalConfiguration(ctx, orgID, configID)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return definitions.AlertmanagerConfigVersion{}, newNotFoundError("alertmanagerConfigVersion", strconv.FormatInt(configID, 10), "configuration version %d does not exist", configID)
+	}
+	if err != nil {
+		return definitions.AlertmanagerConfigVersion{}, err
+	}
+	cfg, err := deserializeAlertmanagerConfig(version.AlertmanagerConfiguration)
+	if err != nil {
+		return definitions.AlertmanagerConfigVersion{}, fmt.Errorf("%w: configuration version %d is invalid: %s", ErrValidation, configID, err.Error())
+	}
+	if err := svc.validateReceivers(ctx, cfg); err != nil {
+		return definitions.AlertmanagerConfigVersion{}, err
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+	if err != nil {
+		return definitions.AlertmanagerConfigVersion{}, err
+	}
+	if revision.concurrencyToken == version.ConfigurationHash {
+		// The version is the current configuration already.
+		return configVersionModel(version, true), nil
+	}
+	cmd := &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: version.AlertmanagerConfiguration,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		ConfigurationVersion:      revision.version,
+		Default:                   false,
+		OrgID:                     orgID,
+	}
+	target := provisionedResource{resourceType: alertmanagerConfigResourceType, id: strconv.FormatInt(configID, 10)}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := PersistConfig(ctx, svc.amStore, cmd); err != nil {
+			return err
+		}
+		if err := svc.pruneProvenances(ctx, orgID, cfg); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.provenanceStore, orgID, models.ProvisioningAuditActionRestore, target, models.ProvenanceNone, nil, configVersionModel(version, false))
+	})
+	if err != nil {
+		return definitions.AlertmanagerConfigVersion{}, err
+	}
+	svc.log.FromContext(ctx).Info("Rolled back Alertmanager configuration", "org", orgID, "version", configID)
+
+	saved, err := svc.history.GetAlertmanagerConfigurationHistory(ctx, orgID, 1)
+	if err != nil {
+		return definitions.AlertmanagerConfigVersion{}, err
+	}
+	if len(saved) == 0 {
+		return definitions.AlertmanagerConfigVersion{}, fmt.Errorf("the rolled back configuration was not saved")
+	}
+	return configVersionModel(saved[0], true), nil
+}
+
+// validateReceivers checks that every integration of the configuration can be built.
+func (svc *ConfigHistoryService) validateReceivers(ctx context.Context, cfg *definitions.PostableUserConfig) error {
+	for _, r := range newReceiverIndex(cfg).all() {
+		integration := alertingNotify.GrafanaIntegrationConfig{
+			UID:                   r.UID,
+			Name:                  r.Name,
+			Type:                  r.Type,
+			DisableResolveMessage: r.DisableResolveMessage,
+			Settings:              json.RawMessage(r.Settings),
+			SecureSettings:        r.SecureSettings,
+		}
+		_, err := alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{&integration},
+			},
+		}, svc.encryptionService.GetDecryptedValue)
+		if err != nil {
+			return fmt.Errorf("%w: integration %q of contact point %q is invalid: %s", ErrValidation, r.UID, r.Name, err.Error())
+		}
+	}
+	return nil
+}
+
+// pruneProvenances removes the provenance of the resources of the organization that are not part of the
+// configuration, so that they can be created again without being locked by a stale provenance.
+func (svc *ConfigHistoryService) pruneProvenances(ctx context.Context, orgID int64, cfg *definitions.PostableUserConfig) error {
+	present := configResourceIDs(cfg)
+	for _, resourceType := range configResourceTypes {
+		current, err := svc.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+		if err != nil {
+			return err
+		}
+		for id := range current {
+			if _, ok := present[resourceType][id]; ok {
+				continue
+			}
+			if err := svc.provenanceStore.DeleteProvenance(ctx, provisionedResource{resourceType: resourceType, id: id}, orgID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (svc *ConfigHistoryService) currentHash(ctx context.Context, orgID int64) (string, error) {
+	cfg, err := svc.amStore.GetLatestAlertmanagerConfiguration(ctx, &models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID})
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return cfg.ConfigurationHash, nil
+}
+
+// configResourceIDs returns the IDs of the provisionable resources of the configuration by resource type.
+func configResourceIDs(cfg *definitions.PostableUserConfig) map[string]map[string]struct{} {
+	ids := make(map[string]map[string]struct{}, len(configResourceTypes))
+	for _, resourceType := range configResourceTypes {
+		ids[resourceType] = make(map[string]struct{})
+	}
+	for _, r := range newReceiverIndex(cfg).all() {
+		ids[(&definitions.EmbeddedContactPoint{}).ResourceType()][r.UID] = struct{}{}
+	}
+	for name := range cfg.TemplateFiles {
+		ids[(&definitions.NotificationTemplate{}).ResourceType()][name] = struct{}{}
+	}
+	for _, mt := range cfg.AlertmanagerConfig.MuteTimeIntervals {
+		ids[(&definitions.MuteTimeInterval{}).ResourceType()][mt.Name] = struct{}{}
+	}
+	if cfg.AlertmanagerConfig.Route != nil {
+		ids[(&definitions.Route{}).ResourceType()][(&definitions.Route{}).ResourceID()] = struct{}{}
+		walkRoutes(cfg.AlertmanagerConfig.Route, func(r *definitions.Route) {
+			if r.UID != "" {
+				ids[r.ResourceType()][r.UID] = struct{}{}
+			}
+		})
+	}
+	return ids
+}
+
+func configVersionModel(cfg *models.HistoricAlertConfiguration, current bool) definitions.AlertmanagerConfigVersion {
+	v := definitions.AlertmanagerConfigVersion{
+		ID:      cfg.ID,
+		Created: time.Unix(cfg.CreatedAt, 0).UTC(),
+		Current: current,
+	}
+	if cfg.LastApplied != 0 {
+		lastApplied := time.Unix(cfg.LastApplied, 0).UTC()
+		v.LastApplied = &lastApplied
+	}
+	return v
+}
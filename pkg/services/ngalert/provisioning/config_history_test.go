@@ -0,0 +1,128 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	secrets_fakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestConfigHistoryService(t *testing.T) {
+	ctx := context.Background()
+	secretsService := secrets_fakes.NewFakeSecretsService()
+
+	t.Run("rolls back to a previous version and keeps the provenance of its resources", func(t *testing.T) {
+		sut, dbstore := createConfigHistoryServiceSut(t, secretsService)
+		previous := encryptedTestConfig(t, secretsService, nil)
+		saveTestConfig(t, dbstore, previous)
+		slack := &definitions.EmbeddedContactPoint{UID: "slack-uid"}
+		require.NoError(t, dbstore.SetProvenance(ctx, slack, 1, models.ProvenanceAPI))
+		saveTestConfig(t, dbstore, encryptedTestConfig(t, secretsService, map[string]string{"added": "{{ define \"added\" }}{{ end }}"}))
+		added := &definitions.NotificationTemplate{Name: "added"}
+		require.NoError(t, dbstore.SetProvenance(ctx, added, 1, models.ProvenanceAPI))
+
+		versions, err := sut.ListAlertmanagerConfigVersions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, versions, 2)
+		require.True(t, versions[0].Current)
+		require.False(t, versions[1].Current)
+
+		rolledBack, err := sut.RollbackAlertmanagerConfig(ctx, 1, versions[1].ID)
+		require.NoError(t, err)
+		require.True(t, rolledBack.Current)
+
+		cfg, err := dbstore.GetLatestAlertmanagerConfiguration(ctx, &models.GetLatestAlertmanagerConfigurationQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, previous, cfg.AlertmanagerConfiguration)
+		provenance, err := dbstore.GetProvenance(ctx, slack, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		provenance, err = dbstore.GetProvenance(ctx, added, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, provenance)
+
+		versions, err = sut.ListAlertmanagerConfigVersions(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		require.Equal(t, rolledBack.ID, versions[0].ID)
+		require.True(t, versions[0].Current)
+		require.False(t, versions[2].Current)
+
+		entries, err := dbstore.GetProvisioningAuditEntries(ctx, models.ProvisioningAuditQuery{OrgID: 1, ResourceType: alertmanagerConfigResourceType})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, models.ProvisioningAuditActionRestore, entries[0].Action)
+	})
+
+	t.Run("fails if the version does not exist", func(t *testing.T) {
+		sut, dbstore := createConfigHistoryServiceSut(t, secretsService)
+		saveTestConfig(t, dbstore, encryptedTestConfig(t, secretsService, nil))
+
+		_, err := sut.RollbackAlertmanagerConfig(ctx, 1, 1000)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("fails if a receiver of the version is invalid", func(t *testing.T) {
+		sut, dbstore := createConfigHistoryServiceSut(t, secretsService)
+		// The secure settings of the default configuration are not encrypted, so they cannot be decrypted on load.
+		saveTestConfig(t, dbstore, defaultAlertmanagerConfigJSON)
+		saveTestConfig(t, dbstore, encryptedTestConfig(t, secretsService, nil))
+		versions, err := sut.ListAlertmanagerConfigVersions(ctx, 1)
+		require.NoError(t, err)
+
+		_, err = sut.RollbackAlertmanagerConfig(ctx, 1, versions[1].ID)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+// encryptedTestConfig returns the default configuration with the given templates, with UIDs for its receivers and
+// their secure settings encrypted.
+func encryptedTestConfig(t *testing.T, secretsService secrets.Service, templates map[string]string) string {
+	t.Helper()
+	c := &definitions.PostableUserConfig{}
+	require.NoError(t, json.Unmarshal([]byte(defaultAlertmanagerConfigJSON), c))
+	c.TemplateFiles = templates
+	c.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers[0].UID = "email-uid"
+	c.AlertmanagerConfig.Receivers[1].GrafanaManagedReceivers[0].UID = "slack-uid"
+	require.NoError(t, notifier.EncryptReceiverConfigs(c.AlertmanagerConfig.Receivers, func(ctx context.Context, payload []byte) ([]byte, error) {
+		return secretsService.Encrypt(ctx, payload, secrets.WithoutScope())
+	}))
+	raw, err := json.Marshal(c)
+	require.NoError(t, err)
+	return string(raw)
+}
+
+func saveTestConfig(t *testing.T, dbstore *store.DBstore, cfg string) {
+	t.Helper()
+	require.NoError(t, dbstore.SaveAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: cfg,
+		ConfigurationVersion:      "v1",
+		OrgID:                     1,
+	}))
+}
+
+func createConfigHistoryServiceSut(t *testing.T, secretsService secrets.Service) (*ConfigHistoryService, *store.DBstore) {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	dbstore := &store.DBstore{
+		SQLStore: sqlStore,
+		Cfg: setting.UnifiedAlertingSettings{
+			BaseInterval: time.Second * 10,
+		},
+		Logger: log.NewNopLogger(),
+	}
+	return NewConfigHistoryService(dbstore, dbstore, dbstore, sqlStore, secretsService, log.NewNopLogger(), tracing.InitializeTracerForTest(), nil), dbstore
+}
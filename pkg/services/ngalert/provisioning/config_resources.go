@@ -0,0 +1,62 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// AMConfigResourceStore is implemented by the stores that can change single receivers, mute timings and templates of
+// an Alertmanager configuration without writing the whole configuration.
+type AMConfigResourceStore interface {
+	UpdateAlertmanagerConfigResources(ctx context.Context, cmd *models.UpdateAlertmanagerConfigResourcesCmd) error
+}
+
+// persistConfigChanges persists a revision whose changes are limited to the given resources. If the store supports it,
+// only these resources are written, which spares serializing and storing configurations of several megabytes for a
+// single change. Otherwise, or if there are no changes, in dry-run mode or if the store cannot change the configuration
+// one resource at a time, the whole configuration is persisted.
+func persistConfigChanges(ctx context.Context, amStore AMConfigStore, orgID int64, revision *cfgRevision, changes []models.AlertConfigurationResourceChange) error {
+	if resourceStore, ok := amStore.(AMConfigResourceStore); ok && len(changes) > 0 {
+		if _, dryRun := dryRunFromContext(ctx); !dryRun {
+			err := resourceStore.UpdateAlertmanagerConfigResources(ctx, &models.UpdateAlertmanagerConfigResourcesCmd{
+				OrgID:                    orgID,
+				FetchedConfigurationHash: revision.concurrencyToken,
+				ConfigurationVersion:     revision.version,
+				Changes:                  changes,
+			})
+			if !errors.Is(err, store.ErrIncrementalConfigUpdateUnsupported) {
+				return err
+			}
+		}
+	}
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return err
+	}
+	return PersistConfig(ctx, amStore, &models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+	})
+}
+
+// resourceChange returns the change that sets a resource of the configuration to the given value.
+func resourceChange(kind models.AlertConfigurationResourceKind, name string, value any) (models.AlertConfigurationResourceChange, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return models.AlertConfigurationResourceChange{}, err
+	}
+	return models.AlertConfigurationResourceChange{Kind: kind, Name: name, Content: string(data)}, nil
+}
+
+// deletedResource returns the change that deletes a resource of the configuration.
+func deletedResource(kind models.AlertConfigurationResourceKind, name string) models.AlertConfigurationResourceChange {
+	return models.AlertConfigurationResourceChange{Kind: kind, Name: name}
+}
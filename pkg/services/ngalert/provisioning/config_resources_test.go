@@ -0,0 +1,86 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// resourceAMConfigStore is a lockingAMConfigStore that records the changes of single resources instead of applying
+// them, or fails them with err.
+type resourceAMConfigStore struct {
+	*lockingAMConfigStore
+	err     error
+	changes [][]models.AlertConfigurationResourceChange
+}
+
+func (s *resourceAMConfigStore) UpdateAlertmanagerConfigResources(_ context.Context, cmd *models.UpdateAlertmanagerConfigResourcesCmd) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.changes = append(s.changes, cmd.Changes)
+	return nil
+}
+
+func TestPersistConfigChanges(t *testing.T) {
+	ctx := context.Background()
+	tmpl := definitions.NotificationTemplate{Name: "team", Template: `{{ define "team" }}{{ end }}`}
+
+	t.Run("only the changed resources are written if the store supports it", func(t *testing.T) {
+		sut, amStore := createConfigResourcesSut(nil)
+
+		_, err := sut.SetTemplate(ctx, 1, tmpl)
+		require.NoError(t, err)
+		require.NoError(t, sut.DeleteTemplate(ctx, 1, "team"))
+
+		require.Zero(t, amStore.saves)
+		require.Equal(t, [][]models.AlertConfigurationResourceChange{
+			{{Kind: models.AlertConfigurationResourceTemplate, Name: "team", Content: `"{{ define \"team\" }}{{ end }}"`}},
+			{{Kind: models.AlertConfigurationResourceTemplate, Name: "team"}},
+		}, amStore.changes)
+	})
+
+	t.Run("the whole configuration is written if the store cannot change single resources", func(t *testing.T) {
+		sut, amStore := createConfigResourcesSut(store.ErrIncrementalConfigUpdateUnsupported)
+
+		_, err := sut.SetTemplate(ctx, 1, tmpl)
+		require.NoError(t, err)
+
+		require.Equal(t, 1, amStore.saves)
+		templates, err := sut.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Contains(t, templates, "team")
+	})
+
+	t.Run("the whole configuration is recorded in dry-run mode", func(t *testing.T) {
+		sut, amStore := createConfigResourcesSut(nil)
+		dryRunCtx, dryRun := WithDryRun(ctx)
+
+		_, err := sut.SetTemplate(dryRunCtx, 1, tmpl)
+		require.ErrorIs(t, err, ErrDryRun)
+
+		require.Empty(t, amStore.changes)
+		require.NotEmpty(t, dryRun.Result().Changes)
+	})
+}
+
+func createConfigResourcesSut(err error) (*TemplateService, *resourceAMConfigStore) {
+	amStore := &resourceAMConfigStore{
+		lockingAMConfigStore: &lockingAMConfigStore{config: defaultAlertmanagerConfigJSON},
+		err:                  err,
+	}
+	return &TemplateService{
+		config: newTracedAMConfigStore(amStore, tracing.InitializeTracerForTest(), log.NewNopLogger(), nil),
+		prov:   NewFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
+	}, amStore
+}
@@ -0,0 +1,119 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const (
+	// configUpdateAttempts is how many times an update of the Alertmanager configuration is attempted when the
+	// configuration is changed by another Grafana instance between reading and persisting it.
+	configUpdateAttempts = 5
+	// configUpdateBackoff is the wait before the first retry of an update. It doubles with every retry.
+	configUpdateBackoff = 50 * time.Millisecond
+)
+
+// configWriteLocks serializes the updates of the Alertmanager configuration of each organization made by this
+// instance. Every update reads the whole configuration, changes it and persists it, so concurrent updates of
+// different resources would otherwise fail the optimistic lock of the store, or overwrite each other if they did not
+// pass the hash of the configuration they read.
+var configWriteLocks = &orgLocks{locks: make(map[int64]*orgLock)}
+
+type orgLocks struct {
+	mtx   sync.Mutex
+	locks map[int64]*orgLock
+}
+
+// orgLock is the lock of an org. It is removed from orgLocks once no update holds or waits for it, so that the locks
+// of deleted orgs are not kept for the lifetime of the instance.
+type orgLock struct {
+	ch    chan struct{}
+	users int
+}
+
+// lock waits for the lock of the org, unless the context is done first. The returned function releases the lock.
+func (l *orgLocks) lock(ctx context.Context, orgID int64) (func(), error) {
+	l.mtx.Lock()
+	ol, ok := l.locks[orgID]
+	if !ok {
+		ol = &orgLock{ch: make(chan struct{}, 1)}
+		l.locks[orgID] = ol
+	}
+	ol.users++
+	l.mtx.Unlock()
+	select {
+	case ol.ch <- struct{}{}:
+		return func() {
+			<-ol.ch
+			l.release(orgID, ol)
+		}, nil
+	case <-ctx.Done():
+		l.release(orgID, ol)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *orgLocks) release(orgID int64, ol *orgLock) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	ol.users--
+	if ol.users == 0 {
+		delete(l.locks, orgID)
+	}
+}
+
+type heldConfigLocksContextKey struct{}
+
+// holdsConfigLock reports whether the context belongs to an update that holds the lock of the org.
+func holdsConfigLock(ctx context.Context, orgID int64) bool {
+	orgs, _ := ctx.Value(heldConfigLocksContextKey{}).([]int64)
+	for _, o := range orgs {
+		if o == orgID {
+			return true
+		}
+	}
+	return false
+}
+
+func withHeldConfigLock(ctx context.Context, orgID int64) context.Context {
+	held, _ := ctx.Value(heldConfigLocksContextKey{}).([]int64)
+	orgs := make([]int64, 0, len(held)+1)
+	orgs = append(orgs, held...)
+	return context.WithValue(ctx, heldConfigLocksContextKey{}, append(orgs, orgID))
+}
+
+// updateAlertmanagerConfig runs update, which reads, changes and persists the Alertmanager configuration of the org,
+// while holding the write lock of the org. Updates nested in update, such as those of the services that a bundle is
+// applied with, run in the same lock. If the configuration was changed by another instance after update read it,
+// update is run again after a backoff with jitter, up to configUpdateAttempts times.
+func updateAlertmanagerConfig(ctx context.Context, orgID int64, update func(ctx context.Context) error) error {
+	if holdsConfigLock(ctx, orgID) {
+		return update(ctx)
+	}
+	unlock, err := configWriteLocks.lock(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	ctx = withHeldConfigLock(ctx, orgID)
+
+	backoff := configUpdateBackoff
+	for attempt := 1; ; attempt++ {
+		err = update(ctx)
+		if attempt == configUpdateAttempts || !errors.Is(err, store.ErrVersionLockedObjectNotFound) {
+			return err
+		}
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,156 @@
+package provisioning
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// lockingAMConfigStore is an AMConfigStore that checks the optimistic lock of updates like the database store does.
+// The next conflicts updates fail as if another instance had changed the configuration.
+type lockingAMConfigStore struct {
+	mtx       sync.Mutex
+	config    string
+	conflicts int
+	saves     int
+}
+
+func (s *lockingAMConfigStore) GetLatestAlertmanagerConfiguration(_ context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) (*models.AlertConfiguration, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return &models.AlertConfiguration{
+		AlertmanagerConfiguration: s.config,
+		ConfigurationHash:         fmt.Sprintf("%x", md5.Sum([]byte(s.config))),
+		OrgID:                     query.OrgID,
+	}, nil
+}
+
+func (s *lockingAMConfigStore) UpdateAlertmanagerConfiguration(_ context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.saves++
+	if s.conflicts > 0 {
+		s.conflicts--
+		return store.ErrVersionLockedObjectNotFound
+	}
+	if cmd.FetchedConfigurationHash != fmt.Sprintf("%x", md5.Sum([]byte(s.config))) {
+		return store.ErrVersionLockedObjectNotFound
+	}
+	s.config = cmd.AlertmanagerConfiguration
+	return nil
+}
+
+func TestUpdateAlertmanagerConfig(t *testing.T) {
+	ctx := context.Background()
+	tmpl := func(name string) definitions.NotificationTemplate {
+		return definitions.NotificationTemplate{Name: name, Template: fmt.Sprintf(`{{ define "%s" }}{{ end }}`, name)}
+	}
+
+	t.Run("concurrent updates of an org do not overwrite each other", func(t *testing.T) {
+		sut, amStore := createConfigWriterSut(0)
+
+		var wg sync.WaitGroup
+		errs := make([]error, 10)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = sut.SetTemplate(ctx, 1, tmpl(fmt.Sprintf("template-%d", i)))
+			}(i)
+		}
+		wg.Wait()
+
+		for _, err := range errs {
+			require.NoError(t, err)
+		}
+		templates, err := sut.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, templates, 10)
+		require.Equal(t, 10, amStore.saves)
+	})
+
+	t.Run("updates are retried if the configuration was changed by another instance", func(t *testing.T) {
+		sut, amStore := createConfigWriterSut(2)
+
+		_, err := sut.SetTemplate(ctx, 1, tmpl("team"))
+
+		require.NoError(t, err)
+		require.Equal(t, 3, amStore.saves)
+	})
+
+	t.Run("updates fail after the last attempt", func(t *testing.T) {
+		sut, amStore := createConfigWriterSut(configUpdateAttempts)
+
+		_, err := sut.SetTemplate(ctx, 1, tmpl("team"))
+
+		require.ErrorIs(t, err, store.ErrVersionLockedObjectNotFound)
+		require.Equal(t, configUpdateAttempts, amStore.saves)
+	})
+
+	t.Run("nested updates of the same org run in the lock of the outer update", func(t *testing.T) {
+		err := updateAlertmanagerConfig(ctx, 1, func(ctx context.Context) error {
+			return updateAlertmanagerConfig(ctx, 1, func(ctx context.Context) error {
+				return nil
+			})
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("locks are released once no update holds or waits for them", func(t *testing.T) {
+		const orgID = 4242
+		err := updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+			return nil
+		})
+		require.NoError(t, err)
+		require.False(t, hasConfigWriteLock(orgID))
+
+		held, release := make(chan struct{}), make(chan struct{})
+		go func() {
+			_ = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+				close(held)
+				<-release
+				return nil
+			})
+		}()
+		<-held
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		err = updateAlertmanagerConfig(cancelled, orgID, func(ctx context.Context) error {
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		close(release)
+
+		require.Eventually(t, func() bool { return !hasConfigWriteLock(orgID) }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func hasConfigWriteLock(orgID int64) bool {
+	configWriteLocks.mtx.Lock()
+	defer configWriteLocks.mtx.Unlock()
+	_, ok := configWriteLocks.locks[orgID]
+	return ok
+}
+
+func createConfigWriterSut(conflicts int) (*TemplateService, *lockingAMConfigStore) {
+	amStore := &lockingAMConfigStore{config: defaultAlertmanagerConfigJSON, conflicts: conflicts}
+	return &TemplateService{
+		config: amStore,
+		prov:   NewFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
+	}, amStore
+}
@@ -0,0 +1,107 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/services/accesscontrol"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+// Contact points can be read and changed by users with the provisioning permissions of the organization, and by users
+// with the receiver permissions on the UIDs of the contact points. The latter let teams of the same organization manage
+// their own contact points without access to the secure settings of the contact points of other teams. Renaming a
+// contact point into the receiver group of other contact points, or renaming all contact points of a receiver group,
+// requires the receiver permissions on all contact points of that group.
+//
+// Grafana has no resource permissions for contact points: creating a contact point does not grant the receiver
+// permissions on it to its creator or their team. The permissions are granted with custom roles scoped to
+// receivers:uid:<uid>, for example with the role API or role provisioning.
+
+// filterReadableReceivers returns the receivers that the user is allowed to read. Reads made without a user, such as
+// those of file provisioning, are not restricted.
+func (ecp *ContactPointService) filterReadableReceivers(ctx context.Context, u *user.SignedInUser, receivers []*apimodels.PostableGrafanaReceiver) []*apimodels.PostableGrafanaReceiver {
+	if u == nil || ecp.evaluate(ctx, u, accesscontrol.EvalAny(
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningRead),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversRead, accesscontrol.ScopeReceiversAll),
+	)) {
+		return receivers
+	}
+	result := make([]*apimodels.PostableGrafanaReceiver, 0, len(receivers))
+	for _, r := range receivers {
+		if ecp.evaluate(ctx, u, accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversRead, accesscontrol.ScopeReceiversProvider.GetResourceScopeUID(r.UID))) {
+			result = append(result, r)
+		}
+	}
+	return result
+}
+
+// authorizeContactPointWrite checks that the user of the request is allowed to change the contact point with the given
+// UID. Changes made without a user, such as those of file provisioning, are not restricted.
+func (ecp *ContactPointService) authorizeContactPointWrite(ctx context.Context, uid string) error {
+	u, err := appcontext.User(ctx)
+	if err != nil {
+		return nil
+	}
+	if ecp.evaluate(ctx, u, accesscontrol.EvalAny(
+		EvalOrgProvisioningWrite(),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversWrite, accesscontrol.ScopeReceiversProvider.GetResourceScopeUID(uid)),
+	)) {
+		return nil
+	}
+	return (&Error{
+		Code:   ErrCodePermissionDenied,
+		Reason: "user is not allowed to change the contact point",
+	}).withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid)
+}
+
+// authorizeContactPointRename checks that the user of the request is allowed to rename the contact point with the
+// given UID to name. Renaming all contact points of its receiver group, which is what a cascading rename does, requires
+// the permissions on all of them. Moving the contact point into the receiver group of other contact points requires
+// the permissions on the contact points of that group.
+func (ecp *ContactPointService) authorizeContactPointRename(ctx context.Context, idx *receiverIndex, uid, name string, cascade bool) error {
+	loc, ok := idx.receiver(uid)
+	if !ok || loc.group.Name == name {
+		return nil
+	}
+	if cascade {
+		return ecp.authorizeReceiverGroupWrite(ctx, loc.group)
+	}
+	if target, ok := idx.group(name); ok {
+		return ecp.authorizeReceiverGroupWrite(ctx, target)
+	}
+	return nil
+}
+
+// authorizeReceiverGroupWrite checks that the user of the request is allowed to change all contact points of the
+// receiver group. Changes made without a user, such as those of file provisioning, are not restricted.
+func (ecp *ContactPointService) authorizeReceiverGroupWrite(ctx context.Context, group *apimodels.PostableApiReceiver) error {
+	u, err := appcontext.User(ctx)
+	if err != nil {
+		return nil
+	}
+	if ecp.evaluate(ctx, u, EvalOrgProvisioningWrite()) {
+		return nil
+	}
+	for _, r := range group.GrafanaManagedReceivers {
+		if !ecp.evaluate(ctx, u, accesscontrol.EvalPermission(accesscontrol.ActionAlertingReceiversWrite, accesscontrol.ScopeReceiversProvider.GetResourceScopeUID(r.UID))) {
+			return (&Error{
+				Code:   ErrCodePermissionDenied,
+				Reason: fmt.Sprintf("user is not allowed to change the contact points named '%s'", group.Name),
+			}).withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), r.UID)
+		}
+	}
+	return nil
+}
+
+func (ecp *ContactPointService) evaluate(ctx context.Context, u *user.SignedInUser, evaluator accesscontrol.Evaluator) bool {
+	permitted, err := ecp.ac.Evaluate(ctx, u, evaluator)
+	if err != nil {
+		ecp.log.FromContext(ctx).Error("Failed to evaluate user permissions", "error", err)
+		return false
+	}
+	return permitted
+}
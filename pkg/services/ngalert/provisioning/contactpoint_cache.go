@@ -0,0 +1,134 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// contactPointCache holds the parsed latest configuration of each org along with the decrypted secure settings of its
+// receivers, so that repeated reads of the contact points neither parse the configuration nor decrypt the secure
+// settings again.
+//
+// Entries are keyed by the hash of the configuration, which means that an entry is never used once the configuration
+// was changed, no matter by which service. Entries are additionally dropped whenever the contact point service saves
+// a configuration. The parsed configuration of an entry is shared by concurrent readers and must not be modified.
+type contactPointCache struct {
+	metrics *metrics.Provisioning
+
+	mtx     sync.Mutex
+	entries map[int64]*contactPointCacheEntry
+}
+
+type contactPointCacheEntry struct {
+	revision *cfgRevision
+
+	mtx       sync.Mutex
+	receivers map[*apimodels.PostableGrafanaReceiver]cachedReceiver
+}
+
+// cachedReceiver is what is derived from a stored receiver when its contact point is read.
+type cachedReceiver struct {
+	// secureSettings are the decrypted secure settings that are set.
+	secureSettings map[string]string
+	version        string
+}
+
+func newContactPointCache(m *metrics.Provisioning) *contactPointCache {
+	return &contactPointCache{
+		metrics: m,
+		entries: make(map[int64]*contactPointCacheEntry),
+	}
+}
+
+// get returns the entry of the latest configuration of the org, which is loaded from the store if it is not cached.
+func (c *contactPointCache) get(ctx context.Context, orgID int64, store AMConfigStore) (*contactPointCacheEntry, error) {
+	cfg, err := store.GetLatestAlertmanagerConfiguration(ctx, &models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("no alertmanager configuration present in this org")
+	}
+
+	c.mtx.Lock()
+	entry, ok := c.entries[orgID]
+	c.mtx.Unlock()
+	if ok && entry.revision.concurrencyToken == cfg.ConfigurationHash {
+		c.record(true)
+		return entry, nil
+	}
+	c.record(false)
+
+	parsed, err := deserializeAlertmanagerConfig(cfg.AlertmanagerConfiguration)
+	if err != nil {
+		return nil, err
+	}
+	entry = &contactPointCacheEntry{
+		revision: &cfgRevision{
+			cfg:              parsed,
+			concurrencyToken: cfg.ConfigurationHash,
+			version:          cfg.ConfigurationVersion,
+		},
+		receivers: make(map[*apimodels.PostableGrafanaReceiver]cachedReceiver),
+	}
+	// The index is built before the entry is shared, as it is built lazily otherwise.
+	entry.revision.receivers()
+
+	c.mtx.Lock()
+	c.entries[orgID] = entry
+	c.mtx.Unlock()
+	return entry, nil
+}
+
+// invalidate drops the entry of the org.
+func (c *contactPointCache) invalidate(orgID int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	delete(c.entries, orgID)
+}
+
+func (c *contactPointCache) record(hit bool) {
+	if c.metrics == nil {
+		return
+	}
+	if hit {
+		c.metrics.ContactPointCacheHits.Inc()
+	} else {
+		c.metrics.ContactPointCacheMisses.Inc()
+	}
+}
+
+// receiver returns what is derived from the receiver of the entry's configuration. It is derived with load if it is
+// not cached yet, and only cached if load reports that it is complete.
+func (e *contactPointCacheEntry) receiver(r *apimodels.PostableGrafanaReceiver, load func() (cachedReceiver, bool)) cachedReceiver {
+	e.mtx.Lock()
+	cached, ok := e.receivers[r]
+	e.mtx.Unlock()
+	if ok {
+		return cached
+	}
+	cached, complete := load()
+	if complete {
+		e.mtx.Lock()
+		e.receivers[r] = cached
+		e.mtx.Unlock()
+	}
+	return cached
+}
+
+// invalidatingAMConfigStore drops the cached configuration of an org whenever a configuration of the org is saved.
+type invalidatingAMConfigStore struct {
+	AMConfigStore
+	cache *contactPointCache
+}
+
+func (s invalidatingAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	err := s.AMConfigStore.UpdateAlertmanagerConfiguration(ctx, cmd)
+	s.cache.invalidate(cmd.OrgID)
+	return err
+}
@@ -0,0 +1,66 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestContactPointCache(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	t.Run("repeated reads use the cached configuration until it changes", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		m := metrics.NewProvisioningMetrics(prometheus.NewRegistry())
+		sut.cache = newContactPointCache(m)
+		_, err := sut.CreateContactPoint(context.Background(), 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), cpsQuery(1), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 2)
+		// Contact points returned from the cache must not share their settings with earlier results.
+		cps[1].Settings.Set("recipient", "modified")
+		cps, err = sut.GetContactPoints(context.Background(), cpsQuery(1), nil)
+		require.NoError(t, err)
+		require.Equal(t, "value_recipient", cps[1].Settings.Get("recipient").MustString())
+		require.Equal(t, definitions.RedactedValue, cps[1].Settings.Get("token").MustString())
+		require.Equal(t, 1.0, testutil.ToFloat64(m.ContactPointCacheMisses))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.ContactPointCacheHits))
+
+		second := createTestContactPoint()
+		second.Name = "second-contact-point"
+		_, err = sut.CreateContactPoint(context.Background(), 1, second, models.ProvenanceAPI)
+		require.NoError(t, err)
+		cps, err = sut.GetContactPoints(context.Background(), cpsQuery(1), nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 3)
+		require.Equal(t, 2.0, testutil.ToFloat64(m.ContactPointCacheMisses))
+	})
+
+	t.Run("saving a configuration drops the cached configuration of the org", func(t *testing.T) {
+		cache := newContactPointCache(nil)
+		store := invalidatingAMConfigStore{AMConfigStore: newFakeAMConfigStore(defaultAlertmanagerConfigJSON), cache: cache}
+		_, err := cache.get(context.Background(), 1, store)
+		require.NoError(t, err)
+		require.Contains(t, cache.entries, int64(1))
+
+		err = store.UpdateAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: defaultAlertmanagerConfigJSON,
+			OrgID:                     1,
+		})
+		require.NoError(t, err)
+		require.NotContains(t, cache.entries, int64(1))
+	})
+}
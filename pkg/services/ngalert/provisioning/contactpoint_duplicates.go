@@ -0,0 +1,328 @@
+package provisioning
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// DuplicateContactPoints is a set of contact points whose integrations have the same types and settings, including
+// the secure settings, so that they send the same notifications.
+type DuplicateContactPoints struct {
+	// Names are the names of the duplicate contact points, in order.
+	Names []string
+}
+
+// FindDuplicateContactPoints returns the sets of contact points of the organization that are duplicates of each other.
+// The secure settings are decrypted to be compared, so the user of the request requires the
+// alert.provisioning.secrets:read permission, although no secrets are returned. Contact points whose secure settings
+// cannot be decrypted are never reported as duplicates.
+func (ecp *ContactPointService) FindDuplicateContactPoints(ctx context.Context, orgID int64) (_ []DuplicateContactPoints, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "FindDuplicateContactPoints", orgID)
+	defer func() { done(err) }()
+	if err := ecp.authorizeSecretsComparison(ctx, orgID); err != nil {
+		return nil, err
+	}
+	entry, err := ecp.cache.get(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return nil, err
+	}
+
+	load := func(r *apimodels.PostableGrafanaReceiver) (cachedReceiver, bool) {
+		complete := true
+		cached := entry.receiver(r, func() (cachedReceiver, bool) {
+			loaded, ok := ecp.loadReceiver(ctx, r)
+			complete = ok
+			return loaded, ok
+		})
+		return cached, complete
+	}
+	byFingerprint := make(map[string][]string)
+	for _, group := range entry.revision.cfg.AlertmanagerConfig.Receivers {
+		if len(group.GrafanaManagedReceivers) == 0 {
+			continue
+		}
+		fingerprint, ok, err := contactPointFingerprint(group, load)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		byFingerprint[fingerprint] = append(byFingerprint[fingerprint], group.Name)
+	}
+
+	result := []DuplicateContactPoints{}
+	for _, names := range byFingerprint {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		result = append(result, DuplicateContactPoints{Names: names})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Names[0] < result[j].Names[0]
+	})
+	return result, nil
+}
+
+// MergeContactPoints removes the duplicates of the target contact point, and points the notification policies and
+// the notification settings of alert rules that use them to the target. Every duplicate must send the same
+// notifications as the target, which is checked by comparing their decrypted settings. The change of the
+// configuration and the rules is applied in one transaction.
+func (ecp *ContactPointService) MergeContactPoints(ctx context.Context, orgID int64, target string, duplicates []string, provenance models.Provenance) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return ecp.mergeContactPoints(ctx, orgID, target, duplicates, provenance)
+	})
+}
+
+func (ecp *ContactPointService) mergeContactPoints(ctx context.Context, orgID int64, target string, duplicates []string, provenance models.Provenance) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "MergeContactPoints", orgID,
+		attribute.String("contact_point_name", target), attribute.Int("duplicates", len(duplicates)))
+	defer func() { done(err) }()
+	if target == "" {
+		return newValidationError("target", "the name of the contact point to merge into must be specified")
+	}
+	if len(duplicates) == 0 {
+		return newValidationError("duplicates", "at least one duplicate contact point must be specified")
+	}
+	if err := ecp.authorizeSecretsComparison(ctx, orgID); err != nil {
+		return err
+	}
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return err
+	}
+	load := func(r *apimodels.PostableGrafanaReceiver) (cachedReceiver, bool) {
+		return ecp.loadReceiver(ctx, r)
+	}
+	resourceType := (&apimodels.EmbeddedContactPoint{}).ResourceType()
+	targetGroup, ok := revision.receivers().group(target)
+	if !ok {
+		return newNotFoundError(resourceType, target, "contact point '%s' not found", target)
+	}
+	targetFingerprint, ok, err := contactPointFingerprint(targetGroup, load)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return newValidationError("target", "the secure settings of contact point '%s' cannot be decrypted", target)
+	}
+	provenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+	if err != nil {
+		return err
+	}
+
+	var removed []*apimodels.PostableGrafanaReceiver
+	seen := make(map[string]struct{}, len(duplicates))
+	for _, name := range duplicates {
+		if name == target {
+			return newValidationError("duplicates", "contact point '%s' cannot be merged into itself", name)
+		}
+		if _, ok := seen[name]; ok {
+			return newValidationError("duplicates", "contact point '%s' is listed more than once", name)
+		}
+		seen[name] = struct{}{}
+		group, ok := revision.receivers().group(name)
+		if !ok {
+			return newNotFoundError(resourceType, name, "contact point '%s' not found", name)
+		}
+		fingerprint, ok, err := contactPointFingerprint(group, load)
+		if err != nil {
+			return err
+		}
+		if !ok || fingerprint != targetFingerprint {
+			return newValidationError("duplicates", "contact point '%s' is not a duplicate of contact point '%s'", name, target).
+				withResource(resourceType, name)
+		}
+		for _, r := range group.GrafanaManagedReceivers {
+			if err := ecp.authorizeContactPointWrite(ctx, r.UID); err != nil {
+				return err
+			}
+			stored := provenanceOrNone(provenances, r.UID)
+			if stored == models.ProvenanceGlobal {
+				return newValidationError("duplicates", "contact point '%s' is inherited from the global contact points and cannot be merged", name).
+					withResource(resourceType, r.UID)
+			}
+			if stored != provenance && stored != models.ProvenanceNone {
+				return fmt.Errorf("cannot change provenance from '%s' to '%s'", stored, provenance)
+			}
+		}
+		removed = append(removed, group.GrafanaManagedReceivers...)
+	}
+	for _, r := range removed {
+		revision.receivers().remove(r.UID)
+	}
+	for _, name := range duplicates {
+		replaceReferences(name, target, revision.cfg.AlertmanagerConfig.Route)
+	}
+
+	updates, err := ecp.rulesUsingReceivers(ctx, orgID, duplicates, target)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(revision.cfg)
+	if err != nil {
+		return err
+	}
+	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := ecp.applyRuleUpdates(ctx, orgID, updates); err != nil {
+			return err
+		}
+		for _, r := range removed {
+			resource := &apimodels.EmbeddedContactPoint{UID: r.UID}
+			if err := ecp.provenanceStore.DeleteProvenance(ctx, resource, orgID); err != nil {
+				return err
+			}
+			if err := ecp.expirations.SetExpiration(ctx, orgID, r.UID, nil); err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionDelete, resource, models.ProvenanceNone, redactedReceiver(r), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	ecp.log.FromContext(ctx).Info("Merged duplicate contact points", "org", orgID, "target", target, "duplicates", strings.Join(duplicates, ","), "rules", len(updates))
+	return nil
+}
+
+// authorizeSecretsComparison checks that the user of the request is allowed to read the secure settings of contact
+// points. Requests without a user, such as those of file provisioning, are not restricted.
+func (ecp *ContactPointService) authorizeSecretsComparison(ctx context.Context, orgID int64) error {
+	u, err := appcontext.User(ctx)
+	if err != nil {
+		return nil
+	}
+	allowed := ecp.canDecryptSecrets(ctx, u)
+	countDecryptRequest(ecp.metrics, "contactPoint", orgID, allowed)
+	if !allowed {
+		return fmt.Errorf("%w: user requires Admin role or alert.provisioning.secrets:read permission to compare secure settings", ErrPermissionDenied)
+	}
+	return nil
+}
+
+// rulesUsingReceivers returns the updates of the alert rules whose notification settings use any of the given
+// contact points, so that they use the target instead.
+func (ecp *ContactPointService) rulesUsingReceivers(ctx context.Context, orgID int64, names []string, target string) ([]models.UpdateRule, error) {
+	if ecp.ruleStore == nil {
+		return nil, nil
+	}
+	replaced := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		replaced[name] = struct{}{}
+	}
+	var updates []models.UpdateRule
+	updated := make(map[string]struct{})
+	for _, name := range names {
+		rules, err := ecp.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, ReceiverName: name})
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range rules {
+			if _, ok := updated[rule.UID]; ok {
+				continue
+			}
+			updated[rule.UID] = struct{}{}
+			newRule := *rule
+			newRule.NotificationSettings = make([]models.NotificationSettings, 0, len(rule.NotificationSettings))
+			for _, s := range rule.NotificationSettings {
+				if _, ok := replaced[s.Receiver]; ok {
+					s.Receiver = target
+				}
+				newRule.NotificationSettings = append(newRule.NotificationSettings, s)
+			}
+			updates = append(updates, models.UpdateRule{Existing: rule, New: newRule})
+		}
+	}
+	return updates, nil
+}
+
+// applyRuleUpdates persists the updates of the alert rules whose notification settings follow a change of the
+// contact points they use, and records them in the audit log.
+func (ecp *ContactPointService) applyRuleUpdates(ctx context.Context, orgID int64, updates []models.UpdateRule) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	provenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	if err := ecp.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
+		return err
+	}
+	for _, update := range updates {
+		if err := recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &update.New, provenanceOrNone(provenances, update.New.UID), update.Existing, update.New); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// contactPointFingerprint returns a hash of the types and settings of the integrations of a contact point, which is
+// the same for contact points that send the same notifications regardless of the names and UIDs of the integrations.
+// The secure settings are decrypted by load, and false is reported if any of them cannot be decrypted.
+func contactPointFingerprint(group *apimodels.PostableApiReceiver, load func(r *apimodels.PostableGrafanaReceiver) (cachedReceiver, bool)) (string, bool, error) {
+	fingerprints := make([]string, 0, len(group.GrafanaManagedReceivers))
+	for _, r := range group.GrafanaManagedReceivers {
+		loaded, complete := load(r)
+		if !complete {
+			return "", false, nil
+		}
+		fingerprint, err := integrationFingerprint(r, loaded.secureSettings)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to compare contact point '%s': %w", group.Name, err)
+		}
+		fingerprints = append(fingerprints, fingerprint)
+	}
+	sort.Strings(fingerprints)
+	return strings.Join(fingerprints, ","), true, nil
+}
+
+// integrationFingerprint returns a hash of the type and the settings of an integration. The settings are decoded, so
+// that their formatting and the order of their keys do not matter.
+func integrationFingerprint(r *apimodels.PostableGrafanaReceiver, secureSettings map[string]string) (string, error) {
+	var settings any
+	if len(r.Settings) > 0 {
+		if err := json.Unmarshal(r.Settings, &settings); err != nil {
+			return "", err
+		}
+	}
+	data, err := json.Marshal(struct {
+		Type                  string            `json:"type"`
+		DisableResolveMessage bool              `json:"disableResolveMessage"`
+		Settings              any               `json:"settings"`
+		SecureSettings        map[string]string `json:"secureSettings"`
+	}{
+		Type:                  strings.ToLower(r.Type),
+		DisableResolveMessage: r.DisableResolveMessage,
+		Settings:              settings,
+		SecureSettings:        secureSettings,
+	})
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(data)), nil
+}
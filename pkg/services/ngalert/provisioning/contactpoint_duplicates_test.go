@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestContactPointDuplicates(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	createSut := func(t *testing.T) *ContactPointService {
+		t.Helper()
+		sut := createContactPointServiceSut(t, secretsService)
+		for _, name := range []string{"team-a", "team-b", "team-c"} {
+			cp := createTestContactPoint()
+			cp.Name = name
+			_, err := sut.CreateContactPoint(context.Background(), 1, cp, models.ProvenanceAPI)
+			require.NoError(t, err)
+		}
+		// A different token is a different contact point, although the token is encrypted.
+		cp := createTestContactPoint()
+		cp.Name = "other-token"
+		cp.Settings.Set("token", "other_token")
+		_, err := sut.CreateContactPoint(context.Background(), 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		return sut
+	}
+
+	t.Run("contact points with the same settings are found", func(t *testing.T) {
+		sut := createSut(t)
+
+		duplicates, err := sut.FindDuplicateContactPoints(context.Background(), 1)
+		require.NoError(t, err)
+		require.Equal(t, []DuplicateContactPoints{{Names: []string{"team-a", "team-b", "team-c"}}}, duplicates)
+	})
+
+	t.Run("finding duplicates requires permission to read secrets", func(t *testing.T) {
+		sut := createSut(t)
+		ctx := appcontext.WithUser(context.Background(), &user.SignedInUser{UserID: 42, Login: "editor"})
+
+		_, err := sut.FindDuplicateContactPoints(ctx, 1)
+		require.ErrorIs(t, err, ErrPermissionDenied)
+	})
+
+	t.Run("merging removes the duplicates and rewrites references", func(t *testing.T) {
+		sut := createSut(t)
+		store := sut.amStore.(*fakeAMConfigStore)
+		cfg := &definitions.PostableUserConfig{}
+		require.NoError(t, json.Unmarshal([]byte(store.config.AlertmanagerConfiguration), cfg))
+		cfg.AlertmanagerConfig.Route.Routes = append(cfg.AlertmanagerConfig.Route.Routes, &definitions.Route{Receiver: "team-b"})
+		raw, err := json.Marshal(cfg)
+		require.NoError(t, err)
+		store.config.AlertmanagerConfiguration = string(raw)
+		ruleStore := fakes.NewRuleStore(t)
+		rule := dummyRule("team rule", 1)
+		rule.UID = "team-rule"
+		rule.NotificationSettings = []models.NotificationSettings{{Receiver: "team-c"}}
+		ruleStore.PutRule(context.Background(), &rule)
+		sut.ruleStore = ruleStore
+
+		err = sut.MergeContactPoints(context.Background(), 1, "team-a", []string{"team-b", "team-c"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		cps, err := sut.GetContactPoints(context.Background(), cpsQuery(1), nil)
+		require.NoError(t, err)
+		for _, cp := range cps {
+			require.NotContains(t, []string{"team-b", "team-c"}, cp.Name)
+		}
+		merged := &definitions.PostableUserConfig{}
+		require.NoError(t, json.Unmarshal([]byte(store.config.AlertmanagerConfiguration), merged))
+		routes := merged.AlertmanagerConfig.Route.Routes
+		require.Equal(t, "team-a", routes[len(routes)-1].Receiver)
+
+		var updates []models.UpdateRule
+		for _, op := range ruleStore.RecordedOps {
+			if u, ok := op.([]models.UpdateRule); ok {
+				updates = append(updates, u...)
+			}
+		}
+		require.Len(t, updates, 1)
+		require.Equal(t, "team-rule", updates[0].New.UID)
+		require.Equal(t, []models.NotificationSettings{{Receiver: "team-a"}}, updates[0].New.NotificationSettings)
+	})
+
+	t.Run("contact points with different settings are not merged", func(t *testing.T) {
+		sut := createSut(t)
+
+		err := sut.MergeContactPoints(context.Background(), 1, "team-a", []string{"other-token"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+
+		duplicates, err := sut.FindDuplicateContactPoints(context.Background(), 1)
+		require.NoError(t, err)
+		require.Len(t, duplicates, 1)
+	})
+
+	t.Run("merging an unknown contact point fails", func(t *testing.T) {
+		sut := createSut(t)
+
+		err := sut.MergeContactPoints(context.Background(), 1, "team-a", []string{"unknown"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+
+		err = sut.MergeContactPoints(context.Background(), 1, "team-a", []string{"team-a"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
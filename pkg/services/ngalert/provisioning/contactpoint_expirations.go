@@ -0,0 +1,181 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const contactPointExpirationsKey = "contact_point_expirations"
+
+// ContactPointExpirationStore keeps the time after which temporary contact points are removed, per organization and
+// keyed by the UID of the contact point.
+type ContactPointExpirationStore struct {
+	kv kvstore.KVStore
+}
+
+func NewContactPointExpirationStore(kv kvstore.KVStore) *ContactPointExpirationStore {
+	return &ContactPointExpirationStore{kv: kv}
+}
+
+// GetExpirations returns the expiration times of the temporary contact points of the organization.
+func (s *ContactPointExpirationStore) GetExpirations(ctx context.Context, orgID int64) (map[string]time.Time, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, contactPointExpirationsKey)
+	if err != nil {
+		return nil, err
+	}
+	expirations := map[string]time.Time{}
+	if !ok {
+		return expirations, nil
+	}
+	if err := json.Unmarshal([]byte(value), &expirations); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal contact point expirations: %w", err)
+	}
+	return expirations, nil
+}
+
+// GetAllExpirations returns the expiration times of the temporary contact points of all organizations.
+func (s *ContactPointExpirationStore) GetAllExpirations(ctx context.Context) (map[int64]map[string]time.Time, error) {
+	all, err := s.kv.GetAll(ctx, kvstore.AllOrganizations, fileProvisioningStatusNamespace)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]map[string]time.Time, len(all))
+	for orgID, values := range all {
+		value, ok := values[contactPointExpirationsKey]
+		if !ok {
+			continue
+		}
+		expirations := map[string]time.Time{}
+		if err := json.Unmarshal([]byte(value), &expirations); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal contact point expirations of organization %d: %w", orgID, err)
+		}
+		if len(expirations) > 0 {
+			result[orgID] = expirations
+		}
+	}
+	return result, nil
+}
+
+// SetExpiration sets the time after which the contact point is removed. A nil time makes the contact point permanent.
+func (s *ContactPointExpirationStore) SetExpiration(ctx context.Context, orgID int64, uid string, expiresAt *time.Time) error {
+	expirations, err := s.GetExpirations(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if _, ok := expirations[uid]; !ok && expiresAt == nil {
+		return nil
+	}
+	if expiresAt == nil {
+		delete(expirations, uid)
+	} else {
+		expirations[uid] = *expiresAt
+	}
+	if len(expirations) == 0 {
+		return s.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, contactPointExpirationsKey)
+	}
+	data, err := json.Marshal(expirations)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, contactPointExpirationsKey, string(data))
+}
+
+// ExpireContactPoints removes the temporary contact points of all organizations that expired at the given time. The
+// notification policies that use a removed contact point are routed to the receiver of the root policy instead.
+func (ecp *ContactPointService) ExpireContactPoints(ctx context.Context, now time.Time) error {
+	all, err := ecp.expirations.GetAllExpirations(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for orgID, expirations := range all {
+		uids := make([]string, 0, len(expirations))
+		for uid, expiresAt := range expirations {
+			if !expiresAt.After(now) {
+				uids = append(uids, uid)
+			}
+		}
+		sort.Strings(uids)
+		for _, uid := range uids {
+			err := updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+				return ecp.expireContactPoint(ctx, orgID, uid)
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove expired contact point '%s' of organization %d: %w", uid, orgID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ecp *ContactPointService) expireContactPoint(ctx context.Context, orgID int64, uid string) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "ExpireContactPoint", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return err
+	}
+	removed, fullRemoval := revision.receivers().remove(uid)
+	var oldState any
+	if removed != nil {
+		oldState = redactedReceiver(removed)
+		root := revision.cfg.AlertmanagerConfig.Route
+		if fullRemoval && root != nil {
+			if root.Receiver == removed.Name {
+				return fmt.Errorf("contact point '%s' is the receiver of the root notification policy", removed.Name)
+			}
+			replaceReferences(removed.Name, root.Receiver, root.Routes...)
+		}
+	}
+	data, err := json.Marshal(revision.cfg)
+	if err != nil {
+		return err
+	}
+	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := ecp.expirations.SetExpiration(ctx, orgID, uid, nil); err != nil {
+			return err
+		}
+		// The contact point was removed by other means already.
+		if removed == nil {
+			return nil
+		}
+		target := &apimodels.EmbeddedContactPoint{UID: uid}
+		provenance, err := ecp.provenanceStore.GetProvenance(ctx, target, orgID)
+		if err != nil {
+			return err
+		}
+		if err := ecp.provenanceStore.DeleteProvenance(ctx, target, orgID); err != nil {
+			return err
+		}
+		err = PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionExpire, target, provenance, oldState, nil)
+	})
+}
+
+// validateExpiration checks that a temporary contact point does not expire before it is saved.
+func validateExpiration(cp apimodels.EmbeddedContactPoint) error {
+	if cp.ExpiresAt != nil && !cp.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("%w: expiration time of the contact point must be in the future", ErrValidation)
+	}
+	return nil
+}
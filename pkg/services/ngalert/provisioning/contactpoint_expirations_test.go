@@ -0,0 +1,101 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestContactPointExpiration(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("contact points cannot expire in the past", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		expiresAt := time.Now().Add(-time.Minute)
+		cp.ExpiresAt = &expiresAt
+
+		_, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("expired contact points are removed and their policies routed to the root receiver", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		expiresAt := time.Now().Add(time.Hour)
+		cp.ExpiresAt = &expiresAt
+		created, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		revision.cfg.AlertmanagerConfig.Route.Routes[0].Receiver = cp.Name
+		data, err := json.Marshal(revision.cfg)
+		require.NoError(t, err)
+		require.NoError(t, PersistConfig(ctx, sut.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			OrgID:                     1,
+		}))
+
+		q := cpsQuery(1)
+		q.Name = cp.Name
+		cps, err := sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.True(t, expiresAt.Equal(*cps[0].ExpiresAt))
+
+		// Not expired yet.
+		require.NoError(t, sut.ExpireContactPoints(ctx, time.Now()))
+		cps, err = sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+
+		require.NoError(t, sut.ExpireContactPoints(ctx, expiresAt))
+		cps, err = sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Empty(t, cps)
+
+		revision, err = getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		require.Equal(t, "grafana-default-email", revision.cfg.AlertmanagerConfig.Route.Routes[0].Receiver)
+
+		entries := sut.provenanceStore.(*fakeProvisioningStore).auditEntries
+		last := entries[len(entries)-1]
+		require.Equal(t, models.ProvisioningAuditActionExpire, last.Action)
+		require.Equal(t, created.UID, last.ResourceID)
+
+		expirations, err := sut.expirations.GetExpirations(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, expirations)
+	})
+
+	t.Run("updating a contact point without expiration makes it permanent", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		expiresAt := time.Now().Add(time.Hour)
+		cp.ExpiresAt = &expiresAt
+		created, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		created.ExpiresAt = nil
+		require.NoError(t, sut.UpdateContactPoint(ctx, 1, created, models.ProvenanceAPI, UpdateContactPointOptions{}))
+
+		require.NoError(t, sut.ExpireContactPoints(ctx, expiresAt))
+		q := cpsQuery(1)
+		q.Name = cp.Name
+		cps, err := sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Nil(t, cps[0].ExpiresAt)
+	})
+}
@@ -0,0 +1,139 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"go.opentelemetry.io/otel/attribute"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// ReceiverTester sends test notifications through receivers with the Alertmanager of an organization.
+type ReceiverTester interface {
+	TestReceivers(ctx context.Context, orgID int64, c alertingNotify.TestReceiversConfigBodyParams) (*alertingNotify.TestReceiversResult, error)
+}
+
+// TestContactPoint sends a test notification through the integration of the contact point and reports how the
+// delivery went. The contact point does not have to exist. If it does, redacted secure settings are replaced with
+// their stored values. A failed delivery is part of the result and not an error.
+func (ecp *ContactPointService) TestContactPoint(ctx context.Context, orgID int64, contactPoint apimodels.EmbeddedContactPoint, alert *apimodels.TestReceiversConfigAlertParams) (_ apimodels.ContactPointTestResult, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "TestContactPoint", orgID,
+		attribute.String("contact_point_uid", contactPoint.UID), attribute.String("contact_point_type", contactPoint.Type))
+	defer func() { done(err) }()
+	if ecp.tester == nil {
+		return apimodels.ContactPointTestResult{}, errors.New("contact points cannot be tested without an Alertmanager")
+	}
+	if contactPoint.Settings == nil {
+		return apimodels.ContactPointTestResult{}, newValidationError("settings", "settings should not be empty")
+	}
+	if contactPoint.UID != "" {
+		revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+		if err != nil {
+			return apimodels.ContactPointTestResult{}, err
+		}
+		if _, ok := revision.receivers().receiver(contactPoint.UID); ok {
+			stored, err := ecp.getContactPointDecrypted(revision, contactPoint.UID)
+			if err != nil {
+				return apimodels.ContactPointTestResult{}, err
+			}
+			secretKeys, err := GetSecretKeysForContactPointType(contactPoint.Type)
+			if err != nil {
+				return apimodels.ContactPointTestResult{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+			}
+			for _, secretKey := range secretKeys {
+				if contactPoint.Settings.Get(secretKey).MustString() == apimodels.RedactedValue {
+					contactPoint.Settings.Set(secretKey, stored.Settings.Get(secretKey).MustString())
+				}
+			}
+		}
+	} else {
+		contactPoint.UID = util.GenerateShortUID()
+	}
+	if err := ecp.resolveVariables(ctx, orgID, &contactPoint); err != nil {
+		return apimodels.ContactPointTestResult{}, err
+	}
+	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
+		return apimodels.ContactPointTestResult{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	extractedSecrets, err := RemoveSecretsForContactPoint(&contactPoint)
+	if err != nil {
+		return apimodels.ContactPointTestResult{}, err
+	}
+	extractedSecrets, err = ecp.encryptSecrets(ctx, extractedSecrets)
+	if err != nil {
+		return apimodels.ContactPointTestResult{}, err
+	}
+	jsonData, err := contactPoint.Settings.MarshalJSON()
+	if err != nil {
+		return apimodels.ContactPointTestResult{}, err
+	}
+
+	params := alertingNotify.TestReceiversConfigBodyParams{
+		Receivers: []*alertingNotify.APIReceiver{{
+			ConfigReceiver: alertingNotify.ConfigReceiver{Name: contactPoint.Name},
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{{
+					UID:                   contactPoint.UID,
+					Name:                  contactPoint.Name,
+					Type:                  contactPoint.Type,
+					DisableResolveMessage: contactPoint.DisableResolveMessage,
+					Settings:              jsonData,
+					SecureSettings:        extractedSecrets,
+				}},
+			},
+		}},
+	}
+	if alert != nil {
+		params.Alert = &alertingNotify.TestReceiversConfigAlertParams{Annotations: alert.Annotations, Labels: alert.Labels}
+	}
+	start := time.Now()
+	result, err := ecp.tester.TestReceivers(ctx, orgID, params)
+	if err != nil {
+		return apimodels.ContactPointTestResult{}, err
+	}
+	duration := time.Since(start)
+
+	for _, receiver := range result.Receivers {
+		for _, c := range receiver.Configs {
+			if c.UID != contactPoint.UID {
+				continue
+			}
+			testResult := apimodels.ContactPointTestResult{
+				Status:     c.Status,
+				StatusCode: statusCodeForTestedIntegration(c.Error),
+				Duration:   duration.Round(time.Millisecond).String(),
+				NotifiedAt: result.NotifedAt,
+			}
+			if c.Error != nil {
+				testResult.Error = c.Error.Error()
+			}
+			return testResult, nil
+		}
+	}
+	return apimodels.ContactPointTestResult{}, fmt.Errorf("no test result for contact point with uid '%s'", contactPoint.UID)
+}
+
+// statusCodeForTestedIntegration classifies the error of a test notification like the Alertmanager API for testing
+// receivers does.
+func statusCodeForTestedIntegration(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	var (
+		invalidReceiverErr alertingNotify.IntegrationValidationError
+		receiverTimeoutErr alertingNotify.IntegrationTimeoutError
+	)
+	if errors.As(err, &invalidReceiverErr) {
+		return http.StatusBadRequest
+	}
+	if errors.As(err, &receiverTimeoutErr) {
+		return http.StatusRequestTimeout
+	}
+	return http.StatusInternalServerError
+}
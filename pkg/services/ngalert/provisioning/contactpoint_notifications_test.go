@@ -0,0 +1,83 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestTestContactPoint(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("test notifications are sent through new contact points", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		alert := &definitions.TestReceiversConfigAlertParams{Labels: model.LabelSet{"team": "a"}}
+
+		result, err := sut.TestContactPoint(ctx, 1, createTestContactPoint(), alert)
+		require.NoError(t, err)
+		require.Equal(t, "ok", result.Status)
+		require.Equal(t, 200, result.StatusCode)
+		require.Empty(t, result.Error)
+
+		calls := sut.tester.(*FakeReceiverTester).Calls
+		require.Len(t, calls, 1)
+		require.Equal(t, "a", string(calls[0].Alert.Labels["team"]))
+		require.Len(t, calls[0].Receivers, 1)
+		integration := calls[0].Receivers[0].Integrations[0]
+		require.NotEmpty(t, integration.UID)
+		require.Equal(t, "slack", integration.Type)
+		require.Contains(t, integration.SecureSettings, "token")
+	})
+
+	t.Run("redacted secure settings of existing contact points are replaced with their stored values", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		created.Settings.Set("token", definitions.RedactedValue)
+
+		_, err = sut.TestContactPoint(ctx, 1, created, nil)
+		require.NoError(t, err)
+
+		integration := sut.tester.(*FakeReceiverTester).Calls[0].Receivers[0].Integrations[0]
+		require.Equal(t, created.UID, integration.UID)
+		encrypted, err := base64.StdEncoding.DecodeString(integration.SecureSettings["token"])
+		require.NoError(t, err)
+		decrypted, err := secretsService.Decrypt(ctx, encrypted)
+		require.NoError(t, err)
+		require.Equal(t, "value_token", string(decrypted))
+	})
+
+	t.Run("failed deliveries are reported in the result", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.tester = &FakeReceiverTester{IntegrationErr: alertingNotify.IntegrationTimeoutError{Err: errors.New("context deadline exceeded")}}
+
+		result, err := sut.TestContactPoint(ctx, 1, createTestContactPoint(), nil)
+		require.NoError(t, err)
+		require.Equal(t, "failed", result.Status)
+		require.Equal(t, 408, result.StatusCode)
+		require.Contains(t, result.Error, "context deadline exceeded")
+	})
+
+	t.Run("invalid contact points are rejected without sending a notification", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		cp.Type = "unknown"
+
+		_, err := sut.TestContactPoint(ctx, 1, cp, nil)
+		require.ErrorIs(t, err, ErrValidation)
+		require.Empty(t, sut.tester.(*FakeReceiverTester).Calls)
+	})
+}
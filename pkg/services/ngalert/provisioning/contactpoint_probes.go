@@ -0,0 +1,174 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/secretrefs"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// probeTimeout is how long a reachability check waits for the endpoint of a contact point to answer.
+const probeTimeout = 10 * time.Second
+
+// probeEndpoints returns the endpoint of a contact point of the types that can be probed, from its decrypted settings.
+var probeEndpoints = map[string]func(settings *simplejson.Json) string{
+	"webhook": func(settings *simplejson.Json) string {
+		return settings.Get("url").MustString()
+	},
+	"slack": func(settings *simplejson.Json) string {
+		if url := settings.Get("url").MustString(); url != "" {
+			return url
+		}
+		return settings.Get("endpointUrl").MustString("https://slack.com/api/chat.postMessage")
+	},
+	"pagerduty": func(settings *simplejson.Json) string {
+		return settings.Get("url").MustString("https://events.pagerduty.com/v2/enqueue")
+	},
+}
+
+// ContactPointProbe is the result of the last reachability check of the endpoint of a contact point.
+type ContactPointProbe struct {
+	// At is when the endpoint was checked.
+	At time.Time
+	// Error is why the endpoint could not be reached, empty if it was reached.
+	Error string
+}
+
+// contactPointProbes checks whether the endpoints of contact points can be reached, and keeps the result of the last
+// check per organization and contact point UID.
+type contactPointProbes struct {
+	client  *http.Client
+	mtx     sync.RWMutex
+	results map[int64]map[string]ContactPointProbe
+}
+
+func newContactPointProbes(client *http.Client) *contactPointProbes {
+	return &contactPointProbes{
+		client:  client,
+		results: make(map[int64]map[string]ContactPointProbe),
+	}
+}
+
+// get returns a copy of the results of the last checks of the organization, by contact point UID.
+func (p *contactPointProbes) get(orgID int64) map[string]ContactPointProbe {
+	if p == nil {
+		return nil
+	}
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+	result := make(map[string]ContactPointProbe, len(p.results[orgID]))
+	for uid, probe := range p.results[orgID] {
+		result[uid] = probe
+	}
+	return result
+}
+
+// set replaces the results of the organization, so that the results of contact points that were removed or are no
+// longer probed are dropped.
+func (p *contactPointProbes) set(orgID int64, results map[string]ContactPointProbe) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	if len(results) == 0 {
+		delete(p.results, orgID)
+		return
+	}
+	p.results[orgID] = results
+}
+
+// probe sends a HEAD request to the endpoint. The endpoint is reachable if it answers with anything but a server
+// error, since endpoints that only accept notifications commonly reject other requests.
+func (p *contactPointProbes) probe(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ProbeContactPoints checks whether the endpoints of the contact points of all organizations that opted in can be
+// reached, so that broken endpoints are reported in the contact point status before a notification needs them.
+func (ecp *ContactPointService) ProbeContactPoints(ctx context.Context, now time.Time) error {
+	if ecp.probes == nil || ecp.orgs == nil {
+		return nil
+	}
+	orgIDs, err := ecp.orgs.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, orgID := range orgIDs {
+		if err := ecp.probeContactPoints(ctx, orgID, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to probe the contact points of organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ecp *ContactPointService) probeContactPoints(ctx context.Context, orgID int64, now time.Time) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "ProbeContactPoints", orgID)
+	defer func() { done(err) }()
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		ecp.probes.set(orgID, nil)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var uids []string
+	for _, receiver := range revision.receivers().all() {
+		if receiver.Probe && !receiver.Disabled {
+			uids = append(uids, receiver.UID)
+		}
+	}
+	sort.Strings(uids)
+	results := make(map[string]ContactPointProbe, len(uids))
+	for _, uid := range uids {
+		probe := ContactPointProbe{At: now}
+		if err := ecp.probeContactPoint(ctx, revision, uid); err != nil {
+			probe.Error = err.Error()
+			ecp.log.FromContext(ctx).Debug("Contact point endpoint cannot be reached", "org", orgID, "uid", uid, "error", err)
+		}
+		results[uid] = probe
+	}
+	ecp.probes.set(orgID, results)
+	return nil
+}
+
+func (ecp *ContactPointService) probeContactPoint(ctx context.Context, revision *cfgRevision, uid string) error {
+	contactPoint, err := ecp.getContactPointDecrypted(revision, uid)
+	if err != nil {
+		return err
+	}
+	endpoint, ok := probeEndpoints[contactPoint.Type]
+	if !ok {
+		return fmt.Errorf("contact points of type '%s' cannot be probed", contactPoint.Type)
+	}
+	url := endpoint(contactPoint.Settings)
+	if secretrefs.IsExternal(url) {
+		if url, err = ecp.secretRefs.Resolve(url); err != nil {
+			return err
+		}
+	}
+	if url == "" {
+		return errors.New("the contact point has no endpoint")
+	}
+	return ecp.probes.probe(ctx, url)
+}
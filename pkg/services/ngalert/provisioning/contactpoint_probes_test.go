@@ -0,0 +1,104 @@
+package provisioning
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestProbeContactPoints(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	webhook := func(url string, probe bool) definitions.EmbeddedContactPoint {
+		return definitions.EmbeddedContactPoint{
+			Name:     "webhook",
+			Type:     "webhook",
+			Settings: simplejson.NewFromAny(map[string]any{"url": url}),
+			Probe:    probe,
+		}
+	}
+
+	t.Run("endpoints of probed contact points are checked", func(t *testing.T) {
+		healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, http.MethodHead, r.Method)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}))
+		t.Cleanup(healthy.Close)
+		broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		t.Cleanup(broken.Close)
+		unreachable := httptest.NewServer(http.NotFoundHandler())
+		unreachable.Close()
+
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.orgs = fakeOrgStore{orgs: []int64{1}}
+		reachable, err := sut.CreateContactPoint(ctx, 1, webhook(healthy.URL, true), models.ProvenanceAPI)
+		require.NoError(t, err)
+		failing, err := sut.CreateContactPoint(ctx, 1, webhook(broken.URL, true), models.ProvenanceAPI)
+		require.NoError(t, err)
+		down, err := sut.CreateContactPoint(ctx, 1, webhook(unreachable.URL, true), models.ProvenanceAPI)
+		require.NoError(t, err)
+		notProbed, err := sut.CreateContactPoint(ctx, 1, webhook(broken.URL, false), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		now := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		require.NoError(t, sut.ProbeContactPoints(ctx, now))
+
+		statuses, err := sut.GetContactPointStatus(ctx, 1)
+		require.NoError(t, err)
+		byUID := make(map[string]definitions.ContactPointStatus, len(statuses))
+		for _, status := range statuses {
+			byUID[status.UID] = status
+		}
+		require.Equal(t, now, *byUID[reachable.UID].LastProbe)
+		require.Empty(t, byUID[reachable.UID].LastProbeError)
+		require.Equal(t, now, *byUID[failing.UID].LastProbe)
+		require.Equal(t, "unexpected status code 502", byUID[failing.UID].LastProbeError)
+		require.NotEmpty(t, byUID[down.UID].LastProbeError)
+		require.Nil(t, byUID[notProbed.UID].LastProbe)
+	})
+
+	t.Run("results of contact points that are no longer probed are dropped", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		t.Cleanup(server.Close)
+
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.orgs = fakeOrgStore{orgs: []int64{1}}
+		created, err := sut.CreateContactPoint(ctx, 1, webhook(server.URL, true), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, sut.ProbeContactPoints(ctx, time.Now()))
+		require.Contains(t, sut.probes.get(1), created.UID)
+
+		created.Probe = false
+		require.NoError(t, sut.UpdateContactPoint(ctx, 1, created, models.ProvenanceAPI, UpdateContactPointOptions{}))
+		require.NoError(t, sut.ProbeContactPoints(ctx, time.Now()))
+		require.Empty(t, sut.probes.get(1))
+	})
+
+	t.Run("contact points of types that cannot be probed are rejected", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		cp := definitions.EmbeddedContactPoint{
+			Name:     "email",
+			Type:     "email",
+			Settings: simplejson.NewFromAny(map[string]any{"addresses": "test@example.com"}),
+			Probe:    true,
+		}
+		_, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "cannot be probed")
+	})
+}
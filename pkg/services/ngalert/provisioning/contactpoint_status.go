@@ -0,0 +1,234 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// IntegrationStatusReader reads the delivery status of the integrations of the Alertmanager of an organization. It
+// returns no statuses for an organization whose Alertmanager is not running.
+type IntegrationStatusReader interface {
+	GetIntegrationStatus(ctx context.Context, orgID int64) (map[string]models.IntegrationStatus, error)
+	GetAllIntegrationStatus(ctx context.Context) map[int64]map[string]models.IntegrationStatus
+}
+
+// GetContactPointStatus returns the delivery status of the contact points of the organization, ordered by name and
+// UID. Contact points that have not tried to send a notification since the Alertmanager started, or whose
+// Alertmanager is not running, are reported without a last attempt. Contact points that are probed are reported with
+// the result of the last check of their endpoint.
+func (ecp *ContactPointService) GetContactPointStatus(ctx context.Context, orgID int64) (_ []apimodels.ContactPointStatus, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "GetContactPointStatus", orgID)
+	defer func() { done(err) }()
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return nil, err
+	}
+	statuses, err := ecp.integrationStatus(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	probes := ecp.probes.get(orgID)
+	receivers := revision.receivers().all()
+	result := make([]apimodels.ContactPointStatus, 0, len(receivers))
+	for _, receiver := range receivers {
+		status := apimodels.ContactPointStatus{
+			UID:      receiver.UID,
+			Name:     receiver.Name,
+			Type:     receiver.Type,
+			Disabled: receiver.Disabled,
+		}
+		if s, ok := statuses[receiver.UID]; ok {
+			lastAttempt := s.LastNotifyAttempt
+			status.LastNotifyAttempt = &lastAttempt
+			status.LastNotifyAttemptDuration = s.LastNotifyAttemptDuration.String()
+			status.LastNotifyAttemptError = s.LastNotifyAttemptError
+			status.ConsecutiveFailures = s.ConsecutiveFailures
+		}
+		if p, ok := probes[receiver.UID]; ok {
+			lastProbe := p.At
+			status.LastProbe = &lastProbe
+			status.LastProbeError = p.Error
+		}
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].UID < result[j].UID
+	})
+	return result, nil
+}
+
+func (ecp *ContactPointService) integrationStatus(ctx context.Context, orgID int64) (map[string]models.IntegrationStatus, error) {
+	if ecp.status == nil {
+		return nil, nil
+	}
+	return ecp.status.GetIntegrationStatus(ctx, orgID)
+}
+
+// DisableFailingContactPoints disables the contact points of all organizations that failed to send notifications for
+// at least the given number of consecutive attempts, so that a broken integration is reported instead of losing
+// notifications silently. Disabling a contact point is recorded in the audit log, with the error of the last attempt
+// as source, and is published as a provisioning change event. A threshold of zero disables nothing.
+func (ecp *ContactPointService) DisableFailingContactPoints(ctx context.Context, threshold int) error {
+	if ecp.status == nil || threshold <= 0 {
+		return nil
+	}
+	var errs []error
+	for orgID, statuses := range ecp.status.GetAllIntegrationStatus(ctx) {
+		uids := make([]string, 0, len(statuses))
+		for uid, status := range statuses {
+			if status.ConsecutiveFailures >= threshold {
+				uids = append(uids, uid)
+			}
+		}
+		sort.Strings(uids)
+		for _, uid := range uids {
+			err := updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+				return ecp.disableContactPoint(ctx, orgID, uid, statuses[uid])
+			})
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to disable contact point '%s' of organization %d: %w", uid, orgID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (ecp *ContactPointService) disableContactPoint(ctx context.Context, orgID int64, uid string, status models.IntegrationStatus) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "DisableContactPoint", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	ctx = WithSource(ctx, fmt.Sprintf("%d consecutive failed notification attempts, last error: %s", status.ConsecutiveFailures, status.LastNotifyAttemptError))
+	changed, _, _, err := ecp.setContactPointDisabled(ctx, orgID, uid, true)
+	if err != nil {
+		return err
+	}
+	if changed {
+		ecp.log.FromContext(ctx).Warn("Disabled contact point that failed to send notifications", "org", orgID, "uid", uid,
+			"consecutiveFailures", status.ConsecutiveFailures, "lastError", status.LastNotifyAttemptError)
+	}
+	return nil
+}
+
+// DisableIntegration disables a contact point, which is a single integration of its receiver, without deleting it. The
+// other integrations of the receiver keep sending notifications, and the settings of the contact point, including its
+// encrypted secure settings, are kept until it is enabled again with EnableContactPoint. Contact points that are
+// disabled already are left as they are.
+func (ecp *ContactPointService) DisableIntegration(ctx context.Context, orgID int64, uid string) (result apimodels.EmbeddedContactPoint, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = ecp.disableIntegration(ctx, orgID, uid)
+		return err
+	})
+	return result, err
+}
+
+func (ecp *ContactPointService) disableIntegration(ctx context.Context, orgID int64, uid string) (_ apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "DisableIntegration", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	if err := ecp.authorizeContactPointWrite(ctx, uid); err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	_, receiver, provenance, err := ecp.setContactPointDisabled(ctx, orgID, uid, true)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	result, err := ecp.redactedContactPoint(ctx, receiver)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	result.Provenance = string(provenance)
+	return result, nil
+}
+
+// EnableContactPoint enables a contact point that was disabled with DisableIntegration, or because it failed to send
+// notifications too many times in a row. Contact points that are enabled already are left as they are.
+func (ecp *ContactPointService) EnableContactPoint(ctx context.Context, orgID int64, uid string) (result apimodels.EmbeddedContactPoint, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = ecp.enableContactPoint(ctx, orgID, uid)
+		return err
+	})
+	return result, err
+}
+
+func (ecp *ContactPointService) enableContactPoint(ctx context.Context, orgID int64, uid string) (_ apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "EnableContactPoint", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	if err := ecp.authorizeContactPointWrite(ctx, uid); err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	_, receiver, provenance, err := ecp.setContactPointDisabled(ctx, orgID, uid, false)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	result, err := ecp.redactedContactPoint(ctx, receiver)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	result.Provenance = string(provenance)
+	return result, nil
+}
+
+// setContactPointDisabled disables or enables a contact point and returns whether it was changed, along with its
+// receiver and provenance. The provenance of the contact point is kept.
+func (ecp *ContactPointService) setContactPointDisabled(ctx context.Context, orgID int64, uid string, disabled bool) (bool, *apimodels.PostableGrafanaReceiver, models.Provenance, error) {
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return false, nil, "", err
+	}
+	loc, ok := revision.receivers().receiver(uid)
+	if !ok {
+		return false, nil, "", newNotFoundError((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid, "contact point with uid '%s' not found", uid)
+	}
+	target := &apimodels.EmbeddedContactPoint{UID: uid}
+	if loc.receiver.Disabled == disabled {
+		provenance, err := ecp.provenanceStore.GetProvenance(ctx, target, orgID)
+		if err != nil {
+			return false, nil, "", err
+		}
+		return false, loc.receiver, provenance, nil
+	}
+	oldReceiver := redactedReceiver(loc.receiver)
+	loc.receiver.Disabled = disabled
+	data, err := json.Marshal(revision.cfg)
+	if err != nil {
+		return false, nil, "", err
+	}
+	action := models.ProvisioningAuditActionEnable
+	if disabled {
+		action = models.ProvisioningAuditActionDisable
+	}
+	var provenance models.Provenance
+	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		provenance, err = ecp.provenanceStore.GetProvenance(ctx, target, orgID)
+		if err != nil {
+			return err
+		}
+		err = PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		})
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, ecp.provenanceStore, orgID, action, target, provenance, oldReceiver, redactedReceiver(loc.receiver))
+	})
+	if err != nil {
+		return false, nil, "", err
+	}
+	return true, loc.receiver, provenance, nil
+}
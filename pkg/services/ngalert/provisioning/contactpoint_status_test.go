@@ -0,0 +1,192 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestGetContactPointStatus(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("contact points are joined with the delivery status of their integration", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		lastAttempt := time.Date(2023, 9, 1, 12, 0, 0, 0, time.UTC)
+		sut.status = &FakeIntegrationStatusReader{Statuses: map[int64]map[string]models.IntegrationStatus{
+			1: {
+				created.UID: {
+					LastNotifyAttempt:         lastAttempt,
+					LastNotifyAttemptDuration: 1500 * time.Millisecond,
+					LastNotifyAttemptError:    "channel_not_found",
+					ConsecutiveFailures:       3,
+				},
+			},
+		}}
+
+		statuses, err := sut.GetContactPointStatus(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, statuses, 2)
+		idx := -1
+		for i, status := range statuses {
+			if status.UID == created.UID {
+				idx = i
+				continue
+			}
+			require.Nil(t, status.LastNotifyAttempt)
+			require.Zero(t, status.ConsecutiveFailures)
+		}
+		require.NotEqual(t, -1, idx)
+		status := statuses[idx]
+		require.Equal(t, created.Name, status.Name)
+		require.Equal(t, "slack", status.Type)
+		require.Equal(t, lastAttempt, *status.LastNotifyAttempt)
+		require.Equal(t, "1.5s", status.LastNotifyAttemptDuration)
+		require.Equal(t, "channel_not_found", status.LastNotifyAttemptError)
+		require.Equal(t, 3, status.ConsecutiveFailures)
+	})
+
+	t.Run("contact points are reported without delivery status when the Alertmanager is not running", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.status = &FakeIntegrationStatusReader{}
+
+		statuses, err := sut.GetContactPointStatus(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, statuses, 1)
+	})
+}
+
+func TestDisableFailingContactPoints(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("contact points that failed too many times are disabled until they are enabled", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		failing, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		flaky, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		sut.status = &FakeIntegrationStatusReader{Statuses: map[int64]map[string]models.IntegrationStatus{
+			1: {
+				failing.UID: {LastNotifyAttemptError: "channel_not_found", ConsecutiveFailures: 5},
+				flaky.UID:   {LastNotifyAttemptError: "timeout", ConsecutiveFailures: 2},
+			},
+		}}
+
+		require.NoError(t, sut.DisableFailingContactPoints(ctx, 5))
+
+		statuses, err := sut.GetContactPointStatus(ctx, 1)
+		require.NoError(t, err)
+		for _, status := range statuses {
+			require.Equal(t, status.UID == failing.UID, status.Disabled, status.UID)
+		}
+		entries := sut.provenanceStore.(*fakeProvisioningStore).auditEntries
+		last := entries[len(entries)-1]
+		require.Equal(t, models.ProvisioningAuditActionDisable, last.Action)
+		require.Equal(t, failing.UID, last.ResourceID)
+		require.Equal(t, "5 consecutive failed notification attempts, last error: channel_not_found", last.Source)
+
+		// Updates keep the contact point disabled.
+		failing.DisableResolveMessage = true
+		require.NoError(t, sut.UpdateContactPoint(ctx, 1, failing, models.ProvenanceAPI, UpdateContactPointOptions{}))
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		cp, err := sut.getContactPointDecrypted(revision, failing.UID)
+		require.NoError(t, err)
+		require.True(t, cp.Disabled)
+
+		// Disabling it again does not record anything.
+		count := len(sut.provenanceStore.(*fakeProvisioningStore).auditEntries)
+		require.NoError(t, sut.DisableFailingContactPoints(ctx, 5))
+		require.Len(t, sut.provenanceStore.(*fakeProvisioningStore).auditEntries, count)
+
+		enabled, err := sut.EnableContactPoint(ctx, 1, failing.UID)
+		require.NoError(t, err)
+		require.False(t, enabled.Disabled)
+		require.Equal(t, string(models.ProvenanceAPI), enabled.Provenance)
+		entries = sut.provenanceStore.(*fakeProvisioningStore).auditEntries
+		require.Equal(t, models.ProvisioningAuditActionEnable, entries[len(entries)-1].Action)
+	})
+
+	t.Run("nothing is disabled when the threshold is zero", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		sut.status = &FakeIntegrationStatusReader{Statuses: map[int64]map[string]models.IntegrationStatus{
+			1: {created.UID: {LastNotifyAttemptError: "channel_not_found", ConsecutiveFailures: 100}},
+		}}
+
+		require.NoError(t, sut.DisableFailingContactPoints(ctx, 0))
+
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		cp, err := sut.getContactPointDecrypted(revision, created.UID)
+		require.NoError(t, err)
+		require.False(t, cp.Disabled)
+	})
+
+	t.Run("enabling an unknown contact point fails", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		_, err := sut.EnableContactPoint(ctx, 1, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestDisableIntegration(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("integrations are disabled without losing their settings or disabling the rest of the receiver", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		first, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		second, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		disabled, err := sut.DisableIntegration(ctx, 1, first.UID)
+		require.NoError(t, err)
+		require.True(t, disabled.Disabled)
+		require.Equal(t, string(models.ProvenanceAPI), disabled.Provenance)
+		entries := sut.provenanceStore.(*fakeProvisioningStore).auditEntries
+		require.Equal(t, models.ProvisioningAuditActionDisable, entries[len(entries)-1].Action)
+		require.Equal(t, first.UID, entries[len(entries)-1].ResourceID)
+
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		cp, err := sut.getContactPointDecrypted(revision, first.UID)
+		require.NoError(t, err)
+		require.True(t, cp.Disabled)
+		require.Equal(t, "value_token", cp.Settings.Get("token").MustString())
+		cp, err = sut.getContactPointDecrypted(revision, second.UID)
+		require.NoError(t, err)
+		require.False(t, cp.Disabled)
+
+		// Disabling it again does not record anything.
+		count := len(sut.provenanceStore.(*fakeProvisioningStore).auditEntries)
+		_, err = sut.DisableIntegration(ctx, 1, first.UID)
+		require.NoError(t, err)
+		require.Len(t, sut.provenanceStore.(*fakeProvisioningStore).auditEntries, count)
+
+		enabled, err := sut.EnableContactPoint(ctx, 1, first.UID)
+		require.NoError(t, err)
+		require.False(t, enabled.Disabled)
+	})
+
+	t.Run("disabling an unknown integration fails", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		_, err := sut.DisableIntegration(ctx, 1, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
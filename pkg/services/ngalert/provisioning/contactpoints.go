@@ -2,21 +2,30 @@ package provisioning
 
 import (
 	"context"
+	"crypto/md5"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"path"
 	"sort"
 	"strings"
+	"time"
 
 	alertingNotify "github.com/grafana/alerting/notify"
-	"github.com/prometheus/alertmanager/config"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
 	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier/channels_config"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/secretrefs"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/user"
 	"github.com/grafana/grafana/pkg/util"
@@ -25,30 +34,94 @@ import (
 type ContactPointService struct {
 	amStore           AMConfigStore
 	encryptionService secrets.Service
+	secretRefs        *secretrefs.Resolver
 	provenanceStore   ProvisioningStore
+	ruleStore         RuleStore
+	expirations       *ContactPointExpirationStore
+	deleted           *DeletedContactPointStore
+	variables         *ProvisioningVariablesService
+	tester            ReceiverTester
+	status            IntegrationStatusReader
+	orgs              store.OrgStore
+	probes            *contactPointProbes
 	xact              TransactionManager
+	quotas            QuotaChecker
 	log               log.Logger
 	ac                accesscontrol.AccessControl
+	tracer            tracing.Tracer
+	metrics           *metrics.Provisioning
+	cache             *contactPointCache
 }
 
-func NewContactPointService(store AMConfigStore, encryptionService secrets.Service,
-	provenanceStore ProvisioningStore, xact TransactionManager, log log.Logger, ac accesscontrol.AccessControl) *ContactPointService {
+func NewContactPointService(store AMConfigStore, encryptionService secrets.Service, secretRefs *secretrefs.Resolver,
+	provenanceStore ProvisioningStore, ruleStore RuleStore, expirations *ContactPointExpirationStore, deleted *DeletedContactPointStore,
+	variables *ProvisioningVariablesService, tester ReceiverTester, status IntegrationStatusReader, probeClient *http.Client, orgs store.OrgStore, xact TransactionManager, quotas QuotaChecker, log log.Logger, ac accesscontrol.AccessControl, tracer tracing.Tracer, m *metrics.Provisioning) *ContactPointService {
+	cache := newContactPointCache(m)
 	return &ContactPointService{
-		amStore:           store,
-		encryptionService: encryptionService,
+		amStore:           invalidatingAMConfigStore{AMConfigStore: newTracedAMConfigStore(store, tracer, log, m), cache: cache},
+		encryptionService: newTracedSecretsService(encryptionService, tracer),
+		secretRefs:        secretRefs,
 		provenanceStore:   provenanceStore,
+		ruleStore:         ruleStore,
+		expirations:       expirations,
+		deleted:           deleted,
+		variables:         variables,
+		tester:            tester,
+		status:            status,
+		orgs:              orgs,
+		probes:            newContactPointProbes(probeClient),
 		xact:              xact,
+		quotas:            quotas,
 		log:               log,
 		ac:                ac,
+		tracer:            tracer,
+		metrics:           m,
+		cache:             cache,
 	}
 }
 
 type ContactPointQuery struct {
 	// Optionally filter by name.
-	Name  string
+	Name string
+	// Optionally filter by name patterns in the syntax of path.Match, for example team-a-*. Contact points whose name
+	// matches any of the patterns are returned.
+	NamePatterns []string
+	// Optionally filter by integration type, for example slack or pagerduty.
+	Types []string
 	OrgID int64
-	// Optionally decrypt secure settings, requires OrgAdmin.
+	// Optionally decrypt secure settings, requires the alert.provisioning.secrets:read permission. Every contact point
+	// whose secure settings are decrypted is recorded in the audit log.
 	Decrypt bool
+	// Optionally mask secure settings so that only their last characters are shown, instead of replacing them with
+	// RedactedValue. Requires the alert.provisioning.secrets.masked:read or alert.provisioning.secrets:read permission,
+	// and cannot be combined with Decrypt.
+	Mask bool
+	// Optionally order by one of the ContactPointSortBy fields. Contact points are ordered by name by default.
+	SortBy ContactPointSortBy
+	// Optionally skip the first contact points of the order.
+	Offset int
+	// Optionally limit the number of returned contact points. Zero means no limit.
+	Limit int
+}
+
+// ContactPointSortBy is a field by which contact points are ordered. Contact points with equal fields and the same
+// name are ordered by their Order, other ties are broken by UID.
+type ContactPointSortBy string
+
+const (
+	ContactPointSortByName ContactPointSortBy = "name"
+	ContactPointSortByType ContactPointSortBy = "type"
+	ContactPointSortByUID  ContactPointSortBy = "uid"
+)
+
+// UpdateContactPointOptions controls how an update of a contact point affects the rest of the configuration.
+type UpdateContactPointOptions struct {
+	// CascadeRename renames all integrations that share the name of the contact point along with it, and updates the
+	// notification policies that use the old name. Otherwise, a renamed integration is moved out of its contact point.
+	CascadeRename bool
+	// Version optionally is the version of the contact point the update is based on. The update fails with
+	// ErrVersionConflict if the contact point was changed since.
+	Version string
 }
 
 func (ecp *ContactPointService) canDecryptSecrets(ctx context.Context, u *user.SignedInUser) bool {
@@ -57,135 +130,475 @@ func (ecp *ContactPointService) canDecryptSecrets(ctx context.Context, u *user.S
 	}
 	permitted, err := ecp.ac.Evaluate(ctx, u, accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets))
 	if err != nil {
-		ecp.log.Error("Failed to evaluate user permissions", "error", err)
+		ecp.log.FromContext(ctx).Error("Failed to evaluate user permissions", "error", err)
 		permitted = false
 	}
 	return permitted
 }
 
-// GetContactPoints returns contact points. If q.Decrypt is true and the user is an OrgAdmin, decrypted secure settings are included instead of redacted ones.
-func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactPointQuery, u *user.SignedInUser) ([]apimodels.EmbeddedContactPoint, error) {
-	if q.Decrypt && !ecp.canDecryptSecrets(ctx, u) {
-		return nil, fmt.Errorf("%w: user requires Admin role or alert.provisioning.secrets:read permission to view decrypted secure settings", ErrPermissionDenied)
+// canMaskSecrets reports whether the user is allowed to see the last characters of secure settings.
+func (ecp *ContactPointService) canMaskSecrets(ctx context.Context, u *user.SignedInUser) bool {
+	if u == nil {
+		return false
 	}
-	revision, err := getLastConfiguration(ctx, q.OrgID, ecp.amStore)
+	return ecp.evaluate(ctx, u, accesscontrol.EvalAny(
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadMaskedSecrets),
+		accesscontrol.EvalPermission(accesscontrol.ActionAlertingProvisioningReadSecrets),
+	))
+}
+
+// GetContactPoints returns the contact points that the user is allowed to read. If q.Decrypt is true and the user is allowed to read secrets, decrypted secure settings are included instead of redacted ones.
+func (ecp *ContactPointService) GetContactPoints(ctx context.Context, q ContactPointQuery, u *user.SignedInUser) (_ []apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "GetContactPoints", q.OrgID)
+	defer func() { done(err) }()
+	contactPoints, _, err := ecp.getContactPoints(ctx, q, u)
+	return contactPoints, err
+}
+
+// GetContactPointsPage returns the contact points selected by the query along with the number of contact points that
+// match the query before the offset and limit are applied.
+func (ecp *ContactPointService) GetContactPointsPage(ctx context.Context, q ContactPointQuery, u *user.SignedInUser) (_ []apimodels.EmbeddedContactPoint, total int, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "GetContactPointsPage", q.OrgID)
+	defer func() { done(err) }()
+	return ecp.getContactPoints(ctx, q, u)
+}
+
+// StreamContactPoints selects the contact points of the query like GetContactPointsPage, but returns a stream that
+// converts them one at a time instead of the converted contact points. This spares holding the settings of all
+// contact points in memory at once when large lists are written to a response.
+func (ecp *ContactPointService) StreamContactPoints(ctx context.Context, q ContactPointQuery, u *user.SignedInUser) (_ *ContactPointStream, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "StreamContactPoints", q.OrgID)
+	defer func() { done(err) }()
+	return ecp.streamContactPoints(ctx, q, u)
+}
+
+func (ecp *ContactPointService) getContactPoints(ctx context.Context, q ContactPointQuery, u *user.SignedInUser) ([]apimodels.EmbeddedContactPoint, int, error) {
+	stream, err := ecp.streamContactPoints(ctx, q, u)
+	if err != nil {
+		return nil, 0, err
+	}
+	contactPoints := make([]apimodels.EmbeddedContactPoint, 0, stream.Len())
+	err = stream.Each(ctx, func(cp apimodels.EmbeddedContactPoint) error {
+		contactPoints = append(contactPoints, cp)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return contactPoints, stream.Total, nil
+}
+
+func (ecp *ContactPointService) streamContactPoints(ctx context.Context, q ContactPointQuery, u *user.SignedInUser) (*ContactPointStream, error) {
+	if q.Offset < 0 || q.Limit < 0 {
+		field := "offset"
+		if q.Limit < 0 {
+			field = "limit"
+		}
+		return nil, newValidationError(field, "offset and limit must not be negative")
+	}
+	for _, pattern := range q.NamePatterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, newValidationError("namePattern", "invalid name pattern '%s': %s", pattern, err)
+		}
+	}
+	if err := validateContactPointSortBy(q.SortBy); err != nil {
+		return nil, err
+	}
+	if q.Decrypt && q.Mask {
+		return nil, newValidationError("mask", "secure settings cannot be both decrypted and masked")
+	}
+	if q.Decrypt {
+		allowed := ecp.canDecryptSecrets(ctx, u)
+		countDecryptRequest(ecp.metrics, "contactPoint", q.OrgID, allowed)
+		if !allowed {
+			return nil, fmt.Errorf("%w: user requires Admin role or alert.provisioning.secrets:read permission to view decrypted secure settings", ErrPermissionDenied)
+		}
+	}
+	if q.Mask && !ecp.canMaskSecrets(ctx, u) {
+		return nil, fmt.Errorf("%w: user requires alert.provisioning.secrets.masked:read or alert.provisioning.secrets:read permission to view masked secure settings", ErrPermissionDenied)
+	}
+	entry, err := ecp.cache.get(ctx, q.OrgID, ecp.amStore)
 	if err != nil {
 		return nil, err
 	}
+	revision := entry.revision
 	provenances, err := ecp.provenanceStore.GetProvenances(ctx, q.OrgID, "contactPoint")
 	if err != nil {
 		return nil, err
 	}
-	contactPoints := []apimodels.EmbeddedContactPoint{}
-	for _, contactPoint := range revision.cfg.GetGrafanaReceiverMap() {
-		if q.Name != "" && contactPoint.Name != q.Name {
-			continue
+	expirations, err := ecp.expirations.GetExpirations(ctx, q.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	receivers := revision.receivers().all()
+	if q.Name != "" {
+		receivers = revision.receivers().namedReceivers(q.Name)
+	}
+	if len(q.Types) > 0 {
+		receivers = filterReceiversByType(receivers, q.Types)
+	}
+	if len(q.NamePatterns) > 0 {
+		receivers = filterReceiversByNamePattern(receivers, q.NamePatterns)
+	}
+	receivers = ecp.filterReadableReceivers(ctx, u, receivers)
+	positions := revision.receivers().positions()
+	less := contactPointOrder(q.SortBy, positions)
+	// Receivers are ordered and paged before they are converted, so that only the secure settings of the returned
+	// contact points are decrypted.
+	sort.SliceStable(receivers, func(i, j int) bool {
+		return less(receivers[i], receivers[j])
+	})
+	total := len(receivers)
+	if q.Offset > total {
+		q.Offset = total
+	}
+	receivers = receivers[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(receivers) {
+		receivers = receivers[:q.Limit]
+	}
+	// The decryption is recorded before any contact point is converted, as a streamed response cannot be withdrawn
+	// once its first contact points are written.
+	if q.Decrypt {
+		if err := ecp.recordDecryption(ctx, q.OrgID, u, receivers, provenances); err != nil {
+			return nil, err
 		}
+	}
+	return &ContactPointStream{
+		Total:       total,
+		ecp:         ecp,
+		q:           q,
+		entry:       entry,
+		receivers:   receivers,
+		positions:   positions,
+		provenances: provenances,
+		expirations: expirations,
+	}, nil
+}
+
+// ContactPointStream converts the contact points selected by a query one at a time.
+type ContactPointStream struct {
+	// Total is the number of contact points that match the query before the offset and limit are applied.
+	Total int
+
+	ecp         *ContactPointService
+	q           ContactPointQuery
+	entry       *contactPointCacheEntry
+	receivers   []*apimodels.PostableGrafanaReceiver
+	positions   map[string]int
+	provenances map[string]models.Provenance
+	expirations map[string]time.Time
+}
 
-		simpleJson, err := simplejson.NewJson(contactPoint.Settings)
+// Len returns the number of contact points in the stream.
+func (s *ContactPointStream) Len() int {
+	return len(s.receivers)
+}
+
+// Each converts the contact points of the stream in order and calls fn with each of them. It stops at the first error
+// returned by fn or the conversion.
+func (s *ContactPointStream) Each(ctx context.Context, fn func(apimodels.EmbeddedContactPoint) error) error {
+	for _, receiver := range s.receivers {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		contactPoint, err := s.convert(ctx, receiver)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		embeddedContactPoint := apimodels.EmbeddedContactPoint{
-			UID:                   contactPoint.UID,
-			Type:                  contactPoint.Type,
-			Name:                  contactPoint.Name,
-			DisableResolveMessage: contactPoint.DisableResolveMessage,
-			Settings:              simpleJson,
+		if err := fn(contactPoint); err != nil {
+			return err
 		}
-		if val, exists := provenances[embeddedContactPoint.UID]; exists && val != "" {
-			embeddedContactPoint.Provenance = string(val)
+	}
+	return nil
+}
+
+func (s *ContactPointStream) convert(ctx context.Context, contactPoint *apimodels.PostableGrafanaReceiver) (apimodels.EmbeddedContactPoint, error) {
+	simpleJson, err := simplejson.NewJson(contactPoint.Settings)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	embeddedContactPoint := apimodels.EmbeddedContactPoint{
+		UID:                   contactPoint.UID,
+		Type:                  contactPoint.Type,
+		Name:                  contactPoint.Name,
+		DisableResolveMessage: contactPoint.DisableResolveMessage,
+		Settings:              simpleJson,
+		Disabled:              contactPoint.Disabled,
+		Probe:                 contactPoint.Probe,
+	}
+	if position, exists := s.positions[embeddedContactPoint.UID]; exists {
+		embeddedContactPoint.Order = &position
+	}
+	if val, exists := s.provenances[embeddedContactPoint.UID]; exists && val != "" {
+		embeddedContactPoint.Provenance = string(val)
+	}
+	if expiresAt, exists := s.expirations[embeddedContactPoint.UID]; exists {
+		embeddedContactPoint.ExpiresAt = &expiresAt
+	}
+	cached := s.entry.receiver(contactPoint, func() (cachedReceiver, bool) {
+		return s.ecp.loadReceiver(ctx, contactPoint)
+	})
+	for k, decryptedValue := range cached.secureSettings {
+		switch {
+		case s.q.Decrypt:
+			embeddedContactPoint.Settings.Set(k, decryptedValue)
+		case s.q.Mask:
+			embeddedContactPoint.Settings.Set(k, maskSecret(decryptedValue))
+		default:
+			embeddedContactPoint.Settings.Set(k, apimodels.RedactedValue)
 		}
-		for k, v := range contactPoint.SecureSettings {
-			decryptedValue, err := ecp.decryptValue(v)
-			if err != nil {
-				ecp.log.Warn("Decrypting value failed", "error", err.Error())
-				continue
-			}
-			if decryptedValue == "" {
-				continue
+	}
+	embeddedContactPoint.Warnings = deprecationWarnings(embeddedContactPoint)
+	embeddedContactPoint.Version = cached.version
+	return embeddedContactPoint, nil
+}
+
+// recordDecryption adds an entry for every receiver whose secure settings are returned in clear text to the audit log,
+// so that it is known who read which secrets. The export is not returned if the decryption cannot be recorded.
+func (ecp *ContactPointService) recordDecryption(ctx context.Context, orgID int64, u *user.SignedInUser, receivers []*apimodels.PostableGrafanaReceiver, provenances map[string]models.Provenance) error {
+	if u != nil {
+		ctx = appcontext.WithUser(ctx, u)
+	}
+	for _, r := range receivers {
+		if len(r.SecureSettings) == 0 {
+			continue
+		}
+		resource := &apimodels.EmbeddedContactPoint{UID: r.UID}
+		if err := recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionDecrypt, resource, provenanceOrNone(provenances, r.UID), nil, redactedReceiver(r)); err != nil {
+			return fmt.Errorf("failed to record the decryption of contact point '%s': %w", r.UID, err)
+		}
+	}
+	return nil
+}
+
+// loadReceiver decrypts the secure settings of a stored receiver and computes its version. It reports whether all
+// secure settings could be decrypted.
+func (ecp *ContactPointService) loadReceiver(ctx context.Context, receiver *apimodels.PostableGrafanaReceiver) (cachedReceiver, bool) {
+	loaded := cachedReceiver{
+		secureSettings: make(map[string]string, len(receiver.SecureSettings)),
+		version:        contactPointVersion(receiver),
+	}
+	complete := true
+	for k, v := range receiver.SecureSettings {
+		decryptedValue, err := ecp.decryptValue(v)
+		if err != nil {
+			ecp.log.FromContext(ctx).Warn("Decrypting value failed", "error", err.Error())
+			complete = false
+			continue
+		}
+		if decryptedValue == "" {
+			continue
+		}
+		loaded.secureSettings[k] = decryptedValue
+	}
+	return loaded, complete
+}
+
+// filterReceiversByType returns the receivers whose integration has one of the given types.
+func filterReceiversByType(receivers []*apimodels.PostableGrafanaReceiver, types []string) []*apimodels.PostableGrafanaReceiver {
+	result := make([]*apimodels.PostableGrafanaReceiver, 0, len(receivers))
+	for _, r := range receivers {
+		for _, t := range types {
+			if strings.EqualFold(r.Type, t) {
+				result = append(result, r)
+				break
 			}
-			if q.Decrypt {
-				embeddedContactPoint.Settings.Set(k, decryptedValue)
-			} else {
-				embeddedContactPoint.Settings.Set(k, apimodels.RedactedValue)
+		}
+	}
+	return result
+}
+
+// filterReceiversByNamePattern returns the receivers whose name matches any of the patterns. The patterns must have
+// been validated with path.Match.
+func filterReceiversByNamePattern(receivers []*apimodels.PostableGrafanaReceiver, patterns []string) []*apimodels.PostableGrafanaReceiver {
+	result := make([]*apimodels.PostableGrafanaReceiver, 0, len(receivers))
+	for _, r := range receivers {
+		for _, pattern := range patterns {
+			if ok, _ := path.Match(pattern, r.Name); ok {
+				result = append(result, r)
+				break
 			}
 		}
+	}
+	return result
+}
 
-		contactPoints = append(contactPoints, embeddedContactPoint)
+// validateContactPointSortBy checks that contact points can be ordered by the given field.
+func validateContactPointSortBy(sortBy ContactPointSortBy) error {
+	switch sortBy {
+	case "", ContactPointSortByName, ContactPointSortByType, ContactPointSortByUID:
+		return nil
 	}
-	sort.SliceStable(contactPoints, func(i, j int) bool {
-		switch strings.Compare(contactPoints[i].Name, contactPoints[j].Name) {
-		case -1:
-			return true
-		case 1:
-			return false
+	return newValidationError("sortBy", "contact points cannot be sorted by '%s'", sortBy)
+}
+
+// contactPointOrder returns the order of receivers by the given field, which must have been validated with
+// validateContactPointSortBy. Receivers with the same name are ordered by their position within their receiver group,
+// given by positions, and remaining ties are broken by UID.
+func contactPointOrder(sortBy ContactPointSortBy, positions map[string]int) func(a, b *apimodels.PostableGrafanaReceiver) bool {
+	var field func(r *apimodels.PostableGrafanaReceiver) string
+	switch sortBy {
+	case ContactPointSortByType:
+		field = func(r *apimodels.PostableGrafanaReceiver) string { return r.Type }
+	case ContactPointSortByUID:
+		field = func(r *apimodels.PostableGrafanaReceiver) string { return r.UID }
+	default:
+		field = func(r *apimodels.PostableGrafanaReceiver) string { return r.Name }
+	}
+	return func(a, b *apimodels.PostableGrafanaReceiver) bool {
+		if fa, fb := field(a), field(b); fa != fb {
+			return fa < fb
 		}
-		return contactPoints[i].UID < contactPoints[j].UID
-	})
-	return contactPoints, nil
+		if a.Name == b.Name {
+			if pa, pb := positions[a.UID], positions[b.UID]; pa != pb {
+				return pa < pb
+			}
+		}
+		return a.UID < b.UID
+	}
 }
 
-// getContactPointDecrypted is an internal-only function that gets full contact point info, included encrypted fields.
-// nil is returned if no matching contact point exists.
-func (ecp *ContactPointService) getContactPointDecrypted(ctx context.Context, orgID int64, uid string) (apimodels.EmbeddedContactPoint, error) {
-	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+// getContactPointDecrypted is an internal-only function that gets full contact point info from the given revision,
+// included encrypted fields. An error is returned if no matching contact point exists.
+func (ecp *ContactPointService) getContactPointDecrypted(revision *cfgRevision, uid string) (apimodels.EmbeddedContactPoint, error) {
+	loc, ok := revision.receivers().receiver(uid)
+	if !ok {
+		return apimodels.EmbeddedContactPoint{}, newNotFoundError((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid, "contact point with uid '%s' not found", uid)
+	}
+	receiver := loc.receiver
+	simpleJson, err := simplejson.NewJson(receiver.Settings)
 	if err != nil {
 		return apimodels.EmbeddedContactPoint{}, err
 	}
-	for _, receiver := range revision.cfg.GetGrafanaReceiverMap() {
-		if receiver.UID != uid {
-			continue
-		}
-		simpleJson, err := simplejson.NewJson(receiver.Settings)
+	embeddedContactPoint := apimodels.EmbeddedContactPoint{
+		UID:                   receiver.UID,
+		Type:                  receiver.Type,
+		Name:                  receiver.Name,
+		DisableResolveMessage: receiver.DisableResolveMessage,
+		Settings:              simpleJson,
+		Version:               contactPointVersion(receiver),
+		Disabled:              receiver.Disabled,
+		Probe:                 receiver.Probe,
+	}
+	if position, ok := revision.receivers().position(uid); ok {
+		embeddedContactPoint.Order = &position
+	}
+	for k, v := range receiver.SecureSettings {
+		decryptedValue, err := ecp.decryptValue(v)
 		if err != nil {
-			return apimodels.EmbeddedContactPoint{}, err
-		}
-		embeddedContactPoint := apimodels.EmbeddedContactPoint{
-			UID:                   receiver.UID,
-			Type:                  receiver.Type,
-			Name:                  receiver.Name,
-			DisableResolveMessage: receiver.DisableResolveMessage,
-			Settings:              simpleJson,
+			ecp.log.Warn("Decrypting value failed", "error", err.Error())
+			continue
 		}
-		for k, v := range receiver.SecureSettings {
-			decryptedValue, err := ecp.decryptValue(v)
-			if err != nil {
-				ecp.log.Warn("Decrypting value failed", "error", err.Error())
-				continue
-			}
-			if decryptedValue == "" {
-				continue
-			}
-			embeddedContactPoint.Settings.Set(k, decryptedValue)
+		if decryptedValue == "" {
+			continue
 		}
-		return embeddedContactPoint, nil
+		embeddedContactPoint.Settings.Set(k, decryptedValue)
 	}
-	return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: contact point with uid '%s' not found", ErrNotFound, uid)
+	return embeddedContactPoint, nil
 }
 
 func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID int64,
-	contactPoint apimodels.EmbeddedContactPoint, provenance models.Provenance) (apimodels.EmbeddedContactPoint, error) {
-	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
-		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
-	}
+	contactPoint apimodels.EmbeddedContactPoint, provenance models.Provenance) (result apimodels.EmbeddedContactPoint, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = ecp.createContactPoint(ctx, orgID, contactPoint, provenance)
+		return err
+	})
+	return result, err
+}
 
+func (ecp *ContactPointService) createContactPoint(ctx context.Context, orgID int64,
+	contactPoint apimodels.EmbeddedContactPoint, provenance models.Provenance) (_ apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "CreateContactPoint", orgID,
+		attribute.String("contact_point_type", contactPoint.Type))
+	defer func() { done(err) }()
 	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
 	if err != nil {
 		return apimodels.EmbeddedContactPoint{}, err
 	}
-
-	extractedSecrets, err := RemoveSecretsForContactPoint(&contactPoint)
+	created, err := ecp.addContactPoint(ctx, orgID, revision, contactPoint)
 	if err != nil {
 		return apimodels.EmbeddedContactPoint{}, err
 	}
+	if err := ecp.persistCreatedContactPoints(ctx, orgID, revision, provenance, []createdContactPoint{created}); err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	return created.redacted(), nil
+}
+
+// CreateContactPoints creates all contact points with a single write of the Alertmanager configuration. Either all
+// contact points are created or none.
+func (ecp *ContactPointService) CreateContactPoints(ctx context.Context, orgID int64,
+	contactPoints []apimodels.EmbeddedContactPoint, provenance models.Provenance) (result []apimodels.EmbeddedContactPoint, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = ecp.createContactPoints(ctx, orgID, contactPoints, provenance)
+		return err
+	})
+	return result, err
+}
 
-	for k, v := range extractedSecrets {
-		encryptedValue, err := ecp.encryptValue(v)
+func (ecp *ContactPointService) createContactPoints(ctx context.Context, orgID int64,
+	contactPoints []apimodels.EmbeddedContactPoint, provenance models.Provenance) (_ []apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "CreateContactPoints", orgID,
+		attribute.Int("contact_points", len(contactPoints)))
+	defer func() { done(err) }()
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return nil, err
+	}
+	created := make([]createdContactPoint, 0, len(contactPoints))
+	for i, contactPoint := range contactPoints {
+		c, err := ecp.addContactPoint(ctx, orgID, revision, contactPoint)
 		if err != nil {
-			return apimodels.EmbeddedContactPoint{}, err
+			return nil, fmt.Errorf("contact point %d: %w", i, err)
 		}
-		extractedSecrets[k] = encryptedValue
+		created = append(created, c)
+	}
+	if err := ecp.persistCreatedContactPoints(ctx, orgID, revision, provenance, created); err != nil {
+		return nil, err
+	}
+	result := make([]apimodels.EmbeddedContactPoint, 0, len(created))
+	for _, c := range created {
+		result = append(result, c.redacted())
+	}
+	return result, nil
+}
+
+// createdContactPoint is a contact point added to a configuration revision that is not persisted yet.
+type createdContactPoint struct {
+	contactPoint apimodels.EmbeddedContactPoint
+	receiver     *apimodels.PostableGrafanaReceiver
+	// overridden are the copies of global contact points that the contact point replaces.
+	overridden []*apimodels.PostableGrafanaReceiver
+}
+
+// redacted returns the created contact point with its secure settings redacted.
+func (c createdContactPoint) redacted() apimodels.EmbeddedContactPoint {
+	for k := range c.receiver.SecureSettings {
+		c.contactPoint.Settings.Set(k, apimodels.RedactedValue)
+	}
+	return c.contactPoint
+}
+
+// addContactPoint validates the contact point, encrypts its secure settings and adds it to the revision.
+func (ecp *ContactPointService) addContactPoint(ctx context.Context, orgID int64, revision *cfgRevision, contactPoint apimodels.EmbeddedContactPoint) (createdContactPoint, error) {
+	if err := ecp.resolveVariables(ctx, orgID, &contactPoint); err != nil {
+		return createdContactPoint{}, err
+	}
+	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
+		return createdContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err := validateExpiration(contactPoint); err != nil {
+		return createdContactPoint{}, err
+	}
+
+	extractedSecrets, err := RemoveSecretsForContactPoint(&contactPoint)
+	if err != nil {
+		return createdContactPoint{}, err
+	}
+
+	extractedSecrets, err = ecp.encryptSecrets(ctx, extractedSecrets)
+	if err != nil {
+		return createdContactPoint{}, err
 	}
 
 	if contactPoint.UID == "" {
@@ -194,7 +607,7 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 
 	jsonData, err := contactPoint.Settings.MarshalJSON()
 	if err != nil {
-		return apimodels.EmbeddedContactPoint{}, err
+		return createdContactPoint{}, err
 	}
 
 	grafanaReceiver := &apimodels.PostableGrafanaReceiver{
@@ -204,92 +617,130 @@ func (ecp *ContactPointService) CreateContactPoint(ctx context.Context, orgID in
 		DisableResolveMessage: contactPoint.DisableResolveMessage,
 		Settings:              jsonData,
 		SecureSettings:        extractedSecrets,
+		Probe:                 contactPoint.Probe,
 	}
 
-	receiverFound := false
-	for _, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
-		// check if uid is already used in receiver
-		for _, rec := range receiver.PostableGrafanaReceivers.GrafanaManagedReceivers {
-			if grafanaReceiver.UID == rec.UID {
-				return apimodels.EmbeddedContactPoint{}, fmt.Errorf(
-					"receiver configuration with UID '%s' already exist in contact point '%s'. Please use unique identifiers for receivers across all contact points",
-					rec.UID,
-					rec.Name)
-			}
-		}
-		if receiver.Name == contactPoint.Name {
-			receiver.PostableGrafanaReceivers.GrafanaManagedReceivers = append(receiver.PostableGrafanaReceivers.GrafanaManagedReceivers, grafanaReceiver)
-			receiverFound = true
-		}
+	// check if uid is already used in receiver
+	if existing, ok := revision.receivers().receiver(grafanaReceiver.UID); ok {
+		return createdContactPoint{}, fmt.Errorf(
+			"receiver configuration with UID '%s' already exist in contact point '%s'. Please use unique identifiers for receivers across all contact points",
+			existing.receiver.UID,
+			existing.receiver.Name)
 	}
-
-	if !receiverFound {
-		revision.cfg.AlertmanagerConfig.Receivers = append(revision.cfg.AlertmanagerConfig.Receivers, &apimodels.PostableApiReceiver{
-			Receiver: config.Receiver{
-				Name: grafanaReceiver.Name,
-			},
-			PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
-				GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{grafanaReceiver},
-			},
-		})
+	// A contact point of the organization overrides the global contact points with the same name.
+	overridden, err := ecp.inheritedReceivers(ctx, orgID, revision, grafanaReceiver.Name)
+	if err != nil {
+		return createdContactPoint{}, err
 	}
+	for _, r := range overridden {
+		revision.receivers().remove(r.UID)
+	}
+	revision.receivers().add(grafanaReceiver)
+	if contactPoint.Order != nil {
+		revision.receivers().move(grafanaReceiver.UID, *contactPoint.Order)
+	}
+	if position, ok := revision.receivers().position(grafanaReceiver.UID); ok {
+		contactPoint.Order = &position
+	}
+	return createdContactPoint{contactPoint: contactPoint, receiver: grafanaReceiver, overridden: overridden}, nil
+}
 
-	data, err := json.Marshal(revision.cfg)
+// persistCreatedContactPoints saves the revision the contact points were added to, together with their provenance,
+// expiration and audit entries, in a single transaction.
+func (ecp *ContactPointService) persistCreatedContactPoints(ctx context.Context, orgID int64, revision *cfgRevision, provenance models.Provenance, created []createdContactPoint) error {
+	changes, err := revision.receivers().changes()
 	if err != nil {
-		return apimodels.EmbeddedContactPoint{}, err
+		return err
 	}
 
-	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
-			AlertmanagerConfiguration: string(data),
-			FetchedConfigurationHash:  revision.concurrencyToken,
-			ConfigurationVersion:      revision.version,
-			Default:                   false,
-			OrgID:                     orgID,
-		})
+	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err = persistConfigChanges(ctx, ecp.amStore, orgID, revision, changes)
 		if err != nil {
 			return err
 		}
-		err = ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance)
-		if err != nil {
+		if err := ContactPointQuota.checkTransactionCtx(ctx, ecp.quotas, orgID); err != nil {
 			return err
 		}
-		contactPoint.Provenance = string(provenance)
+		for i := range created {
+			c := &created[i]
+			for _, r := range c.overridden {
+				if err := ecp.provenanceStore.DeleteProvenance(ctx, &apimodels.EmbeddedContactPoint{UID: r.UID}, orgID); err != nil {
+					return err
+				}
+			}
+			err = ecp.provenanceStore.SetProvenance(ctx, &c.contactPoint, orgID, provenance)
+			if err != nil {
+				return err
+			}
+			if err := ecp.expirations.SetExpiration(ctx, orgID, c.contactPoint.UID, c.contactPoint.ExpiresAt); err != nil {
+				return err
+			}
+			c.contactPoint.Provenance = string(provenance)
+			err = recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionCreate, &c.contactPoint, provenance, nil, redactedReceiver(c.receiver))
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	})
-	if err != nil {
-		return apimodels.EmbeddedContactPoint{}, err
-	}
-	for k := range extractedSecrets {
-		contactPoint.Settings.Set(k, apimodels.RedactedValue)
-	}
-	return contactPoint, nil
 }
 
-func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID int64, contactPoint apimodels.EmbeddedContactPoint, provenance models.Provenance) error {
+func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID int64, contactPoint apimodels.EmbeddedContactPoint, provenance models.Provenance, opts UpdateContactPointOptions) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return ecp.updateContactPoint(ctx, orgID, contactPoint, provenance, opts)
+	})
+}
+
+func (ecp *ContactPointService) updateContactPoint(ctx context.Context, orgID int64, contactPoint apimodels.EmbeddedContactPoint, provenance models.Provenance, opts UpdateContactPointOptions) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "UpdateContactPoint", orgID,
+		attribute.String("contact_point_uid", contactPoint.UID), attribute.String("contact_point_type", contactPoint.Type))
+	defer func() { done(err) }()
 	// set all redacted values with the latest known value from the store
 	if contactPoint.Settings == nil {
-		return fmt.Errorf("%w: %s", ErrValidation, "settings should not be empty")
+		return newValidationError("settings", "settings should not be empty")
+	}
+	if err := ecp.authorizeContactPointWrite(ctx, contactPoint.UID); err != nil {
+		return err
 	}
-	rawContactPoint, err := ecp.getContactPointDecrypted(ctx, orgID, contactPoint.UID)
+	// The same revision is used to merge the redacted values and to stitch the receiver back in,
+	// which avoids loading and parsing the configuration twice.
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
 	if err != nil {
 		return err
 	}
+	rawContactPoint, err := ecp.getContactPointDecrypted(revision, contactPoint.UID)
+	if err != nil {
+		return err
+	}
+	if opts.Version != "" && opts.Version != rawContactPoint.Version {
+		return newVersionConflictError((&apimodels.EmbeddedContactPoint{}).ResourceType(), contactPoint.UID, "contact point with uid '%s' is at version '%s'", contactPoint.UID, rawContactPoint.Version)
+	}
+	if err := ecp.authorizeContactPointRename(ctx, revision.receivers(), contactPoint.UID, contactPoint.Name, opts.CascadeRename); err != nil {
+		return err
+	}
 	secretKeys, err := GetSecretKeysForContactPointType(contactPoint.Type)
 	if err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
 	for _, secretKey := range secretKeys {
 		secretValue := contactPoint.Settings.Get(secretKey).MustString()
-		if secretValue == apimodels.RedactedValue {
-			contactPoint.Settings.Set(secretKey, rawContactPoint.Settings.Get(secretKey).MustString())
+		storedValue := rawContactPoint.Settings.Get(secretKey).MustString()
+		// Masked values that are sent back unchanged keep the stored value, like redacted ones.
+		if secretValue == apimodels.RedactedValue || (storedValue != "" && secretValue == maskSecret(storedValue)) {
+			contactPoint.Settings.Set(secretKey, storedValue)
 		}
 	}
 
+	if err := ecp.resolveVariables(ctx, orgID, &contactPoint); err != nil {
+		return err
+	}
 	// validate merged values
 	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
 		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
+	if err := validateExpiration(contactPoint); err != nil {
+		return err
+	}
 
 	// check that provenance is not changed in an invalid way
 	storedProvenance, err := ecp.provenanceStore.GetProvenance(ctx, &contactPoint, orgID)
@@ -304,12 +755,9 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 	if err != nil {
 		return err
 	}
-	for k, v := range extractedSecrets {
-		encryptedValue, err := ecp.encryptValue(v)
-		if err != nil {
-			return err
-		}
-		extractedSecrets[k] = encryptedValue
+	extractedSecrets, err = ecp.encryptSecrets(ctx, extractedSecrets)
+	if err != nil {
+		return err
 	}
 
 	jsonData, err := contactPoint.Settings.MarshalJSON()
@@ -323,30 +771,41 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 		DisableResolveMessage: contactPoint.DisableResolveMessage,
 		Settings:              jsonData,
 		SecureSettings:        extractedSecrets,
+		Probe:                 contactPoint.Probe,
+		// Updates do not enable disabled contact points, they are enabled with EnableContactPoint.
+		Disabled: rawContactPoint.Disabled,
 	}
 	// save to store
-	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
-	if err != nil {
-		return err
+	var oldReceiver *apimodels.PostableGrafanaReceiver
+	oldName := ""
+	if loc, ok := revision.receivers().receiver(mergedReceiver.UID); ok {
+		oldReceiver = redactedReceiver(loc.receiver)
+		oldName = loc.group.Name
 	}
-
-	configModified := stitchReceiver(revision.cfg, mergedReceiver)
+	// The notification settings of alert rules follow a cascading rename like the notification policies do.
+	var ruleUpdates []models.UpdateRule
+	if opts.CascadeRename {
+		if err := renameContactPoint(revision.receivers(), mergedReceiver.UID, mergedReceiver.Name); err != nil {
+			return err
+		}
+		if oldName != "" && oldName != mergedReceiver.Name {
+			ruleUpdates, err = ecp.rulesUsingReceivers(ctx, orgID, []string{oldName}, mergedReceiver.Name)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	configModified := stitchReceiver(revision.receivers(), mergedReceiver, contactPoint.Order)
 	if !configModified {
-		return fmt.Errorf("contact point with uid '%s' not found", mergedReceiver.UID)
+		return newNotFoundError((&apimodels.EmbeddedContactPoint{}).ResourceType(), mergedReceiver.UID, "contact point with uid '%s' not found", mergedReceiver.UID)
 	}
 
-	data, err := json.Marshal(revision.cfg)
+	changes, err := revision.receivers().changes()
 	if err != nil {
 		return err
 	}
 	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
-			AlertmanagerConfiguration: string(data),
-			FetchedConfigurationHash:  revision.concurrencyToken,
-			ConfigurationVersion:      revision.version,
-			Default:                   false,
-			OrgID:                     orgID,
-		})
+		err = persistConfigChanges(ctx, ecp.amStore, orgID, revision, changes)
 		if err != nil {
 			return err
 		}
@@ -354,62 +813,297 @@ func (ecp *ContactPointService) UpdateContactPoint(ctx context.Context, orgID in
 		if err != nil {
 			return err
 		}
+		if err := ecp.expirations.SetExpiration(ctx, orgID, contactPoint.UID, contactPoint.ExpiresAt); err != nil {
+			return err
+		}
+		if err := ecp.applyRuleUpdates(ctx, orgID, ruleUpdates); err != nil {
+			return err
+		}
 		contactPoint.Provenance = string(provenance)
-		return nil
+		return recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &contactPoint, provenance, oldReceiver, redactedReceiver(mergedReceiver))
+	})
+}
+
+// MigrateContactPoint rewrites a contact point that uses a deprecated integration type or settings to their supported
+// successors. It returns the migrated contact point with redacted secure settings.
+func (ecp *ContactPointService) MigrateContactPoint(ctx context.Context, orgID int64, uid string, provenance models.Provenance) (result apimodels.EmbeddedContactPoint, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = ecp.migrateContactPoint(ctx, orgID, uid, provenance)
+		return err
+	})
+	return result, err
+}
+
+func (ecp *ContactPointService) migrateContactPoint(ctx context.Context, orgID int64, uid string, provenance models.Provenance) (_ apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "MigrateContactPoint", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	contactPoint, err := ecp.getContactPointDecrypted(revision, uid)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	migrated, err := migrateContactPoint(&contactPoint)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	if !migrated {
+		return apimodels.EmbeddedContactPoint{}, newValidationError("", "contact point with uid '%s' does not use a deprecated integration type or settings", uid).
+			withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid)
+	}
+	expirations, err := ecp.expirations.GetExpirations(ctx, orgID)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	if expiresAt, ok := expirations[uid]; ok {
+		contactPoint.ExpiresAt = &expiresAt
+	}
+	secretKeys, err := GetSecretKeysForContactPointType(contactPoint.Type)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	var setSecrets []string
+	for _, secretKey := range secretKeys {
+		if contactPoint.Settings.Get(secretKey).MustString() != "" {
+			setSecrets = append(setSecrets, secretKey)
+		}
+	}
+	if err := ecp.UpdateContactPoint(ctx, orgID, contactPoint, provenance, UpdateContactPointOptions{}); err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	// The secrets were removed from the settings when the contact point was stored.
+	for _, secretKey := range setSecrets {
+		contactPoint.Settings.Set(secretKey, apimodels.RedactedValue)
+	}
+	contactPoint.Provenance = string(provenance)
+	return contactPoint, nil
+}
+
+// RotateContactPointSecrets replaces the given secure settings of a contact point. Only the given settings are
+// encrypted again, all other settings of the contact point stay as they are.
+func (ecp *ContactPointService) RotateContactPointSecrets(ctx context.Context, orgID int64, uid string, newSecrets map[string]string, provenance models.Provenance) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return ecp.rotateContactPointSecrets(ctx, orgID, uid, newSecrets, provenance)
+	})
+}
+
+func (ecp *ContactPointService) rotateContactPointSecrets(ctx context.Context, orgID int64, uid string, newSecrets map[string]string, provenance models.Provenance) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "RotateContactPointSecrets", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	if len(newSecrets) == 0 {
+		return newValidationError("", "no secure settings to rotate")
+	}
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return err
+	}
+	contactPoint, err := ecp.getContactPointDecrypted(revision, uid)
+	if err != nil {
+		return err
+	}
+	secretKeys, err := GetSecretKeysForContactPointType(contactPoint.Type)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	for key, value := range newSecrets {
+		if !isSecretKey(secretKeys, key) {
+			return newValidationError(key, "'%s' is not a secure setting of contact points of type '%s'", key, contactPoint.Type)
+		}
+		contactPoint.Settings.Set(key, value)
+	}
+	// validate the contact point with the rotated values
+	if err := ValidateContactPoint(ctx, contactPoint, ecp.encryptionService.GetDecryptedValue); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	storedProvenance, err := ecp.provenanceStore.GetProvenance(ctx, &contactPoint, orgID)
+	if err != nil {
+		return err
+	}
+	if storedProvenance != provenance && storedProvenance != models.ProvenanceNone {
+		return fmt.Errorf("cannot change provenance from '%s' to '%s'", storedProvenance, provenance)
+	}
+	encrypted, err := ecp.encryptSecrets(ctx, newSecrets)
+	if err != nil {
+		return err
+	}
+
+	loc, _ := revision.receivers().receiver(uid)
+	oldReceiver := redactedReceiver(loc.receiver)
+	secureSettings := make(map[string]string, len(loc.receiver.SecureSettings)+len(encrypted))
+	for k, v := range loc.receiver.SecureSettings {
+		secureSettings[k] = v
+	}
+	for k, v := range encrypted {
+		secureSettings[k] = v
+	}
+	loc.receiver.SecureSettings = secureSettings
+	revision.receivers().touch(loc.group)
+	changes, err := revision.receivers().changes()
+	if err != nil {
+		return err
+	}
+	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := persistConfigChanges(ctx, ecp.amStore, orgID, revision, changes)
+		if err != nil {
+			return err
+		}
+		if err := ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &contactPoint, provenance, oldReceiver, redactedReceiver(loc.receiver))
 	})
 }
 
-func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID int64, uid string) error {
+const (
+	// maskedSecretPrefix replaces all but the last characters of a masked secure setting.
+	maskedSecretPrefix = "****"
+	// maskedSecretSuffixLength is the number of characters of a masked secure setting that are shown.
+	maskedSecretSuffixLength = 4
+)
+
+// maskSecret returns the last characters of a secure setting, prefixed with maskedSecretPrefix, so that secrets can be
+// told apart without revealing them. Secrets that are too short to show a part of them safely are redacted.
+func maskSecret(value string) string {
+	runes := []rune(value)
+	if len(runes) < 3*maskedSecretSuffixLength {
+		return apimodels.RedactedValue
+	}
+	return maskedSecretPrefix + string(runes[len(runes)-maskedSecretSuffixLength:])
+}
+
+func isSecretKey(secretKeys []string, key string) bool {
+	for _, k := range secretKeys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// DeleteContactPoint removes a contact point from the configuration of the organization. A recoverable deletion keeps
+// it in the trash, unless the retention period of the trash is zero.
+func (ecp *ContactPointService) DeleteContactPoint(ctx context.Context, orgID int64, uid string, opts DeleteContactPointOptions) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return ecp.deleteContactPoint(ctx, orgID, uid, opts)
+	})
+}
+
+func (ecp *ContactPointService) deleteContactPoint(ctx context.Context, orgID int64, uid string, opts DeleteContactPointOptions) (err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "DeleteContactPoint", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	if err := ecp.authorizeContactPointWrite(ctx, uid); err != nil {
+		return err
+	}
+	storedProvenance, err := ecp.provenanceStore.GetProvenance(ctx, &apimodels.EmbeddedContactPoint{UID: uid}, orgID)
+	if err != nil {
+		return err
+	}
+	if storedProvenance == models.ProvenanceGlobal {
+		return newValidationError("", "contact point with UID '%s' is inherited from the global contact points and cannot be deleted", uid).
+			withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid)
+	}
 	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
 	if err != nil {
 		return err
 	}
-	// Indicates if the full contact point is removed or just one of the
+	if opts.Version != "" {
+		loc, ok := revision.receivers().receiver(uid)
+		if !ok {
+			return newVersionConflictError((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid, "contact point with uid '%s' no longer exists", uid)
+		}
+		if version := contactPointVersion(loc.receiver); version != opts.Version {
+			return newVersionConflictError((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid, "contact point with uid '%s' is at version '%s'", uid, version)
+		}
+	}
+	// fullRemoval indicates if the full contact point is removed or just one of the
 	// configurations, as a contactpoint can consist of any number of
-	// configurations.
-	fullRemoval := false
+	// configurations. If this was the last receiver we removed, the whole receiver is removed.
+	removed, fullRemoval := revision.receivers().remove(uid)
 	// Name of the contact point that will be removed, might be used if a
 	// full removal is done to check if it's referenced in any route.
 	name := ""
-	for i, receiver := range revision.cfg.AlertmanagerConfig.Receivers {
-		for j, grafanaReceiver := range receiver.GrafanaManagedReceivers {
-			if grafanaReceiver.UID == uid {
-				name = grafanaReceiver.Name
-				receiver.GrafanaManagedReceivers = append(receiver.GrafanaManagedReceivers[:j], receiver.GrafanaManagedReceivers[j+1:]...)
-				// if this was the last receiver we removed, we remove the whole receiver
-				if len(receiver.GrafanaManagedReceivers) == 0 {
-					fullRemoval = true
-					revision.cfg.AlertmanagerConfig.Receivers = append(revision.cfg.AlertmanagerConfig.Receivers[:i], revision.cfg.AlertmanagerConfig.Receivers[i+1:]...)
-				}
-				break
-			}
-		}
+	var oldState any
+	if removed != nil {
+		name = removed.Name
+		oldState = redactedReceiver(removed)
 	}
 	if fullRemoval && isContactPointInUse(name, []*apimodels.Route{revision.cfg.AlertmanagerConfig.Route}) {
 		return fmt.Errorf("contact point '%s' is currently used by a notification policy", name)
 	}
-	data, err := json.Marshal(revision.cfg)
+	if fullRemoval && ecp.ruleStore != nil {
+		rules, err := ecp.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID, ReceiverName: name})
+		if err != nil {
+			return err
+		}
+		if len(rules) > 0 {
+			return newValidationError("", "contact point '%s' is used by the notification settings of the alert rules %s", name, ruleUIDs(rules)).
+				withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid)
+		}
+	}
+	changes, err := revision.receivers().changes()
 	if err != nil {
 		return err
 	}
 	return ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := persistConfigChanges(ctx, ecp.amStore, orgID, revision, changes)
+		if err != nil {
+			return err
+		}
 		target := &apimodels.EmbeddedContactPoint{
 			UID: uid,
 		}
-		err := ecp.provenanceStore.DeleteProvenance(ctx, target, orgID)
+		err = ecp.provenanceStore.DeleteProvenance(ctx, target, orgID)
+		if err != nil {
+			return err
+		}
+		err = ecp.expirations.SetExpiration(ctx, orgID, uid, nil)
 		if err != nil {
 			return err
 		}
-		return PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
-			AlertmanagerConfiguration: string(data),
-			FetchedConfigurationHash:  revision.concurrencyToken,
-			ConfigurationVersion:      revision.version,
-			Default:                   false,
-			OrgID:                     orgID,
-		})
+		if opts.Recoverable && ecp.deleted.retention > 0 && removed != nil {
+			if err := ecp.deleted.add(ctx, orgID, removed, storedProvenance, time.Now()); err != nil {
+				return err
+			}
+		}
+		return recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionDelete, target, models.ProvenanceNone, oldState, nil)
 	})
 }
 
+// contactPointVersion returns the version of a stored receiver. It changes whenever any of its settings, including
+// the encrypted secure settings, is changed.
+func contactPointVersion(receiver *apimodels.PostableGrafanaReceiver) string {
+	data, err := json.Marshal(receiver)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", md5.Sum(data))
+}
+
+// inheritedReceivers returns the receivers of the group with the given name if all of them are copies of global
+// contact points, and nothing otherwise.
+func (ecp *ContactPointService) inheritedReceivers(ctx context.Context, orgID int64, revision *cfgRevision, name string) ([]*apimodels.PostableGrafanaReceiver, error) {
+	group, ok := revision.receivers().group(name)
+	if !ok {
+		return nil, nil
+	}
+	provenances, err := ecp.provenanceStore.GetProvenances(ctx, orgID, (&apimodels.EmbeddedContactPoint{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+	inherited := func(uid string) bool {
+		return provenances[uid] == models.ProvenanceGlobal
+	}
+	if !onlyInherited(group, inherited) {
+		return nil, nil
+	}
+	return append([]*apimodels.PostableGrafanaReceiver(nil), group.GrafanaManagedReceivers...), nil
+}
+
 func isContactPointInUse(name string, routes []*apimodels.Route) bool {
 	if len(routes) == 0 {
 		return false
@@ -430,6 +1124,10 @@ func (ecp *ContactPointService) decryptValue(value string) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// References to external secrets are stored unencrypted and returned as they are, not resolved.
+	if secretrefs.IsExternal(string(decodeValue)) {
+		return string(decodeValue), nil
+	}
 
 	decryptedValue, err := ecp.encryptionService.Decrypt(context.Background(), decodeValue)
 	if err != nil {
@@ -439,86 +1137,107 @@ func (ecp *ContactPointService) decryptValue(value string) (string, error) {
 	return string(decryptedValue), nil
 }
 
-func (ecp *ContactPointService) encryptValue(value string) (string, error) {
-	encryptedData, err := ecp.encryptionService.Encrypt(context.Background(), []byte(value), secrets.WithoutScope())
+// encryptSecrets encrypts all secure settings of a receiver with a single call to the secrets service,
+// so that the data key is resolved only once per operation. The result is base64 encoded.
+// Secure settings that reference an external secret, such as $__env{SLACK_TOKEN}, are not encrypted: the reference
+// is stored and resolved when the integration is built. The reference must be allowed by the configuration of Grafana
+// and resolvable when it is saved.
+func (ecp *ContactPointService) encryptSecrets(ctx context.Context, values map[string]string) (map[string]string, error) {
+	encrypted := make(map[string]string, len(values))
+	toEncrypt := make(map[string]string, len(values))
+	for k, v := range values {
+		if !secretrefs.IsExternal(v) {
+			toEncrypt[k] = v
+			continue
+		}
+		if _, err := ecp.secretRefs.Resolve(v); err != nil {
+			return nil, newValidationError("settings."+k, "%s", err.Error())
+		}
+		encrypted[k] = base64.StdEncoding.EncodeToString([]byte(v))
+	}
+	encryptedData, err := ecp.encryptionService.EncryptJsonData(ctx, toEncrypt, secrets.WithoutScope())
 	if err != nil {
-		return "", fmt.Errorf("failed to encrypt secure settings: %w", err)
+		return nil, fmt.Errorf("failed to encrypt secure settings: %w", err)
+	}
+	for k, v := range encryptedData {
+		encrypted[k] = base64.StdEncoding.EncodeToString(v)
 	}
-	return base64.StdEncoding.EncodeToString(encryptedData), nil
+	return encrypted, nil
 }
 
-// stitchReceiver modifies a receiver, target, in an alertmanager config. It modifies the given config in-place.
+// stitchReceiver modifies a receiver, target, in an alertmanager config. It modifies the indexed config in-place.
+// If order is set, the receiver is moved to that position within its receiver group afterwards, otherwise it keeps
+// its position, or is added last if it moves to another group.
 // Returns true if the config was altered in any way, and false otherwise.
-func stitchReceiver(cfg *apimodels.PostableUserConfig, target *apimodels.PostableGrafanaReceiver) bool {
+func stitchReceiver(idx *receiverIndex, target *apimodels.PostableGrafanaReceiver, order *int) bool {
+	if !placeReceiver(idx, target) {
+		return false
+	}
+	if order != nil {
+		idx.move(target.UID, *order)
+	}
+	return true
+}
+
+// placeReceiver puts the receiver into the receiver group with its name.
+func placeReceiver(idx *receiverIndex, target *apimodels.PostableGrafanaReceiver) bool {
 	// Algorithm to fix up receivers. Receivers are very complex and depend heavily on internal consistency.
 	// All receivers in a given receiver group have the same name. We must maintain this across renames.
-	configModified := false
-groupLoop:
-	for groupIdx, receiverGroup := range cfg.AlertmanagerConfig.Receivers {
-		// Does the current group contain the grafana receiver we're interested in?
-		for i, grafanaReceiver := range receiverGroup.GrafanaManagedReceivers {
-			if grafanaReceiver.UID == target.UID {
-				// If it's a basic field change, simply replace it. Done!
-				//
-				// NOTE:
-				// In a "normal" database, receiverGroup.Name should always == grafanaReceiver.Name.
-				// Check it regardless.
-				// If these values are out of sync due to some bug elsewhere in the code, let's fix it up.
-				// Our receiver group fixing logic below will handle it.
-				if grafanaReceiver.Name == target.Name && receiverGroup.Name == grafanaReceiver.Name {
-					receiverGroup.GrafanaManagedReceivers[i] = target
-					configModified = true
-					break groupLoop
-				}
+	loc, ok := idx.receiver(target.UID)
+	if !ok {
+		return false
+	}
+	receiverGroup := loc.group
 
-				// If we're renaming, we'll need to fix up the macro receiver group for consistency.
-				// Firstly, if we're the only receiver in the group, simply rename the group to match. Done!
-				if len(receiverGroup.GrafanaManagedReceivers) == 1 {
-					replaceReferences(receiverGroup.Name, target.Name, cfg.AlertmanagerConfig.Route)
-					receiverGroup.Name = target.Name
-					receiverGroup.GrafanaManagedReceivers[i] = target
-				}
+	// If it's a basic field change, simply replace it. Done!
+	//
+	// NOTE:
+	// In a "normal" database, receiverGroup.Name should always == grafanaReceiver.Name.
+	// Check it regardless.
+	// If these values are out of sync due to some bug elsewhere in the code, let's fix it up.
+	// Our receiver group fixing logic below will handle it.
+	if loc.receiver.Name == target.Name && receiverGroup.Name == loc.receiver.Name {
+		return idx.replace(target)
+	}
 
-				// Otherwise, we only want to rename the receiver we are touching... NOT all of them.
-				// Check to see whether a different group with the name we want already exists.
-				for _, candidateExistingGroup := range cfg.AlertmanagerConfig.Receivers {
-					// If so, put our modified receiver into that group. Done!
-					if candidateExistingGroup.Name == target.Name {
-						// Drop it from the old group...
-						receiverGroup.GrafanaManagedReceivers = append(receiverGroup.GrafanaManagedReceivers[:i], receiverGroup.GrafanaManagedReceivers[i+1:]...)
-						// Add the modified receiver to the new group...
-						candidateExistingGroup.GrafanaManagedReceivers = append(candidateExistingGroup.GrafanaManagedReceivers, target)
-						configModified = true
-
-						// if the old receiver group turns out to be empty. Remove it.
-						if len(receiverGroup.GrafanaManagedReceivers) == 0 {
-							cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers[:groupIdx], cfg.AlertmanagerConfig.Receivers[groupIdx+1:]...)
-						}
-						break groupLoop
-					}
-				}
+	// If we're renaming, we'll need to fix up the macro receiver group for consistency.
+	// Firstly, if we're the only receiver in the group, simply rename the group to match.
+	if len(receiverGroup.GrafanaManagedReceivers) == 1 {
+		idx.replaceReferences(receiverGroup.Name, target.Name)
+		idx.renameGroup(receiverGroup, target.Name)
+	}
 
-				// Doesn't exist? Create a new group just for the receiver.
-				newGroup := &apimodels.PostableApiReceiver{
-					Receiver: config.Receiver{
-						Name: target.Name,
-					},
-					PostableGrafanaReceivers: apimodels.PostableGrafanaReceivers{
-						GrafanaManagedReceivers: []*apimodels.PostableGrafanaReceiver{
-							target,
-						},
-					},
-				}
-				cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers, newGroup)
-				// Drop it from the old spot.
-				receiverGroup.GrafanaManagedReceivers = append(receiverGroup.GrafanaManagedReceivers[:i], receiverGroup.GrafanaManagedReceivers[i+1:]...)
-				configModified = true
-				break groupLoop
-			}
-		}
+	// If the group already carries the name we want, the receiver stays where it is.
+	existingGroup, ok := idx.group(target.Name)
+	if ok && existingGroup == receiverGroup {
+		return idx.replace(target)
 	}
 
-	return configModified
+	// Otherwise, we only want to move the receiver we are touching... NOT all of them.
+	// Drop it from the old group, and remove the old group if it turns out to be empty.
+	// Then put it into the group with the name we want, which is created if it doesn't exist yet.
+	idx.remove(target.UID)
+	idx.add(target)
+	return true
+}
+
+// renameContactPoint renames the receiver group that contains the receiver with the given UID, together with all of
+// its receivers, and points the routes that used the old name to the new one.
+func renameContactPoint(idx *receiverIndex, uid string, name string) error {
+	loc, ok := idx.receiver(uid)
+	if !ok || loc.group.Name == name {
+		return nil
+	}
+	if _, exists := idx.group(name); exists {
+		return newValidationError("name", "cannot rename contact point '%s' to '%s' because a contact point with this name already exists", loc.group.Name, name).
+			withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid)
+	}
+	idx.replaceReferences(loc.group.Name, name)
+	for _, receiver := range loc.group.GrafanaManagedReceivers {
+		idx.renameReceiver(receiver, name)
+	}
+	idx.renameGroup(loc.group, name)
+	return nil
 }
 
 func replaceReferences(oldName, newName string, routes ...*apimodels.Route) {
@@ -540,6 +1259,12 @@ func ValidateContactPoint(ctx context.Context, e apimodels.EmbeddedContactPoint,
 	if e.Settings == nil {
 		return fmt.Errorf("settings should not be empty")
 	}
+	if e.Order != nil && *e.Order < 0 {
+		return fmt.Errorf("order should not be negative")
+	}
+	if _, ok := probeEndpoints[e.Type]; e.Probe && !ok {
+		return fmt.Errorf("contact points of type '%s' cannot be probed", e.Type)
+	}
 	integration, err := EmbeddedContactPointToGrafanaIntegrationConfig(e)
 	if err != nil {
 		return err
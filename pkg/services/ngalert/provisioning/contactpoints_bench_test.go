@@ -0,0 +1,121 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/accesscontrol/actest"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+const (
+	benchReceiverGroups    = 2000
+	benchReceiversPerGroup = 3
+)
+
+func BenchmarkStitchReceiver(b *testing.B) {
+	b.Run("update in place", func(b *testing.B) {
+		idx := newReceiverIndex(generateConfigWithReceivers(benchReceiverGroups, benchReceiversPerGroup))
+		target := &definitions.PostableGrafanaReceiver{UID: "uid-1000-0", Name: "receiver-1000", Type: "email"}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			stitchReceiver(idx, target, nil)
+		}
+	})
+
+	b.Run("rename group", func(b *testing.B) {
+		idx := newReceiverIndex(generateConfigWithReceivers(benchReceiverGroups, 1))
+		names := []string{"renamed", "receiver-1000"}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			stitchReceiver(idx, &definitions.PostableGrafanaReceiver{UID: "uid-1000-0", Name: names[i%2], Type: "email"}, nil)
+		}
+	})
+
+	b.Run("move to another group", func(b *testing.B) {
+		idx := newReceiverIndex(generateConfigWithReceivers(benchReceiverGroups, benchReceiversPerGroup))
+		names := []string{"receiver-1500", "receiver-1000"}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			stitchReceiver(idx, &definitions.PostableGrafanaReceiver{UID: "uid-1000-0", Name: names[i%2], Type: "email"}, nil)
+		}
+	})
+}
+
+func BenchmarkUpdateContactPoint(b *testing.B) {
+	raw, err := json.Marshal(generateConfigWithReceivers(benchReceiverGroups, benchReceiversPerGroup))
+	require.NoError(b, err)
+	sut := &ContactPointService{
+		amStore:           newFakeAMConfigStore(string(raw)),
+		provenanceStore:   NewFakeProvisioningStore(),
+		expirations:       NewContactPointExpirationStore(kvstore.NewFakeKVStore()),
+		deleted:           NewDeletedContactPointStore(kvstore.NewFakeKVStore(), time.Hour),
+		xact:              newNopTransactionManager(),
+		encryptionService: fakes.NewFakeSecretsService(),
+		log:               log.NewNopLogger(),
+		ac:                actest.FakeAccessControl{},
+		tracer:            tracing.InitializeTracerForTest(),
+	}
+	names := []string{"receiver-1500", "receiver-1000"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		settings := simplejson.New()
+		settings.Set("addresses", "test@example.com")
+		cp := definitions.EmbeddedContactPoint{
+			UID:      "uid-1000-0",
+			Name:     names[i%2],
+			Type:     "email",
+			Settings: settings,
+		}
+		if err := sut.UpdateContactPoint(context.Background(), 1, cp, models.ProvenanceAPI, UpdateContactPointOptions{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// generateConfigWithReceivers creates a configuration with the given number of receiver groups, each referenced by a
+// route of its own and holding the given number of email receivers.
+func generateConfigWithReceivers(groups, perGroup int) *definitions.PostableUserConfig {
+	cfg := &definitions.PostableUserConfig{
+		AlertmanagerConfig: definitions.PostableApiAlertingConfig{
+			Config: definitions.Config{
+				Route: &definitions.Route{
+					Receiver: "receiver-0",
+				},
+			},
+		},
+	}
+	for g := 0; g < groups; g++ {
+		name := fmt.Sprintf("receiver-%d", g)
+		group := &definitions.PostableApiReceiver{
+			Receiver: config.Receiver{Name: name},
+		}
+		for r := 0; r < perGroup; r++ {
+			group.GrafanaManagedReceivers = append(group.GrafanaManagedReceivers, &definitions.PostableGrafanaReceiver{
+				UID:      fmt.Sprintf("uid-%d-%d", g, r),
+				Name:     name,
+				Type:     "email",
+				Settings: definitions.RawMessage(`{"addresses":"test@example.com"}`),
+			})
+		}
+		cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers, group)
+		cfg.AlertmanagerConfig.Route.Routes = append(cfg.AlertmanagerConfig.Route.Routes, &definitions.Route{Receiver: name})
+	}
+	return cfg
+}
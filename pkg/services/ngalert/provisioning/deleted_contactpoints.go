@@ -0,0 +1,291 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const deletedContactPointsKey = "deleted_contact_points"
+
+// DeleteContactPointOptions controls how a contact point is deleted.
+type DeleteContactPointOptions struct {
+	// Recoverable keeps the deleted contact point in the trash of the organization, from where it can be restored
+	// until the retention period of the trash ends.
+	Recoverable bool
+	// Version optionally is the version of the contact point the deletion is based on. The deletion fails with
+	// ErrVersionConflict if the contact point was changed since.
+	Version string
+}
+
+// deletedContactPoint is a receiver in the trash of an organization. Its secure settings stay encrypted.
+type deletedContactPoint struct {
+	Receiver   *apimodels.PostableGrafanaReceiver `json:"receiver"`
+	Provenance models.Provenance                  `json:"provenance"`
+	DeletedAt  time.Time                          `json:"deletedAt"`
+	PurgeAt    time.Time                          `json:"purgeAt"`
+}
+
+// DeletedContactPointStore keeps recoverably deleted contact points for a retention period, per organization and
+// keyed by the UID of the contact point.
+type DeletedContactPointStore struct {
+	kv        kvstore.KVStore
+	retention time.Duration
+}
+
+// NewDeletedContactPointStore returns a store that keeps deleted contact points for the given retention period.
+// A retention period of zero makes all deletions permanent.
+func NewDeletedContactPointStore(kv kvstore.KVStore, retention time.Duration) *DeletedContactPointStore {
+	return &DeletedContactPointStore{kv: kv, retention: retention}
+}
+
+func (s *DeletedContactPointStore) get(ctx context.Context, orgID int64) (map[string]deletedContactPoint, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, deletedContactPointsKey)
+	if err != nil {
+		return nil, err
+	}
+	deleted := map[string]deletedContactPoint{}
+	if !ok {
+		return deleted, nil
+	}
+	if err := json.Unmarshal([]byte(value), &deleted); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deleted contact points: %w", err)
+	}
+	return deleted, nil
+}
+
+// getAll returns the deleted contact points of all organizations.
+func (s *DeletedContactPointStore) getAll(ctx context.Context) (map[int64]map[string]deletedContactPoint, error) {
+	all, err := s.kv.GetAll(ctx, kvstore.AllOrganizations, fileProvisioningStatusNamespace)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[int64]map[string]deletedContactPoint, len(all))
+	for orgID, values := range all {
+		value, ok := values[deletedContactPointsKey]
+		if !ok {
+			continue
+		}
+		deleted := map[string]deletedContactPoint{}
+		if err := json.Unmarshal([]byte(value), &deleted); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal deleted contact points of organization %d: %w", orgID, err)
+		}
+		result[orgID] = deleted
+	}
+	return result, nil
+}
+
+func (s *DeletedContactPointStore) set(ctx context.Context, orgID int64, deleted map[string]deletedContactPoint) error {
+	if len(deleted) == 0 {
+		return s.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, deletedContactPointsKey)
+	}
+	data, err := json.Marshal(deleted)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, deletedContactPointsKey, string(data))
+}
+
+func (s *DeletedContactPointStore) add(ctx context.Context, orgID int64, receiver *apimodels.PostableGrafanaReceiver, provenance models.Provenance, now time.Time) error {
+	deleted, err := s.get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	deleted[receiver.UID] = deletedContactPoint{
+		Receiver:   receiver,
+		Provenance: provenance,
+		DeletedAt:  now,
+		PurgeAt:    now.Add(s.retention),
+	}
+	return s.set(ctx, orgID, deleted)
+}
+
+func (s *DeletedContactPointStore) remove(ctx context.Context, orgID int64, uid string) error {
+	deleted, err := s.get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if _, ok := deleted[uid]; !ok {
+		return nil
+	}
+	delete(deleted, uid)
+	return s.set(ctx, orgID, deleted)
+}
+
+// ListDeletedContactPoints returns the deleted contact points of the organization that can still be restored, the most
+// recently deleted first. Their secure settings are redacted.
+func (ecp *ContactPointService) ListDeletedContactPoints(ctx context.Context, orgID int64) (_ []apimodels.DeletedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "ListDeletedContactPoints", orgID)
+	defer func() { done(err) }()
+	deleted, err := ecp.deleted.get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	result := make([]apimodels.DeletedContactPoint, 0, len(deleted))
+	for _, entry := range deleted {
+		if !entry.PurgeAt.After(now) {
+			continue
+		}
+		contactPoint, err := ecp.redactedContactPoint(ctx, entry.Receiver)
+		if err != nil {
+			return nil, err
+		}
+		contactPoint.Provenance = string(entry.Provenance)
+		result = append(result, apimodels.DeletedContactPoint{
+			ContactPoint: contactPoint,
+			DeletedAt:    entry.DeletedAt,
+			PurgeAt:      entry.PurgeAt,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if !result[i].DeletedAt.Equal(result[j].DeletedAt) {
+			return result[i].DeletedAt.After(result[j].DeletedAt)
+		}
+		return result[i].ContactPoint.UID < result[j].ContactPoint.UID
+	})
+	return result, nil
+}
+
+// RestoreContactPoint moves a deleted contact point out of the trash back into the configuration of the organization.
+// It returns the restored contact point with redacted secure settings.
+func (ecp *ContactPointService) RestoreContactPoint(ctx context.Context, orgID int64, uid string, provenance models.Provenance) (result apimodels.EmbeddedContactPoint, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = ecp.restoreContactPoint(ctx, orgID, uid, provenance)
+		return err
+	})
+	return result, err
+}
+
+func (ecp *ContactPointService) restoreContactPoint(ctx context.Context, orgID int64, uid string, provenance models.Provenance) (_ apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, ecp.tracer, ecp.metrics, "contactPoint", "RestoreContactPoint", orgID,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+	deleted, err := ecp.deleted.get(ctx, orgID)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	entry, ok := deleted[uid]
+	if !ok || !entry.PurgeAt.After(time.Now()) {
+		return apimodels.EmbeddedContactPoint{}, newNotFoundError((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid, "deleted contact point with uid '%s' not found", uid)
+	}
+	revision, err := getLastConfiguration(ctx, orgID, ecp.amStore)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	if existing, ok := revision.receivers().receiver(uid); ok {
+		return apimodels.EmbeddedContactPoint{}, newValidationError("", "uid '%s' is used by contact point '%s' already", uid, existing.receiver.Name).
+			withResource((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid)
+	}
+	// Like a new contact point, the restored one overrides the global contact points with the same name.
+	overridden, err := ecp.inheritedReceivers(ctx, orgID, revision, entry.Receiver.Name)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	for _, r := range overridden {
+		revision.receivers().remove(r.UID)
+	}
+	revision.receivers().add(entry.Receiver)
+	contactPoint, err := ecp.redactedContactPoint(ctx, entry.Receiver)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	data, err := json.Marshal(revision.cfg)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	err = ecp.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := PersistConfig(ctx, ecp.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		})
+		if err != nil {
+			return err
+		}
+		for _, r := range overridden {
+			if err := ecp.provenanceStore.DeleteProvenance(ctx, &apimodels.EmbeddedContactPoint{UID: r.UID}, orgID); err != nil {
+				return err
+			}
+		}
+		if err := ecp.provenanceStore.SetProvenance(ctx, &contactPoint, orgID, provenance); err != nil {
+			return err
+		}
+		if err := ecp.deleted.remove(ctx, orgID, uid); err != nil {
+			return err
+		}
+		return recordAudit(ctx, ecp.provenanceStore, orgID, models.ProvisioningAuditActionRestore, &contactPoint, provenance, nil, redactedReceiver(entry.Receiver))
+	})
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	contactPoint.Provenance = string(provenance)
+	return contactPoint, nil
+}
+
+// PurgeDeletedContactPoints permanently deletes the contact points of all organizations whose retention in the trash
+// ended at the given time.
+func (ecp *ContactPointService) PurgeDeletedContactPoints(ctx context.Context, now time.Time) error {
+	all, err := ecp.deleted.getAll(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for orgID, deleted := range all {
+		purged := false
+		for uid, entry := range deleted {
+			if !entry.PurgeAt.After(now) {
+				delete(deleted, uid)
+				purged = true
+			}
+		}
+		if !purged {
+			continue
+		}
+		if err := ecp.deleted.set(ctx, orgID, deleted); err != nil {
+			errs = append(errs, fmt.Errorf("failed to purge deleted contact points of organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// redactedContactPoint converts a receiver to a contact point with redacted secure settings.
+func (ecp *ContactPointService) redactedContactPoint(ctx context.Context, receiver *apimodels.PostableGrafanaReceiver) (apimodels.EmbeddedContactPoint, error) {
+	settings, err := simplejson.NewJson(receiver.Settings)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	contactPoint := apimodels.EmbeddedContactPoint{
+		UID:                   receiver.UID,
+		Type:                  receiver.Type,
+		Name:                  receiver.Name,
+		DisableResolveMessage: receiver.DisableResolveMessage,
+		Settings:              settings,
+		Disabled:              receiver.Disabled,
+		Probe:                 receiver.Probe,
+	}
+	for k, v := range receiver.SecureSettings {
+		decryptedValue, err := ecp.decryptValue(v)
+		if err != nil {
+			ecp.log.FromContext(ctx).Warn("Decrypting value failed", "error", err.Error())
+			continue
+		}
+		if decryptedValue == "" {
+			continue
+		}
+		contactPoint.Settings.Set(k, apimodels.RedactedValue)
+	}
+	return contactPoint, nil
+}
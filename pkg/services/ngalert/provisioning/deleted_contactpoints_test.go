@@ -0,0 +1,97 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestDeletedContactPoints(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("recoverably deleted contact points can be restored", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteContactPoint(ctx, 1, created.UID, DeleteContactPointOptions{Recoverable: true}))
+
+		deleted, err := sut.ListDeletedContactPoints(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+		require.Equal(t, created.UID, deleted[0].ContactPoint.UID)
+		require.Equal(t, definitions.RedactedValue, deleted[0].ContactPoint.Settings.Get("token").MustString())
+		require.Equal(t, string(models.ProvenanceAPI), deleted[0].ContactPoint.Provenance)
+
+		restored, err := sut.RestoreContactPoint(ctx, 1, created.UID, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, created.Name, restored.Name)
+
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		cp, err := sut.getContactPointDecrypted(revision, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, "value_token", cp.Settings.Get("token").MustString())
+
+		deleted, err = sut.ListDeletedContactPoints(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+
+		entries := sut.provenanceStore.(*fakeProvisioningStore).auditEntries
+		require.Equal(t, models.ProvisioningAuditActionRestore, entries[len(entries)-1].Action)
+	})
+
+	t.Run("permanently deleted contact points cannot be restored", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteContactPoint(ctx, 1, created.UID, DeleteContactPointOptions{}))
+
+		deleted, err := sut.ListDeletedContactPoints(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+		_, err = sut.RestoreContactPoint(ctx, 1, created.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("deleted contact points are purged after the retention period", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, sut.DeleteContactPoint(ctx, 1, created.UID, DeleteContactPointOptions{Recoverable: true}))
+
+		require.NoError(t, sut.PurgeDeletedContactPoints(ctx, time.Now()))
+		deleted, err := sut.ListDeletedContactPoints(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, deleted, 1)
+
+		require.NoError(t, sut.PurgeDeletedContactPoints(ctx, time.Now().Add(sut.deleted.retention)))
+		_, err = sut.RestoreContactPoint(ctx, 1, created.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("contact points are not restored over contact points with the same uid", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		cp := createTestContactPoint()
+		cp.UID = "reused-uid"
+		_, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, sut.DeleteContactPoint(ctx, 1, cp.UID, DeleteContactPointOptions{Recoverable: true}))
+		_, err = sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.RestoreContactPoint(ctx, 1, cp.UID, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
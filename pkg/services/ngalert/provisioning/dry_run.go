@@ -0,0 +1,104 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util/cmputil"
+)
+
+// ErrDryRun is returned by mutations that run in dry-run mode once they have validated and computed their changes.
+// Nothing is saved.
+var ErrDryRun = errors.New("dry run, no changes were saved")
+
+type dryRunKey struct{}
+
+// DryRun collects the changes that mutations in dry-run mode would have made to the Alertmanager configuration.
+type DryRun struct {
+	changes []definitions.ConfigChange
+}
+
+// WithDryRun returns a context in which mutations perform all validation and compute their changes to the
+// Alertmanager configuration, but fail with ErrDryRun instead of saving them. The changes are collected in the
+// returned DryRun.
+func WithDryRun(ctx context.Context) (context.Context, *DryRun) {
+	dryRun := &DryRun{}
+	return context.WithValue(ctx, dryRunKey{}, dryRun), dryRun
+}
+
+func dryRunFromContext(ctx context.Context) (*DryRun, bool) {
+	dryRun, ok := ctx.Value(dryRunKey{}).(*DryRun)
+	return dryRun, ok
+}
+
+// Result returns the changes collected by the dry run.
+func (d *DryRun) Result() definitions.ProvisioningDryRun {
+	changes := d.changes
+	if changes == nil {
+		changes = []definitions.ConfigChange{}
+	}
+	return definitions.ProvisioningDryRun{Changes: changes}
+}
+
+// record adds the differences between the latest configuration in the store and the configuration the command
+// would save to the dry run.
+func (d *DryRun) record(ctx context.Context, store AMConfigStore, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	revision, err := getLastConfiguration(ctx, cmd.OrgID, store)
+	if err != nil {
+		return err
+	}
+	// The stored configuration is serialized again, so that only actual changes differ from the new one.
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return err
+	}
+	var before, after any
+	if err := json.Unmarshal(serialized, &before); err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(cmd.AlertmanagerConfiguration), &after); err != nil {
+		return err
+	}
+	reporter := cmputil.DiffReporter{}
+	cmp.Equal(before, after, cmp.Reporter(&reporter))
+	for _, diff := range reporter.Diffs {
+		change := definitions.ConfigChange{Path: diff.Path}
+		if diff.Left.IsValid() {
+			change.Before = redactSecureSettings(diff.Path, diff.Left.Interface())
+		}
+		if diff.Right.IsValid() {
+			change.After = redactSecureSettings(diff.Path, diff.Right.Interface())
+		}
+		d.changes = append(d.changes, change)
+	}
+	return nil
+}
+
+// redactSecureSettings replaces the encrypted secure settings in a value of the configuration at the given path.
+func redactSecureSettings(path string, value any) any {
+	if strings.Contains(path, "[secureSettings]") {
+		return definitions.RedactedValue
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		redacted := make(map[string]any, len(v))
+		for key, nested := range v {
+			redacted[key] = redactSecureSettings(path+"["+key+"]", nested)
+		}
+		return redacted
+	case []any:
+		redacted := make([]any, len(v))
+		for i, nested := range v {
+			redacted[i] = redactSecureSettings(path, nested)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
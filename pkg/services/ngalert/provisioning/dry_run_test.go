@@ -0,0 +1,69 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestDryRun(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	t.Run("created contact points are not saved and secure settings of the changes are redacted", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		ctx, dryRun := WithDryRun(context.Background())
+
+		_, err := sut.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrDryRun)
+
+		require.Nil(t, sut.amStore.(*fakeAMConfigStore).lastSaveCommand)
+		require.Empty(t, sut.provenanceStore.(*fakeProvisioningStore).auditEntries)
+		changes := dryRun.Result().Changes
+		require.Len(t, changes, 1)
+		require.Equal(t, "[alertmanager_config][receivers]", changes[0].Path)
+		require.Nil(t, changes[0].Before)
+		receiver := changes[0].After.(map[string]any)["grafana_managed_receiver_configs"].([]any)[0].(map[string]any)
+		require.Equal(t, definitions.RedactedValue, receiver["secureSettings"])
+	})
+
+	t.Run("deleted contact points are kept", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateContactPoint(context.Background(), 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		ctx, dryRun := WithDryRun(context.Background())
+
+		err = sut.DeleteContactPoint(ctx, 1, created.UID, DeleteContactPointOptions{Recoverable: true})
+		require.ErrorIs(t, err, ErrDryRun)
+
+		require.NotEmpty(t, dryRun.Result().Changes)
+		provenance, err := sut.provenanceStore.GetProvenance(context.Background(), &created, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		deleted, err := sut.ListDeletedContactPoints(context.Background(), 1)
+		require.NoError(t, err)
+		require.Empty(t, deleted)
+	})
+
+	t.Run("changed notification policies are not saved", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		ctx, dryRun := WithDryRun(context.Background())
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, createTestRoutingTree(), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrDryRun)
+
+		require.Nil(t, sut.amStore.(*fakeAMConfigStore).lastSaveCommand)
+		require.Contains(t, dryRun.Result().Changes, definitions.ConfigChange{
+			Path:   "[alertmanager_config][route][receiver]",
+			Before: "grafana-default-email",
+			After:  "a new receiver",
+		})
+	})
+}
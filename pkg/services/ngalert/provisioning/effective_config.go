@@ -0,0 +1,153 @@
+package provisioning
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ProvisioningAuditReader reads the provisioning audit log.
+type ProvisioningAuditReader interface {
+	GetLatestProvisioningAuditEntries(ctx context.Context, orgID int64) ([]models.ProvisioningAuditEntry, error)
+}
+
+// EffectiveConfigService returns the alerting configuration of an organization as it is applied, with the provenance
+// of every resource and the source of its last change, to help understand why the configuration is what it is.
+type EffectiveConfigService struct {
+	amStore         AMConfigStore
+	provenanceStore ProvisioningStore
+	audit           ProvisioningAuditReader
+	log             log.Logger
+	tracer          tracing.Tracer
+	metrics         *metrics.Provisioning
+}
+
+func NewEffectiveConfigService(store AMConfigStore, provenanceStore ProvisioningStore, audit ProvisioningAuditReader,
+	log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *EffectiveConfigService {
+	return &EffectiveConfigService{
+		amStore:         newTracedAMConfigStore(store, tracer, log, m),
+		provenanceStore: provenanceStore,
+		audit:           audit,
+		log:             log,
+		tracer:          tracer,
+		metrics:         m,
+	}
+}
+
+// GetEffectiveConfig returns the alerting configuration of the organization. Secure settings are redacted and the
+// resources are sorted by name.
+func (svc *EffectiveConfigService) GetEffectiveConfig(ctx context.Context, orgID int64) (_ definitions.EffectiveConfig, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "config", "GetEffectiveConfig", orgID)
+	defer func() { done(err) }()
+
+	revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+	if err != nil {
+		return definitions.EffectiveConfig{}, err
+	}
+	entries, err := svc.audit.GetLatestProvisioningAuditEntries(ctx, orgID)
+	if err != nil {
+		return definitions.EffectiveConfig{}, err
+	}
+	sources := make(map[string]definitions.ResourceSource, len(entries))
+	for _, e := range entries {
+		sources[e.ResourceType+"/"+e.ResourceID] = resourceSource(e)
+	}
+	sourceOf := func(r models.Provisionable) definitions.ResourceSource {
+		return sources[r.ResourceType()+"/"+r.ResourceID()]
+	}
+
+	result := definitions.EffectiveConfig{
+		ContactPoints: []definitions.EffectiveContactPoint{},
+		Templates:     []definitions.EffectiveTemplate{},
+		MuteTimings:   []definitions.EffectiveMuteTiming{},
+	}
+
+	if route := revision.cfg.AlertmanagerConfig.Route; route != nil {
+		provenance, err := svc.provenanceStore.GetProvenance(ctx, route, orgID)
+		if err != nil {
+			return definitions.EffectiveConfig{}, err
+		}
+		result.Policies.Route = *route
+		result.Policies.Route.Provenance = definitions.Provenance(provenance)
+		result.Policies.Source = sourceOf(route)
+	}
+
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&definitions.EmbeddedContactPoint{}).ResourceType())
+	if err != nil {
+		return definitions.EffectiveConfig{}, err
+	}
+	for _, r := range revision.receivers().all() {
+		settings, err := simplejson.NewJson(r.Settings)
+		if err != nil {
+			return definitions.EffectiveConfig{}, err
+		}
+		for k := range r.SecureSettings {
+			settings.Set(k, definitions.RedactedValue)
+		}
+		cp := definitions.EmbeddedContactPoint{
+			UID:                   r.UID,
+			Name:                  r.Name,
+			Type:                  r.Type,
+			Settings:              settings,
+			DisableResolveMessage: r.DisableResolveMessage,
+			Provenance:            string(provenances[r.UID]),
+		}
+		result.ContactPoints = append(result.ContactPoints, definitions.EffectiveContactPoint{ContactPoint: cp, Source: sourceOf(&cp)})
+	}
+	sort.SliceStable(result.ContactPoints, func(i, j int) bool {
+		a, b := result.ContactPoints[i].ContactPoint, result.ContactPoints[j].ContactPoint
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.UID < b.UID
+	})
+
+	provenances, err = svc.provenanceStore.GetProvenances(ctx, orgID, (&definitions.NotificationTemplate{}).ResourceType())
+	if err != nil {
+		return definitions.EffectiveConfig{}, err
+	}
+	for name, content := range revision.cfg.TemplateFiles {
+		tmpl := definitions.NotificationTemplate{
+			Name:       name,
+			Template:   content,
+			Provenance: definitions.Provenance(provenances[name]),
+		}
+		result.Templates = append(result.Templates, definitions.EffectiveTemplate{Template: tmpl, Source: sourceOf(&tmpl)})
+	}
+	sort.Slice(result.Templates, func(i, j int) bool {
+		return result.Templates[i].Template.Name < result.Templates[j].Template.Name
+	})
+
+	provenances, err = svc.provenanceStore.GetProvenances(ctx, orgID, (&definitions.MuteTimeInterval{}).ResourceType())
+	if err != nil {
+		return definitions.EffectiveConfig{}, err
+	}
+	for _, interval := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		mt := definitions.MuteTimeInterval{
+			MuteTimeInterval: interval,
+			Provenance:       definitions.Provenance(provenances[interval.Name]),
+		}
+		result.MuteTimings = append(result.MuteTimings, definitions.EffectiveMuteTiming{MuteTiming: mt, Source: sourceOf(&mt)})
+	}
+	sort.Slice(result.MuteTimings, func(i, j int) bool {
+		return result.MuteTimings[i].MuteTiming.Name < result.MuteTimings[j].MuteTiming.Name
+	})
+
+	return result, nil
+}
+
+func resourceSource(e models.ProvisioningAuditEntry) definitions.ResourceSource {
+	changed := time.Unix(e.Created, 0).UTC()
+	return definitions.ResourceSource{
+		File:    e.Source,
+		Actor:   e.ActorLogin,
+		Changed: &changed,
+	}
+}
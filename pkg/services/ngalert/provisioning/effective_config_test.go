@@ -0,0 +1,101 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const effectiveConfigTestConfig = `
+{
+	"template_files": {"b": "{{ define \"b\" }}{{ end }}", "a": "{{ define \"a\" }}{{ end }}"},
+	"alertmanager_config": {
+		"route": {
+			"receiver": "slack"
+		},
+		"mute_time_intervals": [{
+			"name": "weekends",
+			"time_intervals": [{"weekdays": ["saturday", "sunday"]}]
+		}],
+		"receivers": [{
+			"name": "slack",
+			"grafana_managed_receiver_configs": [{
+				"uid": "slack-uid",
+				"name": "slack",
+				"type": "slack",
+				"settings": {"recipient": "#alerts"},
+				"secureSettings": {"url": "encrypted url"}
+			}]
+		}, {
+			"name": "email",
+			"grafana_managed_receiver_configs": [{
+				"uid": "email-uid",
+				"name": "email",
+				"type": "email",
+				"settings": {"addresses": "team@example.com"}
+			}]
+		}]
+	}
+}
+`
+
+func TestEffectiveConfigService(t *testing.T) {
+	prov := NewFakeProvisioningStore()
+	ctx := context.Background()
+	require.NoError(t, prov.SetProvenance(ctx, &definitions.Route{}, 1, models.ProvenanceFile))
+	require.NoError(t, prov.SetProvenance(ctx, &definitions.EmbeddedContactPoint{UID: "slack-uid"}, 1, models.ProvenanceAPI))
+	require.NoError(t, prov.InsertProvisioningAuditEntry(ctx, &models.ProvisioningAuditEntry{
+		OrgID: 1, Action: models.ProvisioningAuditActionUpdate, ResourceType: "route",
+		Provenance: models.ProvenanceFile, Source: "/etc/grafana/provisioning/alerting/policies.yaml", Created: 100,
+	}))
+	require.NoError(t, prov.InsertProvisioningAuditEntry(ctx, &models.ProvisioningAuditEntry{
+		OrgID: 1, Action: models.ProvisioningAuditActionCreate, ResourceType: "contactPoint", ResourceID: "slack-uid",
+		Provenance: models.ProvenanceAPI, ActorLogin: "editor", Created: 200,
+	}))
+	sut := &EffectiveConfigService{
+		amStore:         newFakeAMConfigStore(effectiveConfigTestConfig),
+		provenanceStore: prov,
+		audit:           prov,
+		log:             log.NewNopLogger(),
+		tracer:          tracing.InitializeTracerForTest(),
+	}
+
+	cfg, err := sut.GetEffectiveConfig(ctx, 1)
+	require.NoError(t, err)
+
+	t.Run("annotates the policies with their provenance and source", func(t *testing.T) {
+		require.Equal(t, "slack", cfg.Policies.Route.Receiver)
+		require.Equal(t, definitions.Provenance(models.ProvenanceFile), cfg.Policies.Route.Provenance)
+		require.Equal(t, "/etc/grafana/provisioning/alerting/policies.yaml", cfg.Policies.Source.File)
+		require.Equal(t, int64(100), cfg.Policies.Source.Changed.Unix())
+	})
+
+	t.Run("returns contact points sorted by name with secure settings redacted", func(t *testing.T) {
+		require.Len(t, cfg.ContactPoints, 2)
+		require.Equal(t, "email", cfg.ContactPoints[0].ContactPoint.Name)
+		require.Nil(t, cfg.ContactPoints[0].Source.Changed)
+
+		slack := cfg.ContactPoints[1]
+		require.Equal(t, "slack", slack.ContactPoint.Name)
+		require.Equal(t, string(models.ProvenanceAPI), slack.ContactPoint.Provenance)
+		require.Equal(t, definitions.RedactedValue, slack.ContactPoint.Settings.Get("url").MustString())
+		require.Equal(t, "#alerts", slack.ContactPoint.Settings.Get("recipient").MustString())
+		require.Equal(t, "editor", slack.Source.Actor)
+		require.Empty(t, slack.Source.File)
+	})
+
+	t.Run("returns templates and mute timings sorted by name", func(t *testing.T) {
+		require.Len(t, cfg.Templates, 2)
+		require.Equal(t, "a", cfg.Templates[0].Template.Name)
+		require.Equal(t, "b", cfg.Templates[1].Template.Name)
+		require.Len(t, cfg.MuteTimings, 1)
+		require.Equal(t, "weekends", cfg.MuteTimings[0].MuteTiming.Name)
+		require.Equal(t, definitions.Provenance(models.ProvenanceNone), cfg.MuteTimings[0].MuteTiming.Provenance)
+	})
+}
@@ -1,10 +1,123 @@
 package provisioning
 
 import (
-	"errors"
 	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ErrorCode is a stable, machine-readable code of the kind of a provisioning error, so that clients can tell errors
+// that are worth retrying from errors that need a change of the request without parsing messages.
+type ErrorCode string
+
+const (
+	ErrCodeValidation       ErrorCode = "invalid"
+	ErrCodeNotFound         ErrorCode = "not-found"
+	ErrCodePermissionDenied ErrorCode = "permission-denied"
+	ErrCodeVersionConflict  ErrorCode = "version-conflict"
+	ErrCodeQuotaReached     ErrorCode = "quota-reached"
+	ErrCodeRateLimited      ErrorCode = "rate-limited"
+	ErrCodeFrozen           ErrorCode = "frozen"
+	// ErrCodeInternal is the code of errors that are not caused by the request.
+	ErrCodeInternal ErrorCode = "internal"
+)
+
+var errorCodeMessages = map[ErrorCode]string{
+	ErrCodeValidation:       "invalid object specification",
+	ErrCodeNotFound:         "object not found",
+	ErrCodePermissionDenied: "permission denied",
+	ErrCodeVersionConflict:  "object was changed since the given version",
+	ErrCodeQuotaReached:     "quota has been exceeded for notification resources",
+	ErrCodeRateLimited:      "too many configuration updates, try again later",
+	ErrCodeFrozen:           "provisioning is frozen for the organization",
+	ErrCodeInternal:         "internal error",
+}
+
+// Error is an error of the provisioning services. It carries the code of the kind of error and, if known, the path of
+// the offending field of the request and the resource that the error is about. Every error matches the sentinel error
+// of its code with errors.Is, such as ErrNotFound for errors with the code ErrCodeNotFound.
+type Error struct {
+	Code ErrorCode
+	// Reason describes the error. It is empty for the sentinel errors.
+	Reason string
+	// Field is the path of the offending field of the request, such as settings.url.
+	Field        string
+	ResourceType string
+	ResourceID   string
+	// Err is the error that caused this one, if any.
+	Err error
+}
+
+var (
+	ErrValidation       = &Error{Code: ErrCodeValidation}
+	ErrNotFound         = &Error{Code: ErrCodeNotFound}
+	ErrPermissionDenied = &Error{Code: ErrCodePermissionDenied}
+	ErrVersionConflict  = &Error{Code: ErrCodeVersionConflict}
+	// ErrQuotaReached is returned when a change would exceed the quota of a kind of notification resources in an org.
+	ErrQuotaReached = &Error{Code: ErrCodeQuotaReached, Err: models.ErrQuotaReached}
+	// ErrRateLimited is returned when an org updates its Alertmanager configuration more often than allowed.
+	ErrRateLimited = &Error{Code: ErrCodeRateLimited}
+	// ErrFrozen is returned when the provisioned resources of an org are changed while provisioning is frozen.
+	ErrFrozen = &Error{Code: ErrCodeFrozen}
 )
 
-var ErrValidation = fmt.Errorf("invalid object specification")
-var ErrNotFound = fmt.Errorf("object not found")
-var ErrPermissionDenied = errors.New("permission denied")
+func (e *Error) Error() string {
+	msg, ok := errorCodeMessages[e.Code]
+	if !ok {
+		msg = string(e.Code)
+	}
+	if e.Reason == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", msg, e.Reason)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the target is the sentinel error of the code of the error.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return t.Code == e.Code && t.Reason == "" && t.Field == "" && t.ResourceType == "" && t.ResourceID == ""
+}
+
+// newValidationError returns a validation error about the field at the given path of the request.
+func newValidationError(field string, format string, args ...any) *Error {
+	return &Error{
+		Code:   ErrCodeValidation,
+		Reason: fmt.Sprintf(format, args...),
+		Field:  field,
+	}
+}
+
+// newNotFoundError returns an error about a resource that does not exist.
+func newNotFoundError(resourceType, id string, format string, args ...any) *Error {
+	return &Error{
+		Code:         ErrCodeNotFound,
+		Reason:       fmt.Sprintf(format, args...),
+		ResourceType: resourceType,
+		ResourceID:   id,
+	}
+}
+
+// newVersionConflictError returns an error about a resource that was changed since the version given by the request.
+func newVersionConflictError(resourceType, id string, format string, args ...any) *Error {
+	return &Error{
+		Code:         ErrCodeVersionConflict,
+		Reason:       fmt.Sprintf(format, args...),
+		ResourceType: resourceType,
+		ResourceID:   id,
+	}
+}
+
+// withResource returns a copy of the error about the given resource.
+func (e *Error) withResource(resourceType, id string) *Error {
+	c := *e
+	c.ResourceType = resourceType
+	c.ResourceID = id
+	return &c
+}
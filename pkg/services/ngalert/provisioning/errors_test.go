@@ -0,0 +1,62 @@
+package provisioning
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestError(t *testing.T) {
+	t.Run("matches the sentinel error of its code", func(t *testing.T) {
+		err := newValidationError("settings", "settings should not be empty")
+
+		require.ErrorIs(t, err, ErrValidation)
+		require.NotErrorIs(t, err, ErrNotFound)
+		require.Equal(t, "invalid object specification: settings should not be empty", err.Error())
+	})
+
+	t.Run("sentinel errors do not match errors with details", func(t *testing.T) {
+		require.NotErrorIs(t, ErrNotFound, newNotFoundError("contactPoint", "a", "not found"))
+	})
+
+	t.Run("wrapped sentinel errors keep their code", func(t *testing.T) {
+		err := fmt.Errorf("%w: invalid policy tree", ErrValidation)
+
+		var provErr *Error
+		require.True(t, errors.As(err, &provErr))
+		require.Equal(t, ErrCodeValidation, provErr.Code)
+	})
+
+	t.Run("carries the resource of the error", func(t *testing.T) {
+		err := fmt.Errorf("failed to update: %w", newVersionConflictError("template", "a", "version mismatch"))
+
+		var provErr *Error
+		require.True(t, errors.As(err, &provErr))
+		require.Equal(t, ErrCodeVersionConflict, provErr.Code)
+		require.Equal(t, "template", provErr.ResourceType)
+		require.Equal(t, "a", provErr.ResourceID)
+		require.ErrorIs(t, err, ErrVersionConflict)
+	})
+
+	t.Run("quota errors match the quota error of the models", func(t *testing.T) {
+		err := fmt.Errorf("%w: templates", ErrQuotaReached)
+
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+	})
+
+	t.Run("services return errors with the offending field", func(t *testing.T) {
+		now := time.Now()
+		_, err := validateMaintenanceWindow(definitions.MaintenanceWindow{Start: now.Add(time.Hour), End: now}, now)
+
+		var provErr *Error
+		require.True(t, errors.As(err, &provErr))
+		require.Equal(t, "end", provErr.Field)
+	})
+}
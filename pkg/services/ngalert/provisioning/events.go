@@ -0,0 +1,185 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const (
+	// eventWebhookQueueSize is the number of events the webhook holds while a previous event is sent. Events that
+	// arrive while the queue is full are dropped.
+	eventWebhookQueueSize = 1000
+	eventWebhookTimeout   = 10 * time.Second
+)
+
+// EventPublisher publishes events on the event bus once the transaction of the context is committed.
+type EventPublisher interface {
+	PublishAfterCommit(ctx context.Context, msg any) error
+}
+
+// publishingProvisioningStore is a ProvisioningStore that publishes a models.ProvisioningChangeEvent for every change
+// added to the audit log, so that other services and outgoing webhooks can mirror the changes.
+type publishingProvisioningStore struct {
+	ProvisioningStore
+	publisher EventPublisher
+	log       log.Logger
+}
+
+// NewPublishingProvisioningStore returns a ProvisioningStore that publishes the changes recorded in the given store.
+// Events are best effort: failing to publish one does not fail the change.
+func NewPublishingProvisioningStore(store ProvisioningStore, publisher EventPublisher, log log.Logger) ProvisioningStore {
+	return &publishingProvisioningStore{
+		ProvisioningStore: store,
+		publisher:         publisher,
+		log:               log,
+	}
+}
+
+func (s *publishingProvisioningStore) InsertProvisioningAuditEntry(ctx context.Context, entry *models.ProvisioningAuditEntry) error {
+	if err := s.ProvisioningStore.InsertProvisioningAuditEntry(ctx, entry); err != nil {
+		return err
+	}
+	if err := s.publisher.PublishAfterCommit(ctx, provisioningChangeEvent(entry)); err != nil {
+		s.log.FromContext(ctx).Warn("Failed to publish provisioning change", "org", entry.OrgID,
+			"resourceType", entry.ResourceType, "resourceId", entry.ResourceID, "error", err)
+	}
+	return nil
+}
+
+func provisioningChangeEvent(entry *models.ProvisioningAuditEntry) *models.ProvisioningChangeEvent {
+	event := &models.ProvisioningChangeEvent{
+		OrgID:         entry.OrgID,
+		Action:        entry.Action,
+		ResourceType:  entry.ResourceType,
+		ResourceID:    entry.ResourceID,
+		Provenance:    entry.Provenance,
+		ActorID:       entry.ActorID,
+		ActorLogin:    entry.ActorLogin,
+		Source:        entry.Source,
+		CorrelationID: entry.CorrelationID,
+		Time:          time.Unix(entry.Created, 0).UTC(),
+	}
+	if entry.OldState != "" && entry.NewState != "" {
+		event.Changes = changedFields(entry.OldState, entry.NewState)
+	}
+	return event
+}
+
+// changedFields returns the sorted paths of the fields that differ between two JSON documents. Objects are compared
+// field by field, and any other values, including arrays, as a whole. Settings of receivers are JSON documents of
+// their own and are compared field by field as well.
+func changedFields(oldState, newState string) []string {
+	var o, n any
+	if err := json.Unmarshal([]byte(oldState), &o); err != nil {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(newState), &n); err != nil {
+		return nil
+	}
+	var changes []string
+	diffFields("", o, n, &changes)
+	sort.Strings(changes)
+	return changes
+}
+
+func diffFields(path string, o, n any, changes *[]string) {
+	om, oIsObject := o.(map[string]any)
+	nm, nIsObject := n.(map[string]any)
+	if !oIsObject || !nIsObject {
+		if !reflect.DeepEqual(o, n) {
+			*changes = append(*changes, path)
+		}
+		return
+	}
+	for k, ov := range om {
+		diffFields(joinPath(path, k), ov, nm[k], changes)
+	}
+	for k, nv := range nm {
+		if _, ok := om[k]; !ok {
+			diffFields(joinPath(path, k), nil, nv, changes)
+		}
+	}
+}
+
+func joinPath(path, field string) string {
+	if path == "" {
+		return field
+	}
+	return path + "." + field
+}
+
+// ProvisioningEventWebhook sends the provisioning change events it receives from the event bus to an outgoing
+// webhook, one JSON document per request. Events are sent in the background and in order. Events that cannot be
+// sent are logged and dropped.
+type ProvisioningEventWebhook struct {
+	url    string
+	client *http.Client
+	events chan *models.ProvisioningChangeEvent
+	log    log.Logger
+}
+
+func NewProvisioningEventWebhook(url string, log log.Logger) *ProvisioningEventWebhook {
+	return &ProvisioningEventWebhook{
+		url:    url,
+		client: &http.Client{Timeout: eventWebhookTimeout},
+		events: make(chan *models.ProvisioningChangeEvent, eventWebhookQueueSize),
+		log:    log,
+	}
+}
+
+// Handle queues the event to be sent. It is meant to be added as a listener of the event bus, and never fails so
+// that it does not hold up the other listeners.
+func (w *ProvisioningEventWebhook) Handle(ctx context.Context, e *models.ProvisioningChangeEvent) error {
+	select {
+	case w.events <- e:
+	default:
+		w.log.FromContext(ctx).Warn("Dropping provisioning change, the webhook queue is full", "org", e.OrgID,
+			"resourceType", e.ResourceType, "resourceId", e.ResourceID)
+	}
+	return nil
+}
+
+// Run sends the queued events until the context is done.
+func (w *ProvisioningEventWebhook) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-w.events:
+			if err := w.send(ctx, e); err != nil {
+				w.log.Error("Failed to send provisioning change to webhook", "org", e.OrgID,
+					"resourceType", e.ResourceType, "resourceId", e.ResourceID, "error", err)
+			}
+		}
+	}
+}
+
+func (w *ProvisioningEventWebhook) send(ctx context.Context, e *models.ProvisioningChangeEvent) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
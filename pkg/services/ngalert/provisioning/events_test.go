@@ -0,0 +1,93 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+type fakeEventPublisher struct {
+	events []any
+}
+
+func (p *fakeEventPublisher) PublishAfterCommit(_ context.Context, msg any) error {
+	p.events = append(p.events, msg)
+	return nil
+}
+
+func TestProvisioningChangeEvents(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+
+	t.Run("changes of contact points are published with the changed fields", func(t *testing.T) {
+		sut := createContactPointServiceSut(t, secretsService)
+		publisher := &fakeEventPublisher{}
+		sut.provenanceStore = NewPublishingProvisioningStore(sut.provenanceStore, publisher, log.NewNopLogger())
+
+		cp, err := sut.CreateContactPoint(context.Background(), 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		cp.Settings.Set("recipient", "new_recipient")
+		err = sut.UpdateContactPoint(context.Background(), 1, cp, models.ProvenanceAPI, UpdateContactPointOptions{})
+		require.NoError(t, err)
+
+		require.Len(t, publisher.events, 2)
+		created := publisher.events[0].(*models.ProvisioningChangeEvent)
+		require.Equal(t, models.ProvisioningAuditActionCreate, created.Action)
+		require.Equal(t, "contactPoint", created.ResourceType)
+		require.Equal(t, cp.UID, created.ResourceID)
+		require.Equal(t, models.ProvenanceAPI, created.Provenance)
+		require.Empty(t, created.Changes)
+		updated := publisher.events[1].(*models.ProvisioningChangeEvent)
+		require.Equal(t, models.ProvisioningAuditActionUpdate, updated.Action)
+		require.Equal(t, []string{"settings.recipient"}, updated.Changes)
+	})
+
+	t.Run("changed fields are compared field by field", func(t *testing.T) {
+		changes := changedFields(
+			`{"name":"a","settings":{"url":"x","keep":1},"list":[1,2]}`,
+			`{"name":"b","settings":{"url":"x","added":true},"list":[1,2]}`,
+		)
+		require.Equal(t, []string{"name", "settings.added", "settings.keep"}, changes)
+	})
+
+	t.Run("webhook sends the events it receives", func(t *testing.T) {
+		received := make(chan models.ProvisioningChangeEvent, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var e models.ProvisioningChangeEvent
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&e))
+			received <- e
+		}))
+		t.Cleanup(server.Close)
+		webhook := NewProvisioningEventWebhook(server.URL, log.NewNopLogger())
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		go func() { _ = webhook.Run(ctx) }()
+
+		err := webhook.Handle(context.Background(), &models.ProvisioningChangeEvent{
+			OrgID:        1,
+			Action:       models.ProvisioningAuditActionDelete,
+			ResourceType: "template",
+			ResourceID:   "my-template",
+		})
+		require.NoError(t, err)
+
+		select {
+		case e := <-received:
+			require.Equal(t, "my-template", e.ResourceID)
+			require.Equal(t, models.ProvisioningAuditActionDelete, e.Action)
+		case <-time.After(5 * time.Second):
+			t.Fatal("the event was not sent to the webhook")
+		}
+	})
+}
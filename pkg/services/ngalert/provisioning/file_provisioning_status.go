@@ -0,0 +1,123 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+)
+
+const (
+	fileProvisioningStatusNamespace = "ngalert.provisioning"
+	fileProvisioningStatusKey       = "file_provisioning_status"
+)
+
+// FileProvisioningStatus is the outcome of the last provisioning of the alerting resources of an organization from
+// files.
+type FileProvisioningStatus struct {
+	LastAttempt time.Time `json:"lastAttempt"`
+	LastSuccess time.Time `json:"lastSuccess"`
+	// Error is the error of the last attempt. It is empty if the last attempt succeeded.
+	Error string `json:"error,omitempty"`
+	// FileErrors are the errors of the files that failed to be applied when they changed, by file name. They are
+	// cleared when the file is applied or all files are provisioned again.
+	FileErrors map[string]string `json:"fileErrors,omitempty"`
+}
+
+// FileProvisioningStatusStore keeps the status of the provisioning of alerting resources from files per organization,
+// so that errors of file provisioning are still visible after the provisioning run that produced them.
+type FileProvisioningStatusStore struct {
+	kv kvstore.KVStore
+}
+
+func NewFileProvisioningStatusStore(kv kvstore.KVStore) *FileProvisioningStatusStore {
+	return &FileProvisioningStatusStore{kv: kv}
+}
+
+// GetStatus returns the file provisioning status of the organization, or nil if alerting resources were never
+// provisioned from files in the organization.
+func (s *FileProvisioningStatusStore) GetStatus(ctx context.Context, orgID int64) (*FileProvisioningStatus, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, fileProvisioningStatusKey)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, nil
+	}
+	var status FileProvisioningStatus
+	if err := json.Unmarshal([]byte(value), &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal file provisioning status: %w", err)
+	}
+	return &status, nil
+}
+
+// ProvisionedOrgs returns the organizations that have a file provisioning status.
+func (s *FileProvisioningStatusStore) ProvisionedOrgs(ctx context.Context) ([]int64, error) {
+	all, err := s.kv.GetAll(ctx, kvstore.AllOrganizations, fileProvisioningStatusNamespace)
+	if err != nil {
+		return nil, err
+	}
+	orgIDs := make([]int64, 0, len(all))
+	for orgID, values := range all {
+		if _, ok := values[fileProvisioningStatusKey]; ok {
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+	return orgIDs, nil
+}
+
+// RecordResult records the outcome of an attempt to provision the alerting resources of the organizations from files.
+func (s *FileProvisioningStatusStore) RecordResult(ctx context.Context, orgIDs []int64, provisioningErr error) error {
+	return s.update(ctx, orgIDs, func(status *FileProvisioningStatus) {
+		status.FileErrors = nil
+		status.setResult(provisioningErr)
+	})
+}
+
+// RecordFileResult records the outcome of an attempt to apply a single changed file to the organizations.
+func (s *FileProvisioningStatusStore) RecordFileResult(ctx context.Context, orgIDs []int64, file string, provisioningErr error) error {
+	return s.update(ctx, orgIDs, func(status *FileProvisioningStatus) {
+		delete(status.FileErrors, file)
+		if provisioningErr != nil {
+			if status.FileErrors == nil {
+				status.FileErrors = map[string]string{}
+			}
+			status.FileErrors[file] = provisioningErr.Error()
+		}
+		status.setResult(provisioningErr)
+	})
+}
+
+func (status *FileProvisioningStatus) setResult(provisioningErr error) {
+	now := time.Now().UTC()
+	status.LastAttempt = now
+	status.Error = ""
+	if provisioningErr != nil {
+		status.Error = provisioningErr.Error()
+	} else {
+		status.LastSuccess = now
+	}
+}
+
+func (s *FileProvisioningStatusStore) update(ctx context.Context, orgIDs []int64, apply func(status *FileProvisioningStatus)) error {
+	for _, orgID := range orgIDs {
+		status, err := s.GetStatus(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		if status == nil {
+			status = &FileProvisioningStatus{}
+		}
+		apply(status)
+		value, err := json.Marshal(status)
+		if err != nil {
+			return err
+		}
+		if err := s.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, fileProvisioningStatusKey, string(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+)
+
+func TestIntegrationFileProvisioningStatusStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	ctx := context.Background()
+	sut := NewFileProvisioningStatusStore(kvstore.ProvideService(db.InitTestDB(t)))
+
+	status, err := sut.GetStatus(ctx, 1)
+	require.NoError(t, err)
+	require.Nil(t, status)
+
+	require.NoError(t, sut.RecordResult(ctx, []int64{1, 2}, nil))
+	require.NoError(t, sut.RecordResult(ctx, []int64{2}, errors.New("invalid contact point")))
+
+	orgs, err := sut.ProvisionedOrgs(ctx)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []int64{1, 2}, orgs)
+
+	status, err = sut.GetStatus(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, status.Error)
+	require.Equal(t, status.LastAttempt, status.LastSuccess)
+
+	status, err = sut.GetStatus(ctx, 2)
+	require.NoError(t, err)
+	require.Equal(t, "invalid contact point", status.Error)
+	require.False(t, status.LastSuccess.IsZero())
+
+	require.NoError(t, sut.RecordResult(ctx, []int64{2}, nil))
+	status, err = sut.GetStatus(ctx, 2)
+	require.NoError(t, err)
+	require.Empty(t, status.Error)
+
+	require.NoError(t, sut.RecordFileResult(ctx, []int64{1}, "rules.yaml", errors.New("invalid rule")))
+	require.NoError(t, sut.RecordFileResult(ctx, []int64{1}, "policies.yaml", nil))
+	status, err = sut.GetStatus(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"rules.yaml": "invalid rule"}, status.FileErrors)
+
+	require.NoError(t, sut.RecordFileResult(ctx, []int64{1}, "rules.yaml", nil))
+	status, err = sut.GetStatus(ctx, 1)
+	require.NoError(t, err)
+	require.Empty(t, status.FileErrors)
+	require.Empty(t, status.Error)
+}
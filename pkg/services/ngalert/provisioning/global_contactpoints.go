@@ -0,0 +1,462 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const globalContactPointsKey = "global_contact_points"
+
+// GlobalContactPointService manages the contact points that are defined once for all organizations of the instance.
+// They are copied into the configuration of every organization, where they can be used in notification policies but
+// not changed. An organization overrides a global contact point by defining its own contact point with the same name.
+type GlobalContactPointService struct {
+	kv                kvstore.KVStore
+	amStore           AMConfigStore
+	encryptionService secrets.Service
+	provenanceStore   ProvisioningStore
+	xact              TransactionManager
+	orgs              store.OrgStore
+	log               log.Logger
+	tracer            tracing.Tracer
+	metrics           *metrics.Provisioning
+}
+
+func NewGlobalContactPointService(kv kvstore.KVStore, amStore AMConfigStore, encryptionService secrets.Service,
+	provenanceStore ProvisioningStore, xact TransactionManager, orgs store.OrgStore, log log.Logger, tracer tracing.Tracer,
+	m *metrics.Provisioning) *GlobalContactPointService {
+	return &GlobalContactPointService{
+		kv:                kv,
+		amStore:           newTracedAMConfigStore(amStore, tracer, log, m),
+		encryptionService: newTracedSecretsService(encryptionService, tracer),
+		provenanceStore:   provenanceStore,
+		xact:              xact,
+		orgs:              orgs,
+		log:               log,
+		tracer:            tracer,
+		metrics:           m,
+	}
+}
+
+// GetGlobalContactPoints returns the global contact points with their secure settings redacted.
+func (svc *GlobalContactPointService) GetGlobalContactPoints(ctx context.Context) (_ []apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalContactPoint", "GetGlobalContactPoints", 0)
+	defer func() { done(err) }()
+
+	receivers, err := svc.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]apimodels.EmbeddedContactPoint, 0, len(receivers))
+	for _, r := range receivers {
+		settings, err := simplejson.NewJson(r.Settings)
+		if err != nil {
+			return nil, err
+		}
+		for k := range r.SecureSettings {
+			settings.Set(k, apimodels.RedactedValue)
+		}
+		result = append(result, apimodels.EmbeddedContactPoint{
+			UID:                   r.UID,
+			Name:                  r.Name,
+			Type:                  r.Type,
+			DisableResolveMessage: r.DisableResolveMessage,
+			Settings:              settings,
+		})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].UID < result[j].UID
+	})
+	return result, nil
+}
+
+// CreateGlobalContactPoint adds a global contact point and copies it into all organizations.
+func (svc *GlobalContactPointService) CreateGlobalContactPoint(ctx context.Context, contactPoint apimodels.EmbeddedContactPoint) (_ apimodels.EmbeddedContactPoint, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalContactPoint", "CreateGlobalContactPoint", 0,
+		attribute.String("contact_point_type", contactPoint.Type))
+	defer func() { done(err) }()
+
+	if err := ValidateContactPoint(ctx, contactPoint, svc.encryptionService.GetDecryptedValue); err != nil {
+		return apimodels.EmbeddedContactPoint{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	receivers, err := svc.load(ctx)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	if contactPoint.UID == "" {
+		contactPoint.UID = util.GenerateShortUID()
+	}
+	for _, r := range receivers {
+		if r.UID == contactPoint.UID {
+			return apimodels.EmbeddedContactPoint{}, newValidationError("uid", "global contact point with UID '%s' already exists", contactPoint.UID)
+		}
+	}
+	receiver, err := svc.toReceiver(ctx, &contactPoint)
+	if err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	if err := svc.save(ctx, append(receivers, receiver)); err != nil {
+		return apimodels.EmbeddedContactPoint{}, err
+	}
+	for k := range receiver.SecureSettings {
+		contactPoint.Settings.Set(k, apimodels.RedactedValue)
+	}
+	return contactPoint, svc.SyncGlobalContactPoints(ctx)
+}
+
+// UpdateGlobalContactPoint changes a global contact point in all organizations that did not override it. Redacted
+// secure settings keep their current value.
+func (svc *GlobalContactPointService) UpdateGlobalContactPoint(ctx context.Context, contactPoint apimodels.EmbeddedContactPoint) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalContactPoint", "UpdateGlobalContactPoint", 0,
+		attribute.String("contact_point_uid", contactPoint.UID), attribute.String("contact_point_type", contactPoint.Type))
+	defer func() { done(err) }()
+
+	if contactPoint.Settings == nil {
+		return newValidationError("settings", "settings should not be empty")
+	}
+	receivers, err := svc.load(ctx)
+	if err != nil {
+		return err
+	}
+	pos := -1
+	for i, r := range receivers {
+		if r.UID == contactPoint.UID {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return newNotFoundError((&apimodels.EmbeddedContactPoint{}).ResourceType(), contactPoint.UID, "global contact point with UID '%s' not found", contactPoint.UID)
+	}
+	secretKeys, err := GetSecretKeysForContactPointType(contactPoint.Type)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	for _, secretKey := range secretKeys {
+		if contactPoint.Settings.Get(secretKey).MustString() != apimodels.RedactedValue {
+			continue
+		}
+		stored, ok := receivers[pos].SecureSettings[secretKey]
+		if !ok {
+			continue
+		}
+		decrypted, err := svc.decrypt(ctx, stored)
+		if err != nil {
+			return err
+		}
+		contactPoint.Settings.Set(secretKey, decrypted)
+	}
+	if err := ValidateContactPoint(ctx, contactPoint, svc.encryptionService.GetDecryptedValue); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	receiver, err := svc.toReceiver(ctx, &contactPoint)
+	if err != nil {
+		return err
+	}
+	receivers[pos] = receiver
+	if err := svc.save(ctx, receivers); err != nil {
+		return err
+	}
+	return svc.SyncGlobalContactPoints(ctx)
+}
+
+// DeleteGlobalContactPoint removes a global contact point from all organizations. It fails if an organization uses
+// the contact point in its notification policies and has no other contact point with the same name.
+func (svc *GlobalContactPointService) DeleteGlobalContactPoint(ctx context.Context, uid string) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalContactPoint", "DeleteGlobalContactPoint", 0,
+		attribute.String("contact_point_uid", uid))
+	defer func() { done(err) }()
+
+	receivers, err := svc.load(ctx)
+	if err != nil {
+		return err
+	}
+	remaining := make([]*apimodels.PostableGrafanaReceiver, 0, len(receivers))
+	for _, r := range receivers {
+		if r.UID != uid {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(remaining) == len(receivers) {
+		return newNotFoundError((&apimodels.EmbeddedContactPoint{}).ResourceType(), uid, "global contact point with UID '%s' not found", uid)
+	}
+
+	orgIDs, err := svc.orgs.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	var usedIn []int64
+	for _, orgID := range orgIDs {
+		revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if removed, fullRemoval := revision.receivers().remove(uid); removed != nil && fullRemoval &&
+			isContactPointInUse(removed.Name, []*apimodels.Route{revision.cfg.AlertmanagerConfig.Route}) {
+			usedIn = append(usedIn, orgID)
+		}
+	}
+	if len(usedIn) > 0 {
+		return fmt.Errorf("%w: global contact point is used by notification policies in organizations %v", ErrValidation, usedIn)
+	}
+
+	if err := svc.save(ctx, remaining); err != nil {
+		return err
+	}
+	return svc.SyncGlobalContactPoints(ctx)
+}
+
+// SyncGlobalContactPoints brings the copies of the global contact points in all organizations up to date. It is done
+// after every change of the global contact points and when Grafana starts, so that organizations created in the
+// meantime inherit them as well.
+func (svc *GlobalContactPointService) SyncGlobalContactPoints(ctx context.Context) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalContactPoint", "SyncGlobalContactPoints", 0)
+	defer func() { done(err) }()
+
+	receivers, err := svc.load(ctx)
+	if err != nil {
+		return err
+	}
+	orgIDs, err := svc.orgs.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, orgID := range orgIDs {
+		err := updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+			return svc.syncOrg(ctx, orgID, receivers)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update the global contact points of organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncOrg updates the copies of the global contact points in the configuration of an organization. Global contact
+// points whose name is taken by a contact point of the organization are left out.
+func (svc *GlobalContactPointService) syncOrg(ctx context.Context, orgID int64, global []*apimodels.PostableGrafanaReceiver) error {
+	revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		// The configuration of a new organization is created by its Alertmanager, it is synchronized on the next run.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&apimodels.EmbeddedContactPoint{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	idx := revision.receivers()
+	inherited := func(uid string) bool {
+		return provenances[uid] == models.ProvenanceGlobal
+	}
+	type change struct {
+		action   models.ProvisioningAuditAction
+		uid      string
+		oldState *apimodels.PostableGrafanaReceiver
+		newState *apimodels.PostableGrafanaReceiver
+	}
+	var changes []change
+
+	wanted := make(map[string]struct{}, len(global))
+	for _, r := range global {
+		wanted[r.UID] = struct{}{}
+	}
+	for uid := range provenances {
+		if _, ok := wanted[uid]; ok || !inherited(uid) {
+			continue
+		}
+		loc, ok := idx.receiver(uid)
+		if !ok {
+			changes = append(changes, change{action: models.ProvisioningAuditActionDelete, uid: uid})
+			continue
+		}
+		if len(loc.group.GrafanaManagedReceivers) == 1 &&
+			isContactPointInUse(loc.group.Name, []*apimodels.Route{revision.cfg.AlertmanagerConfig.Route}) {
+			svc.log.FromContext(ctx).Warn("Keeping removed global contact point that is used by notification policies", "org", orgID, "uid", uid)
+			continue
+		}
+		removed, _ := idx.remove(uid)
+		changes = append(changes, change{action: models.ProvisioningAuditActionDelete, uid: uid, oldState: redactedReceiver(removed)})
+	}
+
+	for _, r := range global {
+		target := *r
+		loc, exists := idx.receiver(r.UID)
+		switch {
+		case exists && inherited(r.UID):
+			if sameReceiver(loc.receiver, &target) {
+				continue
+			}
+			if group, ok := idx.group(target.Name); ok && group != loc.group && !onlyInherited(group, inherited) {
+				// The new name is taken by a contact point of the organization, which keeps precedence.
+				continue
+			}
+			old := redactedReceiver(loc.receiver)
+			stitchReceiver(idx, &target, nil)
+			changes = append(changes, change{action: models.ProvisioningAuditActionUpdate, uid: r.UID, oldState: old, newState: redactedReceiver(&target)})
+		case exists:
+			svc.log.FromContext(ctx).Warn("Skipping global contact point whose UID is used by a contact point of the organization", "org", orgID, "uid", r.UID)
+		default:
+			if group, ok := idx.group(target.Name); ok && !onlyInherited(group, inherited) {
+				continue
+			}
+			idx.add(&target)
+			changes = append(changes, change{action: models.ProvisioningAuditActionCreate, uid: r.UID, newState: redactedReceiver(&target)})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(revision.cfg)
+	if err != nil {
+		return err
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := PersistConfig(ctx, svc.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		})
+		if err != nil {
+			return err
+		}
+		for _, c := range changes {
+			target := &apimodels.EmbeddedContactPoint{UID: c.uid}
+			if c.action == models.ProvisioningAuditActionDelete {
+				err = svc.provenanceStore.DeleteProvenance(ctx, target, orgID)
+			} else {
+				err = svc.provenanceStore.SetProvenance(ctx, target, orgID, models.ProvenanceGlobal)
+			}
+			if err != nil {
+				return err
+			}
+			// A nil pointer in an interface is not nil, so absent states are passed explicitly.
+			var oldState, newState any
+			if c.oldState != nil {
+				oldState = c.oldState
+			}
+			if c.newState != nil {
+				newState = c.newState
+			}
+			if err := recordAudit(ctx, svc.provenanceStore, orgID, c.action, target, models.ProvenanceGlobal, oldState, newState); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// onlyInherited returns true if all contact points of the receiver group are copies of global contact points.
+func onlyInherited(group *apimodels.PostableApiReceiver, inherited func(uid string) bool) bool {
+	for _, r := range group.GrafanaManagedReceivers {
+		if !inherited(r.UID) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameReceiver(a, b *apimodels.PostableGrafanaReceiver) bool {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(left) == string(right)
+}
+
+// toReceiver converts a contact point to a receiver with encrypted secure settings. The secure settings are removed
+// from the contact point.
+func (svc *GlobalContactPointService) toReceiver(ctx context.Context, contactPoint *apimodels.EmbeddedContactPoint) (*apimodels.PostableGrafanaReceiver, error) {
+	extractedSecrets, err := RemoveSecretsForContactPoint(contactPoint)
+	if err != nil {
+		return nil, err
+	}
+	encrypted, err := svc.encryptionService.EncryptJsonData(ctx, extractedSecrets, secrets.WithoutScope())
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt secure settings: %w", err)
+	}
+	secureSettings := make(map[string]string, len(encrypted))
+	for k, v := range encrypted {
+		secureSettings[k] = base64.StdEncoding.EncodeToString(v)
+	}
+	settings, err := contactPoint.Settings.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	return &apimodels.PostableGrafanaReceiver{
+		UID:                   contactPoint.UID,
+		Name:                  contactPoint.Name,
+		Type:                  contactPoint.Type,
+		DisableResolveMessage: contactPoint.DisableResolveMessage,
+		Settings:              settings,
+		SecureSettings:        secureSettings,
+		Probe:                 contactPoint.Probe,
+	}, nil
+}
+
+func (svc *GlobalContactPointService) decrypt(ctx context.Context, value string) (string, error) {
+	decoded, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", err
+	}
+	decrypted, err := svc.encryptionService.Decrypt(ctx, decoded)
+	if err != nil {
+		return "", err
+	}
+	return string(decrypted), nil
+}
+
+func (svc *GlobalContactPointService) load(ctx context.Context) ([]*apimodels.PostableGrafanaReceiver, error) {
+	value, ok, err := svc.kv.Get(ctx, 0, fileProvisioningStatusNamespace, globalContactPointsKey)
+	if err != nil {
+		return nil, err
+	}
+	var receivers []*apimodels.PostableGrafanaReceiver
+	if !ok {
+		return receivers, nil
+	}
+	if err := json.Unmarshal([]byte(value), &receivers); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal global contact points: %w", err)
+	}
+	return receivers, nil
+}
+
+func (svc *GlobalContactPointService) save(ctx context.Context, receivers []*apimodels.PostableGrafanaReceiver) error {
+	value, err := json.Marshal(receivers)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, 0, fileProvisioningStatusNamespace, globalContactPointsKey, string(value))
+}
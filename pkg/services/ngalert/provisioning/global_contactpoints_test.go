@@ -0,0 +1,169 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestGlobalContactPointService(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	t.Run("global contact points are copied into the organizations", func(t *testing.T) {
+		sut, cps := createGlobalContactPointServiceSut(t, secretsService)
+
+		created, err := sut.CreateGlobalContactPoint(ctx, createTestContactPoint())
+		require.NoError(t, err)
+		require.Equal(t, definitions.RedactedValue, created.Settings.Get("token").MustString())
+
+		global, err := sut.GetGlobalContactPoints(ctx)
+		require.NoError(t, err)
+		require.Len(t, global, 1)
+		require.Equal(t, created.UID, global[0].UID)
+		require.Equal(t, definitions.RedactedValue, global[0].Settings.Get("token").MustString())
+
+		inherited := getContactPoint(t, cps, created.UID)
+		require.Equal(t, string(models.ProvenanceGlobal), inherited.Provenance)
+		require.Equal(t, "value_token", inherited.Settings.Get("token").MustString())
+	})
+
+	t.Run("changes of global contact points are applied to the organizations", func(t *testing.T) {
+		sut, cps := createGlobalContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateGlobalContactPoint(ctx, createTestContactPoint())
+		require.NoError(t, err)
+
+		created.Settings.Set("recipient", "new_recipient")
+		require.NoError(t, sut.UpdateGlobalContactPoint(ctx, created))
+
+		inherited := getContactPoint(t, cps, created.UID)
+		require.Equal(t, "new_recipient", inherited.Settings.Get("recipient").MustString())
+		require.Equal(t, "value_token", inherited.Settings.Get("token").MustString(), "redacted secure settings should be kept")
+
+		require.NoError(t, sut.DeleteGlobalContactPoint(ctx, created.UID))
+		all, err := cps.GetContactPoints(ctx, cpsQuery(1), nil)
+		require.NoError(t, err)
+		for _, cp := range all {
+			require.NotEqual(t, created.UID, cp.UID)
+		}
+	})
+
+	t.Run("inherited contact points are read-only in the organizations", func(t *testing.T) {
+		sut, cps := createGlobalContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateGlobalContactPoint(ctx, createTestContactPoint())
+		require.NoError(t, err)
+
+		err = cps.DeleteContactPoint(ctx, 1, created.UID, DeleteContactPointOptions{})
+		require.ErrorIs(t, err, ErrValidation)
+
+		created.Settings.Set("token", "value_token")
+		err = cps.UpdateContactPoint(ctx, 1, created, models.ProvenanceAPI, UpdateContactPointOptions{})
+		require.Error(t, err)
+	})
+
+	t.Run("contact points of an organization override global contact points with the same name", func(t *testing.T) {
+		sut, cps := createGlobalContactPointServiceSut(t, secretsService)
+		created, err := sut.CreateGlobalContactPoint(ctx, createTestContactPoint())
+		require.NoError(t, err)
+
+		own, err := cps.CreateContactPoint(ctx, 1, createTestContactPoint(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		q := cpsQuery(1)
+		q.Name = own.Name
+		named, err := cps.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, named, 1)
+		require.Equal(t, own.UID, named[0].UID)
+
+		// The override is kept when the global contact points are synchronized again.
+		created.Settings.Set("recipient", "new_recipient")
+		require.NoError(t, sut.UpdateGlobalContactPoint(ctx, created))
+		named, err = cps.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, named, 1)
+		require.Equal(t, own.UID, named[0].UID)
+	})
+
+	t.Run("global contact points used by notification policies cannot be deleted", func(t *testing.T) {
+		sut, _ := createGlobalContactPointServiceSut(t, secretsService)
+		used := createTestContactPoint()
+		used.Name = "used-by-policies"
+		created, err := sut.CreateGlobalContactPoint(ctx, used)
+		require.NoError(t, err)
+
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		revision.cfg.AlertmanagerConfig.Route.Routes[0].Receiver = used.Name
+		data, err := json.Marshal(revision.cfg)
+		require.NoError(t, err)
+		require.NoError(t, PersistConfig(ctx, sut.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(data),
+			OrgID:                     1,
+		}))
+
+		err = sut.DeleteGlobalContactPoint(ctx, created.UID)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("unknown global contact points are not found", func(t *testing.T) {
+		sut, _ := createGlobalContactPointServiceSut(t, secretsService)
+
+		err := sut.DeleteGlobalContactPoint(ctx, "unknown")
+		require.ErrorIs(t, err, ErrNotFound)
+
+		cp := createTestContactPoint()
+		cp.UID = "unknown"
+		err = sut.UpdateGlobalContactPoint(ctx, cp)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func createGlobalContactPointServiceSut(t *testing.T, secretService secrets.Service) (*GlobalContactPointService, *ContactPointService) {
+	cps := createContactPointServiceSut(t, secretService)
+	sut := &GlobalContactPointService{
+		kv:                kvstore.NewFakeKVStore(),
+		amStore:           cps.amStore,
+		encryptionService: secretService,
+		provenanceStore:   cps.provenanceStore,
+		xact:              newNopTransactionManager(),
+		orgs:              fakeOrgStore{orgs: []int64{1}},
+		log:               log.NewNopLogger(),
+		tracer:            tracing.InitializeTracerForTest(),
+	}
+	return sut, cps
+}
+
+// getContactPoint returns the contact point of the organization with decrypted secure settings and its provenance.
+func getContactPoint(t *testing.T, cps *ContactPointService, uid string) definitions.EmbeddedContactPoint {
+	t.Helper()
+	revision, err := getLastConfiguration(context.Background(), 1, cps.amStore)
+	require.NoError(t, err)
+	cp, err := cps.getContactPointDecrypted(revision, uid)
+	require.NoError(t, err)
+	provenance, err := cps.provenanceStore.GetProvenance(context.Background(), &cp, 1)
+	require.NoError(t, err)
+	cp.Provenance = string(provenance)
+	return cp
+}
+
+type fakeOrgStore struct {
+	orgs []int64
+}
+
+func (f fakeOrgStore) GetOrgs(context.Context) ([]int64, error) {
+	return f.orgs, nil
+}
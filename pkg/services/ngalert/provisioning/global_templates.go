@@ -0,0 +1,274 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+const globalTemplatesKey = "global_templates"
+
+// GlobalTemplateService manages the notification templates that are defined once for all organizations of the
+// instance. They are copied into the template set of every organization, where they can be used by contact points but
+// not deleted. An organization overrides a global template by defining its own template with the same name.
+type GlobalTemplateService struct {
+	kv              kvstore.KVStore
+	amStore         AMConfigStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	orgs            store.OrgStore
+	log             log.Logger
+	tracer          tracing.Tracer
+	metrics         *metrics.Provisioning
+}
+
+func NewGlobalTemplateService(kv kvstore.KVStore, amStore AMConfigStore, provenanceStore ProvisioningStore,
+	xact TransactionManager, orgs store.OrgStore, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *GlobalTemplateService {
+	return &GlobalTemplateService{
+		kv:              kv,
+		amStore:         newTracedAMConfigStore(amStore, tracer, log, m),
+		provenanceStore: provenanceStore,
+		xact:            xact,
+		orgs:            orgs,
+		log:             log,
+		tracer:          tracer,
+		metrics:         m,
+	}
+}
+
+// GetGlobalTemplates returns the global templates ordered by name.
+func (svc *GlobalTemplateService) GetGlobalTemplates(ctx context.Context) (_ []definitions.NotificationTemplate, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalTemplate", "GetGlobalTemplates", 0)
+	defer func() { done(err) }()
+
+	templates, err := svc.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.NotificationTemplate, 0, len(templates))
+	for name, content := range templates {
+		result = append(result, definitions.NotificationTemplate{Name: name, Template: content, Provenance: definitions.Provenance(models.ProvenanceGlobal)})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// SetGlobalTemplate creates or updates a global template and copies it into all organizations.
+func (svc *GlobalTemplateService) SetGlobalTemplate(ctx context.Context, tmpl definitions.NotificationTemplate) (_ definitions.NotificationTemplate, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalTemplate", "SetGlobalTemplate", 0,
+		attribute.String("template_name", tmpl.Name))
+	defer func() { done(err) }()
+
+	if err := tmpl.Validate(); err != nil {
+		return definitions.NotificationTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	templates, err := svc.load(ctx)
+	if err != nil {
+		return definitions.NotificationTemplate{}, err
+	}
+	templates[tmpl.Name] = tmpl.Template
+	if err := svc.save(ctx, templates); err != nil {
+		return definitions.NotificationTemplate{}, err
+	}
+	tmpl.Provenance = definitions.Provenance(models.ProvenanceGlobal)
+	return tmpl, svc.SyncGlobalTemplates(ctx)
+}
+
+// DeleteGlobalTemplate removes a global template from all organizations that did not override it.
+func (svc *GlobalTemplateService) DeleteGlobalTemplate(ctx context.Context, name string) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalTemplate", "DeleteGlobalTemplate", 0,
+		attribute.String("template_name", name))
+	defer func() { done(err) }()
+
+	templates, err := svc.load(ctx)
+	if err != nil {
+		return err
+	}
+	if _, ok := templates[name]; !ok {
+		return newNotFoundError((&definitions.NotificationTemplate{}).ResourceType(), name, "global template '%s' not found", name)
+	}
+	delete(templates, name)
+	if err := svc.save(ctx, templates); err != nil {
+		return err
+	}
+	return svc.SyncGlobalTemplates(ctx)
+}
+
+// SyncGlobalTemplates brings the copies of the global templates in all organizations up to date. It is done after
+// every change of the global templates and when Grafana starts, so that organizations created in the meantime
+// inherit them as well.
+func (svc *GlobalTemplateService) SyncGlobalTemplates(ctx context.Context) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "globalTemplate", "SyncGlobalTemplates", 0)
+	defer func() { done(err) }()
+
+	templates, err := svc.load(ctx)
+	if err != nil {
+		return err
+	}
+	orgIDs, err := svc.orgs.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, orgID := range orgIDs {
+		err := updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+			return svc.syncOrg(ctx, orgID, templates)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to update the global templates of organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// syncOrg updates the copies of the global templates in the configuration of an organization. Global templates whose
+// name is taken by a template of the organization are left out.
+func (svc *GlobalTemplateService) syncOrg(ctx context.Context, orgID int64, global map[string]string) error {
+	revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		// The configuration of a new organization is created by its Alertmanager, it is synchronized on the next run.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, (&definitions.NotificationTemplate{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	if revision.cfg.TemplateFiles == nil {
+		revision.cfg.TemplateFiles = map[string]string{}
+	}
+	type change struct {
+		action   models.ProvisioningAuditAction
+		name     string
+		oldState any
+		newState any
+	}
+	var changes []change
+
+	var removed []string
+	for name, provenance := range provenances {
+		if _, ok := global[name]; !ok && provenance == models.ProvenanceGlobal {
+			removed = append(removed, name)
+		}
+	}
+	sort.Strings(removed)
+	for _, name := range removed {
+		var oldState any
+		if existing, ok := revision.cfg.TemplateFiles[name]; ok {
+			oldState = definitions.NotificationTemplate{Name: name, Template: existing}
+		}
+		delete(revision.cfg.TemplateFiles, name)
+		changes = append(changes, change{action: models.ProvisioningAuditActionDelete, name: name, oldState: oldState})
+	}
+	names := make([]string, 0, len(global))
+	for name := range global {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		content := global[name]
+		existing, exists := revision.cfg.TemplateFiles[name]
+		switch {
+		case exists && provenances[name] != models.ProvenanceGlobal:
+			// The template of the organization keeps precedence.
+			continue
+		case exists && existing == content:
+			continue
+		case exists:
+			revision.cfg.TemplateFiles[name] = content
+			changes = append(changes, change{
+				action:   models.ProvisioningAuditActionUpdate,
+				name:     name,
+				oldState: definitions.NotificationTemplate{Name: name, Template: existing},
+				newState: definitions.NotificationTemplate{Name: name, Template: content},
+			})
+		default:
+			revision.cfg.TemplateFiles[name] = content
+			changes = append(changes, change{
+				action:   models.ProvisioningAuditActionCreate,
+				name:     name,
+				newState: definitions.NotificationTemplate{Name: name, Template: content},
+			})
+		}
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+	tmpls := make([]string, 0, len(revision.cfg.TemplateFiles))
+	for name := range revision.cfg.TemplateFiles {
+		tmpls = append(tmpls, name)
+	}
+	sort.Strings(tmpls)
+	revision.cfg.AlertmanagerConfig.Templates = tmpls
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return err
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		err := PersistConfig(ctx, svc.amStore, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: string(serialized),
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		})
+		if err != nil {
+			return err
+		}
+		for _, c := range changes {
+			target := &definitions.NotificationTemplate{Name: c.name}
+			if c.action == models.ProvisioningAuditActionDelete {
+				err = svc.provenanceStore.DeleteProvenance(ctx, target, orgID)
+			} else {
+				err = svc.provenanceStore.SetProvenance(ctx, target, orgID, models.ProvenanceGlobal)
+			}
+			if err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, svc.provenanceStore, orgID, c.action, target, models.ProvenanceGlobal, c.oldState, c.newState); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (svc *GlobalTemplateService) load(ctx context.Context) (map[string]string, error) {
+	value, ok, err := svc.kv.Get(ctx, 0, fileProvisioningStatusNamespace, globalTemplatesKey)
+	if err != nil {
+		return nil, err
+	}
+	templates := map[string]string{}
+	if !ok {
+		return templates, nil
+	}
+	if err := json.Unmarshal([]byte(value), &templates); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal global templates: %w", err)
+	}
+	return templates, nil
+}
+
+func (svc *GlobalTemplateService) save(ctx context.Context, templates map[string]string) error {
+	value, err := json.Marshal(templates)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, 0, fileProvisioningStatusNamespace, globalTemplatesKey, string(value))
+}
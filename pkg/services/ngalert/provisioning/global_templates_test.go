@@ -0,0 +1,153 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestGlobalTemplateService(t *testing.T) {
+	ctx := context.Background()
+	global := definitions.NotificationTemplate{Name: "global", Template: `{{ define "global" }}global{{ end }}`}
+
+	t.Run("global templates are copied into the organizations", func(t *testing.T) {
+		sut, templates := createGlobalTemplateServiceSut(t)
+
+		_, err := sut.SetGlobalTemplate(ctx, global)
+		require.NoError(t, err)
+
+		inherited, err := templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, global.Template, inherited["global"])
+		provenance, err := sut.provenanceStore.GetProvenance(ctx, &definitions.NotificationTemplate{Name: "global"}, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceGlobal, provenance)
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		require.Contains(t, revision.cfg.AlertmanagerConfig.Templates, "global")
+	})
+
+	t.Run("changes of global templates are applied to the organizations", func(t *testing.T) {
+		sut, templates := createGlobalTemplateServiceSut(t)
+		_, err := sut.SetGlobalTemplate(ctx, global)
+		require.NoError(t, err)
+
+		changed := global
+		changed.Template = `{{ define "global" }}changed{{ end }}`
+		_, err = sut.SetGlobalTemplate(ctx, changed)
+		require.NoError(t, err)
+		inherited, err := templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, changed.Template, inherited["global"])
+
+		require.NoError(t, sut.DeleteGlobalTemplate(ctx, "global"))
+		inherited, err = templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.NotContains(t, inherited, "global")
+	})
+
+	t.Run("the organizations are updated in the lock of their configuration", func(t *testing.T) {
+		sut, templates := createGlobalTemplateServiceSut(t)
+		held, release := make(chan struct{}), make(chan struct{})
+		go func() {
+			_ = updateAlertmanagerConfig(ctx, 1, func(ctx context.Context) error {
+				close(held)
+				<-release
+				return nil
+			})
+		}()
+		<-held
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := sut.SetGlobalTemplate(ctx, global)
+			done <- err
+		}()
+		select {
+		case <-done:
+			t.Fatal("global templates were synchronized while the configuration of the organization was locked")
+		case <-time.After(50 * time.Millisecond):
+		}
+		close(release)
+
+		require.NoError(t, <-done)
+		inherited, err := templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, global.Template, inherited["global"])
+	})
+
+	t.Run("inherited templates cannot be deleted in the organizations", func(t *testing.T) {
+		sut, templates := createGlobalTemplateServiceSut(t)
+		_, err := sut.SetGlobalTemplate(ctx, global)
+		require.NoError(t, err)
+
+		err = templates.DeleteTemplate(ctx, 1, "global")
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("templates of an organization override global templates with the same name", func(t *testing.T) {
+		sut, templates := createGlobalTemplateServiceSut(t)
+		_, err := sut.SetGlobalTemplate(ctx, global)
+		require.NoError(t, err)
+
+		own := definitions.NotificationTemplate{Name: "global", Template: `{{ define "global" }}own{{ end }}`, Provenance: definitions.Provenance(models.ProvenanceAPI)}
+		_, err = templates.SetTemplate(ctx, 1, own)
+		require.NoError(t, err)
+
+		// The override is kept when the global templates are synchronized again.
+		changed := global
+		changed.Template = `{{ define "global" }}changed{{ end }}`
+		_, err = sut.SetGlobalTemplate(ctx, changed)
+		require.NoError(t, err)
+		inherited, err := templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, own.Template, inherited["global"])
+
+		require.NoError(t, sut.DeleteGlobalTemplate(ctx, "global"))
+		inherited, err = templates.GetTemplates(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, own.Template, inherited["global"])
+	})
+
+	t.Run("unknown global templates are not found", func(t *testing.T) {
+		sut, _ := createGlobalTemplateServiceSut(t)
+
+		err := sut.DeleteGlobalTemplate(ctx, "unknown")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func createGlobalTemplateServiceSut(t *testing.T) (*GlobalTemplateService, *TemplateService) {
+	t.Helper()
+	serialized, err := serializeAlertmanagerConfig(*createTestAlertingConfig())
+	require.NoError(t, err)
+	amStore := newFakeAMConfigStore(string(serialized))
+	prov := NewFakeProvisioningStore()
+	sut := &GlobalTemplateService{
+		kv:              kvstore.NewFakeKVStore(),
+		amStore:         amStore,
+		provenanceStore: prov,
+		xact:            newNopTransactionManager(),
+		orgs:            fakeOrgStore{orgs: []int64{1}},
+		log:             log.NewNopLogger(),
+		tracer:          tracing.InitializeTracerForTest(),
+	}
+	templates := &TemplateService{
+		config: amStore,
+		prov:   prov,
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
+	}
+	return sut, templates
+}
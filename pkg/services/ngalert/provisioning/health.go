@@ -0,0 +1,114 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// ConfigHealth summarizes the state of the alerting configuration of an organization.
+type ConfigHealth struct {
+	// LastConfigSave is the time the current Alertmanager configuration was saved. It is zero if the organization has
+	// no configuration yet.
+	LastConfigSave time.Time
+	// ConfigSize is the size of the current Alertmanager configuration in bytes.
+	ConfigSize int
+	// FileProvisioning is the status of the provisioning of the organization's alerting resources from files. It is
+	// nil if nothing was provisioned from files in the organization.
+	FileProvisioning *FileProvisioningStatus
+	// InvalidReceivers are the integrations of the configuration that fail validation when loaded.
+	InvalidReceivers []InvalidReceiver
+}
+
+// InvalidReceiver is an integration of a contact point that fails validation.
+type InvalidReceiver struct {
+	UID   string
+	Name  string
+	Type  string
+	Error string
+}
+
+// HealthService checks the health of the alerting configuration of organizations.
+type HealthService struct {
+	amStore           AMConfigStore
+	encryptionService secrets.Service
+	fileStatus        *FileProvisioningStatusStore
+	log               log.Logger
+	tracer            tracing.Tracer
+	metrics           *metrics.Provisioning
+}
+
+func NewHealthService(store AMConfigStore, encryptionService secrets.Service, fileStatus *FileProvisioningStatusStore,
+	log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *HealthService {
+	return &HealthService{
+		amStore:           newTracedAMConfigStore(store, tracer, log, m),
+		encryptionService: newTracedSecretsService(encryptionService, tracer),
+		fileStatus:        fileStatus,
+		log:               log,
+		tracer:            tracer,
+		metrics:           m,
+	}
+}
+
+// GetConfigHealth returns the health of the alerting configuration of the organization.
+func (svc *HealthService) GetConfigHealth(ctx context.Context, orgID int64) (_ ConfigHealth, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "config", "GetConfigHealth", orgID)
+	defer func() { done(err) }()
+
+	health := ConfigHealth{
+		InvalidReceivers: []InvalidReceiver{},
+	}
+	if svc.fileStatus != nil {
+		health.FileProvisioning, err = svc.fileStatus.GetStatus(ctx, orgID)
+		if err != nil {
+			return ConfigHealth{}, err
+		}
+	}
+
+	amConfig, err := svc.amStore.GetLatestAlertmanagerConfiguration(ctx, &models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID})
+	if err != nil {
+		return ConfigHealth{}, err
+	}
+	if amConfig == nil {
+		return health, nil
+	}
+	health.LastConfigSave = time.Unix(amConfig.CreatedAt, 0).UTC()
+	health.ConfigSize = len(amConfig.AlertmanagerConfiguration)
+
+	cfg, err := deserializeAlertmanagerConfig(amConfig.AlertmanagerConfiguration)
+	if err != nil {
+		return ConfigHealth{}, err
+	}
+	for _, r := range newReceiverIndex(cfg).all() {
+		integration := alertingNotify.GrafanaIntegrationConfig{
+			UID:                   r.UID,
+			Name:                  r.Name,
+			Type:                  r.Type,
+			DisableResolveMessage: r.DisableResolveMessage,
+			Settings:              json.RawMessage(r.Settings),
+			SecureSettings:        r.SecureSettings,
+		}
+		_, err := alertingNotify.BuildReceiverConfiguration(ctx, &alertingNotify.APIReceiver{
+			GrafanaIntegrations: alertingNotify.GrafanaIntegrations{
+				Integrations: []*alertingNotify.GrafanaIntegrationConfig{&integration},
+			},
+		}, svc.encryptionService.GetDecryptedValue)
+		if err != nil {
+			health.InvalidReceivers = append(health.InvalidReceivers, InvalidReceiver{
+				UID:   r.UID,
+				Name:  r.Name,
+				Type:  r.Type,
+				Error: err.Error(),
+			})
+		}
+	}
+	return health, nil
+}
@@ -0,0 +1,86 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
+	"github.com/grafana/grafana/pkg/services/secrets"
+	secrets_fakes "github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func TestHealthService(t *testing.T) {
+	secretsService := secrets_fakes.NewFakeSecretsService()
+
+	t.Run("summarizes the configuration of the organization", func(t *testing.T) {
+		c := &definitions.PostableUserConfig{}
+		require.NoError(t, json.Unmarshal([]byte(defaultAlertmanagerConfigJSON), c))
+		require.NoError(t, notifier.EncryptReceiverConfigs(c.AlertmanagerConfig.Receivers, func(ctx context.Context, payload []byte) ([]byte, error) {
+			return secretsService.Encrypt(ctx, payload, secrets.WithoutScope())
+		}))
+		raw, err := json.Marshal(c)
+		require.NoError(t, err)
+		amStore := newFakeAMConfigStore(string(raw))
+		amStore.config.CreatedAt = 1000
+		sut := createHealthServiceSut(amStore, secretsService, kvstore.NewFakeKVStore())
+
+		health, err := sut.GetConfigHealth(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), health.LastConfigSave.Unix())
+		require.Equal(t, len(raw), health.ConfigSize)
+		require.Nil(t, health.FileProvisioning)
+		require.Empty(t, health.InvalidReceivers)
+	})
+
+	t.Run("reports receivers that fail validation", func(t *testing.T) {
+		// The secure settings of the default configuration are not encrypted, so they cannot be decrypted on load.
+		sut := createHealthServiceSut(newFakeAMConfigStore(defaultAlertmanagerConfigJSON), secretsService, kvstore.NewFakeKVStore())
+
+		health, err := sut.GetConfigHealth(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.Len(t, health.InvalidReceivers, 1)
+		require.Equal(t, "slack receiver", health.InvalidReceivers[0].Name)
+		require.Equal(t, "slack", health.InvalidReceivers[0].Type)
+		require.NotEmpty(t, health.InvalidReceivers[0].Error)
+	})
+
+	t.Run("includes the status of file provisioning", func(t *testing.T) {
+		kv := kvstore.NewFakeKVStore()
+		status := NewFileProvisioningStatusStore(kv)
+		require.NoError(t, status.RecordResult(context.Background(), []int64{1}, nil))
+		require.NoError(t, status.RecordResult(context.Background(), []int64{1}, errors.New("invalid file")))
+		sut := createHealthServiceSut(newFakeAMConfigStore(defaultAlertmanagerConfigJSON), secretsService, kv)
+
+		health, err := sut.GetConfigHealth(context.Background(), 1)
+
+		require.NoError(t, err)
+		require.NotNil(t, health.FileProvisioning)
+		require.Equal(t, "invalid file", health.FileProvisioning.Error)
+		require.False(t, health.FileProvisioning.LastSuccess.IsZero())
+		require.False(t, health.FileProvisioning.LastAttempt.Before(health.FileProvisioning.LastSuccess))
+
+		other, err := sut.GetConfigHealth(context.Background(), 2)
+		require.NoError(t, err)
+		require.Nil(t, other.FileProvisioning)
+	})
+}
+
+func createHealthServiceSut(amStore AMConfigStore, secretsService secrets.Service, kv kvstore.KVStore) *HealthService {
+	return &HealthService{
+		amStore:           amStore,
+		encryptionService: secretsService,
+		fileStatus:        NewFileProvisioningStatusStore(kv),
+		log:               log.NewNopLogger(),
+		tracer:            tracing.InitializeTracerForTest(),
+	}
+}
@@ -0,0 +1,210 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+// startOperation starts the span of an operation of a provisioning service on the resources of an organization.
+// The returned function must be called with the result of the operation. It ends the span and, if metrics are set,
+// counts the operation by its outcome.
+func startOperation(ctx context.Context, tracer tracing.Tracer, m *metrics.Provisioning, resourceType, operation string,
+	orgID int64, attrs ...attribute.KeyValue) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, span := startSpan(ctx, tracer, "provisioning."+operation, orgID, attrs...)
+	return ctx, func(err error) {
+		endSpan(span, err)
+		if m == nil {
+			return
+		}
+		outcome := operationOutcome(err)
+		m.OperationsTotal.WithLabelValues(operation, resourceType, strconv.FormatInt(orgID, 10), outcome).Inc()
+		m.OperationDuration.WithLabelValues(operation, resourceType, outcome).Observe(time.Since(start).Seconds())
+	}
+}
+
+// countDecryptRequest counts a request for the decrypted secure settings of resources of the given type as the
+// DecryptSecureSettings operation, with the permission_denied outcome if it was not allowed.
+func countDecryptRequest(m *metrics.Provisioning, resourceType string, orgID int64, allowed bool) {
+	if m == nil {
+		return
+	}
+	var err error
+	if !allowed {
+		err = ErrPermissionDenied
+	}
+	m.OperationsTotal.WithLabelValues("DecryptSecureSettings", resourceType, strconv.FormatInt(orgID, 10), operationOutcome(err)).Inc()
+}
+
+// operationOutcome classifies the result of a provisioning operation for metrics.
+func operationOutcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, ErrDryRun):
+		return "dry_run"
+	case errors.Is(err, ErrValidation), errors.Is(err, models.ErrAlertRuleFailedValidation):
+		return "validation_error"
+	case errors.Is(err, ErrNotFound), errors.Is(err, models.ErrAlertRuleNotFound):
+		return "not_found"
+	case errors.Is(err, ErrPermissionDenied):
+		return "permission_denied"
+	case errors.Is(err, store.ErrVersionLockedObjectNotFound), errors.Is(err, ErrVersionConflict):
+		return "conflict"
+	case errors.Is(err, ErrQuotaReached):
+		return "quota_reached"
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	default:
+		return "error"
+	}
+}
+
+// startSpan starts a span for a provisioning operation on the resources of an organization.
+func startSpan(ctx context.Context, tracer tracing.Tracer, name string, orgID int64, attrs ...attribute.KeyValue) (context.Context, tracing.Span) {
+	ctx, span := tracer.Start(ctx, name)
+	span.SetAttributes("org_id", orgID, attribute.Int64("org_id", orgID))
+	for _, kv := range attrs {
+		span.SetAttributes(string(kv.Key), kv.Value.AsInterface(), kv)
+	}
+	return ctx, span
+}
+
+// endSpan records the error of the operation, if any, and ends the span.
+func endSpan(span tracing.Span, err error) {
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.RecordError(err)
+	}
+	span.End()
+}
+
+// correlationID returns the ID that correlates the changes made with the context to the request that made them. It is
+// the ID of the trace of the request, or empty if the request is not traced.
+func correlationID(ctx context.Context) string {
+	return tracing.TraceIDFromContext(ctx, false)
+}
+
+// tracedAMConfigStore is an AMConfigStore that traces reads and writes of Alertmanager configurations and, if metrics
+// are set, reports their size. Saved configurations are marked with the correlation ID of the request that saved them.
+type tracedAMConfigStore struct {
+	store   AMConfigStore
+	tracer  tracing.Tracer
+	log     log.Logger
+	metrics *metrics.Provisioning
+}
+
+func newTracedAMConfigStore(store AMConfigStore, tracer tracing.Tracer, log log.Logger, m *metrics.Provisioning) AMConfigStore {
+	return &tracedAMConfigStore{store: store, tracer: tracer, log: log, metrics: m}
+}
+
+func (s *tracedAMConfigStore) reportConfigSize(orgID int64, size int) {
+	if s.metrics == nil {
+		return
+	}
+	s.metrics.AlertmanagerConfigSize.WithLabelValues(strconv.FormatInt(orgID, 10)).Set(float64(size))
+}
+
+func (s *tracedAMConfigStore) GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) (*models.AlertConfiguration, error) {
+	ctx, span := startSpan(ctx, s.tracer, "provisioning.AMConfigStore.GetLatestAlertmanagerConfiguration", query.OrgID)
+	cfg, err := s.store.GetLatestAlertmanagerConfiguration(ctx, query)
+	if cfg != nil {
+		span.SetAttributes("config_size", len(cfg.AlertmanagerConfiguration), attribute.Int("config_size", len(cfg.AlertmanagerConfiguration)))
+		s.reportConfigSize(query.OrgID, len(cfg.AlertmanagerConfiguration))
+	}
+	endSpan(span, err)
+	return cfg, err
+}
+
+func (s *tracedAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	ctx, span := startSpan(ctx, s.tracer, "provisioning.AMConfigStore.UpdateAlertmanagerConfiguration", cmd.OrgID,
+		attribute.Int("config_size", len(cmd.AlertmanagerConfiguration)))
+	if cmd.CorrelationID == "" {
+		cmd.CorrelationID = correlationID(ctx)
+	}
+	err := s.store.UpdateAlertmanagerConfiguration(ctx, cmd)
+	endSpan(span, err)
+	if err == nil {
+		s.reportConfigSize(cmd.OrgID, len(cmd.AlertmanagerConfiguration))
+		s.log.FromContext(ctx).Debug("Saved Alertmanager configuration", "org", cmd.OrgID, "correlationID", cmd.CorrelationID)
+	}
+	return err
+}
+
+// UpdateAlertmanagerConfigResources forwards the update to the underlying store if it can change single resources of
+// the configuration, and returns store.ErrIncrementalConfigUpdateUnsupported otherwise.
+func (s *tracedAMConfigStore) UpdateAlertmanagerConfigResources(ctx context.Context, cmd *models.UpdateAlertmanagerConfigResourcesCmd) error {
+	resourceStore, ok := s.store.(AMConfigResourceStore)
+	if !ok {
+		return store.ErrIncrementalConfigUpdateUnsupported
+	}
+	ctx, span := startSpan(ctx, s.tracer, "provisioning.AMConfigStore.UpdateAlertmanagerConfigResources", cmd.OrgID,
+		attribute.Int("changes", len(cmd.Changes)))
+	if cmd.CorrelationID == "" {
+		cmd.CorrelationID = correlationID(ctx)
+	}
+	err := resourceStore.UpdateAlertmanagerConfigResources(ctx, cmd)
+	endSpan(span, err)
+	if err == nil {
+		s.log.FromContext(ctx).Debug("Saved Alertmanager configuration resources", "org", cmd.OrgID, "changes", len(cmd.Changes), "correlationID", cmd.CorrelationID)
+	}
+	return err
+}
+
+// tracedSecretsService is a secrets.Service that traces the encryption and decryption of secure settings.
+type tracedSecretsService struct {
+	secrets.Service
+	tracer tracing.Tracer
+}
+
+func newTracedSecretsService(service secrets.Service, tracer tracing.Tracer) secrets.Service {
+	return &tracedSecretsService{Service: service, tracer: tracer}
+}
+
+func (s *tracedSecretsService) Encrypt(ctx context.Context, payload []byte, opt secrets.EncryptionOptions) ([]byte, error) {
+	ctx, span := s.tracer.Start(ctx, "provisioning.secrets.Encrypt")
+	result, err := s.Service.Encrypt(ctx, payload, opt)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedSecretsService) Decrypt(ctx context.Context, payload []byte) ([]byte, error) {
+	ctx, span := s.tracer.Start(ctx, "provisioning.secrets.Decrypt")
+	result, err := s.Service.Decrypt(ctx, payload)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedSecretsService) EncryptJsonData(ctx context.Context, kv map[string]string, opt secrets.EncryptionOptions) (map[string][]byte, error) {
+	ctx, span := s.tracer.Start(ctx, "provisioning.secrets.EncryptJsonData")
+	span.SetAttributes("keys", len(kv), attribute.Int("keys", len(kv)))
+	result, err := s.Service.EncryptJsonData(ctx, kv, opt)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedSecretsService) DecryptJsonData(ctx context.Context, sjd map[string][]byte) (map[string]string, error) {
+	ctx, span := s.tracer.Start(ctx, "provisioning.secrets.DecryptJsonData")
+	span.SetAttributes("keys", len(sjd), attribute.Int("keys", len(sjd)))
+	result, err := s.Service.DecryptJsonData(ctx, sjd)
+	endSpan(span, err)
+	return result, err
+}
+
+func (s *tracedSecretsService) GetDecryptedValue(ctx context.Context, sjd map[string][]byte, key, fallback string) string {
+	ctx, span := s.tracer.Start(ctx, "provisioning.secrets.GetDecryptedValue")
+	defer span.End()
+	return s.Service.GetDecryptedValue(ctx, sjd, key, fallback)
+}
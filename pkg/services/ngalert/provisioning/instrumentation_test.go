@@ -0,0 +1,109 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+func TestProvisioningInstrumentation(t *testing.T) {
+	t.Run("service calls and config store reads are traced with the org", func(t *testing.T) {
+		tracer := tracing.NewFakeTracer()
+		configStore := &MockAMConfigStore{}
+		configStore.EXPECT().GetsConfig(models.AlertConfiguration{AlertmanagerConfiguration: defaultAlertmanagerConfigJSON})
+		sut := NewTemplateService(configStore, &MockProvisioningStore{}, newNopTransactionManager(), nil, log.NewNopLogger(), tracer, nil)
+
+		_, err := sut.GetTemplates(context.Background(), 1)
+		require.NoError(t, err)
+
+		require.Len(t, tracer.Spans, 2)
+		require.Equal(t, "provisioning.GetTemplates", tracer.Spans[0].Name)
+		require.Equal(t, "provisioning.AMConfigStore.GetLatestAlertmanagerConfiguration", tracer.Spans[1].Name)
+		for _, span := range tracer.Spans {
+			require.True(t, span.IsEnded())
+			require.Equal(t, attribute.Int64Value(1), span.Attributes["org_id"])
+		}
+	})
+
+	t.Run("config store errors are recorded on the span", func(t *testing.T) {
+		tracer := tracing.NewFakeTracer()
+		expected := errors.New("test error")
+		configStore := &MockAMConfigStore{}
+		configStore.EXPECT().GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).Return(nil, expected)
+		sut := NewTemplateService(configStore, &MockProvisioningStore{}, newNopTransactionManager(), nil, log.NewNopLogger(), tracer, nil)
+
+		_, err := sut.GetTemplates(context.Background(), 1)
+		require.ErrorIs(t, err, expected)
+
+		span := tracer.Spans[1]
+		require.Equal(t, codes.Error, span.StatusCode)
+		require.ErrorIs(t, span.Err, expected)
+	})
+
+	t.Run("operations are counted by outcome", func(t *testing.T) {
+		m := metrics.NewProvisioningMetrics(prometheus.NewRegistry())
+		configStore := &MockAMConfigStore{}
+		configStore.EXPECT().GetsConfig(models.AlertConfiguration{AlertmanagerConfiguration: defaultAlertmanagerConfigJSON})
+		sut := NewTemplateService(configStore, &MockProvisioningStore{}, newNopTransactionManager(), nil, log.NewNopLogger(), tracing.NewFakeTracer(), m)
+
+		_, err := sut.GetTemplates(context.Background(), 1)
+		require.NoError(t, err)
+		_, err = sut.SetTemplate(context.Background(), 1, definitions.NotificationTemplate{Name: "invalid"})
+		require.ErrorIs(t, err, ErrValidation)
+
+		require.Equal(t, 1.0, testutil.ToFloat64(m.OperationsTotal.WithLabelValues("GetTemplates", "template", "1", "success")))
+		require.Equal(t, 1.0, testutil.ToFloat64(m.OperationsTotal.WithLabelValues("SetTemplate", "template", "1", "validation_error")))
+		require.Equal(t, 2, testutil.CollectAndCount(m.OperationDuration))
+		require.Equal(t, float64(len(defaultAlertmanagerConfigJSON)), testutil.ToFloat64(m.AlertmanagerConfigSize.WithLabelValues("1")))
+	})
+
+	t.Run("saved configurations are marked with the trace ID of the request", func(t *testing.T) {
+		ctx, span := tracing.InitializeTracerForTest().Start(context.Background(), "request")
+		defer span.End()
+		var saved models.SaveAlertmanagerConfigurationCmd
+		configStore := &MockAMConfigStore{}
+		configStore.EXPECT().SaveSucceedsIntercept(&saved)
+		sut := newTracedAMConfigStore(configStore, tracing.NewFakeTracer(), log.NewNopLogger(), nil)
+
+		require.NoError(t, sut.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{OrgID: 1}))
+
+		require.NotEmpty(t, saved.CorrelationID)
+		require.Equal(t, tracing.TraceIDFromContext(ctx, false), saved.CorrelationID)
+	})
+}
+
+func TestOperationOutcome(t *testing.T) {
+	testCases := map[string]struct {
+		err      error
+		expected string
+	}{
+		"success":           {nil, "success"},
+		"validation error":  {fmt.Errorf("%w: missing name", ErrValidation), "validation_error"},
+		"not found":         {fmt.Errorf("%w: no such template", ErrNotFound), "not_found"},
+		"rule not found":    {models.ErrAlertRuleNotFound, "not_found"},
+		"permission denied": {ErrPermissionDenied, "permission_denied"},
+		"conflict":          {store.ErrVersionLockedObjectNotFound, "conflict"},
+		"quota reached":     {fmt.Errorf("%w: templates", ErrQuotaReached), "quota_reached"},
+		"other error":       {errors.New("test error"), "error"},
+	}
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			require.Equal(t, tc.expected, operationOutcome(tc.err))
+		})
+	}
+}
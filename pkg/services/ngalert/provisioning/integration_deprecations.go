@@ -0,0 +1,92 @@
+package provisioning
+
+import (
+	"fmt"
+	"sort"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// integrationDeprecation describes an integration type, or settings of it, that is no longer supported and how to
+// rewrite it to its supported successor.
+type integrationDeprecation struct {
+	// successor is the integration type that replaces the deprecated type. Empty if only settings are deprecated.
+	successor string
+	// removed is true if the integration type was removed without a successor, and cannot be migrated.
+	removed bool
+	// renamedSettings maps deprecated settings keys to the keys that replace them.
+	renamedSettings map[string]string
+}
+
+// integrationDeprecations are the deprecations keyed by integration type.
+var integrationDeprecations = map[string]integrationDeprecation{
+	"hipchat": {removed: true},
+	"sensu": {
+		successor:       "sensugo",
+		renamedSettings: map[string]string{"source": "entity"},
+	},
+	"discord": {
+		renamedSettings: map[string]string{"content": "message"},
+	},
+}
+
+// deprecationWarnings returns the warnings about the deprecated integration type and settings the contact point uses.
+func deprecationWarnings(cp apimodels.EmbeddedContactPoint) []string {
+	deprecation, ok := integrationDeprecations[cp.Type]
+	if !ok {
+		return nil
+	}
+	var warnings []string
+	switch {
+	case deprecation.removed:
+		warnings = append(warnings, fmt.Sprintf("integration type '%s' is no longer supported and has no successor", cp.Type))
+	case deprecation.successor != "":
+		warnings = append(warnings, fmt.Sprintf("integration type '%s' is deprecated, migrate the contact point to '%s'", cp.Type, deprecation.successor))
+	}
+	for _, key := range deprecatedSettings(cp, deprecation) {
+		warnings = append(warnings, fmt.Sprintf("setting '%s' is deprecated, migrate the contact point to use '%s'", key, deprecation.renamedSettings[key]))
+	}
+	return warnings
+}
+
+// migrateContactPoint rewrites the deprecated integration type and settings of the contact point to their successors.
+// It returns false if the contact point does not use anything deprecated.
+func migrateContactPoint(cp *apimodels.EmbeddedContactPoint) (bool, error) {
+	deprecation, ok := integrationDeprecations[cp.Type]
+	if !ok {
+		return false, nil
+	}
+	if deprecation.removed {
+		return false, fmt.Errorf("%w: integration type '%s' has no successor, the contact point needs to be recreated with another integration", ErrValidation, cp.Type)
+	}
+	keys := deprecatedSettings(*cp, deprecation)
+	if deprecation.successor == "" && len(keys) == 0 {
+		return false, nil
+	}
+	for _, key := range keys {
+		value := cp.Settings.Get(key).Interface()
+		cp.Settings.Del(key)
+		if _, exists := cp.Settings.CheckGet(deprecation.renamedSettings[key]); !exists {
+			cp.Settings.Set(deprecation.renamedSettings[key], value)
+		}
+	}
+	if deprecation.successor != "" {
+		cp.Type = deprecation.successor
+	}
+	return true, nil
+}
+
+// deprecatedSettings returns the deprecated settings keys the contact point uses, sorted.
+func deprecatedSettings(cp apimodels.EmbeddedContactPoint, deprecation integrationDeprecation) []string {
+	if cp.Settings == nil {
+		return nil
+	}
+	var keys []string
+	for key := range deprecation.renamedSettings {
+		if _, ok := cp.Settings.CheckGet(key); ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,52 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestMigrateContactPoint(t *testing.T) {
+	t.Run("deprecated types are replaced by their successor and settings are renamed", func(t *testing.T) {
+		settings, _ := simplejson.NewJson([]byte(`{"url":"http://localhost","source":"value_source"}`))
+		cp := definitions.EmbeddedContactPoint{Type: "sensu", Settings: settings}
+		require.Len(t, deprecationWarnings(cp), 2)
+
+		migrated, err := migrateContactPoint(&cp)
+		require.NoError(t, err)
+		require.True(t, migrated)
+		require.Equal(t, "sensugo", cp.Type)
+		require.Equal(t, "value_source", cp.Settings.Get("entity").MustString())
+		require.Empty(t, deprecationWarnings(cp))
+	})
+
+	t.Run("settings already set with the new key are kept", func(t *testing.T) {
+		settings, _ := simplejson.NewJson([]byte(`{"content":"old","message":"new"}`))
+		cp := definitions.EmbeddedContactPoint{Type: "discord", Settings: settings}
+
+		migrated, err := migrateContactPoint(&cp)
+		require.NoError(t, err)
+		require.True(t, migrated)
+		require.Equal(t, "new", cp.Settings.Get("message").MustString())
+	})
+
+	t.Run("removed types without successor cannot be migrated", func(t *testing.T) {
+		cp := definitions.EmbeddedContactPoint{Type: "hipchat", Settings: simplejson.New()}
+		require.Len(t, deprecationWarnings(cp), 1)
+
+		_, err := migrateContactPoint(&cp)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("supported contact points are not migrated", func(t *testing.T) {
+		cp := createTestContactPoint()
+		require.Empty(t, deprecationWarnings(cp))
+
+		migrated, err := migrateContactPoint(&cp)
+		require.NoError(t, err)
+		require.False(t, migrated)
+	})
+}
@@ -0,0 +1,434 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const maintenanceWindowsKey = "maintenance_windows"
+
+// MaintenanceWindowService manages maintenance windows: mute timings that are created from a one-shot or recurring
+// schedule, attached to the notification policies that the alerts of the window are routed to, and removed again
+// once the window expires. The definitions of the windows are kept in the key-value store, per organization.
+type MaintenanceWindowService struct {
+	config  AMConfigStore
+	prov    ProvisioningStore
+	kv      kvstore.KVStore
+	xact    TransactionManager
+	log     log.Logger
+	tracer  tracing.Tracer
+	metrics *metrics.Provisioning
+}
+
+func NewMaintenanceWindowService(config AMConfigStore, prov ProvisioningStore, kv kvstore.KVStore, xact TransactionManager,
+	log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *MaintenanceWindowService {
+	return &MaintenanceWindowService{
+		config:  newTracedAMConfigStore(config, tracer, log, m),
+		prov:    prov,
+		kv:      kv,
+		xact:    xact,
+		log:     log,
+		tracer:  tracer,
+		metrics: m,
+	}
+}
+
+// GetMaintenanceWindows returns the maintenance windows of the organization, sorted by name.
+func (svc *MaintenanceWindowService) GetMaintenanceWindows(ctx context.Context, orgID int64) (_ []definitions.MaintenanceWindow, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "maintenanceWindow", "GetMaintenanceWindows", orgID)
+	defer func() { done(err) }()
+	windows, err := svc.getWindows(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.MaintenanceWindow, 0, len(windows))
+	for _, mw := range windows {
+		result = append(result, mw)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// CreateMaintenanceWindow creates the mute timing of the maintenance window and attaches it to the notification
+// policies that alerts with the labels of the window are routed to. The created window is returned.
+func (svc *MaintenanceWindowService) CreateMaintenanceWindow(ctx context.Context, orgID int64, mw definitions.MaintenanceWindow, p models.Provenance) (result definitions.MaintenanceWindow, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.createMaintenanceWindow(ctx, orgID, mw, p)
+		return err
+	})
+	return result, err
+}
+
+func (svc *MaintenanceWindowService) createMaintenanceWindow(ctx context.Context, orgID int64, mw definitions.MaintenanceWindow, p models.Provenance) (_ definitions.MaintenanceWindow, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "maintenanceWindow", "CreateMaintenanceWindow", orgID,
+		attribute.String("maintenance_window_name", mw.Name))
+	defer func() { done(err) }()
+	loc, err := validateMaintenanceWindow(mw, time.Now())
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	lset := model.LabelSet{}
+	for _, m := range mw.Matchers {
+		lset[model.LabelName(m.Name)] = model.LabelValue(m.Value)
+	}
+	mt := definitions.MuteTimeInterval{
+		MuteTimeInterval: config.MuteTimeInterval{Name: mw.Name, TimeIntervals: maintenanceWindowIntervals(mw, loc)},
+		Provenance:       definitions.Provenance(p),
+	}
+	if err := mt.Validate(); err != nil {
+		return definitions.MaintenanceWindow{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, svc.config)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	windows, err := svc.getWindows(ctx, orgID)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	if _, ok := windows[mw.Name]; ok {
+		return definitions.MaintenanceWindow{}, newValidationError("name", "a maintenance window with this name already exists")
+	}
+	for _, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		if existing.Name == mw.Name {
+			return definitions.MaintenanceWindow{}, newValidationError("name", "a mute timing with this name already exists")
+		}
+	}
+	tree := revision.cfg.AlertmanagerConfig.Route
+	if tree == nil {
+		return definitions.MaintenanceWindow{}, fmt.Errorf("%w: the organization has no notification policies", ErrValidation)
+	}
+
+	mw.Policies = []definitions.PolicyReference{}
+	for _, matched := range matchPolicyRoutes(dispatch.NewRoute(tree.AsAMRoute(), nil), tree, []int{}, lset) {
+		if len(matched.Path) == 0 {
+			return definitions.MaintenanceWindow{}, fmt.Errorf("%w: alerts matching the maintenance window are handled by the root notification policy, which cannot have mute timings", ErrValidation)
+		}
+		route, _, _, err := findRoute(tree, RouteRef{Path: matched.Path})
+		if err != nil {
+			return definitions.MaintenanceWindow{}, err
+		}
+		route.MuteTimeIntervals = append(route.MuteTimeIntervals, mw.Name)
+		mw.Policies = append(mw.Policies, definitions.PolicyReference{
+			Path: matched.Path,
+			UID:  route.UID,
+			Kind: policyReferenceMuteTimeInterval,
+			Name: mw.Name,
+		})
+	}
+	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTimeInterval)
+	mw.ExpiresAt = maintenanceWindowExpiry(mw)
+	mw.Provenance = definitions.Provenance(p)
+	windows[mw.Name] = mw
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+	}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := PersistConfig(ctx, svc.config, &cmd); err != nil {
+			return err
+		}
+		if err := svc.prov.SetProvenance(ctx, &mt, orgID, p); err != nil {
+			return err
+		}
+		if err := svc.setWindows(ctx, orgID, windows); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionCreate, &mt, p, nil, mt)
+	})
+	if err != nil {
+		return definitions.MaintenanceWindow{}, err
+	}
+	return mw, nil
+}
+
+// DeleteMaintenanceWindow deletes the maintenance window with the given name and removes its mute timing from the
+// configuration and from all notification policies.
+func (svc *MaintenanceWindowService) DeleteMaintenanceWindow(ctx context.Context, orgID int64, name string) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return svc.deleteMaintenanceWindow(ctx, orgID, name)
+	})
+}
+
+func (svc *MaintenanceWindowService) deleteMaintenanceWindow(ctx context.Context, orgID int64, name string) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "maintenanceWindow", "DeleteMaintenanceWindow", orgID,
+		attribute.String("maintenance_window_name", name))
+	defer func() { done(err) }()
+	return svc.removeWindow(ctx, orgID, name, models.ProvisioningAuditActionDelete)
+}
+
+// ExpireMaintenanceWindows removes the maintenance windows of all organizations that expired at the given time.
+func (svc *MaintenanceWindowService) ExpireMaintenanceWindows(ctx context.Context, now time.Time) error {
+	all, err := svc.kv.GetAll(ctx, kvstore.AllOrganizations, fileProvisioningStatusNamespace)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for orgID, values := range all {
+		value, ok := values[maintenanceWindowsKey]
+		if !ok {
+			continue
+		}
+		windows := map[string]definitions.MaintenanceWindow{}
+		if err := json.Unmarshal([]byte(value), &windows); err != nil {
+			errs = append(errs, fmt.Errorf("failed to unmarshal maintenance windows of organization %d: %w", orgID, err))
+			continue
+		}
+		names := make([]string, 0, len(windows))
+		for name, mw := range windows {
+			if mw.ExpiresAt != nil && !mw.ExpiresAt.After(now) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if err := svc.expireWindow(ctx, orgID, name); err != nil {
+				errs = append(errs, fmt.Errorf("failed to remove expired maintenance window '%s' of organization %d: %w", name, orgID, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (svc *MaintenanceWindowService) expireWindow(ctx context.Context, orgID int64, name string) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "maintenanceWindow", "ExpireMaintenanceWindow", orgID,
+		attribute.String("maintenance_window_name", name))
+	defer func() { done(err) }()
+	return svc.removeWindow(ctx, orgID, name, models.ProvisioningAuditActionExpire)
+}
+
+func (svc *MaintenanceWindowService) removeWindow(ctx context.Context, orgID int64, name string, action models.ProvisioningAuditAction) error {
+	windows, err := svc.getWindows(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	mw, ok := windows[name]
+	if !ok {
+		return newNotFoundError("maintenanceWindow", name, "maintenance window '%s' does not exist", name)
+	}
+	delete(windows, name)
+
+	revision, err := getLastConfiguration(ctx, orgID, svc.config)
+	if err != nil {
+		return err
+	}
+	var oldState any
+	intervals := revision.cfg.AlertmanagerConfig.MuteTimeIntervals
+	for i, existing := range intervals {
+		if existing.Name == name {
+			oldState = definitions.MuteTimeInterval{MuteTimeInterval: existing, Provenance: mw.Provenance}
+			revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(intervals[:i], intervals[i+1:]...)
+			break
+		}
+	}
+	// The mute timing was removed by other means already.
+	if oldState == nil {
+		return svc.setWindows(ctx, orgID, windows)
+	}
+	removeMuteTimingReferences(name, revision.cfg.AlertmanagerConfig.Route)
+
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+	}
+	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := PersistConfig(ctx, svc.config, &cmd); err != nil {
+			return err
+		}
+		target := definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: name}}
+		if err := svc.prov.DeleteProvenance(ctx, &target, orgID); err != nil {
+			return err
+		}
+		if err := svc.setWindows(ctx, orgID, windows); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.prov, orgID, action, &target, models.Provenance(mw.Provenance), oldState, nil)
+	})
+}
+
+func (svc *MaintenanceWindowService) getWindows(ctx context.Context, orgID int64) (map[string]definitions.MaintenanceWindow, error) {
+	value, ok, err := svc.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, maintenanceWindowsKey)
+	if err != nil {
+		return nil, err
+	}
+	windows := map[string]definitions.MaintenanceWindow{}
+	if !ok {
+		return windows, nil
+	}
+	if err := json.Unmarshal([]byte(value), &windows); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal maintenance windows: %w", err)
+	}
+	return windows, nil
+}
+
+func (svc *MaintenanceWindowService) setWindows(ctx context.Context, orgID int64, windows map[string]definitions.MaintenanceWindow) error {
+	if len(windows) == 0 {
+		return svc.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, maintenanceWindowsKey)
+	}
+	data, err := json.Marshal(windows)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, maintenanceWindowsKey, string(data))
+}
+
+// validateMaintenanceWindow checks the schedule and the matchers of the window, and returns the location its
+// schedule follows.
+func validateMaintenanceWindow(mw definitions.MaintenanceWindow, now time.Time) (*time.Location, error) {
+	if !mw.End.After(mw.Start) {
+		return nil, newValidationError("end", "the end of the maintenance window must be after its start")
+	}
+	loc := time.UTC
+	if mw.Location != "" {
+		l, err := time.LoadLocation(mw.Location)
+		if err != nil {
+			return nil, newValidationError("location", "invalid location '%s': %s", mw.Location, err.Error())
+		}
+		loc = l
+	}
+	for _, m := range mw.Matchers {
+		if m.Type != labels.MatchEqual {
+			return nil, newValidationError("matchers", "maintenance windows only support equality matchers, got %s", m.String())
+		}
+	}
+
+	if mw.Recurrence == nil {
+		if !mw.End.After(now) {
+			return nil, newValidationError("end", "the maintenance window must end in the future")
+		}
+		return loc, nil
+	}
+	var next time.Time
+	switch mw.Recurrence.Frequency {
+	case definitions.MaintenanceWindowDaily:
+		next = now.AddDate(0, 0, 1)
+	case definitions.MaintenanceWindowWeekly:
+		next = now.AddDate(0, 0, 7)
+	case definitions.MaintenanceWindowMonthly:
+		next = now.AddDate(0, 1, 0)
+		start, end := mw.Start.In(loc), mw.End.In(loc)
+		if start.Day() > 28 && start.Day() != end.Day() {
+			return nil, newValidationError("start", "monthly maintenance windows that span midnight must start on one of the first 28 days of the month")
+		}
+	default:
+		return nil, newValidationError("recurrence.frequency", "unknown recurrence frequency '%s', must be one of %s, %s or %s", mw.Recurrence.Frequency,
+			definitions.MaintenanceWindowDaily, definitions.MaintenanceWindowWeekly, definitions.MaintenanceWindowMonthly)
+	}
+	if mw.End.Sub(mw.Start) > 24*time.Hour {
+		return nil, newValidationError("end", "occurrences of recurring maintenance windows cannot be longer than a day")
+	}
+	// The mute timing takes effect as soon as it is created, so the first occurrence has to be the next one.
+	if !mw.Start.Before(next) {
+		return nil, newValidationError("start", "the first occurrence of a recurring maintenance window must start within one recurrence")
+	}
+	if until := mw.Recurrence.Until; until != nil && (!until.After(now) || !until.After(mw.Start)) {
+		return nil, newValidationError("recurrence.until", "the end of the recurrence must be in the future and after the start of the maintenance window")
+	}
+	return loc, nil
+}
+
+// maintenanceWindowIntervals returns the time intervals of the mute timing of the window. One-shot windows are pinned
+// to their dates, one interval per day. The time ranges of recurring windows that span midnight are split at midnight.
+func maintenanceWindowIntervals(mw definitions.MaintenanceWindow, loc *time.Location) []timeinterval.TimeInterval {
+	start, end := mw.Start.In(loc).Truncate(time.Minute), mw.End.In(loc).Truncate(time.Minute)
+	location := &timeinterval.Location{Location: loc}
+
+	if mw.Recurrence == nil {
+		var intervals []timeinterval.TimeInterval
+		for day := startOfDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+			nextDay := day.AddDate(0, 0, 1)
+			ti := timeinterval.TimeInterval{
+				Years:       []timeinterval.YearRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: day.Year(), End: day.Year()}}},
+				Months:      []timeinterval.MonthRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: int(day.Month()), End: int(day.Month())}}},
+				DaysOfMonth: []timeinterval.DayOfMonthRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: day.Day(), End: day.Day()}}},
+				Location:    location,
+			}
+			if day.Before(start) || end.Before(nextDay) {
+				from, to := minuteOfDay(start), 24*60
+				if !day.Before(start) {
+					from = 0
+				}
+				if end.Before(nextDay) {
+					to = minuteOfDay(end)
+				}
+				ti.Times = []timeinterval.TimeRange{{StartMinute: from, EndMinute: to}}
+			}
+			intervals = append(intervals, ti)
+		}
+		return intervals
+	}
+
+	startMinute := minuteOfDay(start)
+	endMinute := startMinute + int(end.Sub(start).Minutes())
+	first := timeinterval.TimeInterval{Location: location}
+	next := timeinterval.TimeInterval{Location: location}
+	switch mw.Recurrence.Frequency {
+	case definitions.MaintenanceWindowWeekly:
+		first.Weekdays = []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: int(start.Weekday()), End: int(start.Weekday())}}}
+		nextWeekday := (int(start.Weekday()) + 1) % 7
+		next.Weekdays = []timeinterval.WeekdayRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: nextWeekday, End: nextWeekday}}}
+	case definitions.MaintenanceWindowMonthly:
+		first.DaysOfMonth = []timeinterval.DayOfMonthRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: start.Day(), End: start.Day()}}}
+		next.DaysOfMonth = []timeinterval.DayOfMonthRange{{InclusiveRange: timeinterval.InclusiveRange{Begin: start.Day() + 1, End: start.Day() + 1}}}
+	}
+	if endMinute <= 24*60 {
+		first.Times = []timeinterval.TimeRange{{StartMinute: startMinute, EndMinute: endMinute}}
+		return []timeinterval.TimeInterval{first}
+	}
+	first.Times = []timeinterval.TimeRange{{StartMinute: startMinute, EndMinute: 24 * 60}}
+	next.Times = []timeinterval.TimeRange{{StartMinute: 0, EndMinute: endMinute - 24*60}}
+	return []timeinterval.TimeInterval{first, next}
+}
+
+// maintenanceWindowExpiry returns when the window is removed, or nil if it recurs forever.
+func maintenanceWindowExpiry(mw definitions.MaintenanceWindow) *time.Time {
+	if mw.Recurrence == nil {
+		end := mw.End
+		return &end
+	}
+	return mw.Recurrence.Until
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func minuteOfDay(t time.Time) int {
+	return t.Hour()*60 + t.Minute()
+}
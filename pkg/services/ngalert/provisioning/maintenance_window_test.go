@@ -0,0 +1,202 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestMaintenanceWindowService(t *testing.T) {
+	ctx := context.Background()
+	teamA, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+	now := time.Now()
+
+	t.Run("window is attached to the policies that its alerts are routed to", func(t *testing.T) {
+		sut := createMaintenanceWindowServiceSut(t)
+		until := now.Add(30 * 24 * time.Hour)
+
+		created, err := sut.CreateMaintenanceWindow(ctx, 1, definitions.MaintenanceWindow{
+			Name:       "nightly",
+			Start:      now.Add(time.Hour),
+			End:        now.Add(2 * time.Hour),
+			Recurrence: &definitions.MaintenanceWindowRecurrence{Frequency: definitions.MaintenanceWindowDaily, Until: &until},
+			Matchers:   definitions.ObjectMatchers{teamA},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, []definitions.PolicyReference{{Path: []int{0}, UID: "team-a", Kind: "muteTimeInterval", Name: "nightly"}}, created.Policies)
+		require.Equal(t, &until, created.ExpiresAt)
+		revision, err := getLastConfiguration(ctx, 1, sut.config)
+		require.NoError(t, err)
+		require.Equal(t, []string{"nightly"}, revision.cfg.AlertmanagerConfig.Route.Routes[0].MuteTimeIntervals)
+		require.Empty(t, revision.cfg.AlertmanagerConfig.Route.Routes[1].MuteTimeIntervals)
+		require.Len(t, revision.cfg.AlertmanagerConfig.MuteTimeIntervals, 1)
+		windows, err := sut.GetMaintenanceWindows(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+		require.Equal(t, "nightly", windows[0].Name)
+	})
+
+	t.Run("alerts handled by the root policy cannot be muted", func(t *testing.T) {
+		sut := createMaintenanceWindowServiceSut(t)
+		other, err := labels.NewMatcher(labels.MatchEqual, "team", "c")
+		require.NoError(t, err)
+
+		_, err = sut.CreateMaintenanceWindow(ctx, 1, definitions.MaintenanceWindow{
+			Name:     "release",
+			Start:    now,
+			End:      now.Add(time.Hour),
+			Matchers: definitions.ObjectMatchers{other},
+		}, models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("expired windows are removed with their mute timings", func(t *testing.T) {
+		sut := createMaintenanceWindowServiceSut(t)
+		_, err := sut.CreateMaintenanceWindow(ctx, 1, definitions.MaintenanceWindow{
+			Name:     "release",
+			Start:    now,
+			End:      now.Add(time.Hour),
+			Matchers: definitions.ObjectMatchers{teamA},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		// Not expired yet.
+		require.NoError(t, sut.ExpireMaintenanceWindows(ctx, now))
+		windows, err := sut.GetMaintenanceWindows(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, windows, 1)
+
+		require.NoError(t, sut.ExpireMaintenanceWindows(ctx, now.Add(time.Hour)))
+		windows, err = sut.GetMaintenanceWindows(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, windows)
+		revision, err := getLastConfiguration(ctx, 1, sut.config)
+		require.NoError(t, err)
+		require.Empty(t, revision.cfg.AlertmanagerConfig.MuteTimeIntervals)
+		require.Empty(t, revision.cfg.AlertmanagerConfig.Route.Routes[0].MuteTimeIntervals)
+		entries := sut.prov.(*fakeProvisioningStore).auditEntries
+		require.Equal(t, models.ProvisioningAuditActionExpire, entries[len(entries)-1].Action)
+	})
+
+	t.Run("deleting an unknown window returns not found", func(t *testing.T) {
+		sut := createMaintenanceWindowServiceSut(t)
+
+		err := sut.DeleteMaintenanceWindow(ctx, 1, "unknown")
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("invalid windows are rejected", func(t *testing.T) {
+		regex, err := labels.NewMatcher(labels.MatchRegexp, "team", "a|b")
+		require.NoError(t, err)
+		testCases := map[string]definitions.MaintenanceWindow{
+			"end before start": {Start: now.Add(time.Hour), End: now},
+			"in the past":      {Start: now.Add(-2 * time.Hour), End: now.Add(-time.Hour)},
+			"unknown location": {Start: now, End: now.Add(time.Hour), Location: "Mars/Olympus_Mons"},
+			"regex matcher":    {Start: now, End: now.Add(time.Hour), Matchers: definitions.ObjectMatchers{regex}},
+			"unknown frequency": {Start: now, End: now.Add(time.Hour),
+				Recurrence: &definitions.MaintenanceWindowRecurrence{Frequency: "hourly"}},
+			"recurring window longer than a day": {Start: now, End: now.Add(25 * time.Hour),
+				Recurrence: &definitions.MaintenanceWindowRecurrence{Frequency: definitions.MaintenanceWindowWeekly}},
+			"first occurrence after the next recurrence": {Start: now.Add(25 * time.Hour), End: now.Add(26 * time.Hour),
+				Recurrence: &definitions.MaintenanceWindowRecurrence{Frequency: definitions.MaintenanceWindowDaily}},
+		}
+		for name, mw := range testCases {
+			t.Run(name, func(t *testing.T) {
+				_, err := validateMaintenanceWindow(mw, now)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+}
+
+func TestMaintenanceWindowIntervals(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	t.Run("one-shot window spanning days", func(t *testing.T) {
+		mw := definitions.MaintenanceWindow{
+			Start: time.Date(2023, 3, 10, 22, 30, 0, 0, berlin),
+			End:   time.Date(2023, 3, 12, 1, 15, 0, 0, berlin),
+		}
+
+		intervals := maintenanceWindowIntervals(mw, berlin)
+
+		require.Len(t, intervals, 3)
+		windows := muteTimingWindows(intervals, mw.Start.AddDate(0, 0, -1), mw.End.AddDate(0, 0, 1))
+		require.Len(t, windows, 1)
+		require.True(t, mw.Start.Equal(windows[0].Start))
+		require.True(t, mw.End.Equal(windows[0].End))
+		// The same dates of the next year are not muted.
+		require.Empty(t, muteTimingWindows(intervals, mw.Start.AddDate(1, 0, -1), mw.End.AddDate(1, 0, 1)))
+	})
+
+	t.Run("daily window spanning midnight", func(t *testing.T) {
+		mw := definitions.MaintenanceWindow{
+			Start:      time.Date(2023, 3, 10, 23, 0, 0, 0, berlin),
+			End:        time.Date(2023, 3, 11, 1, 0, 0, 0, berlin),
+			Recurrence: &definitions.MaintenanceWindowRecurrence{Frequency: definitions.MaintenanceWindowDaily},
+		}
+
+		intervals := maintenanceWindowIntervals(mw, berlin)
+
+		windows := muteTimingWindows(intervals, mw.Start, mw.Start.AddDate(0, 0, 2))
+		require.Len(t, windows, 2)
+		for i, w := range windows {
+			require.True(t, mw.Start.AddDate(0, 0, i).Equal(w.Start))
+			require.True(t, mw.End.AddDate(0, 0, i).Equal(w.End))
+		}
+	})
+
+	t.Run("weekly window", func(t *testing.T) {
+		mw := definitions.MaintenanceWindow{
+			Start:      time.Date(2023, 3, 10, 9, 0, 0, 0, berlin),
+			End:        time.Date(2023, 3, 10, 10, 0, 0, 0, berlin),
+			Recurrence: &definitions.MaintenanceWindowRecurrence{Frequency: definitions.MaintenanceWindowWeekly},
+		}
+
+		intervals := maintenanceWindowIntervals(mw, berlin)
+
+		windows := muteTimingWindows(intervals, mw.Start, mw.Start.AddDate(0, 0, 14))
+		require.Len(t, windows, 2)
+		require.True(t, mw.Start.AddDate(0, 0, 7).Equal(windows[1].Start))
+	})
+}
+
+func createMaintenanceWindowServiceSut(t *testing.T) *MaintenanceWindowService {
+	t.Helper()
+	teamA, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+	teamB, err := labels.NewMatcher(labels.MatchEqual, "team", "b")
+	require.NoError(t, err)
+	cfg := createTestAlertingConfig()
+	cfg.AlertmanagerConfig.Route = &definitions.Route{
+		Receiver: "grafana-default-email",
+		Routes: []*definitions.Route{
+			{UID: "team-a", Receiver: "a new receiver", ObjectMatchers: definitions.ObjectMatchers{teamA}},
+			{UID: "team-b", Receiver: "existing", ObjectMatchers: definitions.ObjectMatchers{teamB}},
+		},
+	}
+	serialized, err := serializeAlertmanagerConfig(*cfg)
+	require.NoError(t, err)
+	return &MaintenanceWindowService{
+		config: newFakeAMConfigStore(string(serialized)),
+		prov:   NewFakeProvisioningStore(),
+		kv:     kvstore.NewFakeKVStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
+	}
+}
@@ -0,0 +1,77 @@
+package provisioning
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// MaxMuteTimingPreviewRange is the longest range a mute timing can be previewed for. Mute timings are evaluated
+// minute by minute, the resolution of their time ranges.
+const MaxMuteTimingPreviewRange = 90 * 24 * time.Hour
+
+// PreviewMuteTiming returns the windows within [from, to) in which the mute timing with the given name mutes
+// notifications. The time intervals are evaluated the way the Alertmanager does, in their own locations, so that
+// daylight saving time transitions are accounted for.
+func (svc *MuteTimingService) PreviewMuteTiming(ctx context.Context, orgID int64, name string, from, to time.Time) (_ definitions.MuteTimingPreview, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "muteTimeInterval", "PreviewMuteTiming", orgID,
+		attribute.String("mute_timing_name", name))
+	defer func() { done(err) }()
+	if !to.After(from) {
+		return definitions.MuteTimingPreview{}, newValidationError("to", "the end of the preview must be after its start")
+	}
+	if to.Sub(from) > MaxMuteTimingPreviewRange {
+		return definitions.MuteTimingPreview{}, newValidationError("to", "mute timings cannot be previewed for more than %s", MaxMuteTimingPreviewRange)
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, svc.config)
+	if err != nil {
+		return definitions.MuteTimingPreview{}, err
+	}
+	for _, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		if existing.Name == name {
+			return definitions.MuteTimingPreview{
+				Name:    name,
+				From:    from,
+				To:      to,
+				Windows: muteTimingWindows(existing.TimeIntervals, from, to),
+			}, nil
+		}
+	}
+	return definitions.MuteTimingPreview{}, newNotFoundError((&definitions.MuteTimeInterval{}).ResourceType(), name, "mute timing '%s' does not exist", name)
+}
+
+// muteTimingWindows returns the contiguous windows within [from, to) in which any of the intervals contains the time.
+func muteTimingWindows(intervals []timeinterval.TimeInterval, from, to time.Time) []definitions.MuteTimingWindow {
+	windows := []definitions.MuteTimingWindow{}
+	var start time.Time
+	muted := false
+	for t := from.Truncate(time.Minute); t.Before(to); t = t.Add(time.Minute) {
+		contained := false
+		for _, interval := range intervals {
+			if interval.ContainsTime(t) {
+				contained = true
+				break
+			}
+		}
+		switch {
+		case contained && !muted:
+			start = t
+			if start.Before(from) {
+				start = from
+			}
+			muted = true
+		case !contained && muted:
+			windows = append(windows, definitions.MuteTimingWindow{Start: start, End: t})
+			muted = false
+		}
+	}
+	if muted {
+		windows = append(windows, definitions.MuteTimingWindow{Start: start, End: to})
+	}
+	return windows
+}
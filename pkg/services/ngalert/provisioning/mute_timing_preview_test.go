@@ -0,0 +1,70 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/timeinterval"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPreviewMuteTiming(t *testing.T) {
+	t.Run("windows follow the location of the interval across daylight saving time", func(t *testing.T) {
+		berlin, err := time.LoadLocation("Europe/Berlin")
+		require.NoError(t, err)
+		intervals := []timeinterval.TimeInterval{{
+			Times:    []timeinterval.TimeRange{{StartMinute: 0, EndMinute: 4 * 60}},
+			Location: &timeinterval.Location{Location: berlin},
+		}}
+		from := time.Date(2023, 3, 25, 12, 0, 0, 0, time.UTC)
+		to := time.Date(2023, 3, 27, 0, 0, 0, 0, time.UTC)
+
+		windows := muteTimingWindows(intervals, from, to)
+
+		// Clocks are set forward from 02:00 to 03:00 on the 26th, so the window is one hour shorter.
+		require.Equal(t, []definitions.MuteTimingWindow{{
+			Start: time.Date(2023, 3, 25, 23, 0, 0, 0, time.UTC),
+			End:   time.Date(2023, 3, 26, 2, 0, 0, 0, time.UTC),
+		}, {
+			Start: time.Date(2023, 3, 26, 22, 0, 0, 0, time.UTC),
+			End:   to,
+		}}, windows)
+	})
+
+	t.Run("windows are clipped to the range", func(t *testing.T) {
+		intervals := []timeinterval.TimeInterval{{}}
+		from := time.Date(2023, 3, 25, 12, 0, 30, 0, time.UTC)
+		to := from.Add(time.Hour)
+
+		windows := muteTimingWindows(intervals, from, to)
+
+		require.Equal(t, []definitions.MuteTimingWindow{{Start: from, End: to}}, windows)
+	})
+
+	t.Run("rejects invalid ranges", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		now := time.Now()
+
+		_, err := sut.PreviewMuteTiming(context.Background(), 1, "asdf", now, now.Add(-time.Hour))
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = sut.PreviewMuteTiming(context.Background(), 1, "asdf", now, now.Add(MaxMuteTimingPreviewRange+time.Hour))
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("returns not found if the mute timing does not exist", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithMuteTimings,
+			})
+		now := time.Now()
+
+		_, err := sut.PreviewMuteTiming(context.Background(), 1, "does not exist", now, now.Add(time.Hour))
+
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+}
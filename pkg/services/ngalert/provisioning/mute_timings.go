@@ -3,32 +3,46 @@ package provisioning
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/prometheus/alertmanager/config"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 type MuteTimingService struct {
-	config AMConfigStore
-	prov   ProvisioningStore
-	xact   TransactionManager
-	log    log.Logger
+	config    AMConfigStore
+	prov      ProvisioningStore
+	ruleStore RuleStore
+	xact      TransactionManager
+	quotas    QuotaChecker
+	log       log.Logger
+	tracer    tracing.Tracer
+	metrics   *metrics.Provisioning
 }
 
-func NewMuteTimingService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *MuteTimingService {
+func NewMuteTimingService(config AMConfigStore, prov ProvisioningStore, ruleStore RuleStore, xact TransactionManager, quotas QuotaChecker, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *MuteTimingService {
 	return &MuteTimingService{
-		config: config,
-		prov:   prov,
-		xact:   xact,
-		log:    log,
+		config:    newTracedAMConfigStore(config, tracer, log, m),
+		prov:      prov,
+		ruleStore: ruleStore,
+		xact:      xact,
+		quotas:    quotas,
+		log:       log,
+		tracer:    tracer,
+		metrics:   m,
 	}
 }
 
 // GetMuteTimings returns a slice of all mute timings within the specified org.
-func (svc *MuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) ([]definitions.MuteTimeInterval, error) {
+func (svc *MuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) (_ []definitions.MuteTimeInterval, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "muteTimeInterval", "GetMuteTimings", orgID)
+	defer func() { done(err) }()
 	rev, err := getLastConfiguration(ctx, orgID, svc.config)
 	if err != nil {
 		return nil, err
@@ -38,15 +52,34 @@ func (svc *MuteTimingService) GetMuteTimings(ctx context.Context, orgID int64) (
 		return []definitions.MuteTimeInterval{}, nil
 	}
 
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&definitions.MuteTimeInterval{}).ResourceType())
+	if err != nil {
+		return nil, err
+	}
+
 	result := make([]definitions.MuteTimeInterval, 0, len(rev.cfg.AlertmanagerConfig.MuteTimeIntervals))
 	for _, interval := range rev.cfg.AlertmanagerConfig.MuteTimeIntervals {
-		result = append(result, definitions.MuteTimeInterval{MuteTimeInterval: interval})
+		result = append(result, definitions.MuteTimeInterval{
+			MuteTimeInterval: interval,
+			Provenance:       definitions.Provenance(provenances[interval.Name]),
+		})
 	}
 	return result, nil
 }
 
 // CreateMuteTiming adds a new mute timing within the specified org. The created mute timing is returned.
-func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error) {
+func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (result *definitions.MuteTimeInterval, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.createMuteTiming(ctx, mt, orgID)
+		return err
+	})
+	return result, err
+}
+
+func (svc *MuteTimingService) createMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (_ *definitions.MuteTimeInterval, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "muteTimeInterval", "CreateMuteTiming", orgID,
+		attribute.String("mute_timing_name", mt.Name))
+	defer func() { done(err) }()
 	if err := mt.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
@@ -61,32 +94,28 @@ func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitio
 	}
 	for _, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if mt.Name == existing.Name {
-			return nil, fmt.Errorf("%w: %s", ErrValidation, "a mute timing with this name already exists")
+			return nil, newValidationError("name", "a mute timing with this name already exists").withResource((&definitions.MuteTimeInterval{}).ResourceType(), mt.Name)
 		}
 	}
 	revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(revision.cfg.AlertmanagerConfig.MuteTimeIntervals, mt.MuteTimeInterval)
 
-	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	change, err := resourceChange(models.AlertConfigurationResourceMuteTimeInterval, mt.Name, mt.MuteTimeInterval)
 	if err != nil {
 		return nil, err
 	}
-	cmd := models.SaveAlertmanagerConfigurationCmd{
-		AlertmanagerConfiguration: string(serialized),
-		ConfigurationVersion:      revision.version,
-		FetchedConfigurationHash:  revision.concurrencyToken,
-		Default:                   false,
-		OrgID:                     orgID,
-	}
 	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, svc.config, &cmd)
+		err = persistConfigChanges(ctx, svc.config, orgID, revision, []models.AlertConfigurationResourceChange{change})
 		if err != nil {
 			return err
 		}
+		if err := MuteTimingQuota.checkTransactionCtx(ctx, svc.quotas, orgID); err != nil {
+			return err
+		}
 		err = svc.prov.SetProvenance(ctx, &mt, orgID, models.Provenance(mt.Provenance))
 		if err != nil {
 			return err
 		}
-		return nil
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionCreate, &mt, models.Provenance(mt.Provenance), nil, mt)
 	})
 	if err != nil {
 		return nil, err
@@ -96,7 +125,18 @@ func (svc *MuteTimingService) CreateMuteTiming(ctx context.Context, mt definitio
 }
 
 // UpdateMuteTiming replaces an existing mute timing within the specified org. The replaced mute timing is returned. If the mute timing does not exist, nil is returned and no action is taken.
-func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (*definitions.MuteTimeInterval, error) {
+func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (result *definitions.MuteTimeInterval, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.updateMuteTiming(ctx, mt, orgID)
+		return err
+	})
+	return result, err
+}
+
+func (svc *MuteTimingService) updateMuteTiming(ctx context.Context, mt definitions.MuteTimeInterval, orgID int64) (_ *definitions.MuteTimeInterval, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "muteTimeInterval", "UpdateMuteTiming", orgID,
+		attribute.String("mute_timing_name", mt.Name))
+	defer func() { done(err) }()
 	if err := mt.Validate(); err != nil {
 		return nil, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
@@ -109,31 +149,24 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 	if revision.cfg.AlertmanagerConfig.MuteTimeIntervals == nil {
 		return nil, nil
 	}
-	updated := false
+	var old *definitions.MuteTimeInterval
 	for i, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if mt.Name == existing.Name {
+			old = &definitions.MuteTimeInterval{MuteTimeInterval: existing}
 			revision.cfg.AlertmanagerConfig.MuteTimeIntervals[i] = mt.MuteTimeInterval
-			updated = true
 			break
 		}
 	}
-	if !updated {
+	if old == nil {
 		return nil, nil
 	}
 
-	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	change, err := resourceChange(models.AlertConfigurationResourceMuteTimeInterval, mt.Name, mt.MuteTimeInterval)
 	if err != nil {
 		return nil, err
 	}
-	cmd := models.SaveAlertmanagerConfigurationCmd{
-		AlertmanagerConfiguration: string(serialized),
-		ConfigurationVersion:      revision.version,
-		FetchedConfigurationHash:  revision.concurrencyToken,
-		Default:                   false,
-		OrgID:                     orgID,
-	}
 	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, svc.config, &cmd)
+		err = persistConfigChanges(ctx, svc.config, orgID, revision, []models.AlertConfigurationResourceChange{change})
 		if err != nil {
 			return err
 		}
@@ -141,7 +174,7 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 		if err != nil {
 			return err
 		}
-		return nil
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionUpdate, &mt, models.Provenance(mt.Provenance), *old, mt)
 	})
 	if err != nil {
 		return nil, err
@@ -150,8 +183,65 @@ func (svc *MuteTimingService) UpdateMuteTiming(ctx context.Context, mt definitio
 	return &mt, err
 }
 
+// DeleteMuteTimingOptions are the options of DeleteMuteTiming.
+type DeleteMuteTimingOptions struct {
+	// Force deletes the mute timing even if notification policies or the notification settings of alert rules use
+	// it. It is removed from these policies and rules.
+	Force bool
+}
+
+// MuteTimingInUseError is returned if a mute timing cannot be deleted because notification policies use it.
+type MuteTimingInUseError struct {
+	Usage definitions.MuteTimingUsage
+}
+
+func (e *MuteTimingInUseError) Error() string {
+	paths := make([]string, 0, len(e.Usage.Policies))
+	for _, ref := range e.Usage.Policies {
+		paths = append(paths, fmt.Sprintf("%v", ref.Path))
+	}
+	return fmt.Sprintf("%s: mute timing '%s' is used by the notification policies at path %s", ErrValidation.Error(), e.Usage.Name, strings.Join(paths, ", "))
+}
+
+func (e *MuteTimingInUseError) Unwrap() error {
+	return ErrValidation
+}
+
+// GetMuteTimingUsage returns the notification policies that use the mute timing with the given name.
+func (svc *MuteTimingService) GetMuteTimingUsage(ctx context.Context, name string, orgID int64) (_ definitions.MuteTimingUsage, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "muteTimeInterval", "GetMuteTimingUsage", orgID,
+		attribute.String("mute_timing_name", name))
+	defer func() { done(err) }()
+	revision, err := getLastConfiguration(ctx, orgID, svc.config)
+	if err != nil {
+		return definitions.MuteTimingUsage{}, err
+	}
+
+	for _, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		if existing.Name == name {
+			policies := muteTimingUsage(name, revision.cfg.AlertmanagerConfig.Route, []int{})
+			if policies == nil {
+				policies = []definitions.PolicyReference{}
+			}
+			return definitions.MuteTimingUsage{Name: name, Policies: policies}, nil
+		}
+	}
+	return definitions.MuteTimingUsage{}, newNotFoundError((&definitions.MuteTimeInterval{}).ResourceType(), name, "mute timing '%s' does not exist", name)
+}
+
 // DeleteMuteTiming deletes the mute timing with the given name in the given org. If the mute timing does not exist, no error is returned.
-func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string, orgID int64) error {
+// A MuteTimingInUseError is returned if notification policies use the mute timing, and a validation error if the
+// notification settings of alert rules use it, unless the deletion is forced.
+func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string, orgID int64, opts DeleteMuteTimingOptions) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return svc.deleteMuteTiming(ctx, name, orgID, opts)
+	})
+}
+
+func (svc *MuteTimingService) deleteMuteTiming(ctx context.Context, name string, orgID int64, opts DeleteMuteTimingOptions) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "muteTimeInterval", "DeleteMuteTiming", orgID,
+		attribute.String("mute_timing_name", name))
+	defer func() { done(err) }()
 	revision, err := getLastConfiguration(ctx, orgID, svc.config)
 	if err != nil {
 		return err
@@ -160,54 +250,154 @@ func (svc *MuteTimingService) DeleteMuteTiming(ctx context.Context, name string,
 	if revision.cfg.AlertmanagerConfig.MuteTimeIntervals == nil {
 		return nil
 	}
-	if isMuteTimeInUse(name, []*definitions.Route{revision.cfg.AlertmanagerConfig.Route}) {
-		return fmt.Errorf("mute time '%s' is currently used by a notification policy", name)
+	// The deletion can only be persisted on its own if no notification policies have to be changed as well.
+	changes := []models.AlertConfigurationResourceChange{deletedResource(models.AlertConfigurationResourceMuteTimeInterval, name)}
+	if usage := muteTimingUsage(name, revision.cfg.AlertmanagerConfig.Route, []int{}); len(usage) > 0 {
+		if !opts.Force {
+			return &MuteTimingInUseError{Usage: definitions.MuteTimingUsage{Name: name, Policies: usage}}
+		}
+		removeMuteTimingReferences(name, revision.cfg.AlertmanagerConfig.Route)
+		changes = nil
 	}
+	var oldState any
 	for i, existing := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
 		if name == existing.Name {
+			oldState = definitions.MuteTimeInterval{MuteTimeInterval: existing}
 			intervals := revision.cfg.AlertmanagerConfig.MuteTimeIntervals
 			revision.cfg.AlertmanagerConfig.MuteTimeIntervals = append(intervals[:i], intervals[i+1:]...)
 		}
 	}
-
-	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
-	if err != nil {
-		return err
-	}
-	cmd := models.SaveAlertmanagerConfigurationCmd{
-		AlertmanagerConfiguration: string(serialized),
-		ConfigurationVersion:      revision.version,
-		FetchedConfigurationHash:  revision.concurrencyToken,
-		Default:                   false,
-		OrgID:                     orgID,
+	var ruleUpdates []models.UpdateRule
+	if oldState != nil {
+		rules, err := svc.rulesUsingMuteTiming(ctx, orgID, name)
+		if err != nil {
+			return err
+		}
+		if len(rules) > 0 && !opts.Force {
+			return newValidationError("", "mute timing '%s' is used by the notification settings of the alert rules %s", name, ruleUIDs(rules)).
+				withResource((&definitions.MuteTimeInterval{}).ResourceType(), name)
+		}
+		ruleUpdates = withoutMuteTiming(rules, name)
 	}
+
 	return svc.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, svc.config, &cmd)
+		err = persistConfigChanges(ctx, svc.config, orgID, revision, changes)
 		if err != nil {
 			return err
 		}
+		if err := svc.applyRuleUpdates(ctx, orgID, ruleUpdates); err != nil {
+			return err
+		}
 		target := definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: name}}
 		err := svc.prov.DeleteProvenance(ctx, &target, orgID)
 		if err != nil {
 			return err
 		}
-		return nil
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionDelete, &target, models.ProvenanceNone, oldState, nil)
 	})
 }
 
-func isMuteTimeInUse(name string, routes []*definitions.Route) bool {
-	if len(routes) == 0 {
-		return false
+// rulesUsingMuteTiming returns the alert rules of the org whose notification settings use the mute timing.
+func (svc *MuteTimingService) rulesUsingMuteTiming(ctx context.Context, orgID int64, name string) (models.RulesGroup, error) {
+	if svc.ruleStore == nil {
+		return nil, nil
 	}
-	for _, route := range routes {
-		for _, mtName := range route.MuteTimeIntervals {
+	rules, err := svc.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	var result models.RulesGroup
+	for _, rule := range rules {
+		if usesMuteTiming(rule, name) {
+			result = append(result, rule)
+		}
+	}
+	return result, nil
+}
+
+func usesMuteTiming(rule *models.AlertRule, name string) bool {
+	for _, settings := range rule.NotificationSettings {
+		for _, mtName := range settings.MuteTimeIntervals {
 			if mtName == name {
 				return true
 			}
 		}
-		if isMuteTimeInUse(name, route.Routes) {
-			return true
-		}
 	}
 	return false
 }
+
+// withoutMuteTiming returns the updates of the rules that remove the mute timing from their notification settings.
+func withoutMuteTiming(rules models.RulesGroup, name string) []models.UpdateRule {
+	updates := make([]models.UpdateRule, 0, len(rules))
+	for _, rule := range rules {
+		newRule := *rule
+		newRule.NotificationSettings = make([]models.NotificationSettings, 0, len(rule.NotificationSettings))
+		for _, s := range rule.NotificationSettings {
+			intervals := make([]string, 0, len(s.MuteTimeIntervals))
+			for _, mtName := range s.MuteTimeIntervals {
+				if mtName != name {
+					intervals = append(intervals, mtName)
+				}
+			}
+			s.MuteTimeIntervals = intervals
+			newRule.NotificationSettings = append(newRule.NotificationSettings, s)
+		}
+		updates = append(updates, models.UpdateRule{Existing: rule, New: newRule})
+	}
+	return updates
+}
+
+// applyRuleUpdates persists the updates of the alert rules whose notification settings no longer use a deleted mute
+// timing, and records them in the audit log.
+func (svc *MuteTimingService) applyRuleUpdates(ctx context.Context, orgID int64, updates []models.UpdateRule) error {
+	if len(updates) == 0 {
+		return nil
+	}
+	provenances, err := svc.prov.GetProvenances(ctx, orgID, (&models.AlertRule{}).ResourceType())
+	if err != nil {
+		return err
+	}
+	if err := svc.ruleStore.UpdateAlertRules(ctx, updates); err != nil {
+		return err
+	}
+	for _, update := range updates {
+		if err := recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionUpdate, &update.New, provenanceOrNone(provenances, update.New.UID), update.Existing, update.New); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// muteTimingUsage returns the routes of the tree that use the mute timing with the given name.
+func muteTimingUsage(name string, route *definitions.Route, path []int) []definitions.PolicyReference {
+	if route == nil {
+		return nil
+	}
+	var refs []definitions.PolicyReference
+	for _, mtName := range route.MuteTimeIntervals {
+		if mtName == name {
+			refs = append(refs, definitions.PolicyReference{Path: path, UID: route.UID, Kind: policyReferenceMuteTimeInterval, Name: name})
+			break
+		}
+	}
+	for i, child := range route.Routes {
+		refs = append(refs, muteTimingUsage(name, child, append(append(make([]int, 0, len(path)+1), path...), i))...)
+	}
+	return refs
+}
+
+func removeMuteTimingReferences(name string, routes ...*definitions.Route) {
+	for _, route := range routes {
+		if route == nil {
+			continue
+		}
+		intervals := route.MuteTimeIntervals[:0]
+		for _, mtName := range route.MuteTimeIntervals {
+			if mtName != name {
+				intervals = append(intervals, mtName)
+			}
+		}
+		route.MuteTimeIntervals = intervals
+		removeMuteTimingReferences(name, route.Routes...)
+	}
+}
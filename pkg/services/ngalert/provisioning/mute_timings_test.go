@@ -10,8 +10,10 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests/fakes"
 )
 
 func TestMuteTimingService(t *testing.T) {
@@ -21,12 +23,16 @@ func TestMuteTimingService(t *testing.T) {
 			GetsConfig(models.AlertConfiguration{
 				AlertmanagerConfiguration: configWithMuteTimings,
 			})
+		sut.prov.(*MockProvisioningStore).EXPECT().
+			GetProvenances(mock.Anything, mock.Anything, mock.Anything).
+			Return(map[string]models.Provenance{"asdf": models.ProvenanceFile}, nil)
 
 		result, err := sut.GetMuteTimings(context.Background(), 1)
 
 		require.NoError(t, err)
 		require.Len(t, result, 1)
 		require.Equal(t, "asdf", result[0].Name)
+		require.Equal(t, definitions.Provenance(models.ProvenanceFile), result[0].Provenance)
 	})
 
 	t.Run("service returns empty list when config file contains no mute timings", func(t *testing.T) {
@@ -286,7 +292,7 @@ func TestMuteTimingService(t *testing.T) {
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
 			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-			err := sut.DeleteMuteTiming(context.Background(), "does not exist", 1)
+			err := sut.DeleteMuteTiming(context.Background(), "does not exist", 1, DeleteMuteTimingOptions{})
 
 			require.NoError(t, err)
 		})
@@ -298,7 +304,7 @@ func TestMuteTimingService(t *testing.T) {
 					GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(nil, fmt.Errorf("failed"))
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
 
 				require.Error(t, err)
 			})
@@ -310,7 +316,7 @@ func TestMuteTimingService(t *testing.T) {
 						AlertmanagerConfiguration: brokenConfig,
 					})
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
 
 				require.ErrorContains(t, err, "failed to deserialize")
 			})
@@ -321,7 +327,7 @@ func TestMuteTimingService(t *testing.T) {
 					GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).
 					Return(nil, nil)
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
 
 				require.ErrorContains(t, err, "no alertmanager configuration")
 			})
@@ -337,7 +343,7 @@ func TestMuteTimingService(t *testing.T) {
 					DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).
 					Return(fmt.Errorf("failed to save provenance"))
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
 
 				require.ErrorContains(t, err, "failed to save provenance")
 			})
@@ -353,7 +359,7 @@ func TestMuteTimingService(t *testing.T) {
 					Return(fmt.Errorf("failed to save config"))
 				sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
 
 				require.ErrorContains(t, err, "failed to save config")
 			})
@@ -365,11 +371,104 @@ func TestMuteTimingService(t *testing.T) {
 						AlertmanagerConfiguration: configWithMuteTimingsInRoute,
 					})
 
-				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1)
+				err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
 
-				require.Error(t, err)
+				require.ErrorIs(t, err, ErrValidation)
+				var inUse *MuteTimingInUseError
+				require.ErrorAs(t, err, &inUse)
+				require.Equal(t, []definitions.PolicyReference{
+					{Path: []int{0}, Kind: "muteTimeInterval", Name: "asdf"},
+				}, inUse.Usage.Policies)
 			})
 		})
+
+		t.Run("removes the mute timing from policies if forced", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimingsInRoute,
+				})
+			var saved models.SaveAlertmanagerConfigurationCmd
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceedsIntercept(&saved)
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+
+			err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{Force: true})
+
+			require.NoError(t, err)
+			cfg, err := deserializeAlertmanagerConfig(saved.AlertmanagerConfiguration)
+			require.NoError(t, err)
+			require.Empty(t, cfg.AlertmanagerConfig.MuteTimeIntervals)
+			require.Empty(t, cfg.AlertmanagerConfig.Route.Routes[0].MuteTimeIntervals)
+		})
+
+		t.Run("refuses to delete mute timings used by alert rules", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+				})
+			sut.ruleStore = ruleStoreWithMuteTiming(t, "asdf")
+
+			err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{})
+
+			require.ErrorIs(t, err, ErrValidation)
+			require.ErrorContains(t, err, "team-rule")
+		})
+
+		t.Run("removes the mute timing from alert rules if forced", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimings,
+				})
+			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().GetProvenances(mock.Anything, mock.Anything, mock.Anything).Return(nil, nil)
+			ruleStore := ruleStoreWithMuteTiming(t, "asdf")
+			sut.ruleStore = ruleStore
+
+			err := sut.DeleteMuteTiming(context.Background(), "asdf", 1, DeleteMuteTimingOptions{Force: true})
+
+			require.NoError(t, err)
+			var updates []models.UpdateRule
+			for _, op := range ruleStore.RecordedOps {
+				if u, ok := op.([]models.UpdateRule); ok {
+					updates = append(updates, u...)
+				}
+			}
+			require.Len(t, updates, 1)
+			require.Equal(t, []models.NotificationSettings{{Receiver: "grafana-default-email", MuteTimeIntervals: []string{"other"}}}, updates[0].New.NotificationSettings)
+		})
+	})
+
+	t.Run("mute timing usage", func(t *testing.T) {
+		t.Run("lists the policies that use the mute timing", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimingsInRoute,
+				})
+
+			usage, err := sut.GetMuteTimingUsage(context.Background(), "asdf", 1)
+
+			require.NoError(t, err)
+			require.Equal(t, definitions.MuteTimingUsage{
+				Name:     "asdf",
+				Policies: []definitions.PolicyReference{{Path: []int{0}, Kind: "muteTimeInterval", Name: "asdf"}},
+			}, usage)
+		})
+
+		t.Run("returns not found if the mute timing does not exist", func(t *testing.T) {
+			sut := createMuteTimingSvcSut()
+			sut.config.(*MockAMConfigStore).EXPECT().
+				GetsConfig(models.AlertConfiguration{
+					AlertmanagerConfiguration: configWithMuteTimingsInRoute,
+				})
+
+			_, err := sut.GetMuteTimingUsage(context.Background(), "does not exist", 1)
+
+			require.ErrorIs(t, err, ErrNotFound)
+		})
 	})
 }
 
@@ -379,9 +478,21 @@ func createMuteTimingSvcSut() *MuteTimingService {
 		prov:   &MockProvisioningStore{},
 		xact:   newNopTransactionManager(),
 		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
 	}
 }
 
+// ruleStoreWithMuteTiming returns a rule store with a rule whose notification settings use the mute timing.
+func ruleStoreWithMuteTiming(t *testing.T, name string) *fakes.RuleStore {
+	t.Helper()
+	ruleStore := fakes.NewRuleStore(t)
+	rule := dummyRule("team rule", 1)
+	rule.UID = "team-rule"
+	rule.NotificationSettings = []models.NotificationSettings{{Receiver: "grafana-default-email", MuteTimeIntervals: []string{name, "other"}}}
+	ruleStore.PutRule(context.Background(), &rule)
+	return ruleStore
+}
+
 func createMuteTiming() definitions.MuteTimeInterval {
 	return definitions.MuteTimeInterval{
 		MuteTimeInterval: config.MuteTimeInterval{
@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/grafana/grafana/pkg/infra/appcontext"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/setting"
 )
@@ -14,18 +17,24 @@ type NotificationPolicyService struct {
 	amStore         AMConfigStore
 	provenanceStore ProvisioningStore
 	xact            TransactionManager
+	quotas          QuotaChecker
 	log             log.Logger
 	settings        setting.UnifiedAlertingSettings
+	tracer          tracing.Tracer
+	metrics         *metrics.Provisioning
 }
 
 func NewNotificationPolicyService(am AMConfigStore, prov ProvisioningStore,
-	xact TransactionManager, settings setting.UnifiedAlertingSettings, log log.Logger) *NotificationPolicyService {
+	xact TransactionManager, quotas QuotaChecker, settings setting.UnifiedAlertingSettings, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *NotificationPolicyService {
 	return &NotificationPolicyService{
-		amStore:         am,
+		amStore:         newTracedAMConfigStore(am, tracer, log, m),
 		provenanceStore: prov,
 		xact:            xact,
+		quotas:          quotas,
 		log:             log,
 		settings:        settings,
+		tracer:          tracer,
+		metrics:         m,
 	}
 }
 
@@ -33,7 +42,9 @@ func (nps *NotificationPolicyService) GetAMConfigStore() AMConfigStore {
 	return nps.amStore
 }
 
-func (nps *NotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
+func (nps *NotificationPolicyService) GetPolicyTree(ctx context.Context, orgID int64) (_ definitions.Route, err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "GetPolicyTree", orgID)
+	defer func() { done(err) }()
 	q := models.GetLatestAlertmanagerConfigurationQuery{
 		OrgID: orgID,
 	}
@@ -42,7 +53,7 @@ func (nps *NotificationPolicyService) GetPolicyTree(ctx context.Context, orgID i
 		return definitions.Route{}, err
 	}
 
-	cfg, err := deserializeAlertmanagerConfig([]byte(alertManagerConfig.AlertmanagerConfiguration))
+	cfg, err := deserializeAlertmanagerConfig(alertManagerConfig.AlertmanagerConfiguration)
 	if err != nil {
 		return definitions.Route{}, err
 	}
@@ -59,44 +70,103 @@ func (nps *NotificationPolicyService) GetPolicyTree(ctx context.Context, orgID i
 	result := *cfg.AlertmanagerConfig.Route
 	result.Provenance = definitions.Provenance(provenance)
 
+	subtreeProvenances, err := nps.provenanceStore.GetProvenances(ctx, orgID, (&definitions.Route{}).ResourceType())
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	for _, child := range result.Routes {
+		setSubtreeProvenances(child, subtreeProvenances)
+	}
+
 	return result, nil
 }
 
-func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) error {
-	err := tree.Validate()
-	if err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+// MaxPolicySubtreeDepth is the maximum length of the path and the maximum depth of a PolicySubtreeQuery.
+const MaxPolicySubtreeDepth = 100
+
+// PolicySubtreeQuery selects a part of a notification policy tree.
+type PolicySubtreeQuery struct {
+	// Path is the list of child indexes leading from the root to the selected route. An empty path selects the root.
+	Path []int
+	// Depth is the number of levels of the selected route to return, including the route itself. Zero means unlimited.
+	Depth int
+}
+
+// SelectPolicySubtree returns the route of the tree addressed by the query, with its children truncated to the
+// requested depth. The given tree is not modified.
+func SelectPolicySubtree(tree definitions.Route, q PolicySubtreeQuery) (definitions.Route, error) {
+	if q.Depth < 0 || q.Depth > MaxPolicySubtreeDepth {
+		return definitions.Route{}, newValidationError("depth", "depth must be between 0 and %d", MaxPolicySubtreeDepth)
+	}
+	if len(q.Path) > MaxPolicySubtreeDepth {
+		return definitions.Route{}, newValidationError("path", "path must not be longer than %d", MaxPolicySubtreeDepth)
+	}
+	selected := &tree
+	for i, idx := range q.Path {
+		if idx < 0 || idx >= len(selected.Routes) {
+			return definitions.Route{}, fmt.Errorf("%w: route at path %v does not exist", ErrNotFound, q.Path[:i+1])
+		}
+		selected = selected.Routes[idx]
 	}
+	return truncateRoute(*selected, q.Depth), nil
+}
 
-	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
-	if err != nil {
-		return err
+func truncateRoute(route definitions.Route, depth int) definitions.Route {
+	if depth == 0 {
+		return route
+	}
+	if depth == 1 {
+		route.Routes = nil
+		return route
+	}
+	children := make([]*definitions.Route, 0, len(route.Routes))
+	for _, child := range route.Routes {
+		truncated := truncateRoute(*child, depth-1)
+		children = append(children, &truncated)
 	}
+	route.Routes = children
+	return route
+}
 
-	receivers, err := nps.receiversToMap(revision.cfg.AlertmanagerConfig.Receivers)
-	if err != nil {
+// UpdatePolicyTree replaces the notification policy tree and returns how the tree was changed. The changes are logged
+// so that it is known who changed the routing of notifications and how.
+func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) (result definitions.PolicyTreeDiff, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = nps.updatePolicyTree(ctx, orgID, tree, p)
 		return err
+	})
+	return result, err
+}
+
+func (nps *NotificationPolicyService) updatePolicyTree(ctx context.Context, orgID int64, tree definitions.Route, p models.Provenance) (_ definitions.PolicyTreeDiff, err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "UpdatePolicyTree", orgID)
+	defer func() { done(err) }()
+	err = tree.Validate()
+	if err != nil {
+		return definitions.PolicyTreeDiff{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
 
-	err = tree.ValidateReceivers(receivers)
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		return definitions.PolicyTreeDiff{}, err
 	}
 
-	muteTimes := map[string]struct{}{}
-	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
-		muteTimes[mt.Name] = struct{}{}
+	err = validatePolicyTree(&tree, revision)
+	if err != nil {
+		return definitions.PolicyTreeDiff{}, err
 	}
-	err = tree.ValidateMuteTimes(muteTimes)
+	err = nps.checkPolicyTreeLimits(&tree)
 	if err != nil {
-		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+		return definitions.PolicyTreeDiff{}, err
 	}
 
+	oldTree := revision.cfg.AlertmanagerConfig.Config.Route
 	revision.cfg.AlertmanagerConfig.Config.Route = &tree
+	diff := diffPolicyTrees(oldTree, &tree)
 
 	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
 	if err != nil {
-		return err
+		return definitions.PolicyTreeDiff{}, err
 	}
 	cmd := models.SaveAlertmanagerConfigurationCmd{
 		AlertmanagerConfiguration: string(serialized),
@@ -114,19 +184,44 @@ func (nps *NotificationPolicyService) UpdatePolicyTree(ctx context.Context, orgI
 		if err != nil {
 			return err
 		}
-		return nil
+		err = deleteRemovedRouteProvenances(ctx, nps.provenanceStore, orgID, oldTree, &tree)
+		if err != nil {
+			return err
+		}
+		if err := nps.checkPolicyQuota(ctx, orgID, oldTree, &tree); err != nil {
+			return err
+		}
+		return recordAudit(ctx, nps.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, &tree, p, oldTree, tree)
 	})
 	if err != nil {
-		return err
+		return definitions.PolicyTreeDiff{}, err
 	}
+	nps.recordPolicyTreeSize(orgID, &tree)
+	logArgs := []any{"org", orgID, "provenance", p}
+	if u, err := appcontext.User(ctx); err == nil {
+		logArgs = append(logArgs, "user", u.Login)
+	}
+	if source := sourceFromContext(ctx); source != "" {
+		logArgs = append(logArgs, "source", source)
+	}
+	nps.log.FromContext(ctx).Info("Updated notification policy tree", append(logArgs, policyTreeDiffLogArgs(diff)...)...)
+	return diff, nil
+}
 
-	return nil
+func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (result definitions.Route, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = nps.resetPolicyTree(ctx, orgID)
+		return err
+	})
+	return result, err
 }
 
-func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID int64) (definitions.Route, error) {
-	defaultCfg, err := deserializeAlertmanagerConfig([]byte(nps.settings.DefaultConfiguration))
+func (nps *NotificationPolicyService) resetPolicyTree(ctx context.Context, orgID int64) (_ definitions.Route, err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "ResetPolicyTree", orgID)
+	defer func() { done(err) }()
+	defaultCfg, err := deserializeAlertmanagerConfig(nps.settings.DefaultConfiguration)
 	if err != nil {
-		nps.log.Error("Failed to parse default alertmanager config: %w", err)
+		nps.log.FromContext(ctx).Error("Failed to parse default alertmanager config", "error", err)
 		return definitions.Route{}, fmt.Errorf("failed to parse default alertmanager config: %w", err)
 	}
 	route := defaultCfg.AlertmanagerConfig.Route
@@ -135,6 +230,7 @@ func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID
 	if err != nil {
 		return definitions.Route{}, err
 	}
+	oldTree := revision.cfg.AlertmanagerConfig.Config.Route
 	revision.cfg.AlertmanagerConfig.Config.Route = route
 	err = nps.ensureDefaultReceiverExists(revision.cfg, defaultCfg)
 	if err != nil {
@@ -161,21 +257,27 @@ func (nps *NotificationPolicyService) ResetPolicyTree(ctx context.Context, orgID
 		if err != nil {
 			return err
 		}
-		return nil
+		err = deleteRemovedRouteProvenances(ctx, nps.provenanceStore, orgID, oldTree, route)
+		if err != nil {
+			return err
+		}
+		return recordAudit(ctx, nps.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, route, models.ProvenanceNone, oldTree, route)
 	})
 	if err != nil {
-		return definitions.Route{}, nil
+		return definitions.Route{}, err
 	}
+	nps.recordPolicyTreeSize(orgID, route)
 
 	return *route, nil
 }
 
-func (nps *NotificationPolicyService) receiversToMap(records []*definitions.PostableApiReceiver) (map[string]struct{}, error) {
-	receivers := map[string]struct{}{}
-	for _, receiver := range records {
-		receivers[receiver.Name] = struct{}{}
+// checkPolicyQuota checks the quota of notification policies in the current transaction if the change adds policies,
+// so that orgs that are over a lowered quota can still change and remove their policies.
+func (nps *NotificationPolicyService) checkPolicyQuota(ctx context.Context, orgID int64, oldTree, newTree *definitions.Route) error {
+	if countPolicies(newTree) <= countPolicies(oldTree) {
+		return nil
 	}
-	return receivers, nil
+	return NotificationPolicyQuota.checkTransactionCtx(ctx, nps.quotas, orgID)
 }
 
 func (nps *NotificationPolicyService) ensureDefaultReceiverExists(cfg *definitions.PostableUserConfig, defaultCfg *definitions.PostableUserConfig) error {
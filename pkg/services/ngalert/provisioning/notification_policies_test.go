@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/setting"
@@ -48,7 +49,7 @@ func TestNotificationPolicyService(t *testing.T) {
 			MuteTimeIntervals: []string{"not-existing"},
 		})
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
 		require.Error(t, err)
 	})
 
@@ -74,8 +75,12 @@ func TestNotificationPolicyService(t *testing.T) {
 			MuteTimeIntervals: []string{"existing"},
 		})
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
+		diff, err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
 		require.NoError(t, err)
+		require.Len(t, diff.Added, 1)
+		require.Equal(t, []int{0}, diff.Added[0].Path)
+		require.Len(t, diff.Modified, 1)
+		require.Contains(t, diff.Modified[0].Fields, "receiver")
 	})
 
 	t.Run("service stitches policy tree into org's AM config", func(t *testing.T) {
@@ -83,7 +88,7 @@ func TestNotificationPolicyService(t *testing.T) {
 
 		newRoute := createTestRoutingTree()
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
 		require.NoError(t, err)
 
 		updated, err := sut.GetPolicyTree(context.Background(), 1)
@@ -99,7 +104,7 @@ func TestNotificationPolicyService(t *testing.T) {
 			Receiver: "not-existing",
 		})
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
 		require.Error(t, err)
 	})
 
@@ -118,7 +123,7 @@ func TestNotificationPolicyService(t *testing.T) {
 			Receiver: "existing",
 		})
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceNone)
 		require.NoError(t, err)
 	})
 
@@ -135,7 +140,7 @@ func TestNotificationPolicyService(t *testing.T) {
 		sut := createNotificationPolicyServiceSut()
 		newRoute := createTestRoutingTree()
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
 		require.NoError(t, err)
 
 		updated, err := sut.GetPolicyTree(context.Background(), 1)
@@ -153,7 +158,7 @@ func TestNotificationPolicyService(t *testing.T) {
 		require.NoError(t, err)
 		expectedConcurrencyToken := config.ConfigurationHash
 
-		err = sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
+		_, err = sut.UpdatePolicyTree(context.Background(), 1, newRoute, models.ProvenanceAPI)
 		require.NoError(t, err)
 
 		fake := sut.GetAMConfigStore().(*fakeAMConfigStore)
@@ -167,7 +172,7 @@ func TestNotificationPolicyService(t *testing.T) {
 		repeat := model.Duration(0)
 		invalid.RepeatInterval = &repeat
 
-		err := sut.UpdatePolicyTree(context.Background(), 1, invalid, models.ProvenanceNone)
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, invalid, models.ProvenanceNone)
 
 		require.Error(t, err)
 		require.ErrorIs(t, err, ErrValidation)
@@ -211,12 +216,60 @@ func TestNotificationPolicyService(t *testing.T) {
 		require.Equal(t, "grafana-default-email", tree.Receiver)
 		require.NotEmpty(t, interceptedSave.AlertmanagerConfiguration)
 		// Deserializing with no error asserts that the saved config is semantically valid.
-		newCfg, err := deserializeAlertmanagerConfig([]byte(interceptedSave.AlertmanagerConfiguration))
+		newCfg, err := deserializeAlertmanagerConfig(interceptedSave.AlertmanagerConfiguration)
 		require.NoError(t, err)
 		require.Len(t, newCfg.AlertmanagerConfig.Receivers, 2)
 	})
 }
 
+func TestSelectPolicySubtree(t *testing.T) {
+	tree := definitions.Route{
+		Receiver: "root",
+		Routes: []*definitions.Route{
+			{Receiver: "a", Routes: []*definitions.Route{{Receiver: "a.a"}, {Receiver: "a.b", Routes: []*definitions.Route{{Receiver: "a.b.a"}}}}},
+			{Receiver: "b"},
+		},
+	}
+
+	t.Run("empty query returns the whole tree", func(t *testing.T) {
+		result, err := SelectPolicySubtree(tree, PolicySubtreeQuery{})
+		require.NoError(t, err)
+		require.Equal(t, tree, result)
+	})
+
+	t.Run("path selects a nested route", func(t *testing.T) {
+		result, err := SelectPolicySubtree(tree, PolicySubtreeQuery{Path: []int{0, 1}})
+		require.NoError(t, err)
+		require.Equal(t, "a.b", result.Receiver)
+		require.Len(t, result.Routes, 1)
+	})
+
+	t.Run("depth truncates children without modifying the tree", func(t *testing.T) {
+		result, err := SelectPolicySubtree(tree, PolicySubtreeQuery{Depth: 2})
+		require.NoError(t, err)
+		require.Len(t, result.Routes, 2)
+		require.Empty(t, result.Routes[0].Routes)
+		require.Len(t, tree.Routes[0].Routes, 2)
+	})
+
+	t.Run("path outside of the tree returns not found", func(t *testing.T) {
+		_, err := SelectPolicySubtree(tree, PolicySubtreeQuery{Path: []int{1, 0}})
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("negative depth is invalid", func(t *testing.T) {
+		_, err := SelectPolicySubtree(tree, PolicySubtreeQuery{Depth: -1})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("depth and path beyond the limit are invalid", func(t *testing.T) {
+		_, err := SelectPolicySubtree(tree, PolicySubtreeQuery{Depth: MaxPolicySubtreeDepth + 1})
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = SelectPolicySubtree(tree, PolicySubtreeQuery{Path: make([]int, MaxPolicySubtreeDepth+1)})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
 func createNotificationPolicyServiceSut() *NotificationPolicyService {
 	return &NotificationPolicyService{
 		amStore:         newFakeAMConfigStore(defaultAlertmanagerConfigJSON),
@@ -226,6 +279,7 @@ func createNotificationPolicyServiceSut() *NotificationPolicyService {
 		settings: setting.UnifiedAlertingSettings{
 			DefaultConfiguration: setting.GetAlertmanagerDefaultConfiguration(),
 		},
+		tracer: tracing.InitializeTracerForTest(),
 	}
 }
 
@@ -236,7 +290,7 @@ func createTestRoutingTree() definitions.Route {
 }
 
 func createTestAlertingConfig() *definitions.PostableUserConfig {
-	cfg, _ := deserializeAlertmanagerConfig([]byte(defaultConfig))
+	cfg, _ := deserializeAlertmanagerConfig(defaultConfig)
 	cfg.AlertmanagerConfig.Receivers = append(cfg.AlertmanagerConfig.Receivers,
 		&definitions.PostableApiReceiver{
 			Receiver: config.Receiver{
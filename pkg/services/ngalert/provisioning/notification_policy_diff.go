@@ -0,0 +1,174 @@
+package provisioning
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// diffPolicyTrees returns how the new notification policy tree differs from the old one. The roots of the trees are
+// always matched. Routes below the root are matched by UID, and by their matchers within the same parent if either
+// of them has no UID. Routes of subtrees that were added or removed are reported one by one.
+func diffPolicyTrees(oldTree, newTree *definitions.Route) definitions.PolicyTreeDiff {
+	diff := definitions.PolicyTreeDiff{
+		Added:    []definitions.RouteChange{},
+		Removed:  []definitions.RouteChange{},
+		Modified: []definitions.RouteChange{},
+	}
+	switch {
+	case oldTree == nil:
+		diff.Added = appendSubtreeChanges(diff.Added, newTree, []int{})
+	case newTree == nil:
+		diff.Removed = appendSubtreeChanges(diff.Removed, oldTree, []int{})
+	default:
+		diffRoutes(&diff, oldTree, newTree, []int{}, []int{})
+	}
+	return diff
+}
+
+func diffRoutes(diff *definitions.PolicyTreeDiff, oldRoute, newRoute *definitions.Route, oldPath, newPath []int) {
+	if fields := changedRouteFields(oldRoute, newRoute); len(fields) > 0 {
+		change := routeChange(newRoute, newPath)
+		change.Fields = fields
+		diff.Modified = append(diff.Modified, change)
+	}
+	matched := make([]bool, len(oldRoute.Routes))
+	for i, newChild := range newRoute.Routes {
+		childPath := appendPath(newPath, i)
+		j := matchRoute(oldRoute.Routes, matched, newChild)
+		if j < 0 {
+			diff.Added = appendSubtreeChanges(diff.Added, newChild, childPath)
+			continue
+		}
+		matched[j] = true
+		diffRoutes(diff, oldRoute.Routes[j], newChild, appendPath(oldPath, j), childPath)
+	}
+	for j, oldChild := range oldRoute.Routes {
+		if !matched[j] {
+			diff.Removed = appendSubtreeChanges(diff.Removed, oldChild, appendPath(oldPath, j))
+		}
+	}
+}
+
+// matchRoute returns the index of the route that is not matched yet and that the given route replaces, or -1 if the
+// given route is new.
+func matchRoute(routes []*definitions.Route, matched []bool, route *definitions.Route) int {
+	if route.UID != "" {
+		for j, r := range routes {
+			if !matched[j] && r.UID == route.UID {
+				return j
+			}
+		}
+	}
+	key := strings.Join(routeMatchers(route), ",")
+	for j, r := range routes {
+		if matched[j] || (r.UID != "" && route.UID != "") {
+			continue
+		}
+		if strings.Join(routeMatchers(r), ",") == key {
+			return j
+		}
+	}
+	return -1
+}
+
+// changedRouteFields returns the names of the fields of the route that differ, ignoring its children.
+func changedRouteFields(oldRoute, newRoute *definitions.Route) []string {
+	oldFields, newFields := routeFields(oldRoute), routeFields(newRoute)
+	var changed []string
+	for name, value := range newFields {
+		if string(oldFields[name]) != string(value) {
+			changed = append(changed, name)
+		}
+	}
+	for name := range oldFields {
+		if _, ok := newFields[name]; !ok {
+			changed = append(changed, name)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+func routeFields(route *definitions.Route) map[string]json.RawMessage {
+	r := *route
+	r.UID = ""
+	r.Provenance = ""
+	r.Routes = nil
+	fields := map[string]json.RawMessage{}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return fields
+	}
+	_ = json.Unmarshal(data, &fields)
+	return fields
+}
+
+func appendSubtreeChanges(changes []definitions.RouteChange, route *definitions.Route, path []int) []definitions.RouteChange {
+	if route == nil {
+		return changes
+	}
+	changes = append(changes, routeChange(route, path))
+	for i, child := range route.Routes {
+		changes = appendSubtreeChanges(changes, child, appendPath(path, i))
+	}
+	return changes
+}
+
+func routeChange(route *definitions.Route, path []int) definitions.RouteChange {
+	return definitions.RouteChange{
+		Path:     path,
+		UID:      route.UID,
+		Matchers: routeMatchers(route),
+		Receiver: route.Receiver,
+	}
+}
+
+// routeMatchers returns the matchers of the route, including the deprecated ones, in the syntax of label matchers.
+func routeMatchers(route *definitions.Route) []string {
+	var result []string
+	for name, value := range route.Match {
+		result = append(result, (&labels.Matcher{Type: labels.MatchEqual, Name: name, Value: value}).String())
+	}
+	for name, re := range route.MatchRE {
+		original, _ := re.MarshalYAML()
+		value, _ := original.(string)
+		result = append(result, (&labels.Matcher{Type: labels.MatchRegexp, Name: name, Value: value}).String())
+	}
+	for _, m := range route.Matchers {
+		result = append(result, m.String())
+	}
+	for _, m := range route.ObjectMatchers {
+		result = append(result, m.String())
+	}
+	sort.Strings(result)
+	return result
+}
+
+// appendPath returns a new path to the child with the given index, so that paths do not share their backing arrays.
+func appendPath(path []int, idx int) []int {
+	result := make([]int, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, idx)
+}
+
+// policyTreeDiffLogArgs returns the changes of the diff as key-value pairs for the log.
+func policyTreeDiffLogArgs(diff definitions.PolicyTreeDiff) []any {
+	describe := func(changes []definitions.RouteChange) []string {
+		result := make([]string, 0, len(changes))
+		for _, c := range changes {
+			s := fmt.Sprintf("%v {%s} receiver=%s", c.Path, strings.Join(c.Matchers, ", "), c.Receiver)
+			if len(c.Fields) > 0 {
+				s += " fields=" + strings.Join(c.Fields, ",")
+			}
+			result = append(result, s)
+		}
+		return result
+	}
+	return []any{"added", describe(diff.Added), "removed", describe(diff.Removed), "modified", describe(diff.Modified)}
+}
@@ -0,0 +1,87 @@
+package provisioning
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestDiffPolicyTrees(t *testing.T) {
+	matchers := func(team string) definitions.ObjectMatchers {
+		m, err := labels.NewMatcher(labels.MatchEqual, "team", team)
+		require.NoError(t, err)
+		return definitions.ObjectMatchers{m}
+	}
+	wait := model.Duration(time.Minute)
+	oldTree := func() *definitions.Route {
+		return &definitions.Route{
+			Receiver: "default",
+			Routes: []*definitions.Route{
+				{UID: "a", Receiver: "team-a", ObjectMatchers: matchers("a")},
+				{Receiver: "team-b", ObjectMatchers: matchers("b"), Routes: []*definitions.Route{
+					{Receiver: "team-b-critical", ObjectMatchers: matchers("b-critical")},
+				}},
+			},
+		}
+	}
+
+	t.Run("same trees have no changes", func(t *testing.T) {
+		diff := diffPolicyTrees(oldTree(), oldTree())
+		require.True(t, diff.IsEmpty())
+	})
+
+	t.Run("routes are matched by UID and by matchers", func(t *testing.T) {
+		newTree := oldTree()
+		newTree.GroupWait = &wait
+		// Moving a route does not change it.
+		newTree.Routes[0], newTree.Routes[1] = newTree.Routes[1], newTree.Routes[0]
+		newTree.Routes[1].Receiver = "team-a-oncall"
+		newTree.Routes[0].Routes = nil
+		newTree.Routes = append(newTree.Routes, &definitions.Route{Receiver: "team-c", ObjectMatchers: matchers("c")})
+
+		diff := diffPolicyTrees(oldTree(), newTree)
+
+		require.Equal(t, []definitions.RouteChange{
+			{Path: []int{}, Receiver: "default", Fields: []string{"group_wait"}},
+			{Path: []int{1}, UID: "a", Matchers: []string{`team="a"`}, Receiver: "team-a-oncall", Fields: []string{"receiver"}},
+		}, diff.Modified)
+		require.Equal(t, []definitions.RouteChange{
+			{Path: []int{2}, Matchers: []string{`team="c"`}, Receiver: "team-c"},
+		}, diff.Added)
+		require.Equal(t, []definitions.RouteChange{
+			{Path: []int{1, 0}, Matchers: []string{`team="b-critical"`}, Receiver: "team-b-critical"},
+		}, diff.Removed)
+	})
+
+	t.Run("routes whose matchers changed are replaced", func(t *testing.T) {
+		newTree := oldTree()
+		newTree.Routes[1].ObjectMatchers = matchers("bb")
+
+		diff := diffPolicyTrees(oldTree(), newTree)
+
+		require.Empty(t, diff.Modified)
+		require.Len(t, diff.Added, 2)
+		require.Equal(t, []int{1}, diff.Added[0].Path)
+		require.Equal(t, []string{`team="bb"`}, diff.Added[0].Matchers)
+		require.Equal(t, []int{1, 0}, diff.Added[1].Path)
+		require.Len(t, diff.Removed, 2)
+		require.Equal(t, []string{`team="b"`}, diff.Removed[0].Matchers)
+	})
+
+	t.Run("matchers of routes with a UID can be modified", func(t *testing.T) {
+		newTree := oldTree()
+		newTree.Routes[0].ObjectMatchers = matchers("aa")
+
+		diff := diffPolicyTrees(oldTree(), newTree)
+
+		require.Empty(t, diff.Added)
+		require.Empty(t, diff.Removed)
+		require.Len(t, diff.Modified, 1)
+		require.Equal(t, []string{"object_matchers"}, diff.Modified[0].Fields)
+	})
+}
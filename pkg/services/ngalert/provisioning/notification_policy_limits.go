@@ -0,0 +1,47 @@
+package provisioning
+
+import (
+	"strconv"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// checkPolicyTreeLimits rejects trees that have more routes, deeper nesting or more matchers per route than the
+// configured limits allow, since large trees slow down applying the Alertmanager configuration. The root is at depth
+// zero and is not counted as a route. Limits of zero are not enforced.
+func (nps *NotificationPolicyService) checkPolicyTreeLimits(tree *definitions.Route) error {
+	if limit := nps.settings.PolicyTreeMaxRoutes; limit > 0 {
+		if count := countPolicies(tree); count > int64(limit) {
+			return newValidationError("routes", "the notification policy tree has %d routes, more than the limit of %d", count, limit)
+		}
+	}
+	return checkRouteLimits(tree, []int{}, nps.settings.PolicyTreeMaxDepth, nps.settings.PolicyTreeMaxMatchersPerRoute)
+}
+
+func checkRouteLimits(route *definitions.Route, path []int, maxDepth, maxMatchers int) error {
+	if route == nil {
+		return nil
+	}
+	if maxDepth > 0 && len(path) > maxDepth {
+		return newValidationError("routes", "route at path %v is nested %d levels deep, more than the limit of %d", path, len(path), maxDepth)
+	}
+	if maxMatchers > 0 {
+		if count := len(routeMatchers(route)); count > maxMatchers {
+			return newValidationError("object_matchers", "route at path %v has %d matchers, more than the limit of %d", path, count, maxMatchers)
+		}
+	}
+	for i, child := range route.Routes {
+		if err := checkRouteLimits(child, appendPath(path, i), maxDepth, maxMatchers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// recordPolicyTreeSize updates the gauge of the number of routes of the tree of the org after it was saved.
+func (nps *NotificationPolicyService) recordPolicyTreeSize(orgID int64, tree *definitions.Route) {
+	if nps.metrics == nil {
+		return
+	}
+	nps.metrics.PolicyTreeRoutes.WithLabelValues(strconv.FormatInt(orgID, 10)).Set(float64(countPolicies(tree)))
+}
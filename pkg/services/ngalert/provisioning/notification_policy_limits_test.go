@@ -0,0 +1,96 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPolicyTreeLimits(t *testing.T) {
+	ctx := context.Background()
+	route := func(children ...*definitions.Route) *definitions.Route {
+		return &definitions.Route{Receiver: "grafana-default-email", Routes: children}
+	}
+	matchers := func(n int) definitions.ObjectMatchers {
+		result := make(definitions.ObjectMatchers, 0, n)
+		for i := 0; i < n; i++ {
+			m, err := labels.NewMatcher(labels.MatchEqual, "label", string(rune('a'+i)))
+			require.NoError(t, err)
+			result = append(result, m)
+		}
+		return result
+	}
+
+	t.Run("trees within the limits are saved", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.settings.PolicyTreeMaxRoutes = 2
+		sut.settings.PolicyTreeMaxDepth = 2
+		sut.settings.PolicyTreeMaxMatchersPerRoute = 2
+		tree := route(route(route()))
+		tree.Routes[0].ObjectMatchers = matchers(2)
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, *tree, models.ProvenanceAPI)
+		require.NoError(t, err)
+	})
+
+	t.Run("trees with too many routes are rejected", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.settings.PolicyTreeMaxRoutes = 2
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, *route(route(), route(), route()), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "has 3 routes, more than the limit of 2")
+	})
+
+	t.Run("trees that are nested too deeply are rejected", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.settings.PolicyTreeMaxDepth = 2
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, *route(route(route(route()))), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "route at path [0 0 0] is nested 3 levels deep, more than the limit of 2")
+	})
+
+	t.Run("routes with too many matchers are rejected", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.settings.PolicyTreeMaxMatchersPerRoute = 2
+		tree := route(route(), route())
+		tree.Routes[1].ObjectMatchers = matchers(3)
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, *tree, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		require.ErrorContains(t, err, "route at path [1] has 3 matchers, more than the limit of 2")
+	})
+
+	t.Run("routes that exceed the limits cannot be created", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		// The default configuration has a route already.
+		sut.settings.PolicyTreeMaxRoutes = 2
+		_, err := sut.CreateRoute(ctx, 1, RouteRef{}, *route(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.CreateRoute(ctx, 1, RouteRef{}, *route(), models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("size of the saved tree is reported", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.metrics = metrics.NewProvisioningMetrics(prometheus.NewRegistry())
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, *route(route(route()), route()), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, 3.0, testutil.ToFloat64(sut.metrics.PolicyTreeRoutes.WithLabelValues("1")))
+
+		_, err = sut.ResetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, 0.0, testutil.ToFloat64(sut.metrics.PolicyTreeRoutes.WithLabelValues("1")))
+	})
+}
@@ -0,0 +1,68 @@
+package provisioning
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+const (
+	policyReferenceReceiver         = "receiver"
+	policyReferenceMuteTimeInterval = "muteTimeInterval"
+)
+
+// BrokenPolicyReferencesError is returned if a notification policy tree refers to receivers or mute timings that do
+// not exist. It lists all of them, so that they can be fixed at once. Such a tree is always rejected, because the
+// Alertmanager cannot load a configuration with broken references.
+type BrokenPolicyReferencesError struct {
+	References []definitions.PolicyReference
+}
+
+func (e *BrokenPolicyReferencesError) Error() string {
+	refs := make([]string, 0, len(e.References))
+	for _, ref := range e.References {
+		kind := "receiver"
+		if ref.Kind == policyReferenceMuteTimeInterval {
+			kind = "mute time interval"
+		}
+		refs = append(refs, fmt.Sprintf("%s '%s' of route at path %v does not exist", kind, ref.Name, ref.Path))
+	}
+	return fmt.Sprintf("%s: %s", ErrValidation.Error(), strings.Join(refs, ", "))
+}
+
+func (e *BrokenPolicyReferencesError) Unwrap() error {
+	return ErrValidation
+}
+
+// checkPolicyReferences returns a BrokenPolicyReferencesError if the tree refers to receivers or mute timings that are
+// not part of the revision.
+func checkPolicyReferences(tree *definitions.Route, revision *cfgRevision) error {
+	receivers := revision.receivers().groupNames()
+	muteTimes := make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.MuteTimeIntervals))
+	for _, mt := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+		muteTimes[mt.Name] = struct{}{}
+	}
+
+	var broken []definitions.PolicyReference
+	var check func(r *definitions.Route, path []int)
+	check = func(r *definitions.Route, path []int) {
+		if _, ok := receivers[r.Receiver]; !ok {
+			broken = append(broken, definitions.PolicyReference{Path: path, UID: r.UID, Kind: policyReferenceReceiver, Name: r.Receiver})
+		}
+		for _, name := range r.MuteTimeIntervals {
+			if _, ok := muteTimes[name]; !ok {
+				broken = append(broken, definitions.PolicyReference{Path: path, UID: r.UID, Kind: policyReferenceMuteTimeInterval, Name: name})
+			}
+		}
+		for i, child := range r.Routes {
+			check(child, append(append(make([]int, 0, len(path)+1), path...), i))
+		}
+	}
+	check(tree, []int{})
+
+	if len(broken) > 0 {
+		return &BrokenPolicyReferencesError{References: broken}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPolicyReferences(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all broken references of the tree are reported", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		tree := definitions.Route{
+			Receiver: "grafana-default-email",
+			Routes: []*definitions.Route{
+				{Receiver: "grafana-default-email"},
+				{UID: "broken", Receiver: "unknown", MuteTimeIntervals: []string{"weekends"}},
+			},
+		}
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, tree, models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+		var broken *BrokenPolicyReferencesError
+		require.True(t, errors.As(err, &broken))
+		require.Equal(t, []definitions.PolicyReference{
+			{Path: []int{1}, UID: "broken", Kind: "receiver", Name: "unknown"},
+			{Path: []int{1}, UID: "broken", Kind: "muteTimeInterval", Name: "weekends"},
+		}, broken.References)
+	})
+
+	t.Run("tree without broken references is accepted", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		tree := definitions.Route{
+			Receiver: "grafana-default-email",
+			Routes:   []*definitions.Route{{Receiver: "grafana-default-email"}},
+		}
+
+		_, err := sut.UpdatePolicyTree(ctx, 1, tree, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+	})
+}
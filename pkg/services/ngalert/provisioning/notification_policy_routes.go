@@ -0,0 +1,362 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+// RouteRef addresses a route of the notification policy tree. The route is looked up by its UID if one is given, and
+// by its path of child indexes from the root otherwise. An empty reference addresses the root.
+type RouteRef struct {
+	UID  string
+	Path []int
+}
+
+func (r RouteRef) String() string {
+	if r.UID != "" {
+		return fmt.Sprintf("with uid '%s'", r.UID)
+	}
+	return fmt.Sprintf("at path %v", r.Path)
+}
+
+// CreateRoute adds the route as the last child of the parent route. The route and its children get a UID if they
+// have none. The provenance is recorded for the new subtree only, so that teams can provision their own subtrees
+// independently of each other and of the rest of the tree.
+func (nps *NotificationPolicyService) CreateRoute(ctx context.Context, orgID int64, parent RouteRef, route definitions.Route,
+	p models.Provenance) (result definitions.Route, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = nps.createRoute(ctx, orgID, parent, route, p)
+		return err
+	})
+	return result, err
+}
+
+func (nps *NotificationPolicyService) createRoute(ctx context.Context, orgID int64, parent RouteRef, route definitions.Route,
+	p models.Provenance) (_ definitions.Route, err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "CreateRoute", orgID,
+		attribute.String("parent_uid", parent.UID))
+	defer func() { done(err) }()
+
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	tree := revision.cfg.AlertmanagerConfig.Config.Route
+	oldTree := copyRoute(tree)
+	parentRoute, _, _, err := findRoute(tree, parent)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+
+	route.Provenance = ""
+	parentRoute.Routes = append(parentRoute.Routes, &route)
+	assignRouteUIDs(tree)
+	if err := tree.Validate(); err != nil {
+		return definitions.Route{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err := validatePolicyTree(tree, revision); err != nil {
+		return definitions.Route{}, err
+	}
+	if err := nps.checkPolicyTreeLimits(tree); err != nil {
+		return definitions.Route{}, err
+	}
+
+	resource := provisionedResource{resourceType: route.ResourceType(), id: route.UID}
+	err = nps.saveRouteChange(ctx, orgID, revision, oldTree, func(ctx context.Context) error {
+		if err := nps.provenanceStore.SetProvenance(ctx, resource, orgID, p); err != nil {
+			return err
+		}
+		return recordAudit(ctx, nps.provenanceStore, orgID, models.ProvisioningAuditActionCreate, resource, p, nil, route)
+	})
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	route.Provenance = definitions.Provenance(p)
+	return route, nil
+}
+
+// UpdateRoute replaces the addressed route and its children with the given route, which keeps the UID of the replaced
+// one. The root can only be changed by replacing the whole tree. A subtree that was provisioned with another
+// provenance can only be changed if that provenance allows it.
+func (nps *NotificationPolicyService) UpdateRoute(ctx context.Context, orgID int64, ref RouteRef, route definitions.Route,
+	p models.Provenance) (result definitions.Route, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = nps.updateRoute(ctx, orgID, ref, route, p)
+		return err
+	})
+	return result, err
+}
+
+func (nps *NotificationPolicyService) updateRoute(ctx context.Context, orgID int64, ref RouteRef, route definitions.Route,
+	p models.Provenance) (_ definitions.Route, err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "UpdateRoute", orgID,
+		attribute.String("route_uid", ref.UID))
+	defer func() { done(err) }()
+
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	tree := revision.cfg.AlertmanagerConfig.Config.Route
+	oldTree := copyRoute(tree)
+	existing, parent, idx, err := findRoute(tree, ref)
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	if parent == nil {
+		return definitions.Route{}, fmt.Errorf("%w: the root route can only be changed by replacing the whole tree", ErrValidation)
+	}
+	if err := nps.checkSubtreeProvenance(ctx, orgID, existing, p); err != nil {
+		return definitions.Route{}, err
+	}
+
+	old := copyRoute(existing)
+	route.UID = existing.UID
+	route.Provenance = ""
+	parent.Routes[idx] = &route
+	assignRouteUIDs(tree)
+	if err := tree.Validate(); err != nil {
+		return definitions.Route{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if err := validatePolicyTree(tree, revision); err != nil {
+		return definitions.Route{}, err
+	}
+	if err := nps.checkPolicyTreeLimits(tree); err != nil {
+		return definitions.Route{}, err
+	}
+
+	resource := provisionedResource{resourceType: route.ResourceType(), id: route.UID}
+	err = nps.saveRouteChange(ctx, orgID, revision, oldTree, func(ctx context.Context) error {
+		if err := nps.provenanceStore.SetProvenance(ctx, resource, orgID, p); err != nil {
+			return err
+		}
+		return recordAudit(ctx, nps.provenanceStore, orgID, models.ProvisioningAuditActionUpdate, resource, p, old, route)
+	})
+	if err != nil {
+		return definitions.Route{}, err
+	}
+	route.Provenance = definitions.Provenance(p)
+	return route, nil
+}
+
+// DeleteRoute removes the addressed route and its children from the tree, together with their provenance. The root
+// cannot be deleted, see ResetPolicyTree.
+func (nps *NotificationPolicyService) DeleteRoute(ctx context.Context, orgID int64, ref RouteRef, p models.Provenance) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return nps.deleteRoute(ctx, orgID, ref, p)
+	})
+}
+
+func (nps *NotificationPolicyService) deleteRoute(ctx context.Context, orgID int64, ref RouteRef, p models.Provenance) (err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "DeleteRoute", orgID,
+		attribute.String("route_uid", ref.UID))
+	defer func() { done(err) }()
+
+	revision, err := getLastConfiguration(ctx, orgID, nps.amStore)
+	if err != nil {
+		return err
+	}
+	tree := revision.cfg.AlertmanagerConfig.Config.Route
+	oldTree := copyRoute(tree)
+	existing, parent, idx, err := findRoute(tree, ref)
+	if err != nil {
+		return err
+	}
+	if parent == nil {
+		return fmt.Errorf("%w: the root route cannot be deleted, reset the tree instead", ErrValidation)
+	}
+	if err := nps.checkSubtreeProvenance(ctx, orgID, existing, p); err != nil {
+		return err
+	}
+
+	parent.Routes = append(parent.Routes[:idx], parent.Routes[idx+1:]...)
+	assignRouteUIDs(tree)
+
+	resource := provisionedResource{resourceType: existing.ResourceType(), id: existing.UID}
+	return nps.saveRouteChange(ctx, orgID, revision, oldTree, func(ctx context.Context) error {
+		return recordAudit(ctx, nps.provenanceStore, orgID, models.ProvisioningAuditActionDelete, resource, p, existing, nil)
+	})
+}
+
+// saveRouteChange persists the changed tree of the revision and removes the provenance of the routes that are no
+// longer part of it, before running the given function in the same transaction.
+func (nps *NotificationPolicyService) saveRouteChange(ctx context.Context, orgID int64, revision *cfgRevision, oldTree *definitions.Route,
+	fn func(ctx context.Context) error) error {
+	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	if err != nil {
+		return err
+	}
+	cmd := models.SaveAlertmanagerConfigurationCmd{
+		AlertmanagerConfiguration: string(serialized),
+		ConfigurationVersion:      revision.version,
+		FetchedConfigurationHash:  revision.concurrencyToken,
+		Default:                   false,
+		OrgID:                     orgID,
+	}
+	err = nps.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := PersistConfig(ctx, nps.amStore, &cmd); err != nil {
+			return err
+		}
+		if err := deleteRemovedRouteProvenances(ctx, nps.provenanceStore, orgID, oldTree, revision.cfg.AlertmanagerConfig.Config.Route); err != nil {
+			return err
+		}
+		if err := nps.checkPolicyQuota(ctx, orgID, oldTree, revision.cfg.AlertmanagerConfig.Config.Route); err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
+	if err != nil {
+		return err
+	}
+	nps.recordPolicyTreeSize(orgID, revision.cfg.AlertmanagerConfig.Config.Route)
+	return nil
+}
+
+// checkSubtreeProvenance rejects changes to a subtree that was provisioned with a provenance that does not allow them.
+func (nps *NotificationPolicyService) checkSubtreeProvenance(ctx context.Context, orgID int64, route *definitions.Route, p models.Provenance) error {
+	if route.UID == "" {
+		return nil
+	}
+	stored, err := nps.provenanceStore.GetProvenance(ctx, provisionedResource{resourceType: route.ResourceType(), id: route.UID}, orgID)
+	if err != nil {
+		return err
+	}
+	if stored != p && stored != models.ProvenanceNone && !(stored == models.ProvenanceAPI && p == models.ProvenanceNone) {
+		return newValidationError("", "route with uid '%s' is provisioned with provenance '%s' and cannot be changed with provenance '%s'",
+			route.UID, stored, p).withResource(route.ResourceType(), route.UID)
+	}
+	return nil
+}
+
+// validatePolicyTree checks that the tree only refers to receivers and mute timings of the revision, and that the UIDs
+// of its routes are unique.
+func validatePolicyTree(tree *definitions.Route, revision *cfgRevision) error {
+	if err := checkPolicyReferences(tree, revision); err != nil {
+		return err
+	}
+
+	uids := map[string]struct{}{}
+	var duplicate string
+	walkRoutes(tree, func(r *definitions.Route) {
+		if r.UID == "" {
+			return
+		}
+		if _, ok := uids[r.UID]; ok {
+			duplicate = r.UID
+		}
+		uids[r.UID] = struct{}{}
+	})
+	if duplicate != "" {
+		return newValidationError("", "route uid '%s' is used more than once", duplicate).withResource((&definitions.Route{}).ResourceType(), duplicate)
+	}
+	return nil
+}
+
+// findRoute returns the addressed route of the tree, its parent and its index in the children of the parent. The
+// parent is nil for the root.
+func findRoute(tree *definitions.Route, ref RouteRef) (route, parent *definitions.Route, idx int, err error) {
+	if ref.UID == "" {
+		route = tree
+		for i, childIdx := range ref.Path {
+			if childIdx < 0 || childIdx >= len(route.Routes) {
+				return nil, nil, 0, fmt.Errorf("%w: route at path %v does not exist", ErrNotFound, ref.Path[:i+1])
+			}
+			parent, idx, route = route, childIdx, route.Routes[childIdx]
+		}
+		return route, parent, idx, nil
+	}
+	if tree.UID == ref.UID {
+		return tree, nil, 0, nil
+	}
+	var find func(r *definitions.Route) bool
+	find = func(r *definitions.Route) bool {
+		for i, child := range r.Routes {
+			if child.UID == ref.UID {
+				route, parent, idx = child, r, i
+				return true
+			}
+			if find(child) {
+				return true
+			}
+		}
+		return false
+	}
+	if !find(tree) {
+		return nil, nil, 0, newNotFoundError(tree.ResourceType(), ref.UID, "route %s does not exist", ref)
+	}
+	return route, parent, idx, nil
+}
+
+// assignRouteUIDs gives every route below the root that has no UID a new one, so that the route can be addressed by
+// UID once the tree was changed through the route methods.
+func assignRouteUIDs(tree *definitions.Route) {
+	for _, child := range tree.Routes {
+		walkRoutes(child, func(r *definitions.Route) {
+			if r.UID == "" {
+				r.UID = util.GenerateShortUID()
+			}
+		})
+	}
+}
+
+// setSubtreeProvenances sets the provenance of the routes with a UID from the provenance records of their subtrees.
+func setSubtreeProvenances(route *definitions.Route, provenances map[string]models.Provenance) {
+	walkRoutes(route, func(r *definitions.Route) {
+		if r.UID != "" {
+			r.Provenance = definitions.Provenance(provenanceOrNone(provenances, r.UID))
+		}
+	})
+}
+
+// deleteRemovedRouteProvenances deletes the provenance of the routes of the old tree that are not part of the new one.
+func deleteRemovedRouteProvenances(ctx context.Context, store ProvisioningStore, orgID int64, oldTree, newTree *definitions.Route) error {
+	if oldTree == nil {
+		return nil
+	}
+	current := map[string]struct{}{}
+	walkRoutes(newTree, func(r *definitions.Route) {
+		current[r.UID] = struct{}{}
+	})
+	var removed []string
+	walkRoutes(oldTree, func(r *definitions.Route) {
+		if _, ok := current[r.UID]; !ok && r.UID != "" {
+			removed = append(removed, r.UID)
+		}
+	})
+	for _, uid := range removed {
+		if err := store.DeleteProvenance(ctx, provisionedResource{resourceType: (&definitions.Route{}).ResourceType(), id: uid}, orgID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// walkRoutes calls fn for the route and all of its descendants.
+func walkRoutes(route *definitions.Route, fn func(r *definitions.Route)) {
+	if route == nil {
+		return
+	}
+	fn(route)
+	for _, child := range route.Routes {
+		walkRoutes(child, fn)
+	}
+}
+
+// copyRoute returns a deep copy of the routes of the tree, so that it is not affected by changes to the tree.
+func copyRoute(route *definitions.Route) *definitions.Route {
+	if route == nil {
+		return nil
+	}
+	c := *route
+	c.Routes = make([]*definitions.Route, 0, len(route.Routes))
+	for _, child := range route.Routes {
+		c.Routes = append(c.Routes, copyRoute(child))
+	}
+	return &c
+}
@@ -0,0 +1,109 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestNotificationPolicyRoutes(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("created route gets a UID and its own provenance", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		created, err := sut.CreateRoute(ctx, 1, RouteRef{}, definitions.Route{
+			Receiver: "grafana-default-email",
+			Routes:   []*definitions.Route{{Receiver: "grafana-default-email"}},
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NotEmpty(t, created.UID)
+		require.NotEmpty(t, created.Routes[0].UID)
+		tree, err := sut.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceNone, models.Provenance(tree.Provenance))
+		// The default configuration has a route already.
+		require.Len(t, tree.Routes, 2)
+		require.Equal(t, created.UID, tree.Routes[1].UID)
+		require.Equal(t, models.ProvenanceAPI, models.Provenance(tree.Routes[1].Provenance))
+	})
+
+	t.Run("route is updated by UID or path and keeps its UID", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		created, err := sut.CreateRoute(ctx, 1, RouteRef{}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		updated, err := sut.UpdateRoute(ctx, 1, RouteRef{UID: created.UID}, definitions.Route{
+			Receiver: "grafana-default-email",
+			Continue: true,
+		}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, created.UID, updated.UID)
+
+		_, err = sut.UpdateRoute(ctx, 1, RouteRef{Path: []int{1}}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+		tree, err := sut.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, tree.Routes, 2)
+		require.Equal(t, created.UID, tree.Routes[1].UID)
+		require.False(t, tree.Routes[1].Continue)
+	})
+
+	t.Run("subtree with another provenance cannot be changed", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		created, err := sut.CreateRoute(ctx, 1, RouteRef{}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateRoute(ctx, 1, RouteRef{UID: created.UID}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		err = sut.DeleteRoute(ctx, 1, RouteRef{UID: created.UID}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("deleted route and its provenance are removed", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		created, err := sut.CreateRoute(ctx, 1, RouteRef{}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		err = sut.DeleteRoute(ctx, 1, RouteRef{UID: created.UID}, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		tree, err := sut.GetPolicyTree(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, tree.Routes, 1)
+		require.NotEqual(t, created.UID, tree.Routes[0].UID)
+		provenances, err := sut.provenanceStore.GetProvenances(ctx, 1, (&definitions.Route{}).ResourceType())
+		require.NoError(t, err)
+		require.NotContains(t, provenances, created.UID)
+	})
+
+	t.Run("root cannot be changed or deleted", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		_, err := sut.UpdateRoute(ctx, 1, RouteRef{}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		err = sut.DeleteRoute(ctx, 1, RouteRef{}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("unknown route returns not found", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		_, err := sut.CreateRoute(ctx, 1, RouteRef{UID: "unknown"}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+		err = sut.DeleteRoute(ctx, 1, RouteRef{Path: []int{3}}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("route with unknown receiver is invalid", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		_, err := sut.CreateRoute(ctx, 1, RouteRef{}, definitions.Route{Receiver: "unknown"}, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
@@ -0,0 +1,85 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/prometheus/alertmanager/dispatch"
+	"github.com/prometheus/common/model"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+// TestRoutePolicy runs each label set through the stored notification policy tree the way the Alertmanager routes
+// alerts, and returns the routes that an alert with these labels would be handled by, together with their receivers,
+// group-by labels and mute timings. Nothing is sent.
+func (nps *NotificationPolicyService) TestRoutePolicy(ctx context.Context, orgID int64, labelSets []model.LabelSet) (_ []definitions.RoutePolicyTestResult, err error) {
+	ctx, done := startOperation(ctx, nps.tracer, nps.metrics, "route", "TestRoutePolicy", orgID,
+		attribute.Int("label_sets", len(labelSets)))
+	defer func() { done(err) }()
+
+	for i, lset := range labelSets {
+		if err := lset.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: label set %d: %s", ErrValidation, i, err.Error())
+		}
+	}
+
+	tree, err := nps.GetPolicyTree(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	root := dispatch.NewRoute(tree.AsAMRoute(), nil)
+
+	results := make([]definitions.RoutePolicyTestResult, 0, len(labelSets))
+	for _, lset := range labelSets {
+		results = append(results, definitions.RoutePolicyTestResult{
+			Labels: lset,
+			Routes: matchPolicyRoutes(root, &tree, []int{}, lset),
+		})
+	}
+	return results, nil
+}
+
+// matchPolicyRoutes returns the routes of the tree that match the label set. It follows dispatch.Route.Match, and walks
+// the Grafana tree alongside, so that the matched routes can be reported with their UID and path.
+func matchPolicyRoutes(route *dispatch.Route, def *definitions.Route, path []int, lset model.LabelSet) []definitions.MatchedRoute {
+	if !route.Matchers.Matches(lset) {
+		return nil
+	}
+	var all []definitions.MatchedRoute
+	for i, child := range route.Routes {
+		childPath := append(append(make([]int, 0, len(path)+1), path...), i)
+		matches := matchPolicyRoutes(child, def.Routes[i], childPath, lset)
+		all = append(all, matches...)
+		if matches != nil && !child.Continue {
+			break
+		}
+	}
+	if len(all) == 0 {
+		all = append(all, matchedRoute(route, def, path))
+	}
+	return all
+}
+
+func matchedRoute(route *dispatch.Route, def *definitions.Route, path []int) definitions.MatchedRoute {
+	// Alerts are grouped by all of their labels if the route groups by "...", regardless of the inherited labels.
+	groupBy := []string{"..."}
+	if !route.RouteOpts.GroupByAll {
+		groupBy = make([]string, 0, len(route.RouteOpts.GroupBy))
+		for label := range route.RouteOpts.GroupBy {
+			groupBy = append(groupBy, string(label))
+		}
+		sort.Strings(groupBy)
+	}
+	muteTimings := make([]string, 0, len(route.RouteOpts.MuteTimeIntervals))
+	muteTimings = append(muteTimings, route.RouteOpts.MuteTimeIntervals...)
+	return definitions.MatchedRoute{
+		UID:               def.UID,
+		Path:              path,
+		Receiver:          route.RouteOpts.Receiver,
+		GroupBy:           groupBy,
+		MuteTimeIntervals: muteTimings,
+	}
+}
@@ -0,0 +1,60 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestTestRoutePolicy(t *testing.T) {
+	ctx := context.Background()
+	teamA, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+	critical, err := labels.NewMatcher(labels.MatchEqual, "severity", "critical")
+	require.NoError(t, err)
+	cfg := createTestAlertingConfig()
+	cfg.AlertmanagerConfig.MuteTimeIntervals = []config.MuteTimeInterval{{Name: "weekends"}}
+	cfg.AlertmanagerConfig.Route = &definitions.Route{
+		Receiver:   "grafana-default-email",
+		GroupByStr: []string{"alertname"},
+		Routes: []*definitions.Route{
+			{UID: "team-a", Receiver: "a new receiver", ObjectMatchers: definitions.ObjectMatchers{teamA}, Continue: true, MuteTimeIntervals: []string{"weekends"}},
+			{Receiver: "existing", ObjectMatchers: definitions.ObjectMatchers{critical}, GroupByStr: []string{"..."}},
+		},
+	}
+	serialized, err := serializeAlertmanagerConfig(*cfg)
+	require.NoError(t, err)
+
+	t.Run("label sets are routed through the tree", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.amStore = newFakeAMConfigStore(string(serialized))
+
+		results, err := sut.TestRoutePolicy(ctx, 1, []model.LabelSet{
+			{"team": "a", "severity": "critical"},
+			{"team": "b"},
+		})
+		require.NoError(t, err)
+
+		require.Len(t, results, 2)
+		require.Equal(t, []definitions.MatchedRoute{
+			{UID: "team-a", Path: []int{0}, Receiver: "a new receiver", GroupBy: []string{"alertname"}, MuteTimeIntervals: []string{"weekends"}},
+			{Path: []int{1}, Receiver: "existing", GroupBy: []string{"..."}, MuteTimeIntervals: []string{}},
+		}, results[0].Routes)
+		require.Equal(t, []definitions.MatchedRoute{
+			{Path: []int{}, Receiver: "grafana-default-email", GroupBy: []string{"alertname"}, MuteTimeIntervals: []string{}},
+		}, results[1].Routes)
+	})
+
+	t.Run("invalid label set is rejected", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+
+		_, err := sut.TestRoutePolicy(ctx, 1, []model.LabelSet{{"in-valid": "value"}})
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
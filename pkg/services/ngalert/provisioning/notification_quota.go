@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+// NotificationQuota is the quota of a kind of notification resources in the Alertmanager configuration of an org.
+type NotificationQuota struct {
+	TargetSrv quota.TargetSrv
+	Target    quota.Target
+	name      string
+	count     func(cfg *definitions.PostableUserConfig) int64
+}
+
+var (
+	ContactPointQuota = NotificationQuota{
+		TargetSrv: models.QuotaTargetSrvContactPoint,
+		Target:    models.QuotaTargetContactPoint,
+		name:      "contact points",
+		count:     countContactPoints,
+	}
+	TemplateQuota = NotificationQuota{
+		TargetSrv: models.QuotaTargetSrvTemplate,
+		Target:    models.QuotaTargetTemplate,
+		name:      "templates",
+		count: func(cfg *definitions.PostableUserConfig) int64 {
+			return int64(len(cfg.TemplateFiles))
+		},
+	}
+	MuteTimingQuota = NotificationQuota{
+		TargetSrv: models.QuotaTargetSrvMuteTiming,
+		Target:    models.QuotaTargetMuteTiming,
+		name:      "mute timings",
+		count: func(cfg *definitions.PostableUserConfig) int64 {
+			return int64(len(cfg.AlertmanagerConfig.MuteTimeIntervals))
+		},
+	}
+	NotificationPolicyQuota = NotificationQuota{
+		TargetSrv: models.QuotaTargetSrvNotificationPolicy,
+		Target:    models.QuotaTargetNotificationPolicy,
+		name:      "notification policies",
+		count: func(cfg *definitions.PostableUserConfig) int64 {
+			return countPolicies(cfg.AlertmanagerConfig.Config.Route)
+		},
+	}
+)
+
+// NotificationQuotas are the quotas of all kinds of notification resources.
+var NotificationQuotas = []NotificationQuota{ContactPointQuota, TemplateQuota, MuteTimingQuota, NotificationPolicyQuota}
+
+// Reporter returns the function that reports the usage of the quota, which is the number of resources in the latest
+// Alertmanager configuration of the org. Only the org scope is reported, as the global usage would require reading
+// the configurations of all orgs.
+func (q NotificationQuota) Reporter(amStore AMConfigStore) quota.UsageReporterFunc {
+	return func(ctx context.Context, scopeParams *quota.ScopeParameters) (*quota.Map, error) {
+		u := &quota.Map{}
+		if scopeParams == nil || scopeParams.OrgID == 0 {
+			return u, nil
+		}
+		tag, err := quota.NewTag(q.TargetSrv, q.Target, quota.OrgScope)
+		if err != nil {
+			return u, err
+		}
+		revision, err := getLastConfiguration(ctx, scopeParams.OrgID, amStore)
+		if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+			u.Set(tag, 0)
+			return u, nil
+		}
+		if err != nil {
+			return u, err
+		}
+		u.Set(tag, q.count(revision.cfg))
+		return u, nil
+	}
+}
+
+// checkTransactionCtx checks whether the current transaction (as identified by the ctx) breaches the quota in the org.
+// Services without a quota checker are not limited.
+func (q NotificationQuota) checkTransactionCtx(ctx context.Context, quotas QuotaChecker, orgID int64) error {
+	if quotas == nil {
+		return nil
+	}
+	limitReached, err := quotas.CheckQuotaReached(ctx, q.TargetSrv, &quota.ScopeParameters{OrgID: orgID})
+	if err != nil {
+		return fmt.Errorf("failed to check the quota of %s: %w", q.name, err)
+	}
+	if limitReached {
+		return fmt.Errorf("%w: %s", ErrQuotaReached, q.name)
+	}
+	return nil
+}
+
+func countContactPoints(cfg *definitions.PostableUserConfig) int64 {
+	var count int64
+	for _, r := range cfg.AlertmanagerConfig.Receivers {
+		count += int64(len(r.PostableGrafanaReceivers.GrafanaManagedReceivers))
+	}
+	return count
+}
+
+// countPolicies counts the routes of the tree below the root, which is the default policy that always exists.
+func countPolicies(route *definitions.Route) int64 {
+	if route == nil {
+		return 0
+	}
+	var count int64
+	for _, r := range route.Routes {
+		count += 1 + countPolicies(r)
+	}
+	return count
+}
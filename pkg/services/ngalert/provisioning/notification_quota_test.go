@@ -0,0 +1,122 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/quota"
+)
+
+func TestNotificationQuotaReporter(t *testing.T) {
+	cfg := createTestConfigWithReceivers()
+	cfg.TemplateFiles = map[string]string{"a": "template"}
+	raw, err := json.Marshal(cfg)
+	require.NoError(t, err)
+	amStore := newFakeAMConfigStore(string(raw))
+
+	usage := func(t *testing.T, q NotificationQuota, scopeParams *quota.ScopeParameters) (int64, bool) {
+		t.Helper()
+		u, err := q.Reporter(amStore)(context.Background(), scopeParams)
+		require.NoError(t, err)
+		tag, err := quota.NewTag(q.TargetSrv, q.Target, quota.OrgScope)
+		require.NoError(t, err)
+		return u.Get(tag)
+	}
+
+	t.Run("counts the resources in the configuration of the org", func(t *testing.T) {
+		expected := map[quota.TargetSrv]int64{
+			models.QuotaTargetSrvContactPoint:       4,
+			models.QuotaTargetSrvTemplate:           1,
+			models.QuotaTargetSrvMuteTiming:         0,
+			models.QuotaTargetSrvNotificationPolicy: 1,
+		}
+		for _, q := range NotificationQuotas {
+			u, ok := usage(t, q, &quota.ScopeParameters{OrgID: 1})
+			require.True(t, ok, q.TargetSrv)
+			require.Equal(t, expected[q.TargetSrv], u, q.TargetSrv)
+		}
+	})
+
+	t.Run("reports nothing without an org", func(t *testing.T) {
+		_, ok := usage(t, ContactPointQuota, nil)
+		require.False(t, ok)
+	})
+}
+
+func TestNotificationQuotaChecks(t *testing.T) {
+	t.Run("creating a mute timing over quota fails", func(t *testing.T) {
+		sut := createMuteTimingSvcSut()
+		sut.quotas = exceededQuotaChecker(t)
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithMuteTimings,
+			})
+		sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+
+		_, err := sut.CreateMuteTiming(context.Background(), createMuteTiming(), 1)
+
+		require.ErrorIs(t, err, ErrQuotaReached)
+		require.ErrorIs(t, err, models.ErrQuotaReached)
+	})
+
+	t.Run("creating a template over quota fails", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		sut.quotas = exceededQuotaChecker(t)
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithTemplates,
+			})
+		sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+
+		_, err := sut.SetTemplate(context.Background(), 1, createNotificationTemplate())
+
+		require.ErrorIs(t, err, ErrQuotaReached)
+	})
+
+	t.Run("updating a template over quota succeeds", func(t *testing.T) {
+		sut := createTemplateServiceSut()
+		sut.quotas = NewMockQuotaChecker(t)
+		sut.config.(*MockAMConfigStore).EXPECT().
+			GetsConfig(models.AlertConfiguration{
+				AlertmanagerConfiguration: configWithTemplates,
+			})
+		sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
+		sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+		tmpl := createNotificationTemplate()
+		tmpl.Name = "a"
+
+		_, err := sut.SetTemplate(context.Background(), 1, tmpl)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("adding a policy over quota fails", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.quotas = exceededQuotaChecker(t)
+
+		_, err := sut.CreateRoute(context.Background(), 1, RouteRef{}, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrQuotaReached)
+	})
+
+	t.Run("changing policies without adding any over quota succeeds", func(t *testing.T) {
+		sut := createNotificationPolicyServiceSut()
+		sut.quotas = NewMockQuotaChecker(t)
+
+		_, err := sut.UpdatePolicyTree(context.Background(), 1, definitions.Route{Receiver: "grafana-default-email"}, models.ProvenanceAPI)
+
+		require.NoError(t, err)
+	})
+}
+
+func exceededQuotaChecker(t *testing.T) *MockQuotaChecker {
+	quotas := NewMockQuotaChecker(t)
+	quotas.EXPECT().CheckQuotaReached(mock.Anything, mock.Anything, &quota.ScopeParameters{OrgID: 1}).Return(true, nil)
+	return quotas
+}
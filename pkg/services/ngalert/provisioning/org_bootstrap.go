@@ -0,0 +1,312 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets"
+)
+
+const (
+	orgBootstrapNamespace  = "ngalert.provisioning.org_bootstrap"
+	orgBootstrapBundleKey  = "org_bootstrap_bundle"
+	orgBootstrapPendingKey = "org_bootstrap_pending"
+)
+
+// maxOrgBootstrapAttempts is how often applying the bootstrap bundle to an organization is tried before it is given up.
+const maxOrgBootstrapAttempts = 5
+
+// orgBootstrapBundle is the bootstrap bundle as it is stored. The bundle is encrypted, because contact points can
+// contain secrets.
+type orgBootstrapBundle struct {
+	UserID     int64                          `json:"userId"`
+	Provenance models.Provenance              `json:"provenance"`
+	Bundle     []byte                         `json:"bundle"`
+	Summary    definitions.OrgBootstrapBundle `json:"summary"`
+}
+
+// orgBootstrapPending marks an organization that was created while a bootstrap bundle was set, and that the bundle
+// was not applied to yet.
+type orgBootstrapPending struct {
+	CreatedAt time.Time `json:"createdAt"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// OrgBootstrapService applies a provisioning bundle to every organization when it is created, so that all
+// organizations of the instance start with the same default contact point, notification policy tree, mute timings and
+// templates instead of the default configuration. The bundle is kept in the key-value store for the whole instance.
+// Organizations are marked when they are created, and the bundle is applied to them once their Alertmanager created
+// their configuration. Rule groups cannot be bootstrapped, because a new organization has no folders.
+type OrgBootstrapService struct {
+	bundles           provisioningBundleApplier
+	freeze            *ProvisioningFreezeService
+	kv                kvstore.KVStore
+	encryptionService secrets.Service
+	xact              TransactionManager
+	log               log.Logger
+	tracer            tracing.Tracer
+	metrics           *metrics.Provisioning
+	// mtx serializes the bootstrapping of organizations by this instance.
+	mtx sync.Mutex
+}
+
+func NewOrgBootstrapService(bundles provisioningBundleApplier, freeze *ProvisioningFreezeService, kv kvstore.KVStore, encryptionService secrets.Service,
+	xact TransactionManager, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *OrgBootstrapService {
+	return &OrgBootstrapService{
+		bundles:           bundles,
+		freeze:            freeze,
+		kv:                kv,
+		encryptionService: encryptionService,
+		xact:              xact,
+		log:               log,
+		tracer:            tracer,
+		metrics:           m,
+	}
+}
+
+// GetOrgBootstrapBundle returns the summary of the bootstrap bundle.
+func (s *OrgBootstrapService) GetOrgBootstrapBundle(ctx context.Context) (_ definitions.OrgBootstrapBundle, err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "GetOrgBootstrapBundle", 0)
+	defer func() { done(err) }()
+
+	stored, ok, err := s.getBundle(ctx)
+	if err != nil {
+		return definitions.OrgBootstrapBundle{}, err
+	}
+	if !ok {
+		return definitions.OrgBootstrapBundle{}, fmt.Errorf("%w: no bootstrap bundle is set", ErrNotFound)
+	}
+	return stored.Summary, nil
+}
+
+// SetOrgBootstrapBundle sets the bundle that is applied to every organization created from now on with the
+// permissions of the user and the given provenance, replacing the previous one. The organizations that exist already
+// are not changed.
+func (s *OrgBootstrapService) SetOrgBootstrapBundle(ctx context.Context, bundle ProvisioningBundle, userID int64,
+	provenance models.Provenance) (_ definitions.OrgBootstrapBundle, err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "SetOrgBootstrapBundle", 0)
+	defer func() { done(err) }()
+
+	if len(bundle.RuleGroups) > 0 {
+		return definitions.OrgBootstrapBundle{}, newValidationError("ruleGroups", "rule groups cannot be bootstrapped, new organizations have no folders")
+	}
+	if len(bundle.ContactPoints) == 0 && bundle.Policies == nil && len(bundle.MuteTimings) == 0 && len(bundle.Templates) == 0 {
+		return definitions.OrgBootstrapBundle{}, newValidationError("bundle", "the bundle has no resources")
+	}
+	data, err := json.Marshal(bundle)
+	if err != nil {
+		return definitions.OrgBootstrapBundle{}, err
+	}
+	encrypted, err := s.encryptionService.Encrypt(ctx, data, secrets.WithoutScope())
+	if err != nil {
+		return definitions.OrgBootstrapBundle{}, fmt.Errorf("failed to encrypt the bundle: %w", err)
+	}
+	stored := orgBootstrapBundle{
+		UserID:     userID,
+		Provenance: provenance,
+		Bundle:     encrypted,
+		Summary: definitions.OrgBootstrapBundle{
+			UpdatedAt:     time.Now(),
+			Provenance:    definitions.Provenance(provenance),
+			ContactPoints: len(bundle.ContactPoints),
+			MuteTimings:   len(bundle.MuteTimings),
+			Templates:     len(bundle.Templates),
+			Policies:      bundle.Policies != nil,
+		},
+	}
+	value, err := json.Marshal(stored)
+	if err != nil {
+		return definitions.OrgBootstrapBundle{}, err
+	}
+	if err := s.kv.Set(ctx, 0, orgBootstrapNamespace, orgBootstrapBundleKey, string(value)); err != nil {
+		return definitions.OrgBootstrapBundle{}, err
+	}
+	s.log.FromContext(ctx).Info("Set organization bootstrap bundle", "contactPoints", len(bundle.ContactPoints),
+		"muteTimings", len(bundle.MuteTimings), "templates", len(bundle.Templates), "policies", bundle.Policies != nil)
+	return stored.Summary, nil
+}
+
+// DeleteOrgBootstrapBundle deletes the bootstrap bundle. Organizations that were created before, but that the bundle
+// was not applied to yet, keep the default configuration.
+func (s *OrgBootstrapService) DeleteOrgBootstrapBundle(ctx context.Context) (err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "DeleteOrgBootstrapBundle", 0)
+	defer func() { done(err) }()
+
+	_, ok, err := s.getBundle(ctx)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: no bootstrap bundle is set", ErrNotFound)
+	}
+	return s.kv.Del(ctx, 0, orgBootstrapNamespace, orgBootstrapBundleKey)
+}
+
+// HandleOrgCreated marks a new organization to be bootstrapped if a bootstrap bundle is set. It is an event listener
+// of the bus.
+func (s *OrgBootstrapService) HandleOrgCreated(ctx context.Context, e *events.OrgCreated) error {
+	_, ok, err := s.getBundle(ctx)
+	if err != nil || !ok {
+		return err
+	}
+	value, err := json.Marshal(orgBootstrapPending{CreatedAt: e.Timestamp})
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, e.Id, orgBootstrapNamespace, orgBootstrapPendingKey, string(value))
+}
+
+// BootstrapOrgs applies the bootstrap bundle to the new organizations whose Alertmanager created their configuration.
+// Organizations whose provisioning is frozen are bootstrapped when the freeze is lifted. If applying the bundle to an
+// organization fails, it is tried again on the next run, at most maxOrgBootstrapAttempts times.
+func (s *OrgBootstrapService) BootstrapOrgs(ctx context.Context) error {
+	all, err := s.kv.GetAll(ctx, kvstore.AllOrganizations, orgBootstrapNamespace)
+	if err != nil {
+		return err
+	}
+	orgIDs := make([]int64, 0)
+	for orgID, values := range all {
+		if _, ok := values[orgBootstrapPendingKey]; ok {
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+	if len(orgIDs) == 0 {
+		return nil
+	}
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var errs []error
+	for _, orgID := range orgIDs {
+		if err := s.bootstrapOrg(ctx, orgID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to bootstrap organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// bootstrapOrg applies the bootstrap bundle to the organization and removes its mark in one transaction.
+func (s *OrgBootstrapService) bootstrapOrg(ctx context.Context, orgID int64) (err error) {
+	ctx, done := startOperation(ctx, s.tracer, s.metrics, "bundle", "BootstrapOrg", orgID)
+	defer func() { done(err) }()
+	logger := s.log.FromContext(ctx).New("org", orgID)
+
+	if s.freeze != nil {
+		frozen, err := s.freeze.IsFrozen(ctx, orgID)
+		if err != nil || frozen {
+			return err
+		}
+	}
+	var applyErr error
+	var applied bool
+	var pending orgBootstrapPending
+	// The lock of the configuration is taken before the transaction, as applying the bundle takes it as well.
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		applyErr, applied = nil, false
+		return s.xact.InTransaction(ctx, func(ctx context.Context) error {
+			var ok bool
+			var err error
+			pending, ok, err = s.getPending(ctx, orgID)
+			if err != nil || !ok {
+				// The organization was bootstrapped by another instance.
+				return err
+			}
+			stored, ok, err := s.getBundle(ctx)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				logger.Info("Bootstrap bundle was deleted before it was applied to the organization")
+				return s.kv.Del(ctx, orgID, orgBootstrapNamespace, orgBootstrapPendingKey)
+			}
+			bundle, err := s.decryptBundle(ctx, stored)
+			if err == nil {
+				_, err = s.bundles.ApplyProvisioningBundle(ctx, orgID, bundle, stored.UserID, stored.Provenance)
+			}
+			if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+				// The configuration of a new organization is created by its Alertmanager, it is bootstrapped on the
+				// next run.
+				return err
+			}
+			if err != nil {
+				applyErr = err
+				return err
+			}
+			applied = true
+			return s.kv.Del(ctx, orgID, orgBootstrapNamespace, orgBootstrapPendingKey)
+		})
+	})
+	if errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return nil
+	}
+	if applyErr == nil {
+		if err == nil && applied {
+			logger.Info("Applied bootstrap bundle to the organization")
+		}
+		return err
+	}
+
+	pending.Attempts++
+	pending.Error = applyErr.Error()
+	if pending.Attempts >= maxOrgBootstrapAttempts {
+		logger.Error("Giving up applying the bootstrap bundle to the organization", "attempts", pending.Attempts, "error", applyErr)
+		return s.kv.Del(ctx, orgID, orgBootstrapNamespace, orgBootstrapPendingKey)
+	}
+	logger.Warn("Failed to apply the bootstrap bundle to the organization", "attempts", pending.Attempts, "error", applyErr)
+	value, err := json.Marshal(pending)
+	if err != nil {
+		return err
+	}
+	return s.kv.Set(ctx, orgID, orgBootstrapNamespace, orgBootstrapPendingKey, string(value))
+}
+
+func (s *OrgBootstrapService) getBundle(ctx context.Context) (orgBootstrapBundle, bool, error) {
+	value, ok, err := s.kv.Get(ctx, 0, orgBootstrapNamespace, orgBootstrapBundleKey)
+	if err != nil || !ok {
+		return orgBootstrapBundle{}, false, err
+	}
+	var stored orgBootstrapBundle
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		return orgBootstrapBundle{}, false, fmt.Errorf("failed to unmarshal the bootstrap bundle: %w", err)
+	}
+	return stored, true, nil
+}
+
+func (s *OrgBootstrapService) getPending(ctx context.Context, orgID int64) (orgBootstrapPending, bool, error) {
+	value, ok, err := s.kv.Get(ctx, orgID, orgBootstrapNamespace, orgBootstrapPendingKey)
+	if err != nil || !ok {
+		return orgBootstrapPending{}, false, err
+	}
+	var pending orgBootstrapPending
+	if err := json.Unmarshal([]byte(value), &pending); err != nil {
+		return orgBootstrapPending{}, false, fmt.Errorf("failed to unmarshal the bootstrap mark of the organization: %w", err)
+	}
+	return pending, true, nil
+}
+
+func (s *OrgBootstrapService) decryptBundle(ctx context.Context, stored orgBootstrapBundle) (ProvisioningBundle, error) {
+	data, err := s.encryptionService.Decrypt(ctx, stored.Bundle)
+	if err != nil {
+		return ProvisioningBundle{}, fmt.Errorf("failed to decrypt the bundle: %w", err)
+	}
+	var bundle ProvisioningBundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return ProvisioningBundle{}, fmt.Errorf("failed to unmarshal the bundle: %w", err)
+	}
+	return bundle, nil
+}
@@ -0,0 +1,102 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/events"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/secrets/fakes"
+)
+
+func TestOrgBootstrapService(t *testing.T) {
+	ctx := context.Background()
+	bundle := ProvisioningBundle{
+		Templates:   []definitions.NotificationTemplate{{Name: "base", Template: "content"}},
+		MuteTimings: []definitions.MuteTimeInterval{{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}}},
+	}
+
+	t.Run("organizations created while a bundle is set are bootstrapped once they have a configuration", func(t *testing.T) {
+		sut, applier := createOrgBootstrapServiceSut()
+		summary, err := sut.SetOrgBootstrapBundle(ctx, bundle, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, 1, summary.Templates)
+		require.Equal(t, 1, summary.MuteTimings)
+		require.NoError(t, sut.HandleOrgCreated(ctx, &events.OrgCreated{Id: 2, Timestamp: time.Now()}))
+
+		applier.err = store.ErrNoAlertmanagerConfiguration
+		require.NoError(t, sut.BootstrapOrgs(ctx))
+		require.Empty(t, applier.applied)
+
+		applier.err = nil
+		require.NoError(t, sut.BootstrapOrgs(ctx))
+		require.Equal(t, []ProvisioningBundle{bundle}, applier.applied)
+
+		require.NoError(t, sut.BootstrapOrgs(ctx))
+		require.Len(t, applier.applied, 1)
+	})
+
+	t.Run("organizations created without a bundle are not bootstrapped", func(t *testing.T) {
+		sut, applier := createOrgBootstrapServiceSut()
+		require.NoError(t, sut.HandleOrgCreated(ctx, &events.OrgCreated{Id: 2, Timestamp: time.Now()}))
+		_, err := sut.SetOrgBootstrapBundle(ctx, bundle, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.BootstrapOrgs(ctx))
+		require.Empty(t, applier.applied)
+	})
+
+	t.Run("organizations are not bootstrapped if the bundle is deleted before it is applied", func(t *testing.T) {
+		sut, applier := createOrgBootstrapServiceSut()
+		_, err := sut.SetOrgBootstrapBundle(ctx, bundle, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, sut.HandleOrgCreated(ctx, &events.OrgCreated{Id: 2, Timestamp: time.Now()}))
+		require.NoError(t, sut.DeleteOrgBootstrapBundle(ctx))
+
+		require.NoError(t, sut.BootstrapOrgs(ctx))
+		require.Empty(t, applier.applied)
+		_, err = sut.GetOrgBootstrapBundle(ctx)
+		require.ErrorIs(t, err, ErrNotFound)
+		require.ErrorIs(t, sut.DeleteOrgBootstrapBundle(ctx), ErrNotFound)
+	})
+
+	t.Run("bootstrapping is given up after too many failed attempts", func(t *testing.T) {
+		sut, applier := createOrgBootstrapServiceSut()
+		_, err := sut.SetOrgBootstrapBundle(ctx, bundle, 1, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, sut.HandleOrgCreated(ctx, &events.OrgCreated{Id: 2, Timestamp: time.Now()}))
+
+		applier.err = errors.New("invalid contact point")
+		for i := 0; i < maxOrgBootstrapAttempts; i++ {
+			require.NoError(t, sut.BootstrapOrgs(ctx))
+		}
+		applier.err = nil
+		require.NoError(t, sut.BootstrapOrgs(ctx))
+		require.Empty(t, applier.applied)
+	})
+
+	t.Run("bundles with rule groups or without resources are rejected", func(t *testing.T) {
+		sut, _ := createOrgBootstrapServiceSut()
+
+		_, err := sut.SetOrgBootstrapBundle(ctx, ProvisioningBundle{RuleGroups: []models.AlertRuleGroup{{Title: "group"}}}, 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+		_, err = sut.SetOrgBootstrapBundle(ctx, ProvisioningBundle{}, 1, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createOrgBootstrapServiceSut() (*OrgBootstrapService, *fakeBundleApplier) {
+	applier := &fakeBundleApplier{}
+	return NewOrgBootstrapService(applier, nil, kvstore.NewFakeKVStore(), fakes.NewFakeSecretsService(), newNopTransactionManager(),
+		log.NewNopLogger(), tracing.InitializeTracerForTest(), nil), applier
+}
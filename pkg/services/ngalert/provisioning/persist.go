@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/state"
 	"github.com/grafana/grafana/pkg/services/quota"
 )
 
@@ -26,6 +27,7 @@ type ProvisioningStore interface {
 	GetProvenances(ctx context.Context, org int64, resourceType string) (map[string]models.Provenance, error)
 	SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error
 	DeleteProvenance(ctx context.Context, o models.Provisionable, org int64) error
+	InsertProvisioningAuditEntry(ctx context.Context, entry *models.ProvisioningAuditEntry) error
 }
 
 // TransactionManager represents the ability to issue and close transactions through contexts.
@@ -37,13 +39,25 @@ type TransactionManager interface {
 type RuleStore interface {
 	GetAlertRuleByUID(ctx context.Context, query *models.GetAlertRuleByUIDQuery) (*models.AlertRule, error)
 	ListAlertRules(ctx context.Context, query *models.ListAlertRulesQuery) (models.RulesGroup, error)
-	GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error)
 	InsertAlertRules(ctx context.Context, rule []models.AlertRule) (map[string]int64, error)
 	UpdateAlertRules(ctx context.Context, rule []models.UpdateRule) error
 	DeleteAlertRulesByUID(ctx context.Context, orgID int64, ruleUID ...string) error
 	GetAlertRulesGroupByRuleUID(ctx context.Context, query *models.GetAlertRulesGroupByRuleUIDQuery) ([]*models.AlertRule, error)
 }
 
+// RuleStateReader returns the current state of the alerts of a rule, which includes how long the last evaluation of the
+// rule took.
+type RuleStateReader interface {
+	GetStatesForRuleUID(orgID int64, alertRuleUID string) []*state.State
+}
+
+// AlertmanagerSilences manages the silences of the Alertmanagers of the organizations.
+type AlertmanagerSilences interface {
+	GetSilence(ctx context.Context, orgID int64, silenceID string) (definitions.GettableSilence, error)
+	CreateSilence(ctx context.Context, orgID int64, ps *definitions.PostableSilence) (string, error)
+	DeleteSilence(ctx context.Context, orgID int64, silenceID string) error
+}
+
 // QuotaChecker represents the ability to evaluate whether quotas are met.
 //
 //go:generate mockery --name QuotaChecker --structname MockQuotaChecker --inpackage --filename quota_checker_mock.go --with-expecter
@@ -51,11 +65,18 @@ type QuotaChecker interface {
 	CheckQuotaReached(ctx context.Context, target quota.TargetSrv, scopeParams *quota.ScopeParameters) (bool, error)
 }
 
-// PersistConfig validates to config before eventually persisting it if no error occurs
+// PersistConfig validates to config before eventually persisting it if no error occurs. In dry-run mode, the changes
+// are recorded instead and ErrDryRun is returned.
 func PersistConfig(ctx context.Context, store AMConfigStore, cmd *models.SaveAlertmanagerConfigurationCmd) error {
 	cfg := &definitions.PostableUserConfig{}
 	if err := json.Unmarshal([]byte(cmd.AlertmanagerConfiguration), cfg); err != nil {
 		return fmt.Errorf("change would result in an invalid configuration state: %w", err)
 	}
+	if dryRun, ok := dryRunFromContext(ctx); ok {
+		if err := dryRun.record(ctx, store, cmd); err != nil {
+			return err
+		}
+		return ErrDryRun
+	}
 	return store.UpdateAlertmanagerConfiguration(ctx, cmd)
 }
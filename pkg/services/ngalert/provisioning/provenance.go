@@ -11,3 +11,12 @@ func canUpdateProvenanceInRuleGroup(storedProvenance, provenance models.Provenan
 		storedProvenance == models.ProvenanceNone ||
 		(storedProvenance == models.ProvenanceAPI && provenance == models.ProvenanceNone)
 }
+
+// provenanceOrNone looks up the provenance of a resource in the result of a bulk provenance query.
+// Resources without a record have no provenance.
+func provenanceOrNone(provenances map[string]models.Provenance, resourceID string) models.Provenance {
+	if p, ok := provenances[resourceID]; ok && p != "" {
+		return p
+	}
+	return models.ProvenanceNone
+}
@@ -0,0 +1,78 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// CleanupOrphanedProvenance removes the provenance records of contact points, templates, mute timings and alert rules
+// of all organizations whose resources no longer exist. A failure for one organization does not prevent the others
+// from being cleaned up. It returns the number of removed records by resource type, including those removed before a
+// failure.
+func (svc *ProvenanceService) CleanupOrphanedProvenance(ctx context.Context) (definitions.ProvenanceCleanup, error) {
+	result := definitions.ProvenanceCleanup{Removed: map[string]int{}}
+	orgIDs, err := svc.orgs.GetOrgs(ctx)
+	if err != nil {
+		return result, err
+	}
+	var errs []error
+	for _, orgID := range orgIDs {
+		if err := svc.cleanupOrg(ctx, orgID, result.Removed); err != nil {
+			errs = append(errs, fmt.Errorf("failed to clean up the provenance of organization %d: %w", orgID, err))
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+// cleanupOrg removes the orphaned provenance records of an organization and adds their number to removed.
+func (svc *ProvenanceService) cleanupOrg(ctx context.Context, orgID int64, removed map[string]int) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provenance", "CleanupOrphanedProvenance", orgID)
+	defer func() { done(err) }()
+
+	// The provenance records are read before the resources, so that a resource created in the meantime is never
+	// mistaken for a missing one.
+	resourceTypes := []string{
+		(&definitions.EmbeddedContactPoint{}).ResourceType(),
+		(&definitions.NotificationTemplate{}).ResourceType(),
+		(&definitions.MuteTimeInterval{}).ResourceType(),
+		(&models.AlertRule{}).ResourceType(),
+	}
+	provenances := make(map[string]map[string]models.Provenance, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		byID, err := svc.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+		if err != nil {
+			return err
+		}
+		provenances[resourceType] = byID
+	}
+
+	existing, err := existingResources(ctx, orgID, svc.amStore, svc.ruleStore)
+	if err != nil {
+		return err
+	}
+
+	for _, resourceType := range resourceTypes {
+		ids, ok := existing[resourceType]
+		if !ok {
+			continue
+		}
+		for id := range provenances[resourceType] {
+			if _, ok := ids[id]; ok {
+				continue
+			}
+			if err := svc.provenanceStore.DeleteProvenance(ctx, provisionedResource{resourceType: resourceType, id: id}, orgID); err != nil {
+				return err
+			}
+			removed[resourceType]++
+			if svc.metrics != nil {
+				svc.metrics.ProvenanceCleanupRemovedTotal.WithLabelValues(resourceType).Inc()
+			}
+			svc.log.FromContext(ctx).Info("Removed orphaned provenance", "org", orgID, "resourceType", resourceType, "resourceID", id)
+		}
+	}
+	return nil
+}
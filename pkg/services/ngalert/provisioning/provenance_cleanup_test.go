@@ -0,0 +1,118 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestProvenanceService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("removes the provenance of resources that no longer exist", func(t *testing.T) {
+		sut, dbstore := createProvenanceServiceSut(t)
+		require.NoError(t, dbstore.SaveAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: `{
+				"template_files": {"kept": "{{ define \"kept\" }}{{ end }}"},
+				"alertmanager_config": {
+					"route": {"receiver": "team"},
+					"mute_time_intervals": [{"name": "weekends"}],
+					"receivers": [{
+						"name": "team",
+						"grafana_managed_receiver_configs": [{"uid": "kept-uid", "name": "team", "type": "email", "settings": {"addresses": "team@example.com"}}]
+					}]
+				}
+			}`,
+			ConfigurationVersion: "v1",
+			OrgID:                1,
+		}))
+		ids, err := dbstore.InsertAlertRules(ctx, []models.AlertRule{dummyRule("kept", 1)})
+		require.NoError(t, err)
+		var keptRule models.AlertRule
+		for uid := range ids {
+			keptRule = models.AlertRule{UID: uid}
+		}
+
+		kept := []models.Provisionable{
+			&keptRule,
+			&definitions.EmbeddedContactPoint{UID: "kept-uid"},
+			&definitions.NotificationTemplate{Name: "kept"},
+			&definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}},
+		}
+		orphaned := []models.Provisionable{
+			&models.AlertRule{UID: "deleted-rule"},
+			&definitions.EmbeddedContactPoint{UID: "deleted-uid"},
+			&definitions.NotificationTemplate{Name: "deleted"},
+			&definitions.NotificationTemplate{Name: "also-deleted"},
+			&definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: "holidays"}},
+		}
+		for _, r := range append(kept, orphaned...) {
+			require.NoError(t, dbstore.SetProvenance(ctx, r, 1, models.ProvenanceAPI))
+		}
+
+		result, err := sut.CleanupOrphanedProvenance(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{
+			"alertRule":        1,
+			"contactPoint":     1,
+			"template":         2,
+			"muteTimeInterval": 1,
+		}, result.Removed)
+		require.Equal(t, float64(2), testutil.ToFloat64(sut.metrics.ProvenanceCleanupRemovedTotal.WithLabelValues("template")))
+
+		for _, r := range kept {
+			p, err := dbstore.GetProvenance(ctx, r, 1)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceAPI, p, "provenance of %s %s", r.ResourceType(), r.ResourceID())
+		}
+		for _, r := range orphaned {
+			p, err := dbstore.GetProvenance(ctx, r, 1)
+			require.NoError(t, err)
+			require.Equal(t, models.ProvenanceNone, p, "provenance of %s %s", r.ResourceType(), r.ResourceID())
+		}
+	})
+
+	t.Run("keeps the provenance of the Alertmanager configuration of an organization without configuration", func(t *testing.T) {
+		sut, dbstore := createProvenanceServiceSut(t)
+		template := &definitions.NotificationTemplate{Name: "template"}
+		require.NoError(t, dbstore.SetProvenance(ctx, template, 1, models.ProvenanceFile))
+		rule := &models.AlertRule{UID: "deleted-rule"}
+		require.NoError(t, dbstore.SetProvenance(ctx, rule, 1, models.ProvenanceFile))
+
+		result, err := sut.CleanupOrphanedProvenance(ctx)
+		require.NoError(t, err)
+		require.Equal(t, map[string]int{"alertRule": 1}, result.Removed)
+
+		p, err := dbstore.GetProvenance(ctx, template, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceFile, p)
+	})
+}
+
+func createProvenanceServiceSut(t *testing.T) (*ProvenanceService, *store.DBstore) {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	dbstore := &store.DBstore{
+		SQLStore: sqlStore,
+		Cfg: setting.UnifiedAlertingSettings{
+			BaseInterval: time.Second * 10,
+		},
+		Logger: log.NewNopLogger(),
+	}
+	m := metrics.NewProvisioningMetrics(prometheus.NewRegistry())
+	return NewProvenanceService(dbstore, dbstore, dbstore, fakeOrgStore{orgs: []int64{1}}, log.NewNopLogger(), tracing.InitializeTracerForTest(), m), dbstore
+}
@@ -0,0 +1,147 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// provenanceResourceTypes are the types of resources whose provenance can be listed, in the order they are listed.
+var provenanceResourceTypes = []string{
+	(&definitions.EmbeddedContactPoint{}).ResourceType(),
+	(&definitions.NotificationTemplate{}).ResourceType(),
+	(&definitions.MuteTimeInterval{}).ResourceType(),
+	(&definitions.Route{}).ResourceType(),
+	(&models.AlertRule{}).ResourceType(),
+}
+
+// ProvenanceService lists the provenance of the alerting resources of the organizations, and removes the provenance
+// records whose resources no longer exist. Such records are left behind when a resource is removed without going
+// through the provisioning services, for example when the Alertmanager configuration is replaced through the
+// Alertmanager API, and they would otherwise prevent a new resource with the same identifier from being edited in
+// the UI.
+type ProvenanceService struct {
+	amStore         AMConfigStore
+	ruleStore       RuleStore
+	provenanceStore ProvisioningStore
+	orgs            store.OrgStore
+	log             log.Logger
+	tracer          tracing.Tracer
+	metrics         *metrics.Provisioning
+}
+
+func NewProvenanceService(amStore AMConfigStore, ruleStore RuleStore, provenanceStore ProvisioningStore,
+	orgs store.OrgStore, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *ProvenanceService {
+	return &ProvenanceService{
+		amStore:         newTracedAMConfigStore(amStore, tracer, log, m),
+		ruleStore:       ruleStore,
+		provenanceStore: provenanceStore,
+		orgs:            orgs,
+		log:             log,
+		tracer:          tracer,
+		metrics:         m,
+	}
+}
+
+// GetProvenances returns the provenance of every existing resource of the organization of the given type, or of all
+// types if it is empty. Resources managed in the UI are listed with no provenance. Provenance records of resources
+// that no longer exist are left out.
+func (svc *ProvenanceService) GetProvenances(ctx context.Context, orgID int64, resourceType string) (_ []definitions.ResourceProvenance, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provenance", "GetProvenances", orgID,
+		attribute.String("resource_type", resourceType))
+	defer func() { done(err) }()
+
+	resourceTypes := provenanceResourceTypes
+	if resourceType != "" {
+		known := false
+		for _, t := range provenanceResourceTypes {
+			known = known || t == resourceType
+		}
+		if !known {
+			return nil, newValidationError("resourceType", "unknown resource type '%s'", resourceType)
+		}
+		resourceTypes = []string{resourceType}
+	}
+
+	existing, err := existingResources(ctx, orgID, svc.amStore, svc.ruleStore)
+	if err != nil {
+		return nil, err
+	}
+	result := []definitions.ResourceProvenance{}
+	for _, t := range resourceTypes {
+		ids := make([]string, 0, len(existing[t]))
+		for id := range existing[t] {
+			ids = append(ids, id)
+		}
+		if len(ids) == 0 {
+			continue
+		}
+		sort.Strings(ids)
+		provenances, err := svc.provenanceStore.GetProvenances(ctx, orgID, t)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range ids {
+			result = append(result, definitions.ResourceProvenance{
+				ResourceType: t,
+				ResourceID:   id,
+				Provenance:   definitions.Provenance(provenanceOrNone(provenances, id)),
+			})
+		}
+	}
+	return result, nil
+}
+
+// existingResources returns the identifiers of the resources of the organization by resource type. The resources of
+// the Alertmanager configuration are left out if the organization has no configuration yet, as its provenance
+// records cannot be checked then.
+func existingResources(ctx context.Context, orgID int64, amStore AMConfigStore, ruleStore RuleStore) (map[string]map[string]struct{}, error) {
+	existing := make(map[string]map[string]struct{}, len(provenanceResourceTypes))
+
+	revision, err := getLastConfiguration(ctx, orgID, amStore)
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return nil, err
+	}
+	if revision != nil {
+		receivers := make(map[string]struct{})
+		for _, r := range revision.receivers().all() {
+			receivers[r.UID] = struct{}{}
+		}
+		existing[(&definitions.EmbeddedContactPoint{}).ResourceType()] = receivers
+
+		templates := make(map[string]struct{}, len(revision.cfg.TemplateFiles))
+		for name := range revision.cfg.TemplateFiles {
+			templates[name] = struct{}{}
+		}
+		existing[(&definitions.NotificationTemplate{}).ResourceType()] = templates
+
+		muteTimings := make(map[string]struct{}, len(revision.cfg.AlertmanagerConfig.MuteTimeIntervals))
+		for _, interval := range revision.cfg.AlertmanagerConfig.MuteTimeIntervals {
+			muteTimings[interval.Name] = struct{}{}
+		}
+		existing[(&definitions.MuteTimeInterval{}).ResourceType()] = muteTimings
+
+		// The notification policy tree is a single resource without an identifier.
+		existing[(&definitions.Route{}).ResourceType()] = map[string]struct{}{"": {}}
+	}
+
+	rules, err := ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return nil, err
+	}
+	ruleUIDs := make(map[string]struct{}, len(rules))
+	for _, rule := range rules {
+		ruleUIDs[rule.UID] = struct{}{}
+	}
+	existing[(&models.AlertRule{}).ResourceType()] = ruleUIDs
+	return existing, nil
+}
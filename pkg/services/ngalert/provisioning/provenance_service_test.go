@@ -0,0 +1,66 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestGetProvenances(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T) *ProvenanceService {
+		t.Helper()
+		sut, dbstore := createProvenanceServiceSut(t)
+		require.NoError(t, dbstore.SaveAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: `{
+				"template_files": {"api": "{{ define \"api\" }}{{ end }}", "ui": "{{ define \"ui\" }}{{ end }}"},
+				"alertmanager_config": {
+					"route": {"receiver": "team"},
+					"receivers": [{
+						"name": "team",
+						"grafana_managed_receiver_configs": [{"uid": "team-uid", "name": "team", "type": "email", "settings": {"addresses": "team@example.com"}}]
+					}]
+				}
+			}`,
+			ConfigurationVersion: "v1",
+			OrgID:                1,
+		}))
+		require.NoError(t, dbstore.SetProvenance(ctx, &definitions.NotificationTemplate{Name: "api"}, 1, models.ProvenanceAPI))
+		require.NoError(t, dbstore.SetProvenance(ctx, &definitions.EmbeddedContactPoint{UID: "team-uid"}, 1, models.ProvenanceFile))
+		// The provenance of a resource that no longer exists is not listed.
+		require.NoError(t, dbstore.SetProvenance(ctx, &definitions.NotificationTemplate{Name: "deleted"}, 1, models.ProvenanceAPI))
+		return sut
+	}
+
+	t.Run("lists the provenance of every resource", func(t *testing.T) {
+		sut := setup(t)
+
+		result, err := sut.GetProvenances(ctx, 1, "")
+		require.NoError(t, err)
+		require.Equal(t, []definitions.ResourceProvenance{
+			{ResourceType: "contactPoint", ResourceID: "team-uid", Provenance: "file"},
+			{ResourceType: "template", ResourceID: "api", Provenance: "api"},
+			{ResourceType: "template", ResourceID: "ui", Provenance: ""},
+			{ResourceType: "route", ResourceID: "", Provenance: ""},
+		}, result)
+	})
+
+	t.Run("lists the provenance of resources of a type", func(t *testing.T) {
+		sut := setup(t)
+
+		result, err := sut.GetProvenances(ctx, 1, "template")
+		require.NoError(t, err)
+		require.Len(t, result, 2)
+	})
+
+	t.Run("fails for an unknown resource type", func(t *testing.T) {
+		sut := setup(t)
+
+		_, err := sut.GetProvenances(ctx, 1, "unknown")
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
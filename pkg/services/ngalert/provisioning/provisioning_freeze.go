@@ -0,0 +1,140 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/exp/slices"
+
+	"github.com/grafana/grafana/pkg/infra/appcontext"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+const provisioningFreezeKey = "provisioning_freeze"
+
+// ProvisioningFreezeService manages the provisioning freeze of organizations. While provisioning is frozen, the
+// provisioned resources of an organization can only be changed by the allowed service accounts, which stops
+// automation from altering routing during incidents and change freezes. Organizations are frozen with the API, which
+// keeps the freeze in the key-value store, or with the configuration of Grafana, which cannot be lifted with the API.
+type ProvisioningFreezeService struct {
+	kv kvstore.KVStore
+	// frozenOrgs are the organizations frozen by the configuration.
+	frozenOrgs map[int64]struct{}
+	// allowedServiceAccounts are the service accounts that can write in all frozen organizations.
+	allowedServiceAccounts []int64
+	log                    log.Logger
+	tracer                 tracing.Tracer
+	metrics                *metrics.Provisioning
+}
+
+func NewProvisioningFreezeService(kv kvstore.KVStore, frozenOrgs map[int64]struct{}, allowedServiceAccounts []int64,
+	log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *ProvisioningFreezeService {
+	return &ProvisioningFreezeService{
+		kv:                     kv,
+		frozenOrgs:             frozenOrgs,
+		allowedServiceAccounts: allowedServiceAccounts,
+		log:                    log,
+		tracer:                 tracer,
+		metrics:                m,
+	}
+}
+
+// GetFreeze returns the provisioning freeze of the organization.
+func (svc *ProvisioningFreezeService) GetFreeze(ctx context.Context, orgID int64) (_ definitions.ProvisioningFreeze, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provisioningFreeze", "GetFreeze", orgID)
+	defer func() { done(err) }()
+	return svc.load(ctx, orgID)
+}
+
+// SetFreeze freezes or unfreezes the provisioning of the organization. An organization that is frozen by the
+// configuration stays frozen.
+func (svc *ProvisioningFreezeService) SetFreeze(ctx context.Context, orgID int64, freeze definitions.ProvisioningFreeze) (_ definitions.ProvisioningFreeze, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provisioningFreeze", "SetFreeze", orgID,
+		attribute.Bool("frozen", freeze.Frozen))
+	defer func() { done(err) }()
+	for i, id := range freeze.AllowedServiceAccountIDs {
+		if id <= 0 {
+			return definitions.ProvisioningFreeze{}, newValidationError(fmt.Sprintf("allowedServiceAccountIds[%d]", i), "service account ID %d is not valid", id)
+		}
+	}
+	freeze.FrozenByConfiguration = false
+	if !freeze.Frozen {
+		freeze = definitions.ProvisioningFreeze{}
+	}
+	if err := svc.save(ctx, orgID, freeze); err != nil {
+		return definitions.ProvisioningFreeze{}, err
+	}
+
+	logger := svc.log.FromContext(ctx).New("org", orgID)
+	if u, err := appcontext.User(ctx); err == nil {
+		logger = logger.New("actor", u.Login)
+	}
+	if freeze.Frozen {
+		logger.Info("Provisioning was frozen", "reason", freeze.Reason, "allowed_service_accounts", freeze.AllowedServiceAccountIDs)
+	} else {
+		logger.Info("Provisioning was unfrozen")
+	}
+	return svc.load(ctx, orgID)
+}
+
+// IsFrozen reports whether the provisioning of the organization is frozen.
+func (svc *ProvisioningFreezeService) IsFrozen(ctx context.Context, orgID int64) (bool, error) {
+	freeze, err := svc.load(ctx, orgID)
+	if err != nil {
+		return false, err
+	}
+	return freeze.Frozen, nil
+}
+
+// CheckWrite returns ErrFrozen if the provisioning of the organization is frozen and the user is not one of the
+// service accounts allowed to change the provisioned resources during the freeze.
+func (svc *ProvisioningFreezeService) CheckWrite(ctx context.Context, orgID int64, u *user.SignedInUser) error {
+	freeze, err := svc.load(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if !freeze.Frozen {
+		return nil
+	}
+	if u != nil && u.IsServiceAccount &&
+		(slices.Contains(freeze.AllowedServiceAccountIDs, u.UserID) || slices.Contains(svc.allowedServiceAccounts, u.UserID)) {
+		return nil
+	}
+	return &Error{Code: ErrCodeFrozen, Reason: freeze.Reason}
+}
+
+func (svc *ProvisioningFreezeService) load(ctx context.Context, orgID int64) (definitions.ProvisioningFreeze, error) {
+	var freeze definitions.ProvisioningFreeze
+	value, ok, err := svc.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, provisioningFreezeKey)
+	if err != nil {
+		return freeze, err
+	}
+	if ok {
+		if err := json.Unmarshal([]byte(value), &freeze); err != nil {
+			return freeze, fmt.Errorf("failed to unmarshal provisioning freeze: %w", err)
+		}
+	}
+	if _, ok := svc.frozenOrgs[orgID]; ok {
+		freeze.Frozen = true
+		freeze.FrozenByConfiguration = true
+	}
+	return freeze, nil
+}
+
+func (svc *ProvisioningFreezeService) save(ctx context.Context, orgID int64, freeze definitions.ProvisioningFreeze) error {
+	if !freeze.Frozen {
+		return svc.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, provisioningFreezeKey)
+	}
+	data, err := json.Marshal(freeze)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, provisioningFreezeKey, string(data))
+}
@@ -0,0 +1,74 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/user"
+)
+
+func TestProvisioningFreezeService(t *testing.T) {
+	ctx := context.Background()
+	serviceAccount := &user.SignedInUser{UserID: 7, OrgID: 1, IsServiceAccount: true}
+	otherServiceAccount := &user.SignedInUser{UserID: 8, OrgID: 1, IsServiceAccount: true}
+	editor := &user.SignedInUser{UserID: 7, OrgID: 1}
+
+	t.Run("changes are rejected while provisioning is frozen", func(t *testing.T) {
+		sut := createProvisioningFreezeServiceSut(nil, nil)
+		require.NoError(t, sut.CheckWrite(ctx, 1, editor))
+
+		_, err := sut.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: true, Reason: "incident"})
+		require.NoError(t, err)
+
+		err = sut.CheckWrite(ctx, 1, editor)
+		require.ErrorIs(t, err, ErrFrozen)
+		require.ErrorContains(t, err, "incident")
+		require.NoError(t, sut.CheckWrite(ctx, 2, editor))
+
+		_, err = sut.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: false})
+		require.NoError(t, err)
+		require.NoError(t, sut.CheckWrite(ctx, 1, editor))
+	})
+
+	t.Run("allowed service accounts can write during the freeze", func(t *testing.T) {
+		sut := createProvisioningFreezeServiceSut(nil, nil)
+		_, err := sut.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: true, AllowedServiceAccountIDs: []int64{7}})
+		require.NoError(t, err)
+
+		require.NoError(t, sut.CheckWrite(ctx, 1, serviceAccount))
+		require.ErrorIs(t, sut.CheckWrite(ctx, 1, otherServiceAccount), ErrFrozen)
+		// The allow-list only applies to service accounts, not to users with the same ID.
+		require.ErrorIs(t, sut.CheckWrite(ctx, 1, editor), ErrFrozen)
+		require.ErrorIs(t, sut.CheckWrite(ctx, 1, nil), ErrFrozen)
+	})
+
+	t.Run("organizations frozen by the configuration cannot be unfrozen", func(t *testing.T) {
+		sut := createProvisioningFreezeServiceSut(map[int64]struct{}{1: {}}, []int64{8})
+
+		freeze, err := sut.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: false})
+		require.NoError(t, err)
+
+		require.Equal(t, definitions.ProvisioningFreeze{Frozen: true, FrozenByConfiguration: true}, freeze)
+		require.ErrorIs(t, sut.CheckWrite(ctx, 1, serviceAccount), ErrFrozen)
+		require.NoError(t, sut.CheckWrite(ctx, 1, otherServiceAccount))
+	})
+
+	t.Run("invalid service account IDs are rejected", func(t *testing.T) {
+		sut := createProvisioningFreezeServiceSut(nil, nil)
+
+		_, err := sut.SetFreeze(ctx, 1, definitions.ProvisioningFreeze{Frozen: true, AllowedServiceAccountIDs: []int64{7, -1}})
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
+
+func createProvisioningFreezeServiceSut(frozenOrgs map[int64]struct{}, allowedServiceAccounts []int64) *ProvisioningFreezeService {
+	return NewProvisioningFreezeService(kvstore.NewFakeKVStore(), frozenOrgs, allowedServiceAccounts, log.NewNopLogger(),
+		tracing.InitializeTracerForTest(), nil)
+}
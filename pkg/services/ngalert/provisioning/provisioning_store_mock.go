@@ -157,6 +157,44 @@ func (_c *MockProvisioningStore_GetProvenances_Call) Return(_a0 map[string]model
 	return _c
 }
 
+// InsertProvisioningAuditEntry provides a mock function with given fields: ctx, entry
+func (_m *MockProvisioningStore) InsertProvisioningAuditEntry(ctx context.Context, entry *models.ProvisioningAuditEntry) error {
+	ret := _m.Called(ctx, entry)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *models.ProvisioningAuditEntry) error); ok {
+		r0 = rf(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MockProvisioningStore_InsertProvisioningAuditEntry_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'InsertProvisioningAuditEntry'
+type MockProvisioningStore_InsertProvisioningAuditEntry_Call struct {
+	*mock.Call
+}
+
+// InsertProvisioningAuditEntry is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry *models.ProvisioningAuditEntry
+func (_e *MockProvisioningStore_Expecter) InsertProvisioningAuditEntry(ctx any, entry any) *MockProvisioningStore_InsertProvisioningAuditEntry_Call {
+	return &MockProvisioningStore_InsertProvisioningAuditEntry_Call{Call: _e.mock.On("InsertProvisioningAuditEntry", ctx, entry)}
+}
+
+func (_c *MockProvisioningStore_InsertProvisioningAuditEntry_Call) Run(run func(ctx context.Context, entry *models.ProvisioningAuditEntry)) *MockProvisioningStore_InsertProvisioningAuditEntry_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(*models.ProvisioningAuditEntry))
+	})
+	return _c
+}
+
+func (_c *MockProvisioningStore_InsertProvisioningAuditEntry_Call) Return(_a0 error) *MockProvisioningStore_InsertProvisioningAuditEntry_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
 // SetProvenance provides a mock function with given fields: ctx, o, org, p
 func (_m *MockProvisioningStore) SetProvenance(ctx context.Context, o models.Provisionable, org int64, p models.Provenance) error {
 	ret := _m.Called(ctx, o, org, p)
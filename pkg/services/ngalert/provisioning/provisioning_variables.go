@@ -0,0 +1,264 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+)
+
+const (
+	provisioningVariablesNamespace = "ngalert.provisioning.variables"
+	provisioningVariablesKey       = "provisioning_variables"
+	// provisioningVariableRefPrefix starts the references to variables. Other ${...} values are left as they are.
+	provisioningVariableRefPrefix = "${vars."
+)
+
+var (
+	provisioningVariableNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	// provisioningVariableRefRegex matches references to variables, such as ${vars.SLACK_CHANNEL_PREFIX}, and escaped
+	// references, such as $${vars.SLACK_CHANNEL_PREFIX}. Escaped references are kept in the stored settings, so that
+	// saving the settings that were read back does not change them, and are sent as ${vars.SLACK_CHANNEL_PREFIX}.
+	provisioningVariableRefRegex = regexp.MustCompile(`\$?\$\{vars\.([^}]*)\}`)
+)
+
+// ProvisioningVariablesService manages the variables of an organization, such as SLACK_CHANNEL_PREFIX, that are
+// substituted in the settings of contact points when they are saved, so that the same provisioning files can be
+// applied to several environments with different values. Settings refer to variables explicitly, as in
+// ${vars.SLACK_CHANNEL_PREFIX}, so that other values that look like references are kept. Variables are not
+// substituted in secure settings, which are kept in the secrets service. The variables are kept in the key-value
+// store, per organization. Changes of the variables of an organization are serialized, like changes of its
+// Alertmanager configuration.
+type ProvisioningVariablesService struct {
+	kv      kvstore.KVStore
+	locks   *orgLocks
+	tracer  tracing.Tracer
+	metrics *metrics.Provisioning
+}
+
+func NewProvisioningVariablesService(kv kvstore.KVStore, tracer tracing.Tracer, m *metrics.Provisioning) *ProvisioningVariablesService {
+	return &ProvisioningVariablesService{
+		kv:      kv,
+		locks:   &orgLocks{locks: make(map[int64]*orgLock)},
+		tracer:  tracer,
+		metrics: m,
+	}
+}
+
+// GetVariables returns the variables of the organization, sorted by name.
+func (svc *ProvisioningVariablesService) GetVariables(ctx context.Context, orgID int64) (_ []definitions.ProvisioningVariable, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provisioningVariable", "GetVariables", orgID)
+	defer func() { done(err) }()
+	variables, err := svc.load(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.ProvisioningVariable, 0, len(variables))
+	for name, value := range variables {
+		result = append(result, definitions.ProvisioningVariable{Name: name, Value: value})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// SetVariable creates or updates a variable of the organization. Contact points that were saved with the previous
+// value keep it until they are saved again.
+func (svc *ProvisioningVariablesService) SetVariable(ctx context.Context, orgID int64, v definitions.ProvisioningVariable) (_ definitions.ProvisioningVariable, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provisioningVariable", "SetVariable", orgID,
+		attribute.String("variable_name", v.Name))
+	defer func() { done(err) }()
+	if !provisioningVariableNameRegex.MatchString(v.Name) {
+		return definitions.ProvisioningVariable{}, newValidationError("name", "variable name '%s' must consist of letters, digits and underscores, and must not start with a digit", v.Name)
+	}
+	unlock, err := svc.locks.lock(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisioningVariable{}, err
+	}
+	defer unlock()
+	variables, err := svc.load(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisioningVariable{}, err
+	}
+	variables[v.Name] = v.Value
+	if err := svc.save(ctx, orgID, variables); err != nil {
+		return definitions.ProvisioningVariable{}, err
+	}
+	return v, nil
+}
+
+// DeleteVariable removes a variable of the organization. Contact points that were saved with the variable keep its
+// value.
+func (svc *ProvisioningVariablesService) DeleteVariable(ctx context.Context, orgID int64, name string) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "provisioningVariable", "DeleteVariable", orgID,
+		attribute.String("variable_name", name))
+	defer func() { done(err) }()
+	unlock, err := svc.locks.lock(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	variables, err := svc.load(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if _, ok := variables[name]; !ok {
+		return newNotFoundError("provisioningVariable", name, "variable '%s' does not exist", name)
+	}
+	delete(variables, name)
+	return svc.save(ctx, orgID, variables)
+}
+
+// substituteContactPoint replaces the references to variables in the settings of the contact point, except for its
+// secure settings. References to undefined variables are validation errors.
+func (svc *ProvisioningVariablesService) substituteContactPoint(ctx context.Context, orgID int64, cp *definitions.EmbeddedContactPoint) error {
+	if cp.Settings == nil {
+		return nil
+	}
+	// Settings that are not an object and unknown types are rejected by the validation of the contact point.
+	settings, err := cp.Settings.Map()
+	if err != nil {
+		return nil
+	}
+	secretKeys, err := GetSecretKeysForContactPointType(cp.Type)
+	if err != nil {
+		return nil
+	}
+	var variables map[string]string
+	changed := false
+	for key, value := range settings {
+		if isSecretKey(secretKeys, key) || !containsVariableRef(value) {
+			continue
+		}
+		if variables == nil {
+			if variables, err = svc.load(ctx, orgID); err != nil {
+				return err
+			}
+		}
+		settings[key], err = substituteVariables(value, variables, "settings."+key)
+		if err != nil {
+			return err
+		}
+		changed = true
+	}
+	if changed {
+		cp.Settings = simplejson.NewFromAny(settings)
+	}
+	return nil
+}
+
+// resolveVariables replaces the references to the variables of the organization in the settings of the contact point.
+// Contact points are saved with the values of the variables, so changing a variable later does not change them.
+func (ecp *ContactPointService) resolveVariables(ctx context.Context, orgID int64, cp *definitions.EmbeddedContactPoint) error {
+	if ecp.variables == nil {
+		return nil
+	}
+	return ecp.variables.substituteContactPoint(ctx, orgID, cp)
+}
+
+// containsVariableRef reports whether a setting, or any of its nested values, refers to a variable.
+func containsVariableRef(value any) bool {
+	switch v := value.(type) {
+	case string:
+		return strings.Contains(v, provisioningVariableRefPrefix)
+	case map[string]any:
+		for _, e := range v {
+			if containsVariableRef(e) {
+				return true
+			}
+		}
+	case []any:
+		for _, e := range v {
+			if containsVariableRef(e) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// substituteVariables replaces the references to variables in the strings of a setting and its nested values. The
+// field is the path of the setting, used in errors.
+func substituteVariables(value any, variables map[string]string, field string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		return expandVariables(v, variables, field)
+	case map[string]any:
+		for k, e := range v {
+			expanded, err := substituteVariables(e, variables, field+"."+k)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = expanded
+		}
+		return v, nil
+	case []any:
+		for i, e := range v {
+			expanded, err := substituteVariables(e, variables, fmt.Sprintf("%s[%d]", field, i))
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// expandVariables replaces the references to variables in a string. Escaped references are kept as they are.
+func expandVariables(s string, variables map[string]string, field string) (string, error) {
+	var err error
+	result := provisioningVariableRefRegex.ReplaceAllStringFunc(s, func(ref string) string {
+		if strings.HasPrefix(ref, "$$") {
+			return ref
+		}
+		name := strings.TrimPrefix(ref[:len(ref)-1], provisioningVariableRefPrefix)
+		value, ok := variables[name]
+		if !ok && err == nil {
+			err = newValidationError(field, "variable '%s' is not defined, use $${vars.%s} to keep the reference literally", name, name)
+		}
+		return value
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
+
+func (svc *ProvisioningVariablesService) load(ctx context.Context, orgID int64) (map[string]string, error) {
+	value, ok, err := svc.kv.Get(ctx, orgID, provisioningVariablesNamespace, provisioningVariablesKey)
+	if err != nil {
+		return nil, err
+	}
+	variables := map[string]string{}
+	if !ok {
+		return variables, nil
+	}
+	if err := json.Unmarshal([]byte(value), &variables); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal provisioning variables: %w", err)
+	}
+	return variables, nil
+}
+
+func (svc *ProvisioningVariablesService) save(ctx context.Context, orgID int64, variables map[string]string) error {
+	if len(variables) == 0 {
+		return svc.kv.Del(ctx, orgID, provisioningVariablesNamespace, provisioningVariablesKey)
+	}
+	data, err := json.Marshal(variables)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, orgID, provisioningVariablesNamespace, provisioningVariablesKey, string(data))
+}
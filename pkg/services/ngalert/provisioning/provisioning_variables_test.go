@@ -0,0 +1,187 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/components/simplejson"
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/secrets/database"
+	"github.com/grafana/grafana/pkg/services/secrets/manager"
+)
+
+func TestProvisioningVariablesService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("variables are listed by name per organization", func(t *testing.T) {
+		sut := NewProvisioningVariablesService(kvstore.NewFakeKVStore(), tracing.InitializeTracerForTest(), nil)
+		_, err := sut.SetVariable(ctx, 1, definitions.ProvisioningVariable{Name: "SLACK_CHANNEL_PREFIX", Value: "staging"})
+		require.NoError(t, err)
+		_, err = sut.SetVariable(ctx, 1, definitions.ProvisioningVariable{Name: "ENV", Value: "stg"})
+		require.NoError(t, err)
+		_, err = sut.SetVariable(ctx, 2, definitions.ProvisioningVariable{Name: "ENV", Value: "prod"})
+		require.NoError(t, err)
+
+		variables, err := sut.GetVariables(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []definitions.ProvisioningVariable{
+			{Name: "ENV", Value: "stg"},
+			{Name: "SLACK_CHANNEL_PREFIX", Value: "staging"},
+		}, variables)
+
+		require.NoError(t, sut.DeleteVariable(ctx, 1, "ENV"))
+		variables, err = sut.GetVariables(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, variables, 1)
+		require.ErrorIs(t, sut.DeleteVariable(ctx, 1, "ENV"), ErrNotFound)
+	})
+
+	t.Run("concurrent changes of variables are not lost", func(t *testing.T) {
+		sut := NewProvisioningVariablesService(kvstore.NewFakeKVStore(), tracing.InitializeTracerForTest(), nil)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, err := sut.SetVariable(ctx, 1, definitions.ProvisioningVariable{Name: fmt.Sprintf("VAR_%d", i), Value: "value"})
+				require.NoError(t, err)
+			}(i)
+		}
+		wg.Wait()
+
+		variables, err := sut.GetVariables(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, variables, 10)
+	})
+
+	t.Run("invalid names are rejected", func(t *testing.T) {
+		sut := NewProvisioningVariablesService(kvstore.NewFakeKVStore(), tracing.InitializeTracerForTest(), nil)
+		for _, name := range []string{"", "1ST", "SLACK-CHANNEL", "${ENV}"} {
+			_, err := sut.SetVariable(ctx, 1, definitions.ProvisioningVariable{Name: name})
+			require.ErrorIs(t, err, ErrValidation, name)
+		}
+	})
+}
+
+func TestExpandVariables(t *testing.T) {
+	variables := map[string]string{"ENV": "prod", "PREFIX": "alerts"}
+	testCases := []struct {
+		in       string
+		expected string
+	}{
+		{in: "#${vars.PREFIX}-${vars.ENV}", expected: "#alerts-prod"},
+		{in: "no variables", expected: "no variables"},
+		{in: "${ENV} is not a reference", expected: "${ENV} is not a reference"},
+		{in: "$${vars.ENV} is kept", expected: "$${vars.ENV} is kept"},
+		{in: "unclosed ${vars.ENV", expected: "unclosed ${vars.ENV"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.in, func(t *testing.T) {
+			expanded, err := expandVariables(tc.in, variables, "settings.text")
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, expanded)
+		})
+	}
+
+	_, err := expandVariables("${vars.UNKNOWN}", variables, "settings.text")
+	require.ErrorIs(t, err, ErrValidation)
+	var provisioningErr *Error
+	require.ErrorAs(t, err, &provisioningErr)
+	require.Equal(t, "settings.text", provisioningErr.Field)
+}
+
+func TestContactPointVariables(t *testing.T) {
+	sqlStore := db.InitTestDB(t)
+	secretsService := manager.SetupTestService(t, database.ProvideSecretsStore(sqlStore))
+	ctx := context.Background()
+
+	setup := func(t *testing.T) *ContactPointService {
+		sut := createContactPointServiceSut(t, secretsService)
+		sut.variables = NewProvisioningVariablesService(kvstore.NewFakeKVStore(), tracing.InitializeTracerForTest(), nil)
+		_, err := sut.variables.SetVariable(ctx, 1, definitions.ProvisioningVariable{Name: "SLACK_CHANNEL_PREFIX", Value: "#staging"})
+		require.NoError(t, err)
+		return sut
+	}
+
+	t.Run("variables are substituted in settings when contact points are saved", func(t *testing.T) {
+		sut := setup(t)
+		cp := createTestContactPoint()
+		cp.Settings = simplejson.NewFromAny(map[string]any{
+			"recipient": "${vars.SLACK_CHANNEL_PREFIX}-alerts",
+			"token":     "${vars.SLACK_CHANNEL_PREFIX}",
+		})
+
+		created, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, "#staging-alerts", created.Settings.Get("recipient").MustString())
+
+		updated := createTestContactPoint()
+		updated.UID = created.UID
+		updated.Settings = simplejson.NewFromAny(map[string]any{
+			"recipient": "${vars.SLACK_CHANNEL_PREFIX}-critical",
+			"token":     definitions.RedactedValue,
+		})
+		require.NoError(t, sut.UpdateContactPoint(ctx, 1, updated, models.ProvenanceAPI, UpdateContactPointOptions{}))
+
+		q := cpsQuery(1)
+		q.Name = cp.Name
+		cps, err := sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.Equal(t, "#staging-critical", cps[0].Settings.Get("recipient").MustString())
+	})
+
+	t.Run("secure settings are not substituted", func(t *testing.T) {
+		sut := setup(t)
+		cp := createTestContactPoint()
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "#alerts", "token": "${vars.SLACK_CHANNEL_PREFIX}"})
+
+		created, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		revision, err := getLastConfiguration(ctx, 1, sut.amStore)
+		require.NoError(t, err)
+		stored, err := sut.getContactPointDecrypted(revision, created.UID)
+		require.NoError(t, err)
+		require.Equal(t, "${vars.SLACK_CHANNEL_PREFIX}", stored.Settings.Get("token").MustString())
+	})
+
+	t.Run("escaped references are kept when contact points are read and saved again", func(t *testing.T) {
+		sut := setup(t)
+		cp := createTestContactPoint()
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "$${vars.SLACK_CHANNEL_PREFIX}-alerts", "token": "token"})
+
+		created, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.Equal(t, "$${vars.SLACK_CHANNEL_PREFIX}-alerts", created.Settings.Get("recipient").MustString())
+
+		q := cpsQuery(1)
+		q.Name = cp.Name
+		cps, err := sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Len(t, cps, 1)
+		require.NoError(t, sut.UpdateContactPoint(ctx, 1, cps[0], models.ProvenanceAPI, UpdateContactPointOptions{}))
+
+		cps, err = sut.GetContactPoints(ctx, q, nil)
+		require.NoError(t, err)
+		require.Equal(t, "$${vars.SLACK_CHANNEL_PREFIX}-alerts", cps[0].Settings.Get("recipient").MustString())
+	})
+
+	t.Run("undefined variables are rejected", func(t *testing.T) {
+		sut := setup(t)
+		cp := createTestContactPoint()
+		cp.Settings = simplejson.NewFromAny(map[string]any{"recipient": "${vars.UNKNOWN}", "token": "token"})
+
+		_, err := sut.CreateContactPoint(ctx, 1, cp, models.ProvenanceAPI)
+		require.ErrorIs(t, err, ErrValidation)
+	})
+}
@@ -0,0 +1,70 @@
+package provisioning
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// RateLimitedAMConfigStore is an AMConfigStore that limits the rate of configuration updates of each org with a token
+// bucket, so that a misbehaving automation cannot rewrite the configuration so often that the notifier falls behind
+// reloading it. Updates over the limit fail with ErrRateLimited. Reads are not limited.
+type RateLimitedAMConfigStore struct {
+	store AMConfigStore
+	limit rate.Limit
+	burst int
+
+	mtx      sync.Mutex
+	limiters map[int64]*rate.Limiter
+}
+
+// NewRateLimitedAMConfigStore returns a store that allows each org perSecond updates per second, with bursts of up to
+// burst updates.
+func NewRateLimitedAMConfigStore(store AMConfigStore, perSecond float64, burst int) *RateLimitedAMConfigStore {
+	return &RateLimitedAMConfigStore{
+		store:    store,
+		limit:    rate.Limit(perSecond),
+		burst:    burst,
+		limiters: make(map[int64]*rate.Limiter),
+	}
+}
+
+func (s *RateLimitedAMConfigStore) GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) (*models.AlertConfiguration, error) {
+	return s.store.GetLatestAlertmanagerConfiguration(ctx, query)
+}
+
+func (s *RateLimitedAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
+	if !s.limiter(cmd.OrgID).Allow() {
+		return ErrRateLimited
+	}
+	return s.store.UpdateAlertmanagerConfiguration(ctx, cmd)
+}
+
+// UpdateAlertmanagerConfigResources is limited like UpdateAlertmanagerConfiguration. It returns
+// store.ErrIncrementalConfigUpdateUnsupported if the underlying store cannot change single resources of the
+// configuration.
+func (s *RateLimitedAMConfigStore) UpdateAlertmanagerConfigResources(ctx context.Context, cmd *models.UpdateAlertmanagerConfigResourcesCmd) error {
+	resourceStore, ok := s.store.(AMConfigResourceStore)
+	if !ok {
+		return store.ErrIncrementalConfigUpdateUnsupported
+	}
+	if !s.limiter(cmd.OrgID).Allow() {
+		return ErrRateLimited
+	}
+	return resourceStore.UpdateAlertmanagerConfigResources(ctx, cmd)
+}
+
+func (s *RateLimitedAMConfigStore) limiter(orgID int64) *rate.Limiter {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	l, ok := s.limiters[orgID]
+	if !ok {
+		l = rate.NewLimiter(s.limit, s.burst)
+		s.limiters[orgID] = l
+	}
+	return l
+}
@@ -0,0 +1,47 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestRateLimitedAMConfigStore(t *testing.T) {
+	update := func(sut AMConfigStore, orgID int64) error {
+		return sut.UpdateAlertmanagerConfiguration(context.Background(), &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: defaultAlertmanagerConfigJSON,
+			OrgID:                     orgID,
+		})
+	}
+
+	t.Run("updates over the burst are rejected", func(t *testing.T) {
+		counting := &countingAMConfigStore{AMConfigStore: newFakeAMConfigStore(defaultAlertmanagerConfigJSON)}
+		sut := NewRateLimitedAMConfigStore(counting, 0.001, 2)
+
+		require.NoError(t, update(sut, 1))
+		require.NoError(t, update(sut, 1))
+		require.ErrorIs(t, update(sut, 1), ErrRateLimited)
+		require.Equal(t, 2, counting.saves)
+	})
+
+	t.Run("orgs are limited separately", func(t *testing.T) {
+		sut := NewRateLimitedAMConfigStore(newFakeAMConfigStore(defaultAlertmanagerConfigJSON), 0.001, 1)
+
+		require.NoError(t, update(sut, 1))
+		require.ErrorIs(t, update(sut, 1), ErrRateLimited)
+		require.NoError(t, update(sut, 2))
+	})
+
+	t.Run("reads are not limited", func(t *testing.T) {
+		sut := NewRateLimitedAMConfigStore(newFakeAMConfigStore(defaultAlertmanagerConfigJSON), 0.001, 1)
+		require.NoError(t, update(sut, 1))
+
+		for i := 0; i < 3; i++ {
+			_, err := sut.GetLatestAlertmanagerConfiguration(context.Background(), &models.GetLatestAlertmanagerConfigurationQuery{OrgID: 1})
+			require.NoError(t, err)
+		}
+	})
+}
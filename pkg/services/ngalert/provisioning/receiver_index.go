@@ -0,0 +1,331 @@
+package provisioning
+
+import (
+	"sort"
+
+	"github.com/prometheus/alertmanager/config"
+
+	apimodels "github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// receiverLocation points at a Grafana-managed receiver and the receiver group it belongs to.
+type receiverLocation struct {
+	group    *apimodels.PostableApiReceiver
+	receiver *apimodels.PostableGrafanaReceiver
+}
+
+// receiverIndex provides constant-time lookups of receiver groups by name and of Grafana-managed receivers by UID.
+// It is built once per loaded configuration and must be kept up to date by every operation that modifies the
+// receivers of that configuration, which is why those modifications should go through the index.
+type receiverIndex struct {
+	cfg *apimodels.PostableUserConfig
+	// groupsByName holds the first receiver group with a given name, in configuration order.
+	groupsByName map[string]*apimodels.PostableApiReceiver
+	byUID        map[string]receiverLocation
+	// byName holds the Grafana-managed receivers by their own name and UID. It is kept up to date together with byUID.
+	byName map[string]map[string]*apimodels.PostableGrafanaReceiver
+	// changed are the receiver groups that were changed through the index, with the name they had when they were first
+	// changed. Groups that were added through the index have an empty name.
+	changed map[*apimodels.PostableApiReceiver]string
+	// removed are the receiver groups that were removed through the index.
+	removed map[*apimodels.PostableApiReceiver]struct{}
+	// routesChanged is set if routes were changed to follow a renamed receiver group.
+	routesChanged bool
+}
+
+func newReceiverIndex(cfg *apimodels.PostableUserConfig) *receiverIndex {
+	idx := &receiverIndex{
+		cfg:          cfg,
+		groupsByName: make(map[string]*apimodels.PostableApiReceiver, len(cfg.AlertmanagerConfig.Receivers)),
+		byUID:        make(map[string]receiverLocation),
+		byName:       make(map[string]map[string]*apimodels.PostableGrafanaReceiver),
+		changed:      make(map[*apimodels.PostableApiReceiver]string),
+		removed:      make(map[*apimodels.PostableApiReceiver]struct{}),
+	}
+	for _, group := range cfg.AlertmanagerConfig.Receivers {
+		if _, ok := idx.groupsByName[group.Name]; !ok {
+			idx.groupsByName[group.Name] = group
+		}
+		for _, receiver := range group.GrafanaManagedReceivers {
+			idx.index(group, receiver)
+		}
+	}
+	return idx
+}
+
+// index adds the receiver to the lookups by UID and by name, replacing any receiver with the same UID.
+func (idx *receiverIndex) index(group *apimodels.PostableApiReceiver, receiver *apimodels.PostableGrafanaReceiver) {
+	idx.unindex(receiver.UID)
+	idx.byUID[receiver.UID] = receiverLocation{group: group, receiver: receiver}
+	named, ok := idx.byName[receiver.Name]
+	if !ok {
+		named = make(map[string]*apimodels.PostableGrafanaReceiver)
+		idx.byName[receiver.Name] = named
+	}
+	named[receiver.UID] = receiver
+}
+
+// unindex removes the receiver with the given UID from the lookups by UID and by name.
+func (idx *receiverIndex) unindex(uid string) {
+	loc, ok := idx.byUID[uid]
+	if !ok {
+		return
+	}
+	delete(idx.byUID, uid)
+	if named := idx.byName[loc.receiver.Name]; named[uid] == loc.receiver {
+		delete(named, uid)
+		if len(named) == 0 {
+			delete(idx.byName, loc.receiver.Name)
+		}
+	}
+}
+
+// group returns the receiver group with the given name.
+func (idx *receiverIndex) group(name string) (*apimodels.PostableApiReceiver, bool) {
+	g, ok := idx.groupsByName[name]
+	return g, ok
+}
+
+// receiver returns the Grafana-managed receiver with the given UID and the group it belongs to.
+func (idx *receiverIndex) receiver(uid string) (receiverLocation, bool) {
+	loc, ok := idx.byUID[uid]
+	return loc, ok
+}
+
+// all returns all Grafana-managed receivers, one per UID, in no particular order.
+func (idx *receiverIndex) all() []*apimodels.PostableGrafanaReceiver {
+	result := make([]*apimodels.PostableGrafanaReceiver, 0, len(idx.byUID))
+	for _, loc := range idx.byUID {
+		result = append(result, loc.receiver)
+	}
+	return result
+}
+
+// namedReceivers returns the Grafana-managed receivers with the given name, one per UID. The name of a receiver is
+// matched rather than the name of its group, since the two can be out of sync in an inconsistent configuration.
+func (idx *receiverIndex) namedReceivers(name string) []*apimodels.PostableGrafanaReceiver {
+	named := idx.byName[name]
+	if len(named) == 0 {
+		return nil
+	}
+	result := make([]*apimodels.PostableGrafanaReceiver, 0, len(named))
+	for _, receiver := range named {
+		result = append(result, receiver)
+	}
+	return result
+}
+
+// groupNames returns the set of names of all receiver groups.
+func (idx *receiverIndex) groupNames() map[string]struct{} {
+	result := make(map[string]struct{}, len(idx.groupsByName))
+	for name := range idx.groupsByName {
+		result[name] = struct{}{}
+	}
+	return result
+}
+
+// add appends a receiver to the group with the receiver's name, creating the group if it does not exist yet.
+func (idx *receiverIndex) add(receiver *apimodels.PostableGrafanaReceiver) {
+	g, ok := idx.groupsByName[receiver.Name]
+	if !ok {
+		g = &apimodels.PostableApiReceiver{
+			Receiver: config.Receiver{
+				Name: receiver.Name,
+			},
+		}
+		idx.cfg.AlertmanagerConfig.Receivers = append(idx.cfg.AlertmanagerConfig.Receivers, g)
+		idx.groupsByName[receiver.Name] = g
+		idx.changed[g] = ""
+	}
+	idx.touch(g)
+	g.GrafanaManagedReceivers = append(g.GrafanaManagedReceivers, receiver)
+	idx.index(g, receiver)
+}
+
+// replace swaps the receiver that has the same UID as the given one in place.
+func (idx *receiverIndex) replace(receiver *apimodels.PostableGrafanaReceiver) bool {
+	loc, ok := idx.byUID[receiver.UID]
+	if !ok {
+		return false
+	}
+	idx.touch(loc.group)
+	for i, r := range loc.group.GrafanaManagedReceivers {
+		if r == loc.receiver {
+			loc.group.GrafanaManagedReceivers[i] = receiver
+			break
+		}
+	}
+	idx.index(loc.group, receiver)
+	return true
+}
+
+// remove drops the receiver with the given UID from its group.
+// It returns the removed receiver and whether the group became empty and was removed as well.
+func (idx *receiverIndex) remove(uid string) (*apimodels.PostableGrafanaReceiver, bool) {
+	loc, ok := idx.byUID[uid]
+	if !ok {
+		return nil, false
+	}
+	idx.unindex(uid)
+	g := loc.group
+	idx.touch(g)
+	for i, r := range g.GrafanaManagedReceivers {
+		if r == loc.receiver {
+			g.GrafanaManagedReceivers = append(g.GrafanaManagedReceivers[:i], g.GrafanaManagedReceivers[i+1:]...)
+			break
+		}
+	}
+	if len(g.GrafanaManagedReceivers) > 0 {
+		return loc.receiver, false
+	}
+	idx.removeGroup(g)
+	return loc.receiver, true
+}
+
+// positions returns the position of every Grafana-managed receiver within its group, by UID.
+func (idx *receiverIndex) positions() map[string]int {
+	result := make(map[string]int, len(idx.byUID))
+	for uid, loc := range idx.byUID {
+		for i, r := range loc.group.GrafanaManagedReceivers {
+			if r == loc.receiver {
+				result[uid] = i
+				break
+			}
+		}
+	}
+	return result
+}
+
+// position returns the position of the receiver with the given UID within its group.
+func (idx *receiverIndex) position(uid string) (int, bool) {
+	loc, ok := idx.byUID[uid]
+	if !ok {
+		return 0, false
+	}
+	for i, r := range loc.group.GrafanaManagedReceivers {
+		if r == loc.receiver {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// move moves the receiver with the given UID to a position within its group, positions past the end of the group
+// move it last. It reports whether the order of the group changed.
+func (idx *receiverIndex) move(uid string, position int) bool {
+	current, ok := idx.position(uid)
+	if !ok {
+		return false
+	}
+	g := idx.byUID[uid].group
+	if position >= len(g.GrafanaManagedReceivers) {
+		position = len(g.GrafanaManagedReceivers) - 1
+	}
+	if position < 0 || position == current {
+		return false
+	}
+	idx.touch(g)
+	receiver := g.GrafanaManagedReceivers[current]
+	if position < current {
+		copy(g.GrafanaManagedReceivers[position+1:current+1], g.GrafanaManagedReceivers[position:current])
+	} else {
+		copy(g.GrafanaManagedReceivers[current:position], g.GrafanaManagedReceivers[current+1:position+1])
+	}
+	g.GrafanaManagedReceivers[position] = receiver
+	return true
+}
+
+// renameGroup renames a receiver group. If another group already has the new name, it keeps precedence in lookups.
+func (idx *receiverIndex) renameGroup(g *apimodels.PostableApiReceiver, name string) {
+	idx.touch(g)
+	if idx.groupsByName[g.Name] == g {
+		delete(idx.groupsByName, g.Name)
+	}
+	g.Name = name
+	if _, ok := idx.groupsByName[name]; !ok {
+		idx.groupsByName[name] = g
+	}
+}
+
+// renameReceiver changes the name of a Grafana-managed receiver, without moving it to another group.
+func (idx *receiverIndex) renameReceiver(receiver *apimodels.PostableGrafanaReceiver, name string) {
+	loc, ok := idx.byUID[receiver.UID]
+	if !ok || loc.receiver != receiver {
+		receiver.Name = name
+		return
+	}
+	idx.touch(loc.group)
+	idx.unindex(receiver.UID)
+	receiver.Name = name
+	idx.index(loc.group, receiver)
+}
+
+func (idx *receiverIndex) removeGroup(g *apimodels.PostableApiReceiver) {
+	idx.touch(g)
+	idx.removed[g] = struct{}{}
+	receivers := idx.cfg.AlertmanagerConfig.Receivers
+	for i, candidate := range receivers {
+		if candidate == g {
+			idx.cfg.AlertmanagerConfig.Receivers = append(receivers[:i], receivers[i+1:]...)
+			break
+		}
+	}
+	if idx.groupsByName[g.Name] == g {
+		delete(idx.groupsByName, g.Name)
+		// Another group might share the name in an inconsistent configuration.
+		for _, candidate := range idx.cfg.AlertmanagerConfig.Receivers {
+			if candidate.Name == g.Name {
+				idx.groupsByName[g.Name] = candidate
+				break
+			}
+		}
+	}
+}
+
+// replaceReferences points the routes that use the receiver group with the old name to the new name.
+func (idx *receiverIndex) replaceReferences(oldName, newName string) {
+	idx.routesChanged = true
+	replaceReferences(oldName, newName, idx.cfg.AlertmanagerConfig.Route)
+}
+
+// touch records that the receiver group is changed.
+func (idx *receiverIndex) touch(g *apimodels.PostableApiReceiver) {
+	if _, ok := idx.changed[g]; !ok {
+		idx.changed[g] = g.Name
+	}
+}
+
+// changes returns the changes of the receiver groups that were made through the index, deletions first and the other
+// changes in configuration order. It returns nil if the changes cannot be persisted one receiver group at a time,
+// because routes were changed as well or a group has no name.
+func (idx *receiverIndex) changes() ([]models.AlertConfigurationResourceChange, error) {
+	if idx.routesChanged {
+		return nil, nil
+	}
+	var result []models.AlertConfigurationResourceChange
+	for g := range idx.removed {
+		if name := idx.changed[g]; name != "" {
+			result = append(result, deletedResource(models.AlertConfigurationResourceReceiver, name))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	for _, g := range idx.cfg.AlertmanagerConfig.Receivers {
+		oldName, ok := idx.changed[g]
+		if !ok {
+			continue
+		}
+		if g.Name == "" {
+			return nil, nil
+		}
+		change, err := resourceChange(models.AlertConfigurationResourceReceiver, g.Name, g)
+		if err != nil {
+			return nil, err
+		}
+		if oldName != g.Name {
+			change.OldName = oldName
+		}
+		result = append(result, change)
+	}
+	return result, nil
+}
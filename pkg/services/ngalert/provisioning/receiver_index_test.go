@@ -0,0 +1,143 @@
+package provisioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestReceiverIndex(t *testing.T) {
+	t.Run("looks up receivers by uid and groups by name", func(t *testing.T) {
+		idx := newReceiverIndex(createTestConfigWithReceivers())
+
+		loc, ok := idx.receiver("ghi")
+		require.True(t, ok)
+		require.Equal(t, "receiver-2", loc.group.Name)
+		require.Equal(t, "email", loc.receiver.Type)
+
+		g, ok := idx.group("receiver-1")
+		require.True(t, ok)
+		require.Len(t, g.GrafanaManagedReceivers, 1)
+
+		_, ok = idx.receiver("does-not-exist")
+		require.False(t, ok)
+		require.Len(t, idx.all(), 4)
+		require.Len(t, idx.namedReceivers("receiver-2"), 3)
+		require.Equal(t, map[string]struct{}{"receiver-1": {}, "receiver-2": {}}, idx.groupNames())
+	})
+
+	t.Run("adding a receiver creates its group if missing", func(t *testing.T) {
+		cfg := createTestConfigWithReceivers()
+		idx := newReceiverIndex(cfg)
+
+		idx.add(&definitions.PostableGrafanaReceiver{UID: "new", Name: "receiver-3", Type: "slack"})
+		idx.add(&definitions.PostableGrafanaReceiver{UID: "new-2", Name: "receiver-1", Type: "slack"})
+
+		require.Len(t, cfg.AlertmanagerConfig.Receivers, 3)
+		require.Equal(t, "receiver-3", cfg.AlertmanagerConfig.Receivers[2].Name)
+		require.Len(t, cfg.AlertmanagerConfig.Receivers[0].GrafanaManagedReceivers, 2)
+		loc, ok := idx.receiver("new")
+		require.True(t, ok)
+		require.Equal(t, cfg.AlertmanagerConfig.Receivers[2], loc.group)
+	})
+
+	t.Run("removing the last receiver of a group removes the group", func(t *testing.T) {
+		cfg := createTestConfigWithReceivers()
+		idx := newReceiverIndex(cfg)
+
+		removed, groupRemoved := idx.remove("def")
+		require.Equal(t, "def", removed.UID)
+		require.False(t, groupRemoved)
+
+		removed, groupRemoved = idx.remove("abc")
+		require.Equal(t, "abc", removed.UID)
+		require.True(t, groupRemoved)
+		require.Len(t, cfg.AlertmanagerConfig.Receivers, 1)
+		_, ok := idx.group("receiver-1")
+		require.False(t, ok)
+	})
+
+	t.Run("receivers are looked up by name after they are changed", func(t *testing.T) {
+		idx := newReceiverIndex(createTestConfigWithReceivers())
+
+		idx.add(&definitions.PostableGrafanaReceiver{UID: "new", Name: "receiver-2", Type: "slack"})
+		require.Len(t, idx.namedReceivers("receiver-2"), 4)
+
+		idx.remove("new")
+		require.Len(t, idx.namedReceivers("receiver-2"), 3)
+
+		idx.replace(&definitions.PostableGrafanaReceiver{UID: "ghi", Name: "receiver-3", Type: "email"})
+		require.Len(t, idx.namedReceivers("receiver-2"), 2)
+		require.Len(t, idx.namedReceivers("receiver-3"), 1)
+
+		loc, _ := idx.receiver("abc")
+		idx.renameReceiver(loc.receiver, "receiver-4")
+		require.Empty(t, idx.namedReceivers("receiver-1"))
+		require.Equal(t, []*definitions.PostableGrafanaReceiver{loc.receiver}, idx.namedReceivers("receiver-4"))
+	})
+
+	t.Run("moves receivers within their group", func(t *testing.T) {
+		cfg := createTestConfigWithReceivers()
+		idx := newReceiverIndex(cfg)
+		uids := func() []string {
+			g, _ := idx.group("receiver-2")
+			result := []string{}
+			for _, r := range g.GrafanaManagedReceivers {
+				result = append(result, r.UID)
+			}
+			return result
+		}
+
+		require.True(t, idx.move("jkl", 0))
+		require.Equal(t, []string{"jkl", "def", "ghi"}, uids())
+		require.True(t, idx.move("jkl", 10))
+		require.Equal(t, []string{"def", "ghi", "jkl"}, uids())
+		require.False(t, idx.move("jkl", 2))
+		require.False(t, idx.move("does-not-exist", 0))
+
+		position, ok := idx.position("ghi")
+		require.True(t, ok)
+		require.Equal(t, 1, position)
+		require.Equal(t, map[string]int{"abc": 0, "def": 0, "ghi": 1, "jkl": 2}, idx.positions())
+
+		changes, err := idx.changes()
+		require.NoError(t, err)
+		require.Len(t, changes, 1)
+		require.Equal(t, "receiver-2", changes[0].Name)
+	})
+
+	t.Run("reports the changed receiver groups", func(t *testing.T) {
+		cfg := createTestConfigWithReceivers()
+		idx := newReceiverIndex(cfg)
+
+		idx.remove("abc")
+		idx.add(&definitions.PostableGrafanaReceiver{UID: "new", Name: "receiver-3", Type: "slack"})
+		g, _ := idx.group("receiver-2")
+		idx.renameGroup(g, "receiver-4")
+
+		changes, err := idx.changes()
+		require.NoError(t, err)
+		require.Len(t, changes, 3)
+		require.Equal(t, deletedResource(models.AlertConfigurationResourceReceiver, "receiver-1"), changes[0])
+		require.Equal(t, "receiver-4", changes[1].Name)
+		require.Equal(t, "receiver-2", changes[1].OldName)
+		require.Equal(t, "receiver-3", changes[2].Name)
+		require.Empty(t, changes[2].OldName)
+		require.Contains(t, changes[2].Content, `"uid":"new"`)
+	})
+
+	t.Run("reports no changes if routes were changed as well", func(t *testing.T) {
+		idx := newReceiverIndex(createTestConfigWithReceivers())
+
+		g, _ := idx.group("receiver-1")
+		idx.replaceReferences(g.Name, "receiver-3")
+		idx.renameGroup(g, "receiver-3")
+
+		changes, err := idx.changes()
+		require.NoError(t, err)
+		require.Nil(t, changes)
+	})
+}
@@ -0,0 +1,509 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/util"
+)
+
+const (
+	silencesKey = "silences"
+	// silencesToExpireKey holds the IDs of the Alertmanager silences of deleted provisioned silences that could not
+	// be expired yet.
+	silencesToExpireKey = "silences_to_expire"
+)
+
+// SilenceService manages provisioned silences, for example for planned maintenance. The provisioned silences are kept
+// in the key-value store, per organization and keyed by UID, and applied to the Alertmanager of the organization. They
+// are applied right away if the service has access to the Alertmanagers, and by SyncSilences otherwise, which also
+// creates silences again that were expired or changed in the Alertmanager.
+type SilenceService struct {
+	silences AlertmanagerSilences
+	prov     ProvisioningStore
+	kv       kvstore.KVStore
+	xact     TransactionManager
+	log      log.Logger
+	tracer   tracing.Tracer
+	metrics  *metrics.Provisioning
+}
+
+// NewSilenceService returns a SilenceService. If silences is nil, the provisioned silences are only applied to the
+// Alertmanagers by the SyncSilences of a service that has access to them.
+func NewSilenceService(silences AlertmanagerSilences, prov ProvisioningStore, kv kvstore.KVStore, xact TransactionManager,
+	log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *SilenceService {
+	return &SilenceService{
+		silences: silences,
+		prov:     prov,
+		kv:       kv,
+		xact:     xact,
+		log:      log,
+		tracer:   tracer,
+		metrics:  m,
+	}
+}
+
+// GetSilences returns the provisioned silences of the organization, sorted by UID.
+func (svc *SilenceService) GetSilences(ctx context.Context, orgID int64) (_ []definitions.ProvisionedSilence, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "silence", "GetSilences", orgID)
+	defer func() { done(err) }()
+	silences, err := svc.getSilences(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	result := make([]definitions.ProvisionedSilence, 0, len(silences))
+	for _, s := range silences {
+		s.Status = s.StatusAt(now)
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UID < result[j].UID
+	})
+	return result, nil
+}
+
+// GetSilence returns the provisioned silence with the given UID.
+func (svc *SilenceService) GetSilence(ctx context.Context, orgID int64, uid string) (_ definitions.ProvisionedSilence, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "silence", "GetSilence", orgID,
+		attribute.String("silence_uid", uid))
+	defer func() { done(err) }()
+	silences, err := svc.getSilences(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	s, ok := silences[uid]
+	if !ok {
+		return definitions.ProvisionedSilence{}, newNotFoundError((&definitions.ProvisionedSilence{}).ResourceType(), uid, "silence '%s' does not exist", uid)
+	}
+	s.Status = s.StatusAt(time.Now())
+	return s, nil
+}
+
+// CreateSilence creates a provisioned silence and applies it to the Alertmanager of the organization. The created
+// silence is returned.
+func (svc *SilenceService) CreateSilence(ctx context.Context, orgID int64, s definitions.ProvisionedSilence, p models.Provenance) (_ definitions.ProvisionedSilence, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "silence", "CreateSilence", orgID,
+		attribute.String("silence_uid", s.UID))
+	defer func() { done(err) }()
+	now := time.Now()
+	if err := validateSilence(s, now); err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	if s.UID == "" {
+		s.UID = util.GenerateShortUID()
+	} else if !util.IsValidShortUID(s.UID) || util.IsShortUIDTooLong(s.UID) {
+		return definitions.ProvisionedSilence{}, newValidationError("uid", "invalid silence UID '%s'", s.UID)
+	}
+	silences, err := svc.getSilences(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	if _, ok := silences[s.UID]; ok {
+		return definitions.ProvisionedSilence{}, newValidationError("uid", "a silence with this UID already exists").withResource(s.ResourceType(), s.UID)
+	}
+
+	s.SilenceID = ""
+	s.Status = ""
+	s.Provenance = definitions.Provenance(p)
+	if err := svc.applyNow(ctx, orgID, &s, now); err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	silences[s.UID] = s
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.setSilences(ctx, orgID, silences); err != nil {
+			return err
+		}
+		if err := svc.prov.SetProvenance(ctx, &s, orgID, p); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionCreate, &s, p, nil, s)
+	})
+	if err != nil {
+		svc.expireApplied(ctx, orgID, s.SilenceID, "")
+		return definitions.ProvisionedSilence{}, err
+	}
+	s.Status = s.StatusAt(now)
+	return s, nil
+}
+
+// UpdateSilence replaces the provisioned silence with the UID of the given silence, and applies the change to the
+// Alertmanager of the organization. The Alertmanager may replace its silence by a silence with a new ID. The updated
+// silence is returned.
+func (svc *SilenceService) UpdateSilence(ctx context.Context, orgID int64, s definitions.ProvisionedSilence, p models.Provenance) (_ definitions.ProvisionedSilence, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "silence", "UpdateSilence", orgID,
+		attribute.String("silence_uid", s.UID))
+	defer func() { done(err) }()
+	now := time.Now()
+	if err := validateSilence(s, now); err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	silences, err := svc.getSilences(ctx, orgID)
+	if err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	old, ok := silences[s.UID]
+	if !ok {
+		return definitions.ProvisionedSilence{}, newNotFoundError(s.ResourceType(), s.UID, "silence '%s' does not exist", s.UID)
+	}
+	if stored := models.Provenance(old.Provenance); stored != p && stored != models.ProvenanceNone {
+		return definitions.ProvisionedSilence{}, newValidationError("provenance", "cannot change provenance from '%s' to '%s'", stored, p).withResource(s.ResourceType(), s.UID)
+	}
+
+	s.SilenceID = old.SilenceID
+	s.Status = ""
+	s.Provenance = definitions.Provenance(p)
+	if err := svc.applyNow(ctx, orgID, &s, now); err != nil {
+		return definitions.ProvisionedSilence{}, err
+	}
+	silences[s.UID] = s
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.setSilences(ctx, orgID, silences); err != nil {
+			return err
+		}
+		if err := svc.prov.SetProvenance(ctx, &s, orgID, p); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionUpdate, &s, p, old, s)
+	})
+	if err != nil {
+		svc.expireApplied(ctx, orgID, s.SilenceID, old.SilenceID)
+		return definitions.ProvisionedSilence{}, err
+	}
+	s.Status = s.StatusAt(now)
+	return s, nil
+}
+
+// DeleteSilence deletes the provisioned silence with the given UID and expires its silence in the Alertmanager.
+func (svc *SilenceService) DeleteSilence(ctx context.Context, orgID int64, uid string) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "silence", "DeleteSilence", orgID,
+		attribute.String("silence_uid", uid))
+	defer func() { done(err) }()
+	silences, err := svc.getSilences(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	old, ok := silences[uid]
+	if !ok {
+		return newNotFoundError((&definitions.ProvisionedSilence{}).ResourceType(), uid, "silence '%s' does not exist", uid)
+	}
+	delete(silences, uid)
+	toExpire, err := svc.getSilencesToExpire(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if old.SilenceID != "" {
+		toExpire = append(toExpire, old.SilenceID)
+	}
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.setSilences(ctx, orgID, silences); err != nil {
+			return err
+		}
+		if err := svc.setSilencesToExpire(ctx, orgID, toExpire); err != nil {
+			return err
+		}
+		if err := svc.prov.DeleteProvenance(ctx, &old, orgID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionDelete, &old, models.Provenance(old.Provenance), old, nil)
+	})
+	if err != nil {
+		return err
+	}
+	if svc.silences != nil && old.SilenceID != "" {
+		// The silence stays in the list of silences to expire if this fails, for SyncSilences to expire it.
+		if err := svc.syncOrg(ctx, orgID, time.Now()); err != nil {
+			svc.log.FromContext(ctx).Warn("Failed to expire the silence of a deleted provisioned silence", "uid", uid, "silenceID", old.SilenceID, "error", err)
+		}
+	}
+	return nil
+}
+
+// SyncSilences applies the provisioned silences of all organizations to their Alertmanagers. Silences that are missing
+// in the Alertmanager, or were changed or expired there, are created again, and the silences of deleted provisioned
+// silences are expired. Provisioned silences that ended at the given time are removed.
+func (svc *SilenceService) SyncSilences(ctx context.Context, now time.Time) error {
+	if svc.silences == nil {
+		return nil
+	}
+	all, err := svc.kv.GetAll(ctx, kvstore.AllOrganizations, fileProvisioningStatusNamespace)
+	if err != nil {
+		return err
+	}
+	orgIDs := make([]int64, 0, len(all))
+	for orgID, values := range all {
+		_, hasSilences := values[silencesKey]
+		_, hasSilencesToExpire := values[silencesToExpireKey]
+		if hasSilences || hasSilencesToExpire {
+			orgIDs = append(orgIDs, orgID)
+		}
+	}
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+	var errs []error
+	for _, orgID := range orgIDs {
+		if err := svc.syncOrg(ctx, orgID, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to synchronize the silences of organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (svc *SilenceService) syncOrg(ctx context.Context, orgID int64, now time.Time) (err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "silence", "SyncSilences", orgID)
+	defer func() { done(err) }()
+	silences, err := svc.getSilences(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	toExpire, err := svc.getSilencesToExpire(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	remaining := make([]string, 0, len(toExpire))
+	for _, id := range toExpire {
+		if err := svc.silences.DeleteSilence(ctx, orgID, id); err != nil && !errors.Is(err, alertingNotify.ErrSilenceNotFound) {
+			errs = append(errs, fmt.Errorf("failed to expire silence %s: %w", id, err))
+			remaining = append(remaining, id)
+		}
+	}
+	uids := make([]string, 0, len(silences))
+	for uid := range silences {
+		uids = append(uids, uid)
+	}
+	sort.Strings(uids)
+	var ended []definitions.ProvisionedSilence
+	changed := len(remaining) != len(toExpire)
+	for _, uid := range uids {
+		s := silences[uid]
+		if !s.EndsAt.After(now) {
+			ended = append(ended, s)
+			delete(silences, uid)
+			changed = true
+			continue
+		}
+		previousID := s.SilenceID
+		if err := svc.applySilence(ctx, orgID, &s, now); err != nil {
+			errs = append(errs, fmt.Errorf("failed to apply silence '%s': %w", uid, err))
+			continue
+		}
+		if s.SilenceID != previousID {
+			silences[uid] = s
+			changed = true
+		}
+	}
+	if !changed {
+		return errors.Join(errs...)
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if err := svc.setSilences(ctx, orgID, silences); err != nil {
+			return err
+		}
+		if err := svc.setSilencesToExpire(ctx, orgID, remaining); err != nil {
+			return err
+		}
+		for i := range ended {
+			s := ended[i]
+			if err := svc.prov.DeleteProvenance(ctx, &s, orgID); err != nil {
+				return err
+			}
+			if err := recordAudit(ctx, svc.prov, orgID, models.ProvisioningAuditActionExpire, &s, models.Provenance(s.Provenance), s, nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// applyNow applies the silence to the Alertmanager if the service has access to the Alertmanagers. Silences the
+// Alertmanager rejects are invalid. Other failures are left for SyncSilences to retry.
+func (svc *SilenceService) applyNow(ctx context.Context, orgID int64, s *definitions.ProvisionedSilence, now time.Time) error {
+	if svc.silences == nil {
+		return nil
+	}
+	err := svc.applySilence(ctx, orgID, s, now)
+	if errors.Is(err, alertingNotify.ErrCreateSilenceBadPayload) {
+		return newValidationError("", "%s", err.Error()).withResource(s.ResourceType(), s.UID)
+	}
+	if err != nil {
+		svc.log.FromContext(ctx).Warn("Failed to apply the provisioned silence to the Alertmanager, it is applied later", "uid", s.UID, "error", err)
+	}
+	return nil
+}
+
+// applySilence makes sure that the Alertmanager of the organization has an unexpired silence like the provisioned
+// one, and sets the ID of that silence.
+func (svc *SilenceService) applySilence(ctx context.Context, orgID int64, s *definitions.ProvisionedSilence, now time.Time) error {
+	id := s.SilenceID
+	if id != "" {
+		existing, err := svc.silences.GetSilence(ctx, orgID, id)
+		switch {
+		case err == nil:
+			if silenceApplied(existing, *s, now) {
+				return nil
+			}
+		case errors.Is(err, alertingNotify.ErrSilenceNotFound):
+			id = ""
+		default:
+			return err
+		}
+	}
+	newID, err := svc.silences.CreateSilence(ctx, orgID, postableSilence(*s, id))
+	if err != nil {
+		return err
+	}
+	s.SilenceID = newID
+	return nil
+}
+
+// expireApplied expires the silence that was applied for a change that could not be saved, unless the change
+// updated the previous silence in place.
+func (svc *SilenceService) expireApplied(ctx context.Context, orgID int64, id, previousID string) {
+	if svc.silences == nil || id == "" || id == previousID {
+		return
+	}
+	if err := svc.silences.DeleteSilence(ctx, orgID, id); err != nil && !errors.Is(err, alertingNotify.ErrSilenceNotFound) {
+		svc.log.FromContext(ctx).Warn("Failed to expire the silence of a provisioned silence that was not saved", "silenceID", id, "error", err)
+	}
+}
+
+// silenceApplied reports whether the Alertmanager silence is unexpired and like the provisioned silence. The
+// Alertmanager moves the start of silences that start in the past to when they are created.
+func silenceApplied(existing definitions.GettableSilence, s definitions.ProvisionedSilence, now time.Time) bool {
+	if existing.Status == nil || existing.Status.State == nil || *existing.Status.State == definitions.ProvisionedSilenceExpired {
+		return false
+	}
+	if existing.StartsAt == nil || existing.EndsAt == nil || existing.Comment == nil || existing.CreatedBy == nil {
+		return false
+	}
+	startsAt := time.Time(*existing.StartsAt)
+	if !startsAt.Equal(s.StartsAt) && (startsAt.Before(s.StartsAt) || startsAt.After(now)) {
+		return false
+	}
+	if !time.Time(*existing.EndsAt).Equal(s.EndsAt) || *existing.Comment != s.Comment || *existing.CreatedBy != s.CreatedBy {
+		return false
+	}
+	matchers := silenceMatchers(s.Matchers)
+	if len(existing.Matchers) != len(matchers) {
+		return false
+	}
+	for i, m := range existing.Matchers {
+		if m == nil || m.Name == nil || m.Value == nil || *m.Name != *matchers[i].Name || *m.Value != *matchers[i].Value ||
+			isTrue(m.IsEqual) != isTrue(matchers[i].IsEqual) || isTrue(m.IsRegex) != isTrue(matchers[i].IsRegex) {
+			return false
+		}
+	}
+	return true
+}
+
+func postableSilence(s definitions.ProvisionedSilence, id string) *definitions.PostableSilence {
+	startsAt, endsAt := strfmt.DateTime(s.StartsAt), strfmt.DateTime(s.EndsAt)
+	comment, createdBy := s.Comment, s.CreatedBy
+	return &definitions.PostableSilence{
+		ID: id,
+		Silence: amv2.Silence{
+			Matchers:  silenceMatchers(s.Matchers),
+			StartsAt:  &startsAt,
+			EndsAt:    &endsAt,
+			Comment:   &comment,
+			CreatedBy: &createdBy,
+		},
+	}
+}
+
+func silenceMatchers(matchers definitions.ObjectMatchers) amv2.Matchers {
+	result := make(amv2.Matchers, 0, len(matchers))
+	for _, m := range matchers {
+		name, value := m.Name, m.Value
+		isEqual := m.Type == labels.MatchEqual || m.Type == labels.MatchRegexp
+		isRegex := m.Type == labels.MatchRegexp || m.Type == labels.MatchNotRegexp
+		result = append(result, &amv2.Matcher{Name: &name, Value: &value, IsEqual: &isEqual, IsRegex: &isRegex})
+	}
+	return result
+}
+
+func isTrue(b *bool) bool {
+	return b != nil && *b
+}
+
+// validateSilence checks the silence, which must end in the future.
+func validateSilence(s definitions.ProvisionedSilence, now time.Time) error {
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrValidation, err.Error())
+	}
+	if !s.EndsAt.After(now) {
+		return newValidationError("endsAt", "the silence must end in the future")
+	}
+	return nil
+}
+
+func (svc *SilenceService) getSilences(ctx context.Context, orgID int64) (map[string]definitions.ProvisionedSilence, error) {
+	value, ok, err := svc.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, silencesKey)
+	if err != nil {
+		return nil, err
+	}
+	silences := map[string]definitions.ProvisionedSilence{}
+	if !ok {
+		return silences, nil
+	}
+	if err := json.Unmarshal([]byte(value), &silences); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal silences: %w", err)
+	}
+	return silences, nil
+}
+
+func (svc *SilenceService) setSilences(ctx context.Context, orgID int64, silences map[string]definitions.ProvisionedSilence) error {
+	if len(silences) == 0 {
+		return svc.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, silencesKey)
+	}
+	data, err := json.Marshal(silences)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, silencesKey, string(data))
+}
+
+func (svc *SilenceService) getSilencesToExpire(ctx context.Context, orgID int64) ([]string, error) {
+	value, ok, err := svc.kv.Get(ctx, orgID, fileProvisioningStatusNamespace, silencesToExpireKey)
+	if err != nil || !ok {
+		return nil, err
+	}
+	var ids []string
+	if err := json.Unmarshal([]byte(value), &ids); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal silences to expire: %w", err)
+	}
+	return ids, nil
+}
+
+func (svc *SilenceService) setSilencesToExpire(ctx context.Context, orgID int64, ids []string) error {
+	if len(ids) == 0 {
+		return svc.kv.Del(ctx, orgID, fileProvisioningStatusNamespace, silencesToExpireKey)
+	}
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	return svc.kv.Set(ctx, orgID, fileProvisioningStatusNamespace, silencesToExpireKey, string(data))
+}
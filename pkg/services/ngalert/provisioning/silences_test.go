@@ -0,0 +1,232 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/pkg/labels"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/kvstore"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// fakeAlertmanagerSilences keeps the silences of all organizations by ID. Changed silences get a new ID.
+type fakeAlertmanagerSilences struct {
+	silences map[string]definitions.GettableSilence
+	created  int
+}
+
+func newFakeAlertmanagerSilences() *fakeAlertmanagerSilences {
+	return &fakeAlertmanagerSilences{silences: map[string]definitions.GettableSilence{}}
+}
+
+func (f *fakeAlertmanagerSilences) GetSilence(_ context.Context, _ int64, silenceID string) (definitions.GettableSilence, error) {
+	s, ok := f.silences[silenceID]
+	if !ok {
+		return definitions.GettableSilence{}, alertingNotify.ErrSilenceNotFound
+	}
+	return s, nil
+}
+
+func (f *fakeAlertmanagerSilences) CreateSilence(_ context.Context, _ int64, ps *definitions.PostableSilence) (string, error) {
+	if ps.ID != "" {
+		if err := f.expire(ps.ID); err != nil {
+			return "", err
+		}
+	}
+	f.created++
+	id := fmt.Sprintf("silence-%d", f.created)
+	state := definitions.ProvisionedSilenceActive
+	f.silences[id] = definitions.GettableSilence{
+		ID:      &id,
+		Status:  &amv2.SilenceStatus{State: &state},
+		Silence: ps.Silence,
+	}
+	return id, nil
+}
+
+func (f *fakeAlertmanagerSilences) DeleteSilence(_ context.Context, _ int64, silenceID string) error {
+	return f.expire(silenceID)
+}
+
+func (f *fakeAlertmanagerSilences) expire(silenceID string) error {
+	s, ok := f.silences[silenceID]
+	if !ok {
+		return alertingNotify.ErrSilenceNotFound
+	}
+	state := definitions.ProvisionedSilenceExpired
+	s.Status = &amv2.SilenceStatus{State: &state}
+	f.silences[silenceID] = s
+	return nil
+}
+
+func (f *fakeAlertmanagerSilences) active() []string {
+	var ids []string
+	for id, s := range f.silences {
+		if *s.Status.State != definitions.ProvisionedSilenceExpired {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func TestSilenceService(t *testing.T) {
+	ctx := context.Background()
+	teamA, err := labels.NewMatcher(labels.MatchEqual, "team", "a")
+	require.NoError(t, err)
+	now := time.Now()
+	silence := func() definitions.ProvisionedSilence {
+		return definitions.ProvisionedSilence{
+			UID:       "maintenance",
+			Matchers:  definitions.ObjectMatchers{teamA},
+			StartsAt:  now,
+			EndsAt:    now.Add(time.Hour),
+			Comment:   "database upgrade",
+			CreatedBy: "ops",
+		}
+	}
+
+	t.Run("created silence is applied to the Alertmanager", func(t *testing.T) {
+		sut, ams := createSilenceServiceSut(t)
+
+		created, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{created.SilenceID}, ams.active())
+		require.Equal(t, definitions.ProvisionedSilenceActive, created.Status)
+		silences, err := sut.GetSilences(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, silences, 1)
+		require.Equal(t, definitions.Provenance(models.ProvenanceAPI), silences[0].Provenance)
+	})
+
+	t.Run("creating a silence with an existing UID fails", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(t)
+		_, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		_, err = sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("updated silence replaces the silence in the Alertmanager", func(t *testing.T) {
+		sut, ams := createSilenceServiceSut(t)
+		created, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		s := silence()
+		s.EndsAt = now.Add(2 * time.Hour)
+		updated, err := sut.UpdateSilence(ctx, 1, s, models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NotEqual(t, created.SilenceID, updated.SilenceID)
+		require.Equal(t, []string{updated.SilenceID}, ams.active())
+	})
+
+	t.Run("provenance of a silence cannot be changed", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(t)
+		_, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceFile)
+		require.NoError(t, err)
+
+		_, err = sut.UpdateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+
+		require.ErrorIs(t, err, ErrValidation)
+	})
+
+	t.Run("deleted silence is expired in the Alertmanager", func(t *testing.T) {
+		sut, ams := createSilenceServiceSut(t)
+		_, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.DeleteSilence(ctx, 1, "maintenance"))
+
+		require.Empty(t, ams.active())
+		_, err = sut.GetSilence(ctx, 1, "maintenance")
+		require.ErrorIs(t, err, ErrNotFound)
+		require.ErrorIs(t, sut.DeleteSilence(ctx, 1, "maintenance"), ErrNotFound)
+	})
+
+	t.Run("silences saved without the Alertmanager are applied by the synchronization", func(t *testing.T) {
+		sut, ams := createSilenceServiceSut(t)
+		offline := *sut
+		offline.silences = nil
+		_, err := offline.CreateSilence(ctx, 1, silence(), models.ProvenanceFile)
+		require.NoError(t, err)
+		require.Empty(t, ams.active())
+
+		require.NoError(t, sut.SyncSilences(ctx, now))
+
+		s, err := sut.GetSilence(ctx, 1, "maintenance")
+		require.NoError(t, err)
+		require.Equal(t, []string{s.SilenceID}, ams.active())
+	})
+
+	t.Run("synchronization creates silences expired in the Alertmanager again", func(t *testing.T) {
+		sut, ams := createSilenceServiceSut(t)
+		created, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+		require.NoError(t, err)
+		require.NoError(t, ams.expire(created.SilenceID))
+
+		require.NoError(t, sut.SyncSilences(ctx, now))
+
+		s, err := sut.GetSilence(ctx, 1, "maintenance")
+		require.NoError(t, err)
+		require.NotEqual(t, created.SilenceID, s.SilenceID)
+		require.Equal(t, []string{s.SilenceID}, ams.active())
+	})
+
+	t.Run("synchronization removes silences that ended", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(t)
+		_, err := sut.CreateSilence(ctx, 1, silence(), models.ProvenanceAPI)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.SyncSilences(ctx, now.Add(time.Hour)))
+
+		silences, err := sut.GetSilences(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, silences)
+		entries := sut.prov.(*fakeProvisioningStore).auditEntries
+		require.Equal(t, models.ProvisioningAuditActionExpire, entries[len(entries)-1].Action)
+	})
+
+	t.Run("invalid silences are rejected", func(t *testing.T) {
+		sut, _ := createSilenceServiceSut(t)
+		testCases := map[string]func(s *definitions.ProvisionedSilence){
+			"no matchers":      func(s *definitions.ProvisionedSilence) { s.Matchers = nil },
+			"end before start": func(s *definitions.ProvisionedSilence) { s.EndsAt = s.StartsAt.Add(-time.Minute) },
+			"in the past": func(s *definitions.ProvisionedSilence) {
+				s.StartsAt, s.EndsAt = now.Add(-2*time.Hour), now.Add(-time.Hour)
+			},
+		}
+		for name, mutate := range testCases {
+			t.Run(name, func(t *testing.T) {
+				s := silence()
+				mutate(&s)
+				_, err := sut.CreateSilence(ctx, 1, s, models.ProvenanceAPI)
+				require.ErrorIs(t, err, ErrValidation)
+			})
+		}
+	})
+}
+
+func createSilenceServiceSut(t *testing.T) (*SilenceService, *fakeAlertmanagerSilences) {
+	t.Helper()
+	ams := newFakeAlertmanagerSilences()
+	return &SilenceService{
+		silences: ams,
+		prov:     NewFakeProvisioningStore(),
+		kv:       kvstore.NewFakeKVStore(),
+		xact:     newNopTransactionManager(),
+		log:      log.NewNopLogger(),
+		tracer:   tracing.InitializeTracerForTest(),
+	}, ams
+}
@@ -0,0 +1,316 @@
+package provisioning
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+)
+
+// SnapshotStore persists the snapshots of the alerting configuration of the organizations.
+type SnapshotStore interface {
+	InsertAlertingSnapshot(ctx context.Context, snapshot *models.AlertingSnapshot) error
+	GetAlertingSnapshots(ctx context.Context, orgID int64) ([]models.AlertingSnapshot, error)
+	GetAlertingSnapshot(ctx context.Context, orgID int64, at int64) (*models.AlertingSnapshot, error)
+	DeleteAlertingSnapshotsBefore(ctx context.Context, before int64) (int64, error)
+}
+
+// snapshotResourceTypes are the types of resources whose provenance is part of a snapshot.
+var snapshotResourceTypes = []string{
+	(&models.AlertRule{}).ResourceType(),
+	(&definitions.EmbeddedContactPoint{}).ResourceType(),
+	(&definitions.NotificationTemplate{}).ResourceType(),
+	(&definitions.MuteTimeInterval{}).ResourceType(),
+	(&definitions.Route{}).ResourceType(),
+}
+
+// SnapshotService takes snapshots of the whole alerting configuration of the organizations and restores an
+// organization to a snapshot. Unlike the history of the Alertmanager configuration, which records every write,
+// snapshots are taken periodically and include the alert rules, so that an organization can be brought back to a
+// known state after its configuration was damaged, for example by a rogue automation.
+type SnapshotService struct {
+	snapshots       SnapshotStore
+	amStore         AMConfigStore
+	ruleStore       RuleStore
+	provenanceStore ProvisioningStore
+	xact            TransactionManager
+	orgs            store.OrgStore
+	log             log.Logger
+	tracer          tracing.Tracer
+	metrics         *metrics.Provisioning
+}
+
+func NewSnapshotService(snapshots SnapshotStore, amStore AMConfigStore, ruleStore RuleStore, provenanceStore ProvisioningStore,
+	xact TransactionManager, orgs store.OrgStore, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *SnapshotService {
+	return &SnapshotService{
+		snapshots:       snapshots,
+		amStore:         newTracedAMConfigStore(amStore, tracer, log, m),
+		ruleStore:       ruleStore,
+		provenanceStore: provenanceStore,
+		xact:            xact,
+		orgs:            orgs,
+		log:             log,
+		tracer:          tracer,
+		metrics:         m,
+	}
+}
+
+// GetSnapshots returns the snapshots of the organization, most recent first.
+func (svc *SnapshotService) GetSnapshots(ctx context.Context, orgID int64) (_ []definitions.AlertingSnapshot, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "snapshot", "GetSnapshots", orgID)
+	defer func() { done(err) }()
+
+	snapshots, err := svc.snapshots.GetAlertingSnapshots(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]definitions.AlertingSnapshot, 0, len(snapshots))
+	for _, s := range snapshots {
+		result = append(result, snapshotModel(s))
+	}
+	return result, nil
+}
+
+// TakeSnapshot saves the current alerting configuration of the organization.
+func (svc *SnapshotService) TakeSnapshot(ctx context.Context, orgID int64) (_ definitions.AlertingSnapshot, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "snapshot", "TakeSnapshot", orgID)
+	defer func() { done(err) }()
+
+	snapshot := &models.AlertingSnapshot{
+		OrgID:   orgID,
+		Created: time.Now().Unix(),
+	}
+	cfg, err := svc.amStore.GetLatestAlertmanagerConfiguration(ctx, &models.GetLatestAlertmanagerConfigurationQuery{OrgID: orgID})
+	if err != nil && !errors.Is(err, store.ErrNoAlertmanagerConfiguration) {
+		return definitions.AlertingSnapshot{}, err
+	}
+	if cfg != nil {
+		snapshot.AlertmanagerConfiguration = cfg.AlertmanagerConfiguration
+	}
+
+	rules, err := svc.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	data, err := json.Marshal(rules)
+	if err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	snapshot.Rules = string(data)
+
+	provenances := make(map[string]map[string]models.Provenance, len(snapshotResourceTypes))
+	for _, resourceType := range snapshotResourceTypes {
+		byID, err := svc.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+		if err != nil {
+			return definitions.AlertingSnapshot{}, err
+		}
+		if len(byID) > 0 {
+			provenances[resourceType] = byID
+		}
+	}
+	data, err = json.Marshal(provenances)
+	if err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	snapshot.Provenances = string(data)
+
+	if err := svc.snapshots.InsertAlertingSnapshot(ctx, snapshot); err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	return snapshotModel(*snapshot), nil
+}
+
+// TakeSnapshots saves the current alerting configuration of all organizations. A failure for one organization does
+// not prevent the others from being saved.
+func (svc *SnapshotService) TakeSnapshots(ctx context.Context) error {
+	orgIDs, err := svc.orgs.GetOrgs(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, orgID := range orgIDs {
+		if _, err := svc.TakeSnapshot(ctx, orgID); err != nil {
+			errs = append(errs, fmt.Errorf("failed to take a snapshot of organization %d: %w", orgID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// PruneSnapshots deletes the snapshots of all organizations that are older than the retention.
+func (svc *SnapshotService) PruneSnapshots(ctx context.Context, retention time.Duration) error {
+	deleted, err := svc.snapshots.DeleteAlertingSnapshotsBefore(ctx, time.Now().Add(-retention).Unix())
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		svc.log.FromContext(ctx).Debug("Deleted expired alerting snapshots", "count", deleted)
+	}
+	return nil
+}
+
+// RestoreSnapshot replaces the alerting configuration of the organization with the most recent snapshot taken at or
+// before the given time: the Alertmanager configuration, the alert rules and the provenances. The restore is recorded
+// in the provisioning audit log. It returns ErrNotFound if there is no such snapshot.
+func (svc *SnapshotService) RestoreSnapshot(ctx context.Context, orgID int64, at time.Time) (result definitions.AlertingSnapshot, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = svc.restoreSnapshot(ctx, orgID, at)
+		return err
+	})
+	return result, err
+}
+
+func (svc *SnapshotService) restoreSnapshot(ctx context.Context, orgID int64, at time.Time) (_ definitions.AlertingSnapshot, err error) {
+	ctx, done := startOperation(ctx, svc.tracer, svc.metrics, "snapshot", "RestoreSnapshot", orgID,
+		attribute.Int64("at", at.Unix()))
+	defer func() { done(err) }()
+
+	snapshot, err := svc.snapshots.GetAlertingSnapshot(ctx, orgID, at.Unix())
+	if errors.Is(err, store.ErrNoAlertingSnapshot) {
+		return definitions.AlertingSnapshot{}, fmt.Errorf("%w: no snapshot of the alerting configuration was taken before %s", ErrNotFound, at.UTC().Format(time.RFC3339))
+	}
+	if err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	var rules []*models.AlertRule
+	if err := json.Unmarshal([]byte(snapshot.Rules), &rules); err != nil {
+		return definitions.AlertingSnapshot{}, fmt.Errorf("failed to unmarshal the alert rules of the snapshot: %w", err)
+	}
+	var provenances map[string]map[string]models.Provenance
+	if err := json.Unmarshal([]byte(snapshot.Provenances), &provenances); err != nil {
+		return definitions.AlertingSnapshot{}, fmt.Errorf("failed to unmarshal the provenances of the snapshot: %w", err)
+	}
+
+	var cmd *models.SaveAlertmanagerConfigurationCmd
+	if snapshot.AlertmanagerConfiguration != "" {
+		revision, err := getLastConfiguration(ctx, orgID, svc.amStore)
+		if err != nil {
+			return definitions.AlertingSnapshot{}, err
+		}
+		cmd = &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: snapshot.AlertmanagerConfiguration,
+			FetchedConfigurationHash:  revision.concurrencyToken,
+			ConfigurationVersion:      revision.version,
+			Default:                   false,
+			OrgID:                     orgID,
+		}
+	}
+
+	current, err := svc.ruleStore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: orgID})
+	if err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	existing := make(map[string]*models.AlertRule, len(current))
+	for _, r := range current {
+		existing[r.UID] = r
+	}
+	var toInsert []models.AlertRule
+	var toUpdate []models.UpdateRule
+	for _, r := range rules {
+		r.OrgID = orgID
+		e, ok := existing[r.UID]
+		if !ok {
+			r.ID = 0
+			toInsert = append(toInsert, *r)
+			continue
+		}
+		delete(existing, r.UID)
+		if len(e.Diff(r, store.AlertRuleFieldsToIgnoreInDiff[:]...)) > 0 {
+			toUpdate = append(toUpdate, models.UpdateRule{Existing: e, New: *r})
+		}
+	}
+	toDelete := make([]string, 0, len(existing))
+	for uid := range existing {
+		toDelete = append(toDelete, uid)
+	}
+
+	err = svc.xact.InTransaction(ctx, func(ctx context.Context) error {
+		if cmd != nil {
+			if err := PersistConfig(ctx, svc.amStore, cmd); err != nil {
+				return err
+			}
+		}
+		if len(toDelete) > 0 {
+			if err := svc.ruleStore.DeleteAlertRulesByUID(ctx, orgID, toDelete...); err != nil {
+				return err
+			}
+		}
+		if len(toUpdate) > 0 {
+			if err := svc.ruleStore.UpdateAlertRules(ctx, toUpdate); err != nil {
+				return err
+			}
+		}
+		if len(toInsert) > 0 {
+			if _, err := svc.ruleStore.InsertAlertRules(ctx, toInsert); err != nil {
+				return err
+			}
+		}
+		if err := svc.restoreProvenances(ctx, orgID, provenances); err != nil {
+			return err
+		}
+		return recordAudit(ctx, svc.provenanceStore, orgID, models.ProvisioningAuditActionRestore, snapshot, models.ProvenanceNone, nil, snapshotModel(*snapshot))
+	})
+	if err != nil {
+		return definitions.AlertingSnapshot{}, err
+	}
+	svc.log.FromContext(ctx).Info("Restored alerting configuration from snapshot", "org", orgID, "snapshot", snapshot.ID,
+		"deletedRules", len(toDelete), "updatedRules", len(toUpdate), "insertedRules", len(toInsert))
+	return snapshotModel(*snapshot), nil
+}
+
+// restoreProvenances makes the provenances of the organization match the ones of a snapshot.
+func (svc *SnapshotService) restoreProvenances(ctx context.Context, orgID int64, provenances map[string]map[string]models.Provenance) error {
+	for _, resourceType := range snapshotResourceTypes {
+		current, err := svc.provenanceStore.GetProvenances(ctx, orgID, resourceType)
+		if err != nil {
+			return err
+		}
+		wanted := provenances[resourceType]
+		for id := range current {
+			if _, ok := wanted[id]; !ok {
+				if err := svc.provenanceStore.DeleteProvenance(ctx, provisionedResource{resourceType: resourceType, id: id}, orgID); err != nil {
+					return err
+				}
+			}
+		}
+		for id, p := range wanted {
+			if c, ok := current[id]; ok && c == p {
+				continue
+			}
+			if err := svc.provenanceStore.SetProvenance(ctx, provisionedResource{resourceType: resourceType, id: id}, orgID, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// provisionedResource identifies a resource by its type and ID where the resource itself is not available.
+type provisionedResource struct {
+	resourceType string
+	id           string
+}
+
+func (r provisionedResource) ResourceType() string {
+	return r.resourceType
+}
+
+func (r provisionedResource) ResourceID() string {
+	return r.id
+}
+
+func snapshotModel(s models.AlertingSnapshot) definitions.AlertingSnapshot {
+	return definitions.AlertingSnapshot{
+		ID:      s.ID,
+		Created: time.Unix(s.Created, 0).UTC(),
+	}
+}
@@ -0,0 +1,116 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+func TestSnapshotService(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("restores the Alertmanager configuration, the alert rules and the provenances", func(t *testing.T) {
+		sut, dbstore := createSnapshotServiceSut(t)
+		require.NoError(t, dbstore.SaveAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: defaultAlertmanagerConfigJSON,
+			ConfigurationVersion:      "v1",
+			OrgID:                     1,
+		}))
+		ids, err := dbstore.InsertAlertRules(ctx, []models.AlertRule{dummyRule("kept", 1)})
+		require.NoError(t, err)
+		var kept models.AlertRule
+		for uid := range ids {
+			kept = models.AlertRule{UID: uid}
+		}
+		require.NoError(t, dbstore.SetProvenance(ctx, &kept, 1, models.ProvenanceAPI))
+
+		snapshot, err := sut.TakeSnapshot(ctx, 1)
+		require.NoError(t, err)
+		snapshots, err := sut.GetSnapshots(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, snapshot.ID, snapshots[0].ID)
+
+		// Damage the configuration.
+		existing, err := dbstore.GetAlertRuleByUID(ctx, &models.GetAlertRuleByUIDQuery{OrgID: 1, UID: kept.UID})
+		require.NoError(t, err)
+		renamed := *existing
+		renamed.Title = "renamed"
+		require.NoError(t, dbstore.UpdateAlertRules(ctx, []models.UpdateRule{{Existing: existing, New: renamed}}))
+		_, err = dbstore.InsertAlertRules(ctx, []models.AlertRule{dummyRule("added", 1)})
+		require.NoError(t, err)
+		require.NoError(t, dbstore.DeleteProvenance(ctx, &kept, 1))
+		require.NoError(t, dbstore.SaveAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: defaultAlertmanagerConfigJSON + " ",
+			ConfigurationVersion:      "v1",
+			OrgID:                     1,
+		}))
+
+		restored, err := sut.RestoreSnapshot(ctx, 1, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		require.Equal(t, snapshot.ID, restored.ID)
+
+		rules, err := dbstore.ListAlertRules(ctx, &models.ListAlertRulesQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Len(t, rules, 1)
+		require.Equal(t, kept.UID, rules[0].UID)
+		require.Equal(t, "kept", rules[0].Title)
+		provenance, err := dbstore.GetProvenance(ctx, &kept, 1)
+		require.NoError(t, err)
+		require.Equal(t, models.ProvenanceAPI, provenance)
+		cfg, err := dbstore.GetLatestAlertmanagerConfiguration(ctx, &models.GetLatestAlertmanagerConfigurationQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, defaultAlertmanagerConfigJSON, cfg.AlertmanagerConfiguration)
+
+		entries, err := dbstore.GetProvisioningAuditEntries(ctx, models.ProvisioningAuditQuery{OrgID: 1, ResourceType: "alertingSnapshot"})
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		require.Equal(t, models.ProvisioningAuditActionRestore, entries[0].Action)
+	})
+
+	t.Run("fails if no snapshot was taken before the time", func(t *testing.T) {
+		sut, _ := createSnapshotServiceSut(t)
+		_, err := sut.TakeSnapshot(ctx, 1)
+		require.NoError(t, err)
+
+		_, err = sut.RestoreSnapshot(ctx, 1, time.Now().Add(-time.Hour))
+		require.ErrorIs(t, err, ErrNotFound)
+	})
+
+	t.Run("deletes the snapshots older than the retention", func(t *testing.T) {
+		sut, _ := createSnapshotServiceSut(t)
+		_, err := sut.TakeSnapshot(ctx, 1)
+		require.NoError(t, err)
+
+		require.NoError(t, sut.PruneSnapshots(ctx, time.Hour))
+		snapshots, err := sut.GetSnapshots(ctx, 1)
+		require.NoError(t, err)
+		require.Len(t, snapshots, 1)
+
+		require.NoError(t, sut.PruneSnapshots(ctx, -time.Hour))
+		snapshots, err = sut.GetSnapshots(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, snapshots)
+	})
+}
+
+func createSnapshotServiceSut(t *testing.T) (*SnapshotService, *store.DBstore) {
+	t.Helper()
+	sqlStore := db.InitTestDB(t)
+	dbstore := &store.DBstore{
+		SQLStore: sqlStore,
+		Cfg: setting.UnifiedAlertingSettings{
+			BaseInterval: time.Second * 10,
+		},
+		Logger: log.NewNopLogger(),
+	}
+	return NewSnapshotService(dbstore, dbstore, dbstore, dbstore, sqlStore, fakeOrgStore{orgs: []int64{1}}, log.NewNopLogger(), tracing.InitializeTracerForTest(), nil), dbstore
+}
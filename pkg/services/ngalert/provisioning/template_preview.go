@@ -0,0 +1,116 @@
+package provisioning
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	tmplhtml "html/template"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	tmpltext "text/template"
+
+	alertingNotify "github.com/grafana/alerting/notify"
+	alertingTemplates "github.com/grafana/alerting/templates"
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/prometheus/alertmanager/notify"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/templatedata"
+)
+
+// templatePreviewName is the name the previewed template is parsed with, and which its errors refer to.
+const templatePreviewName = "preview"
+
+// templateErrorPosition matches the position text/template prefixes the errors of the previewed template with.
+var templateErrorPosition = regexp.MustCompile(`^template: ` + templatePreviewName + `:(\d+)(?::(\d+))?:`)
+
+// PreviewTemplate renders the top-level definitions of the template against the sample alerts, with the template
+// functions of the notifier and the templates of the organization. Errors the template fails to parse or render with
+// are part of the preview, with the line they occurred at. URLs in the rendered output are relative, as the external
+// URL of the instance is not known.
+func (t *TemplateService) PreviewTemplate(ctx context.Context, orgID int64, content string, alerts []*amv2.PostableAlert) (_ definitions.TemplatePreview, err error) {
+	ctx, done := startOperation(ctx, t.tracer, t.metrics, "template", "PreviewTemplate", orgID)
+	defer func() { done(err) }()
+
+	preview := definitions.TemplatePreview{
+		Results: []definitions.TestTemplatesResult{},
+		Errors:  []definitions.TemplatePreviewError{},
+	}
+	// Parse the template on its own first so that it is validated without the templates it would replace.
+	parsed, err := tmpltext.New(templatePreviewName).Funcs(tmpltext.FuncMap(alertingTemplates.DefaultFuncs)).Parse(content)
+	if err != nil {
+		preview.Errors = append(preview.Errors, templatePreviewError("", definitions.InvalidTemplate, err))
+		return preview, nil
+	}
+	names, err := alertingTemplates.TopTemplates(parsed)
+	if err != nil {
+		preview.Errors = append(preview.Errors, templatePreviewError("", definitions.InvalidTemplate, err))
+		return preview, nil
+	}
+
+	revision, err := getLastConfiguration(ctx, orgID, t.config)
+	if err != nil {
+		return definitions.TemplatePreview{}, err
+	}
+	var text *tmpltext.Template
+	tmpl, err := alertingTemplates.FromGlobs(nil, func(tt *tmpltext.Template, _ *tmplhtml.Template) {
+		text = tt
+	})
+	if err != nil {
+		return definitions.TemplatePreview{}, err
+	}
+	tmpl.ExternalURL = &url.URL{}
+	if err := tmpl.Parse(strings.NewReader(alertingTemplates.DefaultTemplateString)); err != nil {
+		return definitions.TemplatePreview{}, err
+	}
+	files := make([]string, 0, len(revision.cfg.TemplateFiles))
+	for name := range revision.cfg.TemplateFiles {
+		files = append(files, name)
+	}
+	sort.Strings(files)
+	for _, name := range files {
+		if err := tmpl.Parse(strings.NewReader(revision.cfg.TemplateFiles[name])); err != nil {
+			return definitions.TemplatePreview{}, fmt.Errorf("failed to parse template '%s': %w", name, err)
+		}
+	}
+	// Definitions of the previewed template replace those of the same name in the templates of the organization.
+	if _, err := text.New(templatePreviewName).Parse(content); err != nil {
+		return definitions.TemplatePreview{}, err
+	}
+
+	if len(alerts) == 0 {
+		alerts = []*amv2.PostableAlert{{}}
+	}
+	for _, alert := range alerts {
+		templatedata.AddDefaultLabelsAndAnnotations(alert)
+	}
+	ctx = notify.WithReceiverName(ctx, alertingNotify.DefaultReceiverName)
+	ctx = notify.WithGroupLabels(ctx, model.LabelSet{alertingNotify.DefaultGroupLabel: alertingNotify.DefaultGroupLabelValue})
+	data := alertingTemplates.ExtendData(notify.GetTemplateData(ctx, tmpl, alertingNotify.OpenAPIAlertsToAlerts(alerts), t.log), t.log)
+
+	for _, name := range names {
+		var buf bytes.Buffer
+		if err := text.ExecuteTemplate(&buf, name, data); err != nil {
+			preview.Errors = append(preview.Errors, templatePreviewError(name, definitions.ExecutionError, err))
+			continue
+		}
+		preview.Results = append(preview.Results, definitions.TestTemplatesResult{Name: name, Text: buf.String()})
+	}
+	return preview, nil
+}
+
+// templatePreviewError returns the error of the preview, with the position it occurred at in the previewed template.
+func templatePreviewError(name string, kind definitions.TemplateErrorKind, err error) definitions.TemplatePreviewError {
+	result := definitions.TemplatePreviewError{Name: name, Kind: kind, Message: err.Error()}
+	if m := templateErrorPosition.FindStringSubmatch(result.Message); m != nil {
+		result.Line, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			result.Column, _ = strconv.Atoi(m[2])
+		}
+	}
+	return result
+}
@@ -0,0 +1,70 @@
+package provisioning
+
+import (
+	"context"
+	"testing"
+
+	amv2 "github.com/prometheus/alertmanager/api/v2/models"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+)
+
+func TestPreviewTemplate(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("renders the top-level definitions with the templates of the organization", func(t *testing.T) {
+		sut := createTemplatePreviewSut(t, map[string]string{
+			"common": `{{ define "common.status" }}{{ .Status }}{{ end }}`,
+		})
+		alerts := []*amv2.PostableAlert{{Alert: amv2.Alert{Labels: amv2.LabelSet{"team": "a"}}}}
+
+		preview, err := sut.PreviewTemplate(ctx, 1, `{{ define "title" }}{{ template "common.status" . }}: {{ range .Alerts }}{{ .Labels.team }} {{ .Labels.alertname }}{{ end }}{{ end }}`, alerts)
+
+		require.NoError(t, err)
+		require.Empty(t, preview.Errors)
+		require.Equal(t, []definitions.TestTemplatesResult{{Name: "title", Text: "firing: a alert title"}}, preview.Results)
+	})
+
+	t.Run("parse errors have the line they occurred at", func(t *testing.T) {
+		sut := createTemplatePreviewSut(t, nil)
+
+		preview, err := sut.PreviewTemplate(ctx, 1, "{{ define \"title\" }}\n{{ .Status }", nil)
+
+		require.NoError(t, err)
+		require.Empty(t, preview.Results)
+		require.Len(t, preview.Errors, 1)
+		require.Equal(t, definitions.InvalidTemplate, preview.Errors[0].Kind)
+		require.Equal(t, 2, preview.Errors[0].Line)
+	})
+
+	t.Run("broken field references are reported with their position", func(t *testing.T) {
+		sut := createTemplatePreviewSut(t, nil)
+
+		preview, err := sut.PreviewTemplate(ctx, 1, "{{ define \"title\" }}\n{{ .Labelz }}{{ end }}", nil)
+
+		require.NoError(t, err)
+		require.Len(t, preview.Errors, 1)
+		require.Equal(t, "title", preview.Errors[0].Name)
+		require.Equal(t, definitions.ExecutionError, preview.Errors[0].Kind)
+		require.Equal(t, 2, preview.Errors[0].Line)
+		require.Equal(t, 3, preview.Errors[0].Column)
+	})
+}
+
+func createTemplatePreviewSut(t *testing.T, templates map[string]string) *TemplateService {
+	t.Helper()
+	cfg := createTestAlertingConfig()
+	cfg.TemplateFiles = templates
+	serialized, err := serializeAlertmanagerConfig(*cfg)
+	require.NoError(t, err)
+	return &TemplateService{
+		config: newFakeAMConfigStore(string(serialized)),
+		prov:   NewFakeProvisioningStore(),
+		xact:   newNopTransactionManager(),
+		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
+	}
+}
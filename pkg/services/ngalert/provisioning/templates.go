@@ -4,28 +4,40 @@ import (
 	"context"
 	"fmt"
 
+	"go.opentelemetry.io/otel/attribute"
+
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/metrics"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 )
 
 type TemplateService struct {
-	config AMConfigStore
-	prov   ProvisioningStore
-	xact   TransactionManager
-	log    log.Logger
+	config  AMConfigStore
+	prov    ProvisioningStore
+	xact    TransactionManager
+	quotas  QuotaChecker
+	log     log.Logger
+	tracer  tracing.Tracer
+	metrics *metrics.Provisioning
 }
 
-func NewTemplateService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, log log.Logger) *TemplateService {
+func NewTemplateService(config AMConfigStore, prov ProvisioningStore, xact TransactionManager, quotas QuotaChecker, log log.Logger, tracer tracing.Tracer, m *metrics.Provisioning) *TemplateService {
 	return &TemplateService{
-		config: config,
-		prov:   prov,
-		xact:   xact,
-		log:    log,
+		config:  newTracedAMConfigStore(config, tracer, log, m),
+		prov:    prov,
+		xact:    xact,
+		quotas:  quotas,
+		log:     log,
+		tracer:  tracer,
+		metrics: m,
 	}
 }
 
-func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (map[string]string, error) {
+func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (_ map[string]string, err error) {
+	ctx, done := startOperation(ctx, t.tracer, t.metrics, "template", "GetTemplates", orgID)
+	defer func() { done(err) }()
 	revision, err := getLastConfiguration(ctx, orgID, t.config)
 	if err != nil {
 		return nil, err
@@ -38,8 +50,19 @@ func (t *TemplateService) GetTemplates(ctx context.Context, orgID int64) (map[st
 	return revision.cfg.TemplateFiles, nil
 }
 
-func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (definitions.NotificationTemplate, error) {
-	err := tmpl.Validate()
+func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (result definitions.NotificationTemplate, err error) {
+	err = updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		result, err = t.setTemplate(ctx, orgID, tmpl)
+		return err
+	})
+	return result, err
+}
+
+func (t *TemplateService) setTemplate(ctx context.Context, orgID int64, tmpl definitions.NotificationTemplate) (_ definitions.NotificationTemplate, err error) {
+	ctx, done := startOperation(ctx, t.tracer, t.metrics, "template", "SetTemplate", orgID,
+		attribute.String("template_name", tmpl.Name))
+	defer func() { done(err) }()
+	err = tmpl.Validate()
 	if err != nil {
 		return definitions.NotificationTemplate{}, fmt.Errorf("%w: %s", ErrValidation, err.Error())
 	}
@@ -52,6 +75,10 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 	if revision.cfg.TemplateFiles == nil {
 		revision.cfg.TemplateFiles = map[string]string{}
 	}
+	action, oldState := models.ProvisioningAuditActionCreate, any(nil)
+	if existing, ok := revision.cfg.TemplateFiles[tmpl.Name]; ok {
+		action, oldState = models.ProvisioningAuditActionUpdate, definitions.NotificationTemplate{Name: tmpl.Name, Template: existing}
+	}
 	revision.cfg.TemplateFiles[tmpl.Name] = tmpl.Template
 	tmpls := make([]string, 0, len(revision.cfg.TemplateFiles))
 	for name := range revision.cfg.TemplateFiles {
@@ -59,27 +86,25 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 	}
 	revision.cfg.AlertmanagerConfig.Templates = tmpls
 
-	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
+	change, err := resourceChange(models.AlertConfigurationResourceTemplate, tmpl.Name, tmpl.Template)
 	if err != nil {
 		return definitions.NotificationTemplate{}, err
 	}
-	cmd := models.SaveAlertmanagerConfigurationCmd{
-		AlertmanagerConfiguration: string(serialized),
-		ConfigurationVersion:      revision.version,
-		FetchedConfigurationHash:  revision.concurrencyToken,
-		Default:                   false,
-		OrgID:                     orgID,
-	}
 	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, t.config, &cmd)
+		err = persistConfigChanges(ctx, t.config, orgID, revision, []models.AlertConfigurationResourceChange{change})
 		if err != nil {
 			return err
 		}
+		if action == models.ProvisioningAuditActionCreate {
+			if err := TemplateQuota.checkTransactionCtx(ctx, t.quotas, orgID); err != nil {
+				return err
+			}
+		}
 		err = t.prov.SetProvenance(ctx, &tmpl, orgID, models.Provenance(tmpl.Provenance))
 		if err != nil {
 			return err
 		}
-		return nil
+		return recordAudit(ctx, t.prov, orgID, action, &tmpl, models.Provenance(tmpl.Provenance), oldState, tmpl)
 	})
 	if err != nil {
 		return definitions.NotificationTemplate{}, err
@@ -89,27 +114,38 @@ func (t *TemplateService) SetTemplate(ctx context.Context, orgID int64, tmpl def
 }
 
 func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name string) error {
+	return updateAlertmanagerConfig(ctx, orgID, func(ctx context.Context) error {
+		return t.deleteTemplate(ctx, orgID, name)
+	})
+}
+
+func (t *TemplateService) deleteTemplate(ctx context.Context, orgID int64, name string) (err error) {
+	ctx, done := startOperation(ctx, t.tracer, t.metrics, "template", "DeleteTemplate", orgID,
+		attribute.String("template_name", name))
+	defer func() { done(err) }()
 	revision, err := getLastConfiguration(ctx, orgID, t.config)
 	if err != nil {
 		return err
 	}
 
-	delete(revision.cfg.TemplateFiles, name)
-
-	serialized, err := serializeAlertmanagerConfig(*revision.cfg)
-	if err != nil {
-		return err
+	var oldState any
+	if existing, ok := revision.cfg.TemplateFiles[name]; ok {
+		storedProvenance, err := t.prov.GetProvenance(ctx, &definitions.NotificationTemplate{Name: name}, orgID)
+		if err != nil {
+			return err
+		}
+		if storedProvenance == models.ProvenanceGlobal {
+			return newValidationError("", "template '%s' is inherited from the global templates and cannot be deleted", name).
+				withResource((&definitions.NotificationTemplate{}).ResourceType(), name)
+		}
+		oldState = definitions.NotificationTemplate{Name: name, Template: existing}
 	}
+	delete(revision.cfg.TemplateFiles, name)
 
-	cmd := models.SaveAlertmanagerConfigurationCmd{
-		AlertmanagerConfiguration: string(serialized),
-		ConfigurationVersion:      revision.version,
-		FetchedConfigurationHash:  revision.concurrencyToken,
-		Default:                   false,
-		OrgID:                     orgID,
-	}
 	err = t.xact.InTransaction(ctx, func(ctx context.Context) error {
-		err = PersistConfig(ctx, t.config, &cmd)
+		err = persistConfigChanges(ctx, t.config, orgID, revision, []models.AlertConfigurationResourceChange{
+			deletedResource(models.AlertConfigurationResourceTemplate, name),
+		})
 		if err != nil {
 			return err
 		}
@@ -120,7 +156,7 @@ func (t *TemplateService) DeleteTemplate(ctx context.Context, orgID int64, name
 		if err != nil {
 			return err
 		}
-		return nil
+		return recordAudit(ctx, t.prov, orgID, models.ProvisioningAuditActionDelete, &tgt, models.ProvenanceNone, oldState, nil)
 	})
 	if err != nil {
 		return err
@@ -9,6 +9,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/setting"
@@ -344,6 +345,9 @@ func TestTemplateService(t *testing.T) {
 				})
 			sut.config.(*MockAMConfigStore).EXPECT().SaveSucceeds()
 			sut.prov.(*MockProvisioningStore).EXPECT().SaveSucceeds()
+			sut.prov.(*MockProvisioningStore).EXPECT().
+				GetProvenance(mock.Anything, mock.Anything, mock.Anything).
+				Return(models.ProvenanceNone, nil)
 
 			err := sut.DeleteTemplate(context.Background(), 1, "a")
 
@@ -386,6 +390,7 @@ func createTemplateServiceSut() *TemplateService {
 		prov:   &MockProvisioningStore{},
 		xact:   newNopTransactionManager(),
 		log:    log.NewNopLogger(),
+		tracer: tracing.InitializeTracerForTest(),
 	}
 }
 
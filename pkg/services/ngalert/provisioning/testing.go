@@ -5,7 +5,10 @@ import (
 	"crypto/md5"
 	"fmt"
 	"strings"
+	"sync"
+	"time"
 
+	alertingNotify "github.com/grafana/alerting/notify"
 	mock "github.com/stretchr/testify/mock"
 
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
@@ -89,7 +92,8 @@ func (f *fakeAMConfigStore) UpdateAlertmanagerConfiguration(ctx context.Context,
 }
 
 type fakeProvisioningStore struct {
-	records map[int64]map[string]models.Provenance
+	records      map[int64]map[string]models.Provenance
+	auditEntries []models.ProvisioningAuditEntry
 }
 
 func NewFakeProvisioningStore() *fakeProvisioningStore {
@@ -135,6 +139,25 @@ func (f *fakeProvisioningStore) DeleteProvenance(ctx context.Context, o models.P
 	return nil
 }
 
+func (f *fakeProvisioningStore) InsertProvisioningAuditEntry(_ context.Context, entry *models.ProvisioningAuditEntry) error {
+	f.auditEntries = append(f.auditEntries, *entry)
+	return nil
+}
+
+func (f *fakeProvisioningStore) GetLatestProvisioningAuditEntries(_ context.Context, orgID int64) ([]models.ProvisioningAuditEntry, error) {
+	latest := map[string]models.ProvisioningAuditEntry{}
+	for _, e := range f.auditEntries {
+		if e.OrgID == orgID {
+			latest[e.ResourceType+"/"+e.ResourceID] = e
+		}
+	}
+	result := make([]models.ProvisioningAuditEntry, 0, len(latest))
+	for _, e := range latest {
+		result = append(result, e)
+	}
+	return result, nil
+}
+
 type NopTransactionManager struct{}
 
 func newNopTransactionManager() *NopTransactionManager {
@@ -145,6 +168,44 @@ func (n *NopTransactionManager) InTransaction(ctx context.Context, work func(ctx
 	return work(ctx)
 }
 
+// FakeReceiverTester records the receivers it is asked to test and reports every integration as notified, or as
+// failed with IntegrationErr if it is set.
+type FakeReceiverTester struct {
+	Calls          []alertingNotify.TestReceiversConfigBodyParams
+	IntegrationErr error
+}
+
+func (f *FakeReceiverTester) TestReceivers(_ context.Context, _ int64, c alertingNotify.TestReceiversConfigBodyParams) (*alertingNotify.TestReceiversResult, error) {
+	f.Calls = append(f.Calls, c)
+	result := &alertingNotify.TestReceiversResult{NotifedAt: time.Now()}
+	for _, r := range c.Receivers {
+		receiver := alertingNotify.TestReceiverResult{Name: r.Name}
+		for _, integration := range r.Integrations {
+			config := alertingNotify.TestIntegrationConfigResult{Name: integration.Name, UID: integration.UID, Status: "ok"}
+			if f.IntegrationErr != nil {
+				config.Status = "failed"
+				config.Error = f.IntegrationErr
+			}
+			receiver.Configs = append(receiver.Configs, config)
+		}
+		result.Receivers = append(result.Receivers, receiver)
+	}
+	return result, nil
+}
+
+// FakeIntegrationStatusReader returns the given delivery status of integrations, by organization ID and integration UID.
+type FakeIntegrationStatusReader struct {
+	Statuses map[int64]map[string]models.IntegrationStatus
+}
+
+func (f *FakeIntegrationStatusReader) GetIntegrationStatus(_ context.Context, orgID int64) (map[string]models.IntegrationStatus, error) {
+	return f.Statuses[orgID], nil
+}
+
+func (f *FakeIntegrationStatusReader) GetAllIntegrationStatus(_ context.Context) map[int64]map[string]models.IntegrationStatus {
+	return f.Statuses
+}
+
 func (m *MockAMConfigStore_Expecter) GetsConfig(ac models.AlertConfiguration) *MockAMConfigStore_Expecter {
 	m.GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).Return(&ac, nil)
 	return m
@@ -155,6 +216,30 @@ func (m *MockAMConfigStore_Expecter) SaveSucceeds() *MockAMConfigStore_Expecter
 	return m
 }
 
+// GetsAndSavesConfig returns the given configuration until another one is saved, and the last saved one after that.
+func (m *MockAMConfigStore_Expecter) GetsAndSavesConfig(ac models.AlertConfiguration) *MockAMConfigStore_Expecter {
+	var mtx sync.Mutex
+	current := ac
+	m.GetLatestAlertmanagerConfiguration(mock.Anything, mock.Anything).Call.Return(
+		func(context.Context, *models.GetLatestAlertmanagerConfigurationQuery) *models.AlertConfiguration {
+			mtx.Lock()
+			defer mtx.Unlock()
+			result := current
+			return &result
+		}, nil)
+	m.UpdateAlertmanagerConfiguration(mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			current.AlertmanagerConfiguration = cmd.AlertmanagerConfiguration
+			current.ConfigurationHash = fmt.Sprintf("%x", md5.Sum([]byte(cmd.AlertmanagerConfiguration)))
+			current.ConfigurationVersion = cmd.ConfigurationVersion
+			current.OrgID = cmd.OrgID
+		})
+	return m
+}
+
 func (m *MockAMConfigStore_Expecter) SaveSucceedsIntercept(intercepted *models.SaveAlertmanagerConfigurationCmd) *MockAMConfigStore_Expecter {
 	m.UpdateAlertmanagerConfiguration(mock.Anything, mock.Anything).
 		Return(nil).
@@ -173,6 +258,7 @@ func (m *MockProvisioningStore_Expecter) GetReturns(p models.Provenance) *MockPr
 func (m *MockProvisioningStore_Expecter) SaveSucceeds() *MockProvisioningStore_Expecter {
 	m.SetProvenance(mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(nil)
 	m.DeleteProvenance(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	m.InsertProvisioningAuditEntry(mock.Anything, mock.Anything).Return(nil)
 	return m
 }
 
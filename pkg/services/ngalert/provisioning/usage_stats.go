@@ -0,0 +1,103 @@
+package provisioning
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// UsageStatsStore reads what the usage stats of alerting provisioning are computed from.
+type UsageStatsStore interface {
+	GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([]*models.AlertConfiguration, error)
+	GetProvenanceCounts(ctx context.Context) (map[string]map[models.Provenance]int64, error)
+}
+
+// UsageStatsService reports anonymous usage stats of the alerting configurations of all organizations of the instance:
+// the number of integrations by type, the size and depth of the notification policy trees, the number of templates and
+// the distribution of provenances by resource type.
+type UsageStatsService struct {
+	store UsageStatsStore
+	log   log.Logger
+}
+
+func NewUsageStatsService(store UsageStatsStore, log log.Logger) *UsageStatsService {
+	return &UsageStatsService{store: store, log: log}
+}
+
+// GetUsageStats returns the usage stats of the instance. Errors are logged and the stats that could not be computed
+// are left out.
+func (svc *UsageStatsService) GetUsageStats(ctx context.Context) map[string]any {
+	stats := map[string]any{}
+
+	configs, err := svc.store.GetAllLatestAlertmanagerConfiguration(ctx)
+	if err != nil {
+		svc.log.FromContext(ctx).Error("Failed to get Alertmanager configurations for usage stats", "error", err)
+	} else {
+		svc.addConfigStats(ctx, stats, configs)
+	}
+
+	counts, err := svc.store.GetProvenanceCounts(ctx)
+	if err != nil {
+		svc.log.FromContext(ctx).Error("Failed to count provenances for usage stats", "error", err)
+	} else {
+		for resourceType, byProvenance := range counts {
+			for provenance, count := range byProvenance {
+				if provenance == models.ProvenanceNone {
+					provenance = "none"
+				}
+				stats[fmt.Sprintf("stats.alerting.provenance.%s.%s.count", resourceType, provenance)] = count
+			}
+		}
+	}
+
+	return stats
+}
+
+func (svc *UsageStatsService) addConfigStats(ctx context.Context, stats map[string]any, configs []*models.AlertConfiguration) {
+	integrationsByType := map[string]int{}
+	policies, maxDepth, templates := 0, 0, 0
+	for _, c := range configs {
+		cfg, err := deserializeAlertmanagerConfig(c.AlertmanagerConfiguration)
+		if err != nil {
+			svc.log.FromContext(ctx).Warn("Skipping invalid Alertmanager configuration in usage stats", "org", c.OrgID, "error", err)
+			continue
+		}
+		for _, receiver := range cfg.AlertmanagerConfig.Receivers {
+			for _, integration := range receiver.GrafanaManagedReceivers {
+				integrationsByType[integration.Type]++
+			}
+		}
+		if route := cfg.AlertmanagerConfig.Route; route != nil {
+			size, depth := policyTreeStats(route)
+			policies += size
+			if depth > maxDepth {
+				maxDepth = depth
+			}
+		}
+		templates += len(cfg.TemplateFiles)
+	}
+
+	for integrationType, count := range integrationsByType {
+		stats[fmt.Sprintf("stats.alerting.contact_points.%s.count", integrationType)] = count
+	}
+	stats["stats.alerting.notification_policies.count"] = policies
+	stats["stats.alerting.notification_policies.max_depth"] = maxDepth
+	stats["stats.alerting.templates.count"] = templates
+}
+
+// policyTreeStats returns the number of policies of the tree, including the root, and its depth. A tree with only the
+// root policy has a depth of 1.
+func policyTreeStats(route *definitions.Route) (size, depth int) {
+	size = 1
+	for _, child := range route.Routes {
+		childSize, childDepth := policyTreeStats(child)
+		size += childSize
+		if childDepth > depth {
+			depth = childDepth
+		}
+	}
+	return size, depth + 1
+}
@@ -0,0 +1,88 @@
+package provisioning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestUsageStats(t *testing.T) {
+	t.Run("aggregates the configurations and provenances of all organizations", func(t *testing.T) {
+		store := &fakeUsageStatsStore{
+			configs: []*models.AlertConfiguration{
+				{OrgID: 1, AlertmanagerConfiguration: defaultAlertmanagerConfigJSON},
+				{OrgID: 2, AlertmanagerConfiguration: defaultAlertmanagerConfigJSON},
+				{OrgID: 3, AlertmanagerConfiguration: "invalid"},
+			},
+			provenances: map[string]map[models.Provenance]int64{
+				"contactPoint": {models.ProvenanceAPI: 2, models.ProvenanceNone: 1},
+				"alertRule":    {models.ProvenanceFile: 5},
+			},
+		}
+		sut := NewUsageStatsService(store, log.NewNopLogger())
+
+		stats := sut.GetUsageStats(context.Background())
+
+		require.Equal(t, map[string]any{
+			"stats.alerting.contact_points.email.count":         2,
+			"stats.alerting.contact_points.slack.count":         2,
+			"stats.alerting.notification_policies.count":        4,
+			"stats.alerting.notification_policies.max_depth":    2,
+			"stats.alerting.templates.count":                    0,
+			"stats.alerting.provenance.contactPoint.api.count":  int64(2),
+			"stats.alerting.provenance.contactPoint.none.count": int64(1),
+			"stats.alerting.provenance.alertRule.file.count":    int64(5),
+		}, stats)
+	})
+
+	t.Run("leaves out the stats that fail", func(t *testing.T) {
+		store := &fakeUsageStatsStore{
+			configs: []*models.AlertConfiguration{
+				{OrgID: 1, AlertmanagerConfiguration: defaultAlertmanagerConfigJSON},
+			},
+			provenanceErr: errors.New("test error"),
+		}
+		sut := NewUsageStatsService(store, log.NewNopLogger())
+
+		stats := sut.GetUsageStats(context.Background())
+
+		require.Equal(t, 1, stats["stats.alerting.contact_points.email.count"])
+		for key := range stats {
+			require.NotContains(t, key, "stats.alerting.provenance.")
+		}
+	})
+}
+
+func TestPolicyTreeStats(t *testing.T) {
+	tree := &definitions.Route{
+		Routes: []*definitions.Route{
+			{},
+			{Routes: []*definitions.Route{{Routes: []*definitions.Route{{}}}}},
+		},
+	}
+
+	size, depth := policyTreeStats(tree)
+
+	require.Equal(t, 5, size)
+	require.Equal(t, 4, depth)
+}
+
+type fakeUsageStatsStore struct {
+	configs       []*models.AlertConfiguration
+	provenances   map[string]map[models.Provenance]int64
+	provenanceErr error
+}
+
+func (f *fakeUsageStatsStore) GetAllLatestAlertmanagerConfiguration(context.Context) ([]*models.AlertConfiguration, error) {
+	return f.configs, nil
+}
+
+func (f *fakeUsageStatsStore) GetProvenanceCounts(context.Context) (map[string]map[models.Provenance]int64, error) {
+	return f.provenances, f.provenanceErr
+}
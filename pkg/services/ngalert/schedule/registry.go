@@ -341,6 +341,7 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(rule.ID)
 	writeInt(rule.OrgID)
 	writeInt(rule.IntervalSeconds)
+	writeInt(rule.EvaluationOffsetSeconds)
 	writeInt(int64(rule.For))
 	writeLabels(rule.Annotations)
 	if rule.DashboardUID != nil {
@@ -353,5 +354,12 @@ func (r ruleWithFolder) Fingerprint() fingerprint {
 	writeInt(int64(rule.RuleGroupIndex))
 	writeString(string(rule.NoDataState))
 	writeString(string(rule.ExecErrState))
+	for _, settings := range rule.NotificationSettings {
+		writeString(settings.Receiver)
+		writeInt(int64(len(settings.MuteTimeIntervals)))
+		for _, name := range settings.MuteTimeIntervals {
+			writeString(name)
+		}
+	}
 	return fingerprint(sum.Sum64())
 }
@@ -433,16 +433,17 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 					Model:         json.RawMessage(`{"test": "test-model-2"}`),
 				},
 			},
-			IntervalSeconds: 23,
-			UID:             "test-uid2",
-			NamespaceUID:    "test-ns2",
-			DashboardUID:    func(s string) *string { return &s }("dashboard-2"),
-			PanelID:         func(i int64) *int64 { return &i }(1222),
-			RuleGroup:       "test-group-2",
-			RuleGroupIndex:  22,
-			NoDataState:     "test-nodata2",
-			ExecErrState:    "test-err2",
-			For:             1141,
+			IntervalSeconds:         23,
+			EvaluationOffsetSeconds: 7,
+			UID:                     "test-uid2",
+			NamespaceUID:            "test-ns2",
+			DashboardUID:            func(s string) *string { return &s }("dashboard-2"),
+			PanelID:                 func(i int64) *int64 { return &i }(1222),
+			RuleGroup:               "test-group-2",
+			RuleGroupIndex:          22,
+			NoDataState:             "test-nodata2",
+			ExecErrState:            "test-err2",
+			For:                     1141,
 			Annotations: map[string]string{
 				"key-annotation2": "value-annotation",
 			},
@@ -450,6 +451,9 @@ func TestRuleWithFolderFingerprint(t *testing.T) {
 				"key-label": "value-label23",
 			},
 			IsPaused: true,
+			NotificationSettings: []models.NotificationSettings{
+				{Receiver: "receiver", MuteTimeIntervals: []string{"weekends"}},
+			},
 		}
 
 		excludedFields := map[string]struct{}{
@@ -268,7 +268,9 @@ func (sch *schedule) processTick(ctx context.Context, dispatcherGroup *errgroup.
 		}
 
 		itemFrequency := item.IntervalSeconds / int64(sch.baseInterval.Seconds())
-		isReadyToRun := item.IntervalSeconds != 0 && tickNum%itemFrequency == 0
+		// the evaluation offset of the group shifts the ticks the rule is evaluated at within its interval
+		offsetTicks := item.EvaluationOffsetSeconds / int64(sch.baseInterval.Seconds())
+		isReadyToRun := item.IntervalSeconds != 0 && (tickNum-offsetTicks)%itemFrequency == 0
 
 		var folderTitle string
 		if !sch.disableGrafanaFolder {
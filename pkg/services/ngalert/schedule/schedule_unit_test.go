@@ -354,6 +354,27 @@ func TestProcessTicks(t *testing.T) {
 	})
 }
 
+func TestProcessTicksEvaluationOffset(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	dispatcherGroup, ctx := errgroup.WithContext(ctx)
+	ruleStore := newFakeRulesStore()
+	sch := setupScheduler(t, ruleStore, nil, nil, nil, nil)
+
+	rule := models.AlertRuleGen(models.WithInterval(3 * time.Second))()
+	rule.EvaluationOffsetSeconds = 1
+	ruleStore.PutRule(ctx, rule)
+
+	var evaluated []int64
+	for i := int64(300); i < 306; i++ {
+		scheduled, _, _ := sch.processTick(ctx, dispatcherGroup, time.Unix(i, 0))
+		if len(scheduled) > 0 {
+			evaluated = append(evaluated, i)
+		}
+	}
+	require.Equal(t, []int64{301, 304}, evaluated)
+}
+
 func TestSchedule_ruleRoutine(t *testing.T) {
 	createSchedule := func(
 		evalAppliedChan chan time.Time,
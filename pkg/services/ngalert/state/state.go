@@ -487,5 +487,11 @@ func GetRuleExtraLabels(rule *models.AlertRule, folderTitle string, includeFolde
 	if includeFolder {
 		extraLabels[models.FolderTitleLabel] = folderTitle
 	}
+	// Alerts of rules with notification settings are routed by the route that is generated for the settings.
+	for _, settings := range rule.NotificationSettings {
+		for k, v := range settings.Labels() {
+			extraLabels[k] = v
+		}
+	}
 	return extraLabels
 }
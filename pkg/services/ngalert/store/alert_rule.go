@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
@@ -139,22 +140,24 @@ func (st DBstore) InsertAlertRules(ctx context.Context, rules []ngmodels.AlertRu
 			}
 			newRules = append(newRules, r)
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleUID:          r.UID,
-				RuleOrgID:        r.OrgID,
-				RuleNamespaceUID: r.NamespaceUID,
-				RuleGroup:        r.RuleGroup,
-				ParentVersion:    0,
-				Version:          r.Version,
-				Created:          r.Updated,
-				Condition:        r.Condition,
-				Title:            r.Title,
-				Data:             r.Data,
-				IntervalSeconds:  r.IntervalSeconds,
-				NoDataState:      r.NoDataState,
-				ExecErrState:     r.ExecErrState,
-				For:              r.For,
-				Annotations:      r.Annotations,
-				Labels:           r.Labels,
+				RuleUID:                 r.UID,
+				RuleOrgID:               r.OrgID,
+				RuleNamespaceUID:        r.NamespaceUID,
+				RuleGroup:               r.RuleGroup,
+				ParentVersion:           0,
+				Version:                 r.Version,
+				Created:                 r.Updated,
+				Condition:               r.Condition,
+				Title:                   r.Title,
+				Data:                    r.Data,
+				IntervalSeconds:         r.IntervalSeconds,
+				NoDataState:             r.NoDataState,
+				ExecErrState:            r.ExecErrState,
+				For:                     r.For,
+				Annotations:             r.Annotations,
+				Labels:                  r.Labels,
+				NotificationSettings:    r.NotificationSettings,
+				EvaluationOffsetSeconds: r.EvaluationOffsetSeconds,
 			})
 		}
 		if len(newRules) > 0 {
@@ -211,23 +214,25 @@ func (st DBstore) UpdateAlertRules(ctx context.Context, rules []ngmodels.UpdateR
 			}
 			parentVersion = r.Existing.Version
 			ruleVersions = append(ruleVersions, ngmodels.AlertRuleVersion{
-				RuleOrgID:        r.New.OrgID,
-				RuleUID:          r.New.UID,
-				RuleNamespaceUID: r.New.NamespaceUID,
-				RuleGroup:        r.New.RuleGroup,
-				RuleGroupIndex:   r.New.RuleGroupIndex,
-				ParentVersion:    parentVersion,
-				Version:          r.New.Version + 1,
-				Created:          r.New.Updated,
-				Condition:        r.New.Condition,
-				Title:            r.New.Title,
-				Data:             r.New.Data,
-				IntervalSeconds:  r.New.IntervalSeconds,
-				NoDataState:      r.New.NoDataState,
-				ExecErrState:     r.New.ExecErrState,
-				For:              r.New.For,
-				Annotations:      r.New.Annotations,
-				Labels:           r.New.Labels,
+				RuleOrgID:               r.New.OrgID,
+				RuleUID:                 r.New.UID,
+				RuleNamespaceUID:        r.New.NamespaceUID,
+				RuleGroup:               r.New.RuleGroup,
+				RuleGroupIndex:          r.New.RuleGroupIndex,
+				ParentVersion:           parentVersion,
+				Version:                 r.New.Version + 1,
+				Created:                 r.New.Updated,
+				Condition:               r.New.Condition,
+				Title:                   r.New.Title,
+				Data:                    r.New.Data,
+				IntervalSeconds:         r.New.IntervalSeconds,
+				NoDataState:             r.New.NoDataState,
+				ExecErrState:            r.New.ExecErrState,
+				For:                     r.New.For,
+				Annotations:             r.New.Annotations,
+				Labels:                  r.New.Labels,
+				NotificationSettings:    r.New.NotificationSettings,
+				EvaluationOffsetSeconds: r.New.EvaluationOffsetSeconds,
 			})
 		}
 		if len(ruleVersions) > 0 {
@@ -353,6 +358,21 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 			q = q.Where("rule_group = ?", query.RuleGroup)
 		}
 
+		if query.ReceiverName != "" {
+			// The notification settings are stored as JSON, so the rules are preselected by the encoded name and
+			// matched exactly once they are decoded. Names with escaped characters are not preselected, because the
+			// backslash is the escape character of LIKE in some databases.
+			encoded, err := json.Marshal(query.ReceiverName)
+			if err != nil {
+				return err
+			}
+			if strings.Contains(string(encoded), "\\") {
+				q = q.Where("notification_settings IS NOT NULL")
+			} else {
+				q = q.Where("notification_settings LIKE ?", "%"+string(encoded)+"%")
+			}
+		}
+
 		q = q.Asc("namespace_uid", "rule_group", "rule_group_idx", "id")
 
 		alertRules := make([]*ngmodels.AlertRule, 0)
@@ -373,6 +393,9 @@ func (st DBstore) ListAlertRules(ctx context.Context, query *ngmodels.ListAlertR
 				st.Logger.Error("Invalid rule found in DB store, ignoring it", "func", "ListAlertRules", "error", err)
 				continue
 			}
+			if query.ReceiverName != "" && !rule.UsesReceiver(query.ReceiverName) {
+				continue
+			}
 			alertRules = append(alertRules, rule)
 		}
 
@@ -405,6 +428,25 @@ func (st DBstore) Count(ctx context.Context, orgID int64) (int64, error) {
 	return r.Count, err
 }
 
+// ListNotificationSettings returns the notification settings of all alert rules of the org that have them.
+func (st DBstore) ListNotificationSettings(ctx context.Context, orgID int64) ([]ngmodels.NotificationSettings, error) {
+	var result []ngmodels.NotificationSettings
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		rules := make([]ngmodels.AlertRule, 0)
+		err := sess.Table(ngmodels.AlertRule{}).Cols("notification_settings").
+			Where("org_id = ? AND notification_settings IS NOT NULL AND notification_settings <> '' AND notification_settings <> 'null'", orgID).
+			Find(&rules)
+		if err != nil {
+			return err
+		}
+		for _, rule := range rules {
+			result = append(result, rule.NotificationSettings...)
+		}
+		return nil
+	})
+	return result, err
+}
+
 func (st DBstore) GetRuleGroupInterval(ctx context.Context, orgID int64, namespaceUID string, ruleGroup string) (int64, error) {
 	var interval int64 = 0
 	return interval, st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
@@ -676,5 +718,14 @@ func (st DBstore) validateAlertRule(alertRule ngmodels.AlertRule) error {
 	if alertRule.For < 0 {
 		return fmt.Errorf("%w: field `for` cannot be negative", ngmodels.ErrAlertRuleFailedValidation)
 	}
+
+	if len(alertRule.NotificationSettings) > 1 {
+		return fmt.Errorf("%w: at most one notification settings can be specified", ngmodels.ErrAlertRuleFailedValidation)
+	}
+	for _, settings := range alertRule.NotificationSettings {
+		if err := settings.Validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
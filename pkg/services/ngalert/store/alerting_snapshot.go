@@ -0,0 +1,79 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ErrNoAlertingSnapshot is returned when no snapshot of the alerting configuration matches a query.
+var ErrNoAlertingSnapshot = errors.New("could not find an alerting configuration snapshot")
+
+// InsertAlertingSnapshot saves a snapshot of the alerting configuration of an organization.
+func (st DBstore) InsertAlertingSnapshot(ctx context.Context, snapshot *models.AlertingSnapshot) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(snapshot); err != nil {
+			return fmt.Errorf("failed to insert alerting snapshot: %w", err)
+		}
+		return nil
+	})
+}
+
+// GetAlertingSnapshots returns the snapshots of the organization, most recent first. Only the ID, organization and
+// creation time of the snapshots are loaded.
+func (st DBstore) GetAlertingSnapshots(ctx context.Context, orgID int64) ([]models.AlertingSnapshot, error) {
+	var result []models.AlertingSnapshot
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(models.AlertingSnapshot{}).
+			Cols("id", "org_id", "created").
+			Where("org_id = ?", orgID).
+			Desc("created", "id").
+			Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query alerting snapshots: %w", err)
+	}
+	return result, nil
+}
+
+// GetAlertingSnapshot returns the most recent snapshot of the organization taken at or before the given Unix time.
+// It returns ErrNoAlertingSnapshot if there is none.
+func (st DBstore) GetAlertingSnapshot(ctx context.Context, orgID int64, at int64) (*models.AlertingSnapshot, error) {
+	result := &models.AlertingSnapshot{}
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		ok, err := sess.Table(models.AlertingSnapshot{}).
+			Where("org_id = ? AND created <= ?", orgID, at).
+			Desc("created", "id").
+			Limit(1).
+			Get(result)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNoAlertingSnapshot
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// DeleteAlertingSnapshotsBefore deletes the snapshots of all organizations taken before the given Unix time and
+// returns how many were deleted.
+func (st DBstore) DeleteAlertingSnapshotsBefore(ctx context.Context, before int64) (int64, error) {
+	var deleted int64
+	err := st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		var err error
+		deleted, err = sess.Where("created < ?", before).Delete(&models.AlertingSnapshot{})
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete alerting snapshots: %w", err)
+	}
+	return deleted, nil
+}
@@ -0,0 +1,64 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationAlertingSnapshots(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, dbstore := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	ctx := context.Background()
+
+	snapshots := []models.AlertingSnapshot{
+		{OrgID: 1, AlertmanagerConfiguration: "config-1", Rules: "[]", Provenances: "{}", Created: 100},
+		{OrgID: 1, AlertmanagerConfiguration: "config-2", Rules: "[]", Provenances: "{}", Created: 200},
+		{OrgID: 2, AlertmanagerConfiguration: "config-3", Rules: "[]", Provenances: "{}", Created: 300},
+	}
+	for i := range snapshots {
+		require.NoError(t, dbstore.InsertAlertingSnapshot(ctx, &snapshots[i]))
+		require.NotZero(t, snapshots[i].ID)
+	}
+
+	t.Run("lists the snapshots of the organization, most recent first", func(t *testing.T) {
+		result, err := dbstore.GetAlertingSnapshots(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []models.AlertingSnapshot{
+			{ID: snapshots[1].ID, OrgID: 1, Created: 200},
+			{ID: snapshots[0].ID, OrgID: 1, Created: 100},
+		}, result)
+	})
+
+	t.Run("returns the most recent snapshot at a point in time", func(t *testing.T) {
+		result, err := dbstore.GetAlertingSnapshot(ctx, 1, 250)
+		require.NoError(t, err)
+		require.Equal(t, snapshots[1], *result)
+
+		result, err = dbstore.GetAlertingSnapshot(ctx, 1, 199)
+		require.NoError(t, err)
+		require.Equal(t, snapshots[0], *result)
+
+		_, err = dbstore.GetAlertingSnapshot(ctx, 1, 99)
+		require.ErrorIs(t, err, store.ErrNoAlertingSnapshot)
+	})
+
+	t.Run("deletes the snapshots taken before a point in time", func(t *testing.T) {
+		deleted, err := dbstore.DeleteAlertingSnapshotsBefore(ctx, 250)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), deleted)
+
+		result, err := dbstore.GetAlertingSnapshots(ctx, 1)
+		require.NoError(t, err)
+		require.Empty(t, result)
+		_, err = dbstore.GetAlertingSnapshot(ctx, 2, 300)
+		require.NoError(t, err)
+	})
+}
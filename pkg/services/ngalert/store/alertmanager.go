@@ -22,13 +22,13 @@ var (
 	ConfigRecordsLimit int = 100
 )
 
-// GetLatestAlertmanagerConfiguration returns the lastest version of the alertmanager configuration.
-// It returns ErrNoAlertmanagerConfiguration if no configuration is found.
+// GetLatestAlertmanagerConfiguration returns the lastest version of the alertmanager configuration, materialized from
+// its resources if they are stored separately. It returns ErrNoAlertmanagerConfiguration if no configuration is found.
 func (st *DBstore) GetLatestAlertmanagerConfiguration(ctx context.Context, query *models.GetLatestAlertmanagerConfigurationQuery) (result *models.AlertConfiguration, err error) {
 	err = st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
 		c := &models.AlertConfiguration{}
 		// The ID is already an auto incremental column, using the ID as an order should guarantee the latest.
-		ok, err := sess.Table("alert_configuration").Where("org_id = ?", query.OrgID).Get(c)
+		ok, err := st.configReadSession(sess).Table("alert_configuration").Where("org_id = ?", query.OrgID).Get(c)
 		if err != nil {
 			return err
 		}
@@ -37,6 +37,14 @@ func (st *DBstore) GetLatestAlertmanagerConfiguration(ctx context.Context, query
 			return ErrNoAlertmanagerConfiguration
 		}
 
+		resources, err := getAlertConfigurationResources(st.configReadSession(sess), query.OrgID)
+		if err != nil {
+			return err
+		}
+		if c.AlertmanagerConfiguration, err = materializeAlertmanagerConfiguration(c.AlertmanagerConfiguration, resources); err != nil {
+			return err
+		}
+
 		result = c
 		return nil
 	})
@@ -47,10 +55,10 @@ func (st *DBstore) GetLatestAlertmanagerConfiguration(ctx context.Context, query
 func (st *DBstore) GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([]*models.AlertConfiguration, error) {
 	var result []*models.AlertConfiguration
 	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
-		if err := sess.Table("alert_configuration").Find(&result); err != nil {
+		if err := st.configReadSession(sess).Table("alert_configuration").Find(&result); err != nil {
 			return err
 		}
-		return nil
+		return materializeAlertmanagerConfigurations(st.configReadSession(sess), result)
 	})
 	if err != nil {
 		return nil, err
@@ -58,6 +66,15 @@ func (st *DBstore) GetAllLatestAlertmanagerConfiguration(ctx context.Context) ([
 	return result, nil
 }
 
+// configReadSession enables prepared statements on the session if configured to do so. The flag is set on sessions
+// of transactions as well, but the engine only prepares statements of sessions that are not in a transaction.
+func (st *DBstore) configReadSession(sess *db.Session) *db.Session {
+	if st.Cfg.AlertmanagerConfigPreparedStatements {
+		sess.Prepare()
+	}
+	return sess
+}
+
 // SaveAlertmanagerConfiguration creates an alertmanager configuration.
 func (st DBstore) SaveAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
 	return st.SaveAlertmanagerConfigurationWithCallback(ctx, cmd, func() error { return nil })
@@ -76,18 +93,22 @@ func (st DBstore) SaveAlertmanagerConfigurationWithCallback(ctx context.Context,
 			Default:                   cmd.Default,
 			OrgID:                     cmd.OrgID,
 			CreatedAt:                 time.Now().Unix(),
+			CorrelationID:             cmd.CorrelationID,
 		}
 
 		// TODO: If we are more structured around how we seed configurations in the future, this can be a pure update instead of upsert. This should improve perf and code clarity.
 		upsertSQL := st.SQLStore.GetDialect().UpsertSQL(
 			"alert_configuration",
 			[]string{"org_id"},
-			[]string{"alertmanager_configuration", "configuration_version", "created_at", "default", "org_id", "configuration_hash"},
+			[]string{"alertmanager_configuration", "configuration_version", "created_at", "default", "org_id", "configuration_hash", "correlation_id"},
 		)
-		params := append(make([]any, 0), cmd.AlertmanagerConfiguration, cmd.ConfigurationVersion, config.CreatedAt, config.Default, config.OrgID, config.ConfigurationHash)
+		params := append(make([]any, 0), cmd.AlertmanagerConfiguration, cmd.ConfigurationVersion, config.CreatedAt, config.Default, config.OrgID, config.ConfigurationHash, config.CorrelationID)
 		if _, err := sess.SQL(upsertSQL, params...).Query(); err != nil {
 			return err
 		}
+		if err := deleteAlertConfigurationResources(sess, cmd.OrgID); err != nil {
+			return err
+		}
 
 		historicConfig := models.HistoricConfigFromAlertConfig(config)
 		historicConfig.LastApplied = cmd.LastApplied
@@ -108,6 +129,7 @@ func (st DBstore) SaveAlertmanagerConfigurationWithCallback(ctx context.Context,
 }
 
 // UpdateAlertmanagerConfiguration replaces an alertmanager configuration with optimistic locking. It assumes that an existing revision of the configuration exists in the store, and will return an error otherwise.
+// The resources of the configuration that were stored separately are replaced by the ones in the new configuration.
 func (st *DBstore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *models.SaveAlertmanagerConfigurationCmd) error {
 	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
 		config := models.AlertConfiguration{
@@ -117,9 +139,11 @@ func (st *DBstore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *mod
 			Default:                   cmd.Default,
 			OrgID:                     cmd.OrgID,
 			CreatedAt:                 time.Now().Unix(),
+			CorrelationID:             cmd.CorrelationID,
 		}
 		rows, err := sess.Table("alert_configuration").
 			Where("org_id = ? AND configuration_hash = ?", config.OrgID, cmd.FetchedConfigurationHash).
+			MustCols("correlation_id").
 			Update(config)
 		if err != nil {
 			return err
@@ -127,6 +151,9 @@ func (st *DBstore) UpdateAlertmanagerConfiguration(ctx context.Context, cmd *mod
 		if rows == 0 {
 			return ErrVersionLockedObjectNotFound
 		}
+		if err := deleteAlertConfigurationResources(sess, cmd.OrgID); err != nil {
+			return err
+		}
 
 		historicConfig := models.HistoricConfigFromAlertConfig(config)
 		if _, err := sess.Table("alert_configuration_history").Insert(historicConfig); err != nil {
@@ -189,6 +216,26 @@ func (st *DBstore) GetAppliedConfigurations(ctx context.Context, orgID int64, li
 	return configs, nil
 }
 
+// GetAlertmanagerConfigurationHistory returns the saved configurations of the org, applied or not, ordered
+// newest -> oldest by id.
+func (st *DBstore) GetAlertmanagerConfigurationHistory(ctx context.Context, orgID int64, limit int) ([]*models.HistoricAlertConfiguration, error) {
+	if limit < 1 || limit > ConfigRecordsLimit {
+		limit = ConfigRecordsLimit
+	}
+
+	configs := []*models.HistoricAlertConfiguration{}
+	if err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table("alert_configuration_history").
+			Desc("id").
+			Where("org_id = ?", orgID).
+			Limit(limit).
+			Find(&configs)
+	}); err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
 // GetHistoricalConfiguration returns a single historical configuration based on provided org and id.
 func (st *DBstore) GetHistoricalConfiguration(ctx context.Context, orgID int64, id int64) (*models.HistoricAlertConfiguration, error) {
 	var config models.HistoricAlertConfiguration
@@ -0,0 +1,462 @@
+package store
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// ErrIncrementalConfigUpdateUnsupported is returned by UpdateAlertmanagerConfigResources if the configuration cannot be
+// changed one resource at a time, for example because several of its receivers have the same name. The whole
+// configuration has to be saved instead.
+var ErrIncrementalConfigUpdateUnsupported = errors.New("the Alertmanager configuration cannot be changed one resource at a time")
+
+// Keys of the configuration document that hold the resources that are stored separately.
+const (
+	configKeyAlertmanagerConfig = "alertmanager_config"
+	configKeyTemplateFiles      = "template_files"
+	configKeyReceivers          = "receivers"
+	configKeyMuteTimeIntervals  = "mute_time_intervals"
+	configKeyTemplates          = "templates"
+)
+
+// listResources are the kinds of resources that are lists in the alertmanager_config object, with their key.
+var listResources = []struct {
+	kind models.AlertConfigurationResourceKind
+	key  string
+}{
+	{kind: models.AlertConfigurationResourceReceiver, key: configKeyReceivers},
+	{kind: models.AlertConfigurationResourceMuteTimeInterval, key: configKeyMuteTimeIntervals},
+}
+
+// UpdateAlertmanagerConfigResources changes single receivers, mute time intervals and templates of the Alertmanager
+// configuration of an organization with optimistic locking. Only the changed resources are written instead of the whole
+// configuration. The first such change moves all resources of the organization out of the stored configuration
+// document. The materialized configuration is still added to the history like with UpdateAlertmanagerConfiguration,
+// but it is assembled from the stored JSON of the resources without decoding any of them.
+func (st *DBstore) UpdateAlertmanagerConfigResources(ctx context.Context, cmd *models.UpdateAlertmanagerConfigResourcesCmd) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		current := models.AlertConfiguration{}
+		ok, err := sess.Table("alert_configuration").Where("org_id = ?", cmd.OrgID).Get(&current)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrNoAlertmanagerConfiguration
+		}
+		if current.ConfigurationHash != cmd.FetchedConfigurationHash {
+			return ErrVersionLockedObjectNotFound
+		}
+
+		resources, err := getAlertConfigurationResources(sess, cmd.OrgID)
+		if err != nil {
+			return err
+		}
+		base := current.AlertmanagerConfiguration
+		// Without resources, the document is the full configuration and has to be split first.
+		split := len(resources) == 0
+		if split {
+			base, resources, err = splitAlertmanagerConfiguration(cmd.OrgID, base)
+			if err != nil {
+				return err
+			}
+		}
+		now := time.Now().Unix()
+		resources, writes, err := applyResourceChanges(cmd.OrgID, resources, cmd.Changes, now)
+		if err != nil {
+			return err
+		}
+		materialized, err := materializeAlertmanagerConfiguration(base, resources)
+		if err != nil {
+			return err
+		}
+
+		config := models.AlertConfiguration{
+			AlertmanagerConfiguration: base,
+			ConfigurationHash:         fmt.Sprintf("%x", md5.Sum([]byte(materialized))),
+			ConfigurationVersion:      cmd.ConfigurationVersion,
+			OrgID:                     cmd.OrgID,
+			CreatedAt:                 now,
+			CorrelationID:             cmd.CorrelationID,
+		}
+		// The stored document only changes if it was split.
+		cols := []string{"configuration_hash", "configuration_version", "created_at", "correlation_id"}
+		if split {
+			cols = append(cols, "alertmanager_configuration")
+		}
+		rows, err := sess.Table("alert_configuration").
+			Where("org_id = ? AND configuration_hash = ?", cmd.OrgID, cmd.FetchedConfigurationHash).
+			Cols(cols...).
+			Update(config)
+		if err != nil {
+			return err
+		}
+		if rows == 0 {
+			return ErrVersionLockedObjectNotFound
+		}
+
+		if split {
+			for _, r := range resources {
+				r.Updated = now
+				if _, err := sess.Insert(r); err != nil {
+					return fmt.Errorf("failed to insert Alertmanager configuration resource: %w", err)
+				}
+			}
+		} else {
+			for _, w := range writes {
+				if err := w.apply(sess); err != nil {
+					return fmt.Errorf("failed to write Alertmanager configuration resource: %w", err)
+				}
+			}
+		}
+
+		historicConfig := models.HistoricConfigFromAlertConfig(config)
+		historicConfig.AlertmanagerConfiguration = materialized
+		if _, err := sess.Table("alert_configuration_history").Insert(historicConfig); err != nil {
+			return err
+		}
+		if _, err := st.deleteOldConfigurations(ctx, cmd.OrgID, ConfigRecordsLimit); err != nil {
+			st.Logger.Warn("Failed to delete old am configs", "org", cmd.OrgID, "error", err)
+		}
+		return nil
+	})
+}
+
+// getAlertConfigurationResources returns the resources of the configuration of the organization in the order they
+// were added in.
+func getAlertConfigurationResources(sess *db.Session, orgID int64) ([]*models.AlertConfigurationResource, error) {
+	var resources []*models.AlertConfigurationResource
+	if err := sess.Table(models.AlertConfigurationResource{}).Where("org_id = ?", orgID).Asc("id").Find(&resources); err != nil {
+		return nil, fmt.Errorf("failed to query Alertmanager configuration resources: %w", err)
+	}
+	return resources, nil
+}
+
+// materializeAlertmanagerConfigurations replaces the stored documents of the configurations that have resources with
+// the full configurations.
+func materializeAlertmanagerConfigurations(sess *db.Session, configs []*models.AlertConfiguration) error {
+	var resources []*models.AlertConfigurationResource
+	if err := sess.Table(models.AlertConfigurationResource{}).Asc("org_id", "id").Find(&resources); err != nil {
+		return fmt.Errorf("failed to query Alertmanager configuration resources: %w", err)
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	byOrg := make(map[int64][]*models.AlertConfigurationResource)
+	for _, r := range resources {
+		byOrg[r.OrgID] = append(byOrg[r.OrgID], r)
+	}
+	for _, c := range configs {
+		materialized, err := materializeAlertmanagerConfiguration(c.AlertmanagerConfiguration, byOrg[c.OrgID])
+		if err != nil {
+			return fmt.Errorf("failed to materialize Alertmanager configuration of org %d: %w", c.OrgID, err)
+		}
+		c.AlertmanagerConfiguration = materialized
+	}
+	return nil
+}
+
+// deleteAlertConfigurationResources deletes the resources of the configuration of the organization. It is called when
+// a full configuration is saved, which then is the stored document.
+func deleteAlertConfigurationResources(sess *db.Session, orgID int64) error {
+	if _, err := sess.Where("org_id = ?", orgID).Delete(&models.AlertConfigurationResource{}); err != nil {
+		return fmt.Errorf("failed to delete Alertmanager configuration resources: %w", err)
+	}
+	return nil
+}
+
+// splitAlertmanagerConfiguration separates the receivers, mute time intervals and templates of a configuration
+// document from the rest of the document. The list of template names is left out as well, as it is derived from the
+// templates when the configuration is materialized.
+func splitAlertmanagerConfiguration(orgID int64, document string) (string, []*models.AlertConfigurationResource, error) {
+	top, am, err := unmarshalConfigurationDocument(document)
+	if err != nil {
+		return "", nil, err
+	}
+
+	var resources []*models.AlertConfigurationResource
+	seen := make(map[models.AlertConfigurationResourceKind]map[string]struct{})
+	add := func(kind models.AlertConfigurationResourceKind, name string, content json.RawMessage) error {
+		if seen[kind] == nil {
+			seen[kind] = make(map[string]struct{})
+		}
+		if _, ok := seen[kind][name]; ok {
+			return fmt.Errorf("%w: there are several resources of kind %s named '%s'", ErrIncrementalConfigUpdateUnsupported, kind, name)
+		}
+		seen[kind][name] = struct{}{}
+		resources = append(resources, &models.AlertConfigurationResource{OrgID: orgID, Kind: kind, Name: name, Content: string(content)})
+		return nil
+	}
+
+	for _, list := range listResources {
+		var items []json.RawMessage
+		if raw, ok := am[list.key]; ok {
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return "", nil, fmt.Errorf("failed to parse %s of Alertmanager configuration: %w", list.key, err)
+			}
+		}
+		for _, item := range items {
+			var named struct {
+				Name string `json:"name"`
+			}
+			if err := json.Unmarshal(item, &named); err != nil {
+				return "", nil, fmt.Errorf("failed to parse %s of Alertmanager configuration: %w", list.key, err)
+			}
+			if err := add(list.kind, named.Name, item); err != nil {
+				return "", nil, err
+			}
+		}
+		delete(am, list.key)
+	}
+
+	var templates map[string]json.RawMessage
+	if raw, ok := top[configKeyTemplateFiles]; ok {
+		if err := json.Unmarshal(raw, &templates); err != nil {
+			return "", nil, fmt.Errorf("failed to parse %s of Alertmanager configuration: %w", configKeyTemplateFiles, err)
+		}
+	}
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := add(models.AlertConfigurationResourceTemplate, name, templates[name]); err != nil {
+			return "", nil, err
+		}
+	}
+	delete(top, configKeyTemplateFiles)
+	delete(am, configKeyTemplates)
+
+	base, err := marshalConfigurationDocument(top, am)
+	if err != nil {
+		return "", nil, err
+	}
+	return base, resources, nil
+}
+
+// materializeAlertmanagerConfiguration returns the full configuration document of a stored document and the resources
+// that were moved out of it. The resources are copied into the document as they are, without being decoded or encoded
+// again, so that only the stored document, which is small once the resources are moved out, is parsed.
+func materializeAlertmanagerConfiguration(base string, resources []*models.AlertConfigurationResource) (string, error) {
+	if len(resources) == 0 {
+		return base, nil
+	}
+	top, am, err := unmarshalConfigurationDocument(base)
+	if err != nil {
+		return "", err
+	}
+
+	lists := make(map[models.AlertConfigurationResourceKind][]string, len(listResources))
+	templates := make(map[string]string)
+	names := make([]string, 0)
+	for _, r := range resources {
+		if r.Kind == models.AlertConfigurationResourceTemplate {
+			templates[r.Name] = r.Content
+			names = append(names, r.Name)
+			continue
+		}
+		lists[r.Kind] = append(lists[r.Kind], r.Content)
+	}
+
+	var amMembers, topMembers []string
+	for _, list := range listResources {
+		items, ok := lists[list.kind]
+		if !ok {
+			continue
+		}
+		delete(am, list.key)
+		amMembers = append(amMembers, jsonMember(list.key, "["+strings.Join(items, ",")+"]"))
+	}
+	if len(templates) > 0 {
+		sort.Strings(names)
+		files := make([]string, 0, len(names))
+		for _, name := range names {
+			files = append(files, jsonMember(name, templates[name]))
+		}
+		delete(top, configKeyTemplateFiles)
+		topMembers = append(topMembers, jsonMember(configKeyTemplateFiles, "{"+strings.Join(files, ",")+"}"))
+		data, err := json.Marshal(names)
+		if err != nil {
+			return "", err
+		}
+		am[configKeyTemplates] = data
+	}
+
+	amDocument, err := marshalWithMembers(am, amMembers)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s of Alertmanager configuration: %w", configKeyAlertmanagerConfig, err)
+	}
+	delete(top, configKeyAlertmanagerConfig)
+	topMembers = append(topMembers, jsonMember(configKeyAlertmanagerConfig, amDocument))
+	document, err := marshalWithMembers(top, topMembers)
+	if err != nil {
+		return "", fmt.Errorf("failed to write Alertmanager configuration: %w", err)
+	}
+	return document, nil
+}
+
+// jsonMember returns the member of a JSON object with the given key and JSON value.
+func jsonMember(key string, value string) string {
+	// Marshaling a string does not fail.
+	k, _ := json.Marshal(key)
+	return string(k) + ":" + value
+}
+
+// marshalWithMembers encodes an object and appends members whose values are JSON already to it.
+func marshalWithMembers(object map[string]json.RawMessage, members []string) (string, error) {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return "", err
+	}
+	if len(members) == 0 {
+		return string(data), nil
+	}
+	var b strings.Builder
+	b.Write(data[:len(data)-1])
+	if len(object) > 0 {
+		b.WriteByte(',')
+	}
+	b.WriteString(strings.Join(members, ","))
+	b.WriteByte('}')
+	return b.String(), nil
+}
+
+func unmarshalConfigurationDocument(document string) (map[string]json.RawMessage, map[string]json.RawMessage, error) {
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(document), &top); err != nil {
+		return nil, nil, fmt.Errorf("failed to parse Alertmanager configuration: %w", err)
+	}
+	if top == nil {
+		top = make(map[string]json.RawMessage)
+	}
+	var am map[string]json.RawMessage
+	if raw, ok := top[configKeyAlertmanagerConfig]; ok {
+		if err := json.Unmarshal(raw, &am); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s of Alertmanager configuration: %w", configKeyAlertmanagerConfig, err)
+		}
+	}
+	if am == nil {
+		am = make(map[string]json.RawMessage)
+	}
+	return top, am, nil
+}
+
+func marshalConfigurationDocument(top map[string]json.RawMessage, am map[string]json.RawMessage) (string, error) {
+	data, err := json.Marshal(am)
+	if err != nil {
+		return "", fmt.Errorf("failed to write %s of Alertmanager configuration: %w", configKeyAlertmanagerConfig, err)
+	}
+	top[configKeyAlertmanagerConfig] = data
+	document, err := json.Marshal(top)
+	if err != nil {
+		return "", fmt.Errorf("failed to write Alertmanager configuration: %w", err)
+	}
+	return string(document), nil
+}
+
+// applyResourceChanges applies the changes to the resources of a configuration. It returns the changed resources in
+// their order in the configuration and the writes of the rows that persist the changes.
+func applyResourceChanges(orgID int64, resources []*models.AlertConfigurationResource, changes []models.AlertConfigurationResourceChange, now int64) ([]*models.AlertConfigurationResource, []resourceWrite, error) {
+	find := func(kind models.AlertConfigurationResourceKind, name string) int {
+		for i, r := range resources {
+			if r.Kind == kind && r.Name == name {
+				return i
+			}
+		}
+		return -1
+	}
+
+	writes := make([]resourceWrite, 0, len(changes))
+	for _, change := range changes {
+		if err := validateResourceChange(change); err != nil {
+			return nil, nil, err
+		}
+		name := change.Name
+		if change.OldName != "" {
+			name = change.OldName
+		}
+		i := find(change.Kind, name)
+
+		if change.Content == "" {
+			if i < 0 {
+				continue
+			}
+			resources = append(resources[:i], resources[i+1:]...)
+			writes = append(writes, resourceWrite{orgID: orgID, kind: change.Kind, name: name})
+			continue
+		}
+
+		if j := find(change.Kind, change.Name); j >= 0 && j != i {
+			return nil, nil, fmt.Errorf("%w: there is another resource of kind %s named '%s'", ErrIncrementalConfigUpdateUnsupported, change.Kind, change.Name)
+		}
+		resource := &models.AlertConfigurationResource{
+			OrgID:   orgID,
+			Kind:    change.Kind,
+			Name:    change.Name,
+			Content: change.Content,
+			Updated: now,
+		}
+		if i < 0 {
+			resources = append(resources, resource)
+			writes = append(writes, resourceWrite{orgID: orgID, kind: change.Kind, name: change.Name, resource: resource, insert: true})
+			continue
+		}
+		resource.ID = resources[i].ID
+		resources[i] = resource
+		writes = append(writes, resourceWrite{orgID: orgID, kind: change.Kind, name: name, resource: resource})
+	}
+	return resources, writes, nil
+}
+
+func validateResourceChange(change models.AlertConfigurationResourceChange) error {
+	switch change.Kind {
+	case models.AlertConfigurationResourceReceiver, models.AlertConfigurationResourceMuteTimeInterval, models.AlertConfigurationResourceTemplate:
+	default:
+		return fmt.Errorf("unknown kind of Alertmanager configuration resource '%s'", change.Kind)
+	}
+	if change.Name == "" {
+		return fmt.Errorf("the name of a resource of kind %s is empty", change.Kind)
+	}
+	if change.Content != "" && !json.Valid([]byte(change.Content)) {
+		return fmt.Errorf("the content of the resource of kind %s named '%s' is not valid JSON", change.Kind, change.Name)
+	}
+	return nil
+}
+
+// resourceWrite is a write of a single resource row. Rows are identified by their kind and name, as the resources that
+// were added in the same transaction do not have an ID yet.
+type resourceWrite struct {
+	orgID int64
+	kind  models.AlertConfigurationResourceKind
+	// name identifies the row that is updated or deleted.
+	name string
+	// resource is the row to insert or the new values of the updated row, and nil if the row is deleted.
+	resource *models.AlertConfigurationResource
+	insert   bool
+}
+
+func (w resourceWrite) apply(sess *db.Session) error {
+	switch {
+	case w.insert:
+		_, err := sess.Insert(w.resource)
+		return err
+	case w.resource == nil:
+		_, err := sess.Where("org_id = ? AND kind = ? AND name = ?", w.orgID, w.kind, w.name).Delete(&models.AlertConfigurationResource{})
+		return err
+	default:
+		_, err := sess.Table(models.AlertConfigurationResource{}).
+			Where("org_id = ? AND kind = ? AND name = ?", w.orgID, w.kind, w.name).
+			Cols("name", "content", "updated").
+			Update(w.resource)
+		return err
+	}
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"crypto/md5"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+const resourcesTestConfig = `{
+	"template_files": {"a": "{{ define \"a\" }}{{ end }}"},
+	"alertmanager_config": {
+		"route": {"receiver": "a"},
+		"templates": ["a"],
+		"receivers": [{"name": "a", "grafana_managed_receiver_configs": []}, {"name": "b", "grafana_managed_receiver_configs": []}],
+		"mute_time_intervals": [{"name": "m", "time_intervals": []}]
+	}
+}`
+
+func TestSplitAlertmanagerConfiguration(t *testing.T) {
+	t.Run("the materialized configuration equals the split one", func(t *testing.T) {
+		base, resources, err := splitAlertmanagerConfiguration(1, resourcesTestConfig)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"alertmanager_config": {"route": {"receiver": "a"}}}`, base)
+		require.Len(t, resources, 4)
+
+		materialized, err := materializeAlertmanagerConfiguration(base, resources)
+		require.NoError(t, err)
+		require.JSONEq(t, resourcesTestConfig, materialized)
+	})
+
+	t.Run("configurations with resources of the same name cannot be split", func(t *testing.T) {
+		_, _, err := splitAlertmanagerConfiguration(1, `{"alertmanager_config": {"receivers": [{"name": "a"}, {"name": "a"}]}}`)
+		require.ErrorIs(t, err, ErrIncrementalConfigUpdateUnsupported)
+	})
+}
+
+func TestIntegrationAlertmanagerConfigResources(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	sqlStore := db.InitTestDB(t)
+	store := &DBstore{
+		SQLStore: sqlStore,
+		Logger:   log.NewNopLogger(),
+	}
+	ctx := context.Background()
+	query := &models.GetLatestAlertmanagerConfigurationQuery{OrgID: 1}
+
+	t.Run("changes single resources and materializes the configuration", func(t *testing.T) {
+		_, configMD5 := setupConfig(t, resourcesTestConfig, store)
+
+		err := store.UpdateAlertmanagerConfigResources(ctx, &models.UpdateAlertmanagerConfigResourcesCmd{
+			OrgID:                    1,
+			FetchedConfigurationHash: configMD5,
+			ConfigurationVersion:     "v1",
+			Changes: []models.AlertConfigurationResourceChange{
+				{Kind: models.AlertConfigurationResourceTemplate, Name: "b", Content: `"{{ define \"b\" }}{{ end }}"`},
+				{Kind: models.AlertConfigurationResourceReceiver, Name: "c", OldName: "b", Content: `{"name": "c", "grafana_managed_receiver_configs": []}`},
+				{Kind: models.AlertConfigurationResourceMuteTimeInterval, Name: "m"},
+			},
+		})
+		require.NoError(t, err)
+
+		config, err := store.GetLatestAlertmanagerConfiguration(ctx, query)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"template_files": {"a": "{{ define \"a\" }}{{ end }}", "b": "{{ define \"b\" }}{{ end }}"},
+			"alertmanager_config": {
+				"route": {"receiver": "a"},
+				"templates": ["a", "b"],
+				"receivers": [{"name": "a", "grafana_managed_receiver_configs": []}, {"name": "c", "grafana_managed_receiver_configs": []}]
+			}
+		}`, config.AlertmanagerConfiguration)
+		require.Equal(t, fmt.Sprintf("%x", md5.Sum([]byte(config.AlertmanagerConfiguration))), config.ConfigurationHash)
+
+		all, err := store.GetAllLatestAlertmanagerConfiguration(ctx)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+		require.Equal(t, config.AlertmanagerConfiguration, all[0].AlertmanagerConfiguration)
+
+		// Resources that are already stored separately are changed in place.
+		err = store.UpdateAlertmanagerConfigResources(ctx, &models.UpdateAlertmanagerConfigResourcesCmd{
+			OrgID:                    1,
+			FetchedConfigurationHash: config.ConfigurationHash,
+			Changes: []models.AlertConfigurationResourceChange{
+				{Kind: models.AlertConfigurationResourceReceiver, Name: "a", Content: `{"name": "a"}`},
+				{Kind: models.AlertConfigurationResourceTemplate, Name: "a"},
+			},
+		})
+		require.NoError(t, err)
+
+		config, err = store.GetLatestAlertmanagerConfiguration(ctx, query)
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"template_files": {"b": "{{ define \"b\" }}{{ end }}"},
+			"alertmanager_config": {
+				"route": {"receiver": "a"},
+				"templates": ["b"],
+				"receivers": [{"name": "a"}, {"name": "c", "grafana_managed_receiver_configs": []}]
+			}
+		}`, config.AlertmanagerConfiguration)
+	})
+
+	t.Run("fails if the configuration was changed since it was read", func(t *testing.T) {
+		setupConfig(t, resourcesTestConfig, store)
+
+		err := store.UpdateAlertmanagerConfigResources(ctx, &models.UpdateAlertmanagerConfigResourcesCmd{
+			OrgID:                    1,
+			FetchedConfigurationHash: "the-wrong-hash",
+			Changes:                  []models.AlertConfigurationResourceChange{{Kind: models.AlertConfigurationResourceTemplate, Name: "a"}},
+		})
+		require.ErrorIs(t, err, ErrVersionLockedObjectNotFound)
+	})
+
+	t.Run("fails if a resource would be renamed to the name of another one", func(t *testing.T) {
+		_, configMD5 := setupConfig(t, resourcesTestConfig, store)
+
+		err := store.UpdateAlertmanagerConfigResources(ctx, &models.UpdateAlertmanagerConfigResourcesCmd{
+			OrgID:                    1,
+			FetchedConfigurationHash: configMD5,
+			Changes: []models.AlertConfigurationResourceChange{
+				{Kind: models.AlertConfigurationResourceReceiver, Name: "a", OldName: "b", Content: `{"name": "a"}`},
+			},
+		})
+		require.ErrorIs(t, err, ErrIncrementalConfigUpdateUnsupported)
+	})
+
+	t.Run("saving a full configuration replaces the separately stored resources", func(t *testing.T) {
+		_, configMD5 := setupConfig(t, resourcesTestConfig, store)
+		err := store.UpdateAlertmanagerConfigResources(ctx, &models.UpdateAlertmanagerConfigResourcesCmd{
+			OrgID:                    1,
+			FetchedConfigurationHash: configMD5,
+			Changes:                  []models.AlertConfigurationResourceChange{{Kind: models.AlertConfigurationResourceTemplate, Name: "a"}},
+		})
+		require.NoError(t, err)
+		config, err := store.GetLatestAlertmanagerConfiguration(ctx, query)
+		require.NoError(t, err)
+
+		err = store.UpdateAlertmanagerConfiguration(ctx, &models.SaveAlertmanagerConfigurationCmd{
+			AlertmanagerConfiguration: "my-config",
+			FetchedConfigurationHash:  config.ConfigurationHash,
+			ConfigurationVersion:      "v1",
+			OrgID:                     1,
+		})
+		require.NoError(t, err)
+
+		config, err = store.GetLatestAlertmanagerConfiguration(ctx, query)
+		require.NoError(t, err)
+		require.Equal(t, "my-config", config.AlertmanagerConfiguration)
+	})
+}
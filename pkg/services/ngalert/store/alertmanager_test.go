@@ -13,6 +13,7 @@ import (
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
 )
 
 func TestIntegrationAlertmanagerStore(t *testing.T) {
@@ -51,6 +52,24 @@ func TestIntegrationAlertmanagerStore(t *testing.T) {
 		require.Equal(t, configMD5, config.ConfigurationHash)
 	})
 
+	t.Run("GetLatestAlertmanagerConfiguration reads with prepared statements", func(t *testing.T) {
+		_, configMD5 := setupConfig(t, "my-config-prepared", store)
+		preparedStore := &DBstore{
+			Cfg:      setting.UnifiedAlertingSettings{AlertmanagerConfigPreparedStatements: true},
+			SQLStore: sqlStore,
+			Logger:   log.NewNopLogger(),
+		}
+		req := &models.GetLatestAlertmanagerConfigurationQuery{
+			OrgID: 1,
+		}
+
+		config, err := preparedStore.GetLatestAlertmanagerConfiguration(context.Background(), req)
+
+		require.NoError(t, err)
+		require.Equal(t, "my-config-prepared", config.AlertmanagerConfiguration)
+		require.Equal(t, configMD5, config.ConfigurationHash)
+	})
+
 	t.Run("GetLatestAlertmanagerConfiguration after saving multiple times should return the latest config", func(t *testing.T) {
 		_, _ = setupConfig(t, "my-config1", store)
 		_, _ = setupConfig(t, "my-config2", store)
@@ -149,12 +168,14 @@ func TestIntegrationAlertmanagerHash(t *testing.T) {
 			ConfigurationVersion:      "v1",
 			Default:                   false,
 			OrgID:                     1,
+			CorrelationID:             "trace-id",
 		})
 		require.NoError(t, err)
 		config, err = store.GetLatestAlertmanagerConfiguration(context.Background(), req)
 		require.NoError(t, err)
 		require.Equal(t, newConfig, config.AlertmanagerConfiguration)
 		require.Equal(t, newConfigMD5, config.ConfigurationHash)
+		require.Equal(t, "trace-id", config.CorrelationID)
 	})
 
 	t.Run("When passing the wrong hash the update should error", func(t *testing.T) {
@@ -57,6 +57,12 @@ func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 	for _, r := range existingGroupRules {
 		existingGroupRulesUIDs[r.UID] = r
 	}
+	// The evaluation offset is a property of the group that is not changed by writing its rules, and that rules moved
+	// to the group take over.
+	var evaluationOffset int64
+	if len(existingGroupRules) > 0 {
+		evaluationOffset = existingGroupRules[0].EvaluationOffsetSeconds
+	}
 
 	//nolint:prealloc // difficult logic
 	var toAdd []*models.AlertRule
@@ -93,6 +99,7 @@ func CalculateChanges(ctx context.Context, ruleReader RuleReader, groupKey model
 			}
 		}
 
+		r.EvaluationOffsetSeconds = evaluationOffset
 		if existing == nil {
 			toAdd = append(toAdd, &r.AlertRule)
 			continue
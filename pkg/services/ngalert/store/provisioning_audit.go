@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// defaultProvisioningAuditLimit is the number of audit entries returned when the query does not set a limit.
+const defaultProvisioningAuditLimit = 100
+
+// InsertProvisioningAuditEntry adds an entry to the provisioning audit log.
+func (st DBstore) InsertProvisioningAuditEntry(ctx context.Context, entry *models.ProvisioningAuditEntry) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		if _, err := sess.Insert(entry); err != nil {
+			return fmt.Errorf("failed to insert provisioning audit entry: %w", err)
+		}
+		return nil
+	})
+}
+
+// PublishAfterCommit publishes the event on the event bus once the transaction of the context is committed, or right
+// away if the context has no transaction.
+func (st DBstore) PublishAfterCommit(ctx context.Context, msg any) error {
+	return st.SQLStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+		sess.PublishAfterCommit(msg)
+		return nil
+	})
+}
+
+// GetProvisioningAuditEntries returns the entries of the provisioning audit log that match the query, most recent first.
+func (st DBstore) GetProvisioningAuditEntries(ctx context.Context, query models.ProvisioningAuditQuery) ([]models.ProvisioningAuditEntry, error) {
+	var result []models.ProvisioningAuditEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		q := sess.Table(models.ProvisioningAuditEntry{}).Where("org_id = ?", query.OrgID)
+		if query.ResourceType != "" {
+			q = q.And("resource_type = ?", query.ResourceType)
+		}
+		if query.ResourceID != "" {
+			q = q.And("resource_id = ?", query.ResourceID)
+		}
+		if !query.From.IsZero() {
+			q = q.And("created >= ?", query.From.Unix())
+		}
+		if !query.To.IsZero() {
+			q = q.And("created <= ?", query.To.Unix())
+		}
+		limit := query.Limit
+		if limit <= 0 {
+			limit = defaultProvisioningAuditLimit
+		}
+		return q.Desc("created", "id").Limit(limit).Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provisioning audit log: %w", err)
+	}
+	return result, nil
+}
+
+// GetResourceHistory returns a page of the changes made to a single resource through the provisioning services, most
+// recent first, along with the number of all changes of the resource. Unlike in GetProvisioningAuditEntries, an empty
+// resource ID selects the resource without an identifier, which is the notification policy tree of the organization.
+func (st DBstore) GetResourceHistory(ctx context.Context, orgID int64, resourceType, resourceID string, page models.ResourceHistoryPage) ([]models.ProvisioningAuditEntry, int64, error) {
+	var result []models.ProvisioningAuditEntry
+	var total int64
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		where := "org_id = ? AND resource_type = ? AND resource_id = ?"
+		var err error
+		total, err = sess.Table(models.ProvisioningAuditEntry{}).Where(where, orgID, resourceType, resourceID).Count()
+		if err != nil {
+			return err
+		}
+		limit := page.Limit
+		if limit <= 0 {
+			limit = defaultProvisioningAuditLimit
+		}
+		return sess.Table(models.ProvisioningAuditEntry{}).Where(where, orgID, resourceType, resourceID).
+			Desc("created", "id").Limit(limit, page.Offset).Find(&result)
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query history of %s %q: %w", resourceType, resourceID, err)
+	}
+	return result, total, nil
+}
+
+// GetLatestProvisioningAuditEntries returns the most recent entry of the provisioning audit log for every resource of
+// the organization, including resources that were deleted since.
+func (st DBstore) GetLatestProvisioningAuditEntries(ctx context.Context, orgID int64) ([]models.ProvisioningAuditEntry, error) {
+	var result []models.ProvisioningAuditEntry
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.Table(models.ProvisioningAuditEntry{}).
+			Where("id IN (SELECT MAX(id) FROM provisioning_audit_log WHERE org_id = ? GROUP BY resource_type, resource_id)", orgID).
+			Asc("resource_type", "resource_id").
+			Find(&result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query provisioning audit log: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/tests"
+)
+
+func TestIntegrationProvisioningAuditLog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, store := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	ctx := context.Background()
+
+	entries := []models.ProvisioningAuditEntry{
+		{OrgID: 1, Action: models.ProvisioningAuditActionCreate, ResourceType: "contactPoint", ResourceID: "a", NewState: `{"name":"a"}`, Created: 100},
+		{OrgID: 1, Action: models.ProvisioningAuditActionUpdate, ResourceType: "contactPoint", ResourceID: "a", OldState: `{"name":"a"}`, NewState: `{"name":"b"}`, Created: 200},
+		{OrgID: 1, Action: models.ProvisioningAuditActionDelete, ResourceType: "template", ResourceID: "t", Provenance: models.ProvenanceFile, Source: "/etc/grafana/provisioning/alerting/templates.yaml", OldState: `"{{ define }}"`, Created: 300},
+		{OrgID: 2, Action: models.ProvisioningAuditActionCreate, ResourceType: "contactPoint", ResourceID: "a", Created: 400},
+	}
+	for i := range entries {
+		require.NoError(t, store.InsertProvisioningAuditEntry(ctx, &entries[i]))
+		require.NotZero(t, entries[i].ID)
+	}
+
+	t.Run("returns the entries of the organization, most recent first", func(t *testing.T) {
+		result, err := store.GetProvisioningAuditEntries(ctx, models.ProvisioningAuditQuery{OrgID: 1})
+		require.NoError(t, err)
+		require.Equal(t, []models.ProvisioningAuditEntry{entries[2], entries[1], entries[0]}, result)
+	})
+
+	t.Run("filters by resource", func(t *testing.T) {
+		result, err := store.GetProvisioningAuditEntries(ctx, models.ProvisioningAuditQuery{OrgID: 1, ResourceType: "contactPoint", ResourceID: "a"})
+		require.NoError(t, err)
+		require.Equal(t, []models.ProvisioningAuditEntry{entries[1], entries[0]}, result)
+	})
+
+	t.Run("filters by time range", func(t *testing.T) {
+		result, err := store.GetProvisioningAuditEntries(ctx, models.ProvisioningAuditQuery{OrgID: 1, From: time.Unix(150, 0), To: time.Unix(250, 0)})
+		require.NoError(t, err)
+		require.Equal(t, []models.ProvisioningAuditEntry{entries[1]}, result)
+	})
+
+	t.Run("limits the number of entries", func(t *testing.T) {
+		result, err := store.GetProvisioningAuditEntries(ctx, models.ProvisioningAuditQuery{OrgID: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, []models.ProvisioningAuditEntry{entries[2]}, result)
+	})
+
+	t.Run("returns a page of the history of a resource with the number of its entries", func(t *testing.T) {
+		result, total, err := store.GetResourceHistory(ctx, 1, "contactPoint", "a", models.ResourceHistoryPage{Offset: 1, Limit: 1})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), total)
+		require.Equal(t, []models.ProvisioningAuditEntry{entries[0]}, result)
+	})
+
+	t.Run("returns the latest entry of every resource", func(t *testing.T) {
+		result, err := store.GetLatestProvisioningAuditEntries(ctx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []models.ProvisioningAuditEntry{entries[1], entries[2]}, result)
+	})
+}
@@ -103,3 +103,26 @@ func (st DBstore) DeleteProvenance(ctx context.Context, o models.Provisionable,
 		return err
 	})
 }
+
+// GetProvenanceCounts counts the provenance records of all organizations by resource type and provenance.
+func (st DBstore) GetProvenanceCounts(ctx context.Context) (map[string]map[models.Provenance]int64, error) {
+	result := make(map[string]map[models.Provenance]int64)
+	err := st.SQLStore.WithDbSession(ctx, func(sess *db.Session) error {
+		var rows []struct {
+			RecordType string
+			Provenance models.Provenance
+			Count      int64
+		}
+		if err := sess.SQL("SELECT record_type, provenance, COUNT(*) AS count FROM provenance_type GROUP BY record_type, provenance").Find(&rows); err != nil {
+			return fmt.Errorf("failed to count provenance records: %w", err)
+		}
+		for _, row := range rows {
+			if _, ok := result[row.RecordType]; !ok {
+				result[row.RecordType] = make(map[models.Provenance]int64)
+			}
+			result[row.RecordType][row.Provenance] += row.Count
+		}
+		return nil
+	})
+	return result, err
+}
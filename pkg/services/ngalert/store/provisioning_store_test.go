@@ -132,3 +132,25 @@ func TestIntegrationProvisioningStore(t *testing.T) {
 func createProvisioningStoreSut(_ *ngalert.AlertNG, db *store.DBstore) provisioning.ProvisioningStore {
 	return db
 }
+
+func TestIntegrationProvenanceCounts(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test")
+	}
+	_, dbstore := tests.SetupTestEnv(t, testAlertingIntervalSeconds)
+	ctx := context.Background()
+
+	require.NoError(t, dbstore.SetProvenance(ctx, &models.AlertRule{UID: "a"}, 1, models.ProvenanceFile))
+	require.NoError(t, dbstore.SetProvenance(ctx, &models.AlertRule{UID: "b"}, 1, models.ProvenanceAPI))
+	require.NoError(t, dbstore.SetProvenance(ctx, &models.AlertRule{UID: "a"}, 2, models.ProvenanceFile))
+	require.NoError(t, dbstore.SetProvenance(ctx, &models.AlertRule{UID: "c"}, 2, models.ProvenanceFile))
+
+	counts, err := dbstore.GetProvenanceCounts(ctx)
+	require.NoError(t, err)
+	require.Equal(t, map[string]map[models.Provenance]int64{
+		(&models.AlertRule{}).ResourceType(): {
+			models.ProvenanceFile: 3,
+			models.ProvenanceAPI:  1,
+		},
+	}, counts)
+}
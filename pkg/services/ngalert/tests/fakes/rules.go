@@ -222,6 +222,9 @@ func (f *RuleStore) ListAlertRules(_ context.Context, q *models.ListAlertRulesQu
 		if q.RuleGroup != "" && r.RuleGroup != q.RuleGroup {
 			continue
 		}
+		if q.ReceiverName != "" && !r.UsesReceiver(q.ReceiverName) {
+			continue
+		}
 		ruleList = append(ruleList, r)
 	}
 
@@ -49,6 +49,8 @@ func ProvideService(bus bus.Bus, cfg *setting.Cfg, mailer Mailer, store TempUser
 		store:        store,
 	}
 
+	netClient = NewWebhookClient(cfg.UnifiedAlerting.NotificationBlockedIPRanges)
+
 	ns.Bus.AddEventListener(ns.signUpStartedHandler)
 	ns.Bus.AddEventListener(ns.signUpCompletedHandler)
 
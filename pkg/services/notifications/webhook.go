@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"syscall"
 	"time"
 
 	"github.com/grafana/grafana/pkg/util"
@@ -32,19 +34,53 @@ type WebhookClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
-var netTransport = &http.Transport{
-	TLSClientConfig: &tls.Config{
-		Renegotiation: tls.RenegotiateFreelyAsClient,
-	},
-	Proxy: http.ProxyFromEnvironment,
-	Dial: (&net.Dialer{
+// ErrBlockedAddress is returned for requests of webhooks to addresses in the blocked IP ranges.
+var ErrBlockedAddress = errors.New("address is blocked")
+
+var netClient WebhookClient = NewWebhookClient(nil)
+
+// NewWebhookClient returns the HTTP client that webhooks are sent with. It refuses to connect to addresses in any of
+// the blocked IP ranges. The addresses are checked after the host name was resolved, for every connection including
+// those of redirects, so that host names that resolve to blocked addresses cannot be used to reach them. Connections
+// through a proxy are checked against the address of the proxy.
+func NewWebhookClient(blocked []*net.IPNet) *http.Client {
+	dialer := &net.Dialer{
 		Timeout: 30 * time.Second,
-	}).Dial,
-	TLSHandshakeTimeout: 5 * time.Second,
+		Control: blockedAddressControl(blocked),
+	}
+	return &http.Client{
+		Timeout: time.Second * 30,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				Renegotiation: tls.RenegotiateFreelyAsClient,
+			},
+			Proxy:               http.ProxyFromEnvironment,
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+		},
+	}
 }
-var netClient WebhookClient = &http.Client{
-	Timeout:   time.Second * 30,
-	Transport: netTransport,
+
+func blockedAddressControl(blocked []*net.IPNet) func(network, address string, c syscall.RawConn) error {
+	if len(blocked) == 0 {
+		return nil
+	}
+	return func(network, address string, c syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		ip := net.ParseIP(host)
+		if ip == nil {
+			return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+		}
+		for _, r := range blocked {
+			if r.Contains(ip) {
+				return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+			}
+		}
+		return nil
+	}
 }
 
 func (ns *NotificationService) sendWebRequestSync(ctx context.Context, webhook *Webhook) error {
@@ -0,0 +1,40 @@
+package notifications
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWebhookClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Run("requests to blocked addresses are refused", func(t *testing.T) {
+		_, loopback, err := net.ParseCIDR("127.0.0.0/8")
+		require.NoError(t, err)
+
+		resp, err := NewWebhookClient([]*net.IPNet{loopback}).Get(server.URL)
+		if resp != nil {
+			_ = resp.Body.Close()
+		}
+		require.ErrorIs(t, err, ErrBlockedAddress)
+	})
+
+	t.Run("requests to other addresses are sent", func(t *testing.T) {
+		_, linkLocal, err := net.ParseCIDR("169.254.0.0/16")
+		require.NoError(t, err)
+
+		for _, blocked := range [][]*net.IPNet{nil, {linkLocal}} {
+			resp, err := NewWebhookClient(blocked).Get(server.URL)
+			require.NoError(t, err)
+			require.NoError(t, resp.Body.Close())
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+		}
+	})
+}
@@ -224,6 +224,7 @@ func (ss *sqlStore) Delete(ctx context.Context, cmd *org.DeleteOrgCommand) error
 			"DELETE FROM temp_user WHERE org_id = ?",
 			"DELETE FROM ngalert_configuration WHERE org_id = ?",
 			"DELETE FROM alert_configuration WHERE org_id = ?",
+			"DELETE FROM alert_configuration_resource WHERE org_id = ?",
 			"DELETE FROM alert_instance WHERE rule_org_id = ?",
 			"DELETE FROM alert_notification WHERE org_id = ?",
 			"DELETE FROM alert_notification_state WHERE org_id = ?",
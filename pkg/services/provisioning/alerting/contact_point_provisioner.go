@@ -5,7 +5,6 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
-	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
@@ -31,6 +30,7 @@ func (c *defaultContactPointProvisioner) Provision(ctx context.Context,
 	files []*AlertingFile) error {
 	cpsCache := map[int64][]definitions.EmbeddedContactPoint{}
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, contactPointsConfig := range file.ContactPoints {
 			// check if we already fetched the contact points for this org.
 			// if not we fetch them and populate the cache.
@@ -48,7 +48,7 @@ func (c *defaultContactPointProvisioner) Provision(ctx context.Context,
 				for _, fetchedCP := range cpsCache[contactPointsConfig.OrgID] {
 					if fetchedCP.UID == contactPoint.UID {
 						err := c.contactPointService.UpdateContactPoint(ctx,
-							contactPointsConfig.OrgID, contactPoint, models.ProvenanceFile)
+							contactPointsConfig.OrgID, contactPoint, file.provenance(), provisioning.UpdateContactPointOptions{})
 						if err != nil {
 							return err
 						}
@@ -56,7 +56,7 @@ func (c *defaultContactPointProvisioner) Provision(ctx context.Context,
 					}
 				}
 				_, err := c.contactPointService.CreateContactPoint(ctx, contactPointsConfig.OrgID,
-					contactPoint, models.ProvenanceFile)
+					contactPoint, file.provenance())
 				if err != nil {
 					return err
 				}
@@ -69,8 +69,9 @@ func (c *defaultContactPointProvisioner) Provision(ctx context.Context,
 func (c *defaultContactPointProvisioner) Unprovision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, cp := range file.DeleteContactPoints {
-			err := c.contactPointService.DeleteContactPoint(ctx, cp.OrgID, cp.UID)
+			err := c.contactPointService.DeleteContactPoint(ctx, cp.OrgID, cp.UID, provisioning.DeleteContactPointOptions{})
 			if err != nil {
 				return err
 			}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
-	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
@@ -31,6 +30,7 @@ func (c *defaultMuteTimesProvisioner) Provision(ctx context.Context,
 	files []*AlertingFile) error {
 	cache := map[int64]map[string]definitions.MuteTimeInterval{}
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, muteTiming := range file.MuteTimes {
 			if _, exists := cache[muteTiming.OrgID]; !exists {
 				intervals, err := c.muteTimingService.GetMuteTimings(ctx, muteTiming.OrgID)
@@ -42,7 +42,7 @@ func (c *defaultMuteTimesProvisioner) Provision(ctx context.Context,
 					cache[muteTiming.OrgID][interval.Name] = interval
 				}
 			}
-			muteTiming.MuteTime.Provenance = definitions.Provenance(models.ProvenanceFile)
+			muteTiming.MuteTime.Provenance = definitions.Provenance(file.provenance())
 			if _, exists := cache[muteTiming.OrgID][muteTiming.MuteTime.Name]; exists {
 				_, err := c.muteTimingService.UpdateMuteTiming(ctx, muteTiming.MuteTime, muteTiming.OrgID)
 				if err != nil {
@@ -62,8 +62,9 @@ func (c *defaultMuteTimesProvisioner) Provision(ctx context.Context,
 func (c *defaultMuteTimesProvisioner) Unprovision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, deleteMuteTime := range file.DeleteMuteTimes {
-			err := c.muteTimingService.DeleteMuteTiming(ctx, deleteMuteTime.Name, deleteMuteTime.OrgID)
+			err := c.muteTimingService.DeleteMuteTiming(ctx, deleteMuteTime.Name, deleteMuteTime.OrgID, provisioning.DeleteMuteTimingOptions{})
 			if err != nil {
 				return err
 			}
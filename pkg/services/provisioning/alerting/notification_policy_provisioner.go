@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/log"
-	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
@@ -30,9 +29,10 @@ func NewNotificationPolicyProvisoner(logger log.Logger,
 func (c *defaultNotificationPolicyProvisioner) Provision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, np := range file.Policies {
-			err := c.notificationPolicyService.UpdatePolicyTree(ctx, np.OrgID,
-				np.Policy, models.ProvenanceFile)
+			_, err := c.notificationPolicyService.UpdatePolicyTree(ctx, np.OrgID,
+				np.Policy, file.provenance())
 			if err != nil {
 				return fmt.Errorf("%s: %w", file.Filename, err)
 			}
@@ -44,6 +44,7 @@ func (c *defaultNotificationPolicyProvisioner) Provision(ctx context.Context,
 func (c *defaultNotificationPolicyProvisioner) Unprovision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, orgID := range file.ResetPolicies {
 			_, err := c.notificationPolicyService.ResetPolicyTree(ctx, int64(orgID))
 			if err != nil {
@@ -3,10 +3,12 @@ package alerting
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/dashboards"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+	"github.com/grafana/grafana/pkg/services/ngalert/store"
 )
 
 type ProvisionerConfig struct {
@@ -18,62 +20,168 @@ type ProvisionerConfig struct {
 	NotificiationPolicyService provisioning.NotificationPolicyService
 	MuteTimingService          provisioning.MuteTimingService
 	TemplateService            provisioning.TemplateService
+	SilenceService             provisioning.SilenceService
+	// Status records the outcome of the provisioning per organization. Optional.
+	Status *provisioning.FileProvisioningStatusStore
+	// ProvenanceService and Orgs find the resources to delete when a file enables pruning. Required for pruning.
+	ProvenanceService *provisioning.ProvenanceService
+	Orgs              store.OrgStore
 }
 
 func Provision(ctx context.Context, cfg ProvisionerConfig) error {
+	_, err := ProvisionWithSummary(ctx, cfg)
+	return err
+}
+
+// ProvisionWithSummary provisions the alerting resources of the files in the configured path, like Provision, and
+// returns how many resources of each kind were provisioned and which kind failed.
+func ProvisionWithSummary(ctx context.Context, cfg ProvisionerConfig) (Summary, error) {
 	logger := log.New("provisioning.alerting")
 	cfgReader := newRulesConfigReader(logger)
 	files, err := cfgReader.readConfig(ctx, cfg.Path)
 	if err != nil {
-		return err
+		recordStatus(ctx, logger, cfg.Status, nil, err, nil)
+		return Summary{}, err
 	}
+	read := len(files)
+	if pruneEnabled(files) {
+		deletions, err := pruneDeletions(ctx, cfg, files)
+		if err != nil {
+			err = fmt.Errorf("failed to find the resources to prune: %w", err)
+			recordStatus(ctx, logger, cfg.Status, files, nil, err)
+			return newSummary(files), err
+		}
+		files = append(files, deletions)
+	}
+	summary, err := provision(ctx, logger, cfg, files)
+	summary.Files = read
+	recordStatus(ctx, logger, cfg.Status, files, nil, err)
+	return summary, err
+}
+
+// provisioningStep provisions or deletes one kind of resource of the files.
+type provisioningStep struct {
+	kind string
+	// name describes the resources in errors.
+	name string
+	run  func(ctx context.Context, files []*AlertingFile) error
+}
+
+func provision(ctx context.Context, logger log.Logger, cfg ProvisionerConfig, files []*AlertingFile) (Summary, error) {
 	logger.Info("starting to provision alerting")
 	logger.Debug("read all alerting files", "file_count", len(files))
+	cpProvisioner := NewContactPointProvisoner(logger, cfg.ContactPointService)
+	mtProvisioner := NewMuteTimesProvisioner(logger, cfg.MuteTimingService)
+	ttProvsioner := NewTextTemplateProvisioner(logger, cfg.TemplateService)
+	silenceProvisioner := NewSilencesProvisioner(logger, cfg.SilenceService)
 	ruleProvisioner := NewAlertRuleProvisioner(
 		logger,
 		cfg.DashboardService,
 		cfg.DashboardProvService,
 		cfg.RuleService)
-	err = ruleProvisioner.Provision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("alert rules: %w", err)
+	npProvisioner := NewNotificationPolicyProvisoner(logger, cfg.NotificiationPolicyService)
+	steps := []provisioningStep{
+		{kind: SummaryKindContactPoints, name: "contact points", run: cpProvisioner.Provision},
+		{kind: SummaryKindMuteTimings, name: "mute times", run: mtProvisioner.Provision},
+		{kind: SummaryKindTemplates, name: "text templates", run: ttProvsioner.Provision},
+		// Rules are provisioned after the contact points and mute timings, which their notification settings can refer to.
+		{kind: SummaryKindAlertRules, name: "alert rules", run: ruleProvisioner.Provision},
+		{kind: SummaryKindNotificationPolicies, name: "notification policies", run: npProvisioner.Provision},
+		{kind: SummaryKindSilences, name: "silences", run: silenceProvisioner.Provision},
+		{kind: SummaryKindSilences, name: "silences", run: silenceProvisioner.Unprovision},
+		{kind: SummaryKindNotificationPolicies, name: "notification policies", run: npProvisioner.Unprovision},
+		{kind: SummaryKindContactPoints, name: "contact points", run: cpProvisioner.Unprovision},
+		{kind: SummaryKindMuteTimings, name: "mute times", run: mtProvisioner.Unprovision},
+		{kind: SummaryKindTemplates, name: "text templates", run: ttProvsioner.Unprovision},
 	}
-	cpProvisioner := NewContactPointProvisoner(logger, cfg.ContactPointService)
-	err = cpProvisioner.Provision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("contact points: %w", err)
+	summary := newSummary(files)
+	// A kind of resource is applied once its last step succeeded.
+	lastStep := make(map[string]int, len(steps))
+	for i, step := range steps {
+		lastStep[step.kind] = i
 	}
-	mtProvisioner := NewMuteTimesProvisioner(logger, cfg.MuteTimingService)
-	err = mtProvisioner.Provision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("mute times: %w", err)
+	for i, step := range steps {
+		if err := step.run(ctx, files); err != nil {
+			summary.setStatus(step.kind, SummaryStatusFailed, err)
+			return summary, fmt.Errorf("%s: %w", step.name, err)
+		}
+		if lastStep[step.kind] == i {
+			summary.setStatus(step.kind, SummaryStatusApplied, nil)
+		}
 	}
-	ttProvsioner := NewTextTemplateProvisioner(logger, cfg.TemplateService)
-	err = ttProvsioner.Provision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("text templates: %w", err)
+	logger.Info("finished to provision alerting")
+	return summary, nil
+}
+
+// recordStatus records the outcome of the provisioning for the organizations referenced by the files. If the files
+// could not be read, the error is recorded for the organizations that were provisioned from files before.
+func recordStatus(ctx context.Context, logger log.Logger, status *provisioning.FileProvisioningStatusStore, files []*AlertingFile, readErr, provisioningErr error) {
+	if status == nil {
+		return
 	}
-	npProvisioner := NewNotificationPolicyProvisoner(logger, cfg.NotificiationPolicyService)
-	err = npProvisioner.Provision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("notification policies: %w", err)
+	orgIDs := referencedOrgs(files)
+	if readErr != nil {
+		var err error
+		orgIDs, err = status.ProvisionedOrgs(ctx)
+		if err != nil {
+			logger.Error("Failed to get organizations provisioned from files", "error", err)
+			return
+		}
+		provisioningErr = readErr
 	}
-	err = npProvisioner.Unprovision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("notification policies: %w", err)
+	if err := status.RecordResult(ctx, orgIDs, provisioningErr); err != nil {
+		logger.Error("Failed to record the status of the provisioning", "error", err)
 	}
-	err = cpProvisioner.Unprovision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("contact points: %w", err)
+}
+
+// referencedOrgs returns the organizations whose resources are provisioned or deleted by the files.
+func referencedOrgs(files []*AlertingFile) []int64 {
+	seen := map[int64]struct{}{}
+	add := func(orgID int64) {
+		seen[orgID] = struct{}{}
 	}
-	err = mtProvisioner.Unprovision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("mute times: %w", err)
+	for _, file := range files {
+		for _, g := range file.Groups {
+			add(g.OrgID)
+		}
+		for _, r := range file.DeleteRules {
+			add(r.OrgID)
+		}
+		for _, cp := range file.ContactPoints {
+			add(cp.OrgID)
+		}
+		for _, cp := range file.DeleteContactPoints {
+			add(cp.OrgID)
+		}
+		for _, p := range file.Policies {
+			add(p.OrgID)
+		}
+		for _, orgID := range file.ResetPolicies {
+			add(int64(orgID))
+		}
+		for _, mt := range file.MuteTimes {
+			add(mt.OrgID)
+		}
+		for _, mt := range file.DeleteMuteTimes {
+			add(mt.OrgID)
+		}
+		for _, t := range file.Templates {
+			add(t.OrgID)
+		}
+		for _, t := range file.DeleteTemplates {
+			add(t.OrgID)
+		}
+		for _, s := range file.Silences {
+			add(s.OrgID)
+		}
+		for _, s := range file.DeleteSilences {
+			add(s.OrgID)
+		}
 	}
-	err = ttProvsioner.Unprovision(ctx, files)
-	if err != nil {
-		return fmt.Errorf("text templates: %w", err)
+	orgIDs := make([]int64, 0, len(seen))
+	for orgID := range seen {
+		orgIDs = append(orgIDs, orgID)
 	}
-	logger.Info("finished to provision alerting")
-	return nil
+	sort.Slice(orgIDs, func(i, j int) bool { return orgIDs[i] < orgIDs[j] })
+	return orgIDs
 }
@@ -0,0 +1,132 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+// pruneEnabled reports whether any of the files asks for the resources removed from the files to be deleted.
+func pruneEnabled(files []*AlertingFile) bool {
+	for _, file := range files {
+		if file.Prune {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneDeletions returns a file that deletes the resources of all organizations that were provisioned from files
+// before but are neither provisioned nor deleted by the files anymore. The notification policy tree of an
+// organization is reset if none of the files provisions it.
+func pruneDeletions(ctx context.Context, cfg ProvisionerConfig, files []*AlertingFile) (*AlertingFile, error) {
+	if cfg.ProvenanceService == nil || cfg.Orgs == nil {
+		return nil, fmt.Errorf("pruning is not supported by this provisioner")
+	}
+
+	declared := declaredResources(files)
+	deletions := &AlertingFile{
+		Filename:   "prune",
+		Provenance: models.ProvenanceFile,
+	}
+	orgIDs, err := cfg.Orgs.GetOrgs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, orgID := range orgIDs {
+		provenances, err := cfg.ProvenanceService.GetProvenances(ctx, orgID, "")
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range provenances {
+			if models.Provenance(p.Provenance) != models.ProvenanceFile {
+				continue
+			}
+			if _, ok := declared[declaredResource{orgID: orgID, resourceType: p.ResourceType, id: p.ResourceID}]; ok {
+				continue
+			}
+			switch p.ResourceType {
+			case (&definitions.EmbeddedContactPoint{}).ResourceType():
+				deletions.DeleteContactPoints = append(deletions.DeleteContactPoints, DeleteContactPoint{OrgID: orgID, UID: p.ResourceID})
+			case (&definitions.NotificationTemplate{}).ResourceType():
+				deletions.DeleteTemplates = append(deletions.DeleteTemplates, DeleteTemplate{OrgID: orgID, Name: p.ResourceID})
+			case (&definitions.MuteTimeInterval{}).ResourceType():
+				deletions.DeleteMuteTimes = append(deletions.DeleteMuteTimes, DeleteMuteTime{OrgID: orgID, Name: p.ResourceID})
+			case (&definitions.ProvisionedSilence{}).ResourceType():
+				deletions.DeleteSilences = append(deletions.DeleteSilences, DeleteSilence{OrgID: orgID, UID: p.ResourceID})
+			case (&definitions.Route{}).ResourceType():
+				deletions.ResetPolicies = append(deletions.ResetPolicies, OrgID(orgID))
+			case (&models.AlertRule{}).ResourceType():
+				deletions.DeleteRules = append(deletions.DeleteRules, RuleDelete{UID: p.ResourceID, OrgID: orgID})
+			}
+		}
+	}
+	return deletions, nil
+}
+
+// declaredResource identifies a resource of an organization in the files.
+type declaredResource struct {
+	orgID        int64
+	resourceType string
+	id           string
+}
+
+// declaredResources returns the resources that the files provision or delete.
+func declaredResources(files []*AlertingFile) map[declaredResource]struct{} {
+	declared := map[declaredResource]struct{}{}
+	add := func(orgID int64, resourceType, id string) {
+		declared[declaredResource{orgID: orgID, resourceType: resourceType, id: id}] = struct{}{}
+	}
+	contactPointType := (&definitions.EmbeddedContactPoint{}).ResourceType()
+	templateType := (&definitions.NotificationTemplate{}).ResourceType()
+	muteTimingType := (&definitions.MuteTimeInterval{}).ResourceType()
+	silenceType := (&definitions.ProvisionedSilence{}).ResourceType()
+	policyType := (&definitions.Route{}).ResourceType()
+	ruleType := (&models.AlertRule{}).ResourceType()
+	for _, file := range files {
+		for _, cps := range file.ContactPoints {
+			for _, cp := range cps.ContactPoints {
+				add(cps.OrgID, contactPointType, cp.UID)
+			}
+		}
+		for _, cp := range file.DeleteContactPoints {
+			add(cp.OrgID, contactPointType, cp.UID)
+		}
+		for _, t := range file.Templates {
+			add(t.OrgID, templateType, t.Data.Name)
+		}
+		for _, t := range file.DeleteTemplates {
+			add(t.OrgID, templateType, t.Name)
+		}
+		for _, mt := range file.MuteTimes {
+			add(mt.OrgID, muteTimingType, mt.MuteTime.Name)
+		}
+		for _, mt := range file.DeleteMuteTimes {
+			add(mt.OrgID, muteTimingType, mt.Name)
+		}
+		for _, s := range file.Silences {
+			add(s.OrgID, silenceType, s.Silence.UID)
+		}
+		for _, s := range file.DeleteSilences {
+			add(s.OrgID, silenceType, s.UID)
+		}
+		// The notification policy tree is a single resource without an identifier.
+		for _, p := range file.Policies {
+			add(p.OrgID, policyType, "")
+		}
+		for _, orgID := range file.ResetPolicies {
+			add(int64(orgID), policyType, "")
+		}
+		for _, group := range file.Groups {
+			for _, rule := range group.Rules {
+				add(group.OrgID, ruleType, rule.UID)
+			}
+		}
+		for _, rule := range file.DeleteRules {
+			add(rule.OrgID, ruleType, rule.UID)
+		}
+	}
+	return declared
+}
@@ -0,0 +1,62 @@
+package alerting
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/alertmanager/config"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPrune(t *testing.T) {
+	t.Run("the prune option is read from the file", func(t *testing.T) {
+		var fileV1 AlertingFileV1
+		require.NoError(t, yaml.Unmarshal([]byte("apiVersion: 1\nprune: true\n"), &fileV1))
+		file, err := fileV1.MapToModel()
+		require.NoError(t, err)
+		require.True(t, file.Prune)
+		require.True(t, pruneEnabled([]*AlertingFile{{}, &file}))
+		require.False(t, pruneEnabled([]*AlertingFile{{}}))
+	})
+
+	t.Run("provisioned and deleted resources are declared per organization", func(t *testing.T) {
+		files := []*AlertingFile{
+			{
+				ContactPoints: []ContactPoint{{OrgID: 1, ContactPoints: []definitions.EmbeddedContactPoint{{UID: "cp"}}}},
+				Groups: []models.AlertRuleGroupWithFolderTitle{{
+					AlertRuleGroup: &models.AlertRuleGroup{Rules: []models.AlertRule{{UID: "rule"}}},
+					OrgID:          2,
+				}},
+				ResetPolicies: []OrgID{1},
+			},
+			{
+				DeleteTemplates: []DeleteTemplate{{OrgID: 1, Name: "template"}},
+				MuteTimes: []MuteTime{{
+					OrgID:    1,
+					MuteTime: definitions.MuteTimeInterval{MuteTimeInterval: config.MuteTimeInterval{Name: "weekends"}},
+				}},
+				Silences: []Silence{{OrgID: 2, Silence: definitions.ProvisionedSilence{UID: "maintenance"}}},
+			},
+		}
+
+		declared := declaredResources(files)
+
+		require.Equal(t, map[declaredResource]struct{}{
+			{orgID: 1, resourceType: "contactPoint", id: "cp"}:           {},
+			{orgID: 2, resourceType: "alertRule", id: "rule"}:            {},
+			{orgID: 1, resourceType: "route", id: ""}:                    {},
+			{orgID: 1, resourceType: "template", id: "template"}:         {},
+			{orgID: 1, resourceType: "muteTimeInterval", id: "weekends"}: {},
+			{orgID: 2, resourceType: "silence", id: "maintenance"}:       {},
+		}, declared)
+	})
+
+	t.Run("pruning fails without the provenance service", func(t *testing.T) {
+		_, err := pruneDeletions(context.Background(), ProvisionerConfig{}, nil)
+		require.Error(t, err)
+	})
+}
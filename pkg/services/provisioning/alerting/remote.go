@@ -0,0 +1,145 @@
+package alerting
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+// remoteExports are the endpoints of the provisioning API of the remote instance that the synchronized configuration
+// is pulled from. They return the configuration in the provisioning file format.
+var remoteExports = []string{
+	"/api/v1/provisioning/alert-rules/export",
+	"/api/v1/provisioning/contact-points/export?decrypt=true",
+	"/api/v1/provisioning/policies/export",
+}
+
+// RemoteConfig configures the Grafana instance the alerting configuration is synchronized from.
+type RemoteConfig struct {
+	// URL of the remote instance.
+	URL string
+	// Token authenticates the requests to the remote instance.
+	Token string
+	// OrgID is the local organization the configuration is applied to.
+	OrgID int64
+	// Client is used to call the remote instance. Defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// SyncFromRemote pulls the alert rules, contact points and notification policies of another Grafana instance from its
+// provisioning API and provisions them in the local organization with the remote provenance. Resources that were
+// pulled before but no longer exist on the remote instance are deleted.
+func SyncFromRemote(ctx context.Context, cfg ProvisionerConfig, remote RemoteConfig) error {
+	logger := log.New("provisioning.alerting.remote")
+	files := make([]*AlertingFile, 0, len(remoteExports)+1)
+	for _, path := range remoteExports {
+		file, err := pullRemoteFile(ctx, remote, path)
+		if err != nil {
+			return fmt.Errorf("failed to pull %s from %s: %w", path, remote.URL, err)
+		}
+		files = append(files, file)
+	}
+	deletions, err := remoteDeletions(ctx, cfg, remote, files)
+	if err != nil {
+		return err
+	}
+	files = append(files, deletions)
+	_, err = provision(ctx, logger, cfg, files)
+	return err
+}
+
+// pullRemoteFile fetches an export of the remote instance and maps it to the resources of the local organization.
+func pullRemoteFile(ctx context.Context, remote RemoteConfig, path string) (*AlertingFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, remote.URL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/yaml")
+	if remote.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+remote.Token)
+	}
+	client := remote.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	fileV1 := &AlertingFileV1{}
+	if err := yaml.Unmarshal(body, fileV1); err != nil {
+		return nil, err
+	}
+	fileV1.Filename = path
+	file, err := fileV1.MapToModel()
+	if err != nil {
+		return nil, err
+	}
+	file.Path = remote.URL + path
+	file.Provenance = models.ProvenanceRemote
+	file.moveToOrg(remote.OrgID)
+	return &file, nil
+}
+
+// remoteDeletions returns a file that deletes the alert rules and contact points of the local organization that were
+// pulled from the remote instance before but are not part of the pulled files anymore.
+func remoteDeletions(ctx context.Context, cfg ProvisionerConfig, remote RemoteConfig, files []*AlertingFile) (*AlertingFile, error) {
+	pulledRules := map[string]struct{}{}
+	pulledContactPoints := map[string]struct{}{}
+	for _, file := range files {
+		for _, group := range file.Groups {
+			for _, rule := range group.Rules {
+				pulledRules[rule.UID] = struct{}{}
+			}
+		}
+		for _, cps := range file.ContactPoints {
+			for _, cp := range cps.ContactPoints {
+				pulledContactPoints[cp.UID] = struct{}{}
+			}
+		}
+	}
+
+	deletions := &AlertingFile{
+		Filename:   "remote",
+		Path:       remote.URL,
+		Provenance: models.ProvenanceRemote,
+	}
+	rules, provenances, err := cfg.RuleService.GetAlertRules(ctx, provisioning.AlertRuleQuery{OrgID: remote.OrgID})
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if _, ok := pulledRules[rule.UID]; ok || provenances[rule.UID] != models.ProvenanceRemote {
+			continue
+		}
+		deletions.DeleteRules = append(deletions.DeleteRules, RuleDelete{UID: rule.UID, OrgID: remote.OrgID})
+	}
+	cps, err := cfg.ContactPointService.GetContactPoints(ctx, provisioning.ContactPointQuery{OrgID: remote.OrgID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, cp := range cps {
+		if _, ok := pulledContactPoints[cp.UID]; ok || cp.Provenance != string(models.ProvenanceRemote) {
+			continue
+		}
+		deletions.DeleteContactPoints = append(deletions.DeleteContactPoints, DeleteContactPoint{OrgID: remote.OrgID, UID: cp.UID})
+	}
+	return deletions, nil
+}
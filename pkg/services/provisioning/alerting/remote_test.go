@@ -0,0 +1,67 @@
+package alerting
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestPullRemoteFile(t *testing.T) {
+	contactPoints := `apiVersion: 1
+contactPoints:
+  - name: cp_1
+    orgId: 1337
+    receivers:
+    - uid: first_uid
+      type: prometheus-alertmanager
+      settings:
+        url: http://test:9000
+`
+	policies := `apiVersion: 1
+policies:
+  - orgId: 1337
+    receiver: cp_1
+`
+
+	var authorization string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		switch r.URL.Path {
+		case "/api/v1/provisioning/contact-points/export":
+			require.Equal(t, "true", r.URL.Query().Get("decrypt"))
+			_, _ = w.Write([]byte(contactPoints))
+		case "/api/v1/provisioning/policies/export":
+			_, _ = w.Write([]byte(policies))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	remote := RemoteConfig{URL: srv.URL, Token: "token", OrgID: 2}
+
+	t.Run("resources are moved to the local organization with the remote provenance", func(t *testing.T) {
+		file, err := pullRemoteFile(context.Background(), remote, "/api/v1/provisioning/contact-points/export?decrypt=true")
+		require.NoError(t, err)
+		require.Equal(t, "Bearer token", authorization)
+		require.Equal(t, models.ProvenanceRemote, file.provenance())
+		require.Len(t, file.ContactPoints, 1)
+		require.Equal(t, int64(2), file.ContactPoints[0].OrgID)
+		require.Equal(t, "first_uid", file.ContactPoints[0].ContactPoints[0].UID)
+
+		file, err = pullRemoteFile(context.Background(), remote, "/api/v1/provisioning/policies/export")
+		require.NoError(t, err)
+		require.Len(t, file.Policies, 1)
+		require.Equal(t, int64(2), file.Policies[0].OrgID)
+		require.Equal(t, "cp_1", file.Policies[0].Policy.Receiver)
+	})
+
+	t.Run("unexpected responses of the remote instance fail the pull", func(t *testing.T) {
+		_, err := pullRemoteFile(context.Background(), remote, "/api/v1/provisioning/alert-rules/export")
+		require.ErrorContains(t, err, "404")
+	})
+}
@@ -39,6 +39,7 @@ type defaultAlertRuleProvisioner struct {
 func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, group := range file.Groups {
 			folderUID, err := prov.getOrCreateFolderUID(ctx, group.FolderTitle, group.OrgID)
 			if err != nil {
@@ -52,7 +53,7 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 			for _, rule := range group.Rules {
 				rule.NamespaceUID = folderUID
 				rule.RuleGroup = group.Title
-				err = prov.provisionRule(ctx, group.OrgID, rule)
+				err = prov.provisionRule(ctx, group.OrgID, rule, file.provenance())
 				if err != nil {
 					return err
 				}
@@ -64,7 +65,7 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 		}
 		for _, deleteRule := range file.DeleteRules {
 			err := prov.ruleService.DeleteAlertRule(ctx, deleteRule.OrgID,
-				deleteRule.UID, alert_models.ProvenanceFile)
+				deleteRule.UID, file.provenance())
 			if err != nil {
 				return err
 			}
@@ -76,7 +77,8 @@ func (prov *defaultAlertRuleProvisioner) Provision(ctx context.Context,
 func (prov *defaultAlertRuleProvisioner) provisionRule(
 	ctx context.Context,
 	orgID int64,
-	rule alert_models.AlertRule) error {
+	rule alert_models.AlertRule,
+	provenance alert_models.Provenance) error {
 	prov.logger.Debug("provisioning alert rule", "uid", rule.UID, "org", rule.OrgID)
 	_, _, err := prov.ruleService.GetAlertRule(ctx, orgID, rule.UID)
 	if err != nil && !errors.Is(err, alert_models.ErrAlertRuleNotFound) {
@@ -85,10 +87,10 @@ func (prov *defaultAlertRuleProvisioner) provisionRule(
 		prov.logger.Debug("creating rule", "uid", rule.UID, "org", rule.OrgID)
 		// 0 is passed as userID as then the quota logic will only check for
 		// the organization quota, as we don't have any user scope here.
-		_, err = prov.ruleService.CreateAlertRule(ctx, rule, alert_models.ProvenanceFile, 0)
+		_, err = prov.ruleService.CreateAlertRule(ctx, rule, provenance, 0)
 	} else {
 		prov.logger.Debug("updating rule", "uid", rule.UID, "org", rule.OrgID)
-		_, err = prov.ruleService.UpdateAlertRule(ctx, rule, alert_models.ProvenanceFile)
+		_, err = prov.ruleService.UpdateAlertRule(ctx, rule, provenance)
 	}
 	return err
 }
@@ -73,6 +73,26 @@ type AlertRuleV1 struct {
 	Annotations  values.StringMapValue `json:"annotations" yaml:"annotations"`
 	Labels       values.StringMapValue `json:"labels" yaml:"labels"`
 	IsPaused     values.BoolValue      `json:"isPaused" yaml:"isPaused"`
+	// NotificationSettings send the notifications of the rule directly to a contact point. Optional.
+	NotificationSettings *NotificationSettingsV1 `json:"notificationSettings" yaml:"notificationSettings"`
+}
+
+type NotificationSettingsV1 struct {
+	Receiver          values.StringValue   `json:"receiver" yaml:"receiver"`
+	MuteTimeIntervals []values.StringValue `json:"muteTimeIntervals" yaml:"muteTimeIntervals"`
+}
+
+func (settingsV1 *NotificationSettingsV1) mapToModel() (models.NotificationSettings, error) {
+	settings := models.NotificationSettings{
+		Receiver: strings.TrimSpace(settingsV1.Receiver.Value()),
+	}
+	for _, name := range settingsV1.MuteTimeIntervals {
+		settings.MuteTimeIntervals = append(settings.MuteTimeIntervals, strings.TrimSpace(name.Value()))
+	}
+	if err := settings.Validate(); err != nil {
+		return models.NotificationSettings{}, err
+	}
+	return settings, nil
 }
 
 func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
@@ -130,6 +150,13 @@ func (rule *AlertRuleV1) mapToModel(orgID int64) (models.AlertRule, error) {
 		return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: no data set", alertRule.Title)
 	}
 	alertRule.IsPaused = rule.IsPaused.Value()
+	if rule.NotificationSettings != nil {
+		settings, err := rule.NotificationSettings.mapToModel()
+		if err != nil {
+			return models.AlertRule{}, fmt.Errorf("rule '%s' failed to parse: %w", alertRule.Title, err)
+		}
+		alertRule.NotificationSettings = []models.NotificationSettings{settings}
+	}
 	return alertRule, nil
 }
 
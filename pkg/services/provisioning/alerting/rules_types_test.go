@@ -187,6 +187,31 @@ func TestRules(t *testing.T) {
 		require.NoError(t, err)
 		require.Equal(t, ruleMapped.NoDataState, models.NoData)
 	})
+	t.Run("a rule with out notification settings should have none", func(t *testing.T) {
+		rule := validRuleV1(t)
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Empty(t, ruleMapped.NotificationSettings)
+	})
+	t.Run("a rule with notification settings should map them correctly", func(t *testing.T) {
+		rule := validRuleV1(t)
+		settings := NotificationSettingsV1{}
+		err := yaml.Unmarshal([]byte("receiver: email\nmuteTimeIntervals: [weekends]"), &settings)
+		require.NoError(t, err)
+		rule.NotificationSettings = &settings
+		ruleMapped, err := rule.mapToModel(1)
+		require.NoError(t, err)
+		require.Equal(t, []models.NotificationSettings{{Receiver: "email", MuteTimeIntervals: []string{"weekends"}}}, ruleMapped.NotificationSettings)
+	})
+	t.Run("a rule with notification settings with out a receiver should error", func(t *testing.T) {
+		rule := validRuleV1(t)
+		settings := NotificationSettingsV1{}
+		err := yaml.Unmarshal([]byte("muteTimeIntervals: [weekends]"), &settings)
+		require.NoError(t, err)
+		rule.NotificationSettings = &settings
+		_, err = rule.mapToModel(1)
+		require.ErrorIs(t, err, models.ErrAlertRuleFailedValidation)
+	})
 }
 
 func validRuleGroupV1(t *testing.T) AlertRuleGroupV1 {
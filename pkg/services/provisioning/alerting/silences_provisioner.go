@@ -0,0 +1,82 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
+)
+
+type SilencesProvisioner interface {
+	Provision(ctx context.Context, files []*AlertingFile) error
+	Unprovision(ctx context.Context, files []*AlertingFile) error
+}
+
+type defaultSilencesProvisioner struct {
+	logger         log.Logger
+	silenceService provisioning.SilenceService
+}
+
+func NewSilencesProvisioner(logger log.Logger,
+	silenceService provisioning.SilenceService) SilencesProvisioner {
+	return &defaultSilencesProvisioner{
+		logger:         logger,
+		silenceService: silenceService,
+	}
+}
+
+func (c *defaultSilencesProvisioner) Provision(ctx context.Context,
+	files []*AlertingFile) error {
+	cache := map[int64]map[string]definitions.ProvisionedSilence{}
+	now := time.Now()
+	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
+		for _, silence := range file.Silences {
+			// Silences of maintenance that is over stay in the files, they are not provisioned again.
+			if !silence.Silence.EndsAt.After(now) {
+				c.logger.Debug("Skipping silence that has ended", "uid", silence.Silence.UID, "org", silence.OrgID)
+				continue
+			}
+			if _, exists := cache[silence.OrgID]; !exists {
+				silences, err := c.silenceService.GetSilences(ctx, silence.OrgID)
+				if err != nil {
+					return err
+				}
+				cache[silence.OrgID] = make(map[string]definitions.ProvisionedSilence, len(silences))
+				for _, s := range silences {
+					cache[silence.OrgID][s.UID] = s
+				}
+			}
+			provenance := file.provenance()
+			if _, exists := cache[silence.OrgID][silence.Silence.UID]; exists {
+				_, err := c.silenceService.UpdateSilence(ctx, silence.OrgID, silence.Silence, provenance)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+			_, err := c.silenceService.CreateSilence(ctx, silence.OrgID, silence.Silence, provenance)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *defaultSilencesProvisioner) Unprovision(ctx context.Context,
+	files []*AlertingFile) error {
+	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
+		for _, deleteSilence := range file.DeleteSilences {
+			err := c.silenceService.DeleteSilence(ctx, deleteSilence.OrgID, deleteSilence.UID)
+			if err != nil && !errors.Is(err, provisioning.ErrNotFound) {
+				return err
+			}
+		}
+	}
+	return nil
+}
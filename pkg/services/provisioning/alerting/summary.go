@@ -0,0 +1,88 @@
+package alerting
+
+// The kinds of resources in a Summary, in the order they are provisioned.
+const (
+	SummaryKindContactPoints        = "contactPoints"
+	SummaryKindMuteTimings          = "muteTimings"
+	SummaryKindTemplates            = "templates"
+	SummaryKindAlertRules           = "alertRules"
+	SummaryKindNotificationPolicies = "notificationPolicies"
+	SummaryKindSilences             = "silences"
+)
+
+// SummaryStatus is the outcome of provisioning one kind of resource.
+type SummaryStatus string
+
+const (
+	// SummaryStatusApplied means that all resources of the kind were provisioned and deleted.
+	SummaryStatusApplied SummaryStatus = "applied"
+	// SummaryStatusFailed means that provisioning a resource of the kind failed. The resources before it may have
+	// been provisioned.
+	SummaryStatusFailed SummaryStatus = "failed"
+	// SummaryStatusSkipped means that the resources of the kind were not provisioned, or not completely, because
+	// provisioning stopped at the failure of another kind.
+	SummaryStatusSkipped SummaryStatus = "skipped"
+)
+
+// Summary is the outcome of provisioning the alerting resources of the provisioning files.
+type Summary struct {
+	// Files is the number of files that were read.
+	Files int `json:"files"`
+	// Resources is the outcome for each kind of resource, in the order they are provisioned.
+	Resources []ResourceSummary `json:"resources"`
+}
+
+// ResourceSummary is the outcome of provisioning one kind of resource.
+type ResourceSummary struct {
+	Kind string `json:"kind"`
+	// Provisioned is the number of resources of the files to create or update.
+	Provisioned int `json:"provisioned"`
+	// Deleted is the number of resources of the files to delete or reset.
+	Deleted int           `json:"deleted"`
+	Status  SummaryStatus `json:"status"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// newSummary counts the resources of the files. Every kind is skipped until it is provisioned.
+func newSummary(files []*AlertingFile) Summary {
+	contactPoints := ResourceSummary{Kind: SummaryKindContactPoints, Status: SummaryStatusSkipped}
+	muteTimings := ResourceSummary{Kind: SummaryKindMuteTimings, Status: SummaryStatusSkipped}
+	templates := ResourceSummary{Kind: SummaryKindTemplates, Status: SummaryStatusSkipped}
+	rules := ResourceSummary{Kind: SummaryKindAlertRules, Status: SummaryStatusSkipped}
+	policies := ResourceSummary{Kind: SummaryKindNotificationPolicies, Status: SummaryStatusSkipped}
+	silences := ResourceSummary{Kind: SummaryKindSilences, Status: SummaryStatusSkipped}
+	for _, file := range files {
+		for _, cp := range file.ContactPoints {
+			contactPoints.Provisioned += len(cp.ContactPoints)
+		}
+		contactPoints.Deleted += len(file.DeleteContactPoints)
+		muteTimings.Provisioned += len(file.MuteTimes)
+		muteTimings.Deleted += len(file.DeleteMuteTimes)
+		templates.Provisioned += len(file.Templates)
+		templates.Deleted += len(file.DeleteTemplates)
+		for _, group := range file.Groups {
+			rules.Provisioned += len(group.Rules)
+		}
+		rules.Deleted += len(file.DeleteRules)
+		policies.Provisioned += len(file.Policies)
+		policies.Deleted += len(file.ResetPolicies)
+		silences.Provisioned += len(file.Silences)
+		silences.Deleted += len(file.DeleteSilences)
+	}
+	return Summary{
+		Files:     len(files),
+		Resources: []ResourceSummary{contactPoints, muteTimings, templates, rules, policies, silences},
+	}
+}
+
+func (s *Summary) setStatus(kind string, status SummaryStatus, err error) {
+	for i := range s.Resources {
+		if s.Resources[i].Kind != kind {
+			continue
+		}
+		s.Resources[i].Status = status
+		if err != nil {
+			s.Resources[i].Error = err.Error()
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+)
+
+func TestSummary(t *testing.T) {
+	t.Run("counts the resources of the files", func(t *testing.T) {
+		files := []*AlertingFile{
+			{
+				ContactPoints: []ContactPoint{{OrgID: 1, ContactPoints: make([]definitions.EmbeddedContactPoint, 2)}},
+				Groups:        []models.AlertRuleGroupWithFolderTitle{{AlertRuleGroup: &models.AlertRuleGroup{Rules: make([]models.AlertRule, 3)}}},
+				ResetPolicies: []OrgID{1},
+			},
+			{
+				ContactPoints:       []ContactPoint{{OrgID: 2, ContactPoints: make([]definitions.EmbeddedContactPoint, 1)}},
+				DeleteContactPoints: []DeleteContactPoint{{OrgID: 1, UID: "deleted"}},
+				Templates:           []Template{{OrgID: 1}},
+				DeleteSilences:      []DeleteSilence{{OrgID: 2, UID: "maintenance"}},
+			},
+		}
+
+		summary := newSummary(files)
+
+		require.Equal(t, 2, summary.Files)
+		require.Equal(t, []ResourceSummary{
+			{Kind: SummaryKindContactPoints, Provisioned: 3, Deleted: 1, Status: SummaryStatusSkipped},
+			{Kind: SummaryKindMuteTimings, Status: SummaryStatusSkipped},
+			{Kind: SummaryKindTemplates, Provisioned: 1, Status: SummaryStatusSkipped},
+			{Kind: SummaryKindAlertRules, Provisioned: 3, Status: SummaryStatusSkipped},
+			{Kind: SummaryKindNotificationPolicies, Deleted: 1, Status: SummaryStatusSkipped},
+			{Kind: SummaryKindSilences, Deleted: 1, Status: SummaryStatusSkipped},
+		}, summary.Resources)
+	})
+
+	t.Run("records the failure of a kind of resource", func(t *testing.T) {
+		summary := newSummary(nil)
+
+		summary.setStatus(SummaryKindContactPoints, SummaryStatusApplied, nil)
+		summary.setStatus(SummaryKindMuteTimings, SummaryStatusFailed, errors.New("invalid mute timing"))
+
+		require.Equal(t, SummaryStatusApplied, summary.Resources[0].Status)
+		require.Equal(t, SummaryStatusFailed, summary.Resources[1].Status)
+		require.Equal(t, "invalid mute timing", summary.Resources[1].Error)
+		require.Equal(t, SummaryStatusSkipped, summary.Resources[2].Status)
+	})
+
+	t.Run("every kind is applied if provisioning succeeds", func(t *testing.T) {
+		summary, err := provision(context.Background(), log.NewNopLogger(), ProvisionerConfig{}, []*AlertingFile{{}})
+		require.NoError(t, err)
+
+		require.Equal(t, 1, summary.Files)
+		for _, r := range summary.Resources {
+			require.Equal(t, SummaryStatusApplied, r.Status, r.Kind)
+		}
+	})
+}
@@ -5,7 +5,6 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/log"
 	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
-	"github.com/grafana/grafana/pkg/services/ngalert/models"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 )
 
@@ -30,8 +29,9 @@ func NewTextTemplateProvisioner(logger log.Logger,
 func (c *defaultTextTemplateProvisioner) Provision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, template := range file.Templates {
-			template.Data.Provenance = definitions.Provenance(models.ProvenanceFile)
+			template.Data.Provenance = definitions.Provenance(file.provenance())
 			_, err := c.templateService.SetTemplate(ctx, template.OrgID, template.Data)
 			if err != nil {
 				return err
@@ -44,6 +44,7 @@ func (c *defaultTextTemplateProvisioner) Provision(ctx context.Context,
 func (c *defaultTextTemplateProvisioner) Unprovision(ctx context.Context,
 	files []*AlertingFile) error {
 	for _, file := range files {
+		ctx := provisioning.WithSource(ctx, file.Path)
 		for _, deleteTemplate := range file.DeleteTemplates {
 			err := c.templateService.DeleteTemplate(ctx, deleteTemplate.OrgID, deleteTemplate.Name)
 			if err != nil {
@@ -15,7 +15,12 @@ type OrgID int64
 
 type AlertingFile struct {
 	configVersion
-	Filename            string
+	Filename string
+	Path     string
+	// Provenance is the provenance the resources of the file are provisioned with. Defaults to models.ProvenanceFile.
+	Provenance models.Provenance
+	// Prune deletes the resources that were provisioned from files before but are not part of any file anymore.
+	Prune               bool
 	Groups              []models.AlertRuleGroupWithFolderTitle
 	DeleteRules         []RuleDelete
 	ContactPoints       []ContactPoint
@@ -26,11 +31,37 @@ type AlertingFile struct {
 	DeleteMuteTimes     []DeleteMuteTime
 	Templates           []Template
 	DeleteTemplates     []DeleteTemplate
+	Silences            []Silence
+	DeleteSilences      []DeleteSilence
+}
+
+func (file *AlertingFile) provenance() models.Provenance {
+	if file.Provenance == models.ProvenanceNone {
+		return models.ProvenanceFile
+	}
+	return file.Provenance
+}
+
+// moveToOrg makes all resources of the file belong to the organization.
+func (file *AlertingFile) moveToOrg(orgID int64) {
+	for i := range file.Groups {
+		file.Groups[i].OrgID = orgID
+		for j := range file.Groups[i].Rules {
+			file.Groups[i].Rules[j].OrgID = orgID
+		}
+	}
+	for i := range file.ContactPoints {
+		file.ContactPoints[i].OrgID = orgID
+	}
+	for i := range file.Policies {
+		file.Policies[i].OrgID = orgID
+	}
 }
 
 type AlertingFileV1 struct {
 	configVersion
 	Filename            string
+	Prune               values.BoolValue        `json:"prune" yaml:"prune"`
 	Groups              []AlertRuleGroupV1      `json:"groups" yaml:"groups"`
 	DeleteRules         []RuleDeleteV1          `json:"deleteRules" yaml:"deleteRules"`
 	ContactPoints       []ContactPointV1        `json:"contactPoints" yaml:"contactPoints"`
@@ -41,11 +72,14 @@ type AlertingFileV1 struct {
 	DeleteMuteTimes     []DeleteMuteTimeV1      `json:"deleteMuteTimes" yaml:"deleteMuteTimes"`
 	Templates           []TemplateV1            `json:"templates" yaml:"templates"`
 	DeleteTemplates     []DeleteTemplateV1      `json:"deleteTemplates" yaml:"deleteTemplates"`
+	Silences            []SilenceV1             `json:"silences" yaml:"silences"`
+	DeleteSilences      []DeleteSilenceV1       `json:"deleteSilences" yaml:"deleteSilences"`
 }
 
 func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	alertingFile := AlertingFile{}
 	alertingFile.Filename = fileV1.Filename
+	alertingFile.Prune = fileV1.Prune.Value()
 	if err := fileV1.mapRules(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing rules: %w", err)
 	}
@@ -61,9 +95,30 @@ func (fileV1 *AlertingFileV1) MapToModel() (AlertingFile, error) {
 	if err := fileV1.mapTemplates(&alertingFile); err != nil {
 		return AlertingFile{}, fmt.Errorf("failure parsing templates: %w", err)
 	}
+	if err := fileV1.mapSilences(&alertingFile); err != nil {
+		return AlertingFile{}, fmt.Errorf("failure parsing silences: %w", err)
+	}
 	return alertingFile, nil
 }
 
+func (fileV1 *AlertingFileV1) mapSilences(alertingFile *AlertingFile) error {
+	for _, sV1 := range fileV1.Silences {
+		s, err := sV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.Silences = append(alertingFile.Silences, s)
+	}
+	for _, deleteV1 := range fileV1.DeleteSilences {
+		delReq, err := deleteV1.mapToModel()
+		if err != nil {
+			return err
+		}
+		alertingFile.DeleteSilences = append(alertingFile.DeleteSilences, delReq)
+	}
+	return nil
+}
+
 func (fileV1 *AlertingFileV1) mapTemplates(alertingFile *AlertingFile) error {
 	for _, ttV1 := range fileV1.Templates {
 		alertingFile.Templates = append(alertingFile.Templates, ttV1.mapToModel())
@@ -0,0 +1,94 @@
+package alerting
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/services/ngalert/models"
+	"github.com/grafana/grafana/pkg/setting"
+)
+
+// FileError is the reason a provisioning file is invalid.
+type FileError struct {
+	File string
+	Err  error
+}
+
+func (e FileError) Error() string {
+	return fmt.Sprintf("%s: %s", e.File, e.Err)
+}
+
+// Validate checks the provisioning files of the directory without provisioning them. The files are parsed like when
+// they are provisioned, which validates the settings of the contact points, and the templates, mute timings,
+// silences, notification policies and alert rule groups are validated like the provisioning services do. References to
+// resources that may only exist in the database, such as the contact point of a notification policy, are not checked.
+// It returns the errors of the invalid files in the order of the files.
+func Validate(path string) ([]FileError, error) {
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	reader := newRulesConfigReader(log.NewNopLogger())
+	var fileErrs []FileError
+	for _, entry := range entries {
+		if entry.IsDir() || (!reader.isYAML(entry.Name()) && !reader.isJSON(entry.Name())) {
+			continue
+		}
+		file, err := reader.readFile(path, entry.Name())
+		if err == nil && file != nil {
+			err = validateFile(file)
+		}
+		if err != nil {
+			fileErrs = append(fileErrs, FileError{File: entry.Name(), Err: err})
+		}
+	}
+	return fileErrs, nil
+}
+
+// validateFile validates the resources of a parsed file that the parser does not validate.
+func validateFile(file *AlertingFile) error {
+	var errs []error
+	for _, t := range file.Templates {
+		if err := t.Data.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("template '%s': %w", t.Data.Name, err))
+		}
+	}
+	for _, mt := range file.MuteTimes {
+		if err := mt.MuteTime.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("mute timing '%s': %w", mt.MuteTime.Name, err))
+		}
+	}
+	for _, s := range file.Silences {
+		if err := s.Silence.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("silence '%s': %w", s.Silence.UID, err))
+		}
+	}
+	for _, p := range file.Policies {
+		if err := p.Policy.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("notification policy of organization %d: %w", p.OrgID, err))
+		}
+	}
+	for _, group := range file.Groups {
+		if err := models.ValidateRuleGroupInterval(group.Interval, int64(setting.SchedulerBaseInterval.Seconds())); err != nil {
+			errs = append(errs, fmt.Errorf("rule group '%s': %w", group.Title, err))
+		}
+		for _, rule := range group.Rules {
+			if err := validateRuleCondition(rule); err != nil {
+				errs = append(errs, fmt.Errorf("rule '%s': %w", rule.Title, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validateRuleCondition checks that the condition of the rule is one of its queries.
+func validateRuleCondition(rule models.AlertRule) error {
+	for _, query := range rule.Data {
+		if query.RefID == rule.Condition {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: condition '%s' is not the ref ID of any query", models.ErrAlertRuleFailedValidation, rule.Condition)
+}
@@ -0,0 +1,47 @@
+package alerting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid files have no errors", func(t *testing.T) {
+		for _, dir := range []string{testFileCorrectProperties, testFileCorrectProperties_cp, testFileCorrectProperties_mt, testFileCorrectProperties_t} {
+			fileErrs, err := Validate(dir)
+			require.NoError(t, err)
+			require.Empty(t, fileErrs, dir)
+		}
+	})
+
+	t.Run("returns the errors of each invalid file", func(t *testing.T) {
+		dir := t.TempDir()
+		write := func(name, content string) {
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+		}
+		write("broken.yaml", "{")
+		write("template.yaml", "apiVersion: 1\ntemplates:\n  - name: broken\n    template: '{{ define \"broken\" }}'\n")
+		write("policy.yaml", "apiVersion: 1\npolicies:\n  - orgId: 1\n    receiver: ''\n")
+		write("valid.yaml", "apiVersion: 1\n")
+		write("ignored.txt", "{")
+
+		fileErrs, err := Validate(dir)
+		require.NoError(t, err)
+
+		files := make([]string, 0, len(fileErrs))
+		for _, fileErr := range fileErrs {
+			files = append(files, fileErr.File)
+		}
+		require.Equal(t, []string{"broken.yaml", "policy.yaml", "template.yaml"}, files)
+		require.ErrorContains(t, fileErrs[1], "default receiver")
+		require.ErrorContains(t, fileErrs[2], "template 'broken'")
+	})
+
+	t.Run("fails if the directory does not exist", func(t *testing.T) {
+		_, err := Validate(filepath.Join(t.TempDir(), "missing"))
+		require.Error(t, err)
+	})
+}
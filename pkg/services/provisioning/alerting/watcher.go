@@ -0,0 +1,181 @@
+package alerting
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"github.com/grafana/grafana/pkg/infra/log"
+)
+
+// Watcher applies the alerting provisioning files of a directory as soon as they change, so that files updated while
+// Grafana runs, such as those mounted from a Kubernetes config map, take effect without a restart. Only the files
+// whose content changed are applied, each on its own so that an invalid file does not hold back the others.
+type Watcher struct {
+	cfg      ProvisionerConfig
+	debounce time.Duration
+	// mu prevents the files from being applied while they are provisioned by other means.
+	mu     sync.Locker
+	logger log.Logger
+	reader rulesConfigReader
+	// applied are the checksums of the files as they were last applied, by file name.
+	applied map[string][sha256.Size]byte
+}
+
+func NewWatcher(cfg ProvisionerConfig, debounce time.Duration, mu sync.Locker) *Watcher {
+	logger := log.New("provisioning.alerting.watcher")
+	return &Watcher{
+		cfg:      cfg,
+		debounce: debounce,
+		mu:       mu,
+		logger:   logger,
+		reader:   newRulesConfigReader(logger),
+	}
+}
+
+// Run watches the directory of the files until the context is canceled. The files that exist when it starts are
+// assumed to be provisioned already.
+func (w *Watcher) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = watcher.Close() }()
+	if err := watcher.Add(w.cfg.Path); err != nil {
+		return fmt.Errorf("failed to watch %s: %w", w.cfg.Path, err)
+	}
+	w.applied, err = w.checksums()
+	if err != nil {
+		return err
+	}
+	w.logger.Info("Watching alerting provisioning files", "path", w.cfg.Path)
+
+	var changed <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.logger.Debug("Alerting provisioning files changed", "file", event.Name, "op", event.Op.String())
+			// The files are applied once they stopped changing for the debounce period.
+			changed = time.After(w.debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.logger.Error("Failed to watch alerting provisioning files", "path", w.cfg.Path, "error", err)
+		case <-changed:
+			changed = nil
+			w.sync(ctx)
+		}
+	}
+}
+
+// sync applies the files that were added or changed since they were last applied. A file that fails to be applied is
+// applied again at the next change of any file. Pruning, if enabled, only happens when every file is valid, as the
+// resources of an invalid file would be deleted otherwise.
+func (w *Watcher) sync(ctx context.Context) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	checksums, err := w.checksums()
+	if err != nil {
+		w.logger.Error("Failed to read alerting provisioning files", "path", w.cfg.Path, "error", err)
+		return
+	}
+	var changed, removed []string
+	for name, sum := range checksums {
+		if applied, ok := w.applied[name]; !ok || applied != sum {
+			changed = append(changed, name)
+		}
+	}
+	for name := range w.applied {
+		if _, ok := checksums[name]; !ok {
+			removed = append(removed, name)
+			delete(w.applied, name)
+		}
+	}
+	if len(changed) == 0 && len(removed) == 0 {
+		return
+	}
+	sort.Strings(changed)
+	w.logger.Info("Applying changed alerting provisioning files", "changed", changed, "removed", removed)
+
+	for _, name := range changed {
+		if err := w.apply(ctx, name); err != nil {
+			w.logger.Error("Failed to apply alerting provisioning file", "file", name, "error", err)
+			continue
+		}
+		w.applied[name] = checksums[name]
+	}
+
+	files, err := w.reader.readConfig(ctx, w.cfg.Path)
+	if err != nil || !pruneEnabled(files) {
+		return
+	}
+	deletions, err := pruneDeletions(ctx, w.cfg, files)
+	if err == nil {
+		_, err = provision(ctx, w.logger, w.cfg, []*AlertingFile{deletions})
+	}
+	if err != nil {
+		w.logger.Error("Failed to prune alerting resources removed from the provisioning files", "error", err)
+	}
+}
+
+// apply provisions a single file and records the outcome in the status of the organizations it refers to, or of the
+// organizations provisioned from files before if it cannot be read.
+func (w *Watcher) apply(ctx context.Context, name string) error {
+	file, err := w.reader.readFile(w.cfg.Path, name)
+	var files []*AlertingFile
+	if err == nil && file != nil {
+		files = []*AlertingFile{file}
+		_, err = provision(ctx, w.logger, w.cfg, files)
+	}
+	if w.cfg.Status == nil {
+		return err
+	}
+	orgIDs := referencedOrgs(files)
+	if file == nil {
+		var statusErr error
+		orgIDs, statusErr = w.cfg.Status.ProvisionedOrgs(ctx)
+		if statusErr != nil {
+			w.logger.Error("Failed to get organizations provisioned from files", "error", statusErr)
+		}
+	}
+	if statusErr := w.cfg.Status.RecordFileResult(ctx, orgIDs, name, err); statusErr != nil {
+		w.logger.Error("Failed to record the status of the provisioning", "file", name, "error", statusErr)
+	}
+	return err
+}
+
+// checksums returns the checksums of the provisioning files of the directory by file name.
+func (w *Watcher) checksums() (map[string][sha256.Size]byte, error) {
+	entries, err := os.ReadDir(w.cfg.Path)
+	if err != nil {
+		return nil, err
+	}
+	checksums := make(map[string][sha256.Size]byte, len(entries))
+	for _, entry := range entries {
+		if !w.reader.isYAML(entry.Name()) && !w.reader.isJSON(entry.Name()) {
+			continue
+		}
+		// nolint:gosec
+		// The files are read from the alerting provisioning directory, like in the config reader.
+		data, err := os.ReadFile(filepath.Join(w.cfg.Path, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		checksums[entry.Name()] = sha256.Sum256(data)
+	}
+	return checksums, nil
+}
@@ -0,0 +1,61 @@
+package alerting
+
+import (
+	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	write := func(name, content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0600))
+	}
+	w := NewWatcher(ProvisionerConfig{Path: dir}, 0, &sync.Mutex{})
+	w.applied = map[string][sha256.Size]byte{}
+
+	t.Run("applies added files", func(t *testing.T) {
+		write("a.yaml", "apiVersion: 1\n")
+		write("ignored.txt", "not a provisioning file")
+
+		w.sync(ctx)
+
+		require.Contains(t, w.applied, "a.yaml")
+		require.NotContains(t, w.applied, "ignored.txt")
+	})
+
+	t.Run("keeps invalid files to be applied again", func(t *testing.T) {
+		write("b.yaml", "{")
+
+		w.sync(ctx)
+
+		require.Contains(t, w.applied, "a.yaml")
+		require.NotContains(t, w.applied, "b.yaml")
+	})
+
+	t.Run("applies changed files", func(t *testing.T) {
+		before := w.applied["a.yaml"]
+		write("a.yaml", "apiVersion: 1\ncontactPoints: []\n")
+		write("b.yaml", "apiVersion: 1\n")
+
+		w.sync(ctx)
+
+		require.NotEqual(t, before, w.applied["a.yaml"])
+		require.Contains(t, w.applied, "b.yaml")
+	})
+
+	t.Run("forgets removed files", func(t *testing.T) {
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.yaml")))
+
+		w.sync(ctx)
+
+		require.NotContains(t, w.applied, "a.yaml")
+		require.Contains(t, w.applied, "b.yaml")
+	})
+}
@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"sync"
+	"time"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/infra/kvstore"
 	"github.com/grafana/grafana/pkg/infra/log"
+	"github.com/grafana/grafana/pkg/infra/tracing"
 	plugifaces "github.com/grafana/grafana/pkg/plugins"
 	"github.com/grafana/grafana/pkg/registry"
 	"github.com/grafana/grafana/pkg/services/accesscontrol"
@@ -17,6 +20,8 @@ import (
 	datasourceservice "github.com/grafana/grafana/pkg/services/datasources"
 	"github.com/grafana/grafana/pkg/services/encryption"
 	"github.com/grafana/grafana/pkg/services/folder"
+	ngmetrics "github.com/grafana/grafana/pkg/services/ngalert/metrics"
+	"github.com/grafana/grafana/pkg/services/ngalert/notifier/secretrefs"
 	"github.com/grafana/grafana/pkg/services/ngalert/provisioning"
 	"github.com/grafana/grafana/pkg/services/ngalert/store"
 	"github.com/grafana/grafana/pkg/services/notifications"
@@ -51,6 +56,9 @@ func ProvideService(
 	quotaService quota.Service,
 	secrectService secrets.Service,
 	orgService org.Service,
+	tracer tracing.Tracer,
+	alertingMetrics *ngmetrics.NGAlert,
+	kvStore kvstore.KVStore,
 ) (*ProvisioningServiceImpl, error) {
 	s := &ProvisioningServiceImpl{
 		Cfg:                          cfg,
@@ -63,7 +71,7 @@ func ProvideService(
 		provisionNotifiers:           notifiers.Provision,
 		provisionDatasources:         datasources.Provision,
 		provisionPlugins:             plugins.Provision,
-		provisionAlerting:            prov_alerting.Provision,
+		provisionAlerting:            prov_alerting.ProvisionWithSummary,
 		dashboardProvisioningService: dashboardProvisioningService,
 		dashboardService:             dashboardService,
 		datasourceService:            datasourceService,
@@ -75,6 +83,9 @@ func ProvideService(
 		secretService:                secrectService,
 		log:                          log.New("provisioning"),
 		orgService:                   orgService,
+		tracer:                       tracer,
+		alertingMetrics:              alertingMetrics,
+		kvStore:                      kvStore,
 	}
 	return s, nil
 }
@@ -87,6 +98,8 @@ type ProvisioningService interface {
 	ProvisionNotifications(ctx context.Context) error
 	ProvisionDashboards(ctx context.Context) error
 	ProvisionAlerting(ctx context.Context) error
+	// ReloadAlerting provisions the alerting resources from the files again and returns a summary of the outcome.
+	ReloadAlerting(ctx context.Context) (prov_alerting.Summary, error)
 	GetDashboardProvisionerResolvedPath(name string) string
 	GetAllowUIUpdatesFromConfig(name string) bool
 }
@@ -134,7 +147,7 @@ type ProvisioningServiceImpl struct {
 	provisionNotifiers           func(context.Context, string, notifiers.Manager, org.Service, encryption.Internal, *notifications.NotificationService) error
 	provisionDatasources         func(context.Context, string, datasources.Store, datasources.CorrelationsStore, org.Service) error
 	provisionPlugins             func(context.Context, string, plugifaces.Store, pluginsettings.Service, org.Service) error
-	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) error
+	provisionAlerting            func(context.Context, prov_alerting.ProvisionerConfig) (prov_alerting.Summary, error)
 	mutex                        sync.Mutex
 	dashboardProvisioningService dashboardservice.DashboardProvisioningService
 	dashboardService             dashboardservice.DashboardService
@@ -145,6 +158,12 @@ type ProvisioningServiceImpl struct {
 	searchService                searchV2.SearchService
 	quotaService                 quota.Service
 	secretService                secrets.Service
+	tracer                       tracing.Tracer
+	alertingMetrics              *ngmetrics.NGAlert
+	kvStore                      kvstore.KVStore
+	// alertingMutex prevents alerting from being provisioned from files concurrently, for example when it is reloaded
+	// while Grafana starts.
+	alertingMutex sync.Mutex
 }
 
 func (ps *ProvisioningServiceImpl) RunInitProvisioners(ctx context.Context) error {
@@ -180,6 +199,12 @@ func (ps *ProvisioningServiceImpl) Run(ctx context.Context) error {
 	if ps.dashboardProvisioner.HasDashboardSources() {
 		ps.searchService.TriggerReIndex()
 	}
+	if remote := ps.Cfg.UnifiedAlerting.RemoteSync; remote.URL != "" && remote.Interval > 0 {
+		go ps.pollAlertingFromRemote(ctx, remote.Interval)
+	}
+	if ps.Cfg.UnifiedAlerting.ProvisioningFilesWatch {
+		go ps.watchAlertingFiles(ctx)
+	}
 
 	for {
 		// Wait for unlock. This is tied to new dashboardProvisioner to be instantiated before we start polling.
@@ -257,7 +282,59 @@ func (ps *ProvisioningServiceImpl) ProvisionDashboards(ctx context.Context) erro
 }
 
 func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error {
-	alertingPath := filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	_, err := ps.ReloadAlerting(ctx)
+	return err
+}
+
+func (ps *ProvisioningServiceImpl) ReloadAlerting(ctx context.Context) (prov_alerting.Summary, error) {
+	ps.alertingMutex.Lock()
+	defer ps.alertingMutex.Unlock()
+	return ps.provisionAlerting(ctx, ps.alertingFilesProvisionerConfig())
+}
+
+// watchAlertingFiles applies the alerting provisioning files as soon as they change.
+func (ps *ProvisioningServiceImpl) watchAlertingFiles(ctx context.Context) {
+	watcher := prov_alerting.NewWatcher(ps.alertingFilesProvisionerConfig(), ps.Cfg.UnifiedAlerting.ProvisioningFilesWatchDebounce, &ps.alertingMutex)
+	if err := watcher.Run(ctx); err != nil {
+		ps.log.Error("Failed to watch the alerting provisioning files", "error", err)
+	}
+}
+
+// alertingFilesProvisionerConfig returns the configuration to provision alerting resources from the files of the
+// provisioning directory with.
+func (ps *ProvisioningServiceImpl) alertingFilesProvisionerConfig() prov_alerting.ProvisionerConfig {
+	cfg := ps.alertingProvisionerConfig()
+	cfg.Path = filepath.Join(ps.Cfg.ProvisioningPath, "alerting")
+	cfg.Status = provisioning.NewFileProvisioningStatusStore(ps.kvStore)
+	return cfg
+}
+
+// SyncAlertingFromRemote pulls the alerting configuration from the Grafana instance configured in the
+// [unified_alerting.remote_sync] section and applies it to the local organization.
+func (ps *ProvisioningServiceImpl) SyncAlertingFromRemote(ctx context.Context) error {
+	remote := ps.Cfg.UnifiedAlerting.RemoteSync
+	return prov_alerting.SyncFromRemote(ctx, ps.alertingProvisionerConfig(), prov_alerting.RemoteConfig{
+		URL:   remote.URL,
+		Token: remote.Token,
+		OrgID: remote.OrgID,
+	})
+}
+
+func (ps *ProvisioningServiceImpl) pollAlertingFromRemote(ctx context.Context, interval time.Duration) {
+	for {
+		if err := ps.SyncAlertingFromRemote(ctx); err != nil {
+			ps.log.Error("Failed to synchronize the alerting configuration from the remote instance", "url", ps.Cfg.UnifiedAlerting.RemoteSync.URL, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// alertingProvisionerConfig returns the services that alerting resources are provisioned with.
+func (ps *ProvisioningServiceImpl) alertingProvisionerConfig() prov_alerting.ProvisionerConfig {
 	st := store.DBstore{
 		Cfg:              ps.Cfg.UnifiedAlerting,
 		SQLStore:         ps.SQLStore,
@@ -266,23 +343,37 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		AccessControl:    ps.ac,
 		DashboardService: ps.dashboardService,
 	}
+	provisioningMetrics := ps.alertingMetrics.GetProvisioningMetrics()
 	ruleService := provisioning.NewAlertRuleService(
 		st,
 		st,
+		&st,
 		ps.dashboardService,
 		ps.quotaService,
 		ps.SQLStore,
+		nil,
 		int64(ps.Cfg.UnifiedAlerting.DefaultRuleEvaluationInterval.Seconds()),
 		int64(ps.Cfg.UnifiedAlerting.BaseInterval.Seconds()),
-		ps.log)
-	contactPointService := provisioning.NewContactPointService(&st, ps.secretService,
-		st, ps.SQLStore, ps.log, ps.ac)
+		ps.log,
+		ps.ac,
+		ps.tracer,
+		provisioningMetrics)
+	secretRefs, err := secretrefs.NewResolver(ps.Cfg.UnifiedAlerting.ContactPointSecretReferences)
+	if err != nil {
+		// The alerting service refuses to start with invalid patterns, no references are allowed until they are fixed.
+		ps.log.Error("Invalid contact_point_secret_references", "error", err)
+	}
+	contactPointService := provisioning.NewContactPointService(&st, ps.secretService, secretRefs,
+		st, st, provisioning.NewContactPointExpirationStore(ps.kvStore), provisioning.NewDeletedContactPointStore(ps.kvStore, ps.Cfg.UnifiedAlerting.DeletedContactPointRetention),
+		provisioning.NewProvisioningVariablesService(ps.kvStore, ps.tracer, provisioningMetrics), nil, nil, notifications.NewWebhookClient(ps.Cfg.UnifiedAlerting.NotificationBlockedIPRanges), st, ps.SQLStore, ps.quotaService, ps.log, ps.ac, ps.tracer, provisioningMetrics)
 	notificationPolicyService := provisioning.NewNotificationPolicyService(&st,
-		st, ps.SQLStore, ps.Cfg.UnifiedAlerting, ps.log)
-	mutetimingsService := provisioning.NewMuteTimingService(&st, st, &st, ps.log)
-	templateService := provisioning.NewTemplateService(&st, st, &st, ps.log)
-	cfg := prov_alerting.ProvisionerConfig{
-		Path:                       alertingPath,
+		st, ps.SQLStore, ps.quotaService, ps.Cfg.UnifiedAlerting, ps.log, ps.tracer, provisioningMetrics)
+	mutetimingsService := provisioning.NewMuteTimingService(&st, st, st, &st, ps.quotaService, ps.log, ps.tracer, provisioningMetrics)
+	templateService := provisioning.NewTemplateService(&st, st, &st, ps.quotaService, ps.log, ps.tracer, provisioningMetrics)
+	provenanceService := provisioning.NewProvenanceService(&st, st, st, st, ps.log, ps.tracer, provisioningMetrics)
+	// The Alertmanagers are not available here, the provisioned silences are applied to them by the alerting service.
+	silenceService := provisioning.NewSilenceService(nil, st, ps.kvStore, ps.SQLStore, ps.log, ps.tracer, provisioningMetrics)
+	return prov_alerting.ProvisionerConfig{
 		RuleService:                *ruleService,
 		DashboardService:           ps.dashboardService,
 		DashboardProvService:       ps.dashboardProvisioningService,
@@ -290,8 +381,10 @@ func (ps *ProvisioningServiceImpl) ProvisionAlerting(ctx context.Context) error
 		NotificiationPolicyService: *notificationPolicyService,
 		MuteTimingService:          *mutetimingsService,
 		TemplateService:            *templateService,
+		SilenceService:             *silenceService,
+		ProvenanceService:          provenanceService,
+		Orgs:                       st,
 	}
-	return ps.provisionAlerting(ctx, cfg)
 }
 
 func (ps *ProvisioningServiceImpl) GetDashboardProvisionerResolvedPath(name string) string {
@@ -1,6 +1,10 @@
 package provisioning
 
-import "context"
+import (
+	"context"
+
+	prov_alerting "github.com/grafana/grafana/pkg/services/provisioning/alerting"
+)
 
 type Calls struct {
 	RunInitProvisioners                 []any
@@ -9,6 +13,7 @@ type Calls struct {
 	ProvisionNotifications              []any
 	ProvisionDashboards                 []any
 	ProvisionAlerting                   []any
+	ReloadAlerting                      []any
 	GetDashboardProvisionerResolvedPath []any
 	GetAllowUIUpdatesFromConfig         []any
 	Run                                 []any
@@ -77,6 +82,11 @@ func (mock *ProvisioningServiceMock) ProvisionAlerting(ctx context.Context) erro
 	return nil
 }
 
+func (mock *ProvisioningServiceMock) ReloadAlerting(ctx context.Context) (prov_alerting.Summary, error) {
+	mock.Calls.ReloadAlerting = append(mock.Calls.ReloadAlerting, nil)
+	return prov_alerting.Summary{}, nil
+}
+
 func (mock *ProvisioningServiceMock) GetDashboardProvisionerResolvedPath(name string) string {
 	mock.Calls.GetDashboardProvisionerResolvedPath = append(mock.Calls.GetDashboardProvisionerResolvedPath, name)
 	if mock.GetDashboardProvisionerResolvedPathFunc != nil {
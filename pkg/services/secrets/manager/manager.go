@@ -183,8 +183,14 @@ func (s *SecretsService) Encrypt(ctx context.Context, payload []byte, opt secret
 		return nil, err
 	}
 
-	var encrypted []byte
-	encrypted, err = s.enc.Encrypt(ctx, payload, string(dataKey))
+	var blob []byte
+	blob, err = s.encryptWithDataKey(ctx, payload, id, dataKey)
+	return blob, err
+}
+
+// encryptWithDataKey encrypts the payload with the given data key and prefixes the result with the data key id.
+func (s *SecretsService) encryptWithDataKey(ctx context.Context, payload []byte, id string, dataKey []byte) ([]byte, error) {
+	encrypted, err := s.enc.Encrypt(ctx, payload, string(dataKey))
 	if err != nil {
 		s.log.Error("Failed to encrypt secret", "error", err)
 		return nil, err
@@ -372,10 +378,44 @@ func (s *SecretsService) Decrypt(ctx context.Context, payload []byte) ([]byte, e
 	return decrypted, err
 }
 
+// EncryptJsonData encrypts all the given values. When envelope encryption is used,
+// the current data key is resolved once and reused for every value.
 func (s *SecretsService) EncryptJsonData(ctx context.Context, kv map[string]string, opt secrets.EncryptionOptions) (map[string][]byte, error) {
-	encrypted := make(map[string][]byte)
+	encrypted := make(map[string][]byte, len(kv))
+	if len(kv) == 0 {
+		return encrypted, nil
+	}
+
+	if s.features.IsEnabled(featuremgmt.FlagDisableEnvelopeEncryption) {
+		for key, value := range kv {
+			encryptedData, err := s.Encrypt(ctx, []byte(value), opt)
+			if err != nil {
+				return nil, err
+			}
+
+			encrypted[key] = encryptedData
+		}
+		return encrypted, nil
+	}
+
+	scope := opt()
+	label := secrets.KeyLabel(scope, s.currentProviderID)
+	id, dataKey, err := s.currentDataKey(ctx, label, scope)
+	if err != nil {
+		s.log.Error("Failed to get current data key", "error", err, "label", label)
+		opsCounter.With(prometheus.Labels{
+			"success":   "false",
+			"operation": OpEncrypt,
+		}).Inc()
+		return nil, err
+	}
+
 	for key, value := range kv {
-		encryptedData, err := s.Encrypt(ctx, []byte(value), opt)
+		encryptedData, err := s.encryptWithDataKey(ctx, []byte(value), id, dataKey)
+		opsCounter.With(prometheus.Labels{
+			"success":   strconv.FormatBool(err == nil),
+			"operation": OpEncrypt,
+		}).Inc()
 		if err != nil {
 			return nil, err
 		}
@@ -74,6 +74,25 @@ func TestSecretsService_EnvelopeEncryption(t *testing.T) {
 		assert.Equal(t, len(keys), 2)
 	})
 
+	t.Run("encrypting json data should reuse the current DEK for all values", func(t *testing.T) {
+		kv := map[string]string{
+			"token":    "some token",
+			"password": "some password",
+		}
+
+		encrypted, err := svc.EncryptJsonData(context.Background(), kv, secrets.WithScope("user:100"))
+		require.NoError(t, err)
+		require.Len(t, encrypted, 2)
+
+		decrypted, err := svc.DecryptJsonData(context.Background(), encrypted)
+		require.NoError(t, err)
+		assert.Equal(t, kv, decrypted)
+
+		keys, err := store.GetAllDataKeys(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, len(keys), 2)
+	})
+
 	t.Run("usage stats should be registered", func(t *testing.T) {
 		reports, err := svc.usageStats.GetUsageReport(context.Background())
 		require.NoError(t, err)
@@ -44,6 +44,7 @@ func ProvideSecretsMigrator(
 		jsonSecret{tableName: "data_source"},
 		jsonSecret{tableName: "plugin_setting"},
 		alertingSecret{},
+		alertingResourceSecret{},
 	}
 
 	return &SecretsMigrator{
@@ -156,6 +157,10 @@ type jsonSecret struct {
 
 type alertingSecret struct{}
 
+// alertingResourceSecret holds the secrets of the receivers of Alertmanager configurations that are stored separately
+// from the configuration document.
+type alertingResourceSecret struct{}
+
 func nowInUTC() string {
 	return time.Now().UTC().Format("2006-01-02 15:04:05")
 }
@@ -7,6 +7,7 @@ import (
 	"fmt"
 
 	"github.com/grafana/grafana/pkg/infra/db"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/secrets"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
@@ -212,7 +213,8 @@ func (s alertingSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.Secre
 		AlertmanagerConfiguration string
 	}
 
-	selectSQL := "SELECT id, alertmanager_configuration FROM alert_configuration"
+	// The receivers of configurations with separately stored resources are handled by alertingResourceSecret.
+	selectSQL := "SELECT id, alertmanager_configuration FROM alert_configuration WHERE org_id NOT IN (SELECT org_id FROM alert_configuration_resource)"
 	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		return sess.SQL(selectSQL).Find(&results)
 	}); err != nil {
@@ -289,3 +291,85 @@ func (s alertingSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.Secre
 
 	return !anyFailure
 }
+
+func (s alertingResourceSecret) ReEncrypt(ctx context.Context, secretsSrv *manager.SecretsService, sqlStore db.DB) bool {
+	var results []struct {
+		Id      int
+		Content string
+	}
+
+	selectSQL := "SELECT id, content FROM alert_configuration_resource WHERE kind = 'receiver'"
+	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(selectSQL).Find(&results)
+	}); err != nil {
+		logger.Warn("Could not find any alert_configuration_resource secret to re-encrypt")
+		return false
+	}
+
+	var anyFailure bool
+
+	for _, result := range results {
+		result := result
+
+		err := sqlStore.InTransaction(ctx, func(ctx context.Context) error {
+			receiver := &definitions.PostableApiReceiver{}
+			if err := json.Unmarshal([]byte(result.Content), receiver); err != nil {
+				logger.Warn("Could not load alert_configuration_resource while re-encrypting it", "id", result.Id, "error", err)
+				return err
+			}
+
+			for _, gmr := range receiver.GrafanaManagedReceivers {
+				for k, v := range gmr.SecureSettings {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						logger.Warn("Could not decode base64-encoded alert_configuration_resource secret", "id", result.Id, "key", k, "error", err)
+						return err
+					}
+
+					decrypted, err := secretsSrv.Decrypt(ctx, decoded)
+					if err != nil {
+						logger.Warn("Could not decrypt alert_configuration_resource secret", "id", result.Id, "key", k, "error", err)
+						return err
+					}
+
+					reencrypted, err := secretsSrv.Encrypt(ctx, decrypted, secrets.WithoutScope())
+					if err != nil {
+						logger.Warn("Could not re-encrypt alert_configuration_resource secret", "id", result.Id, "key", k, "error", err)
+						return err
+					}
+
+					gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(reencrypted)
+				}
+			}
+
+			marshalled, err := json.Marshal(receiver)
+			if err != nil {
+				logger.Warn("Could not marshal alert_configuration_resource while re-encrypting it", "id", result.Id, "error", err)
+				return err
+			}
+
+			result.Content = string(marshalled)
+			if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+				_, err := sess.Table("alert_configuration_resource").Where("id = ?", result.Id).Update(&result)
+				return err
+			}); err != nil {
+				logger.Warn("Could not update alert_configuration_resource secret while re-encrypting it", "id", result.Id, "error", err)
+				return err
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			anyFailure = true
+		}
+	}
+
+	if anyFailure {
+		logger.Warn("Alerting configuration resource secrets have been re-encrypted with errors")
+	} else {
+		logger.Info("Alerting configuration resource secrets have been re-encrypted successfully")
+	}
+
+	return !anyFailure
+}
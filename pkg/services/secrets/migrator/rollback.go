@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/grafana/pkg/infra/db"
 	"github.com/grafana/grafana/pkg/services/encryption"
+	"github.com/grafana/grafana/pkg/services/ngalert/api/tooling/definitions"
 	"github.com/grafana/grafana/pkg/services/ngalert/notifier"
 	"github.com/grafana/grafana/pkg/services/secrets/manager"
 )
@@ -222,7 +223,8 @@ func (s alertingSecret) Rollback(
 		AlertmanagerConfiguration string
 	}
 
-	selectSQL := "SELECT id, alertmanager_configuration FROM alert_configuration"
+	// The receivers of configurations with separately stored resources are handled by alertingResourceSecret.
+	selectSQL := "SELECT id, alertmanager_configuration FROM alert_configuration WHERE org_id NOT IN (SELECT org_id FROM alert_configuration_resource)"
 	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
 		return sess.SQL(selectSQL).Find(&results)
 	}); err != nil {
@@ -294,3 +296,86 @@ func (s alertingSecret) Rollback(
 
 	return anyFailure
 }
+
+func (s alertingResourceSecret) Rollback(
+	ctx context.Context,
+	secretsSrv *manager.SecretsService,
+	encryptionSrv encryption.Internal,
+	sqlStore db.DB,
+	secretKey string,
+) (anyFailure bool) {
+	var results []struct {
+		Id      int
+		Content string
+	}
+
+	selectSQL := "SELECT id, content FROM alert_configuration_resource WHERE kind = 'receiver'"
+	if err := sqlStore.WithDbSession(ctx, func(sess *db.Session) error {
+		return sess.SQL(selectSQL).Find(&results)
+	}); err != nil {
+		logger.Warn("Could not find any alert_configuration_resource secret to roll back")
+		return true
+	}
+
+	for _, result := range results {
+		result := result
+
+		err := sqlStore.WithTransactionalDbSession(ctx, func(sess *db.Session) error {
+			receiver := &definitions.PostableApiReceiver{}
+			if err := json.Unmarshal([]byte(result.Content), receiver); err != nil {
+				logger.Warn("Could not load receiver (alert_configuration_resource with id: %d) while rolling it back", result.Id, err)
+				return err
+			}
+
+			for _, gmr := range receiver.GrafanaManagedReceivers {
+				for k, v := range gmr.SecureSettings {
+					decoded, err := base64.StdEncoding.DecodeString(v)
+					if err != nil {
+						logger.Warn("Could not decode base64-encoded secret (alert_configuration_resource with id: %d, key)", k, result.Id, err)
+						return err
+					}
+
+					decrypted, err := secretsSrv.Decrypt(ctx, decoded)
+					if err != nil {
+						logger.Warn("Could not decrypt secret (alert_configuration_resource with id: %d, key)", k, result.Id, err)
+						return err
+					}
+
+					reencrypted, err := encryptionSrv.Encrypt(ctx, decrypted, secretKey)
+					if err != nil {
+						logger.Warn("Could not re-encrypt secret (alert_configuration_resource with id: %d, key)", k, result.Id, err)
+						return err
+					}
+
+					gmr.SecureSettings[k] = base64.StdEncoding.EncodeToString(reencrypted)
+				}
+			}
+
+			marshalled, err := json.Marshal(receiver)
+			if err != nil {
+				logger.Warn("Could not marshal receiver (alert_configuration_resource with id: %d) while rolling it back", result.Id, err)
+				return err
+			}
+
+			result.Content = string(marshalled)
+			if _, err := sess.Table("alert_configuration_resource").Where("id = ?", result.Id).Update(&result); err != nil {
+				logger.Warn("Could not update secret (alert_configuration_resource with id: %d) while rolling it back", result.Id, err)
+				return err
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			anyFailure = true
+		}
+	}
+
+	if anyFailure {
+		logger.Warn("Alerting configuration resource secrets have been rolled back with errors")
+	} else {
+		logger.Info("Alerting configuration resource secrets have been rolled back successfully")
+	}
+
+	return anyFailure
+}
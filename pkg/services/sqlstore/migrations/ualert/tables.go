@@ -24,6 +24,37 @@ func AddTablesMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add last_applied column to alert_configuration_history", migrator.NewAddColumnMigration(migrator.Table{Name: "alert_configuration_history"}, &migrator.Column{
 		Name: "last_applied", Type: migrator.DB_Int, Nullable: false, Default: "0",
 	}))
+
+	// Create the provisioning audit log
+	addProvisioningAuditLogMigrations(mg)
+
+	mg.AddMigration("add source column to provisioning_audit_log", migrator.NewAddColumnMigration(migrator.Table{Name: "provisioning_audit_log"}, &migrator.Column{
+		Name: "source", Type: migrator.DB_Text, Nullable: true,
+	}))
+
+	for _, table := range []string{"alert_configuration", "alert_configuration_history", "provisioning_audit_log"} {
+		mg.AddMigration("add correlation_id column to "+table, migrator.NewAddColumnMigration(migrator.Table{Name: table}, &migrator.Column{
+			Name: "correlation_id", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: true,
+		}))
+	}
+
+	// Create the table of alerting configuration snapshots
+	addAlertingSnapshotMigrations(mg)
+
+	for _, table := range []string{"alert_rule", "alert_rule_version"} {
+		mg.AddMigration("add notification_settings column to "+table, migrator.NewAddColumnMigration(migrator.Table{Name: table}, &migrator.Column{
+			Name: "notification_settings", Type: migrator.DB_Text, Nullable: true,
+		}))
+	}
+
+	for _, table := range []string{"alert_rule", "alert_rule_version"} {
+		mg.AddMigration("add evaluation_offset_seconds column to "+table, migrator.NewAddColumnMigration(migrator.Table{Name: table}, &migrator.Column{
+			Name: "evaluation_offset_seconds", Type: migrator.DB_BigInt, Nullable: false, Default: "0",
+		}))
+	}
+
+	// Create the table of the resources of Alertmanager configurations that are stored separately
+	addAlertConfigurationResourceMigrations(mg)
 	// End of migration log, add new migrations above this line.
 }
 
@@ -466,6 +497,33 @@ func addProvisioningMigrations(mg *migrator.Migrator) {
 	mg.AddMigration("add index to uniquify (record_key, record_type, org_id) columns", migrator.NewAddIndexMigration(provisioningTable, provisioningTable.Indices[0]))
 }
 
+func addProvisioningAuditLogMigrations(mg *migrator.Migrator) {
+	auditLogTable := migrator.Table{
+		Name: "provisioning_audit_log",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "actor_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "actor_login", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "action", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "resource_type", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "resource_id", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "provenance", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "old_state", Type: migrator.DB_MediumText, Nullable: true},
+			{Name: "new_state", Type: migrator.DB_MediumText, Nullable: true},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created"}},
+			{Cols: []string{"org_id", "resource_type", "resource_id"}},
+		},
+	}
+
+	mg.AddMigration("create provisioning_audit_log table", migrator.NewAddTableMigration(auditLogTable))
+	mg.AddMigration("add index in provisioning_audit_log on org_id and created columns", migrator.NewAddIndexMigration(auditLogTable, auditLogTable.Indices[0]))
+	mg.AddMigration("add index in provisioning_audit_log on org_id, resource_type and resource_id columns", migrator.NewAddIndexMigration(auditLogTable, auditLogTable.Indices[1]))
+}
+
 func addAlertImageMigrations(mg *migrator.Migrator) {
 	// DO NOT EDIT
 	imageTable := migrator.Table{
@@ -568,3 +626,43 @@ func (c extractAlertmanagerConfigurationHistory) Exec(sess *xorm.Session, migrat
 	}
 	return nil
 }
+
+func addAlertingSnapshotMigrations(mg *migrator.Migrator) {
+	snapshotTable := migrator.Table{
+		Name: "alerting_snapshot",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "alertmanager_configuration", Type: migrator.DB_MediumText, Nullable: true},
+			{Name: "rules", Type: migrator.DB_MediumText, Nullable: true},
+			{Name: "provenances", Type: migrator.DB_MediumText, Nullable: true},
+			{Name: "created", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "created"}},
+		},
+	}
+
+	mg.AddMigration("create alerting_snapshot table", migrator.NewAddTableMigration(snapshotTable))
+	mg.AddMigration("add index in alerting_snapshot on org_id and created columns", migrator.NewAddIndexMigration(snapshotTable, snapshotTable.Indices[0]))
+}
+
+func addAlertConfigurationResourceMigrations(mg *migrator.Migrator) {
+	resourceTable := migrator.Table{
+		Name: "alert_configuration_resource",
+		Columns: []*migrator.Column{
+			{Name: "id", Type: migrator.DB_BigInt, IsPrimaryKey: true, IsAutoIncrement: true},
+			{Name: "org_id", Type: migrator.DB_BigInt, Nullable: false},
+			{Name: "kind", Type: migrator.DB_NVarchar, Length: 40, Nullable: false},
+			{Name: "name", Type: migrator.DB_NVarchar, Length: DefaultFieldMaxLength, Nullable: false},
+			{Name: "content", Type: migrator.DB_MediumText, Nullable: false},
+			{Name: "updated", Type: migrator.DB_BigInt, Nullable: false},
+		},
+		Indices: []*migrator.Index{
+			{Cols: []string{"org_id", "kind", "name"}, Type: migrator.UniqueIndex},
+		},
+	}
+
+	mg.AddMigration("create alert_configuration_resource table", migrator.NewAddTableMigration(resourceTable))
+	mg.AddMigration("add unique index in alert_configuration_resource on org_id, kind and name columns", migrator.NewAddIndexMigration(resourceTable, resourceTable.Indices[0]))
+}
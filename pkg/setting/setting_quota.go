@@ -6,6 +6,11 @@ type OrgQuota struct {
 	Dashboard  int64 `target:"dashboard"`
 	ApiKey     int64 `target:"api_key"`
 	AlertRule  int64 `target:"alert_rule"`
+
+	AlertContactPoint       int64 `target:"alert_contact_point"`
+	AlertTemplate           int64 `target:"alert_template"`
+	AlertMuteTiming         int64 `target:"alert_mute_timing"`
+	AlertNotificationPolicy int64 `target:"alert_notification_policy"`
 }
 
 type UserQuota struct {
@@ -49,6 +54,11 @@ func (cfg *Cfg) readQuotaSettings() {
 		Dashboard:  quota.Key("org_dashboard").MustInt64(10),
 		ApiKey:     quota.Key("org_api_key").MustInt64(10),
 		AlertRule:  alertOrgQuota,
+
+		AlertContactPoint:       quota.Key("org_alert_contact_point").MustInt64(-1),
+		AlertTemplate:           quota.Key("org_alert_template").MustInt64(-1),
+		AlertMuteTiming:         quota.Key("org_alert_mute_timing").MustInt64(-1),
+		AlertNotificationPolicy: quota.Key("org_alert_notification_policy").MustInt64(-1),
 	}
 
 	// per User limits
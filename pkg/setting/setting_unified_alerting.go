@@ -3,6 +3,7 @@ package setting
 import (
 	"errors"
 	"fmt"
+	"net"
 	"strconv"
 	"strings"
 	"time"
@@ -95,8 +96,59 @@ type UnifiedAlertingSettings struct {
 	Screenshots                   UnifiedAlertingScreenshotSettings
 	ReservedLabels                UnifiedAlertingReservedLabelSettings
 	StateHistory                  UnifiedAlertingStateHistorySettings
+	RemoteSync                    UnifiedAlertingRemoteSyncSettings
 	// MaxStateSaveConcurrency controls the number of goroutines (per rule) that can save alert state in parallel.
 	MaxStateSaveConcurrency int
+	// AlertmanagerConfigPreparedStatements makes the reads of the latest Alertmanager configuration use prepared statements.
+	AlertmanagerConfigPreparedStatements bool
+	// AlertingSnapshotInterval is how often the alerting configuration of every organization is saved in a snapshot
+	// that it can be restored to. Zero disables snapshots.
+	AlertingSnapshotInterval time.Duration
+	// AlertingSnapshotRetention is how long snapshots of the alerting configuration are kept.
+	AlertingSnapshotRetention time.Duration
+	// ProvenanceCleanupInterval is how often the provenance records whose resources no longer exist are removed.
+	// Zero disables the cleanup.
+	ProvenanceCleanupInterval time.Duration
+	// DeletedContactPointRetention is how long contact points deleted through the provisioning API can be restored.
+	// Zero makes deletions permanent.
+	DeletedContactPointRetention time.Duration
+	// ProvisioningEventsWebhookURL is the URL that the changes made through the provisioning services are sent to.
+	// Empty disables the webhook.
+	ProvisioningEventsWebhookURL string
+	// ProvisioningConfigWriteRate is the number of Alertmanager configuration updates per second that the provisioning
+	// API allows each org, with bursts of up to ProvisioningConfigWriteBurst updates. Zero disables the limit.
+	ProvisioningConfigWriteRate  float64
+	ProvisioningConfigWriteBurst int
+	// ProvisioningFilesWatch applies the alerting provisioning files as soon as they change, instead of only when
+	// Grafana starts or provisioning is reloaded.
+	ProvisioningFilesWatch bool
+	// ProvisioningFilesWatchDebounce is how long the watcher waits after the last change of the files before applying
+	// them, so that the files written together are applied together.
+	ProvisioningFilesWatchDebounce time.Duration
+	// DisableContactPointsAfterFailures is the number of consecutive failed notification attempts after which a contact
+	// point is disabled. Zero never disables contact points.
+	DisableContactPointsAfterFailures int
+	// ContactPointProbeInterval is how often the endpoints of the contact points that opted in are checked. Zero
+	// disables the checks.
+	ContactPointProbeInterval time.Duration
+	// ContactPointSecretReferences are the patterns of the references to external secrets, such as vault:alerting/*,
+	// that the secure settings of contact points can use. No references are allowed by default.
+	ContactPointSecretReferences []string
+	// NotificationBlockedIPRanges are the IP ranges that webhooks of notifications and the probes of contact points
+	// must not connect to.
+	NotificationBlockedIPRanges []*net.IPNet
+	// PolicyTreeMaxRoutes, PolicyTreeMaxDepth and PolicyTreeMaxMatchersPerRoute limit the number of routes, the
+	// nesting depth and the number of matchers per route of notification policy trees saved with the provisioning
+	// services. Zero does not limit them.
+	PolicyTreeMaxRoutes           int
+	PolicyTreeMaxDepth            int
+	PolicyTreeMaxMatchersPerRoute int
+	// ProvisioningFrozenOrgs are the organizations in which the provisioned alerting resources cannot be changed,
+	// regardless of the freeze set with the provisioning API.
+	ProvisioningFrozenOrgs map[int64]struct{}
+	// ProvisioningFreezeAllowedServiceAccounts are the IDs of the service accounts that can change the provisioned
+	// alerting resources of all organizations while provisioning is frozen.
+	ProvisioningFreezeAllowedServiceAccounts []int64
 }
 
 type UnifiedAlertingScreenshotSettings struct {
@@ -126,6 +178,20 @@ type UnifiedAlertingStateHistorySettings struct {
 	ExternalLabels        map[string]string
 }
 
+// UnifiedAlertingRemoteSyncSettings configures the synchronization of the alerting configuration from another Grafana
+// instance through its provisioning API.
+type UnifiedAlertingRemoteSyncSettings struct {
+	// URL of the Grafana instance the configuration is pulled from. Empty disables the synchronization.
+	URL string
+	// Token is a service account token of the remote instance. It needs to be allowed to read decrypted secure settings
+	// of contact points.
+	Token string
+	// OrgID is the local organization the configuration is applied to.
+	OrgID int64
+	// Interval is how often the configuration is pulled. Zero disables the synchronization.
+	Interval time.Duration
+}
+
 // IsEnabled returns true if UnifiedAlertingSettings.Enabled is either nil or true.
 // It hides the implementation details of the Enabled and simplifies its usage.
 func (u *UnifiedAlertingSettings) IsEnabled() bool {
@@ -378,8 +444,100 @@ func (cfg *Cfg) ReadUnifiedAlertingSettings(iniFile *ini.File) error {
 	}
 	uaCfg.StateHistory = uaCfgStateHistory
 
+	remoteSync := iniFile.Section("unified_alerting.remote_sync")
+	uaCfgRemoteSync := UnifiedAlertingRemoteSyncSettings{
+		URL:   strings.TrimSuffix(remoteSync.Key("url").MustString(""), "/"),
+		Token: remoteSync.Key("token").MustString(""),
+		OrgID: remoteSync.Key("org_id").MustInt64(1),
+	}
+	uaCfgRemoteSync.Interval, err = gtime.ParseDuration(valueAsString(remoteSync, "interval", "5m"))
+	if err != nil {
+		return err
+	}
+	uaCfg.RemoteSync = uaCfgRemoteSync
+
 	uaCfg.MaxStateSaveConcurrency = ua.Key("max_state_save_concurrency").MustInt(1)
 
+	uaCfg.AlertmanagerConfigPreparedStatements = ua.Key("alertmanager_config_prepared_statements").MustBool(false)
+	uaCfg.AlertingSnapshotInterval, err = gtime.ParseDuration(valueAsString(ua, "alerting_snapshot_interval", "0s"))
+	if err != nil {
+		return err
+	}
+	uaCfg.AlertingSnapshotRetention, err = gtime.ParseDuration(valueAsString(ua, "alerting_snapshot_retention", "30d"))
+	if err != nil {
+		return err
+	}
+	uaCfg.ProvenanceCleanupInterval, err = gtime.ParseDuration(valueAsString(ua, "provenance_cleanup_interval", "24h"))
+	if err != nil {
+		return err
+	}
+	uaCfg.DeletedContactPointRetention, err = gtime.ParseDuration(valueAsString(ua, "deleted_contact_point_retention", "7d"))
+	if err != nil {
+		return err
+	}
+	uaCfg.ProvisioningEventsWebhookURL = valueAsString(ua, "provisioning_events_webhook_url", "")
+	uaCfg.ProvisioningConfigWriteRate = ua.Key("provisioning_config_write_rate").MustFloat64(0)
+	if uaCfg.ProvisioningConfigWriteRate < 0 {
+		return errors.New("provisioning_config_write_rate must not be negative")
+	}
+	uaCfg.ProvisioningConfigWriteBurst = ua.Key("provisioning_config_write_burst").MustInt(10)
+	if uaCfg.ProvisioningConfigWriteBurst < 1 {
+		return errors.New("provisioning_config_write_burst must be at least 1")
+	}
+	uaCfg.ProvisioningFilesWatch = ua.Key("provisioning_files_watch").MustBool(false)
+	uaCfg.ProvisioningFilesWatchDebounce, err = gtime.ParseDuration(valueAsString(ua, "provisioning_files_watch_debounce", "2s"))
+	if err != nil {
+		return err
+	}
+	uaCfg.DisableContactPointsAfterFailures = ua.Key("disable_contact_points_after_failures").MustInt(0)
+	if uaCfg.DisableContactPointsAfterFailures < 0 {
+		return errors.New("disable_contact_points_after_failures must not be negative")
+	}
+	uaCfg.ContactPointProbeInterval, err = gtime.ParseDuration(valueAsString(ua, "contact_point_probe_interval", "5m"))
+	if err != nil {
+		return err
+	}
+	uaCfg.ContactPointSecretReferences = util.SplitString(valueAsString(ua, "contact_point_secret_references", ""))
+	// An empty value blocks no ranges, so the default is only used if the key is missing.
+	blockedIPRanges := "169.254.0.0/16,fe80::/10"
+	if ua.HasKey("notification_blocked_ip_ranges") {
+		blockedIPRanges = ua.Key("notification_blocked_ip_ranges").String()
+	}
+	for _, r := range util.SplitString(blockedIPRanges) {
+		_, ipNet, err := net.ParseCIDR(r)
+		if err != nil {
+			return fmt.Errorf("invalid notification_blocked_ip_ranges: %w", err)
+		}
+		uaCfg.NotificationBlockedIPRanges = append(uaCfg.NotificationBlockedIPRanges, ipNet)
+	}
+	uaCfg.PolicyTreeMaxRoutes = ua.Key("policy_tree_max_routes").MustInt(0)
+	if uaCfg.PolicyTreeMaxRoutes < 0 {
+		return errors.New("policy_tree_max_routes must not be negative")
+	}
+	uaCfg.PolicyTreeMaxDepth = ua.Key("policy_tree_max_depth").MustInt(0)
+	if uaCfg.PolicyTreeMaxDepth < 0 {
+		return errors.New("policy_tree_max_depth must not be negative")
+	}
+	uaCfg.PolicyTreeMaxMatchersPerRoute = ua.Key("policy_tree_max_matchers_per_route").MustInt(0)
+	if uaCfg.PolicyTreeMaxMatchersPerRoute < 0 {
+		return errors.New("policy_tree_max_matchers_per_route must not be negative")
+	}
+	uaCfg.ProvisioningFrozenOrgs = make(map[int64]struct{})
+	for _, org := range util.SplitString(valueAsString(ua, "provisioning_frozen_orgs", "")) {
+		orgID, err := strconv.ParseInt(org, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid organization ID in provisioning_frozen_orgs: %w", err)
+		}
+		uaCfg.ProvisioningFrozenOrgs[orgID] = struct{}{}
+	}
+	for _, sa := range util.SplitString(valueAsString(ua, "provisioning_freeze_allowed_service_accounts", "")) {
+		saID, err := strconv.ParseInt(sa, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid service account ID in provisioning_freeze_allowed_service_accounts: %w", err)
+		}
+		uaCfg.ProvisioningFreezeAllowedServiceAccounts = append(uaCfg.ProvisioningFreezeAllowedServiceAccounts, saID)
+	}
+
 	cfg.UnifiedAlerting = uaCfg
 	return nil
 }
@@ -72,6 +72,32 @@ func TestCfg_ReadUnifiedAlertingSettings(t *testing.T) {
 			require.Equal(t, SchedulerBaseInterval, cfg.UnifiedAlerting.BaseInterval)
 		})
 	})
+
+	t.Run("should read 'notification_blocked_ip_ranges'", func(t *testing.T) {
+		s, err := cfg.Raw.NewSection("unified_alerting")
+		require.NoError(t, err)
+
+		s.DeleteKey("notification_blocked_ip_ranges")
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Len(t, cfg.UnifiedAlerting.NotificationBlockedIPRanges, 2)
+		require.Equal(t, "169.254.0.0/16", cfg.UnifiedAlerting.NotificationBlockedIPRanges[0].String())
+
+		_, err = s.NewKey("notification_blocked_ip_ranges", "10.0.0.0/8")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Len(t, cfg.UnifiedAlerting.NotificationBlockedIPRanges, 1)
+		require.Equal(t, "10.0.0.0/8", cfg.UnifiedAlerting.NotificationBlockedIPRanges[0].String())
+
+		_, err = s.NewKey("notification_blocked_ip_ranges", "")
+		require.NoError(t, err)
+		require.NoError(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		require.Empty(t, cfg.UnifiedAlerting.NotificationBlockedIPRanges)
+
+		_, err = s.NewKey("notification_blocked_ip_ranges", "10.0.0.0")
+		require.NoError(t, err)
+		require.Error(t, cfg.ReadUnifiedAlertingSettings(cfg.Raw))
+		s.DeleteKey("notification_blocked_ip_ranges")
+	})
 }
 
 func TestUnifiedAlertingSettings(t *testing.T) {